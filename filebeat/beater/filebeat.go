@@ -368,6 +368,11 @@ func (fb *Filebeat) Run(b *beat.Beat) error {
 
 	inputsLogger := fb.logger.Named("input")
 	v2Inputs := fb.pluginFactory(b.Info, inputsLogger, stateStore)
+	if b.API != nil {
+		if err := attachSourcesAPI(b.API.Router(), v2Inputs); err != nil {
+			return fmt.Errorf("failed to attach sources api to monitoring endpoint server: %w", err)
+		}
+	}
 	v2InputLoader, err := v2.NewLoader(inputsLogger, v2Inputs, "type", cfg.DefaultType)
 	if err != nil {
 		panic(err) // loader detected invalid state.
@@ -0,0 +1,168 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/gorilla/handlers"
+	"github.com/gorilla/mux"
+
+	v2 "github.com/elastic/beats/v7/filebeat/input/v2"
+)
+
+// sourcesAPI serves the harvesters and registry entries reported by the input
+// managers on the HTTP monitoring endpoint. The responses are grouped by input
+// ID and can be filtered by input ID and path glob pattern.
+type sourcesAPI struct {
+	reporters []v2.SourceReporter
+}
+
+var sourcesQueryParams = []string{"input_id", "path", "pretty"}
+
+// attachSourcesAPI adds the /harvesters and /registry routes to r, if any of
+// the plugins can report their sources.
+func attachSourcesAPI(r *mux.Router, plugins []v2.Plugin) error {
+	api := &sourcesAPI{}
+	for _, p := range plugins {
+		if reporter, ok := p.Manager.(v2.SourceReporter); ok {
+			api.reporters = append(api.reporters, reporter)
+		}
+	}
+	if len(api.reporters) == 0 {
+		return nil
+	}
+
+	if err := r.Handle("/harvesters", sourcesHandler(api.harvesters)).GetError(); err != nil {
+		return err
+	}
+	return r.Handle("/registry", sourcesHandler(api.registry)).GetError()
+}
+
+func (api *sourcesAPI) harvesters(w http.ResponseWriter, req *http.Request) {
+	filter, pretty, err := parseSourcesQuery(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	grouped := map[string][]v2.HarvesterState{}
+	for _, reporter := range api.reporters {
+		for _, st := range reporter.Harvesters() {
+			if filter.matches(st.InputID, st.Path) {
+				grouped[st.InputID] = append(grouped[st.InputID], st)
+			}
+		}
+	}
+	for _, states := range grouped {
+		sort.Slice(states, func(i, j int) bool { return states[i].Key < states[j].Key })
+	}
+	serveSourcesJSON(w, grouped, pretty)
+}
+
+func (api *sourcesAPI) registry(w http.ResponseWriter, req *http.Request) {
+	filter, pretty, err := parseSourcesQuery(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	grouped := map[string][]v2.RegistryEntry{}
+	for _, reporter := range api.reporters {
+		for _, entry := range reporter.RegistryEntries() {
+			if filter.matches(entry.InputID, entry.Path) {
+				grouped[entry.InputID] = append(grouped[entry.InputID], entry)
+			}
+		}
+	}
+	for _, entries := range grouped {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	}
+	serveSourcesJSON(w, grouped, pretty)
+}
+
+type sourcesFilter struct {
+	inputID string
+	path    string
+}
+
+func (f sourcesFilter) matches(inputID, path string) bool {
+	if f.inputID != "" && f.inputID != inputID {
+		return false
+	}
+	if f.path != "" {
+		matched, _ := filepath.Match(f.path, path)
+		return matched
+	}
+	return true
+}
+
+func parseSourcesQuery(req *http.Request) (sourcesFilter, bool, error) {
+	query := req.URL.Query()
+	filter := sourcesFilter{
+		inputID: query.Get("input_id"),
+		path:    query.Get("path"),
+	}
+	if filter.path != "" {
+		if _, err := filepath.Match(filter.path, ""); err != nil {
+			return filter, false, errors.New(`invalid glob pattern for "path"`)
+		}
+	}
+
+	if !query.Has("pretty") {
+		return filter, false, nil
+	}
+	switch query.Get("pretty") {
+	case "", "true":
+		return filter, true, nil
+	case "false":
+		return filter, false, nil
+	default:
+		return filter, false, errors.New(`invalid value for "pretty"`)
+	}
+}
+
+func serveSourcesJSON(w http.ResponseWriter, value any, pretty bool) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if pretty {
+		enc.SetIndent("", "  ")
+	}
+	_ = enc.Encode(value)
+}
+
+// sourcesHandler only accepts GET requests with known query parameters.
+func sourcesHandler(h http.HandlerFunc) http.Handler {
+	next := handlers.CompressHandler(h)
+	validated := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		for q := range req.URL.Query() {
+			if !slices.Contains(sourcesQueryParams, q) {
+				http.Error(w, "Unknown query param "+q, http.StatusBadRequest)
+				return
+			}
+		}
+		next.ServeHTTP(w, req)
+	})
+	return handlers.MethodHandler{http.MethodGet: validated}
+}
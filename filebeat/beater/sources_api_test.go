@@ -0,0 +1,105 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v2 "github.com/elastic/beats/v7/filebeat/input/v2"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/go-concert/unison"
+)
+
+type fakeSourceReporter struct {
+	harvesters []v2.HarvesterState
+	entries    []v2.RegistryEntry
+}
+
+func (f *fakeSourceReporter) Init(unison.Group) error             { return nil }
+func (f *fakeSourceReporter) Create(*conf.C) (v2.Input, error)    { return nil, nil }
+func (f *fakeSourceReporter) Harvesters() []v2.HarvesterState     { return f.harvesters }
+func (f *fakeSourceReporter) RegistryEntries() []v2.RegistryEntry { return f.entries }
+
+func TestSourcesAPI(t *testing.T) {
+	reporter := &fakeSourceReporter{
+		harvesters: []v2.HarvesterState{
+			{InputID: "a", Key: "filestream::a::native::2", Path: "/var/log/app/2.log"},
+			{InputID: "a", Key: "filestream::a::native::1", Path: "/var/log/app/1.log"},
+			{InputID: "b", Key: "filestream::b::native::3", Path: "/var/log/other.log"},
+		},
+		entries: []v2.RegistryEntry{
+			{InputID: "a", Key: "filestream::a::native::1", Path: "/var/log/app/1.log"},
+			{InputID: "b", Key: "filestream::b::native::3", Path: "/var/log/other.log"},
+		},
+	}
+	router := mux.NewRouter()
+	require.NoError(t, attachSourcesAPI(router, []v2.Plugin{{Name: "filestream", Manager: reporter}}))
+
+	get := func(t *testing.T, target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	t.Run("harvesters grouped by input ID", func(t *testing.T) {
+		rec := get(t, "/harvesters")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var got map[string][]v2.HarvesterState
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		require.Len(t, got["a"], 2)
+		assert.Equal(t, "filestream::a::native::1", got["a"][0].Key)
+		assert.Len(t, got["b"], 1)
+	})
+
+	t.Run("filter by path", func(t *testing.T) {
+		rec := get(t, "/harvesters?path=/var/log/app/*.log")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var got map[string][]v2.HarvesterState
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Len(t, got["a"], 2)
+		assert.NotContains(t, got, "b")
+	})
+
+	t.Run("filter registry by input ID", func(t *testing.T) {
+		rec := get(t, "/registry?input_id=b&pretty")
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var got map[string][]v2.RegistryEntry
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+		assert.Equal(t, map[string][]v2.RegistryEntry{"b": reporter.entries[1:]}, got)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, get(t, "/registry?unknown=1").Code)
+		assert.Equal(t, http.StatusBadRequest, get(t, "/registry?path=[").Code)
+		assert.Equal(t, http.StatusBadRequest, get(t, "/registry?pretty=maybe").Code)
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/registry", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
@@ -19,6 +19,7 @@ package beater
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/elastic/beats/v7/filebeat/config"
@@ -26,6 +27,7 @@ import (
 	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/statestore"
 	"github.com/elastic/beats/v7/libbeat/statestore/backend"
+	"github.com/elastic/beats/v7/libbeat/statestore/backend/bbolt"
 	"github.com/elastic/beats/v7/libbeat/statestore/backend/es"
 	"github.com/elastic/beats/v7/libbeat/statestore/backend/memlog"
 	"github.com/elastic/elastic-agent-libs/logp"
@@ -60,10 +62,7 @@ func openStateStore(ctx context.Context, info beat.Info, logger *logp.Logger, cf
 		esreg = es.New(ctx, logger, notifier)
 	}
 
-	reg, err = memlog.New(logger, memlog.Settings{
-		Root:     paths.Resolve(paths.Data, cfg.Path),
-		FileMode: cfg.Permissions,
-	})
+	reg, err = OpenRegistryBackend(logger, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +81,26 @@ func openStateStore(ctx context.Context, info beat.Info, logger *logp.Logger, cf
 	return store, nil
 }
 
+// OpenRegistryBackend opens the registry backend configured in cfg, relative
+// to the data path.
+func OpenRegistryBackend(logger *logp.Logger, cfg config.Registry) (backend.Registry, error) {
+	root := paths.Resolve(paths.Data, cfg.Path)
+	switch cfg.Backend {
+	case "", "memlog":
+		return memlog.New(logger, memlog.Settings{
+			Root:     root,
+			FileMode: cfg.Permissions,
+		})
+	case "bbolt":
+		return bbolt.New(logger, bbolt.Settings{
+			Root:     root,
+			FileMode: cfg.Permissions,
+		})
+	default:
+		return nil, fmt.Errorf("unknown registry backend '%s'", cfg.Backend)
+	}
+}
+
 func (s *filebeatStore) Close() {
 	s.registry.Close()
 }
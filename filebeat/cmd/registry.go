@@ -0,0 +1,123 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/elastic/beats/v7/filebeat/beater"
+	"github.com/elastic/beats/v7/filebeat/config"
+	"github.com/elastic/beats/v7/filebeat/registrar"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/cmd"
+	"github.com/elastic/beats/v7/libbeat/statestore"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// registryManager gives the registry command access to the filebeat store.
+// It knows about the entries written by filestream and the log input.
+type registryManager struct {
+	registry *statestore.Registry
+	store    *statestore.Store
+}
+
+const (
+	filestreamKeyPrefix = "filestream::"
+	logInputKeyPrefix   = "filebeat::logs::"
+)
+
+func buildRegistryManager(b *beat.Beat) (cmd.RegistryManager, error) {
+	cfg := config.DefaultConfig.Registry
+	if b.BeatConfig.HasField("registry") {
+		sub, err := b.BeatConfig.Child("registry", -1)
+		if err != nil {
+			return nil, err
+		}
+		if err := sub.Unpack(&cfg); err != nil {
+			return nil, fmt.Errorf("invalid registry configuration: %w", err)
+		}
+	}
+
+	logger := b.Info.Logger.Named("registry")
+	if err := registrar.NewMigrator(cfg, logger).Run(); err != nil {
+		return nil, fmt.Errorf("failed to migrate registry: %w", err)
+	}
+
+	backend, err := beater.OpenRegistryBackend(logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+	registry := statestore.NewRegistry(backend)
+	store, err := registry.Get(b.Info.Beat)
+	if err != nil {
+		registry.Close()
+		return nil, err
+	}
+	return &registryManager{registry: registry, store: store}, nil
+}
+
+func (m *registryManager) Store() *statestore.Store {
+	return m.store
+}
+
+// Describe returns the input ID and path of filestream and log input entries.
+// Filestream keys have the format `filestream::<input ID>::<file identity>`,
+// the log input does not persist the input ID.
+func (m *registryManager) Describe(key string, value mapstr.M) (string, string) {
+	switch {
+	case strings.HasPrefix(key, filestreamKeyPrefix):
+		inputID, _, _ := strings.Cut(strings.TrimPrefix(key, filestreamKeyPrefix), "::")
+		path, _ := value.GetValue("meta.source")
+		source, _ := path.(string)
+		return inputID, source
+	case strings.HasPrefix(key, logInputKeyPrefix):
+		source, _ := value["source"].(string)
+		return "", source
+	default:
+		return "", ""
+	}
+}
+
+// ResetOffset sets the offset to 0. The cursor state derived from the content
+// of a filestream file is removed, so it is detected again.
+func (m *registryManager) ResetOffset(key string, value mapstr.M) bool {
+	switch {
+	case strings.HasPrefix(key, filestreamKeyPrefix):
+		if has, _ := value.HasKey("cursor.offset"); !has {
+			return false
+		}
+		_, _ = value.Put("cursor.offset", int64(0))
+		_ = value.Delete("cursor.csv_header")
+		_ = value.Delete("cursor.encoding")
+		return true
+	case strings.HasPrefix(key, logInputKeyPrefix):
+		if _, ok := value["offset"]; !ok {
+			return false
+		}
+		value["offset"] = int64(0)
+		return true
+	default:
+		return false
+	}
+}
+
+func (m *registryManager) Close() error {
+	m.store.Close()
+	return m.registry.Close()
+}
@@ -0,0 +1,152 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/statestore"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp/logptest"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+const (
+	testFilestreamKey = "filestream::my-input::native::1234-66"
+	testLogInputKey   = "filebeat::logs::native::5678-66"
+)
+
+func testRegistryEntries() map[string]mapstr.M {
+	return map[string]mapstr.M{
+		testFilestreamKey: {
+			"ttl":     int64(1800000000000),
+			"updated": []any{int64(281470681743360), int64(1700000000)},
+			"cursor": mapstr.M{
+				"offset":     int64(4096),
+				"eof":        true,
+				"csv_header": []any{"a", "b"},
+				"encoding":   "utf-16le-bom",
+			},
+			"meta": mapstr.M{
+				"source":          "/var/log/app/a.log",
+				"identifier_name": "native",
+			},
+		},
+		testLogInputKey: {
+			"id":              "native::5678-66",
+			"prev_id":         "",
+			"source":          "/var/log/b.log",
+			"offset":          int64(2048),
+			"ttl":             int64(-1),
+			"type":            "log",
+			"identifier_name": "native",
+			"FileStateOS":     mapstr.M{"inode": int64(5678), "device": int64(66)},
+		},
+		"other::key": {"ttl": int64(-1)},
+	}
+}
+
+// openTestRegistryManager opens the registry of filebeat in a temporary
+// directory, holding entries.
+func openTestRegistryManager(t *testing.T, entries map[string]mapstr.M) (*registryManager, string) {
+	t.Helper()
+
+	path := t.TempDir()
+	open := func() *registryManager {
+		b := &beat.Beat{
+			Info: beat.Info{Beat: "filebeat", Logger: logptest.NewTestingLogger(t, "")},
+			BeatConfig: conf.MustNewConfigFrom(map[string]any{
+				"registry.path": path,
+			}),
+		}
+		registry, err := buildRegistryManager(b)
+		require.NoError(t, err)
+		return registry.(*registryManager)
+	}
+
+	registry := open()
+	for key, value := range entries {
+		require.NoError(t, registry.Store().Set(key, value))
+	}
+	require.NoError(t, registry.Close())
+	return open(), path
+}
+
+func readRegistryEntries(t *testing.T, store *statestore.Store) map[string]mapstr.M {
+	t.Helper()
+
+	entries := map[string]mapstr.M{}
+	err := store.Each(func(key string, dec statestore.ValueDecoder) (bool, error) {
+		var value mapstr.M
+		err := dec.Decode(&value)
+		entries[key] = value
+		return true, err
+	})
+	require.NoError(t, err)
+	return entries
+}
+
+func TestRegistryManagerDescribe(t *testing.T) {
+	registry, _ := openTestRegistryManager(t, testRegistryEntries())
+	defer registry.Close()
+
+	entries := readRegistryEntries(t, registry.Store())
+	tests := map[string]struct {
+		inputID, path string
+	}{
+		testFilestreamKey: {"my-input", "/var/log/app/a.log"},
+		testLogInputKey:   {"", "/var/log/b.log"},
+		"other::key":      {"", ""},
+	}
+	for key, want := range tests {
+		inputID, path := registry.Describe(key, entries[key])
+		assert.Equal(t, want.inputID, inputID, key)
+		assert.Equal(t, want.path, path, key)
+	}
+}
+
+func TestRegistryManagerResetOffset(t *testing.T) {
+	registry, _ := openTestRegistryManager(t, testRegistryEntries())
+	defer registry.Close()
+
+	for key, value := range readRegistryEntries(t, registry.Store()) {
+		reset := registry.ResetOffset(key, value)
+		assert.Equal(t, key != "other::key", reset, key)
+		if reset {
+			require.NoError(t, registry.Store().Set(key, value))
+		}
+	}
+
+	// only the offset is changed, and the state derived from the content
+	// of a filestream file is removed
+	want := testRegistryEntries()
+	want[testFilestreamKey]["cursor"] = mapstr.M{"offset": int64(0), "eof": true}
+	want[testLogInputKey]["offset"] = int64(0)
+
+	// numbers are decoded as floats, so entries are compared as JSON
+	wantJSON, err := json.Marshal(want)
+	require.NoError(t, err)
+	got, err := json.Marshal(readRegistryEntries(t, registry.Store()))
+	require.NoError(t, err)
+	assert.JSONEq(t, string(wantJSON), string(got))
+}
@@ -0,0 +1,90 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package amqp
+
+import (
+	"errors"
+	"fmt"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+
+	"github.com/elastic/elastic-agent-libs/transport/tlscommon"
+)
+
+// connection is the subset of *amqp091.Connection used by the input.
+type connection interface {
+	Channel() (channel, error)
+	Close() error
+}
+
+// channel is the subset of *amqp091.Channel used by the input.
+type channel interface {
+	Qos(prefetchCount, prefetchSize int, global bool) error
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp091.Table) error
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp091.Table) (amqp091.Queue, error)
+	QueueBind(name, key, exchange string, noWait bool, args amqp091.Table) error
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp091.Table) (<-chan amqp091.Delivery, error)
+	Ack(tag uint64, multiple bool) error
+	Cancel(consumer string, noWait bool) error
+}
+
+type amqpConnection struct {
+	*amqp091.Connection
+}
+
+func (c amqpConnection) Channel() (channel, error) {
+	return c.Connection.Channel()
+}
+
+// dialAMQP connects to the first reachable host.
+func dialAMQP(c config) (connection, error) {
+	amqpConfig := amqp091.Config{
+		Properties: amqp091.NewConnectionProperties(),
+	}
+	amqpConfig.Properties.SetClientConnectionName("filebeat")
+	if c.Username != "" {
+		amqpConfig.SASL = []amqp091.Authentication{
+			&amqp091.PlainAuth{Username: c.Username, Password: c.Password},
+		}
+	}
+	var tlsConfig *tlscommon.TLSConfig
+	if c.TLS.IsEnabled() {
+		var err error
+		tlsConfig, err = tlscommon.LoadTLSConfig(c.TLS)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+	for _, host := range c.Hosts {
+		uri, err := amqp091.ParseURI(host)
+		if err != nil {
+			return nil, err
+		}
+		if tlsConfig != nil {
+			amqpConfig.TLSClientConfig = tlsConfig.BuildModuleClientConfig(uri.Host)
+		}
+		conn, err := amqp091.DialConfig(host, amqpConfig)
+		if err == nil {
+			return amqpConnection{conn}, nil
+		}
+		errs = append(errs, fmt.Errorf("%s:%d: %w", uri.Host, uri.Port, err))
+	}
+	return nil, fmt.Errorf("failed to connect to any host: %w", errors.Join(errs...))
+}
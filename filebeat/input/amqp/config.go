@@ -0,0 +1,129 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package amqp
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/elastic/elastic-agent-libs/transport/tlscommon"
+)
+
+type config struct {
+	// Hosts are AMQP URLs, e.g. "amqp://localhost:5672/vhost". They are
+	// tried in order when connecting.
+	Hosts    []string          `config:"hosts" validate:"required"`
+	Username string            `config:"username"`
+	Password string            `config:"password"`
+	TLS      *tlscommon.Config `config:"ssl"`
+
+	Queue      queueConfig     `config:"queue"`
+	Exchange   exchangeConfig  `config:"exchange"`
+	Bindings   []bindingConfig `config:"bindings"`
+	Prefetch   int             `config:"prefetch_count" validate:"min=1"`
+	ConsumerID string          `config:"consumer_tag"`
+
+	ConnectBackoff time.Duration `config:"connect_backoff" validate:"positive,nonzero"`
+	WaitClose      time.Duration `config:"wait_close" validate:"min=0"`
+}
+
+type queueConfig struct {
+	Name       string                 `config:"name" validate:"required"`
+	Declare    bool                   `config:"declare"`
+	Durable    bool                   `config:"durable"`
+	AutoDelete bool                   `config:"auto_delete"`
+	Exclusive  bool                   `config:"exclusive"`
+	Arguments  map[string]interface{} `config:"arguments"`
+}
+
+type exchangeConfig struct {
+	Name       string       `config:"name"`
+	Type       exchangeType `config:"type"`
+	Declare    bool         `config:"declare"`
+	Durable    bool         `config:"durable"`
+	AutoDelete bool         `config:"auto_delete"`
+}
+
+type bindingConfig struct {
+	RoutingKey string `config:"routing_key"`
+}
+
+type exchangeType string
+
+var exchangeTypes = map[string]exchangeType{
+	"direct":  "direct",
+	"fanout":  "fanout",
+	"topic":   "topic",
+	"headers": "headers",
+}
+
+// Unpack validates and unpacks the "exchange.type" config option.
+func (t *exchangeType) Unpack(value string) error {
+	et, ok := exchangeTypes[value]
+	if !ok {
+		return fmt.Errorf("invalid exchange type '%s'", value)
+	}
+	*t = et
+	return nil
+}
+
+func defaultConfig() config {
+	return config{
+		Queue: queueConfig{
+			Durable: true,
+		},
+		Exchange: exchangeConfig{
+			Type:    "direct",
+			Durable: true,
+		},
+		Prefetch:       100,
+		ConnectBackoff: time.Second,
+		WaitClose:      2 * time.Second,
+	}
+}
+
+// Validate validates the config.
+func (c *config) Validate() error {
+	for _, host := range c.Hosts {
+		u, err := url.Parse(host)
+		if err != nil {
+			return fmt.Errorf("invalid host '%s': %w", host, err)
+		}
+		switch u.Scheme {
+		case "amqps":
+		case "amqp":
+			if c.TLS.IsEnabled() {
+				return fmt.Errorf("invalid host '%s': ssl requires the amqps scheme", host)
+			}
+		default:
+			return fmt.Errorf("invalid host '%s': scheme must be amqp or amqps", host)
+		}
+	}
+	if c.Username != "" && c.Password == "" {
+		return errors.New("password must be set when username is configured")
+	}
+	if len(c.Bindings) > 0 && c.Exchange.Name == "" {
+		return errors.New("bindings require an exchange name")
+	}
+	if c.Exchange.Declare && c.Exchange.Name == "" {
+		return errors.New("exchange.declare requires an exchange name")
+	}
+	return nil
+}
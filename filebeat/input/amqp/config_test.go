@@ -0,0 +1,100 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package amqp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		c := defaultConfig()
+		err := conf.MustNewConfigFrom(map[string]interface{}{
+			"hosts":      []string{"amqp://localhost:5672/"},
+			"queue.name": "events",
+		}).Unpack(&c)
+		require.NoError(t, err)
+		assert.Equal(t, 100, c.Prefetch)
+		assert.Equal(t, exchangeType("direct"), c.Exchange.Type)
+		assert.True(t, c.Queue.Durable)
+		assert.False(t, c.Queue.Declare)
+	})
+
+	tests := map[string]struct {
+		cfg     map[string]interface{}
+		wantErr string
+	}{
+		"missing hosts": {
+			cfg:     map[string]interface{}{"queue.name": "events"},
+			wantErr: "missing required field accessing 'hosts'",
+		},
+		"missing queue": {
+			cfg:     map[string]interface{}{"hosts": []string{"amqp://localhost:5672/"}},
+			wantErr: "string value is not set accessing 'name'",
+		},
+		"invalid scheme": {
+			cfg:     map[string]interface{}{"hosts": []string{"http://localhost:5672/"}, "queue.name": "events"},
+			wantErr: "scheme must be amqp or amqps",
+		},
+		"ssl without amqps": {
+			cfg: map[string]interface{}{
+				"hosts":       []string{"amqp://localhost:5672/"},
+				"queue.name":  "events",
+				"ssl.enabled": true,
+			},
+			wantErr: "ssl requires the amqps scheme",
+		},
+		"username without password": {
+			cfg:     map[string]interface{}{"hosts": []string{"amqp://localhost:5672/"}, "queue.name": "events", "username": "beats"},
+			wantErr: "password must be set when username is configured",
+		},
+		"bindings without exchange": {
+			cfg: map[string]interface{}{
+				"hosts":      []string{"amqp://localhost:5672/"},
+				"queue.name": "events",
+				"bindings":   []map[string]interface{}{{"routing_key": "logs"}},
+			},
+			wantErr: "bindings require an exchange name",
+		},
+		"invalid exchange type": {
+			cfg: map[string]interface{}{
+				"hosts":         []string{"amqp://localhost:5672/"},
+				"queue.name":    "events",
+				"exchange.name": "logs",
+				"exchange.type": "broadcast",
+			},
+			wantErr: "invalid exchange type 'broadcast'",
+		},
+		"invalid prefetch count": {
+			cfg:     map[string]interface{}{"hosts": []string{"amqp://localhost:5672/"}, "queue.name": "events", "prefetch_count": 0},
+			wantErr: "requires value >= 1 accessing 'prefetch_count'",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := defaultConfig()
+			err := conf.MustNewConfigFrom(test.cfg).Unpack(&c)
+			assert.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
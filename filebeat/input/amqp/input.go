@@ -0,0 +1,302 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package amqp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	amqp091 "github.com/rabbitmq/amqp091-go"
+
+	input "github.com/elastic/beats/v7/filebeat/input/v2"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common/acker"
+	"github.com/elastic/beats/v7/libbeat/common/backoff"
+	"github.com/elastic/beats/v7/libbeat/feature"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/go-concert/ctxtool"
+)
+
+const inputName = "amqp"
+
+// Plugin creates the amqp input plugin.
+func Plugin() input.Plugin {
+	return input.Plugin{
+		Name:       inputName,
+		Stability:  feature.Beta,
+		Deprecated: false,
+		Info:       "AMQP input",
+		Doc:        "The amqp input consumes messages from a RabbitMQ queue using AMQP 0-9-1",
+		Manager:    input.ConfigureWith(configure),
+	}
+}
+
+func configure(cfg *conf.C) (input.Input, error) {
+	config := defaultConfig()
+	if err := cfg.Unpack(&config); err != nil {
+		return nil, err
+	}
+	return &amqpInput{config: config, dial: dialAMQP}, nil
+}
+
+type amqpInput struct {
+	config config
+	dial   func(config) (connection, error)
+
+	mu   sync.Mutex
+	conn connection // current connection, closed once the pipeline client is closed
+}
+
+// deliveryRef identifies a published message. It is stored in event.Private
+// and acknowledged on its channel once the event has been acknowledged by
+// the pipeline.
+type deliveryRef struct {
+	ch  channel
+	tag uint64
+}
+
+func (in *amqpInput) Name() string { return inputName }
+
+func (in *amqpInput) Test(_ input.TestContext) error {
+	conn, err := in.dial(in.config)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func (in *amqpInput) Run(ctx input.Context, pipeline beat.Pipeline) error {
+	log := ctx.Logger.With("queue", in.config.Queue.Name)
+
+	// The connection must stay open while the pipeline client waits for
+	// pending acknowledgements, so it is closed after the client.
+	defer in.setConn(nil)
+	client, err := pipeline.ConnectWith(beat.ClientConfig{
+		EventListener: acker.ConnectionOnly(
+			acker.EventPrivateReporter(func(_ int, privates []interface{}) {
+				ack(log, privates)
+			}),
+		),
+		WaitClose: in.config.WaitClose,
+	})
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	consumerTag := in.config.ConsumerID
+	if consumerTag == "" {
+		consumerTag = "filebeat-" + uuid.Must(uuid.NewV4()).String()
+	}
+
+	log.Info("Starting AMQP input")
+	defer log.Info("AMQP input stopped")
+
+	cancelCtx := ctxtool.FromCanceller(ctx.Cancelation)
+	connectDelay := backoff.NewEqualJitterBackoff(
+		ctx.Cancelation.Done(),
+		in.config.ConnectBackoff,
+		60*in.config.ConnectBackoff,
+	)
+	for cancelCtx.Err() == nil {
+		conn, err := in.dial(in.config)
+		if err != nil {
+			log.Errorw("Error connecting to AMQP broker", "error", err)
+			connectDelay.Wait()
+			continue
+		}
+		// Closing the previous connection discards the acknowledgements
+		// still pending on it, the broker redelivers those messages.
+		in.setConn(conn)
+		connectDelay.Reset()
+
+		err = in.consume(cancelCtx, conn, consumerTag, client)
+		if cancelCtx.Err() == nil {
+			log.Errorw("Error consuming from AMQP broker", "error", err)
+			in.setConn(nil)
+			connectDelay.Wait()
+		}
+	}
+	return nil
+}
+
+func (in *amqpInput) setConn(conn connection) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if in.conn != nil {
+		in.conn.Close()
+	}
+	in.conn = conn
+}
+
+// consume sets up the topology and publishes the deliveries until ctx is
+// done or the channel is closed.
+func (in *amqpInput) consume(ctx context.Context, conn connection, consumerTag string, client beat.Client) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("opening channel: %w", err)
+	}
+	if err := in.setup(ch); err != nil {
+		return err
+	}
+
+	cfg := in.config
+	deliveries, err := ch.Consume(cfg.Queue.Name, consumerTag, false, cfg.Queue.Exclusive, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("consuming from queue '%s': %w", cfg.Queue.Name, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Stop the deliveries, but keep the channel open so that
+			// the in-flight messages can still be acknowledged.
+			return ch.Cancel(consumerTag, false)
+		case d, ok := <-deliveries:
+			if !ok {
+				return errors.New("delivery channel closed")
+			}
+			client.Publish(createEvent(cfg.Queue.Name, d, ch))
+		}
+	}
+}
+
+// setup applies the prefetch count and declares and binds the configured
+// exchange and queue.
+func (in *amqpInput) setup(ch channel) error {
+	cfg := in.config
+	if err := ch.Qos(cfg.Prefetch, 0, false); err != nil {
+		return fmt.Errorf("setting prefetch count: %w", err)
+	}
+	if ex := cfg.Exchange; ex.Declare {
+		if err := ch.ExchangeDeclare(ex.Name, string(ex.Type), ex.Durable, ex.AutoDelete, false, false, nil); err != nil {
+			return fmt.Errorf("declaring exchange '%s': %w", ex.Name, err)
+		}
+	}
+	if q := cfg.Queue; q.Declare {
+		if _, err := ch.QueueDeclare(q.Name, q.Durable, q.AutoDelete, q.Exclusive, false, amqp091.Table(q.Arguments)); err != nil {
+			return fmt.Errorf("declaring queue '%s': %w", q.Name, err)
+		}
+	}
+	for _, b := range cfg.Bindings {
+		if err := ch.QueueBind(cfg.Queue.Name, b.RoutingKey, cfg.Exchange.Name, false, nil); err != nil {
+			return fmt.Errorf("binding queue '%s' to exchange '%s': %w", cfg.Queue.Name, cfg.Exchange.Name, err)
+		}
+	}
+	return nil
+}
+
+// ack acknowledges the messages of the events acknowledged by the pipeline.
+// Events are acknowledged in publishing order, so only the last delivery
+// tag of each channel is acknowledged, together with all the previous ones.
+func ack(log *logp.Logger, privates []interface{}) {
+	last := make(map[channel]uint64)
+	var order []channel
+	for _, p := range privates {
+		ref, ok := p.(deliveryRef)
+		if !ok {
+			continue
+		}
+		if _, seen := last[ref.ch]; !seen {
+			order = append(order, ref.ch)
+		}
+		last[ref.ch] = ref.tag
+	}
+	for _, ch := range order {
+		if err := ch.Ack(last[ch], true); err != nil {
+			log.Warnw("Failed to acknowledge messages, they will be redelivered", "delivery_tag", last[ch], "error", err)
+		}
+	}
+}
+
+func createEvent(queue string, d amqp091.Delivery, ch channel) beat.Event {
+	ts := d.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	fields := mapstr.M{
+		"queue":       queue,
+		"routing_key": d.RoutingKey,
+		"redelivered": d.Redelivered,
+	}
+	putString := func(key, value string) {
+		if value != "" {
+			fields[key] = value
+		}
+	}
+	putString("exchange", d.Exchange)
+	putString("message_id", d.MessageId)
+	putString("correlation_id", d.CorrelationId)
+	putString("content_type", d.ContentType)
+	putString("content_encoding", d.ContentEncoding)
+	putString("app_id", d.AppId)
+	putString("user_id", d.UserId)
+	putString("type", d.Type)
+	if d.Priority > 0 {
+		fields["priority"] = d.Priority
+	}
+	if len(d.Headers) > 0 {
+		fields["headers"] = convertTable(d.Headers)
+	}
+
+	return beat.Event{
+		Timestamp: ts,
+		Fields: mapstr.M{
+			"message": string(d.Body),
+			"amqp":    fields,
+		},
+		Private: deliveryRef{ch: ch, tag: d.DeliveryTag},
+	}
+}
+
+// convertTable converts AMQP header values to types that can be encoded
+// in an event.
+func convertTable(t amqp091.Table) mapstr.M {
+	m := make(mapstr.M, len(t))
+	for k, v := range t {
+		m[k] = convertValue(v)
+	}
+	return m
+}
+
+func convertValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case amqp091.Table:
+		return convertTable(v)
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, e := range v {
+			values[i] = convertValue(e)
+		}
+		return values
+	case []byte:
+		return string(v)
+	case amqp091.Decimal:
+		return float64(v.Value) * math.Pow10(-int(v.Scale))
+	default:
+		return v
+	}
+}
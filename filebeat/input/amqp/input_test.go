@@ -0,0 +1,409 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package amqp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	amqp091 "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	input "github.com/elastic/beats/v7/filebeat/input/v2"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	pubtest "github.com/elastic/beats/v7/libbeat/publisher/testing"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+func TestInputPublishAndAck(t *testing.T) {
+	broker := &fakeBroker{}
+	cfg := testConfig()
+	cfg.Prefetch = 10
+	cfg.Queue.Declare = true
+	cfg.Queue.Arguments = map[string]interface{}{"x-queue-type": "quorum"}
+	cfg.Exchange = exchangeConfig{Name: "logs", Type: "topic", Declare: true, Durable: true}
+	cfg.Bindings = []bindingConfig{{RoutingKey: "app.*"}, {RoutingKey: "audit"}}
+
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var ackedBeforePipelineACK int
+	env := runInput(t, broker, cfg, func(p *fakePipeline) bool {
+		ch := broker.channel()
+		if ch == nil {
+			return false
+		}
+		if len(p.published()) == 0 {
+			ch.deliver(amqp091.Delivery{
+				DeliveryTag:   1,
+				Exchange:      "logs",
+				RoutingKey:    "app.web",
+				MessageId:     "m-1",
+				ContentType:   "text/plain",
+				Timestamp:     ts,
+				Priority:      3,
+				Redelivered:   true,
+				Headers:       amqp091.Table{"retries": int32(2)},
+				Body:          []byte("hello"),
+				CorrelationId: "c-1",
+			})
+			ch.deliver(amqp091.Delivery{DeliveryTag: 2, RoutingKey: "audit", Body: []byte("world")})
+		}
+		if len(p.published()) < 2 {
+			return false
+		}
+		ackedBeforePipelineACK = len(ch.acks())
+		p.ackAll()
+		return true
+	})
+
+	events := env.pipeline.published()
+	require.Len(t, events, 2)
+	assert.Equal(t, ts, events[0].Timestamp)
+	assert.Equal(t, mapstr.M{
+		"message": "hello",
+		"amqp": mapstr.M{
+			"queue":          "events",
+			"exchange":       "logs",
+			"routing_key":    "app.web",
+			"message_id":     "m-1",
+			"correlation_id": "c-1",
+			"content_type":   "text/plain",
+			"priority":       uint8(3),
+			"redelivered":    true,
+			"headers":        mapstr.M{"retries": int32(2)},
+		},
+	}, events[0].Fields)
+	assert.Equal(t, mapstr.M{"queue": "events", "routing_key": "audit", "redelivered": false}, events[1].Fields["amqp"])
+	assert.WithinDuration(t, time.Now(), events[1].Timestamp, time.Minute)
+
+	ch := broker.channel()
+	assert.Equal(t, 0, ackedBeforePipelineACK)
+	assert.Equal(t, []fakeAck{{tag: 2, multiple: true}}, ch.acks())
+	assert.Equal(t, 10, ch.prefetch)
+	assert.Equal(t, []string{"exchange logs topic durable", "queue events durable", "bind events logs app.*", "bind events logs audit"}, ch.calls)
+	assert.Equal(t, amqp091.Table{"x-queue-type": "quorum"}, ch.queueArgs)
+	assert.Equal(t, "consumer-1", ch.consumer)
+	assert.True(t, ch.canceled)
+	assert.True(t, broker.closed(), "connection must be closed on stop")
+}
+
+func TestInputReconnects(t *testing.T) {
+	broker := &fakeBroker{dialErrs: []error{errors.New("connection refused")}}
+	cfg := testConfig()
+
+	var first, second *fakeChannel
+	env := runInput(t, broker, cfg, func(p *fakePipeline) bool {
+		ch := broker.channel()
+		if ch == nil {
+			return false
+		}
+		if first == nil {
+			first = ch
+			ch.deliver(amqp091.Delivery{DeliveryTag: 1, Body: []byte("first")})
+			// Closing the delivery channel simulates a lost connection.
+			ch.closeDeliveries()
+			return false
+		}
+		if ch == first {
+			return false
+		}
+		if second == nil {
+			second = ch
+			ch.deliver(amqp091.Delivery{DeliveryTag: 1, Body: []byte("second")})
+		}
+		return len(p.published()) == 2
+	})
+
+	require.Len(t, env.pipeline.published(), 2)
+	assert.Equal(t, 3, broker.dialCount())
+}
+
+func TestAckGroupsByChannel(t *testing.T) {
+	a, b := newFakeChannel(), newFakeChannel()
+	ack(logp.NewLogger(inputName), []interface{}{
+		deliveryRef{ch: a, tag: 1},
+		deliveryRef{ch: b, tag: 7},
+		nil,
+		deliveryRef{ch: a, tag: 2},
+		deliveryRef{ch: b, tag: 8},
+	})
+	assert.Equal(t, []fakeAck{{tag: 2, multiple: true}}, a.acks())
+	assert.Equal(t, []fakeAck{{tag: 8, multiple: true}}, b.acks())
+}
+
+func TestConvertTable(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+	got := convertTable(amqp091.Table{
+		"string":  "value",
+		"bytes":   []byte("raw"),
+		"decimal": amqp091.Decimal{Scale: 2, Value: 1234},
+		"time":    ts,
+		"nested":  amqp091.Table{"list": []interface{}{int64(1), []byte("two")}},
+	})
+	assert.Equal(t, mapstr.M{
+		"string":  "value",
+		"bytes":   "raw",
+		"decimal": 12.34,
+		"time":    ts,
+		"nested":  mapstr.M{"list": []interface{}{int64(1), "two"}},
+	}, got)
+}
+
+func testConfig() config {
+	cfg := defaultConfig()
+	cfg.Hosts = []string{"amqp://localhost:5672/"}
+	cfg.Queue.Name = "events"
+	cfg.ConsumerID = "consumer-1"
+	cfg.ConnectBackoff = time.Millisecond
+	return cfg
+}
+
+type testEnv struct {
+	pipeline *fakePipeline
+}
+
+// runInput runs the input against broker until done returns true.
+func runInput(t *testing.T, broker *fakeBroker, cfg config, done func(*fakePipeline) bool) testEnv {
+	t.Helper()
+	logp.TestingSetup()
+
+	in := &amqpInput{config: cfg, dial: broker.dial}
+	pipeline := &fakePipeline{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- in.Run(input.Context{
+			ID:          "test",
+			Logger:      logp.NewLogger(inputName),
+			Cancelation: ctx,
+		}, pipeline)
+	}()
+
+	require.Eventually(t, func() bool { return done(pipeline) }, 10*time.Second, time.Millisecond)
+	cancel()
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("input did not stop")
+	}
+	return testEnv{pipeline: pipeline}
+}
+
+// fakePipeline records the published events and acknowledges them on
+// request.
+type fakePipeline struct {
+	mu       sync.Mutex
+	listener beat.EventListener
+	events   []beat.Event
+	pending  int
+}
+
+func (p *fakePipeline) ConnectWith(cfg beat.ClientConfig) (beat.Client, error) {
+	p.mu.Lock()
+	p.listener = cfg.EventListener
+	p.mu.Unlock()
+	return &pubtest.FakeClient{
+		PublishFunc: func(e beat.Event) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.listener.AddEvent(e, true)
+			p.events = append(p.events, e)
+			p.pending++
+		},
+	}, nil
+}
+
+func (p *fakePipeline) Connect() (beat.Client, error) {
+	return p.ConnectWith(beat.ClientConfig{})
+}
+
+func (p *fakePipeline) published() []beat.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]beat.Event(nil), p.events...)
+}
+
+func (p *fakePipeline) ackAll() {
+	p.mu.Lock()
+	n := p.pending
+	p.pending = 0
+	listener := p.listener
+	p.mu.Unlock()
+	listener.ACKEvents(n)
+}
+
+// fakeBroker hands out connections with a single in-memory channel.
+type fakeBroker struct {
+	mu       sync.Mutex
+	dialErrs []error
+	dials    int
+	conn     *fakeConnection
+}
+
+func (b *fakeBroker) dial(config) (connection, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.dials++
+	if len(b.dialErrs) > 0 {
+		err := b.dialErrs[0]
+		b.dialErrs = b.dialErrs[1:]
+		return nil, err
+	}
+	b.conn = &fakeConnection{}
+	return b.conn, nil
+}
+
+func (b *fakeBroker) dialCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dials
+}
+
+func (b *fakeBroker) channel() *fakeChannel {
+	b.mu.Lock()
+	conn := b.conn
+	b.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	return conn.ch
+}
+
+func (b *fakeBroker) closed() bool {
+	b.mu.Lock()
+	conn := b.conn
+	b.mu.Unlock()
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	return conn.closed
+}
+
+type fakeConnection struct {
+	mu     sync.Mutex
+	ch     *fakeChannel
+	closed bool
+}
+
+func (c *fakeConnection) Channel() (channel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ch = newFakeChannel()
+	return c.ch, nil
+}
+
+func (c *fakeConnection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+type fakeAck struct {
+	tag      uint64
+	multiple bool
+}
+
+// fakeChannel records the topology calls. The fields written during setup
+// are only read once the input has stopped.
+type fakeChannel struct {
+	prefetch  int
+	calls     []string
+	queueArgs amqp091.Table
+	consumer  string
+
+	mu         sync.Mutex
+	deliveries chan amqp091.Delivery
+	closedOnce sync.Once
+	acked      []fakeAck
+	canceled   bool
+}
+
+func newFakeChannel() *fakeChannel {
+	return &fakeChannel{deliveries: make(chan amqp091.Delivery, 16)}
+}
+
+func (c *fakeChannel) Qos(prefetchCount, _ int, _ bool) error {
+	c.prefetch = prefetchCount
+	return nil
+}
+
+func (c *fakeChannel) ExchangeDeclare(name, kind string, durable, _, _, _ bool, _ amqp091.Table) error {
+	c.calls = append(c.calls, "exchange "+name+" "+kind+durability(durable))
+	return nil
+}
+
+func (c *fakeChannel) QueueDeclare(name string, durable, _, _, _ bool, args amqp091.Table) (amqp091.Queue, error) {
+	c.calls = append(c.calls, "queue "+name+durability(durable))
+	c.queueArgs = args
+	return amqp091.Queue{Name: name}, nil
+}
+
+func (c *fakeChannel) QueueBind(name, key, exchange string, _ bool, _ amqp091.Table) error {
+	c.calls = append(c.calls, "bind "+name+" "+exchange+" "+key)
+	return nil
+}
+
+func (c *fakeChannel) Consume(_, consumer string, _, _, _, _ bool, _ amqp091.Table) (<-chan amqp091.Delivery, error) {
+	c.consumer = consumer
+	return c.deliveries, nil
+}
+
+func (c *fakeChannel) Ack(tag uint64, multiple bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.acked = append(c.acked, fakeAck{tag: tag, multiple: multiple})
+	return nil
+}
+
+func (c *fakeChannel) Cancel(_ string, _ bool) error {
+	c.mu.Lock()
+	c.canceled = true
+	c.mu.Unlock()
+	c.closeDeliveries()
+	return nil
+}
+
+func (c *fakeChannel) deliver(d amqp091.Delivery) {
+	c.deliveries <- d
+}
+
+func (c *fakeChannel) closeDeliveries() {
+	c.closedOnce.Do(func() { close(c.deliveries) })
+}
+
+func (c *fakeChannel) acks() []fakeAck {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]fakeAck(nil), c.acked...)
+}
+
+func durability(durable bool) string {
+	if durable {
+		return " durable"
+	}
+	return ""
+}
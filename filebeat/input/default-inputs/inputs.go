@@ -18,8 +18,11 @@
 package inputs
 
 import (
+	"github.com/elastic/beats/v7/filebeat/input/amqp"
 	"github.com/elastic/beats/v7/filebeat/input/filestream"
 	"github.com/elastic/beats/v7/filebeat/input/kafka"
+	"github.com/elastic/beats/v7/filebeat/input/redisstreams"
+	"github.com/elastic/beats/v7/filebeat/input/snmptrap"
 	"github.com/elastic/beats/v7/filebeat/input/tcp"
 	"github.com/elastic/beats/v7/filebeat/input/udp"
 	"github.com/elastic/beats/v7/filebeat/input/unix"
@@ -38,8 +41,11 @@ func Init(info beat.Info, log *logp.Logger, components statestore.States) []v2.P
 
 func genericInputs(log *logp.Logger, components statestore.States) []v2.Plugin {
 	return []v2.Plugin{
+		amqp.Plugin(),
 		filestream.Plugin(log, components),
 		kafka.Plugin(),
+		redisstreams.Plugin(),
+		snmptrap.Plugin(),
 		tcp.Plugin(),
 		udp.Plugin(),
 		unix.Plugin(),
@@ -0,0 +1,439 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package filestream
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	loginp "github.com/elastic/beats/v7/filebeat/input/filestream/internal/input-logfile"
+	input "github.com/elastic/beats/v7/filebeat/input/v2"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common"
+	"github.com/elastic/beats/v7/libbeat/common/acker"
+	"github.com/elastic/beats/v7/libbeat/common/file"
+	"github.com/elastic/beats/v7/libbeat/reader/readfile/encoding"
+	"github.com/elastic/beats/v7/libbeat/statestore"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+const backfillTag = "backfill"
+
+// backfillKeyPrefix prefixes the registry keys marking a backfill as
+// completed. The keys are not managed by the InputManager, as their prefix
+// differs from the filestream keys.
+const backfillKeyPrefix = pluginName + "-backfill::"
+
+// manager wraps the InputManager of filestream so inputs with the backfill
+// mode enabled are created without the registry. All other inputs are
+// created by the wrapped InputManager.
+type manager struct {
+	*loginp.InputManager
+}
+
+type backfillSettings struct {
+	ID       string `config:"id"`
+	Backfill struct {
+		Enabled bool `config:"enabled"`
+	} `config:"backfill"`
+}
+
+func (m manager) Create(cfg *conf.C) (input.Input, error) {
+	var settings backfillSettings
+	if err := cfg.Unpack(&settings); err != nil {
+		return nil, err
+	}
+
+	if !settings.Backfill.Enabled {
+		return m.InputManager.Create(cfg)
+	}
+	if m.HasID(settings.ID) {
+		return nil, &common.ErrNonReloadable{Err: fmt.Errorf(
+			"filestream input with ID '%s' already exists, the backfill input "+
+				"must use a different ID", settings.ID)}
+	}
+	return newBackfillInput(cfg, m.StateStore)
+}
+
+// Delete releases the ID of the input, unless it is a backfill input. The IDs
+// of backfill inputs are not tracked, so that checking a backfill config does
+// not release the ID of another input.
+func (m manager) Delete(cfg *conf.C) error {
+	var settings backfillSettings
+	if err := cfg.Unpack(&settings); err != nil {
+		return fmt.Errorf("could not unpack config to get the input ID: %w", err)
+	}
+
+	if settings.Backfill.Enabled {
+		return nil
+	}
+	return m.InputManager.Delete(cfg)
+}
+
+// backfillInput reads all files matching the configured paths once, from
+// the beginning to the end, and publishes the lines whose timestamp is in
+// the configured time window. It does not read nor write the states of the
+// files in the registry, so the state of the inputs collecting the same files
+// is not affected. Once all events are acknowledged, the backfill is marked
+// as completed in the registry and is not run again for the same window.
+type backfillInput struct {
+	id     string
+	paths  []string
+	window timeWindow
+	reader *filestream
+	store  statestore.States
+}
+
+// backfillState is stored once a backfill is completed.
+type backfillState struct {
+	Start     string    `json:"start" struct:"start"`
+	End       string    `json:"end" struct:"end"`
+	Completed time.Time `json:"completed" struct:"completed"`
+}
+
+type backfillStats struct {
+	files     int
+	published uint64
+	outside   uint64
+	invalid   uint64
+}
+
+func newBackfillInput(cfg *conf.C, store statestore.States) (*backfillInput, error) {
+	config := defaultConfig()
+	if err := cfg.Unpack(&config); err != nil {
+		return nil, err
+	}
+	if config.ID == "" {
+		return nil, errors.New("backfill requires an input ID")
+	}
+
+	window, err := newTimeWindow(config.Backfill)
+	if err != nil {
+		return nil, err
+	}
+
+	encodingFactory, ok := encoding.FindEncoding(config.Reader.Encoding)
+	if !ok || encodingFactory == nil {
+		return nil, fmt.Errorf("unknown encoding('%v')", config.Reader.Encoding)
+	}
+
+	// Files are read only until EOF is reached.
+	closer := config.Close
+	closer.Reader.OnEOF = true
+
+	return &backfillInput{
+		id:     config.ID,
+		paths:  config.Paths,
+		window: window,
+		store:  store,
+		reader: &filestream{
+			readerConfig:    config.Reader,
+			encodingFactory: encodingFactory,
+			detectEncoding:  strings.EqualFold(config.Reader.Encoding, "auto"),
+			closerConfig:    closer,
+			parsers:         config.Reader.Parsers,
+		},
+	}, nil
+}
+
+func (b *backfillInput) Name() string { return pluginName }
+
+func (b *backfillInput) Test(_ input.TestContext) error {
+	files, err := b.files()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no file matches the paths %v", b.paths)
+	}
+	return nil
+}
+
+func (b *backfillInput) Run(ctx input.Context, pipeline beat.PipelineConnector) error {
+	log := ctx.Logger.With("backfill.start", b.window.start, "backfill.end", b.window.end)
+
+	store, err := b.store.StoreFor("")
+	if err != nil {
+		return fmt.Errorf("cannot open the registry: %w", err)
+	}
+	defer store.Close()
+
+	key := backfillKeyPrefix + b.id
+	var done backfillState
+	if err := store.Get(key, &done); err == nil && done.Start == b.window.startString() && done.End == b.window.endString() {
+		log.Infof("Backfill was completed at %v already, remove the registry key '%s' to run it again", done.Completed, key)
+		return nil
+	}
+
+	files, err := b.files()
+	if err != nil {
+		return err
+	}
+
+	// Counting reports all published events once they are acknowledged,
+	// including the events dropped by processors.
+	var acked atomic.Uint64
+	ackSignal := make(chan struct{}, 1)
+	client, err := pipeline.ConnectWith(beat.ClientConfig{
+		PublishMode: beat.DefaultGuarantees,
+		EventListener: acker.Counting(func(n int) {
+			acked.Add(uint64(n))
+			select {
+			case ackSignal <- struct{}{}:
+			default:
+			}
+		}),
+	})
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	log.Infof("Backfill started, %d files match the configured paths", len(files))
+
+	var stats backfillStats
+	for _, path := range files {
+		if ctx.Cancelation.Err() != nil {
+			log.Infof("Backfill cancelled after reading %d files", stats.files)
+			return nil
+		}
+
+		if err := b.replay(ctx, log.With("path", path), client, path, &stats); err != nil {
+			log.Errorf("Failed to backfill file %s: %v", path, err)
+			continue
+		}
+		stats.files++
+	}
+
+	log.Infof("Backfill finished: %d files read, %d events published, "+
+		"%d events outside of the time window, %d events without a valid timestamp",
+		stats.files, stats.published, stats.outside, stats.invalid)
+
+	for acked.Load() < stats.published {
+		select {
+		case <-ctx.Cancelation.Done():
+			log.Infof("Backfill cancelled before all events were acknowledged, it will run again")
+			return nil
+		case <-ackSignal:
+		}
+	}
+
+	err = store.Set(key, backfillState{
+		Start:     b.window.startString(),
+		End:       b.window.endString(),
+		Completed: time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot mark the backfill as completed: %w", err)
+	}
+	log.Infof("Backfill marked as completed in registry key '%s'", key)
+	return nil
+}
+
+// replay reads the file at path until EOF and publishes the events within
+// the time window.
+func (b *backfillInput) replay(
+	ctx input.Context,
+	log *logp.Logger,
+	client beat.Client,
+	path string,
+	stats *backfillStats,
+) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	src := fileSource{
+		desc: loginp.FileDescriptor{
+			Filename: path,
+			Info:     file.ExtendFileInfo(fi),
+		},
+		newPath: path,
+	}
+
+	r, _, err := b.reader.open(log, ctx.Cancelation, src, &state{})
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for ctx.Cancelation.Err() == nil {
+		message, err := r.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		if message.IsEmpty() || b.reader.isDroppedLine(log, string(message.Content)) {
+			continue
+		}
+
+		event := message.ToEvent()
+		ts, err := b.window.timestamp(&event)
+		if err != nil {
+			log.Debugf("Skipping event without a valid timestamp: %v", err)
+			stats.invalid++
+			continue
+		}
+		if !b.window.contains(ts) {
+			stats.outside++
+			continue
+		}
+
+		_ = mapstr.AddTags(event.Fields, []string{backfillTag})
+		client.Publish(event)
+		stats.published++
+	}
+	return nil
+}
+
+// files returns the regular files matching the configured paths, sorted by
+// name.
+func (b *backfillInput) files() ([]string, error) {
+	seen := map[string]struct{}{}
+	var files []string
+	for _, pattern := range b.paths {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path '%s': %w", pattern, err)
+		}
+
+		for _, path := range matches {
+			if _, ok := seen[path]; ok {
+				continue
+			}
+			fi, err := os.Stat(path)
+			if err != nil || !fi.Mode().IsRegular() {
+				continue
+			}
+			seen[path] = struct{}{}
+			files = append(files, path)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// timeWindow reads the timestamp of events and checks if it is within
+// [start, end].
+type timeWindow struct {
+	start    time.Time
+	end      time.Time
+	field    string
+	pattern  *regexp.Regexp
+	layouts  []string
+	location *time.Location
+}
+
+func newTimeWindow(c backfillConfig) (timeWindow, error) {
+	var w timeWindow
+	var err error
+
+	if c.Start == "" || c.End == "" {
+		return w, errors.New("backfill requires both 'start' and 'end' to be set")
+	}
+	if w.start, err = time.Parse(time.RFC3339Nano, c.Start); err != nil {
+		return w, fmt.Errorf("invalid backfill start: %w", err)
+	}
+	if w.end, err = time.Parse(time.RFC3339Nano, c.End); err != nil {
+		return w, fmt.Errorf("invalid backfill end: %w", err)
+	}
+	if !w.end.After(w.start) {
+		return w, errors.New("backfill end must be after start")
+	}
+
+	if c.Timestamp.Field == "" {
+		return w, errors.New("backfill requires 'timestamp.field' to be set")
+	}
+	w.field = c.Timestamp.Field
+
+	if c.Timestamp.Pattern != "" {
+		if w.pattern, err = regexp.Compile(c.Timestamp.Pattern); err != nil {
+			return w, fmt.Errorf("invalid backfill timestamp pattern: %w", err)
+		}
+		if w.pattern.NumSubexp() != 1 {
+			return w, errors.New("backfill timestamp pattern must have exactly one capturing group")
+		}
+	}
+
+	if len(c.Timestamp.Layouts) == 0 {
+		return w, errors.New("backfill requires at least one timestamp layout")
+	}
+	w.layouts = c.Timestamp.Layouts
+
+	if w.location, err = time.LoadLocation(c.Timestamp.Timezone); err != nil {
+		return w, fmt.Errorf("invalid backfill timezone: %w", err)
+	}
+
+	return w, nil
+}
+
+// timestamp returns the timestamp of the event read from the configured
+// field.
+func (w timeWindow) timestamp(event *beat.Event) (time.Time, error) {
+	v, err := event.GetValue(w.field)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("cannot read field '%s': %w", w.field, err)
+	}
+
+	var s string
+	switch v := v.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		s = v
+	default:
+		return time.Time{}, fmt.Errorf("field '%s' is of type %T, not a string", w.field, v)
+	}
+
+	if w.pattern != nil {
+		m := w.pattern.FindStringSubmatch(s)
+		if m == nil {
+			return time.Time{}, fmt.Errorf("field '%s' does not match the timestamp pattern", w.field)
+		}
+		s = m[1]
+	}
+
+	for _, layout := range w.layouts {
+		if ts, err := time.ParseInLocation(layout, s, w.location); err == nil {
+			return ts, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse '%s' with any of the layouts %v", s, w.layouts)
+}
+
+func (w timeWindow) startString() string { return w.start.Format(time.RFC3339Nano) }
+
+func (w timeWindow) endString() string { return w.end.Format(time.RFC3339Nano) }
+
+func (w timeWindow) contains(ts time.Time) bool {
+	return !ts.Before(w.start) && !ts.After(w.end)
+}
@@ -0,0 +1,351 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package filestream
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v2 "github.com/elastic/beats/v7/filebeat/input/v2"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	pubtest "github.com/elastic/beats/v7/libbeat/publisher/testing"
+	"github.com/elastic/beats/v7/libbeat/statestore"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/elastic-agent-libs/monitoring"
+)
+
+func TestBackfillConfigValidate(t *testing.T) {
+	tcs := map[string]struct {
+		cfg    mapstr.M
+		errMsg string
+	}{
+		"valid": {
+			cfg: mapstr.M{
+				"backfill.start": "2024-01-01T00:00:00Z",
+				"backfill.end":   "2024-01-02T00:00:00Z",
+			},
+		},
+		"start is required": {
+			cfg: mapstr.M{
+				"backfill.end": "2024-01-02T00:00:00Z",
+			},
+			errMsg: "requires both 'start' and 'end'",
+		},
+		"end must be after start": {
+			cfg: mapstr.M{
+				"backfill.start": "2024-01-02T00:00:00Z",
+				"backfill.end":   "2024-01-01T00:00:00Z",
+			},
+			errMsg: "end must be after start",
+		},
+		"invalid start": {
+			cfg: mapstr.M{
+				"backfill.start": "yesterday",
+				"backfill.end":   "2024-01-01T00:00:00Z",
+			},
+			errMsg: "invalid backfill start",
+		},
+		"pattern needs one group": {
+			cfg: mapstr.M{
+				"backfill.start":             "2024-01-01T00:00:00Z",
+				"backfill.end":               "2024-01-02T00:00:00Z",
+				"backfill.timestamp.pattern": `^(\S+) (\S+)`,
+			},
+			errMsg: "exactly one capturing group",
+		},
+		"invalid timezone": {
+			cfg: mapstr.M{
+				"backfill.start":              "2024-01-01T00:00:00Z",
+				"backfill.end":                "2024-01-02T00:00:00Z",
+				"backfill.timestamp.timezone": "Mars/Olympus_Mons",
+			},
+			errMsg: "invalid backfill timezone",
+		},
+		"take_over is not allowed": {
+			cfg: mapstr.M{
+				"id":                "foo",
+				"take_over.enabled": true,
+				"backfill.start":    "2024-01-01T00:00:00Z",
+				"backfill.end":      "2024-01-02T00:00:00Z",
+			},
+			errMsg: "backfill and take_over cannot be enabled",
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			tc.cfg["paths"] = []string{"/foo/bar"}
+			tc.cfg["backfill.enabled"] = true
+
+			c := defaultConfig()
+			err := conf.MustNewConfigFrom(tc.cfg).Unpack(&c)
+			if tc.errMsg == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestTimeWindowTimestamp(t *testing.T) {
+	newWindow := func(t *testing.T, ts backfillTimestampConfig) timeWindow {
+		c := defaultBackfillConfig()
+		c.Start = "2024-01-01T00:00:00Z"
+		c.End = "2024-01-02T00:00:00Z"
+		if ts.Field != "" {
+			c.Timestamp.Field = ts.Field
+		}
+		if len(ts.Layouts) > 0 {
+			c.Timestamp.Layouts = ts.Layouts
+		}
+		if ts.Timezone != "" {
+			c.Timestamp.Timezone = ts.Timezone
+		}
+		c.Timestamp.Pattern = ts.Pattern
+
+		w, err := newTimeWindow(c)
+		require.NoError(t, err)
+		return w
+	}
+
+	t.Run("pattern extracts the timestamp", func(t *testing.T) {
+		w := newWindow(t, backfillTimestampConfig{Pattern: `^(\S+) `})
+		event := beat.Event{Fields: mapstr.M{"message": "2024-01-01T10:00:00.5Z INFO started"}}
+
+		ts, err := w.timestamp(&event)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 500_000_000, time.UTC), ts.UTC())
+		assert.True(t, w.contains(ts))
+	})
+
+	t.Run("layouts are tried in order", func(t *testing.T) {
+		w := newWindow(t, backfillTimestampConfig{
+			Field:    "log.time",
+			Layouts:  []string{time.RFC3339, "2006-01-02 15:04:05"},
+			Timezone: "Europe/Berlin",
+		})
+		event := beat.Event{Fields: mapstr.M{"log": mapstr.M{"time": "2024-01-02 00:30:00"}}}
+
+		ts, err := w.timestamp(&event)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2024, 1, 1, 23, 30, 0, 0, time.UTC), ts.UTC())
+		assert.True(t, w.contains(ts))
+	})
+
+	t.Run("time values are used as is", func(t *testing.T) {
+		w := newWindow(t, backfillTimestampConfig{Field: "@timestamp"})
+		event := beat.Event{Timestamp: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)}
+
+		ts, err := w.timestamp(&event)
+		require.NoError(t, err)
+		assert.False(t, w.contains(ts))
+	})
+
+	t.Run("invalid timestamps are reported", func(t *testing.T) {
+		w := newWindow(t, backfillTimestampConfig{Pattern: `^\[(.+)\]`})
+
+		for _, fields := range []mapstr.M{
+			{},
+			{"message": 42},
+			{"message": "no brackets"},
+			{"message": "[not a date] message"},
+		} {
+			event := beat.Event{Fields: fields}
+			_, err := w.timestamp(&event)
+			assert.Error(t, err, "fields: %v", fields)
+		}
+	})
+}
+
+func TestBackfillInput(t *testing.T) {
+	dir := t.TempDir()
+	lines := map[string][]string{
+		"app.log.1": {
+			"2024-01-01T09:00:00Z before the window",
+			"2024-01-01T10:00:00Z first",
+			"not a timestamp",
+		},
+		"app.log": {
+			"2024-01-01T11:00:00Z second",
+			"2024-01-01T12:00:00Z dropped by exclude_lines",
+			"2024-01-01T13:00:00Z after the window",
+		},
+	}
+	for name, content := range lines {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(strings.Join(content, "\n")+"\n"), 0o644)
+		require.NoError(t, err)
+	}
+
+	cfg := conf.MustNewConfigFrom(mapstr.M{
+		"id":                         "backfill",
+		"paths":                      []string{filepath.Join(dir, "app.log*")},
+		"exclude_lines":              []string{"exclude_lines"},
+		"backfill.enabled":           true,
+		"backfill.start":             "2024-01-01T10:00:00Z",
+		"backfill.end":               "2024-01-01T12:30:00Z",
+		"backfill.timestamp.pattern": `^(\S+) `,
+	})
+
+	store := createTestStore(t)
+	p := Plugin(logp.L(), store)
+	inp, err := p.Manager.Create(cfg)
+	require.NoError(t, err)
+	require.IsType(t, &backfillInput{}, inp)
+
+	ctx := v2.Context{
+		ID:              "backfill",
+		Cancelation:     context.Background(),
+		MetricsRegistry: monitoring.NewRegistry(),
+		Logger:          logp.L(),
+	}
+	events := make(chan beat.Event, 10)
+	require.NoError(t, inp.Run(ctx, ackingPipeline(events)))
+
+	var messages []string
+	for len(events) > 0 {
+		event := <-events
+		msg, err := event.Fields.GetValue("message")
+		require.NoError(t, err)
+		messages = append(messages, msg.(string))
+
+		tags, err := event.Fields.GetValue("tags")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"backfill"}, tags)
+	}
+	assert.Equal(t, []string{
+		"2024-01-01T11:00:00Z second",
+		"2024-01-01T10:00:00Z first",
+	}, messages)
+
+	// only the completion of the backfill is stored
+	s, err := store.StoreFor("")
+	require.NoError(t, err)
+	defer s.Close()
+	var keys []string
+	err = s.Each(func(key string, _ statestore.ValueDecoder) (bool, error) {
+		keys = append(keys, key)
+		return true, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"filestream-backfill::backfill"}, keys)
+
+	// a completed backfill is not run again, like after a restart
+	inp, err = p.Manager.Create(cfg)
+	require.NoError(t, err)
+	require.NoError(t, inp.Run(ctx, ackingPipeline(events)))
+	assert.Empty(t, events)
+
+	// a different window is run again
+	require.NoError(t, cfg.SetString("backfill.end", -1, "2024-01-01T11:30:00Z"))
+	inp, err = p.Manager.Create(cfg)
+	require.NoError(t, err)
+	require.NoError(t, inp.Run(ctx, ackingPipeline(events)))
+	assert.Len(t, events, 2)
+}
+
+func TestBackfillInputNotCompletedWithoutACK(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "app.log"), []byte("2024-01-01T10:00:00Z first\n"), 0o644)
+	require.NoError(t, err)
+
+	store := createTestStore(t)
+	inp, err := newBackfillInput(conf.MustNewConfigFrom(mapstr.M{
+		"id":                         "backfill",
+		"paths":                      []string{filepath.Join(dir, "app.log")},
+		"backfill.enabled":           true,
+		"backfill.start":             "2024-01-01T00:00:00Z",
+		"backfill.end":               "2024-01-02T00:00:00Z",
+		"backfill.timestamp.pattern": `^(\S+) `,
+	}), store)
+	require.NoError(t, err)
+
+	// the events are never acknowledged
+	pipeline, _ := newTestPipeline(10, true)
+	cancelCtx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	require.NoError(t, inp.Run(v2.Context{
+		ID:              "backfill",
+		Cancelation:     cancelCtx,
+		MetricsRegistry: monitoring.NewRegistry(),
+		Logger:          logp.L(),
+	}, pipeline))
+
+	s, err := store.StoreFor("")
+	require.NoError(t, err)
+	defer s.Close()
+	has, err := s.Has("filestream-backfill::backfill")
+	require.NoError(t, err)
+	assert.False(t, has)
+}
+
+func TestBackfillInputIDs(t *testing.T) {
+	backfillCfg := func(id string) *conf.C {
+		return conf.MustNewConfigFrom(mapstr.M{
+			"id":               id,
+			"paths":            []string{filepath.Join(t.TempDir(), "*.log")},
+			"backfill.enabled": true,
+			"backfill.start":   "2024-01-01T10:00:00Z",
+			"backfill.end":     "2024-01-01T12:30:00Z",
+		})
+	}
+	liveCfg := conf.MustNewConfigFrom(mapstr.M{
+		"id":    "live",
+		"paths": []string{filepath.Join(t.TempDir(), "*.log")},
+	})
+
+	p := Plugin(logp.L(), createTestStore(t))
+	_, err := p.Manager.Create(liveCfg)
+	require.NoError(t, err)
+
+	_, err = p.Manager.Create(backfillCfg("live"))
+	require.ErrorContains(t, err, "filestream input with ID 'live' already exists")
+
+	_, err = p.Manager.Create(backfillCfg(""))
+	require.ErrorContains(t, err, "backfill requires an input ID")
+
+	// deleting a backfill config, like after checking it, keeps the ID of
+	// the live input
+	require.NoError(t, p.Manager.(manager).Delete(backfillCfg("live")))
+	_, err = p.Manager.Create(liveCfg)
+	require.ErrorContains(t, err, "filestream input with ID 'live' already exists")
+}
+
+// ackingPipeline forwards the published events to ch and acknowledges them.
+func ackingPipeline(ch chan beat.Event) beat.PipelineConnector {
+	return pubtest.FakeConnector{
+		ConnectFunc: func(cfg beat.ClientConfig) (beat.Client, error) {
+			return &pubtest.FakeClient{
+				PublishFunc: func(event beat.Event) {
+					cfg.EventListener.AddEvent(event, true)
+					ch <- event
+					cfg.EventListener.ACKEvents(1)
+				},
+			}, nil
+		},
+	}
+}
@@ -50,6 +50,7 @@ type config struct {
 	IgnoreInactive ignoreInactiveType `config:"ignore_inactive"`
 	Rotation       *conf.Namespace    `config:"rotation"`
 	TakeOver       takeOverConfig     `config:"take_over"`
+	Backfill       backfillConfig     `config:"backfill"`
 
 	// AllowIDDuplication is used by InputManager.Create
 	// (see internal/input-logfile/manager.go).
@@ -61,6 +62,28 @@ type takeOverConfig struct {
 	FromIDs []string `config:"from_ids"`
 }
 
+// backfillConfig configures the backfill mode. In backfill mode the input
+// reads every file matching Paths once, publishing only the lines whose
+// timestamp is within [Start, End]. The registry is never read nor updated.
+type backfillConfig struct {
+	Enabled   bool                    `config:"enabled"`
+	Start     string                  `config:"start"`
+	End       string                  `config:"end"`
+	Timestamp backfillTimestampConfig `config:"timestamp"`
+}
+
+type backfillTimestampConfig struct {
+	// Field is the event field holding the timestamp.
+	Field string `config:"field"`
+	// Pattern is an optional regular expression with one capturing group
+	// used to extract the timestamp from Field.
+	Pattern string `config:"pattern"`
+	// Layouts are the Go time layouts tried, in order, to parse the timestamp.
+	Layouts []string `config:"layouts"`
+	// Timezone is used for timestamps without time zone information.
+	Timezone string `config:"timezone"`
+}
+
 type closerConfig struct {
 	OnStateChange stateChangeCloserConfig `config:"on_state_change"`
 	Reader        readerCloserConfig      `config:"reader"`
@@ -83,6 +106,7 @@ type readerConfig struct {
 	BufferSize     int                     `config:"buffer_size"`
 	Encoding       string                  `config:"encoding"`
 	ExcludeLines   []match.Matcher         `config:"exclude_lines"`
+	Framing        readfile.FramingConfig  `config:"framing"`
 	IncludeLines   []match.Matcher         `config:"include_lines"`
 	LineTerminator readfile.LineTerminator `config:"line_terminator"`
 	MaxBytes       int                     `config:"message_max_bytes" validate:"min=0,nonzero"`
@@ -116,6 +140,17 @@ func defaultConfig() config {
 		CleanRemoved:   true,
 		HarvesterLimit: 0,
 		IgnoreOlder:    0,
+		Backfill:       defaultBackfillConfig(),
+	}
+}
+
+func defaultBackfillConfig() backfillConfig {
+	return backfillConfig{
+		Timestamp: backfillTimestampConfig{
+			Field:    "message",
+			Layouts:  []string{time.RFC3339Nano},
+			Timezone: "UTC",
+		},
 	}
 }
 
@@ -168,9 +203,22 @@ func (c *config) Validate() error {
 		return errors.New("'take_over' mode is only allowed if an input ID is set")
 	}
 
+	if c.Backfill.Enabled && c.TakeOver.Enabled {
+		return errors.New("backfill and take_over cannot be enabled at the same time")
+	}
+
 	return nil
 }
 
+func (c *backfillConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	_, err := newTimeWindow(*c)
+	return err
+}
+
 // ValidateInputIDs checks all filestream inputs to ensure all input IDs are
 // unique. If there is a duplicated ID, it logs an error containing the offending
 // input configurations and returns an error containing the duplicated IDs.
@@ -64,7 +64,9 @@ type inputTestingEnvironment struct {
 
 type registryEntry struct {
 	Cursor struct {
-		Offset int `json:"offset"`
+		Offset    int      `json:"offset"`
+		CSVHeader []string `json:"csv_header" struct:"csv_header"`
+		Encoding  string   `json:"encoding" struct:"encoding"`
 	} `json:"cursor"`
 	Meta any `json:"meta,omitempty"`
 }
@@ -23,6 +23,7 @@ import (
 	"io"
 	"os"
 	"slices"
+	"strings"
 	"time"
 
 	"golang.org/x/text/transform"
@@ -50,6 +51,12 @@ const pluginName = "filestream"
 
 type state struct {
 	Offset int64 `json:"offset" struct:"offset"`
+	// Encoding is the name of the encoding detected when the file was
+	// first opened, if the auto encoding is configured.
+	Encoding string `json:"encoding,omitempty" struct:"encoding,omitempty"`
+	// State holds the state of the parsers, like the header read by the
+	// csv parser, needed to resume reading in the middle of the file.
+	parser.State `struct:",inline"`
 }
 
 type fileMeta struct {
@@ -62,6 +69,7 @@ type fileMeta struct {
 type filestream struct {
 	readerConfig    readerConfig
 	encodingFactory encoding.EncodingFactory
+	detectEncoding  bool
 	closerConfig    closerConfig
 	parsers         parser.Config
 	takeOver        takeOverConfig
@@ -75,12 +83,14 @@ func Plugin(log *logp.Logger, store statestore.States) input.Plugin {
 		Deprecated: false,
 		Info:       "filestream input",
 		Doc:        "The filestream input collects logs from the local filestream service",
-		Manager: &loginp.InputManager{
-			Logger:              log,
-			StateStore:          store,
-			Type:                pluginName,
-			Configure:           configure,
-			DefaultCleanTimeout: -1,
+		Manager: manager{
+			InputManager: &loginp.InputManager{
+				Logger:              log,
+				StateStore:          store,
+				Type:                pluginName,
+				Configure:           configure,
+				DefaultCleanTimeout: -1,
+			},
 		},
 	}
 }
@@ -104,6 +114,7 @@ func configure(cfg *conf.C) (loginp.Prospector, loginp.Harvester, error) {
 	filestream := &filestream{
 		readerConfig:    config.Reader,
 		encodingFactory: encodingFactory,
+		detectEncoding:  strings.EqualFold(config.Reader.Encoding, "auto"),
 		closerConfig:    config.Close,
 		parsers:         config.Reader.Parsers,
 		takeOver:        config.TakeOver,
@@ -120,7 +131,7 @@ func (inp *filestream) Test(src loginp.Source, ctx input.TestContext) error {
 		return fmt.Errorf("not file source")
 	}
 
-	reader, _, err := inp.open(ctx.Logger, ctx.Cancelation, fs, 0)
+	reader, _, err := inp.open(ctx.Logger, ctx.Cancelation, fs, &state{})
 	if err != nil {
 		return err
 	}
@@ -142,7 +153,7 @@ func (inp *filestream) Run(
 	log := ctx.Logger.With("path", fs.newPath).With("state-id", src.Name())
 	state := initState(log, cursor, fs)
 
-	r, truncated, err := inp.open(log, ctx.Cancelation, fs, state.Offset)
+	r, truncated, err := inp.open(log, ctx.Cancelation, fs, &state)
 	if err != nil {
 		log.Errorf("File could not be opened for reading: %v", err)
 		return err
@@ -168,7 +179,7 @@ func (inp *filestream) Run(
 
 	// The caller of Run already reports the error and filters out errors that
 	// must not be reported, like 'context cancelled'.
-	return inp.readFromSource(ctx, log, r, fs.newPath, state, publisher, metrics)
+	return inp.readFromSource(ctx, log, r, fs.newPath, &state, publisher, metrics)
 }
 
 func initState(log *logp.Logger, c loginp.Cursor, s fileSource) state {
@@ -189,16 +200,18 @@ func (inp *filestream) open(
 	log *logp.Logger,
 	canceler input.Canceler,
 	fs fileSource,
-	offset int64,
+	st *state,
 ) (reader.Reader, bool, error) {
 
-	f, encoding, truncated, err := inp.openFile(log, fs.newPath, offset)
+	offset := st.Offset
+	f, encoding, truncated, err := inp.openFile(log, fs.newPath, offset, &st.Encoding)
 	if err != nil {
 		return nil, truncated, err
 	}
 
 	if truncated {
 		offset = 0
+		st.State = parser.State{}
 	}
 
 	ok := false // used for cleanup
@@ -238,21 +251,38 @@ func (inp *filestream) open(
 	encReaderMaxBytes := inp.readerConfig.MaxBytes * 4
 
 	var r reader.Reader
-	r, err = readfile.NewEncodeReader(dbgReader, readfile.Config{
-		Codec:      encoding,
-		BufferSize: inp.readerConfig.BufferSize,
-		Terminator: inp.readerConfig.LineTerminator,
-		MaxBytes:   encReaderMaxBytes,
-	})
-	if err != nil {
-		return nil, truncated, err
-	}
+	if inp.readerConfig.Framing.Type == readfile.LineFraming {
+		r, err = readfile.NewEncodeReader(dbgReader, readfile.Config{
+			Codec:      encoding,
+			BufferSize: inp.readerConfig.BufferSize,
+			Terminator: inp.readerConfig.LineTerminator,
+			MaxBytes:   encReaderMaxBytes,
+		})
+		if err != nil {
+			return nil, truncated, err
+		}
 
-	r = readfile.NewStripNewline(r, inp.readerConfig.LineTerminator)
+		r = readfile.NewStripNewline(r, inp.readerConfig.LineTerminator)
+	} else {
+		// Records are split before being decoded, so the same limit
+		// as the EncodeReader is used for the raw records.
+		split, err := inp.readerConfig.Framing.SplitFunc(encReaderMaxBytes)
+		if err != nil {
+			return nil, truncated, err
+		}
+
+		r, err = readfile.NewFrameReader(dbgReader, split, readfile.Config{
+			Codec:      encoding,
+			BufferSize: inp.readerConfig.BufferSize,
+		})
+		if err != nil {
+			return nil, truncated, err
+		}
+	}
 
 	r = readfile.NewFilemeta(r, fs.newPath, fs.desc.Info, fs.desc.Fingerprint, offset)
 
-	r = inp.parsers.Create(r)
+	r = inp.parsers.CreateWithState(r, &st.State)
 
 	r = readfile.NewLimitReader(r, inp.readerConfig.MaxBytes)
 
@@ -267,10 +297,15 @@ func (inp *filestream) open(
 //
 // openFile will also detect and hadle file truncation. If a file is truncated
 // then the 3rd return value is true.
+//
+// With the auto encoding, detected holds the name of the encoding detected
+// the first time the file was opened. It is set when the encoding is
+// detected and reset when the file is truncated.
 func (inp *filestream) openFile(
 	log *logp.Logger,
 	path string,
 	offset int64,
+	detected *string,
 ) (*os.File, encoding.Encoding, bool, error) {
 	fi, err := os.Stat(path)
 	if err != nil {
@@ -307,13 +342,14 @@ func (inp *filestream) openFile(
 		truncated = true
 		log.Infof("File was truncated. Reading file from offset 0. Path=%s", path)
 		offset = 0
+		*detected = ""
 	}
 	err = inp.initFileOffset(f, offset)
 	if err != nil {
 		return nil, nil, truncated, err
 	}
 
-	encoding, err := inp.encodingFactory(f)
+	encoding, err := inp.newEncoding(log, f, detected)
 	if err != nil {
 		if errors.Is(err, transform.ErrShortSrc) {
 			return nil, nil, truncated, fmt.Errorf("initialising encoding for '%v' failed due to file being too short", f)
@@ -325,6 +361,27 @@ func (inp *filestream) openFile(
 	return f, encoding, truncated, nil
 }
 
+// newEncoding creates the encoding of the file. With the auto encoding, the
+// encoding is detected once and used for as long as the file is read.
+func (inp *filestream) newEncoding(log *logp.Logger, f *os.File, detected *string) (encoding.Encoding, error) {
+	if !inp.detectEncoding {
+		return inp.encodingFactory(f)
+	}
+	if *detected != "" {
+		if enc, ok := encoding.Detected(*detected); ok {
+			return enc, nil
+		}
+	}
+
+	name, enc, err := encoding.Detect(f)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("Detected encoding %s", name)
+	*detected = name
+	return enc, nil
+}
+
 func checkFileBeforeOpening(fi os.FileInfo) error {
 	if !fi.Mode().IsRegular() {
 		return fmt.Errorf("tried to open non regular file: %q %s", fi.Mode(), fi.Name())
@@ -349,7 +406,7 @@ func (inp *filestream) readFromSource(
 	log *logp.Logger,
 	r reader.Reader,
 	path string,
-	s state,
+	s *state,
 	p loginp.Publisher,
 	metrics *loginp.Metrics,
 ) error {
@@ -400,7 +457,7 @@ func (inp *filestream) readFromSource(
 			_ = mapstr.AddTags(message.Fields, []string{"take_over"})
 		}
 
-		if err := p.Publish(message.ToEvent(), s); err != nil {
+		if err := p.Publish(message.ToEvent(), *s); err != nil {
 			metrics.ProcessingErrors.Inc()
 			return err
 		}
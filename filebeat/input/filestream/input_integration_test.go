@@ -374,6 +374,59 @@ func TestFilestreamUTF16BOMs(t *testing.T) {
 	}
 }
 
+func TestFilestreamAutoEncoding(t *testing.T) {
+	env := newInputTestingEnvironment(t)
+
+	id := "fake-ID-" + uuid.Must(uuid.NewV4()).String()
+	inp := env.mustCreateInput(map[string]interface{}{
+		"id":                                     id,
+		"paths":                                  []string{env.abspath("*.log")},
+		"encoding":                               "auto",
+		"prospector.scanner.check_interval":      "1ms",
+		"prospector.scanner.fingerprint.enabled": false,
+		"file_identity.native":                   map[string]any{},
+	})
+
+	files := map[string]struct {
+		enc      encoding.Encoding
+		detected string
+	}{
+		"utf16le.log":     {unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), "utf-16le"},
+		"utf16be-bom.log": {unicode.UTF16(unicode.BigEndian, unicode.UseBOM), "utf-16be"},
+		"utf8.log":        {unicode.UTF8, "utf-8"},
+	}
+	sizes := map[string]int{}
+	for name, f := range files {
+		buf := bytes.NewBuffer(nil)
+		writer := transform.NewWriter(buf, f.enc.NewEncoder())
+		writer.Write([]byte("first line\nsecond line\n"))
+		writer.Close()
+		env.mustWriteToFile(name, buf.Bytes())
+		sizes[name] = buf.Len()
+	}
+
+	ctx, cancelInput := context.WithCancel(context.Background())
+	env.startInput(ctx, id, inp)
+
+	env.waitUntilEventCount(6)
+	for name, f := range files {
+		env.requireOffsetInRegistry(name, id, sizes[name])
+
+		fi, err := os.Stat(env.abspath(name))
+		require.NoError(t, err)
+		entry, err := env.getRegistryState(getIDFromPath(env.abspath(name), id, fi))
+		require.NoError(t, err)
+		require.Equal(t, f.detected, entry.Cursor.Encoding, name)
+	}
+
+	cancelInput()
+	env.waitUntilInputStops()
+
+	for _, msg := range env.getOutputMessages() {
+		require.Contains(t, []string{"first line", "second line"}, msg)
+	}
+}
+
 // test_close_timeout from test_harvester.py
 func TestFilestreamCloseTimeout(t *testing.T) {
 	env := newInputTestingEnvironment(t)
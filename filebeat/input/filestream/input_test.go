@@ -19,9 +19,11 @@ package filestream
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -162,6 +164,101 @@ paths:
 	}
 }
 
+func TestFraming(t *testing.T) {
+	records := []string{"first record\nwith a new line", "second record", "third record"}
+
+	testCases := map[string]struct {
+		framing string
+		content []byte
+	}{
+		"length_prefixed": {
+			framing: `
+framing.type: length_prefixed
+framing.length_prefix: uint16le`,
+			content: func() []byte {
+				var buf []byte
+				for _, r := range records {
+					buf = binary.LittleEndian.AppendUint16(buf, uint16(len(r)))
+					buf = append(buf, r...)
+				}
+				return buf
+			}(),
+		},
+		"delimiter": {
+			framing: `
+framing.type: delimiter
+framing.delimiter: "\x1e\x1e"`,
+			content: []byte(strings.Join(records, "\x1e\x1e") + "\x1e\x1e"),
+		},
+	}
+
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "records.bin")
+			require.NoError(t, os.WriteFile(filename, testCase.content, 0o644))
+
+			cfg := fmt.Sprintf(`
+type: filestream
+id: framing-%s
+prospector.scanner.check_interval: 1s
+prospector.scanner.fingerprint.enabled: false
+paths:
+    - %s
+%s`, name, filename, testCase.framing)
+			runner := createFilestreamTestRunner(context.Background(), t, "framing-"+name, cfg, int64(len(records)), true)
+			events := runner(t)
+
+			require.Len(t, events, len(records))
+			var offset int64
+			for i, event := range events {
+				msg, err := event.GetValue("message")
+				require.NoError(t, err)
+				require.Equal(t, records[i], msg)
+
+				off, err := event.GetValue("log.offset")
+				require.NoError(t, err)
+				require.Equal(t, offset, off)
+				offset += int64(len(records[i]) + 2)
+			}
+		})
+	}
+}
+
+func TestFramingSkipsLargeRecords(t *testing.T) {
+	// records are split before decoding, with 4 times message_max_bytes
+	// as the limit
+	large := strings.Repeat("x", 100)
+	content := "first\x1e\x1e" + large + "\x1e\x1esecond\x1e\x1ethird\x1e\x1e"
+	filename := filepath.Join(t.TempDir(), "records.bin")
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0o644))
+
+	cfg := fmt.Sprintf(`
+type: filestream
+id: framing-large-records
+prospector.scanner.check_interval: 1s
+prospector.scanner.fingerprint.enabled: false
+message_max_bytes: 10
+framing.type: delimiter
+framing.delimiter: "\x1e\x1e"
+paths:
+    - %s`, filename)
+	runner := createFilestreamTestRunner(context.Background(), t, "framing-large-records", cfg, 3, true)
+	events := runner(t)
+
+	require.Len(t, events, 3)
+	// like skipped lines, the skipped record is part of the next record
+	wantOffsets := []int64{0, 7, int64(7 + len(large) + 2 + 8)}
+	for i, want := range []string{"first", "second", "third"} {
+		msg, err := events[i].GetValue("message")
+		require.NoError(t, err)
+		require.Equal(t, want, msg)
+
+		off, err := events[i].GetValue("log.offset")
+		require.NoError(t, err)
+		require.Equal(t, wantOffsets[i], off)
+	}
+}
+
 // runFilestreamBenchmark runs the entire filestream input with the in-memory registry and the test pipeline.
 // `testID` must be unique for each test run
 // `cfg` must be a valid YAML string containing valid filestream configuration
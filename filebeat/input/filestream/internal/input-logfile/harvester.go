@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/elastic/beats/v7/filebeat/input/filestream/internal/task"
@@ -52,12 +53,20 @@ type Harvester interface {
 
 type readerGroup struct {
 	mu    sync.Mutex
-	table map[string]context.CancelFunc
+	table map[string]*reader
+}
+
+// reader tracks a running Harvester.
+type reader struct {
+	cancel  context.CancelFunc
+	started time.Time
+	// lastEvent is the time the last event was published in Unix nanoseconds.
+	lastEvent atomic.Int64
 }
 
 func newReaderGroup() *readerGroup {
 	return &readerGroup{
-		table: make(map[string]context.CancelFunc),
+		table: make(map[string]*reader),
 	}
 }
 
@@ -77,7 +86,7 @@ func (r *readerGroup) newContext(id string, cancelation inputv2.Canceler) (conte
 
 	ctx, cancel := context.WithCancel(ctxtool.FromCanceller(cancelation))
 
-	r.table[id] = cancel
+	r.table[id] = &reader{cancel: cancel, started: time.Now()}
 	return ctx, cancel, nil
 }
 
@@ -85,15 +94,34 @@ func (r *readerGroup) remove(id string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	cancel, ok := r.table[id]
+	reader, ok := r.table[id]
 	if !ok {
 		return
 	}
 
-	cancel()
+	reader.cancel()
 	delete(r.table, id)
 }
 
+// get returns the reader associated with id, or nil if there is none.
+func (r *readerGroup) get(id string) *reader {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.table[id]
+}
+
+// snapshot returns a copy of the readers table.
+func (r *readerGroup) snapshot() map[string]*reader {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	readers := make(map[string]*reader, len(r.table))
+	for id, reader := range r.table {
+		readers[id] = reader
+	}
+	return readers
+}
+
 func (r *readerGroup) hasID(id string) bool {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -233,6 +261,9 @@ func startHarvester(
 		hg.store.UpdateTTL(resource, hg.cleanTimeout)
 		cursor := makeCursor(resource)
 		publisher := &cursorPublisher{canceler: ctx.Cancelation, client: client, cursor: &cursor}
+		if reader := hg.readers.get(srcID); reader != nil {
+			publisher.lastEvent = &reader.lastEvent
+		}
 
 		err = hg.harvester.Run(ctx, src, cursor, publisher, metrics)
 		if err != nil && !errors.Is(err, context.Canceled) {
@@ -81,6 +81,8 @@ func (inp *managedInput) Run(
 			"harvester:"),
 		metrics: metrics,
 	}
+	inp.manager.addHarvesterGroup(inp.userID, hg)
+	defer inp.manager.removeHarvesterGroup(hg)
 
 	prospectorStore := inp.manager.getRetainedStore()
 	defer prospectorStore.Release()
@@ -72,6 +72,10 @@ type InputManager struct {
 	ackCH      *updateChan
 	idsMux     sync.Mutex
 	ids        map[string]struct{}
+
+	// harvesters maps the harvester groups of running inputs to their input ID.
+	harvestersMux sync.Mutex
+	harvesters    map[*defaultHarvesterGroup]string
 }
 
 // Source describe a source the input can collect data from.
@@ -281,6 +285,14 @@ func (cim *InputManager) Delete(cfg *conf.C) error {
 	return nil
 }
 
+// HasID returns true if an input with the ID was created and not stopped yet.
+func (cim *InputManager) HasID(id string) bool {
+	cim.idsMux.Lock()
+	defer cim.idsMux.Unlock()
+	_, exists := cim.ids[id]
+	return exists
+}
+
 // StopInput performs all necessary clean up when an input finishes.
 func (cim *InputManager) StopInput(id string) {
 	cim.idsMux.Lock()
@@ -18,6 +18,7 @@
 package input_logfile
 
 import (
+	"sync/atomic"
 	"time"
 
 	input "github.com/elastic/beats/v7/filebeat/input/v2"
@@ -42,6 +43,8 @@ type cursorPublisher struct {
 	canceler input.Canceler
 	client   beat.Client
 	cursor   *Cursor
+	// lastEvent is set to the publishing time in Unix nanoseconds, if not nil.
+	lastEvent *atomic.Int64
 }
 
 // updateOp keeps track of pending updates that are not written to the persistent store yet.
@@ -90,6 +93,9 @@ func (c *cursorPublisher) Publish(event beat.Event, cursorUpdate interface{}) er
 
 func (c *cursorPublisher) forward(event beat.Event) error {
 	c.client.Publish(event)
+	if c.lastEvent != nil {
+		c.lastEvent.Store(time.Now().UnixNano())
+	}
 	if c.canceler == nil {
 		return nil
 	}
@@ -38,7 +38,7 @@ func TestPublish(t *testing.T) {
 		client := &pubtest.FakeClient{
 			PublishFunc: func(event beat.Event) { actual = event },
 		}
-		publisher := cursorPublisher{nil, client, &cursor}
+		publisher := cursorPublisher{nil, client, &cursor, nil}
 		err := publisher.Publish(beat.Event{}, "test")
 		require.NoError(t, err)
 		require.NotNil(t, actual.Private)
@@ -53,7 +53,7 @@ func TestPublish(t *testing.T) {
 		client := &pubtest.FakeClient{
 			PublishFunc: func(event beat.Event) { actual = event },
 		}
-		publisher := cursorPublisher{nil, client, &cursor}
+		publisher := cursorPublisher{nil, client, &cursor, nil}
 		err := publisher.Publish(beat.Event{}, nil)
 		require.NoError(t, err)
 		require.Nil(t, actual.Private)
@@ -67,7 +67,7 @@ func TestPublish(t *testing.T) {
 		defer store.Release()
 		cursor := makeCursor(store.Get("test::key"))
 
-		publisher := cursorPublisher{ctx, &pubtest.FakeClient{}, &cursor}
+		publisher := cursorPublisher{ctx, &pubtest.FakeClient{}, &cursor, nil}
 		err := publisher.Publish(beat.Event{}, nil)
 		require.Equal(t, context.Canceled, err)
 	})
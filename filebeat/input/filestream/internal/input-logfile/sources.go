@@ -0,0 +1,145 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package input_logfile
+
+import (
+	"strings"
+	"time"
+
+	v2 "github.com/elastic/beats/v7/filebeat/input/v2"
+	"github.com/elastic/beats/v7/libbeat/common/transform/typeconv"
+)
+
+var _ v2.SourceReporter = (*InputManager)(nil)
+
+// Harvesters returns the sources harvested by all running inputs.
+func (cim *InputManager) Harvesters() []v2.HarvesterState {
+	cim.harvestersMux.Lock()
+	groups := make(map[*defaultHarvesterGroup]string, len(cim.harvesters))
+	for hg, inputID := range cim.harvesters {
+		groups[hg] = inputID
+	}
+	cim.harvestersMux.Unlock()
+
+	harvesters := []v2.HarvesterState{}
+	for hg, inputID := range groups {
+		for key, reader := range hg.readers.snapshot() {
+			st := v2.HarvesterState{
+				InputID: inputID,
+				Key:     key,
+				Started: reader.started,
+			}
+			if ns := reader.lastEvent.Load(); ns != 0 {
+				lastEvent := time.Unix(0, ns)
+				st.LastEvent = &lastEvent
+			}
+
+			if res := hg.store.ephemeralStore.Find(key, false); res != nil {
+				res.stateMutex.Lock()
+				snapshot := res.stateSnapshot()
+				res.stateMutex.Unlock()
+				res.Release()
+
+				st.Cursor = toMap(snapshot.Cursor)
+				meta := toMap(snapshot.Meta)
+				st.Path, _ = meta["source"].(string)
+				st.Identifier, _ = meta["identifier_name"].(string)
+			}
+			harvesters = append(harvesters, st)
+		}
+	}
+	return harvesters
+}
+
+// RegistryEntries returns the entries of the in memory store that have been
+// written to the registry. The cursors only include updates of acknowledged
+// events.
+func (cim *InputManager) RegistryEntries() []v2.RegistryEntry {
+	if cim.init() != nil {
+		return nil
+	}
+
+	// The resources are collected first, the lock of the in memory store must
+	// not be held while locking a resource.
+	states := cim.store.ephemeralStore
+	states.mu.Lock()
+	resources := make([]*resource, 0, len(states.table))
+	for _, res := range states.table {
+		resources = append(resources, res)
+	}
+	states.mu.Unlock()
+
+	entries := []v2.RegistryEntry{}
+	for _, res := range resources {
+		res.stateMutex.Lock()
+		stored, deleted := res.stored, res.isDeleted()
+		snapshot := res.inSyncStateSnapshot()
+		res.stateMutex.Unlock()
+		if !stored || deleted {
+			continue
+		}
+
+		entry := v2.RegistryEntry{
+			InputID: cim.inputIDFromKey(res.key),
+			Key:     res.key,
+			TTL:     snapshot.TTL,
+			Updated: snapshot.Updated,
+			Cursor:  toMap(snapshot.Cursor),
+			Meta:    toMap(snapshot.Meta),
+		}
+		entry.Path, _ = entry.Meta["source"].(string)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (cim *InputManager) addHarvesterGroup(inputID string, hg *defaultHarvesterGroup) {
+	cim.harvestersMux.Lock()
+	defer cim.harvestersMux.Unlock()
+	if cim.harvesters == nil {
+		cim.harvesters = map[*defaultHarvesterGroup]string{}
+	}
+	cim.harvesters[hg] = inputID
+}
+
+func (cim *InputManager) removeHarvesterGroup(hg *defaultHarvesterGroup) {
+	cim.harvestersMux.Lock()
+	defer cim.harvestersMux.Unlock()
+	delete(cim.harvesters, hg)
+}
+
+// inputIDFromKey extracts the input ID from a key with the format
+// `<Type>::<input ID>::<source name>`.
+func (cim *InputManager) inputIDFromKey(key string) string {
+	inputID, _, _ := strings.Cut(strings.TrimPrefix(key, cim.Type+"::"), "::")
+	if inputID == globalInputID {
+		return ""
+	}
+	return inputID
+}
+
+func toMap(v interface{}) map[string]interface{} {
+	if v == nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if err := typeconv.Convert(&m, v); err != nil {
+		return nil
+	}
+	return m
+}
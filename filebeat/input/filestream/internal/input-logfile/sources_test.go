@@ -0,0 +1,98 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package input_logfile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent-libs/logp/logptest"
+)
+
+func TestInputManager_SourceReporter(t *testing.T) {
+	updated := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	backend := createSampleStore(t, map[string]state{
+		"filestream::my-id::native::1": {
+			TTL:     -1,
+			Updated: updated,
+			Cursor:  map[string]interface{}{"offset": 10},
+			Meta:    map[string]interface{}{"source": "/var/log/a.log", "identifier_name": "native"},
+		},
+		"filestream::.global::native::2": {
+			TTL:     time.Hour,
+			Updated: updated,
+			Cursor:  map[string]interface{}{"offset": 20},
+			Meta:    map[string]interface{}{"source": "/var/log/b.log", "identifier_name": "native"},
+		},
+	})
+	cim := &InputManager{
+		Logger:     logptest.NewTestingLogger(t, ""),
+		StateStore: backend,
+		Type:       "filestream",
+	}
+	require.NoError(t, cim.init())
+
+	t.Run("registry entries", func(t *testing.T) {
+		entries := cim.RegistryEntries()
+		require.Len(t, entries, 2)
+
+		byKey := map[string]int{}
+		for i, e := range entries {
+			byKey[e.Key] = i
+		}
+		a := entries[byKey["filestream::my-id::native::1"]]
+		assert.Equal(t, "my-id", a.InputID)
+		assert.Equal(t, "/var/log/a.log", a.Path)
+		assert.Equal(t, time.Duration(-1), a.TTL)
+		assert.True(t, updated.Equal(a.Updated))
+		assert.EqualValues(t, 10, a.Cursor["offset"])
+
+		b := entries[byKey["filestream::.global::native::2"]]
+		assert.Equal(t, "", b.InputID)
+		assert.Equal(t, "/var/log/b.log", b.Path)
+	})
+
+	t.Run("harvesters", func(t *testing.T) {
+		hg := &defaultHarvesterGroup{readers: newReaderGroup(), store: cim.store}
+		_, cancel, err := hg.readers.newContext("filestream::my-id::native::1", context.Background())
+		require.NoError(t, err)
+		defer cancel()
+
+		cim.addHarvesterGroup("my-id", hg)
+		harvesters := cim.Harvesters()
+		require.Len(t, harvesters, 1)
+		h := harvesters[0]
+		assert.Equal(t, "my-id", h.InputID)
+		assert.Equal(t, "/var/log/a.log", h.Path)
+		assert.Equal(t, "native", h.Identifier)
+		assert.Nil(t, h.LastEvent)
+		assert.EqualValues(t, 10, h.Cursor["offset"])
+
+		hg.readers.get("filestream::my-id::native::1").lastEvent.Store(updated.UnixNano())
+		harvesters = cim.Harvesters()
+		require.NotNil(t, harvesters[0].LastEvent)
+		assert.True(t, updated.Equal(*harvesters[0].LastEvent))
+
+		cim.removeHarvesterGroup(hg)
+		assert.Empty(t, cim.Harvesters())
+	})
+}
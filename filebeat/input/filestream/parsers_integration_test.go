@@ -21,9 +21,11 @@ package filestream
 
 import (
 	"context"
+	"os"
 	"testing"
 
 	"github.com/gofrs/uuid/v5"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParsersAgentLogs(t *testing.T) {
@@ -639,3 +641,48 @@ SetAdCodeMiddleware.default_ad_code route
 	cancelInput()
 	env.waitUntilInputStops()
 }
+
+func TestParsersCSVHeaderPersisted(t *testing.T) {
+	env := newInputTestingEnvironment(t)
+
+	testlogName := "test.csv"
+	id := uuid.Must(uuid.NewV4()).String()
+	inp := env.mustCreateInput(map[string]interface{}{
+		"id":                                     "fake-ID",
+		"paths":                                  []string{env.abspath(testlogName)},
+		"prospector.scanner.check_interval":      "1ms",
+		"file_identity.native":                   map[string]any{},
+		"prospector.scanner.fingerprint.enabled": false,
+		"parsers": []map[string]interface{}{
+			{
+				"csv": map[string]interface{}{},
+			},
+		},
+	})
+
+	lines := []byte("host,status\nweb-1,up\ndb-1,down\n")
+	env.mustWriteToFile(testlogName, lines)
+
+	ctx, cancelInput := context.WithCancel(context.Background())
+	env.startInput(ctx, id, inp)
+
+	env.waitUntilEventCount(2)
+	env.requireOffsetInRegistry(testlogName, "fake-ID", len(lines))
+
+	env.requireEventContents(0, "message", "web-1,up")
+	env.requireEventContents(0, "csv.host", "web-1")
+	env.requireEventContents(0, "csv.status", "up")
+	env.requireEventContents(1, "csv.host", "db-1")
+	env.requireEventContents(1, "csv.status", "down")
+
+	// The header is persisted with the offset, so rows can still be
+	// decoded when reading resumes after the first line.
+	fi, err := os.Stat(env.abspath(testlogName))
+	require.NoError(t, err)
+	entry, err := env.getRegistryState(getIDFromPath(env.abspath(testlogName), "fake-ID", fi))
+	require.NoError(t, err)
+	require.Equal(t, []string{"host", "status"}, entry.Cursor.CSVHeader)
+
+	cancelInput()
+	env.waitUntilInputStops()
+}
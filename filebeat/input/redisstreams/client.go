@@ -0,0 +1,225 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package redisstreams
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	rd "github.com/gomodule/redigo/redis"
+
+	"github.com/elastic/elastic-agent-libs/transport/tlscommon"
+)
+
+// entry is a stream entry read from Redis. Fields is nil for entries that
+// are still pending but have been deleted from the stream.
+type entry struct {
+	stream string
+	id     string
+	fields map[string]string
+}
+
+// streamClient is the subset of the Redis streams commands used by the
+// input.
+type streamClient interface {
+	// createGroup creates the consumer group on stream, creating the stream
+	// if needed. It is not an error if the group exists already.
+	createGroup(stream, group, startID string) error
+	// readGroup reads entries with XREADGROUP from streams, starting after
+	// the ID with the same index in ids.
+	readGroup(group, consumer string, streams, ids []string, count int, block time.Duration) ([]entry, error)
+	// autoClaim claims entries pending for longer than minIdle with
+	// XAUTOCLAIM. It returns the start ID of the next call, "0-0" once the
+	// whole pending entries list has been scanned.
+	autoClaim(stream, group, consumer string, minIdle time.Duration, start string, count int) (string, []entry, error)
+	ack(stream, group string, ids ...string) error
+	close() error
+}
+
+// redisClient implements streamClient using one connection for reading and
+// one for acknowledgements, so ACKs are not delayed by blocking reads.
+type redisClient struct {
+	readConn rd.Conn
+
+	mu      sync.Mutex
+	ackConn rd.Conn
+
+	closeOnce sync.Once
+}
+
+func dialRedis(cfg config) (streamClient, error) {
+	opts := []rd.DialOption{
+		rd.DialUsername(cfg.Username),
+		rd.DialPassword(cfg.Password),
+		rd.DialDatabase(cfg.DB),
+		rd.DialConnectTimeout(cfg.Timeout),
+		// Blocking reads must not time out before the server answers.
+		rd.DialReadTimeout(cfg.Timeout + cfg.BlockTimeout),
+		rd.DialWriteTimeout(cfg.Timeout),
+	}
+	if cfg.TLS.IsEnabled() {
+		tlsConfig, err := tlscommon.LoadTLSConfig(cfg.TLS)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, rd.DialUseTLS(true), rd.DialTLSConfig(tlsConfig.BuildModuleClientConfig(cfg.Host)))
+	}
+
+	read, err := rd.Dial(cfg.Network, cfg.Host, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ack, err := rd.Dial(cfg.Network, cfg.Host, opts...)
+	if err != nil {
+		read.Close()
+		return nil, err
+	}
+	return &redisClient{readConn: read, ackConn: ack}, nil
+}
+
+func (c *redisClient) createGroup(stream, group, startID string) error {
+	_, err := c.readConn.Do("XGROUP", "CREATE", stream, group, startID, "MKSTREAM")
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+func (c *redisClient) readGroup(group, consumer string, streams, ids []string, count int, block time.Duration) ([]entry, error) {
+	args := rd.Args{"GROUP", group, consumer, "COUNT", count}
+	if block > 0 {
+		args = append(args, "BLOCK", block.Milliseconds())
+	}
+	args = append(args, "STREAMS").AddFlat(streams).AddFlat(ids)
+	reply, err := c.readConn.Do("XREADGROUP", args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseReadReply(reply)
+}
+
+func (c *redisClient) autoClaim(stream, group, consumer string, minIdle time.Duration, start string, count int) (string, []entry, error) {
+	reply, err := c.readConn.Do("XAUTOCLAIM", stream, group, consumer, minIdle.Milliseconds(), start, "COUNT", count)
+	if err != nil {
+		return "", nil, err
+	}
+	return parseAutoClaimReply(stream, reply)
+}
+
+func (c *redisClient) ack(stream, group string, ids ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := c.ackConn.Do("XACK", rd.Args{stream, group}.AddFlat(ids)...)
+	return err
+}
+
+// close closes both connections. It can be called concurrently with the
+// other methods to interrupt a blocking read.
+func (c *redisClient) close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = errors.Join(c.readConn.Close(), c.ackConn.Close())
+	})
+	return err
+}
+
+// parseReadReply parses the reply of XREADGROUP, an array of
+// [stream, [[id, [field, value, ...]], ...]] pairs. A nil reply means the
+// read timed out.
+func parseReadReply(reply interface{}) ([]entry, error) {
+	if reply == nil {
+		return nil, nil
+	}
+	streams, err := rd.Values(reply, nil)
+	if err != nil {
+		return nil, err
+	}
+	var entries []entry
+	for _, s := range streams {
+		pair, err := rd.Values(s, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("unexpected XREADGROUP reply with %d elements", len(pair))
+		}
+		stream, err := rd.String(pair[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		streamEntries, err := parseEntries(stream, pair[1])
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, streamEntries...)
+	}
+	return entries, nil
+}
+
+// parseAutoClaimReply parses the reply of XAUTOCLAIM,
+// [next, [[id, [field, value, ...]], ...]]. Redis 7 appends the IDs of the
+// deleted entries, which are removed from the pending list by the command
+// itself, so they are ignored.
+func parseAutoClaimReply(stream string, reply interface{}) (string, []entry, error) {
+	values, err := rd.Values(reply, nil)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(values) < 2 {
+		return "", nil, fmt.Errorf("unexpected XAUTOCLAIM reply with %d elements", len(values))
+	}
+	next, err := rd.String(values[0], nil)
+	if err != nil {
+		return "", nil, err
+	}
+	entries, err := parseEntries(stream, values[1])
+	return next, entries, err
+}
+
+func parseEntries(stream string, reply interface{}) ([]entry, error) {
+	items, err := rd.Values(reply, nil)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]entry, 0, len(items))
+	for _, item := range items {
+		values, err := rd.Values(item, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) != 2 {
+			return nil, fmt.Errorf("unexpected stream entry with %d elements", len(values))
+		}
+		id, err := rd.String(values[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		e := entry{stream: stream, id: id}
+		if values[1] != nil {
+			e.fields, err = rd.StringMap(values[1], nil)
+			if err != nil {
+				return nil, fmt.Errorf("entry %s: %w", id, err)
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
@@ -0,0 +1,85 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package redisstreams
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReadReply(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{
+			[]byte("events"),
+			[]interface{}{
+				[]interface{}{[]byte("1700000000000-0"), []interface{}{[]byte("message"), []byte("hello"), []byte("level"), []byte("info")}},
+				[]interface{}{[]byte("1700000000001-0"), nil},
+			},
+		},
+		[]interface{}{
+			[]byte("audit"),
+			[]interface{}{
+				[]interface{}{[]byte("1700000000002-0"), []interface{}{[]byte("user"), []byte("alice")}},
+			},
+		},
+	}
+
+	entries, err := parseReadReply(reply)
+	require.NoError(t, err)
+	assert.Equal(t, []entry{
+		{stream: "events", id: "1700000000000-0", fields: map[string]string{"message": "hello", "level": "info"}},
+		{stream: "events", id: "1700000000001-0"},
+		{stream: "audit", id: "1700000000002-0", fields: map[string]string{"user": "alice"}},
+	}, entries)
+
+	entries, err = parseReadReply(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	_, err = parseReadReply([]interface{}{[]interface{}{[]byte("events")}})
+	assert.EqualError(t, err, "unexpected XREADGROUP reply with 1 elements")
+}
+
+func TestParseAutoClaimReply(t *testing.T) {
+	// Redis 7 replies include the deleted IDs as third element.
+	reply := []interface{}{
+		[]byte("1700000000005-0"),
+		[]interface{}{
+			[]interface{}{[]byte("1700000000003-0"), []interface{}{[]byte("message"), []byte("retry")}},
+		},
+		[]interface{}{[]byte("1700000000004-0")},
+	}
+
+	next, entries, err := parseAutoClaimReply("events", reply)
+	require.NoError(t, err)
+	assert.Equal(t, "1700000000005-0", next)
+	assert.Equal(t, []entry{
+		{stream: "events", id: "1700000000003-0", fields: map[string]string{"message": "retry"}},
+	}, entries)
+
+	_, _, err = parseAutoClaimReply("events", []interface{}{[]byte("0-0")})
+	assert.EqualError(t, err, "unexpected XAUTOCLAIM reply with 1 elements")
+
+	_, _, err = parseAutoClaimReply("events", []interface{}{
+		[]byte("0-0"),
+		[]interface{}{[]interface{}{[]byte("1-0"), []interface{}{[]byte("odd")}}},
+	})
+	assert.ErrorContains(t, err, "entry 1-0")
+}
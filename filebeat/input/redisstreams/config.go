@@ -0,0 +1,108 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package redisstreams
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/elastic/elastic-agent-libs/transport/tlscommon"
+)
+
+type config struct {
+	Host     string            `config:"host" validate:"required"`
+	Network  string            `config:"network"`
+	Username string            `config:"username"`
+	Password string            `config:"password"`
+	DB       int               `config:"db" validate:"min=0"`
+	TLS      *tlscommon.Config `config:"ssl"`
+	Timeout  time.Duration     `config:"timeout" validate:"positive,nonzero"`
+
+	Streams  []string `config:"streams" validate:"required"`
+	Group    string   `config:"group" validate:"required"`
+	Consumer string   `config:"consumer"`
+	// StartID is the ID the consumer group starts at when it is created
+	// by the input. "$" only reads new entries, "0" the whole stream.
+	StartID string `config:"start_id"`
+
+	BatchSize    int           `config:"batch_size" validate:"min=1"`
+	BlockTimeout time.Duration `config:"block_timeout" validate:"positive,nonzero"`
+	Claim        claimConfig   `config:"claim"`
+	MessageField string        `config:"message_field"`
+
+	ConnectBackoff time.Duration `config:"connect_backoff" validate:"positive,nonzero"`
+	WaitClose      time.Duration `config:"wait_close" validate:"min=0"`
+}
+
+// claimConfig configures how entries left pending by other consumers are
+// claimed with XAUTOCLAIM.
+type claimConfig struct {
+	// MinIdle is the time an entry must have been pending before it is
+	// claimed. Zero disables claiming.
+	MinIdle  time.Duration `config:"min_idle" validate:"min=0"`
+	Interval time.Duration `config:"interval" validate:"positive,nonzero"`
+}
+
+func defaultConfig() config {
+	consumer, err := os.Hostname()
+	if err != nil || consumer == "" {
+		consumer = "filebeat"
+	}
+	return config{
+		Host:         "localhost:6379",
+		Network:      "tcp",
+		Timeout:      10 * time.Second,
+		Consumer:     consumer,
+		StartID:      "$",
+		BatchSize:    100,
+		BlockTimeout: 5 * time.Second,
+		Claim: claimConfig{
+			MinIdle:  5 * time.Minute,
+			Interval: time.Minute,
+		},
+		MessageField:   "message",
+		ConnectBackoff: time.Second,
+		WaitClose:      2 * time.Second,
+	}
+}
+
+// Validate validates the config.
+func (c *config) Validate() error {
+	seen := make(map[string]bool, len(c.Streams))
+	for _, s := range c.Streams {
+		if s == "" {
+			return errors.New("stream keys must not be empty")
+		}
+		if seen[s] {
+			return fmt.Errorf("stream '%s' is configured more than once", s)
+		}
+		seen[s] = true
+	}
+	if c.Consumer == "" {
+		return errors.New("consumer must not be empty")
+	}
+	if c.StartID == "" {
+		return errors.New("start_id must not be empty")
+	}
+	if c.Password == "" && c.Username != "" {
+		return errors.New("password must be set when username is configured")
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package redisstreams
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		c := defaultConfig()
+		err := conf.MustNewConfigFrom(map[string]interface{}{
+			"streams": []string{"events"},
+			"group":   "filebeat",
+		}).Unpack(&c)
+		require.NoError(t, err)
+		assert.Equal(t, "localhost:6379", c.Host)
+		assert.Equal(t, "$", c.StartID)
+		assert.Equal(t, 100, c.BatchSize)
+		assert.Equal(t, 5*time.Minute, c.Claim.MinIdle)
+		assert.Equal(t, "message", c.MessageField)
+		assert.NotEmpty(t, c.Consumer)
+	})
+
+	tests := map[string]struct {
+		cfg     map[string]interface{}
+		wantErr string
+	}{
+		"missing streams": {
+			cfg:     map[string]interface{}{"group": "filebeat"},
+			wantErr: "missing required field accessing 'streams'",
+		},
+		"missing group": {
+			cfg:     map[string]interface{}{"streams": []string{"events"}},
+			wantErr: "string value is not set accessing 'group'",
+		},
+		"duplicate stream": {
+			cfg:     map[string]interface{}{"streams": []string{"events", "events"}, "group": "filebeat"},
+			wantErr: "stream 'events' is configured more than once",
+		},
+		"empty consumer": {
+			cfg:     map[string]interface{}{"streams": []string{"events"}, "group": "filebeat", "consumer": ""},
+			wantErr: "consumer must not be empty",
+		},
+		"username without password": {
+			cfg:     map[string]interface{}{"streams": []string{"events"}, "group": "filebeat", "username": "beats"},
+			wantErr: "password must be set when username is configured",
+		},
+		"invalid batch size": {
+			cfg:     map[string]interface{}{"streams": []string{"events"}, "group": "filebeat", "batch_size": 0},
+			wantErr: "requires value >= 1 accessing 'batch_size'",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := defaultConfig()
+			err := conf.MustNewConfigFrom(test.cfg).Unpack(&c)
+			assert.ErrorContains(t, err, test.wantErr)
+		})
+	}
+}
@@ -0,0 +1,332 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package redisstreams
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	input "github.com/elastic/beats/v7/filebeat/input/v2"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/common/acker"
+	"github.com/elastic/beats/v7/libbeat/common/backoff"
+	"github.com/elastic/beats/v7/libbeat/feature"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/go-concert/ctxtool"
+)
+
+const inputName = "redis_streams"
+
+// Plugin creates the redis_streams input plugin.
+func Plugin() input.Plugin {
+	return input.Plugin{
+		Name:       inputName,
+		Stability:  feature.Beta,
+		Deprecated: false,
+		Info:       "Redis Streams input",
+		Doc:        "The redis_streams input reads entries from Redis streams as a member of a consumer group",
+		Manager:    input.ConfigureWith(configure),
+	}
+}
+
+func configure(cfg *conf.C) (input.Input, error) {
+	config := defaultConfig()
+	if err := cfg.Unpack(&config); err != nil {
+		return nil, err
+	}
+	return &redisStreamsInput{config: config, dial: dialRedis}, nil
+}
+
+type redisStreamsInput struct {
+	config config
+	dial   func(config) (streamClient, error)
+
+	mu     sync.Mutex
+	client streamClient // current connection, used for acknowledgements
+	// inFlight holds the entries published but not yet acknowledged, so
+	// they are not published again when they are read from the pending
+	// entries list.
+	inFlight map[entryRef]struct{}
+}
+
+// entryRef identifies a published entry. It is stored in event.Private and
+// acknowledged with XACK once the event has been acknowledged by the
+// pipeline.
+type entryRef struct {
+	stream string
+	id     string
+}
+
+func (in *redisStreamsInput) Name() string { return inputName }
+
+func (in *redisStreamsInput) Test(_ input.TestContext) error {
+	client, err := in.dial(in.config)
+	if err != nil {
+		return err
+	}
+	return client.close()
+}
+
+func (in *redisStreamsInput) Run(ctx input.Context, pipeline beat.Pipeline) error {
+	log := ctx.Logger.With("host", in.config.Host, "group", in.config.Group)
+
+	in.inFlight = make(map[entryRef]struct{})
+	client, err := pipeline.ConnectWith(beat.ClientConfig{
+		EventListener: acker.ConnectionOnly(
+			acker.EventPrivateReporter(func(_ int, privates []interface{}) {
+				in.ack(log, privates)
+			}),
+		),
+		WaitClose: in.config.WaitClose,
+	})
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	log.Info("Starting Redis Streams input")
+	defer log.Info("Redis Streams input stopped")
+
+	cancelCtx := ctxtool.FromCanceller(ctx.Cancelation)
+	connectDelay := backoff.NewEqualJitterBackoff(
+		ctx.Cancelation.Done(),
+		in.config.ConnectBackoff,
+		60*in.config.ConnectBackoff,
+	)
+	for cancelCtx.Err() == nil {
+		sc, err := in.dial(in.config)
+		if err != nil {
+			log.Errorw("Error connecting to Redis", "error", err)
+			connectDelay.Wait()
+			continue
+		}
+		connectDelay.Reset()
+
+		err = in.consume(cancelCtx, log, sc, client)
+		if cancelCtx.Err() == nil {
+			log.Errorw("Error reading from Redis streams", "error", err)
+			connectDelay.Wait()
+		}
+	}
+	return nil
+}
+
+// consume reads from the streams until ctx is done or a command fails.
+func (in *redisStreamsInput) consume(ctx context.Context, log *logp.Logger, sc streamClient, client beat.Client) error {
+	in.setClient(sc)
+	defer func() {
+		in.setClient(nil)
+		sc.close()
+	}()
+	// Closing the client interrupts a blocking read.
+	stop := context.AfterFunc(ctx, func() { sc.close() })
+	defer stop()
+
+	cfg := in.config
+	for _, stream := range cfg.Streams {
+		if err := sc.createGroup(stream, cfg.Group, cfg.StartID); err != nil {
+			return fmt.Errorf("creating consumer group on stream '%s': %w", stream, err)
+		}
+	}
+
+	// Entries delivered to this consumer before a restart or a connection
+	// loss are still pending, read them first.
+	if err := in.readPending(sc, client); err != nil {
+		return err
+	}
+
+	newIDs := make([]string, len(cfg.Streams))
+	for i := range newIDs {
+		newIDs[i] = ">"
+	}
+	var nextClaim time.Time
+	for ctx.Err() == nil {
+		if cfg.Claim.MinIdle > 0 && !time.Now().Before(nextClaim) {
+			if err := in.claim(log, sc, client); err != nil {
+				return err
+			}
+			nextClaim = time.Now().Add(cfg.Claim.Interval)
+		}
+
+		entries, err := sc.readGroup(cfg.Group, cfg.Consumer, cfg.Streams, newIDs, cfg.BatchSize, cfg.BlockTimeout)
+		if err != nil {
+			return err
+		}
+		in.publish(sc, client, entries)
+	}
+	return nil
+}
+
+// readPending publishes the entries pending for this consumer.
+func (in *redisStreamsInput) readPending(sc streamClient, client beat.Client) error {
+	cfg := in.config
+	for _, stream := range cfg.Streams {
+		after := "0"
+		for {
+			entries, err := sc.readGroup(cfg.Group, cfg.Consumer, []string{stream}, []string{after}, cfg.BatchSize, 0)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				break
+			}
+			in.publish(sc, client, entries)
+			after = entries[len(entries)-1].id
+		}
+	}
+	return nil
+}
+
+// claim takes over the entries that have been pending for longer than the
+// configured minimum idle time, usually because their consumer crashed.
+func (in *redisStreamsInput) claim(log *logp.Logger, sc streamClient, client beat.Client) error {
+	cfg := in.config
+	for _, stream := range cfg.Streams {
+		start := "0-0"
+		for {
+			next, entries, err := sc.autoClaim(stream, cfg.Group, cfg.Consumer, cfg.Claim.MinIdle, start, cfg.BatchSize)
+			if err != nil {
+				return fmt.Errorf("claiming pending entries of stream '%s': %w", stream, err)
+			}
+			if len(entries) > 0 {
+				log.Debugf("Claimed %d pending entries from stream '%s'", len(entries), stream)
+			}
+			in.publish(sc, client, entries)
+			if next == "0-0" || next == start {
+				break
+			}
+			start = next
+		}
+	}
+	return nil
+}
+
+func (in *redisStreamsInput) publish(sc streamClient, client beat.Client, entries []entry) {
+	var deleted []entry
+	for _, e := range entries {
+		if e.fields == nil {
+			deleted = append(deleted, e)
+			continue
+		}
+		ref := entryRef{stream: e.stream, id: e.id}
+		in.mu.Lock()
+		_, published := in.inFlight[ref]
+		in.inFlight[ref] = struct{}{}
+		in.mu.Unlock()
+		if published {
+			continue
+		}
+		client.Publish(in.createEvent(e, ref))
+	}
+	// Entries deleted from the stream can't be published, remove them from
+	// the pending entries list.
+	for _, e := range deleted {
+		_ = sc.ack(e.stream, in.config.Group, e.id)
+	}
+}
+
+func (in *redisStreamsInput) createEvent(e entry, ref entryRef) beat.Event {
+	fields := make(mapstr.M, len(e.fields))
+	var message string
+	var hasMessage bool
+	for k, v := range e.fields {
+		if k == in.config.MessageField {
+			message, hasMessage = v, true
+			continue
+		}
+		fields[k] = v
+	}
+
+	stream := mapstr.M{
+		"key":      e.stream,
+		"id":       e.id,
+		"group":    in.config.Group,
+		"consumer": in.config.Consumer,
+	}
+	if len(fields) > 0 {
+		stream["fields"] = fields
+	}
+	event := beat.Event{
+		Timestamp: entryTime(e.id),
+		Fields: mapstr.M{
+			"redis": mapstr.M{"stream": stream},
+		},
+		Private: ref,
+	}
+	if hasMessage {
+		event.Fields["message"] = message
+	}
+	return event
+}
+
+// ack acknowledges the entries of the events acknowledged by the pipeline.
+// If XACK fails, the entries stay pending and are read again after the
+// next reconnection or claimed by another consumer.
+func (in *redisStreamsInput) ack(log *logp.Logger, privates []interface{}) {
+	ids := make(map[string][]string)
+	var refs []entryRef
+	for _, p := range privates {
+		if ref, ok := p.(entryRef); ok {
+			ids[ref.stream] = append(ids[ref.stream], ref.id)
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) == 0 {
+		return
+	}
+
+	in.mu.Lock()
+	for _, ref := range refs {
+		delete(in.inFlight, ref)
+	}
+	sc := in.client
+	in.mu.Unlock()
+
+	if sc == nil {
+		log.Warnf("Not connected, %d entries stay pending", len(refs))
+		return
+	}
+	for stream, streamIDs := range ids {
+		if err := sc.ack(stream, in.config.Group, streamIDs...); err != nil {
+			log.Warnw("Error acknowledging entries", "stream", stream, "error", err)
+		}
+	}
+}
+
+func (in *redisStreamsInput) setClient(sc streamClient) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.client = sc
+}
+
+// entryTime returns the creation time encoded in the first part of an entry
+// ID, in milliseconds since the epoch.
+func entryTime(id string) time.Time {
+	ms, _, _ := strings.Cut(id, "-")
+	n, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil {
+		return time.Now()
+	}
+	return time.UnixMilli(n)
+}
@@ -0,0 +1,518 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package redisstreams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	input "github.com/elastic/beats/v7/filebeat/input/v2"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	pubtest "github.com/elastic/beats/v7/libbeat/publisher/testing"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+func TestInputPublishAndAck(t *testing.T) {
+	server := newFakeServer()
+	server.add("events", "1700000000000-0", "message", "hello", "level", "info")
+	server.add("audit", "1700000000001-0", "user", "alice")
+
+	// Entries stay pending until the pipeline acknowledges the events.
+	var pendingBeforeACK int
+	env := runInput(t, server, testConfig("events", "audit"), func(p *fakePipeline) bool {
+		if len(p.published()) < 2 {
+			return false
+		}
+		pendingBeforeACK = server.pendingCount()
+		p.ackAll()
+		return true
+	})
+
+	events := env.pipeline.published()
+	require.Len(t, events, 2)
+	sortEvents(events)
+	assert.Equal(t, time.UnixMilli(1700000000000), events[0].Timestamp)
+	assert.Equal(t, mapstr.M{
+		"message": "hello",
+		"redis": mapstr.M{
+			"stream": mapstr.M{
+				"key":      "events",
+				"id":       "1700000000000-0",
+				"group":    "filebeat",
+				"consumer": "consumer-1",
+				"fields":   mapstr.M{"level": "info"},
+			},
+		},
+	}, events[0].Fields)
+	assert.Equal(t, mapstr.M{"user": "alice"}, mustGet(t, events[1].Fields, "redis.stream.fields"))
+	_, err := events[1].Fields.GetValue("message")
+	assert.ErrorIs(t, err, mapstr.ErrKeyNotFound)
+
+	assert.Equal(t, 2, pendingBeforeACK)
+	assert.Equal(t, 0, server.pendingCount())
+	assert.ElementsMatch(t, []string{"events/1700000000000-0", "audit/1700000000001-0"}, server.ackedIDs())
+}
+
+func TestInputReadsOwnPendingFirst(t *testing.T) {
+	server := newFakeServer()
+	server.add("events", "1-0", "message", "delivered before restart")
+	server.add("events", "2-0", "message", "deleted before restart")
+	server.createGroup("events", "filebeat", "0")
+	server.deliver("events", "filebeat", "consumer-1", time.Now())
+	server.delete("events", "2-0")
+	server.add("events", "3-0", "message", "new")
+
+	env := runInput(t, server, testConfig("events"), func(p *fakePipeline) bool {
+		return len(p.published()) == 2
+	})
+
+	events := env.pipeline.published()
+	require.Len(t, events, 2)
+	assert.Equal(t, "delivered before restart", mustGet(t, events[0].Fields, "message"))
+	assert.Equal(t, "new", mustGet(t, events[1].Fields, "message"))
+	// The deleted entry is removed from the pending entries list.
+	assert.Equal(t, []string{"events/2-0"}, server.ackedIDs())
+}
+
+func TestInputClaimsIdleEntries(t *testing.T) {
+	server := newFakeServer()
+	server.add("events", "1-0", "message", "crashed consumer")
+	server.add("events", "2-0", "message", "busy consumer")
+	server.createGroup("events", "filebeat", "0")
+	server.deliverOne("events", "filebeat", "consumer-2", "1-0", time.Now().Add(-time.Hour))
+	server.deliverOne("events", "filebeat", "consumer-3", "2-0", time.Now())
+
+	env := runInput(t, server, testConfig("events"), func(p *fakePipeline) bool {
+		return len(p.published()) == 1
+	})
+
+	events := env.pipeline.published()
+	require.Len(t, events, 1)
+	assert.Equal(t, "crashed consumer", mustGet(t, events[0].Fields, "message"))
+	assert.Equal(t, "consumer-1", server.owner("events", "1-0"))
+	assert.Equal(t, "consumer-3", server.owner("events", "2-0"))
+}
+
+func TestInputReconnects(t *testing.T) {
+	server := newFakeServer()
+	server.add("events", "1-0", "message", "first")
+	server.failNextRead(errors.New("connection reset"))
+
+	cfg := testConfig("events")
+	cfg.ConnectBackoff = time.Millisecond
+	env := runInput(t, server, cfg, func(p *fakePipeline) bool {
+		return len(p.published()) == 1
+	})
+
+	require.Len(t, env.pipeline.published(), 1)
+	assert.Equal(t, 2, server.dials())
+}
+
+func TestPublishSkipsInFlightEntries(t *testing.T) {
+	in := &redisStreamsInput{config: testConfig("events"), inFlight: map[entryRef]struct{}{}}
+	var published []beat.Event
+	client := &pubtest.FakeClient{PublishFunc: func(e beat.Event) { published = append(published, e) }}
+	entries := []entry{{stream: "events", id: "1-0", fields: map[string]string{"message": "a"}}}
+
+	in.publish(nil, client, entries)
+	in.publish(nil, client, entries)
+	assert.Len(t, published, 1)
+
+	in.ack(logp.NewLogger(inputName), []interface{}{entryRef{stream: "events", id: "1-0"}})
+	in.publish(nil, client, entries)
+	assert.Len(t, published, 2)
+}
+
+func TestEntryTime(t *testing.T) {
+	assert.Equal(t, time.UnixMilli(1526919030474), entryTime("1526919030474-55"))
+	assert.WithinDuration(t, time.Now(), entryTime("invalid"), time.Minute)
+}
+
+func testConfig(streams ...string) config {
+	cfg := defaultConfig()
+	cfg.Streams = streams
+	cfg.Group = "filebeat"
+	cfg.Consumer = "consumer-1"
+	cfg.StartID = "0"
+	cfg.BlockTimeout = 10 * time.Millisecond
+	cfg.Claim.Interval = 10 * time.Millisecond
+	return cfg
+}
+
+type testEnv struct {
+	pipeline *fakePipeline
+}
+
+// runInput runs the input against server until done returns true.
+func runInput(t *testing.T, server *fakeServer, cfg config, done func(*fakePipeline) bool) testEnv {
+	t.Helper()
+	return runInputWith(t, &redisStreamsInput{config: cfg, dial: server.dial}, done)
+}
+
+func runInputWith(t *testing.T, in *redisStreamsInput, done func(*fakePipeline) bool) testEnv {
+	t.Helper()
+	logp.TestingSetup()
+
+	pipeline := &fakePipeline{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- in.Run(input.Context{
+			ID:          "test",
+			Logger:      logp.NewLogger(inputName),
+			Cancelation: ctx,
+		}, pipeline)
+	}()
+
+	require.Eventually(t, func() bool { return done(pipeline) }, 10*time.Second, time.Millisecond)
+	cancel()
+	select {
+	case err := <-errCh:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("input did not stop")
+	}
+	return testEnv{pipeline: pipeline}
+}
+
+func mustGet(t *testing.T, m mapstr.M, key string) interface{} {
+	t.Helper()
+	v, err := m.GetValue(key)
+	require.NoError(t, err, key)
+	return v
+}
+
+func sortEvents(events []beat.Event) {
+	sort.Slice(events, func(i, j int) bool { return events[i].Timestamp.Before(events[j].Timestamp) })
+}
+
+// fakePipeline records the published events and acknowledges them on
+// request.
+type fakePipeline struct {
+	mu       sync.Mutex
+	listener beat.EventListener
+	events   []beat.Event
+	pending  int
+}
+
+func (p *fakePipeline) ConnectWith(cfg beat.ClientConfig) (beat.Client, error) {
+	p.mu.Lock()
+	p.listener = cfg.EventListener
+	p.mu.Unlock()
+	return &pubtest.FakeClient{
+		PublishFunc: func(e beat.Event) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.listener.AddEvent(e, true)
+			p.events = append(p.events, e)
+			p.pending++
+		},
+	}, nil
+}
+
+func (p *fakePipeline) Connect() (beat.Client, error) {
+	return p.ConnectWith(beat.ClientConfig{})
+}
+
+func (p *fakePipeline) published() []beat.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]beat.Event(nil), p.events...)
+}
+
+func (p *fakePipeline) ackAll() {
+	p.mu.Lock()
+	n := p.pending
+	p.pending = 0
+	listener := p.listener
+	p.mu.Unlock()
+	listener.ACKEvents(n)
+}
+
+// fakeServer is an in-memory implementation of the stream commands used by
+// the input. Entry IDs are ordered by insertion.
+type fakeServer struct {
+	mu       sync.Mutex
+	streams  map[string]*fakeStream
+	acked    []string
+	dialed   int
+	readErrs []error
+}
+
+type fakeStream struct {
+	entries []entry
+	deleted map[string]bool
+	groups  map[string]*fakeGroup
+}
+
+type fakeGroup struct {
+	lastDelivered int
+	pending       map[string]*pendingEntry
+}
+
+type pendingEntry struct {
+	index       int
+	consumer    string
+	deliveredAt time.Time
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{streams: map[string]*fakeStream{}}
+}
+
+func (s *fakeServer) stream(key string) *fakeStream {
+	st, ok := s.streams[key]
+	if !ok {
+		st = &fakeStream{deleted: map[string]bool{}, groups: map[string]*fakeGroup{}}
+		s.streams[key] = st
+	}
+	return st
+}
+
+func (s *fakeServer) add(key, id string, kv ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fields := map[string]string{}
+	for i := 0; i < len(kv); i += 2 {
+		fields[kv[i]] = kv[i+1]
+	}
+	st := s.stream(key)
+	st.entries = append(st.entries, entry{stream: key, id: id, fields: fields})
+}
+
+func (s *fakeServer) delete(key, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stream(key).deleted[id] = true
+}
+
+// deliver delivers all new entries of the stream to consumer.
+func (s *fakeServer) deliver(key, group, consumer string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.stream(key)
+	g := st.groups[group]
+	for ; g.lastDelivered < len(st.entries); g.lastDelivered++ {
+		g.pending[st.entries[g.lastDelivered].id] = &pendingEntry{index: g.lastDelivered, consumer: consumer, deliveredAt: at}
+	}
+}
+
+// deliverOne delivers the next entry, which must have the given ID.
+func (s *fakeServer) deliverOne(key, group, consumer, id string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.stream(key)
+	g := st.groups[group]
+	if st.entries[g.lastDelivered].id != id {
+		panic("unexpected entry " + id)
+	}
+	g.pending[id] = &pendingEntry{index: g.lastDelivered, consumer: consumer, deliveredAt: at}
+	g.lastDelivered++
+}
+
+func (s *fakeServer) owner(key, id string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, g := range s.stream(key).groups {
+		if p, ok := g.pending[id]; ok {
+			return p.consumer
+		}
+	}
+	return ""
+}
+
+func (s *fakeServer) pendingCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, st := range s.streams {
+		for _, g := range st.groups {
+			n += len(g.pending)
+		}
+	}
+	return n
+}
+
+func (s *fakeServer) ackedIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.acked...)
+}
+
+func (s *fakeServer) failNextRead(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.readErrs = append(s.readErrs, err)
+}
+
+func (s *fakeServer) dials() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dialed
+}
+
+func (s *fakeServer) dial(config) (streamClient, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dialed++
+	return &fakeClient{server: s, closed: make(chan struct{})}, nil
+}
+
+func (s *fakeServer) createGroup(key, group, startID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.stream(key)
+	if _, ok := st.groups[group]; ok {
+		return
+	}
+	g := &fakeGroup{pending: map[string]*pendingEntry{}}
+	if startID == "$" {
+		g.lastDelivered = len(st.entries)
+	}
+	st.groups[group] = g
+}
+
+func (st *fakeStream) entry(index int) entry {
+	e := st.entries[index]
+	if st.deleted[e.id] {
+		e.fields = nil
+	}
+	return e
+}
+
+type fakeClient struct {
+	server    *fakeServer
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (c *fakeClient) createGroup(stream, group, startID string) error {
+	c.server.createGroup(stream, group, startID)
+	return nil
+}
+
+func (c *fakeClient) readGroup(group, consumer string, streams, ids []string, count int, block time.Duration) ([]entry, error) {
+	s := c.server
+	s.mu.Lock()
+	if len(s.readErrs) > 0 {
+		err := s.readErrs[0]
+		s.readErrs = s.readErrs[1:]
+		s.mu.Unlock()
+		return nil, err
+	}
+
+	var entries []entry
+	for i, key := range streams {
+		st := s.stream(key)
+		g, ok := st.groups[group]
+		if !ok {
+			s.mu.Unlock()
+			return nil, fmt.Errorf("NOGROUP %s", key)
+		}
+		if ids[i] == ">" {
+			for ; g.lastDelivered < len(st.entries) && len(entries) < count; g.lastDelivered++ {
+				e := st.entry(g.lastDelivered)
+				g.pending[e.id] = &pendingEntry{index: g.lastDelivered, consumer: consumer, deliveredAt: time.Now()}
+				entries = append(entries, e)
+			}
+			continue
+		}
+		after := -1
+		for j, e := range st.entries {
+			if e.id == ids[i] {
+				after = j
+			}
+		}
+		var indexes []int
+		for _, p := range g.pending {
+			if p.consumer == consumer && p.index > after {
+				indexes = append(indexes, p.index)
+			}
+		}
+		sort.Ints(indexes)
+		for _, idx := range indexes {
+			if len(entries) < count {
+				entries = append(entries, st.entry(idx))
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if len(entries) == 0 && block > 0 {
+		select {
+		case <-c.closed:
+			return nil, errors.New("use of closed connection")
+		case <-time.After(block):
+		}
+	}
+	return entries, nil
+}
+
+func (c *fakeClient) autoClaim(stream, group, consumer string, minIdle time.Duration, start string, count int) (string, []entry, error) {
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	st := s.stream(stream)
+	g := st.groups[group]
+	var indexes []int
+	for _, p := range g.pending {
+		if time.Since(p.deliveredAt) >= minIdle {
+			indexes = append(indexes, p.index)
+		}
+	}
+	sort.Ints(indexes)
+	var entries []entry
+	for _, idx := range indexes {
+		e := st.entry(idx)
+		if e.fields == nil {
+			delete(g.pending, e.id)
+			continue
+		}
+		g.pending[e.id].consumer = consumer
+		g.pending[e.id].deliveredAt = time.Now()
+		entries = append(entries, e)
+	}
+	return "0-0", entries, nil
+}
+
+func (c *fakeClient) ack(stream, group string, ids ...string) error {
+	s := c.server
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.stream(stream).groups[group].pending, id)
+		s.acked = append(s.acked, stream+"/"+id)
+	}
+	return nil
+}
+
+func (c *fakeClient) close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return nil
+}
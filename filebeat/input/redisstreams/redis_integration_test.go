@@ -0,0 +1,82 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build integration
+
+package redisstreams
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	rd "github.com/gomodule/redigo/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var hostPort = fmt.Sprintf("%s:%s",
+	getOrDefault(os.Getenv("REDIS_STREAMS_HOST"), "localhost"),
+	getOrDefault(os.Getenv("REDIS_STREAMS_PORT"), "6381"))
+
+func TestInputRedis(t *testing.T) {
+	conn, err := rd.Dial("tcp", hostPort)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	stream := fmt.Sprintf("filebeat-test-%d", time.Now().UnixNano())
+	t.Cleanup(func() { conn.Do("DEL", stream) })
+
+	// An entry delivered to a crashed consumer is claimed by the input.
+	_, err = conn.Do("XADD", stream, "*", "message", "claimed")
+	require.NoError(t, err)
+	_, err = conn.Do("XGROUP", "CREATE", stream, "filebeat", "0")
+	require.NoError(t, err)
+	_, err = conn.Do("XREADGROUP", "GROUP", "filebeat", "crashed", "COUNT", 1, "STREAMS", stream, ">")
+	require.NoError(t, err)
+	_, err = conn.Do("XADD", stream, "*", "message", "new", "level", "info")
+	require.NoError(t, err)
+
+	cfg := testConfig(stream)
+	cfg.Host = hostPort
+	cfg.Claim.MinIdle = time.Millisecond
+	env := runInputWith(t, &redisStreamsInput{config: cfg, dial: dialRedis}, func(p *fakePipeline) bool {
+		if len(p.published()) < 2 {
+			return false
+		}
+		p.ackAll()
+		return true
+	})
+
+	var messages []interface{}
+	for _, e := range env.pipeline.published() {
+		messages = append(messages, mustGet(t, e.Fields, "message"))
+	}
+	assert.ElementsMatch(t, []interface{}{"claimed", "new"}, messages)
+
+	pending, err := rd.Values(conn.Do("XPENDING", stream, "filebeat"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), pending[0])
+}
+
+func getOrDefault(s, defaultString string) string {
+	if s == "" {
+		return defaultString
+	}
+	return s
+}
@@ -0,0 +1,180 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snmptrap
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+type config struct {
+	// Host is the UDP address the trap receiver listens on.
+	Host string `config:"host" validate:"required"`
+
+	// Communities restricts the accepted v1/v2c community strings. An empty
+	// list accepts every community.
+	Communities []string `config:"communities"`
+
+	// EngineID is the hex encoded authoritative engine ID of the receiver.
+	// It is required to answer SNMPv3 informs.
+	EngineID string `config:"engine_id"`
+
+	// Users holds the SNMPv3 USM users allowed to send traps and informs.
+	Users []userConfig `config:"users"`
+
+	// ResolveOIDs enables translation of OIDs to their MIB names.
+	ResolveOIDs bool `config:"resolve_oids"`
+
+	// MIBPaths lists MIB files or directories loaded when resolving OIDs.
+	MIBPaths []string `config:"mib_paths"`
+}
+
+type userConfig struct {
+	Name           string       `config:"name" validate:"required"`
+	AuthProtocol   authProtocol `config:"auth_protocol"`
+	AuthPassphrase string       `config:"auth_passphrase"`
+	PrivProtocol   privProtocol `config:"priv_protocol"`
+	PrivPassphrase string       `config:"priv_passphrase"`
+}
+
+type authProtocol gosnmp.SnmpV3AuthProtocol
+
+type privProtocol gosnmp.SnmpV3PrivProtocol
+
+var (
+	authProtocols = map[string]authProtocol{
+		"":       authProtocol(gosnmp.NoAuth),
+		"none":   authProtocol(gosnmp.NoAuth),
+		"md5":    authProtocol(gosnmp.MD5),
+		"sha":    authProtocol(gosnmp.SHA),
+		"sha224": authProtocol(gosnmp.SHA224),
+		"sha256": authProtocol(gosnmp.SHA256),
+		"sha384": authProtocol(gosnmp.SHA384),
+		"sha512": authProtocol(gosnmp.SHA512),
+	}
+	privProtocols = map[string]privProtocol{
+		"":        privProtocol(gosnmp.NoPriv),
+		"none":    privProtocol(gosnmp.NoPriv),
+		"des":     privProtocol(gosnmp.DES),
+		"aes":     privProtocol(gosnmp.AES),
+		"aes192":  privProtocol(gosnmp.AES192),
+		"aes256":  privProtocol(gosnmp.AES256),
+		"aes192c": privProtocol(gosnmp.AES192C),
+		"aes256c": privProtocol(gosnmp.AES256C),
+	}
+)
+
+func defaultConfig() config {
+	return config{
+		Host: "localhost:162",
+	}
+}
+
+func (c *config) Validate() error {
+	if c.EngineID != "" {
+		id, err := hex.DecodeString(strings.TrimPrefix(c.EngineID, "0x"))
+		if err != nil {
+			return fmt.Errorf("invalid engine_id: %w", err)
+		}
+		// RFC 3411 section 5: SnmpEngineID is between 5 and 32 octets long.
+		if len(id) < 5 || len(id) > 32 {
+			return fmt.Errorf("invalid engine_id: length must be between 5 and 32 bytes, got %d", len(id))
+		}
+	}
+	if len(c.MIBPaths) != 0 && !c.ResolveOIDs {
+		return errors.New("mib_paths requires resolve_oids to be enabled")
+	}
+	seen := make(map[string]bool, len(c.Users))
+	for _, u := range c.Users {
+		if seen[u.Name] {
+			return fmt.Errorf("duplicate SNMPv3 user %q", u.Name)
+		}
+		seen[u.Name] = true
+	}
+	return nil
+}
+
+func (u *userConfig) Validate() error {
+	if u.hasAuth() && u.AuthPassphrase == "" {
+		return fmt.Errorf("user %q: auth_passphrase is required when auth_protocol is set", u.Name)
+	}
+	if u.hasPriv() {
+		if !u.hasAuth() {
+			return fmt.Errorf("user %q: priv_protocol requires an auth_protocol", u.Name)
+		}
+		if u.PrivPassphrase == "" {
+			return fmt.Errorf("user %q: priv_passphrase is required when priv_protocol is set", u.Name)
+		}
+	}
+	return nil
+}
+
+func (u *userConfig) hasAuth() bool { return u.AuthProtocol > authProtocol(gosnmp.NoAuth) }
+
+func (u *userConfig) hasPriv() bool { return u.PrivProtocol > privProtocol(gosnmp.NoPriv) }
+
+// securityParameters returns the USM parameters used to authenticate and
+// decrypt traps sent by the user.
+func (u *userConfig) securityParameters(log gosnmp.Logger) *gosnmp.UsmSecurityParameters {
+	sp := &gosnmp.UsmSecurityParameters{
+		UserName:               u.Name,
+		AuthenticationProtocol: gosnmp.NoAuth,
+		PrivacyProtocol:        gosnmp.NoPriv,
+		Logger:                 log,
+	}
+	if u.hasAuth() {
+		sp.AuthenticationProtocol = gosnmp.SnmpV3AuthProtocol(u.AuthProtocol)
+		sp.AuthenticationPassphrase = u.AuthPassphrase
+	}
+	if u.hasPriv() {
+		sp.PrivacyProtocol = gosnmp.SnmpV3PrivProtocol(u.PrivProtocol)
+		sp.PrivacyPassphrase = u.PrivPassphrase
+	}
+	return sp
+}
+
+// engineID returns the decoded authoritative engine ID.
+func (c *config) engineID() string {
+	if c.EngineID == "" {
+		return ""
+	}
+	id, _ := hex.DecodeString(strings.TrimPrefix(c.EngineID, "0x"))
+	return string(id)
+}
+
+func (p *authProtocol) Unpack(value string) error {
+	proto, ok := authProtocols[strings.ToLower(value)]
+	if !ok {
+		return fmt.Errorf("invalid auth_protocol '%s'", value)
+	}
+	*p = proto
+	return nil
+}
+
+func (p *privProtocol) Unpack(value string) error {
+	proto, ok := privProtocols[strings.ToLower(value)]
+	if !ok {
+		return fmt.Errorf("invalid priv_protocol '%s'", value)
+	}
+	*p = proto
+	return nil
+}
@@ -0,0 +1,119 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snmptrap
+
+import (
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+)
+
+func TestConfig(t *testing.T) {
+	tests := map[string]struct {
+		config  map[string]interface{}
+		wantErr string
+	}{
+		"defaults": {
+			config: map[string]interface{}{},
+		},
+		"v3 users": {
+			config: map[string]interface{}{
+				"engine_id": "0x8000000001020304",
+				"users": []map[string]interface{}{
+					{"name": "noauth"},
+					{"name": "auth", "auth_protocol": "SHA256", "auth_passphrase": "secret123"},
+					{"name": "priv", "auth_protocol": "sha", "auth_passphrase": "secret123", "priv_protocol": "aes", "priv_passphrase": "secret456"},
+				},
+			},
+		},
+		"invalid auth protocol": {
+			config: map[string]interface{}{
+				"users": []map[string]interface{}{{"name": "u", "auth_protocol": "sha3"}},
+			},
+			wantErr: "invalid auth_protocol 'sha3'",
+		},
+		"missing auth passphrase": {
+			config: map[string]interface{}{
+				"users": []map[string]interface{}{{"name": "u", "auth_protocol": "md5"}},
+			},
+			wantErr: "auth_passphrase is required",
+		},
+		"priv without auth": {
+			config: map[string]interface{}{
+				"users": []map[string]interface{}{{"name": "u", "priv_protocol": "des", "priv_passphrase": "secret123"}},
+			},
+			wantErr: "priv_protocol requires an auth_protocol",
+		},
+		"duplicate user": {
+			config: map[string]interface{}{
+				"users": []map[string]interface{}{{"name": "u"}, {"name": "u"}},
+			},
+			wantErr: `duplicate SNMPv3 user "u"`,
+		},
+		"short engine id": {
+			config: map[string]interface{}{
+				"engine_id": "0102",
+			},
+			wantErr: "length must be between 5 and 32 bytes",
+		},
+		"mib paths without resolution": {
+			config: map[string]interface{}{
+				"mib_paths": []string{"/usr/share/snmp/mibs"},
+			},
+			wantErr: "mib_paths requires resolve_oids",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := defaultConfig()
+			err := conf.MustNewConfigFrom(tc.config).Unpack(&c)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUserSecurityParameters(t *testing.T) {
+	c := defaultConfig()
+	err := conf.MustNewConfigFrom(map[string]interface{}{
+		"users": []map[string]interface{}{
+			{"name": "noauth"},
+			{"name": "priv", "auth_protocol": "sha512", "auth_passphrase": "secret123", "priv_protocol": "aes256c", "priv_passphrase": "secret456"},
+		},
+	}).Unpack(&c)
+	require.NoError(t, err)
+
+	sp := c.Users[0].securityParameters(gosnmp.Logger{})
+	assert.Equal(t, gosnmp.NoAuth, sp.AuthenticationProtocol)
+	assert.Equal(t, gosnmp.NoPriv, sp.PrivacyProtocol)
+
+	sp = c.Users[1].securityParameters(gosnmp.Logger{})
+	assert.Equal(t, "priv", sp.UserName)
+	assert.Equal(t, gosnmp.SHA512, sp.AuthenticationProtocol)
+	assert.Equal(t, "secret123", sp.AuthenticationPassphrase)
+	assert.Equal(t, gosnmp.AES256C, sp.PrivacyProtocol)
+	assert.Equal(t, "secret456", sp.PrivacyPassphrase)
+}
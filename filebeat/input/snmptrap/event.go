@@ -0,0 +1,188 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snmptrap
+
+import (
+	"encoding/hex"
+	"net"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gosnmp/gosnmp"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+const (
+	oidSysUpTime   = ".1.3.6.1.2.1.1.3.0"
+	oidSnmpTrapOID = ".1.3.6.1.6.3.1.1.4.1.0"
+
+	// oidSnmpTraps is the prefix of the generic traps defined in SNMPv2-MIB.
+	// A v1 generic trap N maps to snmpTraps.(N+1) as described in RFC 3584.
+	oidSnmpTraps = ".1.3.6.1.6.3.1.1.5"
+)
+
+var versions = map[gosnmp.SnmpVersion]string{
+	gosnmp.Version1:  "1",
+	gosnmp.Version2c: "2c",
+	gosnmp.Version3:  "3",
+}
+
+var pduTypes = map[gosnmp.PDUType]string{
+	gosnmp.Trap:          "trap",
+	gosnmp.SNMPv2Trap:    "trap",
+	gosnmp.InformRequest: "inform",
+}
+
+// createEvent converts a decoded trap or inform into an event. OIDs are
+// translated to names with mibs, which may be nil.
+func createEvent(p *gosnmp.SnmpPacket, remote *net.UDPAddr, now time.Time, mibs *mibTree) beat.Event {
+	trap := mapstr.M{
+		"version": versions[p.Version],
+		"type":    pduTypes[p.PDUType],
+	}
+
+	var trapOID string
+	if p.Version == gosnmp.Version1 {
+		trap["enterprise"] = oidField(p.Enterprise, mibs)
+		trap["agent_address"] = p.AgentAddress
+		trap["generic_trap"] = p.GenericTrap
+		trap["specific_trap"] = p.SpecificTrap
+		trap["uptime"] = p.Timestamp
+		trapOID = v1TrapOID(p)
+	} else {
+		trap["request_id"] = p.RequestID
+	}
+	if p.Version == gosnmp.Version3 {
+		if sp, ok := p.SecurityParameters.(*gosnmp.UsmSecurityParameters); ok {
+			trap["user"] = sp.UserName
+			trap["engine_id"] = hex.EncodeToString([]byte(sp.AuthoritativeEngineID))
+		}
+		if p.ContextName != "" {
+			trap["context_name"] = p.ContextName
+		}
+	}
+
+	variables := make([]mapstr.M, 0, len(p.Variables))
+	for _, v := range p.Variables {
+		switch v.Name {
+		case oidSysUpTime:
+			trap["uptime"] = v.Value
+		case oidSnmpTrapOID:
+			if oid, ok := v.Value.(string); ok {
+				trapOID = oid
+			}
+		}
+		variables = append(variables, variableField(v, mibs))
+	}
+	trap["variables"] = variables
+	if trapOID != "" {
+		trap["oid"] = oidField(trapOID, mibs)
+	}
+
+	fields := mapstr.M{
+		"snmp": mapstr.M{
+			"trap": trap,
+		},
+		"event": mapstr.M{
+			"kind": "alert",
+		},
+	}
+	if remote != nil {
+		fields["log"] = mapstr.M{
+			"source": mapstr.M{
+				"address": remote.String(),
+			},
+		}
+		fields["source"] = mapstr.M{
+			"ip":   remote.IP.String(),
+			"port": remote.Port,
+		}
+	}
+	return beat.Event{
+		Timestamp: now,
+		Fields:    fields,
+	}
+}
+
+// v1TrapOID returns the SNMPv2 notification OID equivalent to a v1 trap
+// following RFC 3584 section 3.1.
+func v1TrapOID(p *gosnmp.SnmpPacket) string {
+	const enterpriseSpecific = 6
+	if p.GenericTrap != enterpriseSpecific {
+		return oidSnmpTraps + "." + strconv.Itoa(p.GenericTrap+1)
+	}
+	return p.Enterprise + ".0." + strconv.Itoa(p.SpecificTrap)
+}
+
+// oidField returns the OID, or its MIB name when it can be resolved, in a
+// map suitable for an event.
+func oidField(oid string, mibs *mibTree) mapstr.M {
+	m := mapstr.M{"id": oid}
+	if name, ok := mibs.resolve(oid); ok {
+		m["name"] = name
+	}
+	return m
+}
+
+func variableField(v gosnmp.SnmpPDU, mibs *mibTree) mapstr.M {
+	m := oidField(v.Name, mibs)
+	m["type"] = v.Type.String()
+	if value := variableValue(v); value != nil {
+		m["value"] = value
+	}
+	return m
+}
+
+// variableValue converts a varbind value to a type that can be serialized
+// in an event. Octet strings that are not valid printable UTF-8 are hex
+// encoded.
+func variableValue(v gosnmp.SnmpPDU) interface{} {
+	switch v.Type {
+	case gosnmp.Null, gosnmp.NoSuchObject, gosnmp.NoSuchInstance, gosnmp.EndOfMibView:
+		return nil
+	case gosnmp.OctetString, gosnmp.Opaque, gosnmp.BitString:
+		b, ok := v.Value.([]byte)
+		if !ok {
+			return v.Value
+		}
+		if isPrintable(b) {
+			return string(b)
+		}
+		return hex.EncodeToString(b)
+	case gosnmp.Counter64:
+		return gosnmp.ToBigInt(v.Value).Uint64()
+	default:
+		return v.Value
+	}
+}
+
+func isPrintable(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snmptrap
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+func TestCreateEventV1(t *testing.T) {
+	now := time.Now()
+	p := &gosnmp.SnmpPacket{
+		Version:   gosnmp.Version1,
+		Community: "public",
+		PDUType:   gosnmp.Trap,
+		SnmpTrap: gosnmp.SnmpTrap{
+			Enterprise:   ".1.3.6.1.4.1.8072.3.2.10",
+			AgentAddress: "192.0.2.1",
+			GenericTrap:  2,
+			Timestamp:    500,
+		},
+		Variables: []gosnmp.SnmpPDU{
+			{Name: ".1.3.6.1.2.1.2.2.1.1.2", Type: gosnmp.Integer, Value: 2},
+			{Name: ".1.3.6.1.4.1.8072.9", Type: gosnmp.OctetString, Value: []byte{0x00, 0xff, 0x10}},
+			{Name: ".1.3.6.1.4.1.8072.10", Type: gosnmp.Null},
+		},
+	}
+	remote := &net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: 1024}
+
+	evt := createEvent(p, remote, now, nil)
+	assert.Equal(t, now, evt.Timestamp)
+	assert.Equal(t, mapstr.M{
+		"event": mapstr.M{"kind": "alert"},
+		"log": mapstr.M{
+			"source": mapstr.M{"address": "192.0.2.1:1024"},
+		},
+		"source": mapstr.M{"ip": "192.0.2.1", "port": 1024},
+		"snmp": mapstr.M{
+			"trap": mapstr.M{
+				"version":       "1",
+				"type":          "trap",
+				"enterprise":    mapstr.M{"id": ".1.3.6.1.4.1.8072.3.2.10"},
+				"agent_address": "192.0.2.1",
+				"generic_trap":  2,
+				"specific_trap": 0,
+				"uptime":        uint(500),
+				"oid":           mapstr.M{"id": ".1.3.6.1.6.3.1.1.5.3"},
+				"variables": []mapstr.M{
+					{"id": ".1.3.6.1.2.1.2.2.1.1.2", "type": "Integer", "value": 2},
+					{"id": ".1.3.6.1.4.1.8072.9", "type": "OctetString", "value": "00ff10"},
+					{"id": ".1.3.6.1.4.1.8072.10", "type": "Null"},
+				},
+			},
+		},
+	}, evt.Fields)
+}
+
+func TestV1TrapOID(t *testing.T) {
+	tests := []struct {
+		generic, specific int
+		want              string
+	}{
+		{generic: 0, want: ".1.3.6.1.6.3.1.1.5.1"},
+		{generic: 5, want: ".1.3.6.1.6.3.1.1.5.6"},
+		{generic: 6, specific: 17, want: ".1.3.6.1.4.1.9.0.17"},
+	}
+	for _, tc := range tests {
+		p := &gosnmp.SnmpPacket{SnmpTrap: gosnmp.SnmpTrap{
+			Enterprise:   ".1.3.6.1.4.1.9",
+			GenericTrap:  tc.generic,
+			SpecificTrap: tc.specific,
+		}}
+		assert.Equal(t, tc.want, v1TrapOID(p))
+	}
+}
+
+func TestVariableValue(t *testing.T) {
+	tests := []struct {
+		pdu  gosnmp.SnmpPDU
+		want interface{}
+	}{
+		{pdu: gosnmp.SnmpPDU{Type: gosnmp.OctetString, Value: []byte("link down on ge-0/0/1")}, want: "link down on ge-0/0/1"},
+		{pdu: gosnmp.SnmpPDU{Type: gosnmp.OctetString, Value: []byte{0xde, 0xad, 0xbe, 0xef}}, want: "deadbeef"},
+		{pdu: gosnmp.SnmpPDU{Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.6.3.1.1.5.4"}, want: ".1.3.6.1.6.3.1.1.5.4"},
+		{pdu: gosnmp.SnmpPDU{Type: gosnmp.IPAddress, Value: "198.51.100.7"}, want: "198.51.100.7"},
+		{pdu: gosnmp.SnmpPDU{Type: gosnmp.Counter64, Value: uint64(1 << 40)}, want: uint64(1 << 40)},
+		{pdu: gosnmp.SnmpPDU{Type: gosnmp.NoSuchInstance}, want: nil},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.want, variableValue(tc.pdu), tc.pdu.Type.String())
+	}
+}
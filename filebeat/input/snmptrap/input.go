@@ -0,0 +1,187 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snmptrap
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+
+	input "github.com/elastic/beats/v7/filebeat/input/v2"
+	stateless "github.com/elastic/beats/v7/filebeat/input/v2/input-stateless"
+	"github.com/elastic/beats/v7/libbeat/feature"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp"
+)
+
+const inputName = "snmp_trap"
+
+func Plugin() input.Plugin {
+	return input.Plugin{
+		Name:       inputName,
+		Stability:  feature.Beta,
+		Deprecated: false,
+		Info:       "SNMP trap and inform receiver",
+		Manager:    stateless.NewInputManager(configure),
+	}
+}
+
+func configure(cfg *conf.C) (stateless.Input, error) {
+	config := defaultConfig()
+	if err := cfg.Unpack(&config); err != nil {
+		return nil, err
+	}
+
+	return newReceiver(config)
+}
+
+type receiver struct {
+	config
+	communities map[string]bool
+}
+
+func newReceiver(config config) (*receiver, error) {
+	r := &receiver{config: config}
+	if len(config.Communities) != 0 {
+		r.communities = make(map[string]bool, len(config.Communities))
+		for _, c := range config.Communities {
+			r.communities[c] = true
+		}
+	}
+	return r, nil
+}
+
+func (r *receiver) Name() string { return inputName }
+
+func (r *receiver) Test(_ input.TestContext) error {
+	l, err := net.ListenPacket("udp", r.config.Host)
+	if err != nil {
+		return err
+	}
+	return l.Close()
+}
+
+func (r *receiver) Run(ctx input.Context, publisher stateless.Publisher) error {
+	log := ctx.Logger.With("host", r.config.Host)
+
+	var mibs *mibTree
+	if r.config.ResolveOIDs {
+		var err error
+		mibs, err = loadMIBs(r.config.MIBPaths, log)
+		if err != nil {
+			return err
+		}
+		log.Debugw("Loaded MIB definitions", "oids", len(mibs.names))
+	}
+
+	params, err := r.params(log)
+	if err != nil {
+		return err
+	}
+	listener := gosnmp.NewTrapListener()
+	listener.Params = params
+	listener.OnNewTrap = func(p *gosnmp.SnmpPacket, remote *net.UDPAddr) {
+		if !r.accept(p) {
+			log.Debugw("Dropping trap with unknown community", "remote_address", remote.String(), "version", versions[p.Version])
+			return
+		}
+		publisher.Publish(createEvent(p, remote, time.Now(), mibs))
+	}
+
+	log.Info("starting snmp_trap input")
+	defer log.Info("snmp_trap input stopped")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- listener.Listen(r.config.Host)
+	}()
+
+	// The listener must be closed only once it is listening, otherwise
+	// closing is a no-op and Listen never returns.
+	select {
+	case err := <-done:
+		return fmt.Errorf("failed to listen on %s: %w", r.config.Host, err)
+	case <-listener.Listening():
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Cancelation.Done():
+		listener.Close()
+		<-done
+		return ctx.Cancelation.Err()
+	}
+}
+
+// accept reports whether a trap passes the community check. SNMPv3 traps
+// are authenticated by the USM users while unmarshaling.
+func (r *receiver) accept(p *gosnmp.SnmpPacket) bool {
+	if p.Version == gosnmp.Version3 || r.communities == nil {
+		return true
+	}
+	return r.communities[p.Community]
+}
+
+// params returns the GoSNMP parameters used to decode traps and answer
+// informs.
+func (r *receiver) params(log *logp.Logger) (*gosnmp.GoSNMP, error) {
+	logger := gosnmp.NewLogger(&debugLogger{log: log.Named("gosnmp")})
+	params := &gosnmp.GoSNMP{
+		Version: gosnmp.Version2c,
+		Logger:  logger,
+	}
+	if len(r.config.Users) == 0 {
+		return params, nil
+	}
+
+	// The version decoded from each packet takes precedence, but
+	// authenticating v3 packets requires the parameters to be v3.
+	params.Version = gosnmp.Version3
+	table := gosnmp.NewSnmpV3SecurityParametersTable(logger)
+	for _, u := range r.config.Users {
+		if err := table.Add(u.Name, u.securityParameters(logger)); err != nil {
+			return nil, fmt.Errorf("failed to initialize keys of SNMPv3 user %q: %w", u.Name, err)
+		}
+	}
+	params.TrapSecurityParametersTable = table
+	if id := r.config.engineID(); id != "" {
+		params.SecurityModel = gosnmp.UserSecurityModel
+		params.SecurityParameters = &gosnmp.UsmSecurityParameters{
+			AuthoritativeEngineID:  id,
+			AuthenticationProtocol: gosnmp.NoAuth,
+			PrivacyProtocol:        gosnmp.NoPriv,
+			Logger:                 logger,
+		}
+	}
+	return params, nil
+}
+
+type debugLogger struct {
+	log *logp.Logger
+}
+
+func (l *debugLogger) Print(v ...interface{}) {
+	l.log.Debug(v...)
+}
+
+func (l *debugLogger) Printf(format string, v ...interface{}) {
+	l.log.Debugf(format, v...)
+}
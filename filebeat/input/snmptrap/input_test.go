@@ -0,0 +1,205 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snmptrap
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	input "github.com/elastic/beats/v7/filebeat/input/v2"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+type eventCollector struct {
+	mu     sync.Mutex
+	events []beat.Event
+}
+
+func (c *eventCollector) Publish(e beat.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events = append(c.events, e)
+}
+
+func (c *eventCollector) get() []beat.Event {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]beat.Event(nil), c.events...)
+}
+
+func TestInput(t *testing.T) {
+	host := freeUDPAddr(t)
+	cfg := conf.MustNewConfigFrom(map[string]interface{}{
+		"host":         host,
+		"communities":  []string{"public"},
+		"resolve_oids": true,
+		"users": []map[string]interface{}{
+			{"name": "operator", "auth_protocol": "sha256", "auth_passphrase": "authpass123", "priv_protocol": "aes", "priv_passphrase": "privpass123"},
+		},
+	})
+	inp, err := configure(cfg)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var collector eventCollector
+	done := make(chan error, 1)
+	go func() {
+		done <- inp.Run(input.Context{
+			ID:          "test",
+			Logger:      logp.NewLogger(inputName),
+			Cancelation: ctx,
+		}, &collector)
+	}()
+
+	vars := []gosnmp.SnmpPDU{
+		{Name: ".1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(1234)},
+		{Name: ".1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.6.3.1.1.5.3"},
+		{Name: ".1.3.6.1.2.1.2.2.1.1.7", Type: gosnmp.Integer, Value: 7},
+		{Name: ".1.3.6.1.2.1.2.2.1.2.7", Type: gosnmp.OctetString, Value: "eth0"},
+	}
+
+	// Informs are acknowledged, so the first one also waits for the
+	// receiver to be ready.
+	t.Run("v2c inform is acknowledged", func(t *testing.T) {
+		require.Eventually(t, func() bool {
+			client := &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "public"}
+			return sendTrap(t, host, client, gosnmp.SnmpTrap{Variables: vars, IsInform: true}) == nil
+		}, 10*time.Second, 100*time.Millisecond)
+		evt := waitForEvents(t, &collector, 1)[0]
+		assert.Equal(t, "inform", mustGet(t, evt.Fields, "snmp.trap.type"))
+	})
+
+	t.Run("v2c trap", func(t *testing.T) {
+		require.NoError(t, sendTrap(t, host, &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "public"}, gosnmp.SnmpTrap{Variables: vars}))
+		evt := waitForEvents(t, &collector, 2)[1]
+
+		trap := mustGet(t, evt.Fields, "snmp.trap").(mapstr.M)
+		assert.Equal(t, "2c", trap["version"])
+		assert.Equal(t, "trap", trap["type"])
+		assert.Equal(t, uint32(1234), trap["uptime"])
+		assert.Equal(t, mapstr.M{"id": ".1.3.6.1.6.3.1.1.5.3", "name": "linkDown"}, trap["oid"])
+		assert.Contains(t, trap["variables"], mapstr.M{
+			"id": ".1.3.6.1.2.1.2.2.1.2.7", "name": "ifDescr.7", "type": "OctetString", "value": "eth0",
+		})
+		assert.Equal(t, "127.0.0.1", mustGet(t, evt.Fields, "source.ip"))
+	})
+
+	t.Run("unknown community is dropped", func(t *testing.T) {
+		require.NoError(t, sendTrap(t, host, &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "private"}, gosnmp.SnmpTrap{Variables: vars}))
+		// The next trap is only published after the dropped one was handled.
+		require.NoError(t, sendTrap(t, host, &gosnmp.GoSNMP{Version: gosnmp.Version1, Community: "public"}, gosnmp.SnmpTrap{
+			Variables:    vars[2:],
+			Enterprise:   ".1.3.6.1.4.1.99999",
+			AgentAddress: "192.0.2.1",
+			GenericTrap:  6,
+			SpecificTrap: 3,
+			Timestamp:    42,
+		}))
+		events := waitForEvents(t, &collector, 3)
+		require.Len(t, events, 3)
+
+		trap := mustGet(t, events[2].Fields, "snmp.trap").(mapstr.M)
+		assert.Equal(t, "1", trap["version"])
+		assert.Equal(t, "192.0.2.1", trap["agent_address"])
+		assert.Equal(t, mapstr.M{"id": ".1.3.6.1.4.1.99999.0.3", "name": "enterprises.99999.0.3"}, trap["oid"])
+	})
+
+	t.Run("v3 authPriv trap", func(t *testing.T) {
+		require.NoError(t, sendTrap(t, host, &gosnmp.GoSNMP{
+			Version:       gosnmp.Version3,
+			SecurityModel: gosnmp.UserSecurityModel,
+			MsgFlags:      gosnmp.AuthPriv,
+			SecurityParameters: &gosnmp.UsmSecurityParameters{
+				UserName:                 "operator",
+				AuthoritativeEngineID:    "\x80\x00\x00\x00\x01\x02\x03\x04",
+				AuthenticationProtocol:   gosnmp.SHA256,
+				AuthenticationPassphrase: "authpass123",
+				PrivacyProtocol:          gosnmp.AES,
+				PrivacyPassphrase:        "privpass123",
+			},
+		}, gosnmp.SnmpTrap{Variables: vars}))
+		evt := waitForEvents(t, &collector, 4)[3]
+
+		trap := mustGet(t, evt.Fields, "snmp.trap").(mapstr.M)
+		assert.Equal(t, "3", trap["version"])
+		assert.Equal(t, "operator", trap["user"])
+		assert.Equal(t, "8000000001020304", trap["engine_id"])
+		assert.Equal(t, mapstr.M{"id": ".1.3.6.1.6.3.1.1.5.3", "name": "linkDown"}, trap["oid"])
+	})
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(10 * time.Second):
+		t.Fatal("input did not stop")
+	}
+}
+
+func freeUDPAddr(t *testing.T) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	return conn.LocalAddr().String()
+}
+
+func sendTrap(t *testing.T, addr string, client *gosnmp.GoSNMP, trap gosnmp.SnmpTrap) error {
+	t.Helper()
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	p, err := strconv.ParseUint(port, 10, 16)
+	require.NoError(t, err)
+
+	client.Target = host
+	client.Port = uint16(p)
+	client.Timeout = time.Second
+	require.NoError(t, client.Connect())
+	defer client.Conn.Close()
+
+	_, err = client.SendTrap(trap)
+	return err
+}
+
+func waitForEvents(t *testing.T, c *eventCollector, n int) []beat.Event {
+	t.Helper()
+	var events []beat.Event
+	require.Eventually(t, func() bool {
+		events = c.get()
+		return len(events) >= n
+	}, 10*time.Second, 10*time.Millisecond)
+	return events
+}
+
+func mustGet(t *testing.T, m mapstr.M, key string) interface{} {
+	t.Helper()
+	v, err := m.GetValue(key)
+	require.NoError(t, err, key)
+	return v
+}
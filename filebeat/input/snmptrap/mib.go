@@ -0,0 +1,283 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snmptrap
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/elastic/elastic-agent-libs/logp"
+)
+
+// mibTree maps numeric OIDs to the object names defined in MIB modules.
+type mibTree struct {
+	names map[string]string
+}
+
+// baseOIDs holds the well-known registrations from SNMPv2-SMI, SNMPv2-MIB and
+// RFC1213-MIB so that standard traps resolve even without MIB files and
+// vendor MIBs can refer to them.
+var baseOIDs = map[string]string{
+	".1":                   "iso",
+	".1.3":                 "org",
+	".1.3.6":               "dod",
+	".1.3.6.1":             "internet",
+	".1.3.6.1.1":           "directory",
+	".1.3.6.1.2":           "mgmt",
+	".1.3.6.1.2.1":         "mib-2",
+	".1.3.6.1.2.1.1":       "system",
+	".1.3.6.1.2.1.1.1":     "sysDescr",
+	".1.3.6.1.2.1.1.2":     "sysObjectID",
+	".1.3.6.1.2.1.1.3":     "sysUpTime",
+	".1.3.6.1.2.1.1.4":     "sysContact",
+	".1.3.6.1.2.1.1.5":     "sysName",
+	".1.3.6.1.2.1.1.6":     "sysLocation",
+	".1.3.6.1.2.1.2":       "interfaces",
+	".1.3.6.1.2.1.2.2":     "ifTable",
+	".1.3.6.1.2.1.2.2.1":   "ifEntry",
+	".1.3.6.1.2.1.2.2.1.1": "ifIndex",
+	".1.3.6.1.2.1.2.2.1.2": "ifDescr",
+	".1.3.6.1.2.1.2.2.1.7": "ifAdminStatus",
+	".1.3.6.1.2.1.2.2.1.8": "ifOperStatus",
+	".1.3.6.1.3":           "experimental",
+	".1.3.6.1.4":           "private",
+	".1.3.6.1.4.1":         "enterprises",
+	".1.3.6.1.5":           "security",
+	".1.3.6.1.6":           "snmpV2",
+	".1.3.6.1.6.1":         "snmpDomains",
+	".1.3.6.1.6.2":         "snmpProxys",
+	".1.3.6.1.6.3":         "snmpModules",
+	".1.3.6.1.6.3.1":       "snmpMIB",
+	".1.3.6.1.6.3.1.1":     "snmpMIBObjects",
+	".1.3.6.1.6.3.1.1.4":   "snmpTrap",
+	".1.3.6.1.6.3.1.1.4.1": "snmpTrapOID",
+	".1.3.6.1.6.3.1.1.4.3": "snmpTrapEnterprise",
+	".1.3.6.1.6.3.1.1.5":   "snmpTraps",
+	".1.3.6.1.6.3.1.1.5.1": "coldStart",
+	".1.3.6.1.6.3.1.1.5.2": "warmStart",
+	".1.3.6.1.6.3.1.1.5.3": "linkDown",
+	".1.3.6.1.6.3.1.1.5.4": "linkUp",
+	".1.3.6.1.6.3.1.1.5.5": "authenticationFailure",
+	".1.3.6.1.6.3.1.1.5.6": "egpNeighborLoss",
+	".1.3.6.1.6.3.18.1.3":  "snmpTrapAddress",
+	".1.3.6.1.6.3.18.1.4":  "snmpTrapCommunity",
+}
+
+var (
+	// mibAssignment matches value assignments of OID valued macros such as
+	//   ifIndex OBJECT-TYPE ... ::= { ifEntry 1 }
+	//   enterprises OBJECT IDENTIFIER ::= { private 1 }
+	mibAssignment = regexp.MustCompile(`(?s)([a-z][A-Za-z0-9-]*)\s+(OBJECT\s+IDENTIFIER|OBJECT-TYPE|OBJECT-IDENTITY|MODULE-IDENTITY|NOTIFICATION-TYPE|OBJECT-GROUP|NOTIFICATION-GROUP|MODULE-COMPLIANCE|AGENT-CAPABILITIES)\b(?:[^:]|:[^:]|::[^=])*?::=\s*\{([^}]*)\}`)
+
+	// mibSpacedArc matches the "name(number)" form of an OID element with
+	// optional white space.
+	mibSpacedArc = regexp.MustCompile(`([A-Za-z][A-Za-z0-9-]*)\s*\(\s*(\d+)\s*\)`)
+)
+
+var errNotMIB = errors.New("not a MIB module: missing DEFINITIONS")
+
+type mibDefinition struct {
+	name   string
+	parent string
+	arcs   []string
+}
+
+// loadMIBs reads every MIB file found in paths and builds the OID name tree.
+// Directories are read non-recursively and files in them that are not MIB
+// modules are skipped. Definitions may refer to objects defined in other
+// files regardless of the order in which files are read.
+func loadMIBs(paths []string, log *logp.Logger) (*mibTree, error) {
+	var defs []mibDefinition
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read MIB path: %w", err)
+		}
+		files, isDir := []string{p}, info.IsDir()
+		if isDir {
+			entries, err := os.ReadDir(p)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read MIB directory: %w", err)
+			}
+			files = files[:0]
+			for _, e := range entries {
+				if e.Type()&fs.ModeType == 0 {
+					files = append(files, filepath.Join(p, e.Name()))
+				}
+			}
+		}
+		for _, f := range files {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read MIB file: %w", err)
+			}
+			d, err := parseMIB(string(data))
+			if errors.Is(err, errNotMIB) && isDir {
+				log.Debugw("Skipping file that is not a MIB module", "file", f)
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse MIB file %s: %w", f, err)
+			}
+			defs = append(defs, d...)
+		}
+	}
+	return newMIBTree(defs), nil
+}
+
+// parseMIB extracts the OID definitions from the text of a MIB module.
+func parseMIB(text string) ([]mibDefinition, error) {
+	text = stripMIBText(text)
+	if !strings.Contains(text, "DEFINITIONS") {
+		return nil, errNotMIB
+	}
+
+	var defs []mibDefinition
+	for _, m := range mibAssignment.FindAllStringSubmatch(text, -1) {
+		if d, ok := parseOIDValue(m[1], m[3]); ok {
+			defs = append(defs, d)
+		}
+	}
+	return defs, nil
+}
+
+// stripMIBText removes comments and the contents of quoted strings from the
+// text of a MIB module. ASN.1 comments run from "--" to the next "--" or the
+// end of the line, strings may span several lines.
+func stripMIBText(text string) string {
+	var b strings.Builder
+	b.Grow(len(text))
+	inString, inComment := false, false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case inString:
+			if c == '"' {
+				inString = false
+				b.WriteByte(c)
+			}
+		case inComment:
+			if c == '\n' {
+				inComment = false
+				b.WriteByte(c)
+			} else if c == '-' && i+1 < len(text) && text[i+1] == '-' {
+				inComment = false
+				i++
+			}
+		case c == '"':
+			inString = true
+			b.WriteByte(c)
+		case c == '-' && i+1 < len(text) && text[i+1] == '-':
+			inComment = true
+			i++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// parseOIDValue converts the elements of an OID value such as
+// "{ ifEntry 1 }" or "{ iso(1) org(3) dod(6) }" into a parent name followed
+// by numeric arcs. Elements in "name(number)" form contribute their number.
+func parseOIDValue(name, value string) (mibDefinition, bool) {
+	d := mibDefinition{name: name}
+	value = mibSpacedArc.ReplaceAllString(value, "$1($2)")
+	for i, f := range strings.Fields(value) {
+		label, num, hasNum := strings.Cut(strings.TrimSuffix(f, ")"), "(")
+		if !hasNum {
+			num = label
+		}
+		if _, err := strconv.ParseUint(num, 10, 32); err == nil {
+			d.arcs = append(d.arcs, num)
+			continue
+		}
+		if i != 0 || hasNum {
+			return d, false
+		}
+		d.parent = label
+	}
+	return d, len(d.arcs) != 0
+}
+
+// newMIBTree resolves the definitions against the base OIDs and each other.
+func newMIBTree(defs []mibDefinition) *mibTree {
+	names := make(map[string]string, len(baseOIDs)+len(defs))
+	oids := make(map[string]string, len(baseOIDs)+len(defs))
+	for oid, name := range baseOIDs {
+		names[oid] = name
+		if !strings.Contains(name, ".") {
+			oids[name] = oid
+		}
+	}
+
+	// Definitions may refer to parents defined later, so resolve until no
+	// further progress is made.
+	pending := defs
+	for len(pending) != 0 {
+		var unresolved []mibDefinition
+		for _, d := range pending {
+			prefix := ""
+			if d.parent != "" {
+				var ok bool
+				prefix, ok = oids[d.parent]
+				if !ok {
+					unresolved = append(unresolved, d)
+					continue
+				}
+			}
+			oid := prefix + "." + strings.Join(d.arcs, ".")
+			oids[d.name] = oid
+			names[oid] = d.name
+		}
+		if len(unresolved) == len(pending) {
+			break
+		}
+		pending = unresolved
+	}
+	return &mibTree{names: names}
+}
+
+// resolve returns the symbolic name of oid. The name of the longest
+// registered prefix is used and the remaining arcs, typically the instance
+// index, are appended. It returns false when no prefix is registered.
+func (t *mibTree) resolve(oid string) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	if !strings.HasPrefix(oid, ".") {
+		oid = "." + oid
+	}
+	for prefix := oid; prefix != ""; {
+		if name, ok := t.names[prefix]; ok {
+			return name + oid[len(prefix):], true
+		}
+		i := strings.LastIndexByte(prefix, '.')
+		if i <= 0 {
+			break
+		}
+		prefix = prefix[:i]
+	}
+	return "", false
+}
@@ -0,0 +1,115 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package snmptrap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent-libs/logp"
+)
+
+const testMIB = `
+ACME-TRAP-MIB DEFINITIONS ::= BEGIN
+
+IMPORTS
+    MODULE-IDENTITY, OBJECT-TYPE, NOTIFICATION-TYPE, enterprises
+        FROM SNMPv2-SMI;
+
+-- The module identity is defined after an object
+-- that refers to it to exercise out of order resolution.
+acmeAlarmState OBJECT-TYPE
+    SYNTAX      INTEGER { ok(1), failed(2) } -- inline comment
+    MAX-ACCESS  accessible-for-notify
+    STATUS      current
+    DESCRIPTION "The state of the alarm. Contains ::= in text."
+    ::= { acmeObjects 1 }
+
+acme MODULE-IDENTITY
+    LAST-UPDATED "202401010000Z"
+    ORGANIZATION "ACME"
+    CONTACT-INFO "noc@example.com"
+    DESCRIPTION  "Test MIB."
+    ::= { enterprises 99999 }
+
+acmeObjects OBJECT IDENTIFIER ::= { acme 1 }
+acmeNotifications OBJECT IDENTIFIER ::= { acme 0 }
+
+acmeAlarm NOTIFICATION-TYPE
+    OBJECTS     { acmeAlarmState }
+    STATUS      current
+    DESCRIPTION "An alarm was raised."
+    ::= { acmeNotifications 1 }
+
+acmeRoot OBJECT IDENTIFIER ::= { iso(1) org(3) dod(6) internet(1) private(4) 2 }
+
+END
+`
+
+func TestParseMIB(t *testing.T) {
+	defs, err := parseMIB(testMIB)
+	require.NoError(t, err)
+
+	tree := newMIBTree(defs)
+	tests := map[string]string{
+		".1.3.6.1.4.1.99999":       "acme",
+		".1.3.6.1.4.1.99999.1.1.0": "acmeAlarmState.0",
+		".1.3.6.1.4.1.99999.0.1":   "acmeAlarm",
+		"1.3.6.1.4.2.5":            "acmeRoot.5",
+		".1.3.6.1.6.3.1.1.5.3":     "linkDown",
+		".1.3.6.1.2.1.1.3.0":       "sysUpTime.0",
+	}
+	for oid, want := range tests {
+		got, ok := tree.resolve(oid)
+		assert.True(t, ok, oid)
+		assert.Equal(t, want, got, oid)
+	}
+
+	_, ok := tree.resolve(".2.1")
+	assert.False(t, ok)
+}
+
+func TestParseMIBNotAModule(t *testing.T) {
+	_, err := parseMIB("just some text")
+	assert.ErrorIs(t, err, errNotMIB)
+}
+
+func TestLoadMIBs(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ACME-TRAP-MIB.txt"), []byte(testMIB), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("not a MIB"), 0o644))
+
+	tree, err := loadMIBs([]string{dir}, logp.NewLogger("test"))
+	require.NoError(t, err)
+	name, ok := tree.resolve(".1.3.6.1.4.1.99999.0.1")
+	assert.True(t, ok)
+	assert.Equal(t, "acmeAlarm", name)
+
+	_, err = loadMIBs([]string{filepath.Join(dir, "README")}, logp.NewLogger("test"))
+	assert.ErrorIs(t, err, errNotMIB)
+}
+
+func TestNilMIBTree(t *testing.T) {
+	var tree *mibTree
+	_, ok := tree.resolve(".1.3.6.1")
+	assert.False(t, ok)
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/elastic/beats/v7/filebeat/inputsource/udp"
 	"github.com/elastic/beats/v7/filebeat/inputsource/unix"
 	"github.com/elastic/beats/v7/libbeat/common/cfgtype"
+	"github.com/elastic/beats/v7/libbeat/reader/readfile"
 	conf "github.com/elastic/elastic-agent-libs/config"
 	"github.com/elastic/elastic-agent-libs/logp"
 )
@@ -67,6 +68,7 @@ type syslogTCP struct {
 	tcp.Config    `config:",inline"`
 	LineDelimiter string                `config:"line_delimiter" validate:"nonzero"`
 	Framing       streaming.FramingType `config:"framing"`
+	LengthPrefix  readfile.LengthPrefix `config:"length_prefix"`
 }
 
 var defaultTCP = syslogTCP{
@@ -109,7 +111,7 @@ func factory(
 			return nil, err
 		}
 
-		splitFunc, err := streaming.SplitFunc(config.Framing, []byte(config.LineDelimiter))
+		splitFunc, err := streaming.SplitFunc(config.Framing, []byte(config.LineDelimiter), config.LengthPrefix)
 		if err != nil {
 			return nil, err
 		}
@@ -31,6 +31,7 @@ import (
 	"github.com/elastic/beats/v7/filebeat/inputsource/tcp"
 	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/feature"
+	"github.com/elastic/beats/v7/libbeat/reader/readfile"
 
 	conf "github.com/elastic/elastic-agent-libs/config"
 	"github.com/elastic/elastic-agent-libs/mapstr"
@@ -76,6 +77,7 @@ type config struct {
 
 	LineDelimiter string                `config:"line_delimiter" validate:"nonzero"`
 	Framing       streaming.FramingType `config:"framing"`
+	LengthPrefix  readfile.LengthPrefix `config:"length_prefix"`
 }
 
 func newServer(config config) (*server, error) {
@@ -102,7 +104,7 @@ func (s *server) Run(ctx input.Context, publisher stateless.Publisher) error {
 	metrics := netmetrics.NewTCP("tcp", ctx.ID, s.config.Host, pollInterval, log)
 	defer metrics.Close()
 
-	split, err := streaming.SplitFunc(s.config.Framing, []byte(s.config.LineDelimiter))
+	split, err := streaming.SplitFunc(s.config.Framing, []byte(s.config.LineDelimiter), s.config.LengthPrefix)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,62 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package v2
+
+import "time"
+
+// SourceReporter can be implemented by an InputManager to report the
+// sources its inputs are collecting from, and the state stored in the
+// registry for them. The reports are used for debugging only, and are
+// served by the HTTP monitoring endpoint.
+type SourceReporter interface {
+	// Harvesters returns the sources that are currently being collected from.
+	Harvesters() []HarvesterState
+
+	// RegistryEntries returns the entries of the registry known to the
+	// InputManager.
+	RegistryEntries() []RegistryEntry
+}
+
+// HarvesterState describes a source that is currently being collected from.
+type HarvesterState struct {
+	InputID string `json:"input_id"`
+	Key     string `json:"key"`
+	Path    string `json:"path,omitempty"`
+
+	// Identifier is the name of the method used to identify the source.
+	Identifier string `json:"identifier,omitempty"`
+
+	Started   time.Time  `json:"started"`
+	LastEvent *time.Time `json:"last_event,omitempty"`
+
+	// Cursor is the most recent cursor, including updates of events that
+	// have not been acknowledged yet.
+	Cursor map[string]interface{} `json:"cursor,omitempty"`
+}
+
+// RegistryEntry describes the state stored in the registry for a source. The
+// cursor only includes updates of acknowledged events.
+type RegistryEntry struct {
+	InputID string                 `json:"input_id"`
+	Key     string                 `json:"key"`
+	Path    string                 `json:"path,omitempty"`
+	TTL     time.Duration          `json:"ttl"`
+	Updated time.Time              `json:"updated"`
+	Cursor  map[string]interface{} `json:"cursor,omitempty"`
+	Meta    map[string]interface{} `json:"meta,omitempty"`
+}
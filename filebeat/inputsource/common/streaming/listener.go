@@ -29,6 +29,7 @@ import (
 	"sync/atomic"
 
 	"github.com/elastic/beats/v7/filebeat/inputsource"
+	"github.com/elastic/beats/v7/libbeat/reader/readfile"
 	"github.com/elastic/elastic-agent-libs/logp"
 	"github.com/elastic/go-concert/ctxtool"
 )
@@ -55,12 +56,14 @@ type FramingType int
 const (
 	FramingDelimiter = iota
 	FramingRFC6587
+	FramingLengthPrefixed
 )
 
 var (
 	framingTypes = map[string]FramingType{
-		"delimiter": FramingDelimiter,
-		"rfc6587":   FramingRFC6587,
+		"delimiter":       FramingDelimiter,
+		"rfc6587":         FramingRFC6587,
+		"length_prefixed": FramingLengthPrefixed,
 	}
 
 	availableFramingTypesErrFormat string
@@ -213,8 +216,9 @@ func (l *Listener) Stop() {
 }
 
 // SplitFunc allows to create a `bufio.SplitFunc` based on a framing and
-// delimiter provided.
-func SplitFunc(framing FramingType, lineDelimiter []byte) (bufio.SplitFunc, error) {
+// delimiter provided. The length prefix is only used by the length_prefixed
+// framing.
+func SplitFunc(framing FramingType, lineDelimiter []byte, lengthPrefix readfile.LengthPrefix) (bufio.SplitFunc, error) {
 	if len(lineDelimiter) == 0 {
 		return nil, fmt.Errorf("line delimiter required")
 	}
@@ -230,6 +234,8 @@ func SplitFunc(framing FramingType, lineDelimiter []byte) (bufio.SplitFunc, erro
 		return FactoryDelimiter(lineDelimiter), nil
 	case FramingRFC6587:
 		return FactoryRFC6587Framing(lineDelimiter), nil
+	case FramingLengthPrefixed:
+		return readfile.ScanLengthPrefixed(lengthPrefix, 0), nil
 	default:
 		return nil, fmt.Errorf("unknown SplitFunc for framing %d and line delimiter %q", framing, lineDelimiter)
 	}
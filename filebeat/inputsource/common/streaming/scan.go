@@ -21,31 +21,14 @@ import (
 	"bufio"
 	"bytes"
 	"strconv"
+
+	"github.com/elastic/beats/v7/libbeat/reader/readfile"
 )
 
 // FactoryDelimiter return a function to split line using a custom delimiter supporting multibytes
 // delimiter, the delimiter is stripped from the returned value.
 func FactoryDelimiter(delimiter []byte) bufio.SplitFunc {
-	return func(data []byte, eof bool) (int, []byte, error) {
-		if eof && len(data) == 0 {
-			return 0, nil, nil
-		}
-		if i := bytes.Index(data, delimiter); i >= 0 {
-			return i + len(delimiter), dropDelimiter(data[0:i], delimiter), nil
-		}
-		if eof {
-			return len(data), dropDelimiter(data, delimiter), nil
-		}
-		return 0, nil, nil
-	}
-}
-
-func dropDelimiter(data []byte, delimiter []byte) []byte {
-	if len(data) > len(delimiter) &&
-		bytes.Equal(data[len(data)-len(delimiter):], delimiter) {
-		return data[0 : len(data)-len(delimiter)]
-	}
-	return data
+	return readfile.ScanDelimited(delimiter, 0)
 }
 
 // FactoryRFC6587Framing returns a function that splits based on octet
@@ -78,10 +61,10 @@ func FactoryRFC6587Framing(delimiter []byte) bufio.SplitFunc {
 			return 0, nil, nil
 		}
 		if i := bytes.Index(data, delimiter); i >= 0 {
-			return i + len(delimiter), dropDelimiter(data[0:i], delimiter), nil
+			return i + len(delimiter), readfile.DropDelimiter(data[0:i], delimiter), nil
 		}
 		if eof {
-			return len(data), dropDelimiter(data, delimiter), nil
+			return len(data), readfile.DropDelimiter(data, delimiter), nil
 		}
 		// request more data
 		return 0, nil, nil
@@ -32,6 +32,7 @@ import (
 
 	"github.com/elastic/beats/v7/filebeat/inputsource"
 	"github.com/elastic/beats/v7/filebeat/inputsource/common/streaming"
+	"github.com/elastic/beats/v7/libbeat/reader/readfile"
 	conf "github.com/elastic/elastic-agent-libs/config"
 	"github.com/elastic/elastic-agent-libs/logp"
 )
@@ -71,6 +72,7 @@ func testReceiveEventsAndMetadata(t *testing.T, network string) {
 		cfg              map[string]interface{}
 		framing          streaming.FramingType
 		delimiter        []byte
+		lengthPrefix     readfile.LengthPrefix
 		splitFunc        bufio.SplitFunc
 		expectedMessages []string
 		messageSent      string
@@ -211,6 +213,18 @@ func testReceiveEventsAndMetadata(t *testing.T, network string) {
 			},
 			messageSent: "14 <9> message \n010 <6> msg \n114 <3> message \n2",
 		},
+		{
+			name:         "length prefixed framing",
+			cfg:          map[string]interface{}{},
+			framing:      streaming.FramingLengthPrefixed,
+			delimiter:    []byte("\n"),
+			lengthPrefix: readfile.Uint16BigEndian,
+			expectedMessages: []string{
+				"message \n0",
+				"msg 1",
+			},
+			messageSent: "\x00\x0amessage \n0\x00\x05msg 1",
+		},
 	}
 
 	for _, test := range tests {
@@ -229,7 +243,7 @@ func testReceiveEventsAndMetadata(t *testing.T, network string) {
 			}
 			config.Network = network
 
-			splitFunc, err := streaming.SplitFunc(test.framing, test.delimiter)
+			splitFunc, err := streaming.SplitFunc(test.framing, test.delimiter, test.lengthPrefix)
 			if !assert.NoError(t, err) {
 				return
 			}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/elastic/beats/v7/filebeat/inputsource/common/streaming"
 	"github.com/elastic/beats/v7/libbeat/common/cfgtype"
+	"github.com/elastic/beats/v7/libbeat/reader/readfile"
 )
 
 type SocketType uint8
@@ -54,6 +55,7 @@ type Config struct {
 	MaxConnections int                   `config:"max_connections"`
 	LineDelimiter  string                `config:"line_delimiter"`
 	Framing        streaming.FramingType `config:"framing"`
+	LengthPrefix   readfile.LengthPrefix `config:"length_prefix"`
 	SocketType     SocketType            `config:"socket_type"`
 }
 
@@ -28,12 +28,15 @@ import (
 
 	conf "github.com/elastic/elastic-agent-libs/config"
 	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/paths"
 
 	"github.com/elastic/beats/v7/heartbeat/config"
 	"github.com/elastic/beats/v7/heartbeat/hbregistry"
 	"github.com/elastic/beats/v7/heartbeat/monitors"
 	"github.com/elastic/beats/v7/heartbeat/monitors/plugin"
 	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers/monitorstate"
+	"github.com/elastic/beats/v7/heartbeat/notifier"
+	"github.com/elastic/beats/v7/heartbeat/prometheus"
 	"github.com/elastic/beats/v7/heartbeat/scheduler"
 	_ "github.com/elastic/beats/v7/heartbeat/security"
 	"github.com/elastic/beats/v7/heartbeat/tracer"
@@ -44,6 +47,8 @@ import (
 	"github.com/elastic/beats/v7/libbeat/common/reload"
 	"github.com/elastic/beats/v7/libbeat/esleg/eslegclient"
 	"github.com/elastic/beats/v7/libbeat/management"
+	"github.com/elastic/beats/v7/libbeat/statestore"
+	"github.com/elastic/beats/v7/libbeat/statestore/backend/memlog"
 )
 
 // Heartbeat represents the root datastructure of this beat.
@@ -57,6 +62,9 @@ type Heartbeat struct {
 	monitorFactory     *monitors.RunnerFactory
 	autodiscover       *autodiscover.Autodiscover
 	replaceStateLoader func(sl monitorstate.StateLoader)
+	notifier           *notifier.Notifier
+	stateRegistry      *statestore.Registry
+	stateStore         *monitorstate.LocalStore
 	trace              tracer.Tracer
 }
 
@@ -85,7 +93,20 @@ func New(b *beat.Beat, rawConfig *conf.C) (beat.Beater, error) {
 
 	// Check if any of these can prevent using states client
 	stateLoader, replaceStateLoader := monitorstate.AtomicStateLoader(monitorstate.NilStateLoader)
-	if b.Config.Output.Name() == "elasticsearch" && !b.Manager.Enabled() {
+	var stateRegistry *statestore.Registry
+	var stateStore *monitorstate.LocalStore
+	if parsedConfig.StateStore.Enabled {
+		var err error
+		stateRegistry, stateStore, err = openStateStore(parsedConfig.StateStore)
+		if err != nil {
+			trace.Abort()
+			return nil, fmt.Errorf("could not open monitor state store: %w", err)
+		}
+		// The local store is used whatever the output is, it is never replaced
+		// by the ES loader.
+		stateLoader = stateStore.Load
+		replaceStateLoader = func(monitorstate.StateLoader) {}
+	} else if b.Config.Output.Name() == "elasticsearch" && !b.Manager.Enabled() {
 		// Connect to ES and setup the State loader if the output is not managed by agent
 		// Note this, intentionally, blocks until connected or max attempts reached
 		esClient, err := makeESClient(context.TODO(), b.Config.Output.Config(), 3, 2*time.Second)
@@ -116,6 +137,34 @@ func New(b *beat.Beat, rawConfig *conf.C) (beat.Beater, error) {
 
 	sched := scheduler.Create(limit, hbregistry.SchedulerRegistry, location, jobConfig, parsedConfig.RunOnce)
 
+	var clientWrappers []monitors.ClientWrapper
+	var notif *notifier.Notifier
+	if parsedConfig.Notifications.Enabled() {
+		notif, err = notifier.New(parsedConfig.Notifications)
+		if err != nil {
+			trace.Abort()
+			return nil, fmt.Errorf("could not set up notifications: %w", err)
+		}
+		clientWrappers = append(clientWrappers, notif.WrapClient)
+	}
+
+	if stateStore != nil {
+		clientWrappers = append(clientWrappers, stateStore.WrapClient)
+	}
+
+	if parsedConfig.Prometheus.Enabled() {
+		if b.API != nil {
+			promRegistry := prometheus.NewRegistry()
+			if err := b.API.AttachHandler(prometheus.Route, promRegistry); err != nil {
+				trace.Abort()
+				return nil, fmt.Errorf("could not attach prometheus endpoint: %w", err)
+			}
+			clientWrappers = append(clientWrappers, promRegistry.WrapClient)
+		} else {
+			logp.L().Warn("heartbeat.prometheus requires the HTTP endpoint to be enabled with http.enabled, not exposing monitor metrics")
+		}
+	}
+
 	pipelineClientFactory := func(p beat.Pipeline) (beat.Client, error) {
 		return p.Connect()
 	}
@@ -125,6 +174,9 @@ func New(b *beat.Beat, rawConfig *conf.C) (beat.Beater, error) {
 		config:             parsedConfig,
 		scheduler:          sched,
 		replaceStateLoader: replaceStateLoader,
+		notifier:           notif,
+		stateRegistry:      stateRegistry,
+		stateStore:         stateStore,
 		// monitorFactory is the factory used for creating all monitor instances,
 		// wiring them up to everything needed to actually execute.
 		monitorFactory: monitors.NewFactory(monitors.FactoryParams{
@@ -134,6 +186,7 @@ func New(b *beat.Beat, rawConfig *conf.C) (beat.Beater, error) {
 			PluginsReg:            plugin.GlobalPluginsReg,
 			PipelineClientFactory: pipelineClientFactory,
 			BeatRunFrom:           parsedConfig.RunFrom,
+			ClientWrappers:        clientWrappers,
 		}),
 		trace: trace,
 	}
@@ -150,6 +203,16 @@ func (bt *Heartbeat) Run(b *beat.Beat) error {
 	bt.trace.Start()
 	defer bt.trace.Close()
 
+	// Deferred first so that the notifier is closed last, once the monitors
+	// are stopped, and sends the notifications of their last checks.
+	if bt.notifier != nil {
+		defer bt.notifier.Close()
+	}
+	if bt.stateStore != nil {
+		defer bt.stateRegistry.Close()
+		defer bt.stateStore.Close()
+	}
+
 	// Adapt local pipeline to synchronized mode if run_once is enabled
 	pipeline := b.Publisher
 	var pipelineWrapper monitors.PipelineWrapper = &monitors.NoopPipelineWrapper{}
@@ -192,6 +255,14 @@ func (bt *Heartbeat) Run(b *beat.Beat) error {
 			return err
 		}
 	}
+	if bt.config.RemoteMonitors.Enabled() {
+		stopRemoteMonitors, err := bt.RunRemoteMonitors(b)
+		if err != nil {
+			return err
+		}
+		defer stopRemoteMonitors()
+	}
+
 	// Configure the beats Manager to start after all the reloadable hooks are initialized
 	// and shutdown when the function return.
 	if err := b.Manager.Start(); err != nil {
@@ -324,6 +395,31 @@ func (bt *Heartbeat) Stop() {
 	bt.stopOnce.Do(func() { close(bt.done) })
 }
 
+// openStateStore opens the local store of the monitors' state and removes the
+// states that expired since the last run.
+func openStateStore(cfg config.StateStore) (*statestore.Registry, *monitorstate.LocalStore, error) {
+	backend, err := memlog.New(logp.L().Named("monitorstate"), memlog.Settings{
+		Root:     paths.Resolve(paths.Data, cfg.Path),
+		FileMode: cfg.Permissions,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	registry := statestore.NewRegistry(backend)
+	store, err := registry.Get("heartbeat")
+	if err != nil {
+		_ = registry.Close()
+		return nil, nil, err
+	}
+
+	localStore := monitorstate.NewLocalStore(store, cfg.TTL)
+	if err := localStore.Cleanup(); err != nil {
+		logp.L().Warnf("could not clean up monitor state store: %v", err)
+	}
+	return registry, localStore, nil
+}
+
 // makeESClient establishes an ES connection meant to load monitors' state
 func makeESClient(ctx context.Context, cfg *conf.C, attempts int, wait time.Duration) (*eslegclient.Connection, error) {
 	var (
@@ -0,0 +1,84 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/elastic/beats/v7/heartbeat/watcher"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/cfgfile"
+	"github.com/elastic/beats/v7/libbeat/common/reload"
+	conf "github.com/elastic/elastic-agent-libs/config"
+)
+
+// RunRemoteMonitors runs the monitors listed by the `heartbeat.config.remote_monitors`
+// endpoint. Whenever the list changes, only the monitors whose config changed
+// are restarted.
+func (bt *Heartbeat) RunRemoteMonitors(b *beat.Beat) (stop func(), err error) {
+	pollConfig := watcher.DefaultHTTPPollConfig()
+	if err := bt.config.RemoteMonitors.Unpack(&pollConfig); err != nil {
+		return nil, fmt.Errorf("could not read remote monitors config: %w", err)
+	}
+
+	logger := b.Info.Logger.Named("remote_monitors")
+	runners := cfgfile.NewRunnerList("remote_monitors", bt.monitorFactory, b.Publisher, logger)
+	poller, err := watcher.NewHTTPPoller(pollConfig, func(content []byte) {
+		configs, err := parseRemoteMonitors(content)
+		if err != nil {
+			logger.Errorf("could not parse remote monitors, keeping the current ones: %v", err)
+			return
+		}
+		logger.Infof("loading %d remote monitors", len(configs))
+		if err := runners.Reload(configs); err != nil {
+			logger.Errorf("error loading remote monitors: %v", err)
+		}
+	})
+	if err != nil {
+		runners.Stop()
+		return nil, err
+	}
+
+	return func() {
+		poller.Stop()
+		runners.Stop()
+	}, nil
+}
+
+// parseRemoteMonitors parses a YAML or JSON list of monitor configs.
+func parseRemoteMonitors(content []byte) ([]*reload.ConfigWithMeta, error) {
+	cfg, err := conf.NewConfigWithYAML(content, "remote monitors")
+	if err != nil {
+		return nil, err
+	}
+	if !cfg.IsArray() && len(cfg.GetFields()) > 0 {
+		return nil, errors.New("expected a list of monitors")
+	}
+
+	var monitors []*conf.C
+	if err := cfg.Unpack(&monitors); err != nil {
+		return nil, err
+	}
+
+	configs := make([]*reload.ConfigWithMeta, 0, len(monitors))
+	for _, monitor := range monitors {
+		configs = append(configs, &reload.ConfigWithMeta{Config: monitor})
+	}
+	return configs, nil
+}
@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package beater
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRemoteMonitors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		ids     []string
+		err     bool
+	}{
+		{
+			"yaml",
+			"- type: tcp\n  id: db\n  hosts: ['db:5432']\n- type: http\n  id: web\n  urls: ['http://web']\n",
+			[]string{"db", "web"},
+			false,
+		},
+		{
+			"json",
+			`[{"type": "tcp", "id": "db", "hosts": ["db:5432"]}]`,
+			[]string{"db"},
+			false,
+		},
+		{"empty list", "[]", []string{}, false},
+		{"empty", "", []string{}, false},
+		{"not a list", "type: tcp\nid: db\n", nil, true},
+		{"invalid", "- type: [tcp", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configs, err := parseRemoteMonitors([]byte(tt.content))
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			ids := []string{}
+			for _, c := range configs {
+				id, err := c.Config.String("id", -1)
+				require.NoError(t, err)
+				ids = append(ids, id)
+			}
+			require.Equal(t, tt.ids, ids)
+		})
+	}
+}
@@ -44,11 +44,15 @@ type Config struct {
 	PublishTimeout time.Duration        `config:"publish_timeout"`
 	Monitors       []*conf.C            `config:"monitors"`
 	ConfigMonitors *conf.C              `config:"config.monitors"`
+	RemoteMonitors *conf.C              `config:"config.remote_monitors"`
 	Scheduler      Scheduler            `config:"scheduler"`
 	Autodiscover   *autodiscover.Config `config:"autodiscover"`
 	Jobs           map[string]*JobLimit `config:"jobs"`
 	RunFrom        *LocationWithID      `config:"run_from"`
 	SocketTrace    *SocketTrace         `config:"socket_trace"`
+	Notifications  *conf.C              `config:"notifications"`
+	Prometheus     *conf.C              `config:"prometheus"`
+	StateStore     StateStore           `config:"state_store"`
 }
 
 type JobLimit struct {
@@ -61,6 +65,15 @@ type Scheduler struct {
 	Location string `config:"location"`
 }
 
+// StateStore defines the syntax of the heartbeat.state_store block, the local
+// store of the monitor states.
+type StateStore struct {
+	Enabled     bool          `config:"enabled"`
+	Path        string        `config:"path"`
+	Permissions os.FileMode   `config:"file_permissions"`
+	TTL         time.Duration `config:"ttl" validate:"min=0"`
+}
+
 // DefaultConfig is the canonical instantiation of Config.
 func DefaultConfig() *Config {
 	limits := map[string]*JobLimit{
@@ -70,7 +83,7 @@ func DefaultConfig() *Config {
 	// Read the env key SYNTHETICS_LIMIT_{TYPE} for each type of monitor to set scaling limits
 	// hard coded list of types to avoid cycles in current plugin system.
 	// TODO: refactor plugin system to DRY this up
-	for _, t := range []string{"http", "tcp", "icmp", "browser"} {
+	for _, t := range []string{"http", "http_journey", "tcp", "udp", "icmp", "dns", "grpc", "tls", "browser"} {
 		envKey := fmt.Sprintf("SYNTHETICS_LIMIT_%s", strings.ToUpper(t))
 		if limitStr := os.Getenv(envKey); limitStr != "" {
 			tLimitVal, err := strconv.ParseInt(limitStr, 10, 64)
@@ -85,6 +98,11 @@ func DefaultConfig() *Config {
 
 	return &Config{
 		Jobs: limits,
+		StateStore: StateStore{
+			Path:        "monitor_states",
+			Permissions: 0o600,
+			TTL:         6 * time.Hour,
+		},
 	}
 }
 
@@ -35,10 +35,14 @@ import (
 	"testing"
 	"time"
 
+	conf "github.com/elastic/elastic-agent-libs/config"
 	"github.com/elastic/elastic-agent-libs/mapstr"
 
 	"github.com/elastic/beats/v7/heartbeat/ecserr"
 	"github.com/elastic/beats/v7/heartbeat/monitors/active/dialchain/tlsmeta"
+	"github.com/elastic/beats/v7/heartbeat/monitors/plugin"
+	"github.com/elastic/beats/v7/heartbeat/monitors/stdfields"
+	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers"
 	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers/summarizer/summarizertesthelper"
 	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers/wraputil"
 
@@ -50,9 +54,32 @@ import (
 	"github.com/elastic/go-lookslike/isdef"
 	"github.com/elastic/go-lookslike/validator"
 
+	"github.com/elastic/beats/v7/heartbeat/scheduler/schedule"
+	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/common/x509util"
 )
 
+// RunMonitorCheck creates a monitor of type typ checking a single endpoint
+// from configMap, runs its job once and returns the resulting event.
+func RunMonitorCheck(t *testing.T, create plugin.PluginMake, typ string, configMap mapstr.M) *beat.Event {
+	t.Helper()
+
+	config, err := conf.NewConfigFrom(configMap)
+	require.NoError(t, err)
+
+	p, err := create(typ, config)
+	require.NoError(t, err)
+	require.Equal(t, 1, p.Endpoints)
+
+	sched := schedule.MustParse("@every 1s")
+	job := wrappers.WrapCommon(p.Jobs, stdfields.StdMonitorFields{ID: "test", Type: typ, Schedule: sched, Timeout: 1}, nil)[0]
+
+	event := &beat.Event{}
+	_, err = job(event)
+	require.NoError(t, err)
+	return event
+}
+
 // HelloWorldBody is the body of the HelloWorldHandler.
 const HelloWorldBody = "hello, world!"
 
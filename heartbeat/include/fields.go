@@ -32,5 +32,5 @@ func init() {
 // AssetFieldsYml returns asset data.
 // This is the base64 encoded zlib format compressed contents of fields.yml.
 func AssetFieldsYml() string {
-	return "eJzsvft7GzeyKPh7/gqsZr+VlEO2SL0sa+/sXkWSE33HD40lT+Yknk8Eu0ESoybQAdCSmbPnf98PVQAa/ZBMyaJjZ3xvjociu4GqQqFQVajHX8jPR29fn73+8f8gJ5IIaQjLuCFmxjWZ8JyRjCuWmnzRI9yQW6rJlAmmqGEZGS+ImTFyenxBCiX/xVLT++4vZEw1y4gU8P0NU5pLQQ6SQTLoZ+wm+e4v5DxnVDNywzU3ZGZMoQ+3tqbczMpxksr5FsupNjzdYqkmRhJdTqdMG5LOqJgy+MoOPeEsz3Ty3Xd9cs0Wh4Sl+jtCDDc5O7QPfEdIxnSqeGG4FPAVeeHeIe7tw+8I6RNB5+yQrP9vw+dMGzov1r8jhJCc3bD8kKRSMfhbsd9Krlh2SIwq8SuzKNghyajBP2vzrZ9Qw7bsmOR2xgSQit0wYYhUfMqFJWHyHbxHyKWlN9fwUBbeYx+Moqkl9UTJeTVCz07MU5rnC6JYoZhmwnAxhYnciNV0nYumZalSFuY/m0Qv4G9kRjUR0kObk0CeHrLHDc1LBkAHYApZlLmdxg3rJptwpQ283wBLsZTxmwqqghcs56KC662jOa4XmUhFaJ7jCDrBdWIf6Lywi76+PRju9wd7/e2dy8HB4WDvcGc3Odjb+WXdrc6Elrm5gqHCIvrlz+mY5bpz4XGV5dhyOHyBH6/w+2u2uJUq62CA41IbObcPbCGtCsqVDrgdU0HGjJR2uxhJaJaROTOUcDGRak7tIPZ7hyu5mMkyz2CLplIYygURTNslRXCAre3/O8pzXBtNqGJEG2kJSLWHNABw6gk3ymR6zdSIUJGR0fWBHjlytCj832u0KHKeAnRrh2RtImV/TNVaj6wxcWO/KZTMyhR+/59lCD9nWtMpu4fyc2rS2ZUU+eLKsA+mg9IvpCK5nDpaASu5YR3jOIrhT/ZJ93OPyMLwOf89sKxlsRvObu124oJQeNp+wVQgnJ1OG1WmprSkzeVUk1tuZrI0hIpqx9Rg6BFpZkw5yUNSXP1UipQaJqJNY6QFYk4omZVzKvqK0YyOc0Z0OZ9TtSAy2qzxDp6XueFFHnDXhH3g2kqLGVtUE87HXLCMcGEkkSI83Vzrn1ieS/KzVHm2xCoaOr1v88SbhE+FVOyKjuUNOyTDwfZue0Vfcm0snu49HXaJoVPCaDrz2NfZ89eY+5Alt9f+uQwX0ikTyFnuBDkKX0yVLItDst3Bd5czhm+GVXU708lxSujYMgVK3Im5tRvSympjz9OJWzoqFnaNqN3YeW63co9kzOAHqYgca6Zu7HIie0vLljNpV1YqYug102TOqC4Vm9sH3LDhseaG14SLNC8zRn5g1IoWwFWTOV0QmmtJVCns225epRM4PAHR5HuHqhtSz6w8HrNK9MNOsPBTnmvPq0gkVQph95VEAlnYIvyUG/J2xlR8UMxoUTDLsRZZ2NkBVThELAGE496JlEZIY3nBI3tIznC61CodcoJIwz63G7dXwZdYViBO8RkzapJovx+dvwIVyB3SdYTcitOi2LKo8JQlpOKNWKBnknnSgSQHnYbwCXIL18Qe5cTMlCynM/JbyUo7vl5ow+aa5Pyakf+kk2vaI29ZxpE/CiVTpjUXU78o7nFdpjMr+F/KqTZUzwjiQS6A3I5kuEGBye/ZJ7HGVO2accnzLPHyzs3elABdMuBOKdDcYacfDBOZ1RDsVDVSThw/4Np5Hne6FIp9q1QJN4CRYXdSsegYD3YgxYVAFSgMaXdGoeQNz1jP6kS6YCmf8JTg26B7cR00REfZSDLNmVE8tTwVVOJnyX4yIBt0nu3vbvZIzsfwM3796z7d3mEHk4PJzmCyNxgMx3Rnd5ftsr3d7CB7no4PttPxcPAsDSBafAzZHmwP+oPt/mCPbO8cDgeHwwH5j8FgMCDvLo//GShcW+EJzTWrLSsrZmzOFM2veFZfVOaW4wkW1s9BeGYl4oQzhdKCa7dvNvgEDig4xfRmc4m5VYbUHBRPbxvQVEltF0Ibqqz4HJeGjJBDeDaC7Wc3XnuFDuiuJfSkRogm+k/D0+8E/81qzg/HO2hsViKhHIP3bkE1HDMCUot3MKBDL6uhZ/9dBYJO8QVxGh8ArRXUhOJTePqhhjLlNww0Xyrca/i0+3nG8mJS5lZmWgngMAwDm1tJXjj5TbjQhorUacKN40fbieEMskzitC1SaVusoAokQxibayIYy9C8vZ3xdNaeKgjyVM7tZNZyi/A+m1j54Q8aQBVPIP+VnBgmSM4mhrB5YRbtpZxIWVtFu1CrWMXLRXHP8vnDzU5AaH5LF5poY/8NtLXWhJ551sRldYYevmuVuqQijQhHdKBq9SyyuJtozKpHQGPhk9rCVyvWZIDa4s9pOrPWZpvE8Tiezk5wr4DUf3dHQp3YDZj2wYWi0u1Ya9U1lbU0Usi5LDW5AA3gI+rrkSC0egWVBrJxdLGJG9Mpow6wVArBwBdxJgxTghlyrqSRqfTn/sbZ+SZRsoTTsFBswj8wTUqRMTyn7emrZG4Hs9JNKjKXihHBzK1U10QWTFEjldVvvfuAzWg+sS9QYtWbnBGazbng2tideeN1aTtWJueoeFNDnEcEkZjPpeiRNGdU5YvqBAQbKEArc54uwL6YMVAZLILJJ+tHopyPg1573xGay6C81ZbIHRU4DqF5LlPQsR2kreVzamf4OmwEt7puoI2ji9ebpITB80V1Emm0rcKS4F45q9EjYsnh3nD/eQ1hqaZU8N9BbCbt4+VT1Aewbq9iKkci0LsFyL1Og47lq5SfBuXfRJjALC3sf5TScuTLl8fRjkxz3jAkj6tv7rEkj9ybdut57qTasSM33O4M3Ah+cdyGdJqwBw4tRMWmVGVgOVjDQArdi55Hq2HM0bXLpaA5meTyliiWWmO75ue4PD53o+I5VYHZgs1+YR+PIIPtqJkI9qJ95uK/XpOCptfMbOjNBGZB10jhBEprKnRfWkWvNqk3dBVo3kxbOJwp5qlkFBWaAjAJuZBzFoyjUqORaZiakzXvk5VqrXLDKDbxssuBIhoIatxw7mfnBMCVHbNgBIMTICKA24wWLDH1y1xNEcOPbg7HRH4Ce5aVurQEcaNW1jcXFrx/lQIXAIxxNK+9x7xjsIq+QprWkFbNwvXqwz72LsngyMTxtvw8wSUNmwcVN5plRLM5FYancBKwD8bpeOwDau89VKm8HNBB0zOS3HCLLv+dVZ4ViyhTYM9pbkrqluNsQhayVGGOCc1zz3z+fLAydCrVomcf9SqKNjzPCRO6VE4fdX5wq8ZkTBvLHpaklmATnudBjNGiULJQnBqWL57AqqZZppjWq7K8YBega8XxnJvQaUlB/MzHfFrKUucL5HJ4JwjSW0suLecM7gVIzjU4P8/Oe9aIxtNYKkLtMfOBaGn5JyHkvyqKB62x0qFwfyh662Hy+2GUuC9GSLK6LioIN5GqmZXoo8aDcpTwYmRBGSUI1qhHMlYwkTljADV5KSogwM/jVrLStZJ/u+Oc6uTf9kSPvFwLw/RH1P5oxdEnVH+tBsgP9gd09IV7PbcTHSOgIG0v0MFuDTBk55XYflbK4h6OrXgHpWPOhjWPd1xzurBbED3P8LKVB5PSHi6/WRk+4SyLxwZlhArUAOxLYVRB0YIGeuJWqObImLIGQCBguHTxd6wARZa5y9MwKBOKp7O5PVW7LOvE/ZGk7h1P6ymTScrN4mpFTpNja8d0cuUrazcx516tgSOF4YIJc5XKbBUwXd7Kfs6MYfY4zVj9rjnMvq674X599N1HNmg3Misi8OuYj/1kbaClMjNyNGeKp7QDyFIYtbjiWq6K5sc4BTm7eANEb0F4fHQnWKtiTQdS5yofU0GzNqXgZPu4t2TK5FUheVAr6peAUky5KTNUwXJq4I8WBOv/TdZyuI3uP9tJ9oe7BzuDHlnLqVk7JLt7yd5g7/nwgPzPegvIpz3OGk5ezVTfq1LRT2jEefL0iHNyoWItJ2SqqChzqrhZxDrRgqRWNwNLIhK8x17lCS5E5HCuUElOmT3snT01yaVUTmfogctsxitrpVIuELycFLOF5vaDv7FMvYzSEQivpYkiQOCelqNjaQ66zZRJj21b4o6lNlL0s7S1NoXUhuar2mXr5zA8ijWqtUx5dXeJMQIO5ArRv7uYikrbd1dQ4bopXKCOGbkW8lZY244SiwpMJBX55eycRDgRYG1QpW+oWpBbnlkNDk41t6vx4go+tun3fHewO3iImFVsyqVYpQB7CzPcJ7/6fzu+C64VSTAHU6cA+1vJxqzNf9aq+b2yCZ70WJ0xDIb6HfygkxrD9cKt7dnR66PouU7g3UG1daSmcCzTrR9KJqS+OuIqUj4/whi8+AiW4YEaHmfnwUqr64cbZ+c3u5bbz85v9jeT2lxzmq5iP786Ou4GpnFpIaQJt8dz6hTwty+OybPB7jbcv2O0IcsOyak1nmRqmCEb4BDgukcO+mNeqahWx9/Eq1+nGrlgtltJfi2LgqmUavZPMmMfaMZSPqc5yfiUG7j7sWqU8VptGNOBjxNbASJIKTSfuqAdNmUqIRdlCnf+N+5BF+uFd1YIAw0jzhbFjHVI38GgPxj0907h353+9k5tpQQ1SZMzOs/Hbu5Yv1RUaPQgnZ1brJw/BQNEXx9dBuck2WDJNHF+dyuVK5cpQU+cd8nXLoHDoRP544hRFC5qxJTkkmZkTHMqUjgDJ1yxW5rn6P9UsrRHY8PKt0gXUpmHGfne5NNG8W7LP6aGHf9roQf6/R5g/dawPse3H2XrbtfhaK3JMib43etx7tYgFhTxfPY80oYpll11WdlPpydaoTTj0xnTJprU0wjn7gEiRcEyD7Iux/hTtP4vqttw1Pei4Zy9bfWVtYaVu2bF11r8Rbdh767fM2aYmoNWWyiWcm31FVCbKPoAIUYJgnnLcc5TosvJhH8II8IzGzNjisOtLXwEn0ikmm4m5FItQCxKVLQ+cKtFopI1XhDN50W+IIZeV+uKPsOcagNiFyNXUacS0hBwfd2yPAfsL1+eVHFRa6lMyuu1tmC8ywkQyL5KbgiTANMHk+EeF4qP54tU+Dz3rAL6OmEfUlaYKuwOXqvuZlvsnsB9PCUFVYZHFw2kBQEID45z2f9zv6M2U9k1YICUdk3szCkV1U0DqfNVL6JAiNttITRmubztZvPuPVHfNzFt125vbxNGtUnmCzcCMgbuDKrNWhSlgEC4UWZUV2G3gCuoH2GaSptb0+V4O9HleFjbfL0aE1fgoUHhXNo+bq0aY62He05IK+B5DpfYTHHZEfpjEVhWEzSyuAI0PoPUY5OJPaRumJ3VMYrDfoNdvjzZ7KExFSypiu6BaCg6ev46EoSAZVnPK9EmSdoCsjlvGDYKLLKrBHzwdUtGkIp3CcVqJZYTj/B9jW9KzVSyWpaJ/Xd4cy0V3gfbyTFkZc7gPkRO7joWqSAvT47OIRAWMT4JQ8W8st7Gjs0pz1eE3DuLAUzgjZikDYCVnh0G8ld0A2PRXNfVMQBOKHpDeU7HeYdxm4+ZMuSUC22YY6waReB69Q9jO5h99XyHSK4sELcdjOrjqhE/Hy8HVz5bRU6NVa472BPhXKFLNV4JnKwNxIzq2ao4wVEKpI2dBx1zSjFr1bUi06kTS4JQIcUiTjFC+yRilXeauYjWEWDBM7yvhj8sdqOgAqRSTHCtaF6bk4qsQ6uCCMsOplpJYPMdcc1IstbuvugP+3v97WF/e7C9u737fLj97OBZf3v/+fbu9vPdwW5/e2dv+Hxv/9nBfn84GAzaSDyds/Azy8GLmbU+0V0PWShc3EsqmrA7ZaCSefNy+slY/kgpCulmwMowk7+vAL9kPRGtAfT6r2vXfEwFvYKYzbUeWVMMtG4xvbID+sSsO+lWxdTJEgEPIXX+i7sj6jDVl+DuDBEWMBQYLGKiaMjhq9BAPxrGbntnAkRwkzuziybkVZXdwXUcZk4FOT3eRovLbtAJM+mMabibiUYn3GiX0FUBaTd3PW+xllDGdQhfroPgxlWlcJliis2lCcHORJZG84xFMzUhQ5gocalMHiHPOqJ61d0r1VMscdBqIMjZcpN7h48dlusKVEewKB/aA+ei1FxYgWb5pO/SXtF6hadcClLyPYpB+MpQNWUm+Z4QI2vMPfbBApg9Z5/yMK2v64j6XrR6jF1EmZxYItRYRCpL1qm0WLhQRN0jiukC9ep8kZCf5C27YSoimWZGkw4E3KANNOalNdulcVmjE7hpC/dVSkrjQA+DE+e0hlPACwNZUaHigAg1iENKTUnzsFCO0pimh7didoE8A/vZGojYFbMiMuQ4OzLGk3kyBqJV9PSpvNInXsVRHgZDW8OatRcNw0U8bHdQdAkIW8tawXYHRdsc1QHdEwQJpnApuDrFcL3ag24uYPM4iIpnIS/XHfoLkvHJhKnYXQ23xxyyTq2qbI/avmGCCkOYuOFKinn9nqaSrUc/X4TJedbzAVog/8mbtz+SswwzZCF4qGzqH23LdX9//9mzZwcHB8+fP+8k5ypDAtoE9SoAzTnV99Ay0DDQ6NNoicZXi5oZ10VOF7EpEvuRsCxHP2M3y7qTnG3Hc24WV+3b1KdTVKJ58LaU+7BOOCnxbFUMb1yAZapTiLgozJYGU+o+o9r0h/XbYZ9TtLqtd+Zzyc5OvEgGFcIf+E1AeX+4vbNrVeXnAzpOMzYZdEO8Qu4OMMfxgm2oo2tg+LKdvPZkEL3yOkeUx3YvGc12MmcZL+s+f3egfZO3TyJvlxAaDYJ/k8hPKZE9cf9Mgnl5tL8e0f0InP544b480F+++F8eF1f77LOcDG6uWOZ2SZaaHDkP7/TI0e+lYtE3HZUqFn03ySPJ8HnktScERsUtSwKUsnUidIvW+YI8mgzWWl0mS+iTo9g9JWDCxCMfF/+it7pHqMW3R6ZpUd02S4VxaDSXKaOi7XK8XTp60CGOEZwrQtsFcD7p4fFA/Hxhn8/D3x4RXxYiLmOTcW24mJZcz/xzuuGkg+pPlbLir22wTBloKp5teoRNQRM5Pd4mN5q8pPNxRnvkx+Nz8uPxKbmpNJyjoiCnYspF2EN/f2Vfsd+7kkJdO5EWBWHuNfvZgdxzmKpS9MiEqik1rEdymL69H/H7ZZfs310k/7vL4j+ZEI6DEr8+ERuC574J0K9GgDof+Tenx+dyejQI/s3p8ZROD0/cfzOnh0P7T+X0aOL0VTg9HNB/CqeHw+XfXcNukOHfVdGuyPBn0reXR/zr1MiXx++bzv6l6+whSE5m7ErzqaCm9KXXXbSczBi5qP1yd9jc5Yxp1qxmXoszhfizMRdULTB9PkyqP71gYsanTJsrmk+l4mY2XyXPzaieQf01P1nQfC1GmKiBlbXvTvuocWWgAzb8oNhAhWvikndDohBUzApD+o4clunhSQUFaV3mSMXPSJsK3Da/6Bnd3ttfdotjeeE6hVsBtGMpc0ZFFxF/wJ8gDJoWEEbJsVKno4NF3WVFt6NDLRt8JP4zch3wqd3nKyxHbRkiClxelhN4h7nkKsH7LhlkTkU5oa5XxHhhKeRbAdwwkUmVRGOyqnK5Yjm7oZgoe1RYvvn+zQUErHVl5MwTOydLPhSpPY4/LJamraGmXFmxuaMs467EZFuKwHnOlMF0QeZA6abxpMx9zf4plB9Si8LIqaLFjKeEKSWVrsIh41FvaM6zuJyKVFYIaePnIy8ZvWGkFFEVxYlPzIdXq1e8FlKNH4a9tbazSGcsve4qAX/69u2bt1fvXl++fXdxeXpy9fbNm8ul16jEjjMrKo9xgcPXS3150R60uqogFU+VtDxMjqUqZK1I9scVC0bnK97Hdoqn3MwwnlRut7pyxH4Lu4YjUbxp5Rx52B4+/dtP//jl4NXB0d+XpqXvyLQENbOKVWsUO7FbhIqM1DtV1U/2Rg8pKOwNZ1pbrm8Ptof9gf3vcrh9OBwc7gx+WVrOwx5jyzDHPefS+oWR9hCGpYv2ecfeJemsni/8d7vhMby4ev2u93xQeirnvt5kD0k549XxXsvk9eHGlaSxp7+UuXbtJ1y4OAExgnoBCqkWuzzsBAVJ9ol07T7wMTEOrKr60X/DFOaJ0ynlIqrrZ98ICqRV8WNPYacspjXif0TQLkOYSmsGDdfJuKAwx1/eU7Q5PFgvzOtK5raaeUW9gFz/EAdkgCJE7JvQog3D5KvI8e+8wIr09BnLiygVDVIvsKpIGFm7pA6xsLaH3etPEIOeFmVShuZd9zOWTmnOsqtJLmlnsbf1c6ZSq+Yen79DGqLRy7Xr8sF/r/rEubqncgJP2zMwKn0gMsINUdgQBLAeWJYdJuQipZApb7UxqewpMhgE/tH441X847K7K+P6OlGMZklHrdAHVYiF80vavVThCGOSjSktp2wTGlQQjeV/sCbEBp1OFZtGLcRcWhHNcwBNbxLNRcqqdHDsRxOV+F/alwmo3ipu2GfA1c5jmPgD0V1lomS17XlWj47mczpdqdMl9qjBZCHDCQGyIhY7Cnla1UEzdLoiyCqZ6uCi00YyfNSp8f7po46N9/RsbHr9YVbX/rA275zNpVo8ncB7BeMRGI8UKP3sx+UFWGD/JxNkK2S5amFFqGQXpsUK1QmbQu2DpxAsd4kUKEVlz2F7IOd5KI4NFbUmNG07Zqpd8WRSxePLxeoQDr1VPeZ/JMJO51gRa73F0cmcCjpF3Z3rCo2WkYLtTiM10GpMV9ooRuexInhiFamL6uuPdIKMRvGamaHXDAvScIGF9b1pIditazVXjR9KXut0xqIrnjPR9Ur94aqQYKhWET0aHLrQ/NMTXDbLxvrMz/hVlxQ5kXkuoSvqnArB1CEZ/XeEMFxq/k+/9pX9rJlpfAvlmwqasv8ZVcoshw6WLs856pAK9lKofTCj0A5ZeWNJOQ8NodpX/anoyMDgizDRCXklVaMrh2MVrOAzkaVwWaBch87UUB0Kgw6SVG6NczndoqLPhQm9RvtG9s2M9UNsAjW0j7P2cZX6uEq/2rcdjIXU5p9hjY8EOcW3NaMqndXWIJVCc0g+rfdOGtP0GvtPZjxlGq3PcGFQZxWoVjvXtfJIjfddbV9yUjJkDtxFN0xAZdL2uBqzkqFMEzKIHYp98KypmLYSw2BrjlpFlE7eZ9rVsAgdSkfvRz0y2rL/fG//+X/tP2v2n/9l//l/7D//n/2HjMgGsFXFJpse4lFvBBdlo7+MEt99XDPcMnWiQ8cXZoUe1PConJd3MMO05BnbYsL3LMdhtsIwW2mpFBNmy1G4nypGDesDlZKZmed/afxCC94vqJn1C6roXP8ak/CfT2CzuU25hCS2TGeoMFf3aEtrlcfa7qGowaaZoaSjhsyhQ61mQjPvhnOutffh6HkfmbteeCXvRatj7UhMufiQULAH7LoXSs6ZmbES/mIig3Lio3hkZlJkvhrnAmgQ03XLwZQ22C0Svs+wF/6M3jBPMaKZiUe9ZaGFEIrd92vgIePp+7VQQ8e/C08kZISlMty3I+cVikeFGcN1EA5MNRl1yNVR8l78wBYSHE4NRo6H7DgyUmu/KU4tkiwjcLxiYYJRgA3nnlEdbYN42JgxD98LQr4nr3yJAs8Ho/4If3ktQXdBD4ewKmkkzdea53O8xg/RXGH/PxVnH2HVE1/CPYyfgPEEH4PDx3UpoiABYV9yMY2J5U6i5L14RQVUSVea0Nza8gsf7shc4XYvjLGzJ114nyJyU23LdOkBQt76e243xphpQwpLbJ4yLEnuyJkQC048JEIGdeK8By6ucA4XLCP39ihx7SyRVZz7HLokQ9eleFx70kAznvDu3cxbP0PqvBqP6dh2FJYmZlrQZir5fQ+3xkN+IuNW3ceXZdslY8GW4dj1I0HkDVOWhCB7FwWrCSLHL3H7ADyd8gWyLsviMJq1XE71GjDfGmr+ei0hPzPCPhQsxe5d9uCnWUbWjLL7Ya3mhVvTC2FmzK7rWtXXjCoyKU2pOuKP7ITL+W2jflw1hb3x9T0Ke/RopXCiU7upIDJvS9U78AXosX3ZFpZKTuquWbgiqjqpYWGdWnO3nquxbDUU76uASGPXogs50i5o1FOddja48wvbgVvUMu5jzeLwHHbN4ip3813t0KIJQE3NoBIrapea5VzUGsFizy836tg3UgMfv6hjrO+a0JOhTky/XeJ+f2dRfSrk1TC2vx50d/T1+vUAb9ySHkpfwQEpup/zVAhYYhOIQOmvqitcrdndUq3hwrbHAZ6qNVwYFlrE4U781hruW2u4f6/WcPF29DXzQTJ+ef3hYlC/NYl7erp/axL3rUnctyZx35rEfWsS961J3Lcmcd+axH2VTeJiJfHL6BQXQfStXdwX0C6OF+Awj/jkIz3SWK05WqH4jRW8J69+2exqj1ZVTv6iOsRBS7Io8NNhCuGgFW2MtItlKXHCIDXv6TFcRc+3Bxixn6/xW23fky+o+1vN3fmtBdy3FnDfWsB9awH3rQXctxZw31rAfWsB91XftHxrAfetBdy3FnDfWsB9awH3rQXcA1rAZTmeuz7O6+VL+PP+hIxlCtmAyz3nY0UVZ5pkC0Hn6ETxBJU0Q0+a9HUD4GbD/QzhnLJgyvWkAhmpMY7cSoc1PaPQz702zxoqhVVtFzBovCEw9mkJzgJgBsfTLsY02FI+JePQQ/M9OUEE+jkX126+BdkYJVmejzZJKudzSKkAB5EU5GcuMnmrq/cvENw3WBBiY5Ro2fXeO8E/9EGZbeHegqUGxiLn464B5zR9c/EEGcm1KkjJt3JCn6+cUIP0X1F1oQbk34oNra7YUJPU32oPffG1h5pL9ucpRdTA7FtloqerTNQk7Z+tUFETv291i1ZUt6hB6G9ljO6gk9U+k3m2tyLp9epkD6d4EDx6RocrAujip6Ph4yCqVNoVwLS9t/84qPbctfdKoNobbj8GKp0xtozEfhRUFyenp+cPg2pFKkfNv+ts1eYBjEdKni/InBa6q3ICGGdQf1hftzfzNVOC5TvbiXdkLIFuQc2qHJkvyjxHiO0kLdwbwB8fvnd+gvcXYOPvbL9/FEIsgdxEw9JQiXgFdWbO35F4Gt+Q2/u0LdotFD/s7z4AC3twUrFYEQKYhANxpzBNi816Pr83I9TAUzxnfajp9qT6ccGSCLBVY9sIf34Esuc0jhH/OHJ2+KsbpvRnwM5N80jM9pOd5Pn+YJAMn+0O9x6AIp8Xq7wPOcJbkFBIrJDKuBY856e408iRIA4K0u9DoAg8RiK4iP3FXaF7O2fCxZSpQnHhqo1DztoNE4RODFNEMaSYy9/07XmsvtgHPCs9TVGhg/mvscSCTKEyR9ZzKX63GGUBmbxYW8UoWlX/sNBjanRdx1MCH6amViFkwhVjCxAUWC/GzBSjpq+YKxCyPRjubg2GW0ZhBZb+nObWaOsjcfrOmQgVQjoCMdP9g8FOusueb28P7YcspXvP93cozXb2s2zyAAbxGVFXsBlWeHUXdsKnSLOL86Oz15fJ6T9OH4Cis4NXjZeb5lPwWwvi+v2Ho1PvnIfPb4KbHY/gtfsJEO5NBBp0/t7k9QX8ec+9yQu8MXEJH3bCk9cX5LeSwQaE+kJC3zJVbQT7O9z/hPRnxmEvhiBncNuKac7CWAtSKC7hhmTKDODlhnWDbowyoaGo1CE8P9okeH4v/CTx6BBO4BPx8R7U3fiYkJyM04bcfo2xL7QWV+ZgQJv2lqETBdcuZHHAOG0o8dXR5lNketcosXSFw1YxCAp3d1EBASrcGxjyQ9OZm4torOdGFDOlEtE1tb9NaHa6uJwxAjEL12zh6FUlWfuFQfpr5mat55CPF+T0+KJyR79lqVSZGwtkNEjW2HM7r9DBH/3kgtzat06PL9zwzdwju8aW97AMBgQeQ0g9w6KhtYIP9jnP4+TIkDkXfF7Oe+7LMK5HCkpgRfyGNXRGFjgoQdBCg+sq4qVnDYowJIQSpnCgcvDMWYyoJoXUmo8xiiSDghtWL4zKm/hyczJi4xagVJO01Eb6cnDNLHaHc5rTlZUZwF4vFFMvwoL4Sn1V7TXf3waOedX23p297gTdjrYqXcdX+ItFI8ae+kD2+uZgFPac9Bl0+GrBRKZ9RA1UaAFp5UkSD+hxbx3/w0Hi/+ukwiozFpuJ30bGzYkaoJOCKYjdjWhzBm4wcEPKCTl+ffTqlECNIlcvTuY3ViuLhNP6usYaP6NIxJio6IQUDKUGhOLoQloSh+uYaBDYlwk5C7JKSOOjJptj+kzx0W8l06HCwcgeOyyq6BEtC4QQ3xE17pfGmGXiB+8tmMwh2NswdQP3WlZ0A8JAgc5V8O5ems5iyc4mIJhq1TG4TqnKWJaQX5iSvhrQHNylMxf3gTK0IuC4ohpO0VGXoJtRV9gI73JWNcF7pIwB3qzBPWM0Y+pqktPp6i4tfcDNNnFZ9VZM4swEZq71mypYamplmw7J0VGPXB73yNuTHnl71CNHJz1yfNIjJ286nMy/rr09WeuRtbdHPhbnrsrXT7o0FidMM4qvw6h2oQ1O6yiUnCo6R9YLtzqVYQepBkxhDZp4IKhbWfCqfAqKBd1hWW8Ph/U2xbLoSHp9cuRd2IwUeIGFChR2BXBXQNdcQK4P6q01VZaQOdOaTlkSB5BwDaFCjnZOgBl/LYjDoGoMlIGIpnjMO2n0t3enb/+rRqMgEz+brqCcdojnBJojH1ULaqJ7lSciHIUN0OITLziLXalMn9IipOiDi8OqgnF92w3MbdnZhronFgIy3N7fjFNFpK69UQnxOLeUasJ0Sgu7p6hmZDjwOaGabLw/OTnZrBTwH2h6TXRO9cwZer+VEqrRhJHdUAm5pGPdIylVitMpc1aDKz+b86ha0oSxLB4Bqskql8f43vTIe4VvvRfAf8zdIz7sdA3r/Ifn7X3L1fuScvUCX3zmpD1ecyo4DO/LtGsJi68ot+z29rab6N8SyVAEfkske1giWcVAn8c8cFbS/ZrF0dFRvaSSN1WvPqXmwVHLQ5fn5OzcKnIMGv+OYs/GqOFi8D+OvKfP8Q6fTHha5uBAKjXrkTFLaamDV/qGKs7MwptGMafOqdHWJIyKeSfk9IOB4sEBvqgqpAfUzJhiWOBX6CQizqjSWaEMODfBmwXhbFDq18zYHKqZREOjXoAvwe+Mag5B9WHEG65LaAzl1BWr4U6k6jRzIqeJtXeqP4dNw8frwZ/DDPBzdVfBef0GAjdr0K1wU6zHuyJ49X2QVNZzFIZKfJbx6sfWQpYqKuIe3QpA8NiU3zBtH4rvE3rwRRxjhlXww7iZ0GGUCcLWvBhYFooKAO/ld3cANSAa80vhi6IWTDn8N2SBXtd8YYfQUoYTxdlquC02E3IkMkKdhyaM2arrazfV3bcT3o9vrTgnDFr8HRy+obdvWrv3OT3+2L3PK2ZoP3ZS+xZ1zgv96a2dOy/aowAexX4ruWLxMJ/EzKfHF+HWHQ62QHfsg2FkQkYs1Yl7aIR5nB6MSiqCqgSyqNQGuybDFXfuykjGDpmfZ0zgWsLCpkrqSIPzld37fec0dRcaFiAIA875dGbyRZWlUXl6Kmzg/Sg/KGcGW6VPlbvhptm/LKi+zko6Y3PaoD+pZW51sNQwGSSDmKPySY2jXr4gP4FT6iOM1ZmH9ZKL8gM5/cDSEk3fl1xcw4cXWGdp4/Tli03ooAhl8z+Z+T5D3NErms6g2HUce+SIbKnVHXd0sN9fPvRovDDsSqpsqULDj8Hhh4VhRLPfSmiBIid3A/6SG5MzcioyTpcPuC/KqxWeX8fn78LxdS/Vz4RhS0etwYnApbiKAtMfE7/utChobMlEpQSFEkkW1HVdMT05s+KCGpcAFjYuN3F7PuVDCjK42LCKm68uOKHX6Et1wSWIilR66YhL9gEiepbAepJTY1h1c1yv0ckxGh2HYxlhOZuHtEcMPV8UbHm40B2e0DFfcfzW3+thW5ajjqJsqx8w/PvMt1IjG0c/nG0+FI1VOlFRRtcvGJv7Ylk4V3i7Cp3W8CiIgHTzPhBMJoxaxPVin6xEmyNmNcGnUtQ1pVwtD6+vDT4MsSVRuNX0AFcH/9Ig6ys65isC9eN7y1McNYg3Fw+l+AqPH8cd951Ay0L52YXaA3eai/R8qnMBh3uCc8GFMS0DmGBRxtajQqd8zFRrrcNJbe3pT4mP0uW4j9psGBK8yIJRMyMjlk8Sj3Hy/Wj5rRxeSmd8mbSTDiFZ63dR18JmvK9/K10G4piOec7NAlLbFR+XMcn0A7uIBritBJbFMgH4DwL9YkaFkIK44UlK87R0EcZBTXs00KsMG7DMd+H4EXaVixR4KIwrvChtgRjXKl4eQl9v/EpOJsv1MXwSYHG2TwBX89+XoexDmoW0gAy12O1kD4d1hWdjC1Q71MMhvOHKlDS/Wr4X0oP0uxaUbr56RbbHAPz41X8EtA9c/ak9cj/XkQmT/dFHJmL8wCPTvfQAFeOxG8VRzRMrMNODYV3xhm7A+bAtDXWGrkJFpBWB6TVMV4WpKvQEaUYQKsV1hMzS8BuWT1aYWeWHJ3oxH0uXgGS30ZIWRXDgKOW6Cnq/bfhi6dpZVES5Fq7YCVxGLCBGLWzed9gNd47bHZ9zwfxFwaCXWs7IhBlsT+mvdaBAXko1urlUHIaLHntuNMsnUR1ggaM/QabFirpbAJExsK8RLI6A122pbAUQ3F3SsQMCF0z4ETC6K9514O1jE+v73dD0+gq6hC6xZW55nqU04PyZa/NdYvWKFJpr+pbUXCPpLLcWOaR6sA+mjuRnClgIy9iLg0uw1gf4+eIUNKz6HRkswQv+L3pDk5yKafK6zPNzCUHlp/7xWIjc+JsoL0TCF/cLEbeBay1IXSoVVMz4YO4ozFQ1yQd+MoqnNWFQdc23jxJoUOQ6U+pWI9FG61ToS1k1J0fhVEV8vJRBNMF9n288HioeUhMyHiBiRkyrMUjoVy4nERJuPD8U9WV+LJdBMURisYeq7L2otasLkMbAlNBOwY3p05gghiduGICt8sIgqRTCKYljZm4ZVJKL+pfSeqdTnIwLbrDXkV2qXGqL25FfiY+TG1rW+CEh/0mU2IQmJ3NGdanAz6NDZ+s2ZaPH4LrD0GsWeDgmc8weFY3nbC4hy5BpO4wfLqso7frK3vAgkQybQ1R2qVhCLhiuuWvZbk+6EaLNMYnL3Sp7LxAUfA0JWWELx4llDlIoSmSoady9ftL1ZtrO0H+6Ro84eogD8RHmruZnpLrHjcIwIzzOehPRW+TMWDYC1qgiDWZUeHqn1LCphPAOP35YdCtIRkCoPs2yUY+M3H7qw35i8JVVkvoYzZGN4r6QUYkjYYHL80VsQLhEdnREso5YolIz1S+o1paYfUw5rS/GlAlzxbOrFVe3m+IOspvL4+HCifBeUSpfrslrHyMALeFZFZSFIQRAmdAv2XWQxabXkarGoUW2v6S5qZecqjclwn49Elq8zawOknomqJdINlUzZddcOYQ1YDRbZcW5ugCKTXLoPj5jRJYmlf6ooyaAJO/q/+DqPAEZ1td1LBy5jmH17XPmly8vvJAKIzqAU6aiZtV23LOTkEg8ZVharRJo8LiVZFzrEjtkV3e69dXxnCo85V1kn6sV5StRNat72QWsjejTt6w+hPR1u6vBooehyBUGwUBDVBc9gj3Kw7BQZeGWWwO8akuGpRcaLeQr2x0qUNeitISM1goKeTJlGcORxV2HRyHKY0bknBvDGt2dO/rWH1YPjCq0+i5iMpA4YnwkEHRIidOpiBy7jLFab1jLJVFkSjXZnGsY6COTZZJpiJsNy9KYt6J1PP+982oupm5aVwNPyPb8sQS2y+uWIHa/jOwsV36Wq7uGrsECJhyytns+3uYV3YJ2h5vj7KQtW/16LWuF+1NiNScfFl50fD6RpYIorGOc03eLxnoJGKzKQ8BGLC4w/M8Fh7s1sAN54MmMM0VVOourTjWPwcoER1GzNuZTMi6h1dYaROpUI3Km6wHqkbTPDVNO4WxMcegO0RFZOH09BLgRKHDvAsbdY9W6pobfcLNwuWihoiyojXAmhcZlbka7KCNfeMWXtqRxa1Fdjj1YTQUjjO8DI928EI4O0sBCWDAVqPF7aPGvQ497HclJaixnwdKESL2Iku1gy9qR9hF/wtOd92fOlk+jtMFQlAKltD3fIGIVai9HlIua+/viB6VmQW/PmK6VFnUWvCaliDr994hiU6qyPF59UMDhaWJNydJ+kIpY9MAHDJGIqOvLG6ZA0YeaQP5I9sY117Wjy9U+QVOzU1bs7u8e1ImPyt5HZMFd4VnrbjfgIPVz3b6zVS87iqSzMm/CVVQUUjGKdZcFijmwxsYLjEsueMFyLtidPI31v1PXN+9/h7KpKDaoib+q2uk6WGv0A2hZCDm7owN6fCoLMrdWkeamxDDSnvO0m1tJwrRuo41ZR7Aqatn+zzROC6+VdvLXqmhgZSyH/HS0TeP4bZfx6+4SGopIzXKEZYFX8WyBNQnl+jPCjZMSDUjmUnAjq0oZ1RBWO5TVitk//U22keSasYKUBeqI8FK8uepUTal23oM6Ha3ijjsupXkvXtmG5tTOZtgeDPf7g73+9s7l4OBwsHe4s5sc7D37pZ7HYM/m1g3p01dMdNM0SjyIGkUwSwkSS7G2lrX0oGyDc2nlcmrJ7Y4bbO1J09o5k8tpz7ngcjnd7MWTxwWS0ZxcuOMFa0NUoi6ulG83RQw2LDrUFZuDzIa6+VZT8zHhMLw1MWtzg7ctlJuYy6zMK9bHHkfYqcFXZM+k6VV6bjxMx2FT0HTGkogWYXlLtUzz9I4rxcabXBSluQrREVRIV1LCu+BKEz9A9Sue57zzGcxVAx4ZdjLOiZu6Fn1OIKsuTFvnJJRTSHW75/FvJjLYQJjPZ6r8uVqFkC5Z5AUNzC4y742xa8pb3ZeYWKYIwl1HSgVq6zRpHiTIb/bg9N97tSoAbs8aSL+TY/DYZXXf8wovo36iekY2CqZmtNB282kD11FVhT4Iy1P01p1kBsKPKaZ4Re73uRTaKIs+eG0hZcFqjk2mH27v7O7tPzt4Puj6dPTD8UkN9VXeoJydWGy8Vyv2ezVgPqC7k73BIKtDJqasXRh8eZ3kMpwJ2ALES1WqFL9hwaJLmTCK5q4yi5GqpWGAbuE7f4AyMKoOnFgXb/ClVxfyRaiYmDhJWZ3EuZat0WvaVDzBnLmi8772Ntr69ry2AEXnuzvLNb3tdDeeCef3srsL/a7WDNO6nFuNQUhicQNrpxc0BXf2+mSvmZJC5nJa6/hjjxp57TNsuT6s0Yr8ryZy1Td+uUdLndl7yXAwXL7k/DVvCqMvzM719RAeZeiifx1z9OxAfT9K83oICr15tSH+OQaldiGhMZndvuyuUqLUNmwhANXbdb2ZVbcF7fxM3mpBeRe37aE5U8YrMrAXahcUDfeVczRN2o7PquEDpofNsNWtxsIwAEGt6GJ0wJEZFRkkhFzO2AKSzG6tqQxNf/w2VcziDPdF1ZeoZgBBlMwrrLmBUWCnz1heYEyNNpYZbmcM3H+hNFQq5+gDItRAQt20zKkKNasq01FZ5apD5bEUrLF+TadamSKLs0TV2qCKEODS1BRdnqkzH8BAQVlVFlgC17EVNFy2JjIMjRZFXk5BE2h7UqpEVwo7QXjtGfXhI1AF4fzd7Pl9gyOPGqUcaqZgdRsMNy72+bv0zBrVvex/EN3r5H1rZTf7YIKPwHKtMFyFTfbOcfmdykHMLiE+BAt+2uf8wBuunJkuco71RLmxFlrs1CmoMnrTcnK8Wbxy3yNA5YlURDFIS7/TTLc2ATzhWoxkMr2qHNBWHFjdJyRkYZE0gqV/WVZtK2tfuGR7AMQozm68tT66wtUfwb1MqRn0GMKek/KGKcUzx6w0Si72+fQe3B4pcmYtUM0YGb1AcQXJNouC6ZEX06NTq1ryFGEkb5lTmztOsgtWkOFzMjg43N4/HA7wLvX49MXh4P/6y3B79/++YGlpFw7/Ilj5eE4FnTKF3w0T9+hw4D5USq4VdboEMYTdzrWRRcEy/wL+r1bpX4eDxP7/Icm0+et2Mky2k21dmL8Ot3e2v4uI0Qj0CEvVdca6C6Uv+pi1huRjT1mH38hX+MiYkC6/MMhwPDsjdzP1CwKBBZX1THlu9bfgWiqY8gWcwkkqDHhM7JmN9ZHxhqelzL2WxhVBc73uXL1gqN1Nww2d18Oz2r5GuYk1IxsqgD21fAuW6JyrTvEGYXr2CHS+S9QOeOUdihCMQD+yh6II8HuVnGK9DTgOC1l6y5VsBNzcPQwWrkRNJQxaFf1B5dThCF6PqjFkFR0buswEPwRqFnb0SNjpUM0BjygrR2iexwu81LLexKnpbmHjchAvSgX8VJFFuCK87owDJyIU+bV6vtYydeEmuA53KF+mJoWrnhx28IoEk0bMkOUMPyvEAIdLiEOrW4168RFDxSIob3DicKhDGq6ao9u762p1NBO641B1ZK2JGFdQelUZ3OsXofZF1z5DdzrsKlRUfH2ei4V2Pri29/2lnEbe5jmqjTUVoyq44U3UkIzsjOY4JC10KLunrqPbLHAkXyz03OqpM2OKbBM86tjprBy7UAV/D93oRRpG3MB2Jb2qH0bfodj3x1X/qLRGpJhu3tW9pbaMilG9uozNtzA6uZ0t4tIVPsysLaTajueOYBw7GtDN6kE8BaXciVZLUcfgIcqnFq8Txv0ZVDAfRgBvj+oyxQ0Z5Ie7mnKvIN1GFWjV0T9bVL3ELPIh6KvRR53csjGBrpOuIpZowBMNaXdvxgR3x47V9awQDMZMOBsa4AUxWltnBBKZcjTOJQRjaG7YqINpLqGAl2tDR0oRLvnrav9H7X7F6i7MFTCbm4C8e/uS5Fxc+9Jg9/fP9HzZ5Do/CrYrhlA3nsahcyGeFgXFUWQx94LSUytBHzkJDsE8tAe1Yni6zqWA20w4csONKNCzvSq+SwcKiLhW3hbMsfWXwQB8jUsvD9fXVzrSEe/SGie5pJ1R02+5viYwAtiHikvFsTpXUxBqJ6uIljkkUuqofOc7zdztGaAG91furg91AbtzkztgvxJSLdMd+U4k1l+DL47/zjIY9iMI9TAOU6cUroADEgPLM8PBoMN/OafcNYx2jfIXsoR1r98ouRMBJQnUE9YRQLp+gWiHuHX+SGsgUedSBDSQaq6GD2hJ2OC6cUfgy6UsQb0HpXetX/g6LJiweteRDtHqjUehkhHC72/eMDuqFQfQg2tQel2vfs4+0NQQqDTjatg7nSgKCIjDATxs1R1muAlqUeuGRWb9A26t7qEUlODFAOMwQX3/1A7M+y5sfw5VzoOxEEaMq6FHtfbwKX+v5OMrYqPcSyeduEvGsvAHdxRqGlYCApbdrNz5FFIpNNcm1rsdZ8auRhMaf3e1JHA6XsBnzCyZoV/TKJfTRMPvif89SWXGRokXvv7r6niNvflVhhDmSLspWopK7VYYpdqEK3ZL88jdeHZysRmiUWtvBPXbsTXhRhN5K8KMWMzNnu9VlbYwbioLDPC9G90oTCkg3D5FntV52lC1TCLy/feEeAn50ZtCF+Ic3xVGHIF3hlVcyh2XhXaf/i7FCgsJ3m+k1lCyG6ISHHaFA0LoaHMJGA7mui6SK0Yzr5O5w9ozenXhEx2TuAE9c1TxrLFFn6aswGI0YVJfGxMq7FO7/aUA0+/sxE2+dloqWbCto7k2TGV0vhaV66bjsWI3aOP6xy8u1zbR5CQ//XQ4n1fChNPcP9Uf7B0OBmubDTHazjT6wrxUZsbVI2MeITyw7oBqhPKt6XLcx+DHNTjpe8hSGEgYnR2kUuRbAZVRTK7uESbseusoQtLJ1QwCDGTk+EKkoG5uoeySgtLpnDq+JGkzCv0zxi46vxIUTqlzTamW6T7yKMZpmg4CxobGaF4jkyDcuIDI9humDZ967OoeniWsCoEh525ovBfgop+xwsxao+OR5C79KmcP3meLOMHP1TsVYHiSIqcpu9M+ucMuqbb8J9kn80WHhQJTbO1tPxtmLBv3J3vjQX93e3jQP3g2GfR3abp78GxAdw4m7H7rxfPDhNJamdAXlH6sTqjVI0rNlE/qC5ER3Yl8k1KgNU+1yzSL0q3AXVrvRN/wOHxabm+eLXsy39Mu3HcL9ykZsPpw4wczuNgh8Kt4ZB9QXo+lZTuG60mTRsMcUXYKMr6pVic81AproZPn2R6lu326f7DX3033Jn26vT3u7+7uTg4G45003T5YFl2j+HS6lOfz7koTJ7WMuhqLueGXT+F3zzun0NVKG95UxHfTBl9Uz99h9rxpzEx6d0jUQ7FbYU7y2mWETmiv3Oap96KrT9F78T7IyveEfA+i772wn4pyrMsxfobwSFD+8W+rkSn8CGfAWpcEXVL8cRdU4MWf//uerOYjbJvdSIGFxjuteBTILtZkbM3CenC6y9K1v0Ksvs9LhZJ8KPf98fcC+oq7YifO6owuTEC/gStYf0D5xF//NxXZllQVsqQWZdtznWTC7dx4gVOe+Qt48qqKcvj1xdmrf/pOp7pK8XWCXW8m+LI7HNxdRyMNFpzE0CWAZUjNBj7hfKii0NyFzpOkymJM+CfYa+svqYtWc8FrOSZG+aE77zX9BVi1xBrDyKEFMBwgeAfXEYZKDZZOW1mZlKrrGK5HmC+2isKXrjwfaK03VC0szxQ5NZb3E/ITUxguD92N2IcZLTVcHuauFgvKgLoSa5Wl4CDncR6oq918w3pwkwq9AbIeybhiqZFqYVX3VC0KEwdWoOxhPTLjWcZED9Iy8F8p8kXPKY49cqu46bi4W/91zT+71iNr+LTvE7BMXprM2JXmU4HJ5Bmf2gOG5lalN7NlHK2P70qEnaNJmKwKjOdTNMTcBcTdDUjieLaAhfZX814Aul5twe4AczsM6RvHgjfKPqkg3MX1MKn8ZkibCtyOW9QZ3d7bfyTpMRXqI6byEupfFLDK4e7RzwDZq2iptg7tdSuJHss09hMX09WpJeuNpnnL8kmUaxEyxkCmR8Vb51SUE5qGegG0uvS9YSKTKql5JoNhHNsCR4Xlqu/fXEBniK7OMfPEzsmSD0WawIXgY0m92kT9+6/RaincBEHpJvmkxBY7uZxO7RYHsSenihYznvqKS8HhEY8Kmb6NYDqjSm38fOQlozeMlKJy0nHfLAZfrV7xRkQ1fuVtoZqUwqWpt1cMuplcvXt9+fbdxeXpydXbN28uH7tkJZZObhesfBJH2AUOXwtbgIxLFGVNxEJYATmWqpC19JqHYmYYna9409spnnLnw3hSua3tgjP8fnfaYlJt9DDoAzf86d9++scvB68Ojv7+WNJ6h/AnKH8ndj9B8mEtHzQwBx4KdiOEwBbMMYLTsn1EbA+2h/2B/e9yuH04HBzuDJbPCWjiZ/fnUqrtPSfe+oWRPpYjlhEd+x77OEdc8vd6TZC75IXr/+z7Ess5HhwQ2QJpnVEycO0WAVoE1a4SrJohZa6r0JEbli+wUgYqICjg2irep5zNIBQ/kczdmgVePU65gTqekY7hSyP44h+R/szIGGulu0SGaEE6xTqtrcVHZPYD6dSVg/0w4woMSN90A62hZe0pSH1CZqu9X7em0ijP6KnMv8picsYqVsbA6kDdBiH+Fnr2wzBuAdG0Kgu4/xvN7VQjd1XA7V5hmowAiyjUyWVlY8K9ZRNT6d/20R7RXKRhOH8L4eH2uxRqSzbyiOMaWU/e+AEGD77gejBhAKhlEmS0DqK3BlcFpR8/TkFwZlAuQXTFbeXjmnGZ4jdR8Da09HbXVdEVUgvDrZmcsy2ae8oHTO1wVzjMpyLbydwnCmx1bD1+D7b1Cy0QzP4sr7RM4SNJO9Oeojz3omAqpZrhAVC79oXDNQ+BJHGD9mWlEssnyZ+jA5TF5GvvAmVx+Co7QQHg/87doPJJ8qV2hLKw/Um6QkWofPGdoSJYv/TuUBGoX0OHqAjcr6lLVAz2V9opKkLhC+8WFUH6pXeMsqB+qV2j4j5KSwD379w5qvbiV9Y9qgb719RBqgb4F9xFqgbnF9tJqgbl19FNqhvkL7ejVA3eL7arVA3Kr6WzVCfQX253qbjf0mc6Wr/WDlO1F7+CLlM1eL/gTlMA51febcri8IV3nIqjmg0Tq7RU4YYozNIj7EOal5m/dMwZhc+ZvKfASHBpwwX/jOoofcIPrMmGD743VCXT3zd74OcOY8JsUJFRxM7skEG/sTb9fa0H3uw1HGGtI0+8cPI3RKVKdd0R1vCE8SgwhSv07yNT4LqqGVcaB6QGlg3ovxFoW/egyJe7tfFDh5ACuJJrTtQaPQzqZiEuzpbmt3ShYYGosUvrqA3T+JBjGNLagsAN0NSm2YgFTrxrDVfOEBJWx+P15YuLnq9DTaiguZzK0qWakKMcMlkMQ0fUhVGMzsnG0cnFZi/UIXbbIozqajHCo9AbJlyh/KuEMix5zjLyf54cXR4l5BcpWHJWBWRg5bG5dAnPtVx4X5vDSBc6GsrXZfJW5JJmcb1ncIoIZqDm9tHJBVyy+VoeFdXdXZtU80MyOj58X1Aze2/kewszaNdhVxxqOWdXgUlHSIFR49swsrvTq6rR+I1SVV6o3kqwbUt9wlGzwF30phVLMRStl5oPVw9APErFHWWeE4u06xyT2M+jHl6rxldRwHjdpXrjRYysw49Iy+nKQn3OFZ9TtcA4achT/PHsZPPee9X14WAwrN/+VlHWq4YwjrXqhK59G2oPqWSe7a0IvlcnezhFe1I9o8MVzXrx09HwnmmrWNgVTLy9t3/P1HvDZfw9j5x6b7h959Q6Y2xVTHhxcXJ6eh5NvcSm5WJ1jR7O7NhV+qtXa/D0qDQXnybS3MHbe/s7Bzv1PTznc7bK69ZXZ69O0ZPtAyDi6EC0NeOdTaTyR6Oc1LwRhJTQQManQd7e3iacCppINd3Cch5gcGzNWcZpH/y88efkw8zM81/Pjl4fRYfbhKec5ugV/mfPRTX4K9eE/Gw1wo669FYVwGuGcc56tfRmbJUQ6shGqId+R0uy0nx1nPTKMlJMdi6ITA3NK+6inUl/64P93UGDhT4xaKojZioEO1EoSwrRbfXNv0It+HXjsHGHfOjTWlkXvnYwRua5OKAWybyl0NTm5a1YWZwGpobZCdZB4VaxH/SeU9PqNk8H0mduzvrCa2px4FyvsXzBtOuIyqqZb1kU7fSwqKytu1a8YJ8j1uj4/F09zshQNWWmSsPsjDVaPtCogIzzgopVhdShYQLV22GalvrX8+mDEMvowlr6GA/awOuTwu8LlkSArRrb6NtHIntOq7iFZZCzw684diBgd1O/J34gZvvJTvJ8fzBIhs92h3sPQJHPixV6xtaP0BnmkHK32FDfnJyf4k6z1rWDgvT70BEPHovbchD7S6O4e9RDA4O4OcMyFIRODCSJI8VcKQvlWi2mMmNYIb+SZooKHbKLNBZX9T0bfP+FW9f2gIqpr5umaHDNAPSYnVkPIVdOPaKmpphNuGJsgaUpxrmcbmGt575VLaxs2toeDHe3BsMt8FNwMe270LM+EqfvchUTq7O17elBun8w2El32fPt7aH9kKV07/n+DqXZzn6WTR7AID6i5Qo2wwrVirATPkWaXZwfnb2+TE7/cfoAFF2azarxctN8Cn5rQVy//3B06v1Z8PlNKOB6gSm3yxLg4TdgHS5lO4jd1mCQ1ByEUXAzKgnoJMJKRVyTNfvnWpuFh/s7B7s1QPGYvvqqVbBLVDVACYPSR4s5VOb5bM3wYbXA6NpA3su4goIKDpLNFs+F6gehFNJKq31AhZyzE7LxDjxuqqrcGWXdbVw03HGoyy/jlPuwN3ieUOeW5jco0lZ+q+VyIqN5XcjVxsXR680EbSowskNZgK4kUVqaGVYEpSKrpSLBko5LUzm/3WUvOTv3N+VM98jJ6wsSY0zIBnQi4XmWUpVp55Znc8rz6r02Yb9PGLY9SFK59D0t0B56OKsE4VzlgeKJ7+pIgdjdOH4NfGOBgDzgiISBuC1sXft08PKRn/h0Ro60LhUVKSMXTN0wRY6PHkeEUpiVpd5UBIBZyMbxJnYsbeL37uIxwEelDli2yoU8iSdy63jymHU8/uu7ix5581e/nmci7ZE37/5qNbKoWFiPHL/+6z1rHrbOJ619LlOat8q5Pvni+2m8vHm52VKaLHtYSfF3zm4fg4lUUypcvb0VYxNPpcnGm0/YzGci/VRkaX5VCr4qxbELZ5oTO6NF/d0jcG8w+mPw14ZCDtUVKK2rq60ejk47HxbDxvnCwXnZIxegupy3WPqY5nwileD0QSgKaa7AeFwCp7u8tZd8DtYeWo3N7G3ogAS6NJiiQvOMKSzuxdsZ7tuD7UF/8Kw/3CeDncPh3uHO8/8YDA4HgwdjhS2eVokW1sxdAqXh8/7gAFAaHu4ODrf3HoESlDBOr67ZYuWVgY5axYB8cQIs9wCQ2JFbqL69eNi5ECGVlupmVRvrEqsY3rAotIoRluf2gdT9VKEVlReCxNVw+HEdFUry9zktIgiuTbG3PXwsJdiHQgr20GyjRr4gDhEWMGPgum4sX6jTsQRW+3t7O8881ZftlPUI7D/RNof69tYyd5ZStKq6oCla7Ny01fvtwe7SpSkBZs0Up/lVLbr/qRnXtZXFqapy/bqsuLj7FIQmKKEKfLqImjNO4gbIsPbFjLp6+D3C4yBXdBD6AC8JplZutRBrL4Us7DB0OqOQpara1N3be/HDD8+Pn52c/vBi8Pxg8PxkuH18fPQwaREqXKxcAkbBVRNLyLjkUiizEUmJn1nVCRzvpANR8OieQE8vLsiPkrykYkqOoRqTC/pcJOSCseAtnXIzK8fgKJ3KnIrp1lRujXM53prKYTLc3dIq3cJyTluWMPBPMpV/ebmz86z/cmdvp0V/DNboP1Q+OyP+j7FcdTBdPRhNrDByNpnmckzzoOUJtvSFRwPJP8Iy/UTD1AP/JVimrepkzgWEff3uME0vLv9aqa498vKvF1SQF9bo5DqVkenas+ZLAobq0677F2OV1jB/FCp/tFl610atLeEnY/YF2KANRB+Gy5/ZnnR3uqtVi6IEYzup01NaXLdzP+QhZpXhZnN1nX90f95T1vlHJn3R4hS6+yi1cDHxUKaRVsFeUAHHwqoYVtSCIHEPaa11ASjjUybDK3H9R99BiGErf4zYZukMFMSqMaOF7Ozca3tSudtj1ddlUeQ8lOz6pFL53CxWVUnx2AvI9j2nFEYxWu+riC0imDBXaSsw7kngubyVfVfdKG0FWobZ13U3zK+X1rYqRFZE2Ne10pRusjbAUpkZOQJbgDYABLXlimu5KlofO83o7OINELutMBx1grQqVnTgdK7sMRW0UVXMb9uPgDJl8iouJlKX2FJMuSkzrBmZUwN/tK+i/pus5VKsHZL+s51kf7h7sDPokbWcmrVDsruX7A32ng8PyP/UrwFXmSX0zsoYn/bYiFqigTQ9X2cOm+LICZkqKsqc1lq3mxlbWJnKUJpGV+vH3jBt9IjlCqVvCp3RdA/vSHMplbOZe8HsbXcSRfDyKnkZ1dUeyDk8KeuZYVVGDLpXuLCGt5yDeI/kd/uCfyy1kaKfpbV1KaQ2NF/Vrlo/h+FRfDVTtmAtPLi1wpzQd6HRtChqqBxaoo4ZuRby1rVssajARFKRX87OYwMHWyhWVeBvecbyBR5k3iaCpj/wsU2757uD3aU9popNrRKyQmH1Fma4T1b1/3bcBdOKpJWDp1NY/a1kY1bnue6Wbk9zZLrOjuR31xYsZrJe0FTOjl4fRc91Au4Ooq0jNYUjl279UDIh9dURV+wjLXHbGUlevwtf3N+3CNOMnJpnpVFH90J4RldNCRo1DZ+2RVEm55SvLE02VhBC4Dr8hYSAJqFz5nqLxt3ba+2WBXl5cnRu9/8RNoGvimEi/HE6XEiQWVV0jfOf8ro7r0JKYoYMZsdsha4Un+vYjGkOACXf1XOZYr79yf99j2HiWzp4tq04NWo9ys0t1+654MOMW5DiidoI7YQmfsGbqbyjzo7CXHcY8upkrwcJaZsES/IwpxIk5CjLPFCT0AgGw1PdEOMFyeUtuJR9YH4dRDzxqfewYh0FbBysWUEVlCd0I9P66bWhBb3Gnmo9gs2RZ3Tnam+4vRkQrHK+q3NOMxPSk9tIw8NRWeoSOvPcBLOXEgWhs1bPYQL6zWKwIDkFFaMfrEQ3oJeN/6I7LigYKRCkMvSYy6rELgQRsnvDLeXCmZpkw+ToqS9YjyhmJ8N615tPYAR+7jTKz59B+cckT/4xeZNfSMpkEH3SVSz3os//fW+rLehr1Wy1hTfXudufVmxwoQ0VUbvj0+MLeDf53kuozi60Vl9ut6aCSaWotp/XYaAV1YwWBRMsAx8bqLpVMMGcUV0qrEV3SzU0kRQJ4OrCIuspSDOqsluqWC/U1pljBWHdIycyvcboCkO5ABPIbvz/LMeQzg9dkLNQmPFT9v3dyUpPojxWId2ujkQ8X1c15Kv9esR0WpRJqel0mSMb+slnV3d3qT9nypqUkD4FZwCuHkS0hMbv7h62artun4bu8TXLhxs8DVzvbWxdH6lRFynNLd4TarUlS6FaX/tIyzqEPygxtxLmAR7sxbe6Ff9Cp50bpujUKxqVme1e173QjXwAkA6D8aURpqsYpmVNsIzr60QxmiVxxu5jr/SNNNUFuM8CJhtTWk7ZJnTqsodnyrSelNYw36DTqWLTqLsAQbrTPAfQ9KarcB/KsmDPOJLKPH9gxT1AFXuDrR5XO49h4o9E9/PZIWgxyEkkNr2U98bIXZZIVU0ed0Yabwk7yPq6vstGCSNKRV4z88PZm4ua9QIzYaXY9tgV0NFMYUSwjlzyi+ooUv/m9eWbizfLLsWUyeQLcscDOH8Wl3wdmS/ULY9AfnGu+RisL8Q9b0H64l30Fshvbvov001v1+abq/7JXfWWrF+iuz6C68tw2VuA/vxu+7oTYEWUX//JjR1radGmOjPOwKtyCjW5nTmpOPKQjcAfaPeKYqZUQnt/Muiozjr/iKv7afBxfm7UjeMGYkc60BHNVuOLJJbwSs/KRt9nPVxjzBkVXEwnZW6l5kKWijBxw5WEckrR8Kd+yV2EvcKYc2dtjsaMGqy416RC8REq8KILT/CN8KKZpBl8kjRdFbOQV0fH8bSBAhZxIY2r2Y61q0BQvn1xTJ4Ndreh93E5nUKt4kNyStMZkalhhmy4NmY9ctAf8yqx2tp7m9jt0mm2zstwK8mvIer6n2TGPtCMpXxOc2wCqMmU33jfOaxpZcggn+PEFJq5lcK1ZObCsClTCblAk5LfuAfx2sv51l1n3jDibFHMWMfhuf7r2mDQHwz6e6fw705/e2etR1pf7voG2XffszzN8r2+d59D/JZLG4YdHu3uaFe/E/yDc0l5vQUM799KmkMpqjBmZCeC14+iBuRc/ZW/qNSW5JCuYJU7RexSZtCvyZq69eUz0j7f2ESudX/CplAT/ClcD3c5HeAKSZbg6aR57qcG1oEmKq1O3iCKnszl0EC1oOk1W6pE+HLIuvG+OHS5WN3SKpYyCCX0SH8huK56bQPefxC+UicTOuf5qsLN31wQHJ9seJ1NsWxGTY9kbMyp6JGJYmyssx65RQdZuwAGPtmCu8zzp4P6M5chad0soISuV4ILFamcb6nb9UVTS+VX8l/0prW210wJ9oRUuh8HnC2ADYadoreuUUML8t1kNxn0h8PtvruPbkL/tL6HL2OF44qMjlB3Lek/mvTwESGfaz39fG7vpkwYqXukHJfClPftV6pueWu/rrCmzvo7jdJw5OYZOW8D9Kc2bCoV/x2fkE0kuTCyUkwrY3OsJM3ApGIKKrCCHOON4kr+cc3IROa5vLUjOwOmXlSVbPh4ErZ5SHIsPj+nKVBU8A9VTuRtq+3sGYL05sJaP+vr0NMD7+fAGeNMKReHkXO8f2P19uP2iXGlw4Wr5ISc54xqKCRJSg1OGXvWyIL5PiWQ4olTnR5f9CxVCyULqRnhJvKJucL1bS0c0HzAkbTain8tPl9WYA0HyXA3GdagbXP109gJl663XsNGeCEVOc5lmYVbG3+hhBkZcJXvWvlCRaKcXzMyMtvJnGW8nI8Sy0w384rb2ldG4d6+h61pwh2Wr+AXZ4JUxnkYsctIr9sKZbFkRd67lKoLlkqR6UohmlFNxowJglFr9WXb2d6LwzmMqUVg/nR5eQ5/3x3O8cLHr4WkGfsSduyH/OYgf0qVe9mjmQlNJDxS1tJSuRcxiv1WMv0EsZh+oLHMFo9Rzz/aW+siri7XAJ/ArE2iHxw8uxtEVz15CSB9Waw/5gy/dJY1Lve9+P7E8lySW6lcs4cW3itYlUu4mtf3rc2GBRYc6NjzsuO0Hu7udC/VyuJg14+cv68ZCgtdsGq0Bse+ciGEuZxqHx0S1jLNOTQQsThqKAcF5U2hpCj1baHC03ZFeVaFSaKkw+sYIqToa0NFRlWGYCDRKn/z6B/9twhZ/+ykahQilf3l2AHKpbC/dlRU3N5hu3v7z/rs4Pm4P9zOdvp0d2+/v7u9vz/cHT7bfUBAi1+kOTMzubKFqq0FTnVfN3zFwHPFjT2PICo29HIJfXkx/LweHTH68fRyVB1JoykzvivKj+xyBI4/ax03i9t4Tat+x9Qm/Pmbi8tu6q24ucD6K+7KCga10u7HpvyPKBqipuYlhPLli3rg35hq9Bb4kzpqGBcLqIqaVvFzz4+O8YX+JejIrg0uOZbzgipvdc5jkGkY1Kp/kdIQZltf1yQe1o3qFZIZywvnuc+YYanrCaEYNZqE0GtC5lynUkz4FLpPuU3dXkk+p1O2NeVLF9D1NFZswpRaWQ7wWzd8xYrx1mnJXF9bY5zLaVwPbKsBuy6k0Oyzn+s47bIHewzk13qy34fx3Ue7x/xzn+0O2scd7g7oP1r0OTCeTvZFS/iEws+N2iH98JfHiL+arAujOuXlSWSeI6421JS6I4rh01us1vcNTtQdzLA7qMdEr9a4B7jucqwNwXivGiE5H3tsDZ7Vvrw/Jy8MEOfl+RpniqVSWcUSLhKwpi9+rM9LauY0FOhWzDWHHy+wTSyyhktrmnDFbmme94iSJbT+yCW1myOnImVqM4xabZMPYZuEsWZUZOBBouHOIZVCuOsDQs7c61TbreDGpMQqdXk0TEUCBM6PpZnQUsGtBtEFFdDicBP3dAyHv2jpIEVH2sOnW8o053RVtaYD6+AseNdRrWSV2tfrCAbzq1p5USzLzn2bI9RxgcQcdOAekaVxHxTJ5r9b6wgi0aolEXTe5cZyLy4rTVZmBlb0OjtpEqvG9hW1Ll6/Om/tH0LOTjpOvqVNqRWGOJ7Fa8Hu5oh2SyYz+wj8VaGHaSy/Xro/78lNOmmlDYFNZk+yXE6ncEKxdEYF13PLXP5LMKkt9FGNGDDKq1QlKwCr1fpoulJrOjeul6GpNRMgtHXLqtV+/iiNuW5H6oXO5TRMNGbRkQb5mWRkwcXHku9HNUT8W1VnNOn8mJA85XrE1jG06oVFgmXx+N8HW3ZcGqKoc5qSEcL8/QjSKYXzpp4eXzjyPUFCVOj/uSqtrtXCyhIcGgCA1YO0Si2zW9O/caMZNux9Pa6W6m1Vbf1JxQ3klmqxvm4wAwczTgJ8PZJJWK/QVfU+j8HWDVVbuZxuTUoBnUB04jfaEhIl7m7zpLcCb7wXxWIV4qH9MtQLOAXaOM6VMaXc7YF2BHJDKTC1oFE+u2EKAppNo+QsnN7C5SZPJSQGItvDIHjBAPvGzZtJhquCG2th364U9IUswRtXlCbebWGvW6nkgSHQ+xAVjQvc6v6nzTh3Tc6ZX0kUSaNbqsSoR0ZMKfs/HP6pdA2ad3jrmFLOPxGJ2mnTg/Bk0a5xAChO5E56exa6Vo6om/kyuaUuQQjFGyseJc2p9gFcXHDDXYpgNQPoDs5SoSQttZHz7igDqaa+3wT2R0rGUhptFC2SH/ynGrHQ4QcdvJKcN4OEHxxCY4eI4mhqjRgpF95+czwH4RKIuXM8xrlojf3SQHV3+048VpkM0eSBp8IufN9VUcBfHYdiIq7/e02yY1wguOFTg+9Vk3W/YscFmVD1o25vsMA3yb/oDe0keinSFRaPbJHcTWd3BTq7W1T+CO9wXwgypLKDmFoCfuwdfxeUTtrNmaGQsxLLcpemEp2BKD3n3MRsccOpGybc+GvGyNsXx5rs7W7vWqR3hvu7SQf8yYSmPOdmkazClbAeYegqPxM/Yet4A2zpDeU5HeexInCUWnsbdoqMsLJ2t0XrjoxkKnzAcZV2HIa0727vtBl3e+deGq1QSkSUsid1Hz1iSxOrgQekMD3rwqVQXKrlitA+bKkby+znaTP0I5eYVUNyTQ7I9xVx/iMoC0kYEY7SUMjcvq+gTwNhHwqWurt+H5BNHfc08tOfDztu+nb2usgaAHj4NvrojglK0tI7pqY6u6MFCtRDY8NIYMTaYlW5pzlxJWmASk1n1dnJxWYvVgytZtcC3u3MqbSEd/aS/3GU3Au61TPhMPN6pgVWGy5SE6mzVt+0Go8sUPHLK7hTWaBN3tAtO0FpLXmnTAgLvmrN4Y9mhjBhPVNgKSYA/+QdHBDZFX/g4kdQtNb91JkJjQjy2CfzOvrqI+WyQvx3rXAMOnLn81I4IwAtcHnDlNNQaFWlBsIR/Dhx4Rddc3f4SPfHlJnxo/sAKDdsM0mUCqcmPUGhl8oAWtU2gk76Ua3kaErsgqhueAqabYhacU6GeMmRl7wjPdBuA/l0K2PakLNz3QOHuO7FFeo1mGO3XPlWF5uNKD1U2Z2+jVwRAWm3UgXneig5EMaoucraMRkVWrq2yBE2Y+ZjOiodqSLYLRtXVLKkHME196hjpJ6LS5zQlI2lvB7FoQAjc2tVVzVqhJog+tEN4ZjFlW+MDBXbMOv4t5KpBRfT9p6lfF7jro4L3JZd/5DL23W8vcVbW2xuzJRCR/9Y2i0FJT4asUlnEzJCNsEb5RFGwFiWscaHtf3998olT/fIyO9j9xNqMbyipi7nHYfV/kGNAE64mMXVKoO+fCdq70oVkPXvkbPbAgvE4c6gmtyyPHfyL+BTKGlkKvMqjb4uGqM2QsRImffpVEht7KHoQ7uM9Lxeyf9JXg+57u4cHfVCsQyS8+nMbAXi9XkGRfQ69MHD2Zv/0K93f/qPVz/uvfqvrYPZmfrH+W/p7i9/+33w19pSBNZYgZ9p7cQP7hUDvzWNopMJT5P34q3vHMNCeBVV7PC9IO8Dcd6T7/3F5ntByPfuZhM/czGWpcjwD1ma6C/uOjG7lz74v+KRyfekFMDc78V7AUJ5TovCCh4QU9o7du2B5wyguRTcSOVLrrAPphcP2eHxrQLToCSOJlBhw1LlhrPbnqvpGDJXNXm/5hFei4eWirxfc9ivJffC60ktFSmY4nNmmGrBH4/tUbkf/hrgzWUNE9Xo0YkcLtNaj7xfC4sGf4VFW3PY+mWLCJG8F5V7qfaKczClSmqYNUBEYApoGo+hflyjGyqGFHqFYe2NhgLkjTBzK2EJNagc7tI7TJKg14vmWtaGRTArTMLktRndpuiYy+emx4P60fzFSATEZRVjH0XUu8yQSZnDt2cX5/YAj4f8+/nrcKKGeP9kre11AlrWxMhEqluqMpZdfUp2e9W6GO9gIidk9JO7FSiU/NCOnho+306GyTCpe1U5FXS1XTmgNMS5Pyxeo42/4QX57e1tYmFIpJpuUa35FPIU9JY/XvoIXPuL5MPMzPPNyhy5cMcKKCG5a3Li39Ju8WnOp8IdaKAbv2bmRS5vMWwZPrnsgjAuRDujdl+69IIunNqt8eqEFmIpEt/tf3wdUmIFU/FFL80ydwK7xB/L+V4ducmpcA/HzuJqb0H8jGBqbvns7y+PXiOH/dbnov8bfmEoXg9zTVzpgYQc5VbJi+rKITz+7tBOm/AMyAqf3SUjwB7B1LjHtbpEGBLg0Exk7tIbZAAsGgQp2p17MNhOhr8RJlJa6DJ3IRpGRmIeI2AalvAvjF33yM9cMT2j6jrZDAT/WBCGRSBx2K1oxwDN26EYtXCd1u5eOsoiwmCFzpA3zrJHZO4KurgTnQeGxqwQEagTMeU3TLhkPCwoDRlFznSo6lj5TddE50eI3P6ZT3gN7M6k9vsMni7jxmeyP8a8ce92GDjVLx0mjv8xDOmNnW4jZ7sed+hF8gr06nUXLffm4v9n7+2b28iRPOH/71MgtBcnux+yROrNliIm7mhJbitGlmVR7u7t0QYFVoEkRkWADaAksTf2uz+BxEuhWCWZIllu2c25vRlZIoHMBJDITGT+8hR95AlJjaV2H1bVdYJwwhmeEuEJiqo9dPCebxroJmHyBkIDN1KO6nOKfV2cT7t2b/B1SKxrj6YHXMgNggakzVAmFcFJYKz+08wTnjoPJZPnBqUg21dZMmkgFU8aiE7u9ps0Hk8aiKg4el2X/FQ8I76aKkPn2Gv2yjb7LNIS2DVyCBqvTiSJG2hCxyCWuoSipy5I5Xu+wn6Ey8u/sNtR4NM2Tv0p/N1T4OZBcuYswjlEA7EHMmno2yoz0XcuKsK4CQHvJu8MrUisGm58k6BisgC/OmKzaEFb71tfMQYfRRZ72/maZ5/54jDNzaCYxcRAJllWwenzhcalDH7FkcjY/AJAkg+Uni5ywGWzGOvu3UQ2ILaq/SvwlilTIpM2vGwu2q2JAH5hXAcM5UzRPLxgBza2qR02JCmYEfIMUi7B9i4NraXaufjoixb+V648/P4MXhZwmj7xsGB1uEuOpgOEma/hAKkbPqXfF9LlhJq9IXO7+wl5Axd2VJPhIWgcoY9EAkTcHxnJzMDo5OoMIPqha7n0kcaJ4IBIlod2/DC+04cgJt6RVyo6eUCi3MlRdwWvISTMmV/Mq3Nn3YJYoRE3blSefw8R+iCZ3HjQWjyAZOIvDK0VzYYADMZwCMVNEhodTF3xgws0ItQ1pQRYjAsRMD+uq7mddalmigrcuxWUFmhHeba0AAX4HiEYiCVkXuVvwb68QKJ1KcGz/aWSDH/42oISx99nsUGJoe/ZjAtZ+M6tuRJTZTje1UUkrBZ2iLzulcCHyJ7g7jEdDGUX+QsgFgRyG4t3he2RdWofFRroxEba8zvo+OPvDfThsoHOyFB/Qjt6swK9yPopjXtmmPmbs6+bGqybGqybGqybGqybGqybGqybGqybGqybGsyHwzDT06Bo5+aPgiuMZDh/v/ZQhg8sfK+xDIduvQ5mLIOLUBLiDx/NKLP8vYczHEffczyjwMMPE9BwXH3DiAZlMR+HST+LRTRypAhsRp25Lay2KkUzIIrhB/1KNOP44+9zS3KxBMA8wS+HHqu+xWvqdFNoclOmwEtq3fRmZm+ssOnN6vzioxxd4Mm1dLn78EFYHgtQYJKhFA9vPJdaWwRbC3Jsc7NhkGfv+VdO//Ko5xpDMoWH8NK+LENcDDGjf866hKcDxHgImAB5zoQkJAmh1y1dKRkoRMYTVeHItXuQNtv9ubAQ67Yc9g8vo2nDui3Hui3Hui3Hui3Hui3H99KWYyJ4ksXz4BAvGsizMzxi0MyQKLctsrvHByCC4rTeChgXGLOT2bBX0XSvrX3JqIj9m7tNI2JeKCADb+zqiYvmvLDtQdFEEPeU4ipr8pGmEyKjKrQvV/skQkx5Z/QB9Fci4X8m8D9ggMEPPE0JAISZ6Jz+KU9yq6j9LwSncnxblvA6UMJ+gYHn23Dd6RgzNRPerjy/KyHNb7Xg7syxnOIRkQrqF+C7Ltt09vdf7b9iUwGDCiJBjLPtCqV865rCrE7HjTGDXrAC4VihbOLu+OWACWivXr3YuTj1qtAU4Ht4TCzEFDmzBYdVw34j3LX7ROH2vFo+TjOpiKgzjFR4j7fTPZe8TNRlPX+5PHPEacE7UdutswzN9e6Sq9AxWUy48MA7yaPFdSw8jI+gu60+r/m+hVoefAslwimOyw7VbdYnTWMZzG2yBM57XXdsMAXCg4EBjbKxQgOr8WrAhfFymoowzFRZIyLIyVbZpMJqt6iL8/LsNOK3OsFuPquaMPCfq9awPoZI1ZzwpBkn8f3cZqfnp0Y/xfVam58XW5Q9LxM10h7eou7EG8vLVJzqYyMYAXQZxsc4gbBFyrNkwDOWiGl5x+VfeZq/R4+Zv+Bnfv8V7KNwHFc6QJjQegIsRuNUBW+yOSBRzMcTzFy0jAubuVKwNmeyPUIwJOkR/EcknQBMFBYCM2MsDGiqhQrjQDc3FxykLCEP4JQx+KALMHoycn5Wge1c2/PUl8pmXGpmSaLSZbE6cr51ECjcaS58lzu6hU3svdJufvc83Rbik8Pz9siB1Zt2NuaxvDH6XcaM1wHjrwSMv+No8fetJVYcKv6O48TrIPE6SDxXhfRLjxCHEBh4SMKb/iL41ZMXfG4tPn6/g3UoFU5TkvhCXzero+9UuREkAu0JWWqlodzX8gxRo4iCa0XSP8NRIRfZD20JMWPamtt8LEg0hS4vcWDmLRUSE/GIKhKrTNSlNOxaFaYqrfrD2/3efhF9oJ/RNKk5XrfZsWepcjVBPWkqZqM1frvkx9ztFv+bAB/EY0JpLUcV6n7omAoDZgrhCQDLuSEqACAHu4M35O1Bkuy3+62Dt2/77W1CWq1W/+Dtwf7+2/03b9qtOJn34McjEt/KrK677cgOXxKW4xA8ljsiDJRq1ZW8/7a/s32Q4IO3BztkZ7d1cBC/Sd7iZC/uH8QHu8XnmWDymjg6LlaGAH5aUTt4yj9NCPOQzIIPBR7Du0mK2TCDqCS3W0pCcuyWICnF/ZRskcGAxjSvekc55kDRszTi7MmY13bPn7IEloYN0YjfhwxDywK/orbaL5NENKEcpYGGKe/jtCQX8+sqRsg8nnKCVaXZd6UVImCAVdJXlFxKY8JkbbbRmRneNpfKY6YhZe6wB3pCm1RYGw9C2bsCZGosDDNi6OwLPkbdi+PfkJvujEqlRSBCm0NK2k9JDqEnJ8kDwOfZIeXW67Ke6UxwPCJ+4O2oVaN/UHlFBFPkO4cXDfP6+mVdYDUykiysGy1tqLD3VCbFFmz9rSOSplhsDflWO2pvRwez/YEBfb22gP0HPtYkmyiYnyx8IvGWDdivVOamiu9Wip5oQOFhdbnWZXozzXvfaINnDq6f1ZzC7ZhC093yPbK9vdP+Zs6RC02XbQFIfLT+gbNDwy1merJNJ6ThOtCpES5+xDxq5U8QEJfwYDSHSEzGDZRMbocN1BfkvoGY/sWQjBuIZfDrf2NRPvNiMvcLTb2WmFvQ4ixhN9jt6CB0Cor+wAn6AL18F/EIfjV+ILrgQumtj04eSJyZH19dnLz2rXy+C3P76OJLYRqksBgS5cPE0OWpZH7v785tPRbC97UUkjAo+oRpChkUpjWgBddNEFbwKZoS6PpXDuzQWHCt9dARFxMuilBWX2GzfqvSs5qUzctncnqBw+rsr3Cmx67ZrfKszfhNz2RrP9qJDvZbraj9Zre9Ny9/dDwZYVlbU80cGh+cmzEg4Bts+4sT24OtwxwVqNmEBqLwMRTQhfRfbM65S1wYUDYkYiIoU6hPGeBtw8M0wgNFBLSp1uLyfS5MU9KYJ6QZtrFEFujTubMSjTAUKcSZENpqN8apgSGMR/B2Buj5SmDvDgP1JsL2Vaj9+/v7aEAFIVMCePv9lA+31EgQrJqCmCaEW9ut9u5Wq72lBI5vKRs2xzjV9kjTCKepJ6RsGI3UOC1fVK14/21rJ94lB9vbbf1DEuO9g/0djJOd/SSZu9+666PRg2NQd0mcFuQyGqx70Tk9v4pOfjuZl796kyk9U1UZlc9kbsPr5+uHzom7heHn2Ye8jae5D3iPXYWyMwyCXz39pD1XpNBNUf0grY+zf5SGHozQCcCizhV7x0PPHTccoslWsBWDbrhjA3gXmcqpGzf9hCY3iA8UYUgqPJUuJm2mQlRJkg4QZn51NVcTatSM/qDxx11/AnjsMuTmceXl7JxhXRXKmx0h8NTitoPwsBhmAAjf0MIQysfrIWOpL3maKeL6IOcqckQQ8YZeoOI+4qlWyiaTwEhsIri2pqAQnCp6Vyg3r6zpAr+wT9mWlKONBtpopvq/M0mE/t92K9L/r70/W9Sl5dYDIInnOUwzkQjChspfUW7P6LEhVWI665kUCqCCLgcOKta2wdAc63/1s/iWKIQZTqeSSsQZGvF7P+RYm21+TdC99qe9UlDcrFFwlNBHuE38F8ZG/pj5EakNRxlDQmZyQmPKM+l7WpWX4BnmbEJ6kg4Zhrh0QodEqh5Oh1xQNaorRgo5NPbCQ34ybwhoegprN7NgBu1XL5hpnDbMqBwhz4VtAIF9e1dbueiL6qBJth/SVWhrVQ6fFADqbFVU3jfOyCYntyx1OcLbe/sLip48UPlVoPM+5ynBrEqm78yfwja3dIBwLpawN0HpyGp1trkg5fonyoY19mPS2yWIAs67T6j0H3VNlGY7kenjmg0wuIqmZDUPFJns+LADJ8lbEQuSkjvbhaUz0bvqp09dQMMo74uYjyM9J4keJnEEOfyLilphldV3tXztNSjoAIcMKdUidxo05aYrRiymE8WHAk9GNDbdymV+R4Wj3uGUJiGulHbbRSaVm0+b4HcEZSwH6LU9hd1X86+45OJ8fD/sPZYoY/AURCp66p9cXn667H05v7r80r06Oe5dfvp0teiSZQAHUxdsUNcMX7BEIXPHqLKVBgVmOFMEj2s+9HqKVZ58GA+e36A+BV4h8ydvY9RH+UHPr+DnHfiTzx9++/3tx7edXxYVrb6hFB5P5hDuY49Dx/o8YZaYZ3PfE8lvDnMp6INgHvT1UYIWP3Bblq+I7dZ2u9nS/3fV3j5stw53Wr8vemXA+Zzr6euJG2+zq7hrLhnqiIpzr918OgOURhPjY+Vff+x7zibT/hxcHCQx0EtqRHM7opAGA7BIBRhxbWZwnrqGXNp0I+nUvEYbA8QouLI5vczdDEpxSTFXWxaQr0yHVOG0aGOYp229mYaYMqkKLgfEdaamX1yh5X+lWseFtfiKzn6unMZjzJJeSufCbLmnaRJjr8JWkt5XlPL7LE0dVUhTZTYKuAu2mb9VdrM5m87H85NaX2/GxzNbFqdp7mwE8ofaxJIXsoQXGLqAqAk9MAXynt+8y0TSQfQNXg0+4nikRV54ObDq4OTs/SOvBm/3m/M/HGhO+lNFelwktdXRvptqy4z8kcHrJx88TvwZVSol6IQlFM9tAGge4knWq/EZ8ejiS6Ea91EGTpnyEd/5CBcEtmovuDAXuVdPHpQw9TYm88JlG/g2l5rcTZk/m7l8EuuY5kaFApXWz2iqTMI1hAuTCLLtMPM4nQN8ay4QGzI2rSR43stiLv7JA8Tq5+B8kGKlCCNJFftntrjZDEcSRAzGnanJM9cilDs/h7YRvF5GuF93heovxRcZvbvCblfvzPWU47m96rw7fb0IK4DAWBMT5q3XgDw+dk6eQ6vepTWReowVRuYtOSDUzrsAqYQpMQ3RmFeWUmGFmk+wCskaKNC6szg3Wg/tDXC4fZt0R3RuTz6LbNnDfVoTuV8/b07yZ5RlD+hTdxHJ13hF2Z3y1C31HEq/ucJb4PTZd95V3R1muBXdHa7H5BzEMRJ4naVGYfA44J6/bE2COcAGpg3HI9fQsrzuQfyB6IWyfl5enGFDr/apCgBz9dAGB8Qhh/SnSGb95kwzWkipZERb9Tchx9FPN8873v6L8YhuL6ZACzAwM4nPtCn/yGwkpY/7NKVqCiF6QftZKDZLx0LLDFcAn8yTgfMs8rsjzBhnyA6PYpzGtl1ubtotRfggxbU9C+qN2LV7E04ZTLYYnTVmBZTIDOsLn0elQ/7v8cFAkrpe/EsEm9mWJHnxlFBI9JyLUN8ZISy4eR69Nd6hJXL1UItReUeFynDas/irq7YLS5Ta+Rze63JE15Ec/CjFC+yEIYSDvtHVCpP91Ver4XiBq9V+8RnmyKIHx0rOCcxvqoXorfmQz9D6/GM+woIkvZT2BSQE1kSqs0zNdMhPZ56RIEWAyoChZ/GgSDqoMSfTDY/kdNznNnVRH6vneyVsqWok1zMkiFUj+HrVY1J7v9naa27vXLXeHrb2Dnd2o7d7O/M/KBnUlBqfHx9HGql6cyQzzR6MFjMvkdBmF15E+gHMi6mSMw3EZQgM6EflA3QPoNKF+m4o0IE+S46QZv5+9+XL6XEDdadyzJlL/kM/fzk9lnndN/QJdkm8MHMGrKZT/1Zqeqf5prLwTFrm+ogzqUQWwysatjl16dQOF0oOULJjPtZUTQSOFY2hFHBMFR2Gz/IXp8dIkEwCXP89SVMo+w0ecbGTZux3GAeERTomDYRjwaWcBbdBrs2Jlh6XquKNLd6Od/f2koPBwcHOm725S0nzx5XV7cJvjBzRmUkQLB7eIEFwRmLh886MTGhVs7/npfBdwcsVVea1upjJl7cFg22liBi7poyAYhZVNYjPjQXcN7YCjOnRO/PJ3Cm3iGhQjRhm1ur/wKNcRQlhe+fNvFtHH8BonOzVpL4+Hu+ZKcqTypHHWFz1rN0PnfYT0+bJcTVMvL23/8TUe+154h0LTr3X3n50apkQMk8Wx0JTd49PTi6CqefYd981zM2mu9JM2MB/v8vHBFJmUGxL7U3tuc2KEkjSMU2rCgBntdcEC61C1gncz0vgnqcyI5fsOsX7W6Z4W8GvM73/skzv6hX4jhK+qxlY533Xl/f9iMTX6d8vPv37kZX7cbLAqxlcJ4OvLhn8EQn/aDnhj7C5Tg2vKTW8Wt7rDPGviWudKP4dJIrb1fpx8sUDhr73tPGAle8yezyk/2+cRB6I4aXmkgck/iAp5WWOXnxmeZnkl55gXqb4e8gzL1P9PaWbV1D/nWadlzl54cnnZYJfeg56QPFLTUUPSFxnpC8qse8tMb2Khe8pP72K/hecpl5F7ovNVq8i9vtIWn+S8pebu15F9otNYa8i9nvJZH+K9peb0F6gep3XvpjEvof09iqyX3CWe0jud57sHrDy3eS8O5q/n9R3T/E6A36dAf8XZ8C7vfhSE+HryXV/jmDW2fDzS+ubJsU/k6xvlzb/fMK+YWL984n7hqn3zyXupSXnW+JeYI7+N0rDn19GE/It3vnr7iaTM/M36SuTM/zjdpjJefzRe83knK67zqy7zsyzT374/jOe079jJ5qyHIZzhSeeFQ0+zb1qyy80aQkq6mzir/Ps+kSPr73o5xpik1nqS8n6z+va6NvdlNZgd3t3+5nEgds1h3CfFbOyWaT1Ra1AQSXR6rfFFQyMTo9XIVtLZY36yZIbvih6gs3szdZziabqZcdfvN8AlM5EJvQOhN83TEjOOBK+Xg9Lv0dBZugoyG30pXuHfshB0H8co77g95IIJIkCbUaVJcJFge5J37SPhduaqXSK+ISwIIt83lXIJpry5+3uouNIYs6SogobYa3GCEPZpLRb2jvbzzXY7rnQxkAvoYLEiosVuh2r3zV6c1iCkSd4tvR3VihbIz4mWzilMZlbNj+GR/n3cSV/aB/yb+A8rr1GtPYan94gP7y7+Lf3E1+ig+iJ+/bun5v6JTl33nz7C123GRpegmPmSXqBbtcTJ+/H8cmcVP46j8tR8NL9qfm3wwqcLUedIEMqlZWF7Ud9Gf7u8YbU74FdZBpIg71lLxs/gN4Jxl2w5Bj2F2vXDAWWYXbyyi3RT65QCmZB94IqRWwb7D6WZH8XERbzRBtV+RF8z4VnXJQZbyCZxSN9CrtE/aLNv5MHKFy5JMPPGRFT+7tGEYwAWl3LidnxPE/HgmI0k6J1k056+nc3kUfQ4BNrbPYz5UyGAFmJKGf13hHhKiwAOSLPZvV1+1oPXJ783Ht3et65/E/DOUmcBVuyJ3///C7rHLU6v3x+d9XpdDrwb/Off8xrZ8ASmxvoa5BLMxX8xYU8MrAEpmpXL6M+KGZcVy/khXLhGcYSYZcsXPVNkL9dC7fQESy/pGwYpHHZz/vNAFOiV1qY3d8bINST3y4658e97u+vzbqHyT6eBqpy54YzYse1U9o6cMh6sxPCRtWjf/xydnUKc8HYbrg0Rf2cyjssKFRkpgDTZoZl2ZgIGgOv+c7VYx7/+uny2Gzck597n/W/CqQHuyzYRB4/KCExHeMUCWLzpY3P9YpEQ3Sz0d64qUhN2vzXxtHhtVD4WpCkp9Tkuk/Z9XiKJ5OIPJBnwNrBxipnF68G1UdhlmCRFNfbXKNWWzicDDnLodkS83Ixond1MNDp9wW5o7Be4Hi4KJeer3SNfPjn2cd5Cb4l0xro/UDvSBNuHXpnswT5AFLzS8R2P72/+rVzeXKdO0VOVZ9fXR8Zi8UWPl6fjrUZ856mBJ1AmqHeoJ9gUnl9T5kmVO+7ub0mrEY1sA/IInrsEDhEL1VDDwcnFHR01cJdLy0Qf8wrBHN9TPrZcBhUxn1FQiGdqxTReeA+G3BCe5eXNsh8FOfGEmi1oq2U/+pxU2kzwLeUROmrekwsMtUAx/oixoqgCb3jJktZ8IwlCKMJJYD14ejTeszdXYDxAh+ASyBEg7NxMKlNY4A/YlM0SbH+JGX6hjk56trMU3QVkmCHNhEmTYnVBeMGkgqCVO524gMAn4EpjE1g70YqAuMl9yUtNh9DN1aK0Y3npKMVZCyI8tnlWkKnF67miUgXYnMBPpYQAUnSDcT7kog7IhouVT3fEcom2TZQnFLCVAO5j+pTwojSRnQ04OIei4QkPTqJ0OkATXmG8GRCLL7O6YXT24rn1NPJTQM+qUlS2lwwQgOJYTSkd4RpFpSgdxSn6bSBGNeWvzbB7kfEb3OqYDIMgcT+NEc7DaY6bB9sR61oO2rvucqgZUzpGsO5nTQ1dweWIyLN9uBMC0q4DWctLoN35I5FA2TotUsmjbMJyHG5XO2oWuQjkk70dpJUZTYoC1LVU20KvUUkoKSNcBG5CsKwOcYpleiVQQIjggw4fENvNK1K4TL0BMyPBgLJ+zXKV49vCuB9yFr/KqhkqBb8iTlbXhzh581VQtD7z8fnsoESPsaUmTL7Bvia0lps9ld6k6cUy2fU3tN5knj9h0pcW31+elHJXDHWIGsDYXL7G/CvZhYBfle1CD43/yuy8vdMZlfJXTLu30/cMPoz9rBD2Y17A3FQbxAPsjUpplSGTb3uRNzJCw+146QJsIWOrmgH4ZQIFXDLuAF0AcZyj8puMpgiKCCyo5knEucfGFcqINzuwkOnmx1RyZhKePrShrTgqb7MlL7uZMN9VBMGp+D0uLt1etHN/zCggtzjNNUbmfTdkAHSSPCBTKQWJU02EGGJwX5JiLKFrVpVmKtNEvTq5PjyNZIQS/eFS0TFK9DQOFMjXtce1uaRPsFDzOif9oLkAk0kyRLOpmN31AwRcNThJ61huUGkIklBqcIauh3ndwxo98K+D127rsKiecZF8gw/LsaKDFcauSsebjeBFYs1Hu1QQeElsZ1t7D3lROBlou+qfNO4GrtqUXSUIuOJdr5OAwvujODbub3b2h/Yr8CDL72tw7Lb5XZyqGbyXcrjWyTIHxmRCizFSdZPaYyOz7umMu7D1dVFF22hq7MuYBPymKdy7qulrvLKjuHx9NioLypd1eA9VSNT8Y1kzA3Mj7aNh2Bmeps0j984tVm5cZ61Ydqt9rxySWlMmKzrESZ0s+xM1jI3NtTTmsGLxlStaZcIJwThO0zTygK/zgTHI4K2o7lT7mp9gCKFV1rgE0KBDkJ1vnNx9unon73j825PH4Le1Vl3Xt4EgYebuC4GNy/dBOjL5ZlePfw15PFwrf3qVt4G/q9ajHp4bdGbu9YGWA3c8+amRAmPs7xeuTgbuGv6ZG5u5vuJcZXvooZ2IkKERYxSym6BH5N2YQhMzSOWEUHf+Sb5JWeRvMAIKkcqXd4GYdE9vaUTklAccTHc0v/aWmh5tQVWG/bM+czOlUQ10ISnNJ42jMViLAJIRPS3rna34GQ/6+43Ja9jMu7nUGJ5gM4GT3sXVuX33hvra145ZdkL0f0Q1+HCZzF4GcGVIPM7wThPwWVgMB2+fh0UFWb5Wmi3Wub/zyu7etPWruAUm4y1LSTIHZWzpkOfaK5h70DUxHZyKbMWfYUnn5ABEg5dp27+myecp479nF5kB9mCpX3pgUCW/htD2DsVMWfMLs/AG+rGFUKCDLGAsKwk4LbIRvB5s/59ah5ujT4dpPwe3uVEkntS77lAV0cXdtSGhQ5zZBraYkLv8gwayqiiOEXd/zxHExzfEvVKOsREO6geMKfFPPqYveiNrtmZrIJMpyV5/K9cCzi5QKIctoNDhNL6RwjHKjO4C5JYZH8xRht+vA2tP+BWC4Z1VLAZwqWB/Ld/tt6jVd5aiytMU5lfFnZEQwpgt7OhW+B8ipAPGzLpFiYwfjVwYUcMYM7BOf13xsymgIcvE3W0364aLBct46o05ABUsF5Gk40462ofmeG3HAvFtzUTJsNJgiQZY6ZobJ6hHuCOxQyRB5Oq2CgodSohtDbIUv2xO6rZpX+S/AVaM0qEwoXYm4ubCj/HQDvUbkxmVKi7SEzg1D55SkXTFBETrjPYShAxAF87COKCwAY0Tb1uwpOJ4BNBsSK+pcNSTvfciF4LGVRwGsyVaBfMh7cLWFF43KfDjGcynZpdDt/x2h/ecaUv4k6pVHo1Ty8aCLu4HYSgM0YfkOR6/0QI/WcucZze46k0Af3iVY7vHU3uPNxE9hc3RmRF241p6yp/uk4y114BQuURndxoUm4iQ9ZNAyVkQuBVAHFrSyDOgoikvmZnEoewjAp4i4vkDlmQHDMOwmnKPZU20MEZH/NMWhVh5J7/2hNoNYgd6FWne/66BEsDScY4HuWRKSNKk+VJKm7uvfb+wSzPYXjmJWINzJ+t9CngpDpj72fOhylBZ2dHBSlUJPvMk9v5KILhO0jrASiVAIsTTqLdCEZhlxfo7W4x4ALb+SuULdxBJ4i954inQKXdnJXt11y7OhMEZRDiw2iQ6cvlD63DAdkpGNvE8Fn+fpA7UHhsn/7G2PbWyedIiKB3AQS+R5c2mk2bNkQrEX1/CT7OjVYmaDzSjlzZfRpwHtl/RLH9jpP1kPAopmpaV1OmI6qm1bvyI2dKEJyWyeFMUUZYFWbQSmi6uufN1CRrAb6Pv9hdLgnMvimr6T7vzOsAFJmpScDnhW5XdrIy0VyoEepA9hGuIDJjSkx7VPK6ZH5kpkCn3U8g9BKFR51Hyapra1qSKlf5CDOclCUFN1vJqSuRMyS8FwLnF5EHORtSlSXGBEuxgn+UY/j/jTZSzjYOUfPNTrTf3n2702qgjRSrjUO0uxfttfYO2m/R/2yWiKwxLrf5RRLRdKbUTMwaIyeeBsImimQMaz5AQ4FZlmIR9rVTIzJFMYDYaU+igClnTR5VjANSYYzkmDDzhgQVHCk3qXV9InIkMOet5MaFIS/NgXVNrLiBYqejwsTFcw4QlvqDxqkCH0TbLGOwbYaEO27LGrfPpeKsmcSltZlwqXBa1ynbvIDhjVrDUvKYFvMEPcmFVl+ZNpFza99mpfiUGn0vufjeLeP3DHI5kWbFgLEJ9PvpBQp4QrC1wZS+w2KK7mmiLTi41eyphsdT82NZfge7rd25w9BarIIMKWd1KrBLmOEp/dX8fPQYXTVpMEtTpQL7nJE+Ke8/7dX8yWe7eK3mWnXlNnp8/3DhNYLLdj3tnHeCz1USby+qrY4YwrWMt95lhHHZ61BB5n+2mnyFy+psiDwxasY+fHV6cberd/vpxd3+62JOxBjHdZznj52jamJmgvyM2wC+sSrNSbt8f4TetHa3AX00Gw4BxfkQnWjniceKKPTKhl4b6G2zT3MTVdv4r02PR2sa2afZe47+lU0mRMRYkv9CI/KAXeoxdLmTaEjvXKw1zD9EjnwzsUkGz5jtVUyZIkMiItTN4phISe/sB43rLskEC9clEPsRR9PJiFRo31ar2Wo1907gv3ea2zuFlWJYRUvkymxeCcykDUpBPV0YROljfVGcd658bNLiRVLrneaXH0cTQe+0uj3++PvrYDmLlw6o7pTjBPVxilkM116QUsEFEjzTt+GMY6/5nPC5CuieVagWCgCqhF+uCEx07xk+brFU0Xx7IY+2WLBXXoYliyit2EN1gNBs1RERJOlV+dIrbmxOhyMiVTCpk5GZuwGMTCYk8SRnffOnmTIfK75GUAICw1mvWlslGzO+7IZWUhvhLx7vnm4i14ANC7CMJKZSWyW29TlE+lJ6a8tFTf6EzAYD+uBHhM+8Gik1OdzaMh8xn4i4GL6O0JVJLVXcmFMPdOwf6/pTJOl4kk6Rwrf5uprIYIqlAuWa4j5JpbGcGFeQGmgQkDX3V2fH0t+jGzGPstuNsvp7zNX3Yq9zN/hJYNN7x+CJQInLSgsM9Twd0aQAkoeYTIxD4cMvNhWiuFXsdo8QOmXaQsVC0eA5AZUoAOVh27Tq/2//bjPXvPcCbkaW2sr4GLP8PQEV91UjkIBtiSDLDPVJyu+rt3n1mSiem1C2G/f39xHBUkXjqR3BbAxzMrBUG3kX91PbkNaMMsI5SLbh1ZQ7uWlym21DZv3tSGb9duHwNQqbOCevALBspRCMsdEwZ45xpASmqT4yEyIor2hXqxmY195TfNIDNr6B1iODAYEexXpWu1Es96/I1dnx64Zxmby/lMvdC82ojoZ7bgQloLes2yvBIYnKCnJ2Xj9sUGGsVwn2wfetGUErPqYU85WYTz3C7wv7JpNERPVumTBKl5cU+0znIIcD8cFj1yJm6Oy4c6FVVsdwfOyHCvfKZpk7MsY0rYm5L5oDmKDYRKdAgNaeK8Yu+cbvLJrNTZlfAxBqeiKdLu0TodAJZVIRu7EKEoFH1L9s25k8mtr3nWGythyix7tz2Dwhm0YEDztbLqu9YnsaOmsMnIYrYSYrE1EnDJSVFGgbqNGB8JswNVCFhENTYGXUEkOYcTYd0z+DjHQjQv/PL5IMslQfhhvggibmVRr+obm78SZAzNnArNVskiNLKqwq7fxVbaqvItKsZivZ1YIpZ093t9lu7jW3283t1vbu9u5Be/vN2zfN7f2D7d3tg93WbnN7Z699sLf/5u1+s91qtcpMrC4k+I31YHekvU9m0exTPqTsSVHhiDyqAwVPa8Ob6Lg6StjKMJN7lYDoo6X50QKKW9rHDPdwMqZso4E2BAGrmw17esCvVlWEOXMOgDFImnO/erKolbhvl1KwVPg3U0QCEYo8MzxoN32PJYp5mpIYgI/sb6+gm5odGMr9pjxDA8oScxy9ckj5UFqt4LvuuLmhHNpkIdqTOuBcMa7IIaqg376iS5IOmqapnHXj7Ocs1ln0k4HNsL806JDRTyhPfPaCMF/Q/FsMSfsln2HjEqaghDfm8PSuBZapITcwEqZoooEg986YkunU7YgP/J5ASatyjWOkiQnNQ+84084qV5pnrfu0QnNrok1r4wFyrmbkRFSeE2szfwsrb3L9ClvLdruzabDmjSdvdiNzweQk68XQasBD+hUlZocN5eYLTLjw2ZiztNmEBYPsYblySanm61G+AcvU5Wv9FfL0oKVFdDSLx6izm+RJyl5wjlvHPgWYUlx4CnVJmNRvyuJ5B70w6/oFcCjoy+VpXszn3hpe0cnd7qEJ7wr0Lzq52/8v+Odrk/wmiMki9MMCTsQrkw4nq/ogvdmOtvejVrR9uLe7MzcUNWF3VHA2JnP1oF9Ipqd5WpmpPPMzWjGHupZKJDLGihBFNqoCcGDugyJjoII8Alg4sESvLBSheRlTeEjZsIE+dxrBdXxHUj4ZQ8ETUXH0ulGiT/vuvieayTTSVy12wDWOqjziYE9ZDlJm7GSvNLUaC2o3ZRD31twFk5eXOGdp7tWdjMiYCJzW2MDvxM1RMu2CE/OKDgACiDxQqbfvzHGhCWLaVk3TqcUfla7JnCAAKChNB78bJ2BtBCecSK39y5J6i3cHe63WoCCMWqzaiv6FvkIAtnG+JU4Hszs95uOJoDIw/fnAgF0wnhCbfVFgOdcrfsuA4QCBm4TICsHar5SaD4bEWASuMb7V97pCEy4l7RuQPG+n5KEoba/ojTwmStDY2C4A8DRjvRQhI7ThBAHjOEuxAHr9kGRMFSS05gaj/9s5VzaxmhpsC0bMlS0Jyb9gT1KBDIhl84LY8/MfpHCbimnjzGKFbvT3rGelHS34p5Y+mNm4Ioia7Lwhe6Q/IC1M9uPdgzfbSZ8cDFrtN7u4vb/zpt9/u737ZrBf2I815SwUYhJus5nM9ydvLVLK2LS71J9MsPMBGMTuF5ym/N4sv+9zH2xmr/RAqiIDLAEfDweUiaKXbCwFV4/g9Cy8d+YnhPkwf3hDWNMFS+DgJMVS0dgibxROkXOYw8i5eWjMpPJJ2igICr8jWMmqQUxo1Sph6Lw58SiG/qN6IW9y196gyAz0wTBvM0Hf0orgfMhH0x634ibiCak1H83tJuy3BEw5o2eCnaDuudFF4QUZXtvMps/7v8ExDUouQ1xPSK8CQ9vAmzSCRXCse7WYp5P1XbdVP6i9TjxlDuLGjTbfXppRyQEJ5R01Q4D+rFnzoP6uuFHtHow0CXp6WWEg6UuPbW7mYQWA8rZ2O7ziAXN+tsbMqx4XjkgLABKCjueRDA4nmrJhRuXIr1p+KOFI6/sCZZPCVW/vOS41qSgMOFk8RysXBlYw5C14lVC2rSp3Ta5g3O55jZpGK3gZW6bGmJmiLUkqzAQ3X7Nl/9Muamjrya19jtX6HFasa9fjR3Y93CKvPZDniGvto6x9lJfgo8y/Y9dezNqLWdiLecY2W/s5az9n7ees1s+Z//jJAFx3pSWCBrEZwfgz0pgbmNPSWBM+OjwTO/ykZ99G8MWZtl7GLq94Ay7YK95SKDxCGk6CSU7cIp8OzCBc+DGwIDPUzZ7yRxT8vbPgbgq6++Yruv2ZC1aJ37OaNful2GrOLZl7t5/Dm7aaXnGUcn6LsL4aDR4oUebZdObFPuhu5++Qsrx2ou1o7jaLf92p8+kt5rV4HQFZbQTEinUdAfmRIyBukdcRkOeIax0BWUdAvpMIiN2x6wjIOgJSZwTEbbN1BGQdAVlHQL55BMQevxcdAbE0riMg30sExC7YOgLyNTmtd/RL2NFPIm7/TfarD8rlESJXbpT/5olqI/MpV6jj2hqVoJ6RJKbOZBCgdTuo4y0DuBIUeMhC06QAe1lvDFyEg25YvJYQwB1AL62ZEIKhGKoKRITQ2HkPv5CpAF/6K8jSIZ6NhZfOeXoMQDmYJeZM0gRQHbTMtGuRUkbCZsAGJdiO2nfQy1BTzIp8y8cmdGIoCtPt9RAp/DSodTOhJz+2izlYyAuHJGmwr11Vk3dYoIzONJOr/pyTgufSwMZ5cX9fONJW7msc6TWO9BpH+i/FkTYn0bVqz5XgCwSTNqSuwaRXL/I1mPQaTHoNJr0Gk16DSa/BpNdg0msw6e8TTNrYhy8ETBqIWYNJvxgwabs7vgKirLUyRF7y6497fOVKIOWgtxtSAkNskQ1fPLD0o+KIlpTHCwSWnt/F/Ybo0lY/oJeELm0EtUaXXqNLr9Gl1+jSa3TpNbr0Gl16jS69Rpdeo0uv0aXX6NJrdOk1uvTfBl1ajQTBRso22+sq/83j2V4b702Wjj6mKZaSDqauAAaK0FIi9I9xzEXiDCs7F1L4gTM+nl5bCq+9UaQZ/nh6dXmCOldX/+fon9cPnRM0EHhMtE0VXbNSQpjWBprfAiX5wJYOk9/kvRwqbAjAxcROj7sNdP7z+19trZ7La8co5uOx1tKW5CgfGuLLwFCkcKxoHP0UEjYmmEEjf5cIp2wswhrFrtU+4oN8TOXHtIRdb9DxBMfqeuN1VJiRxCNQCE9Pmo9sUnBuKYMoB9i4OB55fOj+1D1TKZN/aOZpwLrFMR9PUirhzSYfcshx6skkLIEXRpQQprWn9tNMwqEmfeN/oSVTtvKEgnnOo0EGrzx1xhPQMsjfoyhLtEvNhUS8/28SK2nnc6Fjm12IWVIw/gMgaYhcuyEpZ1tBBsS8/mHAY+RJmoNbQ3MVt5/gL0H64yNcV3C7HNXR3ymZaxkhvfTsr+USvBaWjLPE4rrs3Y28bvy6p9Va746whIsmI5kS8GbtKLjuCQCjv+5lEv4n0IJaD55zRrbO+P3WR5LQbLz1gQ5H1z0Z4zRP9aQMdSaQEfmAOu5q716d/oa2o3Z4wwXj/mII8tncOUUIBvfNEEz+FZYozqTiY5dvfM1OHiagzcNR7+z7uiCH1wyhnyCzoOsq+9yvGDE/nfF784PhzfysGdyYXXnzgeVWPVihmpb92KVhmKvWFkiYJGct4CSL3dtiftnOsnp6gR4i+H+QtG8aJkD+SUrviHBqtMOGKRHo5J9LqlIIXdSMUBGgKHgzzVNgn0cLIRT0ioq824XfX3xg/XjK2euS4CYjKkf/bzb+v7BgBjQlEYYXWjKPB5/kBW5F5/MMnje00Q2BdJoaLA43dGUROOMKLEr9aXO/SHRLyAQpgeNbs6v012Hc6CvG+HycKlu4XB9MiXNJQAT5fIEAOv6X9qkgz6rpT5GPYKAPRJDNTQnlm6xJHkY4kxCaceaX0UOBOaXtJ0EQsTrL5KW7pJ9DhEU8onekYdAKYF0aecyogQiLxXSiSJIH/ckDiTNFGmhEk4SwBhIEJ+a/9TXXsHZBA90LqioKYzb/teE+qx0s8+mvOlZzrWXME9KTdMiwNtOjhA6JVD2cDrmgajSua4HhPRRLsPT8ZL7ES9NjQuiCxxAm9aKcCciHBbWeCyjx1T4T7B4qkc2m8E84UMeQB1CmQUm1/qQA8A0b08+BV4xscnLL6yRHeHtv7nLX+VfF4Ih8JQjS5zwlmFWJ+535U+gEUqjEcjNoIdmElrKHr92hGraa/omyYY1YLXqTBWGqeXdX0KqogA0S4NlADfQAx5oRk3yDJB+oe603jPkWBWMSB2IgkSApubOucWei9+JPn7pQMlf1/jKO9JwkepjE0UTwh2kNq6CwyuqEm6L6x9kopi/Bi4mwFVsEGVKqV2OQmTeslA+HkI2uVSwfCjwZ0RgRIbTz6LM5w1HvcEqTMLuWC+3gS+XmQ2cE3xGUsaCObuDytOCr+VdcPnk+vh9W39MZi0ckvq3CpTi5vPx02ftyfnX5pXt1cty7/PTpqobVzMDPriuvsmuGL9SIQKarUZslS5zGgutzgY64mHCBn9VQZ26mFcHjmrWInmKVqgTG48LqClsW6xSIxb8Kep75QZ+pQU4+f/jt97cf33Z+qUHq+g5VeDxPxuBj9u6xPqCYJcbkvfeVmG5LmbtJn6whYcRkOkJ9Jtzn5Ztqu7Xdbrb0/121tw/brcOd1u813FygC+Yy9J+4kze7igvn/gX6qELHoHhUzHf5RSsm05cu//pj33POuYmdQuCqYYQ+orkRVMhEcb3qco2obSTOU4tRgm1nQQTqzlhPRpmWTdearAfQzUuuQLVZZJ6Ah1ThtGggaY8aMqLwEFMWFK5BXTRl2vWAQGwBravydsGFZfrK1bE6EWpvejkH9b22qGEcfY+DRzmvTwrFv2aLFr6/Es70SCt2vHOv04JCYFNiA/AA1a64A/OzcWEYxu4I455mE00BuhnrqW4sJhjV55JIdANcBDAS+hsQVfs36Bv3KAeOiv5oA0nKYj8cJCmwnG6fj6cltAopJySA0lh9QMyiUJhmoUEacchGyeNK8CoYc957Xbz5LJAce8r5pvYtIUKnvq7d5nkaTSTCqJapzmxY/zavTCrJZWvEx2QLp/l6LSUfTUTPTL6siCqP3zEU99ra0ydkdJUXX1FprilnD+X2PUO/UpbwezlTC2KiBjl2gK/XMNeh1lmh5GOeVpWwLfmeAPIk6SCCMJIi4ELWtO0+4ngEUB3BVO40nZy9rz5RD2/3m/tzowZ9hcn+VJEeF8ny2+YRFt9NteNI/sjgNYIPHufrjCqVEnTCEopX4YRo9uJJ1ivjJq2MuaOLLwXopEd5O2WKpKviyV7OvaSEwvasK/XkQQkM8VNQVh5B1ReJ6Bk3pb9YI3RqiLHhu9znUWAg9TOaKoO0N57Q1GrM2OEP9wka4FtjqY5xCo4UsMKFXPLZw4uGPEy4mOvZf5BipQirfvk/s4FnMxxJEEkJwDCbODaY5tMJWRnZI4ITIiLcp71KVKmV7dcZYCm9XTtBzOydMZHhHXuAY4Jedd6dvl4xl5B5UxN/H2AKk9zz2JlcERtBy/uVGyPailVYn8WABzvvarkgTIlpWO+/sgI7uxT5BDWvh8lsqfn8bG60HtobBj3awZs4fuRKLPiQI9nDfVoTJ18/9m69zijLHtCn7orXq8Zb2W69py7mFTHxzbX1apUAHa/0ujTD1X9dSltbPAfdj2f3bXYYwvCIHaa8MZshZ8pxcDxCdrLyRip4LrdkaiNvufdin+ssapokPmEwBHbrT5HM+k2Th+OHhIJARrAaoZuQ4+inm5VpGT9mPKLbi2n/Qtpe0TAe0ab8I7PB8j7u05SqaY6oXkC3jMPC45WxpW8ePpknYPAszrojzBhnyA6PYpzGWWrrDXx5dU08DVI8rMt20ju7azc7nGiYbOUs1FiYUuIgzDtcGQMOhKfHBwNJ6mpWU+LFzFYfN5L+Oc+6PAdbr8SDxy/Sk62clRotihInBqR4xQzcUaEynPbmz997lkVeYsLOVyx1rIGfxbfWAsysdmsNly8jmN/QgMn+akPDcLxaQ8OO+Qy7bdFDaoXqZOm34qpZqVnXzLCxUm0zwoIkvZT2BRa0tgRR5xOY6ZCfrlhDlPO6KvYUSQc1Fp+64ZGcjvs8NfWn+giv1MHU1NYZxYA3Yj9LA5GHOM0Sl6yQEgw/J7y6p5XPAoHHH8g0KsBv+IEleuUSuBUW0fDP1w14EfJjelQE6APg9Zl96k/Qq43hnxsNePfZMCNsVPQdnAQ3xOJCH3Bxu+Kq/plrQO+TW3gCznPuTH/HGbhDHLzK++PhhfaJmZBMI+jR6If2aU/wlD87UWl0P6idBXXsy7pBA9fLipXeEHaNYBpXlw5DWvgW80CXz+cH1t8w1SeQIlvk4/zqfbdhNAG8jOOUD3kGr/OYoU4KaFuKmIBoVwmCx+hV57j72qXHELfkflTT5MB81JR/uyfKf2dSaW8tJQn638edq06EfueMRKd50phpcDeGV8tCY6D+1L1YQuYApO3btAGJEn7PUo4dsHGxFAx1GOocd+HB3OGB5VK37+ZcjA/RzdHh9QSr0bXi15pmcF78WTqUfEx6fpPeGAnczPzWj2zf582DamguuCQXdJN/K0I35Qldeyk/ZPBNfV5CKkpfmv1w/gHImct3R5am0Fe0YT+of75pmMSK8KkXNl6AzBAc/3ARA5d9cU0wrC1T8ULQMRZTi+hweoxe/Xx6/PrJ9InNdqvVXoUlltfS1s1XmHtaydOqkh705RuNk72auPp4vAf3+yqudBhHjnC7Jlq7HzrtlRObV0rUQO723v7KCd5rzxPIXJDgvfb2igmWCSF1Hclu9/jk5GJlBFNWwiZeXUEhK0HZ56lFuaXpehzMKsjtvf2dtzurUJFjOiZ1Zot8PP14Yl6lXBpZmHFu8W8DxYm4cKYMHxTCbAhBfTEaKTWRh1tb9/f3EcUMR1wMt7CUdGi6T2+NSUJxE15fwp+jh5Eap/867fiWANoYGdCY4tS81fxXw2Z5ubSQCP2q7f6xSRHFDOxBYIZKV3jTt4BufswxlyrvMRWy7jBxVrFs9W3NjwASPijsRx4rnObbtRo1dbO1v9tayZ5cMm+2Im3W57tqp40npk3rCkj9RpBTdh1CjzVwZbWL40qXfNJoaXmcW7oa15Hfs9rS58BVhwk2wbsTlVgKq7KotPm9OkZWjyrx3vkLYQJ3Y2Y3+LBERcZuIfSQBDmtz8vY3VrtBpqQb5FsenTxpZhoahpven++Otl0JZmmEyjhn2BWV/72qUfmNtOUnJCGa8QLSfo2Q7FpyiNmWK6rKG5CvgFqhRfETIeTBeRwgcM2MEvxrf+35tQsz/hdMWvmmUzvRzvRwX6rFbXf7Lb3VsM9HU/qxEPsmCi05dfm9ADgIbo4MacadRiyVKBmE4Ci4WMooAvpv8x0ch1QNiRiIihTpn4KoI/utKIcKCKQIEaYtp2j66QZ84Q0gc9c3wrMpK8vlqZ/N4/jTAiSNCzmmGnLamp0rKUpsA9hAvUGC6JYvSWsWYpVwSAeUEHIFDTPVj/lwy0DVtLUdpvWg1vbrfbuVqu9BfE8yoZNm5bcNMJpWviDSNvKFb0a4v23rZ14lxxsb7f1D0mM9w72dzBOdvaTZLCavePSDHtwhGo0sfz5WUZzdi86p+dX0clvJ6vh3hba1s2ynWYZ1jf8rQFAhDZaDD9/mhCDCoW6BhZkBbJ5/pN6xQuQHkTrCfAsC5H5oGrH2EUmOqsHhSK2Df3PCkDq9v7O21XYC8Yy6b10c/TKGFBgkGorSk7HKWW3K3lurjEOAYsPzvgrs8sTKqB7gKW/jMakP7YCnrLaIutXDn789Bi9+gJBdYEkiTNB1TTEBXjVnYm4G+eqvrj7w17rIML2KYveGR1e+6u7hYEI5rXJuK+6nfPXkXGoIdDjAZmqIDNwpkYcRAiwxkFFNGyffqbyBzOHjJw3oSKygY7PuyjkGKFXtsFaEmORSPuUVwAMyzOF8+X4KbKd2KOYr2xZqJQZEZFhoc7L1a2LhYiFe+bV0TlsRE0EAKYE0vVyLwnCtsOHCDqg66GOlJnALCaoa5piH83d5nNu+UCzu9plY7rrvTp6DQaknGX9S3fFfAXQVCSpc/mPw4ns6h8vsvpH//jSbaBP/3C74JTFDfTpyz+gcUqOlddAR+f/eGKn+LNY146BdpU5pE9dW8ZN43Tb2euSRao3ldZKv1Byv2ImQzzWmhkNp5Lo1aclFMcpi2uUA057GaN1GexV4sAp0jNqqXxZQCwzJ2fFopEKQ3l1D/yI+iBq/b2v59Omj5vP3/pXDdQFG++idEaOcEoHXDC6CqAG4J5x1YMAwRzsPvZwcUXH4NGbyMAsbg6ViHFwbyDcwCRNoPEUgM6UFnm7td1qtt402/uotXPY3jvcOfj/Wq3D1tyNWudhuE8GfK7Y7cIcD6iQah5u2wfN1lvgtn242zrc3lstt6YnUu+WTGuHoeyUkCcdzlTYwOmWlA/2ZXdll1rAb5yJu7oOsfZhYPwgR5Ygkqb6A7H9U85xAHMJqBv+UqcyAOx0j68l+TAq1WRvu12DkMjDhDPy3JLiGWwCM4Rf9oTAY83MonsMtzkY3t/b23njFoQl5GGmMQiPeya4ONswZHWCWTJqA00J6Z8+RBXsBTnBsYnlUFX2nrZbu29XxY4kguK0Nzdi/xINbMxUDosfrlR/LKpvd2j2BApSKsLiaQ7w65qrm4xI2DGTEWYZtGtuIBrWU5gwtUvH5eDkptrw0p6qx6TxQ8cjDDgaoiz4vb33794dHL05Pnn3vnXwtnVw3N4+OuqsTDN59LPaFfFpsS9VARXUQ7AFGulXYh4HxkTLTIa98IxJMuAZA+Tmnzk6w2yIjgAw1FYKTCPUJcSH84dUjbI+RPKHPMVsuDXkW/2U97eGvB21d7ekiLcM4uiWFgz8VzTk/3G2s/Omebazt1NuewQpaM0VXhM26PLXhBOkjyc4MmYZNpUY0TDlfZx6m5eRVTzxzfD/V4QL6osWOL5eQrighMhrA336cD4aL+he/SO38Rvo7B9dzNB7gVlMZcyDeEJDe4cRRA++2W55MaGCglBWzeVfHSt4TCkUFr5Opl9AYGBGBitj82/q5Ns8i3otwQACRU9qTbPSNt5ZlikhVU8SMs/ZfNR9NyVRs3DJlCntwg1NMx5brQWOvOnCRwAseaSK3YK1V+PJm+V2E+IZ7XaztXfVfnO4vXe4+yZqtZZGUh4SHsVUTesCJz9y+recOMCZEgQviZAH9HOmqPYce3EpLXglTFzd86aFwYxLeet+9k1Zzej5ktZmkcWa1um8AB5vJyuzwoUaoQ44UEsqFcMV2HM9KnldS3dkTcbT7idYu7K5tJLVMXzUdYYsD5W76wgzvCzcruYB0FxLlkqJ/iHhvRA7rnhbcTakKkuMPkyxgn+Uddl/o42Us41D1HyzE+23d9/utBpoI8Vq4xDt7kV7rb2D9lv0PytQbXUW936RRDQd1MJM6iVGTp4NBxBt+nryARoKzLIUi7D9hRqRKYqx9qf7PAtLZo9chEKPESQrUWHqXmPCFBHSNNUfpJwLGzxp+PhH4rqa+UENeWkOzmJ8iQaKvblcLAPPC1lNdI4yaKQ4hgLmIeGO23J6UJ9LxVkzWfIFSi/mhEuF07o0xeYFDG80/Gx5Niyg47EA+Q/d8vIMcptZ7QGRx3jqoVBvGb9n0C0NaVZgIi7Q76cXoTeLkE2WsH2z7mlC0qkpM3YOMHS/hh/LAj/Ybe0uGfbXwhZkqC29GlXzJczwlGZufl4S1zlgpCbdbJmoVM2fM9InK9j32rD8k7NaLBvXTlqP7+7/XHW5xsunnfNO8LlKbq1VsNURQ7CM8Na7jDAuex0qAqyohaRA5ymZ9B96duvCPH2p2LcQ8YE/lE/0LWxH29FOtGQOZIq/qTsCEBwvzhsZY3FL2TBSaV01shtXAg8GNEZnmmV0IbjiMU8hUqqteUuBjNClC2WbR9e8K2rYLhX9hH79cHp1Yjqf/nx5cnJufux8fHdyaX68PDkutUP9dUTVkq9Nroyvh+eJsKxqy7hZw85hXh7+7vv2O0dv5DnE8Ngz29e1BKCe+B7RC2iJ3d0lIxY2L72usMtV0b7M2d+ULiW+vJyp+LOXiXSEsyU1vCDQz7G2QNqlGx99uTxDKWW3UDrIQ7ycqmZ4T25m91Rly9SDnKIt/6WtVqvV3t5Z8nbQRozU5h6A8odl4St3az45kGuYBZqfKsKMGdzHkuzvIsJinmg9nVvC77nwcDmOWMRNFQRnMjclukSB1XzyACrnkgw/Z0RM7e8axf5XMYdzxlni2/dYBCKtoOBh+Cad9PTvbvLEAD6xC9rPlNu6QWNRE8gRJOZ3RDiUWmhglqPr+bZQWqVdnvzce3d63rn8T8O5vxAqgGk+v8s6R63OL5/fXXU6nQ782/znH6vcAQYk8WvNR13GdeU6H7mScK3v9SrrA2HGddDNXmYXXh7GYDLYhlXfhOWxS+VJht0hKRumuZNjP+/3iklMf6Vl3f29ATI/+e2ic37c6/7+2gJG5QuU00BVXswGkGIwrp3StiCRJoQOE8I+1qN//HJ2dQpzwdhuOOju5Ee8w4ICKH9K2FCNzLA2gwB4zTe2HvP410+Xx2Zfn/zc+6z/VSA92ITBHvMeZkJiOi4BDaBXJBqim432xk0FEtrmvzaODq+FwteCJD2lJtd9yq7HUzyZROSBLN2PuLjvyhUqq2lhqTBLsEiK28HgZFpd47G3ZgVgdsyKmBzR2VSvlfDX6fcFuTPxBLhjXf21nq90rXz459nHFfFzS6Y1sPOB3pGmIKkpMoFSKD4AmNJyysWn91e/di5PrvNaOXdNnF9dH2VCEKbsi8/16RgPiSlVOoHm3Hr3f4JJ5fU9ZZpQvalXJJxyqdlKpPM+BzjLQQpMc3JI4WP2+qha9uul5eVVTIXcro9JPxsOl0VM8wIM2ajrMcIkb1krpLS9VsOQjDFjRPSkwnMB/z7mRUCgXhPe+WXr5PjSNpJ12L0ZNPwfZGk6RQlRpo39GKc0pjyTYb0dtEP+cnlW9iGW5NO6+cvweG48IL06dAydUkMTGQBmeF8ScUcSrbKTLLboTuBTQY/SqqyV7SVDljWWTm5c2aYiBYfQ39HGDD2aaql8As7BYKAMda9Of0PbUSsKIwblsMKhCRPgTHHGxzyTTeNP2F8LRQc4VuZfHt6mFIZI+BhT1tQyMh+F8romThJh/q33l/mJTu52gz/Qyd2+/efMmGMcB58bZ4o8mB+1L2x/Mi2WzT9cs2Tzr0yk1wyFQ/4E9ZpNHEPw2nzq3mi3plMqzVsyNX952GsdNIPEp1IQxfOx3NbJRBoZ6dXlUh3D6E6jZSLNrcYNKEbxZY4bgdF9ypDkY4JiLCEsoR3XMZ4icJUtXurphb5Ptrgw4QmzPdJpjomEUYE15EBymDm4ptEioJfmMVADyTDkzo6/MUPcmCeNkELDkCbMVoJqOlOqiMApOr242/djEhan3Ka43/zrxmCI/tcNenV6cvUeXb4/8oNuv9nZfm1oCj+YZ9w6N8A9q3joYAsc58jNw1JAdslyLkp++T1UO4Sz7zyeS9tXNPvJczxa361KaDco6IwMG3Ae/Oer8uiuwackCtEBospg/MqG3syMK0TuiJjqKQyS8Mz3ZwZ3006IoDxB40yaLsV9h/ZFEuNyEZezlpsE8OE+QRsTNtzIk74BODrSv/t7AFnrnTcQGEDj69p4FwbwOVBgFgsGjtt/3ATqTPHJxswi3/zHjandUmiCRY52aIletucACCBL0zmYn4mHrB4c4nRg4I2/XJ6ZTgwGVQYzpXXplGdC34C51p0GGwfgu/MIAWXoxrF2A0hlALOjCr12BYk5k0pkYEtCemDYFgJge3I2TBz/0ShmUR8e7u7ubBnQnP/7xz/s782//0PxyfJr5tTTS1i3zS/MP2x4tQnbXCJJ4D0kl6eXY4V6oQwxou65uEVjzqjigrKh0VreKnb3eJ9o9Wi3i4XexDLcABg8BpTyoc3v0F/VGnigCDPg5qEZap4psBoVDmC4X8bEbkX/NT8slq6nqyO0Aeg3KTGJp4yrsvZaaOvo0R758/K7aoKlDBTcylGv7fBOidmrdcn8YkP4XLg3T4V0L2wgJiAs0MxW5BuroPXZj21fb+6j75hHid/dLRfzLP2epjn5IyO1Va2AvQYT2APo87CASfMXG5WuYtyfYb16MweldMf+X7hjjVEXNiUIZ4n0/YSL5jrj+rugXUQefjCl1gHtkbX1hamjg/n6mfKfagSTGWaNWehHNG0oGCLjicrpAdLNJ2/st2fQ6BI6gIc7BclVfaLuSdCFWk+q7rlxkVZhSBgvlAiS9Op1Ca8g+DscEdDhblK4c8zEDRDSZEK8rpFZ3/xp5tmtYBsHY5kPQ6h5Y8B5+Dy5AYBe4S9mrwxjY9vFSIgiYgx5jhNBYipJOnWNRVIqFUrpbaFOV2aDAX3wI8JnXunL4nBry3zEfCLiYvg6Qldi6l5jJxPBH+jYFHtTCS2s6HiSTpHCt8UMFWt+6/VPcZ+k0rzeaDsTLuB7kqbA/dXZscz1YMyj7LYCx2xliBB6H8l4ROrLPO3C6I+reriWZ/0f88J/c1hpjBt6H7ngVyAOt3XrPE5+EtegxiTimoDrHxlOjX1nPwOun3Uyg8TYNHUiMQU25CEmE2MljbhtL2k6qM0cN6svIojbYBAuLTR+maUAinaomcsoT/i77c/ss4XBVdJGIswcY8Z4buAWzmYjkEAejpllqE9Sfl+tKqr1SlH3hLI1sSssVTSe2hHM4TLaBUvljQ0fK7KjFHxu4FXaci2v/dxmlll/W2+gdkGBNQqKICfPXD7Wq3LwZPkYGyYypu8tJTBN8+BDhULAcsnKR731FZ/0gMFvcKGQwcCmJGkz22whK5dX5Ors+HXDBM18InK+IrmTCIq54boJgYoNNUVwfCpCM7Pz5jG4/JN6/WCHfN/3Dtw5j105+UrMd/nA75ffbA4svqZN9sUOv1rvZ42W+NLQEtdAiXOK5ofASFzDI9YAj/ijIyOuQRHXeIhflcrfCiXh74SC+LcBQPwbYR+uYQ+fJ5814mFZJt8v2OEa5/Bl4ByuIQ5fBsTh3xjd8AcENlxjGta5R16Mf79COMO/G5Lhjw9i+PfFL/wxoAshjZEkEVZ8TOO67SHz+mfmCgpfjIkJqY6WHo8lRdgdFZyNw4xTwhIo1YYEQpsXCTmUJZH0cdL0b8gLhaCcfODD30Y85rHZXmclWVVJKZCMl9YzpARInNDfX47w9t7+MnKqrS/bjJB6NClBSmg/NLO8a8p8U+9qrgcHO5iQvebbfdxu7uL9drPfIvvNVpzEO+2dpNXuL9S11UtC+8nfShh6rmXlQVPSJ1g130atqNXcbm23o9ZetL3TbLVarfZCcQ4nixrr6GZEoWxVHcxsDkuMTSDEZ1orKPAxdAa58b5gfkjvIDnbinFWUP4PPZMDLrL5WxoOBB4TfRRrkkZY42qLI/2UeS/eTJjmulgR7YP+aXB64hRLSQfFWhSFY0Vjg+ND4pEJM/iHegv0ZGaKtJVop7Jj0bgI5WMzMHwv1L6rWQWsB6hPlA1E9H1gcCEUomxIpIKyULjQBVGCO5CcsHAGD4eGPVjkcmTh4+nV5QnqXF39n6N/FtZkKHg2iXBKcV3pARtXWpPrCV4R6T0VmBcCBBgKn/gAQfU71JwpkcGd70pbw4JH2NXY9DSOb40YceGh2xYW+LaV+m/a+ptG1+zXEdQocRUOKcgfGYX+yVOewTJlkiBcEBq0oDVEe16iUvnk5r/Qxkc8JDEWCv0Mn97fQPPjRZjVqO0agaXI745nrQEIPpTa8muQCz4cd641oElZ+D+3Wj5OO6eka/TUN2bBn77jHQ+ZaSV5vz89n9taMuKuG5EKZC4KsFTPEH5hc4e7fnHh50NWbPr5ZF9gp7wILu6JlcLxbTSmShDtbG/Bt+UWHIqteZcpd/CwjOZ26x57e7BxeRuQxymAYJprCqx7X51vbz9TvJD/2gvP/NkjxXW656+1P/9HRtKgK7FEBMcjb+5zsy6mOzkpm3ztvfb+wUKCCSMLK1Yhq6/D+xQQW40y+jPnw5Sgs7O5kWFzacScDfQC1HekcxP3uqdG5LpnEECajGRKALawo+C6Z/rDX/fgFcF8PDjT55yRrTN+v/WRJDQbb32gw9F1T8Y4JchhTlOGOpMJYQl9QB2nPCzoRDu3JgvwE78Ygtz7YUARgsGlL01MElNSGGdS8bHxdWV0zU4eJgBdEo5awMdE6CeAjO6aOUjifsUsMMQZvzc/GN7Mz5rBksIwH3j+SgerUtNSHxdquZwvgwH70eGe5C961uyeZe/0Aj1E8P+KuCkJSekdEc4L7LBhSgQ6+efcEcBcEpA2Gtm00fodu0chdgvpq0X8TL+P+OAp/MzJiMrR/1sgT2mmkYkpqiXzmK2PJa1snmHr6ORQAnoB3dCVpcH6OsVpCp+2DhS6JWRiLmuze6DcV487fy3bLHdKCdrPasSH7ABQHh8YtvP5AqY7/pc2zJujmfenaJJiNeBiHKEPRJDNTb1rGGdN8jDCmYRM7NSGjY1eCdxca98Qq4OMPeMqnA8RFrEB2or5eAKJ0kkjx8xpIMLgNZUk+eM5AeAs0kAjmiSENZAgODH/ra+qhr3PGwBBVVEhtfmvDffZjQbaMJ9+BurezPrFPCE9//QcJVT71LU/g5tyRCwh8IRLOUiaHpOQYdB+Hi/aCN4W8wd0aVA3LBYIlfAXkuQFQIAk5Ye0OlNqexU+KSCN3tZ9+IJQZGQTvM6X1uZ5QdKnV6JQz/oY2Fmf85RgViXid+ZPAN1vIEIQHWgD36cZUOngG8rJ/UpkZEVbSv9E2bBXm+O+aRx3n1gx7y6i0n/UfttsBDyZpK4sboxZNsCxQVgB78ZhTlmwtSgYk4RAGSm5s9gHnYnecz996gKeS1UtzjjSc5LoYRJHE8EfpiuSvMIqq08vPw1kPPPeDqRUr8AgMzVMKR8OIYkYElCGAk9GNEZECC5kHoINR4XUzLCtCRdI+6vKzYfOCL4jKGM5+Cn1MH/w1fwr5SwBP6y+azMWj4h2cssLeHJ5+emy9+X86vJL9+rkuHf56dPVilbQPJ/W1Uyia7MgWKEZU+LUYMk6dhCR6IiLCRdhBHdJRhXB45o1hJ5ilWoCxuPC6gFT++uVw0RwbZFHuVbwgz5TO5x8/vDb728/vu38siJJ67tP4fE8DQ8es0ePywD+ha1j7hd9alw2cWIAr+EersTobzdb+v+u2tuH7dbhzjPw+b/Crj7bcxnfT9ylm13FhXO9Av1SlVkUj4qVzb9oRYOV860e0zXme84ZBiAc+H1iqvEKKOWFmmOo9CvAO2t7hvPUwoBi+xyCQH0ZS8cox7JpucJbH/TrklKvNmGgKJgOqcJp0ZjRHizUu+MhpizAn9Pf6FOm3QEL6hysT+UNgQtL8xX1v5zYtPe6nHMIeLwwjr5/wZub1x8EDDCzFQvfX5gb/e0VO7q5x6e17ZgobPqQjTAbPuL6mr95CH8YJnbw89o1zCbQg+RmrKfySP76zBGJboCLHM7RYrQjnPwbdIlrsAYOg3l9lJBiZIeDjGiW0+1RFbSEFpVsQu5obXHEYxjcIooZNWd7sISkl7yd+dsAzjLjPOS6+HHjG5CzfBNQiWycPUKnPk3donIYzSLCCJFpwtmw/mTeda0ki60RH5MtnOZr9GyZ6Il7ZsJlxVJ5tI6h1MO2FX1CLkUEN7henL2S29nMNd+YwYUynnme/u8z/M01pnVQKG2oBShr1AVi7SBDkg4iCMkoAi5bTdvrI45HlBEUTuVOysnZ++rT8vB2v7k/N552BWP9KWRQ1teQ591UO2q2hYXm51FezqhSKUEnLKF4UQdAsxRPsl6NCUBHF198pPzJtTlliszdF7iKD3t59oJ7bZHr7+RBQfVLYhTOhEtJ+ynJ0dX0jJvSX4IROjXE2JBX7mMYZMV+RlPI8tP2JE2t1ov15WSxjAfYPtuOcQqOC7DC8/KbRcRBHiZczAVsP0ixUoT531bm8prhSIJISiBnzcR4wSw2fWMWJ3VEcEJEhPu0ZxN2a9qLQaau24qdIM70zpin8B47wDFBrzrvTl+vgDNIt6qJpw8whcnoeuyMLUG63uF1GQjQNQhw8wO67bzLU06YEtOwa/HKUCutyPMJapA7NzGnes/D5kbrob0BcT8PgOt4eAay4hNcyB7u05qo//rRdetyRln2gD51V7AuNd6Udls9dVkuQfg316zLH2SDo72yK8wMV88VJs0D9jy0MhLEwmai+a7BmQWNgoc5howuMBBjOB4hO1l5kxS8gFsydU1o8hZ3BaRwSezQBrbcZZj0p0hm/abJ/fBDAvwhI1iN0E3IcfTTzVKawo8Tj+j2Ypq6kAZWND5HtCn/yGwwuO/6/iVUmrBAIEVLxyo2AVw9fDKPY/0sbrojzBhnyA6PYpzGWWoUoLdOV8nHIMVztQ1aRGPoXdu1GxlOKEy2ErJrzJktUR3mqy1FtOvK3+ODgSR1lR6W6DezrZaD5wNCuL0f9jh9mm43GUBHrIT8Gm/2EvV6qJUQfUeFynDamz/X61mWbolwO5/L6VopD4tvmwUYWH7bQB+Tb3Xhw2R/9YVvOF7+wrfjPMNmWvTQWUE6+fkttwrya9YXM6QvrTEAEqFnEElo/UirMB3y0wWQT1QG/C3DkiLpYITlPA00FmHFDY/kdNzn0NlqBEdyaaes7pZh7wvdt+br+vVDNMuaEfSAi9s6oU42O3o/3MJTY56HBQ+jWEoe07zRMw5efP3W94L6xEyIooEwEsS++rmhfYoMPBPPTlQa3Q9qZ0Ed+4Kb3uOpNP3ZlN4Edl1gGteZ2PZZB7B381iUz+cH1t8wFQOQHlnk4/zqfdd2v4YXWJzyIc+k7VzYscDLxAT/ukoQPEavOsfd1y7Fgrhl9qMCUdJ81NRHuecyaIYU4zQlCfrfx52rToR+54xEAcATlTkUcyaDcuL+1LeuV9ymY7tu0yjh9yzluNBUxZfpoA5DneMuPNJOJ9oIDfa8e6vlYnyIbo4OrydYja4Vv9Y0g7Pgz8+h5GPS85v0xkjgZua3fmT7JhyAMdgj5ZIm0E3+rQjdlCe8mW1IHnxTn5eQitKXZj+cfwDyq/LdkTdCNh/UP980zAN++OwIGw8ILDfUDxZx/ja/M6d/WFsm24WgYyymtvzt9Bi9+vn0+PWTz/Sb7VarvajVBPN8E17CHMRKPpZ5XAcIi3GyVxMnH4/34I5e9Fp2CBvtmujrfui0V0Jgnt1eA4nbe/srIXKvPU8wb0Ei99rbKyBSJoTUday63eOTk4uliKQsx4RbefGWHjvvB+hMQ3Ov5tafLXotKbPtvf2dtzuLqrMxHZM6Mw4+nn48Ma8oLrUozBg2MYNQySEunHnBB4UQFDJoj4WOhxQzDOXLWEo6BCwfuTUmCcVNeEUIf44eRmqc/uu0c94JDIQBjSlOzZvDf9kWkD7NIEK/avt7bFICMQMbDZjR5pR58erbxit+zDGXyqPDFli3TdUW3YPj+rbgR70Dw1WgDPFYQediuy1xGMjOd19rf7e18N5bMjeyIjXS5zRqJ8n2E11U3jU6K+czV7u1xTzoUO4uOoxYk+Nrk/hKy+Bcv8XdM37PakujAhcYJtgED0pU1pUvY83M2brzL6uqf+/s8DABtzGz6t7Fr8jELLjxSZC3+LxMzK3lN8qEfIuEwqOLL8VkQoXFkCjvG1cnFC6cTTiBcuYJZnXl3xp/FfAHYJqScd/IgZ6wctlpTZO6PsPmKouOJuQbVOp75oPfLsj7Bc5TiBbgVf9vzWk8ntm7YhbGMxndj3aig/1WK2q/2W3Pja1X5piOJzWGZTc7JhLr0PRMjgg0zkYXJ+aUog5DlgrUbELLRfgYCuhC+i8zfWcHlA2JmAjKlKlVAciWO63soPM9oIBPqH1y5wJxA9TGE9IEPnOdKTCTvh5TohG+I4jHcSYAV8c0vrg3/QqhTsJafAL78B5Qb2rhi5UywpqHWBUM0wEVhExBq2z1Uz7cMqAMTW1Lab22td1q72612lsQ66Js2LTppk0jnKYtBY+0zVqOybTi/betnXiXHGxvt/UPSYz3DvZ3ME529pNksPh+calnPTgqNZpA/pwsowm7F53T86vo5LeTxTm2hYl1s2mnWYbdDa/5AfDJRkzh508TYtBsUNfAHywojyXbR5jXDmggQZnx3goR6aBywtgtJiqpB4UioQ39z4rmjO39nbeL3u3Gcui9dBPxyhg4YCRqK0dOxylltws/j9bo08Mig2P7yuzghArAm7Q0l5Fj9McW5COrLXJ8NYLLRkDQ+AsEjUWOyxbURb/qzkSUjTOz2rjyugcnYn9VD86qpfi7N998UibfWdfNJ3h5Ca04vrN2m09I88X04Vioz+YTjP3V/Tdqb7A5J+8voBfHN+ms+YQ4ftDGHVUc/3C9NB9j8sdpolnF4Y/WPfMrPP5922Z+RTB/r36ZXxHG99Aos4qFdYfMb9ghs3IB1q0xv11rzMoF+MF7Yj7N8/fVDPMpXl6C6/39dMF8SpIvxu1eqP3lU5z91X73SvtezsvoC3Cyn9vw8inW/kYO83fZ4jJkREjVk4TMc9YedYVN6cosHCplSrtJQ9PowlbVgFPsW6NJOhyp0GoxlSuevFkONyEe0G43W3tX7TeH23uHu2+iVmshpNQh4VFM1bQuYOEjpzfLj9icKUHwAmhbQDNnimqPrBeX0kNXQvjVPW9aiLy4lKfsZ9+U1cydL2DpFdmqaT3OCwDPdrIy+VyoEeqAk7KAYjCcgC3Vo5LXtURH1lw77X6CNSqbLQuvgqG9rjNh6a7cOUeY4UXgNDXdgNxYshhKNA8J74WYVMXbhLMhVVli9FiKFfyjrIP+G22knG0couabnWi/vft2p9VAGylWG4dody/aa+0dtN+i/1lQJdVZMPlFEtF0ZeczqXmmWa6tPFS2VPWOpPpvQ4FZlmIRwsyrEZmiGGt/tM+zsAzxyHn1qtjgjgpTSxgTpl33hnlcTzkXNuDQ8DGDxHX68YO6jsceeMLY6w0Ue/O0WE6bFweayBVl0DhsDEWhQ8Idt+XUkz6XirNmssBLil7ACZcKp3Wd/M0LGN5o5tkyV1g0x1cBfhs6ReUZwzar1oOcjvHUwyHeMn7PoIMQ0qzARFyg308vQs8QIftYb3vM3NOEpFNTrumcScXdj2UhH+y2dhcIcWsBCzLUFleN6vUSZnhKuzY/L4DPGhBfk361hFeq188Z6ZMF97Q26v7krBZrY2QApJEe393PuSqSWTzSm/G0c94JPlfJob21tzpiCNYK3nqXEcZlr0NFgGszN+d0nvI0/6Fnt+fK02GKvbkQH/hD9kRvrna0He1EC+TIpfibmvwAQfAiLP4xFreUDSOV1lV3uHEl8GBAY3Sm2UQXgise8xQihNp6thTI6JpduriteRnMW/mFPf7QT+jXD6dXJ6Zd38+XJyfn5sfOx3cnl+bHy5PjUg8/+NICArL1Uz08TyhiVXvDzRq20PEy8BfVt9kiepfOwfpjb0RfP/YA7+AblS5w7Hd3F3DzbcJxXfGJ2U7GfuJN6XKdy8uWij97mUhHOFtANdfdKPiy0CM4pewWarZ4CABS1fHpyY3q3llsXW+QpLLlv7TVarXa2zsLqHVtWUhtawEqdlhHu3I/4pNDq4VZoEufIszYoH0syf4uIizmiVa2uRn6nguP+eGIRdyksXMm87u+SxSYrCcPoEIuyfBzRsTU/q5RbAQTczhDnCW+14WFUdEKB14vb9JJT//uJn+l5hO7iP1MuS0adMMz0Q9BYn5HhIOthO49ObSX75WiVdTlyc+9d6fnncv/NJx7pV6BrvH5XdY5anV++fzuqtPpdODf5j//WHbVDRLb1zrmuRTbyrU9cvW0WmfrldUb34zrcFq9nC68DIxFY8DUqr4JS2KXx5MMO0JSNkxzr8J+3u8Pk4n8Ssu3+3sD5Hzy20Xn/LjX/f21RbrJFyWngaq80gjwj2BcO6XF95cmdgwTwt7Vo3/8cnZ1CnPB2G44aH/iR7zDggJydkrYUI3MsPZpG3jNN7Me8/jXT5fHZi+f/Nz7rP9VID3YeMG+8i5dQmI6LlVmo1ckGqKbjfbGTQVs0+a/No4Or4XC14IkPaUm133KrsdTPJlE5IEs1CyzuNfK5Qar6cemMEuwSIpbwIDxWZ3igYJmmTa7ZAnGRnQ2j2glPHX6fUHujKMO96QratXzla6JD/88+7gED7dkWgMLH+gdaUJHfG2qQM0KHwDmYfnN/9P7q187lyfXefGSU/vnV9dHmRCEKfukcX06xkNi6ktOoCus3tmfYFJ5fU+ZJlRv2CUEUq4DWolE3udIS3lVt+mEC3lgzF4BVct7vbSMvMqokNX1Melnw+Ei0E1eaCHpdUXhTWaQtR5K22hxJmSMGSOiJxWeCyH0MWseotWa2M4vWyfHl7bLoQP5zKBz9CBL0ylKiDK9kcc4pTHlmQwLoKAn55fLs7ItvwBv1n9ehq9z433oVaBjaOkXmq3Ftui2rbpBLAB/BprpVaVKbC8Qz6uxZm3jyiL5Fxwwf4ca0/BoqiXxCbiFC50y182/Ff3/7H3tchs7stj/fQoUT1VsbSiKpL4sp87dkiX5HmVlW7HkPTc5d0sCZ0ASq+FgPMBI4qn8yGvk9fIkKXQDGMwHqSElWrRs19YekZwBuhuNRnejP3xbvGqum677NFMiFhORyU3U683XqeJDGij85Op4VMz7UEwojzc1XfBRyHfapGGY4mfNR/gXT253vB94crtnPpbGnNDAe26SKXaPf2rb0/yFfT7xg+3YiZ+yNPrPmPhD/hWS5jZpAN5cfOoOpdWmFRibN2yKv9zvdg82vQibinPC4bE4u2Rp1EGKrcq0OYbRrYTK0ijX5FqQWeByzVqeInwaEykmjARUgumvjcYJnRIwU03xxdNzfSZsiRRdAMgS0TQv+EJJATViK4OYduvYQQxKIeaOQ8xhHwmrW1/jENfo1/chRIQ0YCYdT8MZccVSGpHT89s9NyaLg0iYWOfrP66xIOE/r8nr05PL9+Tz+yM3aH9/u7+BMPkP5uGZVjW3dwuu9qipcmXBzd09AHZFmy1Sfjm+WXnd17z5taOwSx91k+cFLV3Ll1SbI16rTmC6JkVjL6uj2251kinCh4QrLBIq25qBY6EIu2XpVE+BpUhL75cGt9MmLOUiJJNMYgvNgS1fxEI0fZgNjsqPdXh4wEgriUetPCoYqs129Hcvt/qt5rZhSqFy9KqY7RyrxHqCyhTJgG31y7UntpRIWqWFvf7lGhNxFElompdjM0AvU2AckM6iqAHCJf/D02fWnw6xDuqXz2dYah1LbJhu01ORQefrXKJOPQaBOr+5Rc5jcm1Ru4bSS1BzRBUaRKYsELFUaQY6IMSh+XXfoYZJjgb6vmd6B4uy7u3OzvYWVhD529dfzff4+RclkuXWyYqedVirV19idwHgRCKwsySSwb1BTkNHuxrRwWMSM3Un0hsyETFXIuXxCCWS02btuTxgWvQZFjE1AKn0F52Cdk8iMTKBCvpVLV2HisVY+dhXJdG1T9W43Dnd8ciEGfZzr7lhqbTNCi2gbex+zTCqMRaqKpmWYhc92oyfl+OkhErpCa8nL49rhrcCyhyVSwSpIrCNCoLMc4+eG8eHB4wnaQ1pW8vCt/Dl08MdOPQ5MRPgnZ1qxsZS90sa+q8ZW1maAuhWMIHZUC5ACBDDX4wntw5Ztyf1KpUYv3I2/g3ORlTA/Ark/iwdfcbQojodC/0uSIs0N/0x39WDvWN0cWzYTmG+QabcU21vMkQWVTg3ItacjwmbJCqHB0DHJ6/N26XyWiEfwqWWggigAVN3zGuXCo357wSaMMsqAGgNspSFV6s10y7BeToaM5DDdlI4N3DiNhAmSZiTHTIb4E+lK6mC7uqNhQ+Dq7Y1FMK/rmtB5SL/i7LYRx3YLEDIFEsnEHSXpCzgkkVT2zkg4lKRiN8UEiplNhzyezciPPNaC/y3W1v4CD7REeloo0Mu06m9nUySVNzzCWbfcgm9ZfgkiaZE0Zti2IVRj/WaR3TAIom3HFonhEP0jkURYH95dixzGReITnZTU7DpUSn3mndkMGarC328gNFni244Tss2Cd5sX7+tVZYR3hkH85IksCy6ym3jJrGdJjD6Ex2ZXzMaoS5mngETzBh7XjRmFFkyYKYFuw9YghrNWJi+bNjCqLStjFzogM+EAkF5oYNDGQLI3uA4FwpG+N00HnUhqmC+aIUOZg5oHItcGS3swbZHgdwVUkZowCJxVy8S6uVHUcb4tEW/EZWqM5maEXAToRShUjmFwflpzCgF2xdwlSZXx0k5y8AyG/Q1A/UKgqpd2PA5eHiwGKvH1mfKx2ihV0qfSSqlPMqdADUbn8olUto0uyuRXAFS3+CwYMOhCbPRajCyjaHFa3Z5drzRRieVi37NVyE33EDotm0rEBCfvkTwtkyNW6Q8b+7zyp/UawZc8X2fKXCezDpO8pVodrDA98sxmK08vSLG+mKGf7x18rMU3DqUgvtZBW4OOb7bAnA/a789Ue23l1j27Qet+Paz2FsdJV582vpLL/H2oqu7vfDCbj9ruj1Mkx+1nNv3XcntZxG35yvi9rN+2/PVb/vBSre9kKptPwu2PTUvrI2t/MhabT9CmbaXWaHtxyrO9v3WZbOB+B0acboq939Lq78wwWsmnYS1U5sW/5LB2kHCF4RrqzQDVG32h58fAOF3FPunBTeobNKCX9vE8LlWO/o3zdzTzn/Gv48h1Fcof8iUfc049GqbigzuvDLJCCUfTi8/n5DDy8v/cvR3aIPllcBxKHjodirZB6/+IK3/2DwcsVi1SPMsSLc0K2tOBOvCw8qC5CYq3DtCDg5U1oHEHDJgY3rLRepTz123TETIImZUywrxfOLXU9wftIb4DkYeVgl90d3d7S9M3hXqGK1ymYLvisRwq1wh8mH4nsfhwlROIqq0sFqpjHGTfFt6+5la//AztQ5/v6ikSh3+mdm8J/iTHB6bWitH5/jHGY8zkz41ocGnC/zzI0Yawwd/yE/DIQ8Y2d7bxecuKDVv2N598YMcYdnAH7eOIxx964WcmXApIbfq8hrAH2mhxsZiPOLTxrLL4/dkgeFmnzkOxgIG1VWwdjlVigY3nQlXKYPe9XaALZCRWwsvz0qzJsfm3l6rbgtuWLcCPiEX2rDoKMQd8wH7o+KHSyGiwu6NScNtVLtyGsPqgulJmi6G0ppQ8G3UAZiqbhE65H/ORbigpcEo7UK4EXnN7juz+RRfkVuXh91ut79FNqoUg1/qCLPKg9xPIre82phIPk0qDPJ4IlVpVMzZL5HpG0vaLI3WiVj+8FXCNR2lSFcWjMEP/m22pp3t0bvTDrQYOe1bcuuy1909qOE++H4GhZ52jz5JbtgcyTtXnV94HWZoVytbhyMxmdA4hMuQC8QiHmGz6CRl9jq+ukbPJCAa0/MB+2Vl9Gz+7gzCymzwrWQFBKajwPBnfaz89cd6HHm73d4s0dHpdhvfXM8g7hqKmdmSZMEFmm+qrXiBzsUdSy/GLGqutdav0PMImcak9sk7S7NfMakXe3/+crjFiND/oriC7XaG13WjVGTJW4JadakNu2Z655VVglD9lrbDYqwXCnmA0tShkGQogkwSgd5XOz4hia1Ly5Vk0RDOJA4l1eDeIZoSeit4KAmPN0OWQLohjaaSyzzUHUG47+x2D8yo/iXdkEc2QNtU3tdI/aWGKCo1dab8HW0pFPBkvDLv/QXmi5qLA1tqA6dEdgyz1H2NJbl8UlfE5dnF1cnR8W8nV58vDq9+P7387erw5OKq139zdfTu6Aqv0ptu1CDiLFadarz9k6dYn3zYtCUrpaJxuEkjERevXAUkjuZBJAhbJRYqkxkwzyRT8Mcm5NBKrG1LrqsoXQVjKFYj4VooDzRxg0JKDia14h0CVZC5Um2pcnra6TS+GZsFyYpIfAg1JMWwQGtvclNRbEJvGMmS8oW3IwaAOG8tllqDvPaOXQWqTLhPHtqDFVkg4tEPg0S5AnBVkzH+aOGitNrE/tXcE2ngHFM57kzC3RUtzFFBYsUjrYpziI2z2/7D8S4J+YjhVebxyWe3fuaC0VFPDJtsmVKgFWZsCSgponE1/i8/a88FX9UFWmHZVRdbBWNUVqL7fn/vaP99/2h399374/3jNydv3r15v/Pu/bv33aODk8aNDPw1kWPae7ZFufjtsPfdr8rByfbB9vHBdm/7zZs3b477b9709/aO+scHvd1+b+e4d9w7Ojp5128cd1VanfyoeZb16e/u1a+Qo+Ftfnf++BXKR8WVepp9s/dm//3e3t5hd3fn5H1v/7D75qT/vt/b658cvts5enfUPe7v7Z70jvff7O++O9nfefd++2i/1z86POgfH75vHOJtcMQkhBUtWk18lZcBaMu2AwT2E6h2tQdRoYKit0oVl0eekvRZCEWODiF16TQephSrJWUpI5eMTtrk+OhXly17fPTrArkcZvJ/0e1VHd8oBLDIUF7gH+eVUPA81Dr2GBPGpyRhqWY1zWIXF2dbud5NyJjGoRzTm2r5p3CH7Q56b8K9we5usN/r7/ffHGz3+73gYG9A+8175RhyPEWWxzFVbAsyITwdGSq04SRNkj78nVmTH/Gq3+33Nrv6f5eQF/G2212sd4OH76OzPhZFuJwE8hCyvYP97lMgC0Wi0lXGYx5qxTugUaSFZUwuPp4amapYFEkTzAOZhJghMxZSgVRRAr/xzkorHyB8XCk2Qdcn3h9qY4oo0SG/Y+W/Qqz5LeURHWiR4ALN3bgjpimfcLSDr0OmBRx2vjJFJeuTxRauImlpjrLyOeVzRSLnktiR5UGJPJnibyCKj0WQTVxB+SeSxDJLsNnPFdrSqwoycWaVmaZedygY8fjNmEWRqDNYZljw/d29q38/+qAt+O03O9qeyR88OTqe96hbl9ZS9s/PugDPVxfAX4IfvShALS2+s4oANTisQ3rDd1YOoIaKa5PfsFQtgBqEnju3YeWFAB7AeQ1yHb5JFYAaMrzQ5Agf0xeX/19G7uUk//uYvbTM/xm4/bhp/zMI8mPl/M8gwveQ8O+D/jPb/xtm+xcI/zPV/9ul+hcI/8Lz/Otx/b6S/OtwWAcT+PvJ8K+j4NqYv0ul99dh9Nz275Pm9j+E4BoYu4sm9teh9AMYrt9lSv8q7ZkZAYy5hWPbzI74LYvNNUkbLzRpkkQ8oIOoehMtWZD0d/fSxpYLk4oOIhDsDTAdCBExGtch9A5/IsOIFtAy5d8vzy5IzEZCcbyvuqPSa8OpFU+nUqmUxhIatZs42ZiwGPQh/TmLYxY13m4xu1dXNmT2my6li9MdMPgK4GZhh5ybuvpoYxFebONxevjxMG+f/NrvFMRpTCFsmUqtpU5YrOSWiuSma6ymcdjEcWf+0Lkfq0n0C42SeNPCuMlDuVEKkTIdWXKjIRJ3LIUWI7Xtr7Z6ncZMlzKZTVbKcFyWgquB4cy80BbGYavZ6x4VnDKXNmYzvE9fz4hfA9uiEb9VlJ4r4ncWJCsi8Sojfv21WGoN1jPi18D5YiJ+7TJ9zxG//pq8jIjf51yVp474La3OC4n4bbhC+ajfYcSvwXGlEb8XC8X2VmJ68zMCYa2Yct8kttdM/i+6vbIgsvrgXpz4yYJ7tw92dnZ6dLC3u7+7w/r97v6gx3qDnd39wfbeTq95ASekx1Nd4UpFJ0kl1tUEdq5DcK+H75Pc6i6C8DcP7jXIrjbQ9KJxSGlJINcIgErQ0coEwM84yOeLg/SX4EePg6ylxXcWB1mDwzpcAn1ncZA1VFybi6Cl4iBrEHrue6CVx0E+gPMaXA19kzjIGjK80OskH9MXFwdZRu7lxEH6mL20OMgZuP24cZAzCPJjxUHOIML3EAfpg/4zDvIbxkEWCP8zDvLbxUEWCP/C4yDrcf2+4iDrcFgHE/j7iYOso+DamL9LxUHWYfTc9u+TxkE+hOAaGLuLxkHWofQDGK7fZRxk8Zr+qaH9iKoZSWjqrjbsdXNCU2niteB7kfIR18yH0Wk1FzmdfmPnuF2LFYcHftTUj/ifLMQQOrjCdtGBcIj4aD6Eoi08OhNBx3YJjW1t5DqcqhjNwKeAzSujsvNcdbTdPxIagx5tG0YFAqv7azGhUhqwzl8M5If4cMrMhRXc74tEm+cQqoeDUIwEpRC/1yYyC8YQCgAtI5hUGBsKYQVmXL3TeMBg51ISUkUHmthfM5ZOO8gXOfcPhwf0zcGb3mA/CMJd6td2BWC/IenK1IHPWHZVYs3kJGKE3QKpIn7DfMqYeLQB05YjUWLENEXQQrI3d2Zkqq3n1NFvTOMwQkvLTcJjxdJNEzfJQktSWSbfzmB40B9u7+7vD7Z3QrpHtwN20D8Iu6zLdva39/5Sw6GmXKxHZovDNya2nbYxu/rvcCyhNOajsSYigKzfuxPpDZkwKrPUGJTAw44nDf+6pfC52J4RJSJ3u8Pu3j6l3QE96PYH+w2ImqUox0xd4i+fz+Dj7LrEXz6f2YrDcAyGWneFIkBoEwoNijkmaaq0nf7l85nEW0vzpEVK02WQMnrD4xEJxV2s2UkQGYzZhLUJ1nZqk4SqsXlfEBtl+5hSwzjwiuT3q2MY3bJPlka5LGoVy1K1HMsQchoTKSYMAqa10NJ0ntApVtI2Ye2n55oKW5q0mt4hT1mgomnbuSNoETU0szt6bPBx6LHbGDbu7pzJHXg3RkLPoX+6NiW1kHI+hIiQBsxcXWs4I65YSiNyen6758ZkcRAJ42+8/uMa1u76n9fk9enJ5Xvy+f2RG7S/v93fQJj8B3PXiXW/QLDwQNMnUbBjzD604LoREexX5XOwpiCYy2mwYe+r4gjoC6DBygmHMbdaStvJa7QWs+UdasBLEPIb2mi8iNEQd4/yluqyOjqXBKIOJFOEa6llIq/bmi9jofRxkU6hXPsYTs3i+6XB7bQJS7kIySSTCgYZ6BNBw8fC4omSpzDgwwNGWkk88qpm6ddbHf2dN9dHoUzQ8h3WjDN4gRqk4cxPOwupJK+tlato2hn9udEGzN2YQDaqVfrYjx90jPW6Nfqz1UZ4cITWRpWfEuPMskw0TOlo0sxnvRQPnYtUGSXdiBUCN1q4CX659oSMEkmrtF7Xv1zjFZUq6M0WaIOewyWLmmi3NibFfPmNe8KcDrHXhj5doCMpn2ipSGM4Iqcig8LuucybemstlfCjvHhMrrM06ujxriFpCmJPQWbivuUSPJkxRjuxEK1AUEatIAJ1yw0pRZYG9ZkvNj8nl0Zvd3a2tySjaTD+29dfzff4+RclksLaWOGw9uvz6ks8EaFWpcJcogHbSiIZiwt0c/Sq2fk8JjG2YCQTEXMltJ2DAkUMQBEK3Wk5YFpyGbaAlUwZlf5CU8ghI5EYybY7z6DZgWIx+ZeWTc7OMLHEoIAUNpTPFxNmWM695oalUsvZOyodoO2CghQLVRUsS7GIHm3GzwXuSaiUnux58nQjM3zeOgIOsE4JBjVenHtL86hxaQ5P/hlCtErTinTBC0X0g7w1lnUtHCKXpRU4dnaqFw47O9sFoMDUXKXaARMYZsVfBwy1D/zFpO3V4eD4XdO0xFSV8+VvcL6gbuJ7YPxZOlpm06ICGQv9LuzENL85w2gKD/aO0T5TvKKD+QaZck+1vckQWdRu3IiQUkBjwiaJyuEB0PHJa/N2QGMtRdy1MYeUhVhxqhgZMHXHWDEDU90JVNpLhygmZbKUhVertTcuPesynxRErbWgNL5JwvKG09kAf/KWsaKteWPhw2DgtYZC+IFHLb0gLf+LsqRErc/QNWSKpRMes1CfnwGXLDL5HhRy/4y7Ir+wltlwyO/diPAMpLm+3drCR/CJjkhHGx1ymU5NwWGaJKm45xMM4eBS2yKST5JoShRYnFWFUC9lRAcsklr6RKAuwblzx6IIsL88O5a5oAlEJ7tpVUV4OS7LudjAsF0VH1zA6LPFIhwsZeUaAwWu39aqhwjvjCOqiJllqFUyuZsEZLlRhvG4n5KvGY1Q2TDPxNiMHgRSLgdoFFns0HnP7gOW4JE9FtqK0a9lcWg068ou7oCpTq1zw7MryhCAW9Gks6N0gt8DdFo6P5CyTeNg5oDGsciVrcKOaXsUyC3wMkIDFmH+SnUD1+/2okTwaYvuCipVZzI1IyDL456nUrU6ZfeAGaVgmwGu0lz7OJlk+VJmg35HZoNeQay0C9szBw+lu1HlbQh9PkYLnSH6YFAp5VFupNZsUyob34IqkVwBGt9AmLPhkAWQgqA1O2QUg/1rdnl2vNFGb8hNLO5iTcKc7rn9AUKxbb2PIN78re1tkhpDvTxv7lzxmq0FYgJ88H3LfJD3s8R9vhLNBD98X+CbTLJ0hREGX8zwNQq3DwF6TI2L136e7eMFLgTXv/H0Ws2R8BiVYi0g6EBkKDjhUbTVoGMdu6XOFDZeRbDyHJeY5naaP8b0loEnhkHEh0g9l06sUs6kURthEhArIgXLMIbXeGglhXVH05hQyMk31iOeAJ6gnJiFe1S3ujGNR0x2VisN/ObX6O0V6TQnOajCEwZRcGI4S5ejMTk7PjzXpD1EZj52Q/lioHm1dIM75CCtkLGLSU7NSyYZ8PSh+sTRPU/fj1Tj+UrmCkBbawyuGUbFfjyMBixV5ITHUjEeL0oS4PVn41mY/bmZFkmwsh7A1WtEV5gJsDf9OeVUKjbZSiKqtEBdmLcRixUeLP4q4mSLguhl7j85j31xfWRNDQdoMJNip9LCITWEq32UljGhsYinE/6n5/tF8ruPXyQbZpHehNf6pQ4PrzUP4geN4LVTOgMRD3GdaVQ8GOOwRo/PJAsXZ9cyowZ5msdTMqm9VZA12b8Xm73N3c1+b7Pf7e/0dw56/f03+5v9vYP+Tv9gp7uz2d/e7R3s7u2/2dvsdReoeG1QrHLxskg+vXi+GIvU2IQiJZEYeRe7dbSiHbakaE5FtLIsZ1eiCMM59EyEouqmeL7PjY5WQunVH60bPqAxvaLhhMetNmmlDIzEeHSlB1yg8M+L05bcFbI1FH5IhTDHfk1VwhzAn0phDVF+YLWwTITvVTEs47GWqmEO5E/l8DHKYU7HF6we5kj+2ApiTocfQkV8Dg3Cj3taR+WgedDNE2gOFrqXqhQU8VvL874I4rc/yu38P0/pmae0JdH3egC7gufrdbY2l3SPPHhdlM6PcKYqmo6Y+iFdEwb1NfVLGOjWVe94BqeEochLVT4WpcBaqieLIrGWvggD4U8V5zGOCEPE71UJao7hmqlJ39gFYYjwgnUlP1jqio5sJo8XMkXybxsETuEYNnwqhpR+KPk7YRgbT8kgFXdedrXb3ZdjNjXZKHIs7og+iWJyxwY2NRhyV/RQPB7lgfamJkDmQLVB7o+PdQqZnvZbiXEzW3mN+flYxOwB22UlAOUkrUodOqQpLwC1QH7W86lyscctVwVuKWP4QfzJo4hu7Xa65DWuwX8jR+dfzHqQTxek17/qYQjnBxroL/5jgxwmScR+Z4O/c7W1193t9Dq9XQfn67//dvnhrI3v/DsLbsSGrUGy1et3uuSDGPCIbfV2T3o7bwyRt/a6O6ZjlCO17AzphEerSqD5dEFwfPLaRn6mLBxT1SYhG3Aat8kwZWwgwza543Eo7uRGhYD4ZAXuZhmW62l6f8LKG/HIqIfWHIj9xGTXASSFCl6oBFe4Cxnmg/gXvWVlGt2wNGarMtoqOOBsDmwsHELvZu2Lnc5Op7vZ6/U3oU4oD8rQr6E59+gVtmUGvPWdtaT/UaaHNSG+1Xra+czeDVishGyTbJDFKpu3X2l6xyv7VQO2MjNBYvD7tZnHVF4Aa4EqNhIp/xOfEGUkeayEW1wtjs2RNUgFDaFaIEsDrfiDHONMejbEJ/e4ZGQookjc6ZFNm8E8Vxoy4V67UkQbb0nE4+y+TSY0AIrG/D5P1jB0rZaN+HRBpiJ79SrVJzyFvAxIATBpRyYZOOJStU2av5fngaUF3JCJSDJtQ4Udch4xKhmJmCKZhIwIMphqQsV6BhpjdVCc6uTooq2pmqQiEZIR7uUH0jCEFpLVmH5As6mmLGRntdWvKnzeVGD1up1e+QBdLaheWbEH1Ch96HtK+G1kDkyjfv/j7PBjE8VbP2dVbprmOZzGhJySN91+p/eVKDp6LTcweSyhwQ1TruCRxNwPKgmPR1DKBJpt4J8wPpVSBNwU79NDxDa5G2x3MO411m5jUldR2EyGR6JtNOl2ykfMce9o7OuwSFkg0lAPx+NRZLBVdARpZiAdMigHAd0t7eKNsQCCBvTrJo83vxIWBzSRGUIp28b1UAcZKeStq2nCAy/fzWRbQIkX6hL0JYulSMlr1hl1yP9i7KZNfucpk2Oa3mxA9jm/ZdGUOPMMHE0pHULB5RIleByzdOaq4hAEHzLI5QssyWubR2JGNb8V8d+YgeR89BA/M+6iWM5BD6XdX6w4j6ZO/vLYSSiNe1zDK5rRsdkRs+RQdDQCWWCG/DSw3cg85rbc2/G53JwCNfxnHzdDOt72XUtQq8XtClOHzDqkQi6DlIEDrLzDzJgAgTferHUZ8pTd0SiSbZIC88s2ekBoSAY0onHAUvkE9u/KnLCA6OkxGhaaVfIy1m5VqnK86Vm0QvP4U2KKegIG4HpaBAeRKcnDBwqku9Mgi2KW0gF3BWftsVD5Yfb5oI+HwkANMttozdSkkuZmO07njqlHpZWhwrfSkhDQiUoMrQKh5X8ajLli2MYLEFQVelEIQ5J5vu8lKI6m6IrVtjedPHg99G9JjsEK1nNdfLk42dB/YH+FCB50g+Yv2GKMIiXvzT7fKGSq5s2uv2Y0mspRRtOwg39DkfCvd2wwZlGyNRRXUBko2tL6YcTCEdNDbxUQvLK6NpOdsZr88T9gIAdYkRj5s//cqK0LY2tc2VzEqlr56o+WxWuBm9wg0oeLTSJfEZdAz4jCRK7OaoEKMhBprokWFif39fjlbKCHCLQkD26l3KrWyv3HRePC3h7Ea2ZmV2jpfVFPSNhy5mST7qCnEZyZ/rR1b8/YFMEt60y4Shm2gNcSbWtIvwJzR78Et+wKEm6vPODkVZAybVb9cQR15t20vqTlDE/sk/tESC0vjv5x4mP4z8qqnsbahvp0QbBJDel3ev3OXtsv51Ikh7EFP58fLdD1m0HLhlVvCys7vVsp0I/w8pTLOUtT3RJ1S1SzJ06akmBleorG3GJsBMLr0+MNW1zA9OEoFOWoOzoJ5nh3yKmflk2y4kWfmcAMam+lq3QtnxlNWf9uTNUVl1d6C/Bww/B6mcdzx0CZ10+P/1mzRpvY+Kjb7TZufgOVPdnqypYfkpRhWbXZAqagZRtpg6VWJ1zxERpJjhZ2MRz3h6V1KROmfkWCEd8c8Fh/C17hYMT/pv/41dFxr9dbgIya8a5WyvzG1hQpkQGN61m1thVWr9t701mEKfT4MUs7tywOxaoKvl+aYjGzjnUAgSAIFbQuWUwHUfPuRoFIWWeQ98WZh8wwErT2GH11oYfBihEpjUfmFrXb6Wr9u9ftdE3dF/0nGTB7CzERUhHJblnq1xZ8pxVLaUYU2kbVepqUTMoJXNuC1E4iwZUlyoSplAeSvKZK0eCG3EKIT+73xLJ+91xN2yRJ+S2P2IiZqscmrkOxFEtCb7QJnyQ0UPmofpSGHsONq18bpTCsHsrEWwFMphMsFKKeoQTUKF1WQQfW3QxFkGmUNyr66W5nd7ElZvEtT0WsR2t0+/mN1vrEB+uhRafxlLiilcAlZoXaZJkVgrt9njI9vlyDJVJskoh0nVbn0kD00MLAFeKEqgwJrUkacq+QVrtwXtu1Cp5uXzSk8Go96mC+f7QNVQr+j9xgfv3xH8cb+WEPVccUdLR2NIJlAP6k8Q2PR+DIbp2Ju1abtD6wkGeTFnJz6zc+GrdgCbRxRm77elGd+HQjAifIspsSIgjzuRRMlY+13ema6lVT8DSGbMjjYllePUL+cGGNPC6CJ7gk4i5mIWovNKYj9ES9P/18cdn5lI6whw55DV9o4Um+XGxi0/9YxJtJKobcM7W87jVtcjcWWhhwaWtpK0HGLEpA7oPfXbIAmFNrtiAntPaViNjrB6cYnUhCg1RIVJzvRBqFM1g0vg07MZeqMxK34KnYNKII2LUqDPAKpRmrmiVZoXbhVr1Ww4C6T5p6ICjsIUihFRz0X48czZKUi5QrsxAkZSOaQoyBJwKWo2BFidfTBG7qB7yS97vdA98ZCY1zjkod4efeV3GptYAIDwe8qUFLRG8s657Um+W+1LZfFlpz+n5Ljt0+oimJxGhkukqQy7MLooUp3veEfMThJLQN+/IufI4iLMiU1vHIgMc05VqPudj6cPrhpDhbbKLeByKEZ+AApdFUQjllKNRuoRTg979xe/Z3W83d74GGgbESO1zot9tQwdvdBkNE4LX+AZojXXdgGDPimMoxk5bfjk8+b7JYnxrFLvxazLiYddN2QL95Dd1foDh+4RJmwPLLZnc7iLdbCIh+uSPHtL+7d73h0Du5NYtKVR6I6/fTrTib7Q1Tfv0m20VQLCmwRRPSw69TadzRerWNA4tcq0h2vHZS16Z9hBkRfg4izmJlCPr4uxIawQbWxw1kNKwqXtT15DJ987x5TR3M1xeHHzc6GMmn55HklqZTfSIEpW0KaoNtFYoKhLdW4PIZQK9PvT0hihNXNG+iobn/+OMF8TEm5LUeypaxlkZdLySKsGpn0Fd/9ap+N9Y+TCvvZ+lE6RpRLtfDvaZV/+It+h3+z9GdUpZRa96e0sC9Dh0pF1s9bEjpGk5q1apNPn35tdSWHlpQzllpt1eWXfG16UT5QTOFlgr/4OxuQSSeu/nkchv3NA4egeca9KBcDO0SZy+I+gvtVRkLdQVtaBqgE+bnbdFe4BNGoMMPD8YVpRBbAUQiHjHTwTuEita3NOJhjc+1393s7m/29kh3+21v9+32wX/tdt82z/fRCOE91SoxAt9DE2x6B5vdN4BN7+1O921/dzFsvHbyq+4Nfuga6NuAIbzgV5We+2UsF+i+7eETZOntqjYRXIDr8REXE87Cokg/EJifvIb6XstzzzIj2E3eksU6Lyr4axs12e03viLwiMDuExE3azrl9TUp4Hpihsg7XrAUSo8XFw2DG5ohtLe7u73vzNOQ3ZcizUVwhfFl5Qj05ohL/meTxZ+FNLgo+J/uAsRbS5nQQBtoZMBVVTvvd3feNHezpJxGq23da5IkcSp7ZwpHjmPb+tMNXCYggKRiceD7s4fmJhtKuMOKJ2MaY9fdNuHKiw1HK1YZT4MAIynSigVceyQJhoy7ofOufhXC7u6+f/fu4Gj/+OTd++7Bm+7Bca9/dHTYvC+/dWesXNCdFlOmC03cLRC+RPidQejkZMLgKsgvQo9HsnW/kH8X5IzGI3KUThMlSMQHKU2nHXLBmLtJHXE1zgYQ3zQSEY1HWyOxNYjEYGskep3ezpZMg60ABtjSNj38X2ckfjnb3t7fPNverfYk0mr57t7mAmLYNuB+FnNTOntzVs/0x7e8d/g9hzm5vDVp4V4Hc7IseqyjRm+emfbkxeWvuQ7aJme/Fvr7e/Ym+vLBunyy1V4bU7KA9KJYPLctOWtTFhbuMUitgeFYwrExGi/UCLSN8Veq6XjZROgBB9Wjwmbb84De1DO/JQMGV9s0DsYixY+bgY14NPc57/CZAgj/BmMf2c5L5kzSr7v7CXu1ADehUWSaW4L7WYNa6zGHlKixkMoT1EgnGnHXvDKhamwf9h6sAVD/O2ZJygK4tdiEm4P8RbimgU+8mB1FY5ueVYBP49dRfML+tPn3s8HDKPjSwxM+wrhMc3VQGB0pUhhWwGYxX+GHqzq+mYG6Wx8Iu4FQgFGWwqLgZHX4NSC9XiH/ublowaDLrunckTVxtbrPZIfHUnlO1AdpBG4JfJfYdwkP7bYIIpGF+Q440h9tHEFKJkzRkCpavyk+mF8xGCQovAoBh7k9QsPwCh64skPqJwMmJQab+XukgDm81OETOvLq3s66m/LrnUz4Jh0EYa+/XStZctY51WOT02MX6IiIWFqhsPmFHOolhGdEFPocbCHViHUQXEuEB8GdxR21w8zlEG92C/lVA3rNB8DRwI20MAwFqfVIKJruFg+OCQ3GPGZXXir3smCYofys8KZQ+OFhV56QXBaUWeM1hSdJBQjYRzOIGWhx/kjZKFdVl529MEh5ZiPfQhHcwA4yAu7Yfq6RBvgbKFD6oI8iBl2/Qbrhb1oYyLFI1RUeMbliZPUKnG/TCbcZ578Dqwn++aV8cbCCnMQDEMqCuR/rCOgRsf6V2iWcMZUWnYvPBsLc28wLzlp6s9mky09nesOSX8jlp+NPb8lv4k5rUBOaYBmFv1VgKegyZL4+Q2YfTMQdTghCx/K0VjH+MottDJ//Zp+pDH0aD4XP3ebUgz6oVsZ5DK2/r2Vncy6eHF34idq2WafssEB2ppPInp+YOUhTdDLHIt7M3ywVIBazOnQ22hmzl7JQXM8OMRAiYjRuuBzDnFaQw5SzSXVeITuDjEfVKasc4NSWVu/Nca970GoGzqcLAjP4oUX1gAQiZLX7Zh4sUqVMBePmwNhZsEpoPHUce5MNWBozBVEThkP/7n9XM27+u1NDizplPijx+XO+fM5felBGF4BelhvLa5GIsF6ALSQWPNokAn1w1WXXU2U1p8GyM52LkHw5Pa6fiCeVeQpfNZ/i9Lw6A3gwEho8HdnyEauTibByPD1yMlsLa8ZkJZvx8RPaAesS9PWM/+///F9pil9VQTKnzV8ffa55P19NaJLweGSebf21oVDxcDLn8IQmVZChoim6JNcObg+2euAliyCvaP1Ad5DVA56yJOIBlcVSqeTR3JuPO2PThCyJxHRS8qA8fuJ83BkTg291mEVPjrI38IypH9B/l53YDWsuckI+hARVhc2WbYf5vORomsWKT9iGPdrNKZqf6+fuixoIzI/5ie4cKXUncD42eaLjl903NR3M3J08MH6O+VCeRtzFLK1M5ANYWSFLGXi1qNDlb5TRInWJ4A8xBpnnfq+FrVFV5iI0JSZ9NDx1RRrKcxaqJhRnrf0pFumkFI5Si37DWsv2X+6DhGr4f7E75V8iEjecbtJMiZBLyHrLt81/x1/JsfllSvzniOcLfNATWzOUrzcbONyQs+4ozHMddFUXk9we2ouNnPb2dsdEsIihA82rGFcPTWPPVSNATmgwNvWTx7RQncBE8wU0JgNGGFfjfC1CEmZYCkXRVGWJ5QkciEOB9wkWRnAXEpD8kdCUTpjSKKcmWRLWmikwybH1PXyhP7ZN9j2ABilWNNJDKIkhTafn+IQRWISHbciLgezJAkiQa6UkUKaeuCZtJElFmAVNjfBGJIboOnfWmAm0meiwngfQCpivANAr6UoqvvZg2ngAKC9b/8lgwlFdMIUjmcdZUh/CUOqSx/UQZumMjLbl4fry+YyMxR2GiSEgZlcAjPOWMMhS1nS/Ft0xM+D5fcxgI+Y0uaPSbTLj1KKZGuvzyhYzSkkslPNIlK9+W6YiyJjRVMHt7kTEXIm0VZK4M4SleXqmQjLz3hJmNW8X7ypnazHeFcSslZwzp11ROymKg1qb+8kmKaxO2Rc782ifc7DPPNYhGfFPlr4lEjItq4g91iVSQAs64/xLDExFPxs67Nio84yIhlmhNBJppoheCkUjiyAk8jOp6saah0gma9HwQnNr5z62RySPyYQHqZAsEHEoayyxYMwaXwNkadSpvDBLh5+79oeYwqdHNCAU85CvVZBctyHJUv9nrJT+qA9e+Fte12w0z9/dBJFCf6SlEfnNeojE0LWYQFXErLzWQ45QwEMGfDwCr659lhcX2L2kmf/0vAbLRzgIT8/nQnnqQ1WExPrB2oXxIAGYJ7bkdmBjRzClVoroloWEJzZfMw8MyFKwjCEopN45UOB7UwgkrKzLMhdBWOVVpHoRrOQOICQb0khsyoWlhBJQibDcqcvzJ4xZcHNVlgRLQHZIlLhhsdWZIY9bci3raMxEJqMp4fGtuGGh7TI1xMklVlzO6xXfQW04W8GXnJ7jlRI8bA91Wwj5+OOFKSxWXQiIlkloVe5pKl1B1YqGkp5PmKl3AmpPgjUIjD8V1H5Q3rF0JsYp4N8AM2gl8JTW4lkceg/D11bLi9m9AnESZhEL8eW6exzoMvL4wwvHwcnzHVM0UtokkxkU3zVGTyvjLQgebJlL/1bNRQD+sth5U4TvHIcAB0heYK+oXZGn9ISUnTAw++nxUzg1aodG757RQsHt5ymhH8xSwPdlzdNEdmLBhHC+58wOXL8GhVFb5ymTxmKFavxUKhPCyCYc8jyMnUsNZ4J9mrMN7mW3bVkcJoLHSrZhf0tvf3M1JtcTEcL5Fl13Wg/ouY/V1E6PHZP7BCmQSW/OK1rl2dB/fu4s7yGvDxpW4QoBnjDr6THKAMbimdrZ1URWJi/pSXMmP2PxSI0BTQ1Ajiu4C9g9lworiU94FHmH9sxDa0mZYs9GHK9NWlnSapNWKO5iIzaGEd5HtArv0nhq4NXWZDAGYctjJYh9fsbRVYUZanIV3K4FmBXotYZb06y6IDUHYaMhs2TeqJoCy42r35w3sibQ1ZhLlZeOJlBuUx/4YdmvmZdMDaukq1wzFcEZW86G2iaaoyG8Gx2Klc21KuH8b6UfybwdTh7a5WTWTm8074J7njy470ljG6kKzrIygMyTA+RxS/MYmUAWlAtknmwgczZdBY+ZMoLMkhNLDF8vL8hsmbHEHGXZ4RSObDKhIC4qKgf+0tDdlY9DvmsNY3GZXz368usLBxgKSpkFAWOhF3T2mENhgYmHlEc1sz7VWY/jOMGLtg7cm9yNRcQ65ARNBm3rpYBr9QynSrFJUlW7IHqrGShQTixh6VCkE495ECiXFU16zn43BRj0Q21TeC4wdTotayJXwp1NysC6LAM+ofdXBvgqKReAHryI9J5Psom3kIbVYRVtlwXEkIUd8lEoU7+MBirLc7/Nk3JCo8i/j8zzbWIaXc0i+UIRkJdpxggfkliQYZbCMhuY73gUFeBFmkLXDJVOi52a/SASlU6fxg/hPBDgjrAFKcDnYB0OWIbRrp+7DTBeE08sHp5/gPJEPKjYYTNEYiXpZ87Vih7demrAC1cwxKGiX3wD8DvJbRxTHoi/CalIJMRNljQEMR+DLHJJ4U1UCNidLVbX1qX51H7J3EWYxXks9YjfsniWmzBVCzpInFPc+hOxMRMsJaFQkQ6u/a2zs2nAyJP76O1mmsZqzBQPvKCg1oX7EhMjm+oZ/lj19JqxQN6ERY/JQ7zb6HLNebxocENH7Kp4Qf3we1Ah5XEawKkeAusI5O0ooJcHXNqAGijSEM8Vl5YqrarF41sRlAKMcqymkaBV50vFTHzA1hxEYlA9bnhMCwZrAUf8FQtzlsHwh72a8MWvJyd8knfz0KMQk9xYHF8qVhUK5S06b2fN9BPWXOPNC2zyYtFrbbB5iM/mNTLXnFjSIJxz5zibhqSRqMMq+/Z+Q7GkHHD1EPEqaMynwDy4yjKvPNW/RJbGbPqNuGcJn4qTcnRUpkrxFpiOZC5WAHwtNQx+68cXBrA1Zg2WpuLhmNClGGMe4SdMymKWHnmYoaSiwc3Dr+SX8SljsRwLdZWy4cPazTSmEx5UIy/nIX/HQzVuLMVKi/S7ftnetkHIRg6yXriE30OCXd3EY8ZH43rPYYOZf4O3H5y6duZBJGb5sh7cMYdKpXyQmXB69L/gsR/yWx5mtAAHztQhn+JoCsGD2gaBc4OFREIREa5eoaMFqiGLmJE79iokXzOW6o+Lbjw9yUKnWR1tNaBgKqPlF03zEGWJJhXkmkL5ZxErFqsOORIp1ujH7qmAObGdHwjXJoKtGJKKO+mH6PpKrPnR1rFvqM7mQ9YvZQG9d8UpwGOhUhq4fLTZQe4JSzmLa9KlFriJPRxIEWWK2bawRouEAhv5DA4640ApYoj/sD3yLbPdIWJ6a7pMmWtw9LrMEqDz5cIwqHfHPrhD7ImkEXtvS3NqHhlmEUkoj22nntUeKS7r6inwOKPpiD03JmEQPR6TY7sjDSrQ4JeFhMUhsss3WpWiGbQkMmeCLg/5DM2pGfxBtPRdSJBF2QT3bUSnIlNEjvlQYQ8Y50mLIjJMoQB+1dFhtv0VCK3HiCIYwEgJvGjjEzzM8o4KcA7QlJGU0chIm8IgRkM078olxc1jQkBir/uah1K9fl70QpB5JmRx2WyZTn1EiyGZ0PSGCH2KUOhm6BWnmqX6pfXazoMsPxGxUCI2TbvBuU4lOCMAWZT1cEU5m/az6E+a7eWK8+pp7A9/M5dWr4zLii2QXOX2OzE99FYJnTtQSllo9rJBqrDLkTNdxQmlEt/zfHl5vmB8vBmhnuaz/M56msWcd3niA2ngd9bquPfDUl5nc0kAEcE2+tuQpu6yo9QdahkzcCDCaWM2/hZ6eF2ABEHt3Ld7LPKAgAsuLt7T2GckynlU5wMamU4/9RZaykKeskA9YfiCLabohiZDEUXiDmGlKVTLhhKArmOzNi7OqFSEQ4N1c9XNsVKIMhk0Q5FiSlllRk0Up+xwSeS4cG9aWDNGQ1ZKFyeznbRktqO2ES0glIPGKNjt5HZl83Brs1tx/WpYf9Frj9Lljh49olOWkhTuWlTKE4z0bXrfYeOklzsC/neFLs7zM2DqjrHY3C8PpgpEqqHH14xBV3h9Dt6lXCkWa0uuMlq+O+BRk82H8sRALtKONyn0sBMxoVHKaEUiEK8sZbXNlHctZf99FEofHsN8MuzCoLRCBZHLwKEiRQcAJUnKhvy+DXZ2rQAg9no6FAxH0mJgmvsj8CiFiwrQCuJisERpmQQAgkamFiArVBrw34KZKaSG1a4WktVN+U34i1S8FKTYCbGQElBz6OA/JONPTiAr5AS95dmVEQNLccJcPpAmq8B0EkR/uC95aiRGVVKgBHlAYqwxkS2HX+Hh9DRkxuQPu7usjPcJ7nI4/C818WuEOx4Dem96pwRc2xfOTSP99XrkK1fduCXZ//2unNWcloxFKK4RCAaVcnbLQpc97ZtoxNlo9Ta3UHRJ19Ucae2DZ3P0LaNoQzKW2CC8Qy40P6HmWxkOE4y44jQil0fn3vraaKYOOYlDozdDd6ZcfldGC3noguWYp7St71mwLlxsDGIV+PawXhDQTRsawypY2BbWU9i9bdTghQzjpBiY+JBlXHr8UZZxAtVTYNGfIhyqFI949FizwOyk5fZ91Vqq3fdMKjqIuBwTWt69C+jxebzZuuyGFdhZcyia6714dcSSnHqQ5aDlUZG860IoGxonghu5698ofjr6+8WuPhfuG4fe2zHqiTorHM6bqCQ63j4gOh67Q88u1muHVpQGf3d6O/OWU0s2/ZAJsZ+jjLmN6g2iRIH068KP7hyL/CBNvVQshkZS+qWFjrRo4QhNjzEWOsq8BkNXsVBXIBOK/RvJ7DRO2yLjLdnvvHHt86uUy3tp8JgM6S3W5Ck31+7krSOvO+SEphFnNjGs2AvSscQrWejrDndWhU6QD2Hqt958CKeHc1kXQRRmvu6QM6qeEMtnly9jGodyTG+e7MSqSJghj7V40aC6yRpYcZWB1+9gK8+DLLPYeh4zBQlL2OPH7x/m9R+Zh6jTPOp6EhUBeYpyiPO7Etl/XnciK215MPHNhtOjD+cNxat5s56gM7jx9BwLVjSTqsZFsXBadZH7P5q0qyHRyJGTYCw+m4HBS/IUBoAbmXz23CqfWaKtyKIIaMg3T50J8f8DAAD//xCuVdM="
+	return "eJzsvW1zG7nxIP5+PwX+ygvZ+ydHlGxrbdXV3XElbaI6PyiWNvn9sk6J4EwPiWgIzAIYSdxcvvtVAw0MhqRkShad3ayqXC57ONPobjQajUY//IH9dfjx/cn7P/5/7EgxqSyDQlhmp8KwUlTACqEht9W8x4Rl19ywCUjQ3ELBxnNmp8COD89YrdU/ILe9b/7AxtxAwZR0z69AG6Eke50NskG/gKvsmz+w0wq4AXYljLBsam1tDnZ2JsJOm3GWq9kOVNxYke9AbphVzDSTCRjL8imXE3CPEHQpoCpM9s03fXYJ8wMGufmGMStsBQc49jeMFWByLWorlHSP2A/0DaOvD75hrM8kn8EB2/7fVszAWD6rt79hjLEKrqA6YLnS4P6v4edGaCgOmNWNf2TnNRywglv/385420fcwg7CZNdTkI5VcAXSMqXFREhkYfaN+46xc+S3MO6lIn4HN1bzHFldajVrIfSYndci51U1ZxpqDQakFXLiBiKI7XArJ82oRucQxz8pE/z8b2zKDZMqYFuxyJ6eF48rXjXAhEmQqVXdVEgYgaXBSqGNdd8noyBaGnIQVy1WtaihErLF6yPx3M8XK5VmvKo8BJP5eYIbPqtx0rf3Brv7/cGr/t6L88Hrg8Grgxcvs9evXvxtm2an5E1lLxyoOIlh+is+hsqsnHg/y2qMEu5e8P+88M8vYX6tdLFCAA4bY9UMpXPH86rmQptI2yGXbAysweViFeNFwWZgOROyVHrGEQjKOtHKzqaqqQq3RHMlLReSSTA4pR4dJ9YId1hVzI1nGNfAjFXIQG4CphGB48C4UaHyS9AjxmXBRpevzYjYscThf27xuq5E7rDbOmBbpVL9MddbPbYF8gqf1FoVTe5+/9c6jJ+BMXwCd3B+xm0+vVCyml9YuLErOP2D0qxSE+KVEzICS4JDHPMLDN+kn3tM1VbMxC9RZFHErgRc43ISknEHFx+AjozD4YzVTW4bZG2lJoZdCztVjWVctiumg0OPKTsFTZqH5X72cyVzbkEmi8YqlPMZ42zazLjsa+AFH1fATDObcT1nKlmsEaeTks2ayoq6irQbBjfCWFyuMG8HnI2FhIIJaRVTMr69ONd/gqpS7K9KV8Uas2j55K7Fky4SMZFKwwUfqys4YLuDvZfLM/pWGIt00ncmrhLLJwx4Pg3Ud8Xzp1T6vEjubf19HSnkE5BesmgHGcYHE62a+oDtLWO5fT4F/2WcVVqZpMc542MUCvyvUaW9xgWJutriflrS1HE5xzniluWqqiC3pscKsP4fSjM1NqCvwATxViiWU4UzqzSz/BIMmwE3jYYZ6goCG19bXPCGCZlXTQHse+CoWhyths34nPHKKKYbiRs4jatN5jZPR2j2LZFKIM0U9fEYWtXvVgLiz0Vlgqy6bxGuxHWFim0KDreEPk0gr6eg041iyusaUGKR2CmkpDqDBBkgSXpLpaxUFmUhEHvATvxwORodqvRE4xLDpW16LX4ZigIjw2cMnMTLr/fh6TtnAgmzgiCacV7XO0iKyCFjrWykCr1QEObHaXJn0zBRohHBcWzcypmdatVMpuznBhpkmJkbCzPDKnEJ7P/w8pL32EcohHESUGuVgzFCTghyeN00+ZRxw96qibHcTPHl4ek7dobipIllfoE6Ib9jnaQWU7tqxo2oiizoOxp9UQOs0gG3aoHFFXZ8Y0EWaCHgUB1WliQPfJLqQbKlHLbITyEJgFVxdXI5XwHPrUDuJ8KbQBEkroxaqytRQA9tIlNDLkqRoxTNuHW2l0BzxlsrxNlEM83AapGjTEWT+LtsPxuwZ3xW7L983mOVGLuf/eOf9vneC3hdvi5fDMpXg8HumL94+RJewquXxeviTT5+vZePdwff5RFFpMeyvcHeoD/Y6w9esb0XB7uDg90B+/8Hg8GA/Xh++Hd6eWGGS14Z6Ewr1FOYgebVhSi6kwo0HY8wsWEMJgrUiKUA7bWFMLRunonSbVBuFzPPF6dYoDGkZ87wDGcDnmtlcCKM5RrV57ixbOTAZaIYueWHJtTyDL3mL5HRZYcRotiETP8oxc8NPIRu0mkHTiN5Peb4de1MwzEwFKFMFLeSV3TIw783QSAZvgi+swEszaBh3JnftPt5C2UirvC4pNCU8jPn3yYDZgpVXTYV6kzUAERhBGyvFfuB9DcT0lguc7KEF7YfgwO7PQiFhKwt1lpbUHPtlHaELQyTAKiNlGTXU5FPl4eKijxXMxwMT24J3Scl6o+w0ThS/Q4UHqnSgmQVlJbBrLbz5akslerMImrXTczi+by+Y/romRuA8eqazw0zFv+OvMXThJkG0XS0hoOeg+eMurDHMtymwxYdudq+60WcBhpD+4qzWETZmfgIc0kAOpM/4/kUT5vLLE7hBD6T4t4Aq/9CW0KX2Qs47TsXis73UqvVdEzWxiqpZqox7MxZAJ8xX4eS8fYTbzSwZ8Oz5yiHPBijhFiupATniziRFrQEy061sipXYd9/dnL6nGnVuN2w1lCKGzCskQX4fRp3X60qnF/UbkqzmdLAJNhrpS+ZqtGtpDTatwRxDFNelfgBZ2jeVMB4MRNSGIsr8yrY0mjXFGqGR2KnSMgj4omYzZTssbwCrqs5AS6gdGegiK2qRD5HnYOICiIw+2L7SDazMeiuxKzcQislJ6skg7YKDwddHwpPi0XAdGn6yOyMjwlmMAkJIZzk989Z44BX83YnMv5sFacE+QlxwpdEcvfV7v6bDsFKT7gUvzi1mS1vL19iPrjT7UXK5XbY6Ba422lwp/GzwPkPCSWOuCXq/6jUpAL29u1hsiLzSiwcJA8rscZJckhf4tIL0olnGyeOwgpcGX4hhMmhBUmWcEAOT0xo/0y4LlCyDR4MlDS95H1/ahgL79oVSvKKlZW6ZhpyPGxHPY9WxvnhKUH1+1SL5hJu+ABfTzBzy9GAjOdFfOfsv9+zmueXYJ+Z55mzZbxrpCaFsjSUd1+iodcZlGAq7SxvQE9XOIoFLlnNpeGOyoydqRnQSnAeBfemBT1jW3SGsUpvBUwV01CC7qAiFwg0fsHRz+QE8HI0hngIdk6AAHYaUGCIlpyEaW6HSPF3rM/YYWcA3Msa06DlS1Db07eQiN4/Gunw84dxPJNGz9MqYC1/pbJLINHM8vPVd+uY5CGKCcHbCeNEl7RbPN5wQ++mgRmXVuSIIK5YZDGXDG689d7zJhUBFSZaelbhXUHDK/ELBA85uklZDtqd54ywDafpOCnZXDU6jlHyity6jIX9AXXoROl5D18NJoqxAj3L0jTO+8CjHxzNmAKMRfFAliLDSlFVUY3xutaq1oJbqOaPcKrmRaHBmMdTnV1V41aBm8IgczQgWUlR/czGYtKoxlRzL+XuGwLJ2DWyy6gZoF8fXRPGOT9PTnuMh90Y3fW4zdwwgx5mmzH23y3Ho9XY2lDMza/m1wGnsB5GGT0YebmNwofnfZDomSGouO4a76P2p/5RJuoRarxR5tEaoXutBlnQYcCJHZ40I0jn58m2u7Nist/dds5N9rvd0VtcxnML5jNmfzLj3ifU/ayDyPcIzzv64r0erUQSBK9Ilyfo9csOYl6cP4PZg3QELndaw+kpnrAk4UyXYLwPRFfZGMjz7NjcY5yVDW4uP6MOLwUUKWxnjHDpLQA8jkeokvsTtOOnV0HtGAVocQXtxWhwQpLTWGl0JfCioMvTCBSkFvkUncfZ9iJ/S6Uy+g9eRHd4PQGV5cLOL5bXwKOw/FDY+WqpfIfnJuDVMjoKb31B2otcFZvA6fxa9SuwFnA7LaB71xxH3zar8X4//OYzC3Q1MRti8PtUjsNgy0grbadsOAMtcr4CyUZaPb8QRm2K54d+CHZy9sExfQnDw+GtaG1KNAmllbN8yCUvljlVqTz1nd2GzgTURa2EtKvGfavkRFi8p0ITrOLW/WcJg+1/sq1Kya0D1v/uRba/+/L1i0GPbVXcbh2wl6+yV4NXb3Zfs39tLyH5uNtZB/ftHw3ofjClkp9QleKlsmdPj5GTyzEIf5toLpuKa2GDbc/C/a0Gf52Y2D6HweSJLkQv4UJ7P2QOeHan81RZKaXJZsDrR+9zDqeVsFUxQq9i9XRuMLAj3ljmQUe1R0TG3iubRICgSw9tNjRlZs62mYAK1C5r3LEyVsl+kS/NTa2M5dWmVtn2qQPvVhjjxqhctHeXyMuIckvoXyimorX2cWsKoVJ43RQvUMfALqW6lni24wxJcQMpzf52csoSmpgLuXCm9BVe7V+LAqq539VoVaNxSP9c5t+bl4OXg/uoWQ0ToeQmFdhHN8Jd+qv/58Pb8NqQBiOcViqwPzcwhmX5w1PNL0puAhs0ZBA8Q/hhSwoC14u3tifD98PkvZXI00a1M9ToMxaS73zfgFTmYig0mHUFQ9SfoVLUq+g4OY2ntLCvevvw2cnp1Us0605Or/afZ52xZjz/zGAPYen2u+HhamQSTYV8l8rG2+MZJwP84w+H7LvByz30alG0IYb5HaMvVOUWLHvm/AZ43/66PxatiYo2vnOXR9OIgtmuFfupqWvQeKXxdzaFG15ALma8YoWYCOvuftCMssGqjTAJfT8wKhDJGmnEhIJ2YAI6Y2dN7u78r+hFivXyd1YeBx4hTuf1NIZIJNIzGPQHg/6rY/f3i/7ei85MSbxKrNfYH1dLx/a55tJ4D9LJKU4K+VN8gOj74Xl0TrJnkE0y8rvzimaOgLqAqOCS71wCx00n8ccxq7m7qJETVilesDGv8AJImx4rhYZrdAe5Iwf6/kGHIMKU6FppuwbZK458xuo2CuNWbiD83wo/vN/PdNlx1+m3Q/Wp//pBZ929Lh5Lc7LOEfz2+TilOUgVRToe7kfGgobiYtUpe6VAPEhxoVKaiskUI5zbQQOP/Ng9R0hd48Vz6ZnWjP1Pyfz/0N6Ge3svAUfnbbRXthZOuVuovrbSB6sP9nT9joFpeuZuz2oNuTBorziziXsfoItRwuHrZlyJnJmmLMVNhOjeeYZR3wc7O/4V/wZ6mp5n7FzPUVbRNYyG1o1AK9IbWeM5M2JW4y0Av2zn1dnHDGPG3R2wj1z17kkMsXKur2uoKkf9+dujNi5qK1dZc7mVbS8KX8KNjlREtm9SGuIgTlHEI8MdLpQQzxeWKUPXYhAVlGeMBM2h9keN6JhJ7maXxD1z9/Gc1VxbkVw0sCUMnDJ1hwl/CqHfvTXTnmvwJyTBj5xz2d40sK5c9RIOUMSWWSZoDHhjtVLMV68JZm/j7db19XUG3NhsNicIXjD8yuDGbgX1FCPlCQrGyMewW0crRnC0w7TW3JZpxnuZaca7ncXXi4C76PkDBbm0iQsJjK2ev9mRChW8qHDJ1KCFWhH6g5StawlaVV84Mr6C1oOyxE37CphVNQkKUf8Mzt8ePe/5aNR4kmr5TjAZKZdeuI50SgBFNsgKwUPislQYvIJcHDeCTQKLcJYQ/NZvWzM6rXibUmxnYj316J535KYxoOl2ZVMik/rv/M210v4+GAfHKeJsBu5iRZWrVUAPbem3R8NTVFlDT/FRBJXKStcIwgEymHFRbYg4dBYxN0A4xHStEYcAas8VLr7f0A0Mkrlt2m3AOaH4FRcVRtwtmYDDagzasmMM4gIhlznirlf/bWLnRt+83Llhso0F4i4Ho4a4ajdwuDT0Vz47dcUtGtcrxNO9/sg3hB1M05nwgy0jMeVmuqHhQ9guEou5cVM8l+ZKa8Az7lJkOie1JBmXSs7TFCN/PklE5UcDFNE6wo9cpDLeV7v/IEdHMXQ9V7L04Vq86oyJfsRlqwrdsauEaiOBzcuiRLPl6Fhc3Wf93f6r/t5uf2+w93Lv5Zvdve9ef9ff23+z93IP3Zr9vRevdt+82v/u9X5/dzAYLBOxLGsPJeMr68GzKZ4+ETwqhEpNhLyTVTyDW3WgVhWYLhceTeSHWnOXboYjMTdSuK9wfsluItoC0ts/bV2KMZf8wsVsYoagBneikZMLBBgSs27lW6Azr1RTdEPqwoPbI+p+wDnASLgqjbBwoJDpQpaaxxy+lgzvR/Ox24Qd+i2yO7KLSvauze4QJg0z55gFveevwnGBlmDzKRh3N5NAZ+gPxJdC3BsiiQqlPf8sJZQJzNfy4ctdFAiubiRlimmYKRuDnZlqrBEFJCMtYuZx4oxSmQJBBJgCctyndK/UTbF0vySA7LQdPDh8RI6Zwy2qxLCoE88jchSlRmEFBqqyT2mv/vTq3qIUpOxbd53iH1muJ2Czb9FfRlBjYJ5/HeXAvxVw2t4O6KeqNVBMEWWqxEiyjohgBGNjJwqpoFBE08MslNqfcDCC6k/qGq5AJywzYA1bQQABXSBj1uCxXVnKGi0bk95XaYXZZPhFiCkDXKjOaY1KJSoDRcuE7gzdIClpCIzntuFVnCjyE/g0PccSF4xBEONoC4TgjGGwTsxxJjamgwU2RqYRTKVjKq8KiVdplIeLm0zmbHnSHIaRE7dwdA0Ml6a1xY2ALnJ0WaJWYPcIQYK5uxTcnGG43a5BGgtJT8PBmChiXi5t+nNWiLIEnbqr8QeLwWh4LeyDvvoWJJeWgbwSWslZ956m1a3Dv54FQpkoeiFA69Bh9eHjH9lJ4dxPPnioWbQ/su3FTWl/f/+77757/fr1mzdvVrJzg/brCoYGE4BXgps7eBl5SHDZF/ISx13BzUKYuuIU6LHEO0Dvisixisfd+3bCVX+2ExUGTi3fpj4aa4fJOP5WVYSwTucf8XurBn8D5USG1ieqR4rCXLJgGtNH31l/t3s7HHKKNrf0TmgEdnIUVDJSEHbRJURFf3fvxUs0ld8M+DgvoBysxniD0h1xTuMFl7EOKIWHy8lrj4bRu2BzzOs7EErYaPeyGRSi6Qb+0Yb2pG8fRd+uoTQWGP6kkR9TIwfm/icp5vXJ/u2o7gfQ9O9X7usj/etX/+vTQrXPvsrOQGOlOneVZunokdP4TY8Nf0FvR/tkWafM5n0a5IFs+Dr6mkajyPN1WYBvLzJhtWqdzcMQ92cDnlb1hliQOsEjJ9yAWSA+Lf7Fr02P8V8aDT02yev2thkzpjEOjVcqBy6XFgO/NvckHK/hldwQ2RTA+aibxz3pI9Bf6YQYCAllIdIyNoXAZMRJI8w0EBy9jgSWoeuvNVbCtY0vU+YslSA2PQYTZ/eho/LKsLd8Ni54j/3x8JT98fCYXbUWzrCu2bGcCBnX0F/esSvjnlNJoVXKiNc1A/oM/00o94hS3cgeK7mecAs9Vrnhl9ejf77ulP3eVTK9/pVk9deni4Nwb4j2r62EA1m/TRUbsH9SoL8dBUo+cjqdfhU9+vt2eiww/Ouskd+L0yMw93fm9CCy/6OcHos0/SacHoT0f4TTg2ghg+yr7Aw0VqpzV2mWlVbmpizsBTZ8HX396zO0Wzb8zpweRPh/rNOD6Huy2X/tNnuYMEwqv8AsUW4bDZ1oOUw9P+v8cnvY3PkUTAhPCiFdaeQKxZ+NhcR0dRyUxUFN9sVRLYXAVh8XvJooLex0tkmZwxhejCOKg0XLFylyWydV1r497aMjlZEPrg8HpmxhAxXMKfbJuzFRCIMnWwmjkqiuIimODNoVpKXMkVaePW9adJflxUz53qv9u+Wl5bUvL9zl8FIA7VipCrhcxcTv/U+4OHNeI+EunL/lAy5hyopeUkjbKAbb66KKIIWcbLAcNSqh1r5YWxLEiuMSVYIPXTLYjMum5NQrYjxnvG0FcAWyUDHel7Uxa8g6DRVc4ZHKKnSxVsC+/XDmAtaWZz5XswzHhOymznE7vpmvzVvLbWM2xddhUQgqMbmsRZBTWMPPpwsCobKaxxggTzX7J7hqcz2vrZpoXk9FzkBrrBwbwyFTqFe8EkVaTgXDPjVGU9J47C3wK2CNTKooliEx333afqLKRfgRLPYiaGQ+hfxyVQn4448fP3y8+PH9+ccfz86Pjy4+fvhwvvYcNa7Fy6YK/Jx58J2DTlTtoBcpeSewyroqLTtUuladItmfJcUCn214HeMQj7mYHTylabVSOeKwhKnhSBJvGoHecw0f//lP//W31+9eD/+yNi9DR6Y1uBmbVC1wDLtUuajpbqeq7s6+0EMKX0dmrjA0t/cGe7v9Af45393DDgQvBn/bXpsgXJZQrEHOHfvS9hl2PPIh8+k6X7F2sadYJ1/4L6gruA2FOW5b8/67EJSeq1moN4kZhoUz1CPITiZvCDduNQ3u/kpViC62n6BwcebUiBuWlNT2l+2gTpN9IV9Xb/iII52qulv/FWhcfwXjE0zhDmdKn64fDUhpu57ClbqYd5j/GUW7DmMCW8jCBd01mNOHt9vK2/HFYDCjQnDaBneqpWZe7aYX+ocQkhGLGLHftmhDqU2AhAXXsdOxUG6SiuZSL3yVlQjZUFKHnOOhCdd6tv3F1npeN1kTm3fdLVgm5xUUF2WluF0pXaegsVoZOzz90ath7zHFngvIQSz+G/vEUd1TVbq30WgNBowvqCUs01hSmKgeoPbfzdhZzl2mPFpjSuMuMhhE+UEE0cZMf7xbiFpGFMJcZtg5IltRK3QlO26rmIK7iFW4lloaHUz2bMKbCTx3DSowRRt3MMyknbNnfDLBit+t5qS0IiyagKiZ51g+P4c2Hdz3o0lK/Gf3IvVaCwtfgVYcB9th/PvIFcUG7C1KlGyXvSiy7qgzPtmo0yX1qLnBaNMPCKGK9R2FlFyFmuWTDWHW6lTCi08WkuGTTo13D590bLyjZ+PC+CduVGp/2Bl3BjOl54+n8N45eMzBY1gpDCumT+AeCozAssdTZBsUuXZi20p2cVhfoTqDiat98BiK5TaV4kpR4T6MGzKWo/FD+4paJc+XHTPtqng0rUKDZkJujuDYWzVQ/u8kmGyONUh8iGh99NDR08KdcwAN8JaMbNFCLLAYvU7MQLSYLozVwGepIXiEhtRZ+/gOU/B8CimUYJlhb0JXQwmvgF012HC0kHBNreZa+LHktcmxOVq0ik/kqk+6L7eFBINeT18ldKj5J4GlnOj01E+Zn+mnlBRZqqpSrivqjEsJ+oCN/pkQnKGA/qvfeYT/NmAXnuJwpuY5/GsUJsa50mbAKc85IIutVPG8RBOJhy7XDlmHw5ImDw3WV6KqPwTRDYJEJZSYjL1TeqErhzurhQo+pWokZYEKEztTu+pQuOBFnuVqZ1ypyQ6XfSFt7DXat6pvp9CPsQnc8r6nt+9nqe9n6Sf8mnDEsrB/j3M8lOzYf22A63zamb5cSYPO2sXeSWOeY7kfJmSB9yH+9BkvDBIAyA9XdSIyynF64Xuq7cuOGjRCiTVKYiauUDLco6Us9VnJrkyTFxBEBW6CaGIfPi1Cp5RORZQECsF1g1MNi9ihdPRp1GOjHfzrW/zrf+FfW/jX/8C//if+9X/xLzZiz5xYtWLyPGA86o3cRdnoD6MsdB834DV5l+mu4wuglscTHG+dl7cIw6QRBeyADD3L/dztRDA7eaMxcmaHONzPNXALfcelbGpn1R8WfuG16NfcTvtYyXNmfkpZ+PdHOLPRolxDE6PQWS7tRVcld/TeVuuxxjUUViqXc7xxQ03HMfYAO9QakAaCG45ca58IJmOfkuNuUF7ZJ7nUsXaEd2o3GCeFvUB6bFRrbEEyhcb9D2ThyomPUshgcy98Hcl1qLnE4Wvhrrys7xbpnhe+F/4UfRHEMWbAplCvIbYQ8vdBn7ZcGx2Rf9qKNXTCt+6NjI1c5nhGT0fkFUqhuhHjdZD7DLXbaIVeHWWf5PcwV7JYFuQU5IotI8fzmxYciUR/Hm6vvjDBKOLmx8YCd+0ySMGmgnnwSTL2LXsXShQEORj1R/6X98qdAl21QS7RXk20+dbi/pzO8boWRtxWHkuyhzi9OjbjivAz9j78Mzp8qEsRdxrQWQlCTlJm0U6UfZLvsPksQsaahniWn4dwR6DC7aQQqLMnnwefopfEFOpKO0Cq63DPTd1Bx1hbtEZm4+U6TmhgZ8YQnRSkx8zViQseuLTCubtgGdHXI9dtSSoSFXKfuy7JrutSChd3Glz87be3C293D+nKagqTxHYUpyYVWuRXor/vkNYU5BcKLl0G3Oc6ZF4/lsRuDyVTV6CRhcg4/LKjiEheosnB2KHfnSosW1+payjSMJotbI+95YRvy1v+Zitjf8W+tFjWBGfTbfy8KNiW1bgeAkO8F27LzKWdAu6uW8GMlMA1Kxv0EWfbi+zDAe9mXGBb0o+rY7AvPL7DYE9ejdYxObUXDUQIZ6luB76IvW9ftuNLJWdd1ywysnVlU2GdcARzu0GPaiyjhUIg3a5EjWxIInFCA14pEp0Gd2FiV9CWtIz7XLM4vw9TsziCeD6F29qhJQOglGJUFl63OevSQCVkpxGs7/lFUMehkRreI3DZpdjcNmBgQ5eZBLLT749qtXpGOlmNsMP1IN3RqzKtTI0jdlrSu9JXboOUq98LbI5U+iYQkdOPYLh9va5wKbNpVFIJYTNcbA1HEKn/5KO1hotgsbMjPLWGe2oN93tsDZcuR5IGrxl/ff3hUlSfmsQ9NYl7ahL31CTuqUncU5O4pyZxT03inprEPTWJc03iUiPx19EpLsHoqV3cr6BdnKhxZlI5+UyPtHif5Qoc11pcYZzo0bu/PV/VHq2tnPyr6hDnWpIlgZ9EKUqZbXljFU4WcuIIMKMse3wKN9Hz7R6H2K/X+C1Bqqt6ltphdTFYOdmP2f0t5dZTC7inFnBPLeCeWsA9tYB7agH31ALuqQXcUwu4pxZwTy3gnlrAPbWAe2oB97tpAVdUVSfO6+3bNRIy2iissHQ6q8Vl46L3mVVirLnGoIhijhGyeXLaQRcRuqSkcyy5IiDuZoN+xvhJpmpMjEcD3F96Y/JNiSGDim2ZKcfTd3ecLW8UtrVdUBBMOAiMQ1oCnQAAO8OXGJdpVXoKiikZB0GPfMuOPAH9SshLGm/Ono2yoqpGz1muZjOUWZzNAoM+/ipkoa5N+/2ZR/eDS53DD41a9d2PUtz0XT/jJdqXcOmgMa/EeBXAGc8/nH15kFS3ClL2VE7o65UTWmD9b6i60ALmT8WGNldsaJHVT7WHfvW1hxan7D+nFNECZU+ViR6vMtEia//TChUt0vdUt2hDdYsWGP1UxqhTxqjlE1qf2ax4tQZvHqK93h29cj3vs3vhY6Z8d0MInf1puPswjFqTdgM47b3afxhWr3b3NofVq929h2BlCoB6U1idHR0fn94Pqw2ZHB3/Lp1Vk5XsNmCX2YteATbjtQkRDKmR4g5nrumSuVxezJcYpFK92MuCI2MNcjELvEvuo3H/B3S/O4xxkCXaF5A/PPhEfoJPZ86/8WLv04MIgszlJlrIUetuiDasCJYOQzXKo08byV4i8Wb/5T2owIJ/XM43RIBPwnFxp26Yji2M2PdCfm/BOOZaz2pRQR/NouxR7eMasgSxTVObPH0gsac8jRH/PHEI/uIKtPkK1NEwD6RsP3uRvdkfDLLd717uvroHiWJWb/I+ZOgUeCBKzNC3Sy14To8xtxMyNpSMsGD9Pp5//WsswYvhL3SFHs45pcCs61oLSdXG0T2L6YWMlxY0puQ6jlH+ZmjPg/Zi381ihO2C5+Lx3/gSCyp3lTmKHqX4XbsEQZ/J62urWM2j+wBxpdToro2npX+Z206FEIwjhLlTFL5ejJ1i3Y8+uqtQN+3sDXZf7gx2dzBtGiu49GeYGKuh75nTJ2eiqxCyvJsM8v3Xgxf5S3izt7eL/yhy/urN/gvOixf7RVHeQ0BCRtQFTtYjXy2vXglfos3OTocn78+z4/86vgeJdA7eNF00zJfQtxXV9aeb4XFwzrt/f4hudr8Fb93NgEB+IU3n3uT92efuTX5ATsaED/R2Hb0/Yz83gPcW7kDNpbkG3S4E/B0Xpo3pzyDcWoxBzs5tKycVRFhzVmuBG7JiE7COLgJLQJ+NCmlcUakDHHs+eo6qw05hHgZJoWNcREzEd0iGGx8bk5MdmJg8zo2PfeGduDLCwZ9pr0FDO3cxi8PBWcbSfzp6nn35JUaXE2tXOOzO41Ay7u7uiBOexfSFM4ZcArAfixlfwI5psI2WUCzdJix2ukDnsYtZuIQ5+QBoXsYQ3PKUoG2ARu3mkI/n7PjwLKwBxj5CrnRBsJyOdpo19dzOWnK82g2DY3stbhEegU89aO+VdXOMsocVVCjw2IXUg/slCoW7EsT3aAoyNrRsJqSYNbMePYxwA1GuBFZAK9bQGaE2cQVSlsgQpo146eGBIoLkCA0TzkvMM7fKUYTFzZQxwr2Nss2LgqFdmJQ3oXtOOpfcgig3LG+MVaEcXLa9SuyyvOIbKzOAYuPgI4lxQoh50NZeC/1t3Davl713J+9Xoo7QNoR5rPBHj8fB1xZQXVwcwLE1DdLpKKJPsbaACRE1uIi9tgosSQEG2pe2/91BFv6s5MIGd/GlxG+ryIBaNW1Y1RRDVtLVeOLcYK58OpZvfj98d4zu6DFQvThVXaE7MlFO29vG1/gJ9bIolTGCxIp4Tmu4UBxTK1kk1zEJEJwDLFoUdRXGQVLU5CLMkCk++rkBEyscjDBzB5KKHsm0uBDiNmJ05dRYW60xM7elVsScMMzr0VfuXgtVtyPYcWDlLAR3L8+ncSDGxlA6xZQq7kKYnOsCioz9DTQVdjJOlgP8UEqoZeC45ZofYmm17r5eLagbbIR3HpaXKh+qY5xsdvCeAi9AX5QVn5gN4b0dA272GGXVo5r0IzM3crKYjl0lpk7ZpgM2HPbY+WGPfTzqsY/DHhse9djhUY8dfViW2e2ftj4ebfXY1sdhiMUJxG7sJgynBmnyaUbpdRg3FNpAVketscELJhBx297qEExGqQag577sQwLIVX+tRVs+xasFs2xy7+/t7nbbFKt6RdLroxNPYTMK8xsKUl/UFYCugC6FLHBLcBSSKUUQGZuBwbLRWRpAgo2mwAaLjRSYDdeCHozbbDxnXERTCvNWHv35x+OP/93hUdSJX81W0GQd+n0CiRHwWbOgo7o3hKXbEXG4RdQW00XcO1QqM6S0SCX7zsWBpmBSWI4987ktL/bw1OMwYLt7+7GgKcq+Mp0vWiUeD0Z4ojYMTM5rXFPcANsdhJxQw559Ojo6opxb/PM9lugzFTdTOuj93CgLKWQClbFzPjY9LDagBRYv96cGKj9btQWGGSsB2qpYuAdhNVlNeYyfbI990v6rTxK3LdRmrv/J/XbXOM9LOUybnPRVeXtPuXq/ply9KBeR/5uUhzgIEx2nAlF4V6bdkrL4DeWWXV9fr2b6UyLZUyLZQxLJWgH6OscDOiXdbVkMh8NuSaVwVL34kpoHwyUPXVWxk1M05LBwoWSjcFTCQ9eoIzIQfxwFTx/JjihLkTeVcyA1BnpsDDnHWtUkyFcY627noShxhOkdbQZdT0kxb2yxjNcUtsUvZKhBiyg6fHERMOcRTZgziuB9GXBhozcLX/elfu0UZmirpKC9XeA/cr8DN3hIsCpCvBIGM41/ATJX0MItlV4Wsu2fthKnCZ532v/uLh58gh38NY4BYazVVXDef3BNIzvYbXBRbKerInr1Q5BU0SMOo0XqpDIRx5OSzVWjybXa+R69XdXcOVsNvpTeJ/TcA9qG3Gu+Cn6EW0gToZQet8WLgXWxaBGgZRPuADpILIyPriU3Pm5/RP8z5fjlokE4VqlVcUehs5pfFs/x7rNgnDw0ESZxtbvob7+dCH58VUa/yZJ8R4dvkBLIO/c+x4efu/d5B5b3Uyc1HUZz8kJ/eWvnlRftSQCPhp8boSEF80XCjK7zcOvuNrbIdyQSY3iwHn9uMnpphDszj2gQTKLFKRjn6Hd5DaiacdYcyNS7+VcMS3Vz6SYWb/MSCy5Udu/3yWlKFxqIEPLZVGIytdW8zdKIgBNq3PdJflCF1VbcqU67qcOC/f9AVMn34erC8/B1hEibAZGwJFK72SAbpBJVlR2JevsD+5NzSn1GsFbmYb0VsrlhxzeQN/7o+1bIS/ePH9wexJ4dv/3hOTZY4rjxfbnwfYW4o3ccy65DN/aImHz89odb4o5e7/fXDz3C8lEXSgemPz4N388t3vX93LgWKKq8HfG3wtoK2LEsBJfr4p/XzcUG9y+M/Arb151cx6ictaPW3I6AZeeSwPS7ME9e62BHVhSWnkIlEY2gWCIJUd02rdCzE1QX3FICGMFkaJ4l7fl0CCko3MUGGm6humDJL9HvM2cUXOJJUdpk69IONxiNso6Dr6y4tdDeHHdIf4sqU5XYOEA55w9UMItpj063IpT18fLu8IyPxYbjt/7SDdtCiRom2Vbf+y62J6GVGns2/P7k+X3J2KQT1evo7gXj4rpYF88N3q66Tmt+K0iQpHHviSZgFcy0XuyaV22frx1GzGwH+FKOUlPKzcrw9tbgZte3JIq3mgHhduNfG2VzwcdiQ6h+fm0FjnsL4sNZdk/0N7j9kHTctQOti+VXV2o07rpoUqTnGvittS+I2SPtCxTGtI4ylWDsaqzWDZ0KMVNLc01A0a/Xhig9JD7KNOO+ozUQxLwXWQJG7Y+gKrNAcfbtaP2lHD/Kp2LvYUqy0++iw77Dqeibnxu0N2qtxnwsKsxixdR2LcZNyjLC497Tm6EGVvX8sVE/m2LzQckIPMt5lTcUYRzNtAcjvcmwAVQ0ZySPztqiSIH74rjBi9IlFNNaxetjGOqNX6iyXK+P4aMg60f7AnSN+AUeJq+3NgtZQjIMxnCw++O6wb1xCVUc6/4YXgmN/WkvyEm8BrL3su+WsKTxglP64Qg/fPYfgO09Z3+CW+7X2jLdYP/uLdMhcd8tkz4KU7sGvx66UIhrNGQrTPfGdcMLegHP+y1pV2foIlZE2hCawcKkKkxxOF95wYVKCZMQszb+FqoyyWB6bLwDeGbms7GiBCQ8wbVL5k5EA5quNknHbxsfrF07i8sk14KKnbjLiLmLUYuL90ds74sXdYgnvUfB/HUNGKKPCaclWPSTTsK1jNK4prHsvXNz6TQM173ChDVQlUGzYGiSh55tf7FbePmG71Eyio8RPXeltxgs7hG/M87yUTC4vaTjCgwomPAzaKyueLeC7hCb2BnDWJ5fXqAHdGGclUvmWlQFBiL/e2rz4bHN4cscvjGE37EOpbWuMIbGwo3tEvmVAhbiNPbS4BJqTInxkmkKmq/6nRxYohf8H/yKZxWXk+x9U1Wn6M8CfRxeT5XIVbiJCkokPrhbidDVKSkS6pXsU6lwNcONvaUwU9sk38kT9p4lWF4ZRJ0zZJWa4MVdMAH8jenSFWu4WMXC3K5feGxO7pVTG/HxVkXV5O77QiP6WPGQ25jxgE8RUITBYr9yVSZEELwAiocyP1gjxRVDxIQQ3wyv13Y0DQHSPjAltlMgmCGNCe8NedowwDV2aIHkSkoyEsdgrzFcg6f9S3m306kfTEhhsXdRgbDyShmkbRhm4vPsxmtz2maYz9SSje+WVmEygWm08/+Ti2clZ5PXXFEfyy8hynDK5lQ8Wh7PYIaFdXHbwtHCqEXLaeoriz00CKqFmYvKxt7E7AxwdiG0bMedbuTJdknBMYuArDMn1G1CFkGM9/kusYwwxXGxss/C3esXXW/myxn6j7KPbKMuxNgfJWMcSIgwp5qf3Sg22u8ohj1d3ZQc57/CqyphvGi0kQZTLgO/c25holx4B2MLk46KZOQY1edFgW3/aT313XoC9wiNpL678IJilPaFTEocSTwRYhhHEGeiDKNWneTBilgirFvar7kxyMy+zxjvTgZ2WboQxcWGq9vhMP78E+mgcCJ/r6h0KNcUrI8Rx28yUYy+YYwxxtj/Y+/Nn9vIkT3x399fgdD7xldyD1kidVnWbkesWrLbiudrTHmmt8cTIlgFkvVcLLDrkKze2P9945NIoFCHJEoWfcx7ERM9skQmMhNAIu/kFALiTGCTT00jTx567alqqKqQSxukuay3nKoPJZpI7uspSwxNLqxT3z8QViNhYcTDlV1aAyKZXmmR7QuQqWmCClZ8U5dFqO1TJwuHkr4hz9r2eSI2oCLME45x7uNqx+cszl+NrJByEBlh9HKyQs8c97NT++rSCn5xnGnDRZIszvMStPgx3fru2JPKkjW3mX3cK8p2omp298IG1iByVRDpQ/iFu12NI3rkmlyZJJgoqipnCIMqjZi6LFzF8FmnTsegXzZHyFe2O7bO25CzKfKKq73CkkuV4WAwW/AfPgUVFL2Ii6IGCHe6PbfeNcMVYlyR1RfndRZ7B98wCO0lauVUQk+4Yqw2GxYn2stMqRZbxDkBumOxSCsUfhTVtjTWdRBr69+6LqrreVnugZfq9vq+BBaF2wLf/TLGKhd2lYubQNdwgY7AOQn8ef+aO9CVdkd4ibPTtmy1+/VvdyjsVtTaV6IuYx/r5TONF/mcT5HeiCysE7OmnRZt+iWYZNXYJWz44sKk/3FyOO8BAFnkxTxWGbKGrv2NaDyDlQlOwMXGJJ6JSYkKunwD99CDGKu8nqDuoE7jpFAZK5yNJY74ER2La9bXXYIbusOypHdNsB1MaAeXcXHNtWiuoyypjfQmucFlvCI0nbFtvGJbW8rqWZbw4lm0PJ7U4dvESF6Xhj2RNACGyNYM6xvFqj+T5ICSETBFMl+cVpl69rsqbx/S+pN2hz/h8d77M7bl+SUl74JrSkFE9fC+4bhSjqKflugN97fND5APbvX2COqa11qULfhclKk36b+HaWIyixJ/9/XU1iIKmJIlysF0holbEc6LyUSEKpULfYnoOPqeoCcQs9MZ155ahvPNvU+MqdkpK/YO9g7rzDfKXp3/LVlwU3rWJt8GA6T+ruM72/W2o/RpknnTOPOaQmYKEwVRaGn0QLLGJtdIMc7EMl4qlMfdeKYRJI3TkOfm/S/XNhWqAB1U71fVOF3G1cF0pqpyKWcueukyQJra+xk6GeWFyOOipBNm5gHDp3elhVuWL9pEdSSr4iYq909nVolmaycbVlU8hC+h+nRjm/r521zxy7GEhiJSsxyRH2dYTEzHlEOEw4xAQ61rwVKigclCp3HhDFXhgYB2qKsdwz9tJLvQ4pNSS1EujY5IX/IvV52rcG0SoQ0+QnE3Ny6USc/f7Ibm1Dr5mzuD4UF/sN/f2T0fHB4N9o9294LD/ae/b9buA17wVoT08Tsm8jI+0VO3X3YrqUqJCkvJ5CvmaEWTVi4teHE0c9GO9pRh7Z1J9KxnHL/wrDzp+Yv7DZKNOXnNzwvEYXVfYS1UEHEpfLQL7DKqgRYL8uLTIAZoajYnnMDDxKytDVWkajex0FGZVEcff+zxpAbbkT3SRa/Sc30wHY/NEi0VAo8XbnvLWje/m/a3I6TY+GacLsviwv4xlanmlhL8d10W/gdk/jpOkrjzM6bElM7IsPPgnPLS9vCwUy/1l62fJPoEGsVnxl9l/q3QBiezA7SLqn6uujtFtyyyggZ/JijGG4M9jVvTl1Qa1dnb+Zzf9KRUqLZek+ZDYs6bzqrfW7WKAZsW71R+pyfksYuCGqprDEa9RPe7raXK5uh9muhZXuA3Xoe+J9jPTF7xS4YZtRhDnyi/WitSC53mRQbycd/hb55l0Bybh364s7u3f/D08Nmg66fjX05Ov1oE5ewUl956taoda+F8KPem+4NBVMcsnfkx83vrJOfuTaDz4qQqCu8vbSsTuBbSIpMJd2ZBF/GOBvL2LrByMa4eHF8Xb5xLqy4k165jYsCS0i1A6eZN6DVtyl8AvWQKv/c2CDDvNRCqoLICJXJ55bPdfeAsZb8XdDXjd0UHgjwvF9AYMBZTwp8bpzOu0LX0Ot9EOM90qhM9q038wcwf/clW2Mb5UY1X4n82iat+Y7e7yiu47c3eD4aD4eot5+GBvuMcfVs71/ZDeJChC+rGXKMHQH0LpfIXGgcCNXqzaoP/Zx8V+9obqWuK2XXpQileaRsOBndvd/nx7KLutKDZz2StFiPv/LE9MoFbkBUZugvs9mdnv38x2UPnbCDf8en0GUOjmOsr1sfBKsKVFzGH2YGdYBRRGiW4qedzheqPa3GF7Jy0cA8idBp0rKd4UfVLo2bgQhWZTiqqY3irrk0J/VwlS5OGmhc4DFdzRe4/q8ugHgVcRokcejNmalYmElF+07PKAdUZ3GDtq0IcrB39mk61NkXWrOJ1a4MTw9DS1BS5zpTNB3yCZVW5RCZzzpMpUsRSsVMGtLEoknJGdmXbk8L7idwKugmp1Z6NPnxMqiCU3/xJz94bA9l1RuMj70C6xjP2iJnPdzCdgNe4bmX/vfheZ+97yG6Eea2PAKc2LeLMXbIPfMpvVA4YrmDlyD4YZZKAEw7wFrczy5dJbPqJxgUsNN+pg2YO+ROcZAd0opxy3xPEZbhUMkVl6Tea6bAJ6BM8YiTS4UXlgEYLeDnxCrLwcRjMCrJPRdW1gn3BxfaESJHF6tJa6+MLs/toWDeFNKAZQ2bmJFwpWRzxYZXuYRCunt6i2xPLRMECzZUSYxLiRk3ApcnHVkyPn0O1jEPTnli8V6w2d7xkI7UUw2dicHi0c3A0HJhY6snzF0eD///fhzt7/2OkwhJanfmXMJ2PFzKVM5WZ3w0D/uhwwD849K9QCZuXJIbQ1PVa5IXG1Hv7BfP/eRb+PBygQjUYiigvft4JhsFOsJMvi5+HO7s2p52Y4b+lwtuqrjeWA0r3OvFf+5lFvOOhryzTN7YdPiKVaq4vdDKcPum7m6XdEFxBWwAmxFTGCSLrzrW0VJlt4ORe0rQgj8lcFtwfWUXVIh5+b3TBTdB41h33C6be3Rx/9qLLUe1eE8Z5j1JMHUT6Je66m1njvXPVK95gTE/IMGTfpdEO4so75BHooX6MRzF1+POOkNeHJHmoF0tdWstVbDnaOA4zl5Wm4oA62lg5ZRqf9LyrbWV+bSyP80MQiYSSAzqBdsZqN50pcsnC9e1t8Erb6vJ/8D/eWL878Isyo/NUsQUysKqYN05EavILR2me65DTTcw+3KB8ebTXZnIAeMWCaSNnKO9VqxZzPwhxBN1qXMHH+U6v7acBxziRZOpCzV707lO1O7lK845HldlaEzHcUDqry5hHs9U3R673Rdc9M+50ulVGUbH9eUbXOfvg2t53pERV3mY48yvnETeWcotaE9UVI09UQwlR1YSyW/o68mWhJ3l0nS+gp6KdUfSEPOpYCVEiTlVgwM1ZpA7ilhlX0qvmYfSZxL59rvrHJYzIdPakvY/m27VtzJTMdbquTXxP0MXV/NoL77g0s7aQ4g24PRkH0Ihv0IPQIEhmNrVHZ+6As3yoR4utt4MaXGQuKcp8e1yXKQzSyQ8OTfFXDN/GFWoOMtBzs8R06iV91YSBkOJKTfCafLYdsaq0Bvpm1XHI3N5IpTE/O9D1VO4ZM1ZqNNFzYrS2z4B4zYdyPEk0JWPkcaHGHYfmnPru4WADwzJ1Qf662n+n3Z9ZXWx9h40XEB/evxJJnH7ig+X1F2/b4dW5bJ46C4VsHPijZBGHfuqctUVYUBx7FnPPKT2WCBpoy7sDy/eIzMNxjzRtyYOEoR6bJ9dFRImf7V2xUzqMgPB75W3TGtv/PhiQr3Hl7YnzTxe5pyPepDVOEy2Lrg14H+efBEGAZKO5CFCa9LQlCHOWVSLXCRVS5q7XAE2Q5+gZkbaZV7E+owvAeKr7jCvcL+BKqxPQecBuJGLzDfw06MUViexugnpIQJAiDyUlkzFEIQY4M8PBoHmmkCsoYx4YzYPy0bUJ+16PKPGLYCQJ9RPOPYTcjG1BhhtAXLE/EgaSZJcikWG4xj18oLvwgOtgs8ZE2y5lBe7dq7xrc2T7sJha2xr/avxRIm98FElcvOs28kaxpyoBgl8MBHk1NVmr+88+y7AQ1GkGsqDyNXkJAX46gMXNuUm5ayHSzpvculSeWX/TZbkfp87nLsHYLVBjV/3BvC1g+3fX5dwZCw4iWw3wYFvVpjIpbFzJ5lf4RrmVTnnAQcZyaR9uL9XU7UQO/ZtXjdmnECKtM4fLzkHlk2m1Vfve5nLRqQ8oq+M5eiYKbEbOtBgnehbk9PfA/j0IdaTGgX0a7a+r59X35jt7kWukeYmWouKznaUahiVdycRzN56djp64bNTaN5z6zccaxUECIUC7Yg8ykMy+qkubgxuiqBv43Uyul6Zk/9DhA3laP9MIH9YP9APihCbEeWekkFOc/VhhKxWryku5IViIe/qnTtUKSD9IpTi/w0itkYQLUQkO7DDDFLTTNqPf4lyPCSRIuLE6GT/W9qA7oP4zaS6gPRxVPqt3V45D+G3hwqsWtb0xqcO+xPXXKaJG4uyUF994XiLvbPt4gd6+kVxseO265WSSqUtj49qPj843aKiRTMXLl0eLRSVMYpnYT/UH+0eDwYbVL2+uNGqJ0G/rpSrmcfbAnEfQNq47oML68htojGGSHzfw8heIL6qUEwm9t0NUijwDtwjUcnLhIUix37mXIclyNYJ0gSLrQBqiqG/uMsOW4omyTh3bkrSZhf4VcxfZrwT/buPUlFmyrhvfNB2Q4MeD0axGpimuFKeU2X6JgqWZpa7u4VnBqkjp3lplz9SNxmk/Usti3oJOp89WmTioHM/mRnfVwrjbZHiKZSJDdaN9coNd4uB/mX2yuO6wUGiJ7f2dp8NIRZP+dH8y6O/tDA/7h0+ng/6eDPcOnw7k7uFU3W692PMwlTL36/peSHlXn1CoWohF2KI+a8d3WohKTMuUbHK4mEylmVduhdPbmETvHoUvb/kY6iRa9WW+ZVy4nRZuSzJI2OGUY28TmztEfhVLbHA79ysUFTXArJxpj30rsVluDa86xRz8otod96GgeR7l9Fm0L+VeXx4c7vf3wv1pX+7sTPp7e3vTw8FkNwx3Dlclt8ji2UxlKxB7c6eJ01pFXe2IMfjgnugE7BS6iKMVMHvoNlTM52WdL6pnY5jspu4JVYStfXDpT/elbo01yRvnHjm2mqvjTH1Mu+YUfUw/MlAhPgrxE4m+jyl+WpaTvJyYnxGsNMq/+Tc0ssz8SG/ARpNTAHM7kyyLUCJYE3/237dUNR9DQ1DNElgavNPKR6HqYoysUWkjOZ2rdOcSNUcxzrrxhwA2y337/L2Ik6rZCVudXsCE9BsKwdoHyhb+2n/LNNrWWUWsyzMgDbPHk2RcdG5ybZY8swF48brKcvjHi7PX/+TPwvCyd5kFe/4kMF/mx4FjHVVba3dpJU0JQKg7Tlr0MFD3FlRF4MHmFz8LJsVFRSvciRvT8l5JzlZzfVvJ4rKgO+OaNgBWbXFu0siRov8JwoGTbzrSUGVhWqepfAWsH3KTeRABypxATLWeR8qx+yW356MY3iX6n06uoSsVsJEC8VJlCiYWfGlpX32eyzKn4GHCvVjoJW0osVCWnIPcllbzdsJMiC9VD75e2AQ5cjWiOFNhobNrqO5hdr0s/MQK856pnpjHUaRSuKpkZP6LthA9Vhx74iqLi47A3eY/NuxnMSzAfNrOCbhRrlQbBs/JBXyPsigzFUQx8gIvZAIbppgv1rWLkMfIA8ZVdYtVifHxLLVj1XDSbh5A4uezOSo4N1Q6Aciz2pzdgYNbbSuXUhtvFD6ZUboLzzCp/GaGNxW67c3I53Jn/+CBrCfxmtcZ3jKVV1D/vITVGLqeWwGcYEu19WhvQhI99NDgpzidrU8t2WwMzVv1nHi1Fq5iDMdKes1bFzItpzJ0/QJkFfS9VGlkO9BYz6QzjH1bAM1glfjp7YgGSLTPRagXAdZUwedlGFBA8KGsXm+h/u1htFoJN8yJoryB5Yh7QM1K9GyGK05iDxMSl3PkOqA0hnMcmlCp0reRTFdkaG/C62GW/aUSZVo56bhW3n61+oqeNuE7sHgFy5TL1Ns7RtNMLj68OX//YXT+/PTi/du35w/dspL6dXVMrG/d7ods2siAr6UtAAMWZU3CXFqBONHZUtfKa+5LWaHkYs2XHks85s0neDrjq026THXfWVsMqovugN7zwj//68vffj98fXj8t4ey1jqEV2DuTcrfKe4T/J71elB3OGgTSR10iS0UagRvrRveo2lzZ7Az7A/wv/PhztFwcLQ7+H3zofThSqtoBepuefE2RyggydlWrWREx73H/Mna9Lq/1XuC3CQveP6znUusF+bhgF4HVhVzrxi4FkXAQ1APJUDN0DrJq9QRjI8UJIJoWRZwm4/6NpNQ/EI2d2sWQDmKZ3EBx6ldD/LftkawzT8YJvRnJSZmDgEXMngb0inWZW0v7pDZ9+RTzcx9yP3aJAPSDt2gW7ayPZXj5BJD6t+vW1OhV2f0WOZfZTGxsWo6Y5juQN0Gofmbm9lPWjVvoAkIlEtgIMYLLDXmUAHixYgJj4kKL9WJq7JNwT2OSVHp3/hoDxGK0IGzUQiLt72lQKPBr1qPrMd+j04JuPMF15MJHULNQ5lHso6itQbXhaWF75cgsBmUaFyjyB8r70KWuMyo1GOYgkd6c7jKCyG13oXtuV6obZlYzjtKAe7CgPlSYrso3TzFAnb0+C3U1gNaJJjtW85wBXxanGbZWfbE3EKt03KpMmRImAegFvbFbdaJSyTxeHSyqlRC199/jQlQoORHnwIFGn7ISVCE+H/laVDJNPheJ0Jhb/5FpkJ5pHz3k6E8XL/36VAeqj/ChCgP3R9pSpSP9g86KcojYY1PFJ+U216p+2D61QUer3sfVL/XqVH+HKUVkHuMMRg/6uSo2hd/sOlRNdx/pAlSNcS/4ylSNTzbjvn1oXmvSVI1LH+MaVLdKH+/E6Vq+K7xDf2yqVI1LH+UyVKdSH+/06X8eUsrYPhfecJU7Yt2i1fg2UMvDnOOl7SPUh48CN81X/IvmTRFeP7g06ZAw3c+ccpDFuxcp6VKESK3Sk+oz3AV26BjoiQV+kW66Iy7uJAtiCL+glavfMICRh9STr4vZBbM/kTrgdQrJ6PVEAtCgVAlSDiaF4mtjdmfGz2KVG0YCK6Ox3NBLtNZjX1TnX3qSGt4PPYdQ1p+4kb/NjMF3ptmHmYtIdUdWYYpxFs8DGn5uUdNvjhqY0G7lAIKyTUXakF3QHkVwXm2GFx/ndMGSfS8sdymZWzKMeEO05JOg0wjbz0HGN9AEfG1ybit0/Hm/MWIW6uALalM9EyXXGoijlESlCIWR2PKR0WGfIet49PRExuwVvZaOKjci5E+CkSrvDkE6GAYJCoS/9/p8flxIH7XqQpsg2+V4VOmcMQUPNsNpqw6jqgAN5M6ykFDU6qUaBnZDlJACw7uLFUos0/F8ekIoF0vDweWX0toBUdifHL0cSmL+cdCfwTOOIuBu25HqCG+cIcUhfhpJMaN3zrIcd7oRmMvio1Ii3H1rUCM2wva3mYOpPdNvOw+Fq0v+cvEiQ8V8VR7xYTpJwaieXJMgJ/RthJhVT8UBWRvaNXrb6LKVpWWs7Wl+rzL4gWyAiibHdUfW7+enTq3mC/SHQmbw8FgWI/+VlnW68bQz7XqxK4dDcV7GCyi/TXh9/p0H6JnXi+epd/kczlc06qjl8fDW5atcmHXsPDO/sEtS+8Pd9a39P5w58al80ipdR3C0ej0+fN33tIrXNo4Xd+ghzPArspfrVqDG8G2ifdutu7I5s7+we7hbv0OL+KFWme49fXZ6+d0zazUrWUH4lWRziID7ohT8tOopzVvBHolgGJbBnl1dRXEMpWBzmbbpp0HLIB8e6GiWPaxZu3n4PO8WCT/ODt+c+wg6uk0DlFSTZ/4Z4+zGmzINUBPo7SrLz1UAZPTPEHjIb+82YxKcH1kPdJt1/5Vj9JifSfptY5qAhXHR4fILHOni8uUm4docLA3aByhL0ya6siZcslO0L51hFTUKKgtukYt2G/8yrzxjQinJ1S9g/FrV3XSYhn/EDS1eX1VpQs/Ng1kB9ECm6RwZ74f9JZXE7rN46H0lYezvrCamp8412tsnzPtOrKy3IouO+tBWVnbN+34Un2NXKOTdx/qeUaFzGaqqMowuxSoz6snGi2p4nwp0+s1EWAME5kIXqal/vVs+SDlMnJaSx9iKHjU9PulCjzE1k2t99sHEvtOVnkLqxAH8GvOHXDU8TIPpOwg2A2eHQwGwfDp3nD/HiTGi+UaPWObx6QcWqI4io3HVIp3z+msknXNWIh+H/qN+Zjw8BL4C1fwWveIN0PDJHEjtxdFr0JOkYBJ02iWMUcQUVuBmmdKR+/TjXSwqaenqy6Cy+RSuZkNdv7CFY89QOYxazyZdK4Z4MoV2p575JWSGatHsqgpZmjcpK5JUGxPEj3bNr2e+1BcIZu2dwbDve3BcJv8FHE663PqWd8wp48F43QWQGdr29OD8OBwsBvuqWc7O0P8EIVy/9nBrpTR7kEUTe9xQGxGywU2a53ONXcTvkSajd4dn705D57/9vweJHKZzbrp4mW+RFpvOHH98fPxc5shTD+/dQ1cR2TebKzKgPtHwGrkmaIDAMG1JoOk5iD0kpuNkmCcRDgnNDF1A//caB/h4cHu4V4NUfNMX/zQKtg50SBAA+kb+fUCrX84dOQWXp+9SbuFr4stMJ6qwXuiwuRJ68y57gcWu3JtbjfXIQcetw/kccuqzp1sGaPqbmvUcMehkZ3KWrh3OOU+7w+eBZLd0igIAFFrj2pxTaS3LqdcbY2O3zwJjE2FdVBLbtoCeIVGDFrQIFvUemNIYlQrRcJ3qUrcOb852CvO3tlIucp74vTNSPgUC7EFUFdxEoUyi3J2y6uFjF2EXeVtxv4UKDP2IAj15r/dcQlqvKcZzhlevoVO1/mgWOZzHymsJLZO3tC5ARKweHwWOua2qOXx6eTlEy/j2Vwc53mZSTTAGqkMwzlPjh/GhBLprmtnAK0itk6ekPqTN+n7MHoI8l6rAxWtcyNP/YUIEbF1+pB9PPn5w6gn3v5s9/MsDXvi7Yef4SrjD+Fc9sTJm59v2XMGK75s71EOlMTFujffLmPlzasnTa681qWRFH+L1dVDKNHZTKbcb2/N1PhL5WLr7Rdc5rM0/FJiZXJRpnHxFWmWicCKIP3DA2hvHPSH0I+iYHWhswtSyldr3vwl1NN6UFDseu7hPO+JEaku71pH+kQm8VRnaSzvRWKqiwsyHleg6SZvLeYOwk4xVmOzejumGfDIfyNTNM0xz0JRL804Cppk7Ax2Bv3B0/7wQAx2j4b7R7vP/jIYHA0G96bKjHhaJ1lmDuEKJA2f9QeHRNLwaG9wtLP/AJKohXF48Uldr70z0LGF7/IybHMC4oLBBJBbpL4fHT+UqLDMLtWaCIKSTfC91ColVJLgBIf8p4osr70QFa4ySGph7f7k4jktJqRxXiz3d4YP5QSK5lJ132qjGsHPGYTbQLRmv2xtHxeJr0TVwf7+7lP+5cqTsh5A/Rfa5thqgLCWkrer+VKG6MkpJnHRVu93BnuH98I5V1ksk4tadv9jH1weK2uW4kICev+qU9z9CtIQFNcFPryump3F3L2WO5Ri75dzyf3weyL2k1yNg9AmeCGsFeoEWgjsJVeF7UCHc0lVqlmbu/v7L3755dnJ09Pnv7wYPDscPDsd7pycHN9PWrgOF2uXgJyXE3OnSp/HVZsNh0Qg/q6qSeAmJs1QBT/dU5rpFafiVy1eyXQmTqgbEyd9XgdipJTzls7iYl5OoJZvz3Qi09n2TMNlOtme6WEw3NvOs3DbtHPaxlGk/wQz/e+vdnef9l/t7u+2+A8zbv+gf1/5zEb8t7Fcc2e6WjSaVJnM2WCW6IlMnJaXquKBRH4Ly7RJ04fRg5D/HizTpjhi3HiuX2v3jGk6Ov+5Ul174tXPI5mKF3A0xHmoPdO1J87SMCBD9XH3/buxSmuUP4gU325aMzmdZqnFo0lZbQu/mLLvwAZtEHo/Wv6V7UmO6a5XLfIKjHFIWE9pnbrd2zG3eM+U9vs6/6r0XW2df1XaNi1GhDaUWXbNOfHUs1g6NZpuPZBGRAiNfxDGdc1P6qMLSBmfKe2+4vd/5Eiw0eNtxrYK56QgVoMZgdnZO6vt6Yyjx1k/L5GbpqJH6IkcxsX1ujopnlgB2drM15gyrmRSR4VGRKi0uPCe0sfE5/xK97m7UdhKtHSrb+bdOL85vv0EdhGyJsb6GWxusTbCOivm4phsgXrPLFZbLuJcr4vXJ6wZnY3eks++hd3JcSdK6zqKjE7nzp7IVDa6itlrewcqM6Uv/GYitTVf6XQWF6gegIWFPI6i7GDE5v8RG4lON45E/+lucDDcO9wd9MRGIouNI7G3H+wP9p8ND8X/rYcB23x6NIG8+SFXWd+WPXp/wpGTTgj2bJ85Ojb42yyTKUaLVuoXtSi8FiF6DlHTWy+0fmIN08aM2Dgz0jekyWiYUocYaaJ1xjZzz5m9dgJJ5dQx6CVV8bJRV3sidMqah8IbXXjTbcm9gtSestALEu+e/G4H+Cc6L3Taj8Lavix1XshkXbdq8x2BpxvVKtkCHx26FZF/47kLVW4jZwq6Jm5uJOpEiU8phh6h5opIoYV0Jn4/e+cbOGaEYtUF/iqO0HqTHjK+yXgb+cc2757tDfZW9phmagYlZI3C6j2tcJus6v/1pAunNUkrxqdTWP21VBNVP3N2UtkaMDnnyY7iTx4L5h+yntNUkHTvfa4TcX6Ito+zGS5lKrd/KVWq84vjOFP57YehXZFk9Tv3i5s1PNBAf7VqHqRRx/RC+kxeDSVo9DR83BFFkV7IOF2XBPcVBJe4TugTtuT7FgsFZ6fQU08i1sctp+LV6fE7hKiOqbuu1wzT4O+Xw1nK4mi9/lMvjcZmPBui0PJ1bqfAbrupFF/r2fR5TggF3sF1uaB8bl/af99imNiRDvbYVie1GqEwiQtMMKRc08qH6Y8gNS9qI7WThvix4QgmsqMOUBRPhxGvT/d7yB4YPqHbsMwUqwSBOI4ii9TUDYKhkTwWxORaJPqKGnvaxPw6irQ40OSqH+qjQHN6Ra6WMpOFzqxQkPXXaytPMaLN9AwlVPO53L3YH+48cQRWNd/VO4dBPbY8uU00XXuvLXWJd1VfOrNXioxSZ6HnIIAWh5wsKJ6TitF3ViIDtLLxP+UuJwXjD+AlQ6QZczzyhNuj56qgymMXpaTkQxj8WwWG6KSRWCoMM8FiuJHJtS0r/xJh9LXLKL9+BeW3KZ78NnWT30nJpEVnrvOiJvrsv28Rfcf0rdaoLRO5Tvh+QrCgHTpSmtzFf34you8GP/FJ9yJgtiCc9eX2aCp8EZ2L3fVjoOTaEXO5XKoUneoxUwuqrvucWCiZl5npRYeu6hgimQbipebORHmjBGkuswgjUXqut87CdBBGiqMOP6nMNrtRGQ9b/Y9ygk4zNIQJSXCPcO9vLlZ6FOWxSunmPhL+ei398PPhwcXBXg2/cFkGJebj1ZHrPMw0Tz66uHlK/TuVwaSkBsFk6pndi3N/8DvHYTk4q6f06VBXCqqxfOLCvAY8e3sAmT10Z1CIEYIQ6UxgDo7OcJsGg24t64j+IUVxBXMpM2eQ+1E0W2XjoMlLlcmZVTQcRPv1vOemkQ8I0yEfE0ED9zFUyMfp9htcbUQU558CjMUK/Irdh4b0C11UAXBbBSy2ZrKcqSc0qQuGBaaQoDfDtdiSsxkGzlf9oYThu0wStAf8lD/hDveuLYuZGYfu4ck9O+4BXmBmg62fVqxTqPRbkvv17BBaCTyoxKaV8tYY6b4jPa+bvLkZoX8lAART526wURxEnYk3qvjl7O3I4oLDHdBK1M2mAzZ/UE/9lRxEso64+CVzF83d7JO3b87fjt6uuhUzpYPvyB1P6DhP9g/ukq8TsyYG+6fdLXYPt7xB8rtzzftoretoMkqruueBkvVC3YHOt3PRA8k2v/7bTf89uOmxN//tqn90Vz3Y+j266z28vg+XPRD613fbV/RCR1sT5zdfMmxLJ9byLtVZwQZeVVOIebYsFccWszG8CgvclUwVZZbm1p+MD1jrPNisURVH66CH/dy0buwPEDvOHR+hNKO5JDdJLOkrPchGdvVWYQxEOuJ0hgmicSqudZkJlV7GmU4X9YFxnNflMuwzWMtkfoOz44mSRUCcanJheQcX4mUXndg2ES+bRZoW6kKGd4B98GERr49P/GX5g0jaUWjZyT3bcWgKIyjfvzgRTwd7O2B7Xs4wtlhFR+I52g7rsFCF2OIxZj1x2J+4dDZ0bSzUExF73nv2Mlxp8Q+Xdf1PMVefZaTCeCFh0s5QIjWLL63vnPbUweRzbhaG/E9FmfJI5hiz21UWiJExKRG1oQ+asBf71nkyr4M4v17OVcfjufmPjcGgPxj095/Tf3f7O7uYit385d7GP+tnYl13/c2t9zyUqb3i5oZ7t9u71R/S+DO7pKzeQn6GP0qkycVONxO+nUheP0mH0yaEVf4iZGVRV0mRw3LOBLYyonlNMHXr21do3NPGJeLR/YGa4VQ+iuvhJqcDzCsBl6dOyaXAS4vYDlGxCwtLHrj4eC6HBqlLGX5SxeMRy/C+O3LjdH1bm6lQUSqhJfo7oXXde+vo/kb06jyYykWcXK9A4UPk3duRMPDFltXZMhXNZdETkZrEMu2JaabUJI/QrI6aA7cbYJhPtvAuk+TxsP7KbUhakQWs1OwE5zpSsW+pU719LUPxdiRe6/+Ul6rJo08IuCTr2tsmDWY1hzYedpHJKx7U0MJ8L9gLBv3hcKfP8egm9u03+MffYb8jIzPqpi39rckPmxHyeDy5HWO7Ht9dOEp03hPlpEyL8rb7KrOrOG1iv8Yerkj4JOE45nXG7G2AX0AWCpVxfxp5qZtExmmhGaYQnrE5ybSMyKRSGXVgJTkW19Ttt+7jOcoTk0Rf4e6yAVPFvShat2XzSdSTI5HAXd+DVUYcTePPVU0k89XXDGkNnIlrXW5uZujYbuJzOE7WlOI8jCQ28TfOyLDhN3xiolqNyKNAvENremTBwlii/EmoTXqp7JwSKvE0IefnJ+g1r1G7sdS5ErF7G4VrXN/WwonMf7vj/nhHhS/Gmk5L65zzcncKrOEgGO4Fwxq27VP9OHbCOc/Wa9gICPWcJLqMbMuyzAaUTEUGtp1NfVpdJPEnJcbFToBGw+ViHIizqbhcVKetHTJiAwSjnqe1GJbt4OdXglTGuYPYZaTXbYVyuWJH3puUqpEKdRrllUKEIQsTpVJRLtvbtruz76dzFEUtA/Pl+fm7O9I5Xtj8NVc0gy+Zif2oJRRO/pRZYmUPsrXsEAlGCR8ps8SKmEyhWuYRcjEtoImOrh+intuJSv5X6/z2u8s10Be0apPph4dPb0aRuyevgKRti/Vt3vBztqzNdt9K70uVJFpc6SyJuulew66cUxpCftvebAFZig2ZmZcdr/Vwb/dpJ8pry4PdPBZlZyqsgjuqxms84DrjFMJEz3KbHcJwhQiTGE3Vicac2kEhVcm0FJV2LJT7NHY0jqo0SSPpInxDilSnfWRjRTKLzJYbplX+5vFv/fcGs/7Z6dhB1ZkY/9Y/YURjneKv7WdwuLOr9vYPnvbV4bNJf7gT7fbl3v5Bf2/n4GC4N3y6d4+EFrtJC1XM9do2qrYXZqnbpuFnSEhLoRQIzpt1s1xsF1NOP69nR4x/fX4+rp6k8UwVdirKr+p8TI4/OHqazW2splWPMbUZ/+7t6Lybe2seLrD5Oua2gvZi0n2sX9IaR13W1AKTdiC66ol/Ewldkj37tgt9HR4J5IqbUPz48+MT84X+OenIPAZXnKCLdWatzoWPsnRAof45mN5qSFzxwfKdsQrJXCVL9txHCv2+zTLotZsLl3otxAL19ek0ntH0Kb7U7Z2MF3Kmtmfxyg10GcsgU1OVZWurAX7P4Kuj6F+dlsy1vTXQedh1DECHjQbu+VKnufrq77pZdtWH3UfyR33Zb6P45qfdUv6133bG9mGPOyP9rUUfo/F4ss/bwkcUfgy1Q/qZvzxE/NVknYPKysujyDxmLlotlHlHFsOXj1it3xuzUHcyw96gnhO9XuOe8OIl2reBjHeLiPOx+9agG8R/h0kIAeIAsFFGeortcYYOTBkUSwqakDJj4kWNdUXNnKYG3Zni4fAT3G6hJ+ZocFkTOtdfySTpiUyXNPoDc/PERCaoI8hs6wz2BpFA/uyuiYM1l2lEHiTpYg6hTlMOHwhxxl+HZ0jbkycRQ5slHpiKBQY5CytXaY6O/1Cr86VMBShCVCe5ruFhAy0drGCd0pcAX24pyySW+ZqOnjs6GFsDX1Je28mqtK/XkQxmd5UBCxHj9izsmCOj4xKLY9KBewiA8g+ZiBZ/ikTPANPbklQuutxY/MVVpUkcrZ1fZ6dNZtWOfcWt0ZvX7yoCGagQZ6cdL9/KptTjhhluIBGL3HwiWtirYn4H/hb7RM98+fVKz+6QXJunrbIhssnwkiV6NoNQWKhwLtM4X8DpZ39ZZDLNgb0zWCAEocG6UiUIwGq37ixXai3HcK0MDWEmKMjObZ1V63tlzHU7Mr/OEz1zC02U96RRfaYYA13zseCncY0Q+y1XBllo9mNiJcEzYusUQr0AESry4f/kbFn0g88kO03FmPgc/DQGV5GUT39AwZdhX7D5xfLNzf9c04HebI2wAsOxqrF6iEIR4rDD9G9ENBmiuHXG1UqzrZqhEbPulczTzc3CVOBgfcrTnfGQj0gjN8qdyls9BtuXMtvGWJhpmdIkkDywF+3GG+ndSW+6zaNGBd5aLwq47vKh7TbUGzg53vDJ5Q/Sh2z0IBfSB5WRqYXR/rm6VGhgjUBSveUsFkOYjNLFZlqRt42uFeFjAgx0b3jdSCuzK+ZiXSPIVino17okb9yyLPzb5u46pJJFRsxVZhWNEd1h96eqcFqIkV4ou5MmaWx8JbN03BNjlWX4v5j+U+kaMunw1qks01l9W3HTszXs63k9M50X4pceKqBEuyzOtnZtcsu8JBXCv1g+lDCRuU3gitO4iLlEsFqBdAe2VKQIy7zQi+4kWp3N7LwJMx8pmGhd5EUml8Ev9qcas4zDL8DVC5I4VSsIJE9at9gDEF4ejRs7wi5ca7/xmYMhwpSz49GvRWvclwapezs30rFGTWGzeQYeizr3+66OAjZ07JqJ8Px3BwRYmLzAEN7tsDDfqxbr/grgkkyo5lG3L5g7N8F/ykvZyfQyDdsVMo/G8xbLeTncCuPsbnG5yd0GSbFtBGkJMarGCvib2fE3YcnSbqEKSa2lGGE6wFymYiU7FW9Dei5QmWkXEeIylgzGRfxzpcT7Fye52N/b2QPRu8ODvUYpvvnGVIZxgkjnOlwJmx6F3PlZ2AXt1jiWE7XyUsaJnCS+InAcwt6mm6I9qmB3g6wbKpJlahOOq7JjBxLf3dltH9yd3Vt5tEYp4XEKL3XfeMRWZlaDDtRTJE+7aFlmMbqcXD/6Vje22a5jMf/iLVYVyDgXh+Knijl/ccpCUPc58qtipv1nNKdBqM/oT8JWCCdkSz497qDQysNnw/YJGe7ud7HVIXD/a3TnjbGw7zwETfWwZt5Qg3oabOgJDF9brDr3NBd2cA2Xms6qs9PRk56vGEKzayHPN3OmwXi2l+wfx8GtqEPPJAXf6plAFm2ow8LBJwQoNKWJlTJxma9ChHppbHKm2n6pE5XWlnfKBPv5tWsOjPI3OwxuwXqlwEqHAJLsphPg2RXfcPM9LFr7/pzNBLvz7AH1fTJvvF/d4pfBpbb+03rjGJAb6sWiTNkIMBY4mjuxhiKrLjXUaNfC8Ru/VHkQ3koPajNjodsEKAbbLBKVqTlPj+DX8AygdV0jmqTv9Ur2lgRH4a+OMbEjr7JW2MnA7GCownOkO95tmXO6HSGUfvYu75FDPO/5HepzMseu4syOunhSmcmeys76tjkVHpKQpxWem67lgINRc5W1czIqsvLaJnvUTJTN6XBAPYZdqUnFJTj+xxTmHndA6nFeIlyjE60/jf1UgHFxBdU1q9xz5sSS5cFvMEUIJ8rvfFNo17GNGCj+KFV2DQd4687KeFE7XR0B3JZdf5/g7abJzCKwxm1J2QHk6J9oXClq8dHITTqbirE5JiaiPCb9YowjE2NUpk7d7zMunu6JMTPX/sloMXHFzbxctBmwe3BYYwALl+L6Ym3efm8SNctsUOcRJ87ecYM4czNkLq5UkrD8Y5ACukihQ51UZfR10eiNERKF1klfzlINv4VwqV2Ftme9kv/TRF/dPTnam4WCA5LEs3mx7ZjXjyNqotfm9/Bo/vYv+Zu9l395/ev+6/+9fTg/y35790e49/tf/xz8XNsKdzTq+/AofqaNUwvcKgb2ahaZnGJ61Mf0vZ0co1x6lczU0cdUfGSQQnwUP9nA5sdUiJ+E8n6O0wnGy5h/6LLw/hXzJGb+0mf7Lx+y+EmUKR3uj+nHlITyQi6XEDz0mHDHKvPgsQG00Glc6My2XFGfi54PssPjWyWmAcxmLqjDBrhyGaurHvd0dJWrufi4YQne8EHrTHzcYOo3glvxtazG5AOVxQtVqKyFvw/bknI7/jXEm9vqFqrxo5M4s00bPfFxw20a/ctt2gZTa7fNY0TwMa3cS7WvsIMJQ7NpVYeRoAVpaLxJ9YtzNKFLCx9TmhWGAzxpKkDWCEPfMGxhTioHB73dIgFab2A2Uq5rYA2aFSVu8dqKfCk61rK16T5QC80GRjwkzqscey+jnitDkB2H356N3uEB90H+7d0b96Ky2p3lwUZTuvDm1cTIVGdXMotUdBEv75Ak8bJLVlB1ezW62MRgPCek9yeOCiwz/bmdPTV8thMMg2FQ96rGMpXrncpBrSHe2cfiDS0ltqwgxxxO4BDobLYtc9SeI9aeb9vnpW+Qa/8i+IyJ/y7eLMSInxVSQlB8g0tov5Xz5ssknqX8oOGgopHXi0Rf0cnP6SeuLnBwKdvZaPfoigDWd9HUYvhBndFpqrIv8j+y9RIQJD/QKyNMzI9TV/iDk8+SJ7hMZMofZqCifrcofyZV2QLn7G+vjt+YE/ZHP077f5hfFNKEh+NccOuBQByjyMrjEuNjY4dYNogjYiv9zEFGwt3DqRHHLXMPJOGBMngOeuNhJOlikhRxcw8HO8HwD6HSUC5zyGaocqCvEvMmA8YBNZbw70p96om/o/HIXGafgierRhSJ+QFTt8J2PuTGEM/bqRi1dJ3mYRsOHkDBGp0hb9myNwfopqSLG8m5Z2rMGgmhPhGz+FKlXIxnGkrjjOVsOvBjHztrv0XOryXMzb/H07iGdmdR+20GT5dxYyvZH2Le8Hc7DJzqLx0mjv2jA2mNnW4jZ2evTjXLzTvIfshmbXK23NvRmXitI5UYTfPKOlRw5o4rS128ktcqcwgF3RY6Wc8IVEdpPibXwDjP5+szil1dnGWYi8Gvg2Mjvpp2bz2FoEdpM2iMjDSOSln9D7OO35rFtZJxuIqEeLtVRsueKMJlT8TLy4N+HC6WPaGKMHiyLv4VYYN9rdzqr3bW+Mk25ywAB/YMHyqHUrzMVdgTy3hBbFkXU7B0jSs/8hP2r/B4WVosFN9P/db/3S2O6mMvObPuqGZvoHSNTHoiV2EJRzpJMM9d6dAk68Zm/Jkk+Z6FT1/iLMAmxP/H3tc2t60ja36fX4HKFyW3JFnyW+xU7QfFdk5c10l8LOfMTCZbNkRCEm4ogkOQdjRb+99vPXghQZG2KVn0sXNUu3dOokhAd6PR6G486C6N2Cl60Cb6xhGjS/3kh5ETj7nIF1vTHPULAw40tZqBGFZV0Jc9NC4h+NGKJQ3rC4BIMU4wXdc2pFyssW7vTWRb5VYRX6lomYdJnEqTXtbOwFYUK37xYVYYypDgpBfMwNo3NcO6JDkzKpxBIKQkVUNDqoPzT0Y0WYflS1c/nZsFPN67+2JBjAvgaFzwh1mPaCV1rRYy0wtpMaFaNyShNeStuDCjaoRHzL0u+cSkKhH375SpZFfok5PLM6QbdNdy03CDhziwVUWyPLWTDWOdKYQVoSi04bfywOqSk6PhGm5DmIuZXy2qs3vdFLEiU4GoyMXfqwy9AyZX0SbkrojITxJYRa0QqgajOwQARQCh4ULOzGPiqS4hQ/2UgMazQgYsG9e+uaX3Pyqw91bqaQEC5cWnBcSp7+EWAzGE3G8uF2XezQTS3TwlWPopQUmG3G9cgH/u24ISxw26DznPa39sUGLoJbtxLgsv3JsrMVUux7s2fmxQYSvy2lsCa0nv4+4uGzxlhRtAGjOKoYtnhemRdWouFdrkxGTa8zPo+NO3Nvl40SZnbIJvINBbFOg5oE3elR6GJZumBpumBpumBpumBpumBpumBpumBpumBpumBmttarDY06Do51oCTIhenH/VTAYPnyiVwcOCf/rychk8XAxLN8mMpZMZPPzLZTPKLJetx8tKZ/Dw5eczCjz8MgkNHj55RoOHnpi5oJ/VMhoW3mySGYaRzEhba1XKZqgsRjboA9mM40/faktyNQBgDvDLS48VF7fhTjeFJjdlCjZNb56g6c3a9lrrKK8ucO9aWuy++qK6/DMFCjQYKhHuiWfQlQvF1hyMbTYwH+foPetT5DePmGumwBRZCS/Esmi9MKEh/89iSHg6JqFwCyaA5pAxn/lu6XVDV8DGCWGzKKkI5PpXuNadD3/btOXYtOXYtOXYtOXYtOXYtOXYtOV4bFuOKBZ+6iUNkQqYk5nhDodmgUS53esV6JMs5jRo9gWMTYwBpMWzHv5F1728+9fju19O8zK6rmSUmBS0DG6bjrbwPs7ZVZcowqH8KbSZYfYqxb6syUeaR0x2q6p92bdPcVaHj5Br6/QBUc19IKqveaT+oxww9Qe0AlQFwjTSCH/KQW4VJcLsmAWR3rDQF3EDQv1DDVxP4YbzGQ2ThfR25f5dC2mZqpkpui7SNPamDHWeElFEmy5+/mD/FQMFdF4QxUwH2/ahlKmXxeLCrNbGzWgIoB9gmF5C0sie8Y+B4tGIX1XKdW3beHB+mplC/QA/K49J43hOrNtC3VfDmSLc9Ecsof26Vt4LUokniw1GgoX7eDPdsuSlcVPe89eLM0scBG9FbVTnMTQ3qyWXueO6qnDxvzLKs8XrJvGzHR8ukd6vud7C4Cf0B9Q7CpBZXlTkH+mIdbRzWJchN3hviKcvzhSEjsfMc3KFCnBMXiMdoXIPnYSFeGPs2iYzKCGSJWlU4bWbqot1ebYW8al2sJ3PmCaq+M9Nq8NIwmTSiYTf8XzvVi7NT4Nxiu21Vp8X8yi7LhMN0v7F0SW749VwpvYStg3gbng3EIoZ9YGK9wKR+mOA9+J5WePyn9zP353bLDvgFz6/50kJzJc7jjlrCQtj2AnlMapIx72TzQsSAaxPQ5stQ/pPIVcK3uYC2iPzOC91mKjnm7IgQjRHaBzTUDsLYx6gXoGiQXVzs8lBHvrsJ1KyKj41mzxPOrr8rKO2c2PXU6Zff1b/Jzb4luKSFAOHl50EctjK0nd2PiELSpxFpcP87LlbkaHHX2w976xyYLXSLuY8Hu+Mvsic8SZh/EDC+AVni1+2lVhzqvgF54k3SeJNkrhOktjsh4ZUpaThdY1UniG2hKKUBJ0w96Q/dz6694CX7OHzXdWVBpA5YH720NfOauk7TewIkqgW+DSoGsr+LEeIgoe2FTZcVPSAdUZVWORsaEMIdQtS5mMB74ohcuDzY70QtAfgeK+bxqwhTTBrVZiqtOo/D/av9ncLpI1SHvgN5+taA7OXKlcTe1tRkS/f2BRDMupixiS5tlTVB8lqQnliNuMJGX4cYCTdxDZmyiz42RClXb2zP94dv2UHh76/3x/1Dg8ORv1txnq93ujw4HB//2D/7dt+z/PrbnxvyrwfMm3qbDsyw5eEZTlUEQsq9o7Na++SNuwfjHa2D316eHC4w3Z2e4eH3lv/gPp73ujQO9wtXs84kzfE0XH+F8uUXaxFyr9ELLSgsigWk5jO1L1JQMNJil2QCKNSUoFjt1CYEtXxt9h4zD2ev3onmSkqBnNGnFfSE42d86ehr5YmnJCpuHUZVi0LshU1r/1SyeIObFLQJpNAjGhQkov+uIoR5tdgwqcJqyL0EgZR1QCrpK8ouYB7LJSsxnSryKx1poc3zaXynKlLmd3sjp2AS0Xhw8SJOSuUTPFLQ3Be7DfWWLnh+fE/iJ3uDHdtt7iHy4aMUL9xFLC8hJ6M/J+qfJ4ZUm69KXsPg4h6U5YNvN3tNRgfVB4RzhS55ogCFQ32yzpHnW4lycK68ZJCOdRtpRK9pzwabB2xIKDx1kRs9bv97e7hYn9gVX29sYT9R1ytRubKLJvMvSLJPBtVeJPL3FXJupUSt9z8AqdWlSYCtgzKVPe8gcNTg+ulmlNYjSk03S3RvL+9vdN/suDIpqbLvoACPpr4wLh6BRVD5x01c9t2oEumtPgVfamVX0GAnbwYzTsSR7M28aMfkzYZxaiKG+KDCRpYhqn6+H9oXN7zcTSru4zNemJ2QYuzZHTqLeUGBcV44IR8VL18V4kI/q7jQHIu4gSqT05+Mi/Vf3x9fvIGZWNUK58X4W4fnX8tTEMSGk9YkqWJx7xic//c362rBsX0/bqpt4UD7DQFBAVIb9ueFj5qfeNbPGCq61+JqU8clYzFOCFHIo5EnF9u1GDToappVp1PV+T0nLqvsx/gDGM3HFZlrJlpVmRrv7vTPdzv9br9t7v9vbr88VmE4vUNseaUxgdHfIa3gPAQCIUVAoddMggtFaTTQcCuv0Ycugj+xWDOLXBhzMMJi6MYRcJHPFT1tlX1KkLHuNWKUe494kHW50I3JcUL3o7bxpKYQp82nJVkStUjBS9FZ4O2udDXZQjRzXUCRD2K7MY0C4dBq8mwPVhqHxWaga9ic6bq7Y/QjTOZot5XB08yYI+2tnv93a1efyuJqQfsQGdGA/gjHS2cDiZEQgglm8sHVc/bP+jteLvscHu7jz/4Ht073N+h1N/Z9/1xXe2wfTSusFIVT5nXvwceY8GG54PTz5fdk3+c1OXPQB2bZspM8xjmXmX2+fvPwYk9hdWf8+Shvsh7dT/3Du+efaFsHQPno7vdglbdTKGdItsRxR/SML+UVj0Ykfm1VecK46kkbzYc4f6Wo4qmCYHuIqgK3nXhQzJybaePuH9NxDhhITplzKXNSeupkC1mASrzZasLriI0wTEF73U8bjLXcA0suXle+XF+zkQ2pIKtQRzTuanbroRH40mqCsK3IYw4yfL12HF0JEWQJsz2QTZDqnodhGWOnmPiPtE5akNoJIGWGKr/MtWdKpQ8wYMvZy3Ltqr1r1cqLhzxcEvKKZ5zdQL8LxIl+G+/18X/6+8vPuqC3K5UTYka0ruz6PMZCydJdkRZncHYCioxd5i9dHsVhQtdDmypWNMGAxxDtqMUpZ0JDWkwl1yixt9U3GZDzmg4z9eE3CKezowCSmJjjZytRD6p0yT7AQp0oNKndU4I4SYdhUNvTGQqI+5xkcqsp1V5CXbvtxi5xHF4Xkk+CSl88q7PJ0wmVzRA1atk2lSOFAaHmAOPZJNlCEbQU1i7hQVTx7JaMN04bZJyOSUZF6YBBM3au5qXi9mjOhw2dn8Si4CTMOXqm7Eq6mxMVN43TssmJ7csdTml23v7K4qe/eQykUWBlwDPIyECRsMqmb7X/+S2uUV10lwsbm+CRcpbqOHZWpFy/ImHkwb7MV1OC1nAunrCTT1Up4nSYicyMqNhOqYeBORDFWieKNLo+G5FuWn12jZgN6YLC+qlM/JfX4aqGkZZLzwx66K6K+v+jLyuwvCvKuqEJqlsSswP3gY5HeBgx5L0DpFbC4rW3TCZXjyPEtwGRFPu6W7lMj+j3FFvaMB9t64UwvYYHdTMfHDBbxhJw+xm2fYUtj/NfyLGi+NnwyIbnYbqKohV9NQ/ubj4cnH19fPlxdfh5cnx1cWXL5erLlmqysE0VTZoqIcveKKgwJiyRcYelRRY4CxhdNbwpscU69z5ajx1/YatjePU2e/Gqe/mGz0bdMkNf/L7x398O/h0MPhjVdHihEroLKoh3Lsuh46xn1AgKMluiQrKoYy3utDSF/rYSvg6ZFuR6m9t97b7nR7+/2V/+12/926n9621Kn/Y0syvwd09J15rCISrNE0VchtRse+JN6V8oVAa93WMlf/8rt9ZnwzBqjo4kESAqJIpt8aYFGEwOAiKZcThZggR2IZccN1YMCfKBKlpjYFrrfVsVkbxkWKu9ixAsqojQYOij6GvtqFME2A68gtd/ELldeYKS1Js+V9p1mlhLR6w2cvKaTajoX8V8Fo1W2554Hs09tcJ7ytK+UMaBJYq4LZMRQkV0jHfNXaLmE0b42WTmlhvIcbTKkuDIA82HPmrt4mlKOQRUaAbApKO6oEZkyzyq7tMLBh3n+DW4BP1phB54ebAmIOTsw933Boc7HfqXxyAE1zTXYnYXtSsn4/384QRyf6dqttPMb6b+DOeJAEjJ6FvQc41efCi9KrBa0Tc37ivce9kAEn2YCnCkY9FAUfnwFzlXD35iRQuLJQKMzO0QdbmEuS2ZH5tZvEkJjA1YxLCE3X6Ag+TwGk16UK/q9B2NMzqdI7pD11Px6SMdSsJEcvuMvyzn8gz1wkexgFNEhYyv4r9M/O4WQ/HfMJ0jTslaZQK8s1z52Vom6rbyy4dNf1C9Y/ijQy0y+129V4fT3k9t9eD96dvVmFFVWBsiAl916v7Vdy1T5ahFVraEKnHeImkjh+XUDPvCqQy1Jh1qzHXzBA+DKkwQs0nWIdkdVOThnW69ar3s/9K3XBnbdIt0bk/uRTZ8oqOeEPkPrzfrOTPgF4nX4arSL7BI8poyn2n1DKUPrnBM/MuQ6q5561BY62zg8/WeHbYHpM1iAuZTKopaw1CfTlgr7/MmwS9gdXeYqjFZyYrr7sZFGkwBq0ycZ4JBxEJ4iY4tM8nVMFcDK3709rKIaM5kemos9CMVkEqQwav/trluPtfeaGPpUTV9aZ8u4a8KgxooQxMQYRHU96R/07BThSLER3xAO9akaKP+Sh1xWboWGmZu7DQIpqvm/zhlIahCIkZnng08Ey73LyD+aMIHwe0sWtBKOLQ6Kby0NRkq9FZzlQ2R6b7vnA5Km3l/ysxHkuWPBXBerZHkrw6JBQRpKxFqJ2s8OBmOXobPENL5GKu1ai84XGS0uDK1F+tQfBSfmGJUjOfrff6OKKbAAffSfEKmjDB8fxUR6ua7M8+WhURqxyt5od2iWvIbNWNYyRnprSHkuyuRG/Dm3yB1uW3+ZTGDGnaUawAgQ2Raj1TPR3JpkPa0hTJ4dJhaCkeEhaMG8Rk2uGJnM9GwkAX4bHmW6gusaFfg8a7EmNZzxAnV40hqy+T+vud3l5ne+eyd/Cut/duZ7d7sLdT/0IJYIBk3uD1492VRgxrC4ap2OwBupSYm0jVZlfdiBioCfIt5pWcbiAu3cKA2ahiTG5RRi7r/qt0UT3QgWHLIESd/P7u69fT4zYZzuVMhBb8R377enos83ffqCmXgXjVzKliNZhnd6UIfpymsmKcT+ZwfSRCmcSpp27RqHn6hn4YJcmhpI262xDoTAvYkqeeAs54wie5NhFyfnpMYob3olSSW4a7Clm4xDVdm8fcswQRoUrcczwzprjclovFbYhtcwLpCZlU3LF5297u3p5/OD483Hm759dWwuxyZX1a+MSVIwYLAEFXwx3+uvdd7yzIhCcVfZsecmuKmw+mhP3k0H0/S7IYqvK2YEqtEgZYntM2eWFfFq5lR+q2DIemGiOv3plPZne5qYimXiOambNx1aVcxRPC/s7bvz0gfismbMDuzN+rIaVVzNen4z21x4tPQNUnckr7Dc06/Djo3zNtDo5rYOLtvf17pt7rbzc39V5/+86ppc9Y1NTUw+OTk3Nn6hp6t97g/omNVcseaZjB2ee4vIbnIZG1UU/tY6DNbAsHFJ2Y8aDqAeCi9YpoDBOyAXAvB+CuoXiOZDcQ76eEeBvBb5DefxrSu3oFXhDgu5qBDe67Odz3HRLfwL+fPfz7jpX7dVDg1QxuwODrA4PfIeFfDRN+B5sbaHhD0PBqeW8Q4ncgxDNxbYDiLwAoblbr18GLOwy9dNi4w8qLRI+79P+FQeSOGJ4rltwh8ReBlJc5evbI8jLJzx1gXqb4JeDMy1S/JLh5BfUvFHVe5qTBE24d4PMywU9uIc28K1D8XKHoDokbRHotRHqFxF4aML2KhZeET6+i/xnD1KvILWdUm6N2KbR6FbEvA7R+L+XPF7teRXaDJ/HjIOxVxL4UJPt9tD9fQHuB6g2uvQauvUJi2YLXEN2qm8oI0ExpzzTZfQzZDduBx6DcXXJfONjdYeXFYN4tzS8H+p5RvEHAbxDwfzIC3upidnm0PmVc/9XZ2rDuywhmg4avgYY30npSUPySZD0dbH55wp4QWL88cU8IvV+WuOcGzjfErTel0QAEoTEYfn0ZRaz7C3STyZn5i/SVyRl26GuaaefTp+gwk/P4q/eayTnddJ3ZdJ25u+tMrie/fP+ZjFMDIG6aPTPN8+hEU5bDhPvLRT4PZ4NP86ja8KuatDgv6gzw1/yNjBgiK7wj7y5LPvcfAOsvRbl1m3g5K7S7vbu9JHEq7CqS9/iclUGRNpe1UgbK765fLS7VwOT0eB2yNVQ2aJ8Mue6NYkawnr3TW5Zo9HxaH7kNxg2qO5VzwF0aDVSft3VKDhZZ5u/1qMx0FCR0yZGDbcye7lk6lcJYgRJKRrG4BaxYskRZM54YImwW6JaNdPtY1SMuTII5ERFK4HZbS65CGoHyGsvgaHdBSkPmidAvmrApIJ6MhSSNStrS39le1mG7FTGcgSufx8xLRDx/zloD5TAEk4xga/qNgEpC2ZqKGdui6PdcWza/RkT51wklf+kY8i8QPG6ixk3UeG/U+BcIF//yceJzDBAz4p4+/LNTP6fgztL0Z4ZuCzQ8h8AsI+kZhl337LxfJyazUvnzIi5LwXOPp+qrwxqCLUtdzCYo/T53+1FfuJ/d3ZD6g2IXtVbUO+9E2MMmGwCaEFMHF/iYds1AI3VddPIa1q3AT+uL8WOImoXcxhwNdnQNlBGVbH+XsNATeKntbMEPIs4Yj8uMt4lMvSl24ZAlf6BS0slPVa3hgk1+R+9i81m7WIxAtbqWkdZ4kcOx1GM0DdG6DqIrfHbdzSpoiMg4m6iYZFyGfMwRS6zXe8Ni+8KChi7UJH+3j9zMxclvV+9PPw8u/qk5Z771YEv+5Lff36eDo97gj9/fXw4Gg4H6O/4wGPyfvz2g3oUl1kfzwiKXzvSFF/zFhTzSZQl03SksIzaKHte+FzK/I+Q8Y5jiQZEBC1f9EtTZtbAL3VXLL3k4yU4XYr+fKYOakryGMIff2gT/PfnH+eDz8dXw2xu97i7YJ6OBJ3lwI0JmxjVTmnfgEg6TmVApKkb/9PXs8lTNpca2wwUBGeVU3tCYo5AACVQnbj1smM5YzD2ko2muuRjz+O9fLo614p78dvU7/lYgPRu3oERZ/SCfeXxGAxIzg5dWZkFhlcj1q/6r6wpoUutfr47efY8T+h142ySJvo94+H02p1EELNsSZe3ATgW6uKRVq5iNYUJDn8Z+phNqLH2MGmth62TIRQ4h2OG3ulxM+U0TDAxGo5jdcLVemCjLcmG+0jHy8b/PPtUl+AebN0DvR37DOurUQdUKVR1DjOG4lc+84ZcPl38fXJx8z4Mia6o/X34/0h6Lefj4/XSG1PIHFKk7UTBDKOgXZVPk91sewi+E3tXlHpQ1wL5qQYmx3cIhWKo2hlM7VNnoRVlg4b4/WiBmVFIlmO/HbJROJiyuKyGXznWK6LMTPqs57FleUpB6FFt6jatT9JXyj+52lVpOfUvJEhzVM2YqU42ph4MYlXEifiPUiUNjkYY+0MycqVoflj7YMXt2qRov6gvqEHCrwZk8mIRrrDrjhnMSBRTf5CFOmJOjoUGekkuXBDO0zjCBEmMLZqh6KmLndALYOgj0FErG1k/hseO85LGkqc0Xkmsjxe51xskABtKLWZKhyyGh03P75olJm2KzCT70WlIg6TYRI8niGxa3LVTdDOozmRiQbZt4AWdh0ib2q9glIUvgRHfHIr6lsc/8Kx51yemYzEWKKoTM1Nc5Pbd2OxE59Ty6bqtvgqQE7oIWmrKelEw40oyn5ySJ+Q0H3rwNzO4Mj51jOCaZmvNETYZHHm24dVm1U2eqd/3D7W6vu93t79mXQY9xpRtM5w6CAEqAyGzKpFYPEUJQsVU443FBu5JsW8CXcAqmpHCp0HszEa5czagQ+ZQFEbLEkiepWmRVZjRmmKoV47GAxMUNXiZko1rCnBqnXJLXUAYkftkYGq4VDaYUwsoJeNO930g44hUyaSpIgXyh95hJ5ilrfOS8ZKgW/Ik+CsywpPB9fZQw8uH348+yTXwxQ900NUubYJtI82DEfAQlDziVS7y951ENmfDoLq6NPT89r2SuMFMqWVxjrsfoN6YgC4ugPqtahAyb/4CsLP1xGrDCIWP/fs8Jc5EG5uGAfnlo70BsqTfQYN+kqPOChvPMdhJh5UUneEIAApBCoklWYpDQgMWJw20o1DsMLf88ojJKpqZwHhCZ0W5VnGnjA7WgsUO40cJ31jZbovwZl3BJcE4ksQhwmCU47mTbfhWEqV1wejzcOj0f5v8w5jG7pUGAQ4SN7JBOpRHnC2kcmCppso2uKCrcJj5LzMNWmAp9tElGXp8cX7whUuXSs4dLLPHWYKFpmkxFUzoM96hNRDyhIf+POSBFTCLJUl+E85ndapoICFb/CRZW6LbGGRUkX0OrcZnGKOte0PvM72r969UwoXHnTMT+EnEc+jNP1pq5KwhmYCcwYjGVrc1QzsNLZjrbmHPKisCMSZQPkCuNGN8nikGSsFmE4OvU8eDOGP1RVyoODw0JBglH5wOrIODZLreVQzWT7wPh/SAxkhYyUZ5ilI4C7pHjz0PdG+bj5eX5kGyRy7MhUpiJ8EQg60qA+w0xPtA8nh5r84XKyfrVIBIbukkokZ6I4KPDr1bm0/FJzZgkN5uVirOUwvR7/bpyQbI3lKwh4bhhlpnJeObah7rfMpgRiXm1hpCI+ozQG8qDygd+g4h6U0a2u7Uhd41eQLHCLa3iU8ROCdV6++Lsy9F/Xx1/Hl5hE1xdng3r8hYzVQffa4rB1oWdgHy9OMOOpQ9VHnfX2gxJimtupZD9KwwLhodHr89ak2DV5d1bLUl84aX5e+XibCpcw85stXJ9CkWSa1Gb8GKFRUoCHv5Q/GjYhSYw0MBCLYKRjU2yMW0lL+UEdVuLy2hxGyzs3vIfPGI+p10RT7bwt62VlhceGEueYOdCjpIlbRKJgHvztvZY4B8YIKI9FOcIt9TOXursRyhPyYyhI3FJ/23y9OrcmPyrD9r7qiunNH0mth83pJCZRTGYEY1HLfMzQbYXDgPdwPDh4yAbsdqU9Pu9nv6/urJrFraGrW0Ra1sEGWYXvKbYHDFwrXQHB6Dt5FJmrfsAT5Yjfeq6odMw/+Se4GlgvgddtSVb8KIf6q28faSbkDnLggpPhKFZnnHmqKuFQd35CY1xS0gkU2GLbDvf1+s/4vriVtvTcSBu1b1c7OeRFO5jLo/OTYDVNqXDLJn4W8w8xm9yBA0PecJpQIb//Ewi6v1gyWtpKyaaQTFgTou+9NG6mDldizMZAxnMS/IwY+JjK5ckpqGkZnCVoTTxEZrkpMiD4YQwlf3jGXmVjfcK9kOdas6wlopwgXCJO8/sn030aIw3rHhCeSDzo8mMqEkBJVgcKhemcPkwKZNhYQIdVysuzIj5TRcPscb/k4aKd31prLOO5tdVg+WiDUVSGhJ7Qi9jR23OxVD7SA+/ZVko3q2hzVaIDCqRbEbDhHsgEPATCJqGhP3UUEWTWzWDcqlSa+gOkghyw2VKA/4flt9Ag1EWJ7SQe7N50zibY4yI244JR5nmB4lOnJorT5nwICAslDpLgdpKKmOgbjKcJK7Kaox5EGS2iUZRLKIYN1fBfB1Bd+2KXqvYw5baDWoJ7YJl6e1CrSg6G/FJKlIZzLWWq9+YIQlq1wcK6AM8jSQBl+iBRU7P24TavB2MKU6rn0QK6E+XkH/mEgdMdI5XRPldiznK6a2lye6H66754FqLLFM+hT0K4V2ZUfHCI7XtFaBi110eXcPWXXc1Wddt4rOIqVsBpMWU2hGRVexXdbK5xRZkqyK7hXqLq2CHTJEcPQ6S+iKj0iQ6RChmIpUmm6Plnn9sxswsiBno9WD4+U2pLA3Oc1VTzNoSkwHUKE9WcXLv9fcPF3l20zNrLp/4xGilLw4n1Yi934SYBIycnR0VpFAB9ildD1ZAGN2fFQh5j39Ae73ErcWpbLJRBG2wywt0sFsgTKvzA5StZCOgdk7u3R7shkqjnJXt10yLE6puxdSPYQ/GKQ6Xf8OGq8pOztjKDaFhfn+QjRrSmbn6m1HTWyefw2cxrt0zAVrwt3H2RayCLB9QZXwnG5SFMfemCOS6rUX5joXomr90PTEryHrCRNdDCa3yHliLyI+QxqvUyk/INTMalMkRYcJRcqqiZtBaaLq8FZ1Ag7UwQ36wWyyJmr1lAJ2LdH8e/O2BDVrNTEMCdmPNbLKSsD+LOJmSgUIf0QoiU1Riv+JSNCXzIz0FOR1+UQ9AShQeDe4kqynVNCRVrvIRDalflpQ62UpBXYmcCRNXbuH8wrxnIpzwBLeBcMFwVZukFQJp/T/yKhDhq3ek83anu9/fPdjptcmrgCav3pHdve5eb++wf0D+f/E4B5HrPc4KtLe+ShZ3rCvl/BNUkBIrnjbeiUAjlYDwb5OYhmlAY7evXTJlc+LBN1ORhOP7HFmXJynmAXmsMBrEYzjsTSg1DoSITdHovBKYjVbsUUUMeUFeWBfuRDJvE8/aqNz3J+SzSCAn7tmgSsUg8FlmyreZMGG5LVvckZCJCDu+V1qbSMiEBk3tsta5Gl7tMEKlFB4v4gQzknNGFUBWut6+QaVkkBqcSza/9yMUtyFAi5SAFTWRiMm303Pi8ERUdKFc6RsaAxzpw4PDiUXMroYLa/5Ylt/hbm+3dhoaKg9woAibNGAAXYvwPvvV+f3oLroasmCGpkoD9nvKRqysf4hq/iPCJqjJnttgfHskWYXL0a6ng88D53uVxJuDamsQ48KHh3TrfcpCIa8GPGayrmLw6AEuq9EQOTBqwT98fXp+swu37vT8Zv9NtzDXjHoPTLaKSFufBkfVxDiWCnIHWsBmy2bUOOAXH47I297uNrJMEshB9BR8R04QPAkvYQl5bVKvbXLQGfHcRYWP/wY/y1wjczV7K8i/0ihisUcl+79kyn5SCz32+QQVBSb8xuZaXfwhseTriWFAQoCIVE9FAtD9hMVdMkw9vK6AC6y+qPM4kkU0tl0CbRMdQqbzaMoqrG+v1+n1Onsn6n93Ots7hZUKadLlUY3zsVo7WpcxDaVJSiETXUii4B2DTz4PLrPcpKkXyU10aoZUYLco5je4ujn+9O2Ns5zFQ0eZ7kBQn4xoQENPHXsOpELEJBYpTsNuq8QnXvHW4HSph2quADD+MxaBzu7JogTui3ELjJ7rX68U0RYf7JWXoU6gffcSnBuxu+bAnQ+njlSN76+qYulKHVjJPMH0TPlkik7h+aRWRnruNpFJzKP/Ze/bm9s2lj3/308xxbO1lLMkRFIP26pK7dKSnKiO/IhIJ9lEp6ghMCRxBWJ4AVCPbO133/r1PDAgKJsRAcfnhveeiiUKnJnuafR093T/eiECu+TlWP3JUWJv87K4lg6E2+G0Vw2rpLHiyzagpBruB+vdd53YiqSRZE5hwEUi/DCFVaJbn1OkLwpvdbmoyp9Il5NJ+GBHpGf2cB15sr+vUizUE7iNfOGxoUotRQAY5tRDOLeXdYCoD+cLhPn5bb6vZAWziKN1wr1kER+LKFVBSFy00mUIISCD+uHlWWrP0YYvveVtw2uuCp/DjYJUWLbXKQ12EhJ66xh8JlBistLMa8oQQDSiAnlOEUoXC+VQ2PCLToUoiooWd4+xC9wmLXiShc51AiutgJSHbtOKofTfdeaa9V7wJ5CgZvZ5nN8nsKJctRwO6JYIaZmgscCN1FoxX/9OsOwp3jbu7+89wdPMmz/qEZRgqDeDp1nDqCeGW4QsD4HNeA6SraSB0grtNLnN1kiX456XLsfdwsuXo4kXl1cAWNZccMZotNT9TSyB4BBGeGUWIgllDjSkZzlhoGxTey+TixGR8RW0nphMcFV2BwDzhXZzNfV7Ynh59qKlgJmsv5TzXY/JtHJpmetGUgIQWSMrejwQ57nCoBTk6rzrKoyxSxi+8e+tGUkrPqUU853YTD3S5wW5QWKxvkOpS2TcKF1eUmwznZ0cDiYn61UAqirY5Vn/I1RWX1F8ZodyZaVoBGECT8x5GNVEHEJCjCYwrkrRGqEFQHuuCeT9G92zgMxmmh8DFGqyiVAlE7AfjUWSsfMwTjMRxmWOUL7EXyZ2NHv9ckfTbIaW8RwCn+7OofOEdBoRXezsm6z2NeJJj9cZOHV3Qk1WXkSNdUOmjwmIpeIh+J9URwZHrpBwCA5yrZaQkY+U9fAPuwblnzii8ikVyGYIJ+wGX/LCQN1K0y/g6I0xhPDvRN3iriY5xsEaqwpB13VCFQZfcKaqESW9W0TH6ts9aHfbR+1et93r9A57h6+7vZevXrZ7x697hz0EL9u9g6Pu66Pjl6+O291Op1MmoixrzyXjK+vBwQzep0Gzj+Q0jD/LKu6JJ3VgIvNON1WLfN/UUWImRjOZWwmKPuo1r8/2a/7euA3HPOYjHszDuNFCJhV5NPF0hAG/WFVh6IQvHPqFeqOB89FnsuZMBha65KymYBnPn/6G5PdEhfzyzHCn3fQ9T5kvo0ig17p9c4czkdqBkZNEWUOTEGWZceAoh0hOU112abvumLkR5tdZiPpNnUiZxTITJ2zN+vUteiqiSVs1ldNunH5OY51539ESzIcKHdL7juWJz5YR6gugXz1lZrQZNiZhikp4fUlX72DYMptK/GyKJlqMchGVUR89Gon4Ud4Lqn7NTOOYVGTphuudL+GsygwuInQfFJoel0xrWmAiZbbCJ5HlObE687ew8yrXz3xJj6gYqUojyH318mY3qcjKS8ZmACTHQvoVOaaHdflmC0xkYrMxV9emExYoMV9PadSD/rr331Za8biry/f6C8vDoKVNNGtOnlqdFpLPruwbznHr66sAVYqLV9wmYYZWKIvvO+kF57gersChsE9XF3kxn14/2wsXd4cnUHhg5u/h4u74X/TrC/zO8apQFqEdFogJbE+lw6Xr+iC97Hm9YxRZnxwdHmwMRS3iuzCR8TzP0KucpxYpL9WVZ3ZGXfHs6lpciSzjuAhRpKMqBAdmHkyWMakgiwDmDpyyPQ1FSMWBacanYTxtsZ/6eUQlAJ1ygcdbVPX4olVaH3x3bbA86jRjHLXcANeYVdlB9eockDISc0MeLmUCt3YzdeLefF6YvLzFOUkb7+5iJuYi4VGNDfzOzRwl0855Y/bCCRIlmXgI0yx9sfq6hAGLYasihqmSelPTZC4RhFKYtggT6kYPSEZwIEUK7V/m1Ct+ODnqdCYFZtRi1a7pX2hUnhJjwwJd6lOQdAB9JmF+ZjGoyzkKm2MZCJ19USA51ytWZMhwQOQUX1nDWP2VUvNBdzEagWvOb4GwkSHzIA1RHO06MXZkslcgyHORJajGxhJknNdnm2GLkBEwnBB9C9FRPKH12iHFHFhlgWsw2r+9l5lOrA4VtkUs1JGdCpF/Qb9JhWVQLNu8pnpJdlgnhVtVTCOll6yCG3yPfFXlaNGvEDi8+4KvCaIGBy/FkRhPRIeLY//w9cteMBavJ53uy0PePT54OR6/6h2+nBwX5LE6B+XpmISmWme+f/bUKtYDmi+Gaf5mwixXwCBaXpCIfK+23/a5d4RZjwG1z1GTT0gaNh4OrqZFLxkTa9AQR8/SfacdVCcjrzQFu9CmC08pHHOOUG/oa+SNwltkHGY3co4H/GiJawI7Yh4UfiN4lhZfRfzxBtbj+NEoYeq8ubAohvZRaNYbO6pGkZngxcAghb6lZbkSLh1t/boVhQipMmVJqk69G2niViTw4hYkpygJ2b2kVRUOSPtloxX1NpIGgyTkngcr4HoiXSqAuGl4k5azCYZ0qxbzdLKx6bZqB9XHiV2Zgbgxo20mSysq2XJ/nUStLADP0qa59XdFQdUy6OFyDfkfxg4tvMlSpHGzaaw1pqC8td1Ot3hEnJ2ttXKrJxOzSA0AYi4oHN8JfiC90WE8XYbpzJ5l+UtJrzTOC7ZcFI56fc7JFEt1yoKYwXPUfInJCqa8BasS8uHlpEB0UWrsiFZ6XrA2/uDwWBM15zGVO6G6sfx6mfnaHf1/3aKG1p7czueo1ufQbHVs3bpYu3M9/jLXw2zy39wDIQ9kc3btfJSdj/It+CibS+zOi9l5Mc/2Yv6EmBlLuyhrOz9n5+fs/Jzn+jmbv36pA65bpZrXiM1oGpOtam3vT66xJnx0ykY0+El/+jSiLzqSh6NC2eVr7oAL9oq1FAqXkIrZziQar/IEbjcNIhM7Bko7iqtbfcufUPD3xoK7Kejum7JoFf7+JzdM32vXsWcaAnx1yywIyJe9aa3pM8kiKW+R2MCVb47LVLo2Xbmx19QUzpAyvw68nne4KZ/+urfOrEDfFu8iINVGQDRbHZe7LtbuIiB/WQTEbPIuArJRBESzaxcB2UVA/k0iIFpidxGQXQSkzgiIETPjgxdlbRcB2UVAdhGQGiMg+vX7piMgeo27CMi/SwREb9guAvKFCMhOor8JiTbb8XeWV8MDJ0Jkyo3yTz5TbaSe0kUNtq1RCeoZaChUZzJx0LoN1PG+Alzx1lUYQTAc7GWEj50B4Hy1NBqzC+COz42ZkINmmFUVFuFCYxvWF4ly8KW/gCzt4tloeGk9It7MJwCUnVl8GadhQKgO4BlciyiMhdsMWKEE61HHBnqZaorjIt3pUxMaNhSZqYcsIIXrqIEalvL+7dgm5qAhL7R8a2htU9VkHRYqoyNLLl7/nOG1pVLBxll2e81vvcbGxZHWfN/hSO9wpHc40n8pjrR6E7UgOErwGwSTVkvdgUnvwKR3YNI7MOkdmPQOTHoHJr0Dk96BSe/ApBWYtLIPvxEwaVrMDkz6mwGT1tLxBRBlHunIix6U7skMvvJaIGWntxtANym2GE+/eWDpJ9nhbcmPbxBYenMXt1dcTGkvNnG/N0SX1vrBna8Et1ucfK0gVIouTWvS7jES+Hbo0jt06R269A5deocuvUOX3qFL79Cld+jSO3TpHbr0Dl16hy69Q5f+L44unc0SwTM322uYf/J0tlfjLYajZDQ/4mmKSh5dAINYO49Egh99XyaBMaz0XCzjD8jCeLzWK7y2RhEIfncxvDpn/eHwf5z+8/qhf84mCZ8LOJredVxKCIMxCQYWVpIPrNeh8puslxMmOgRgYmIXZ4MWe//D2190rZ7Jawfk3XwuY7tkLx96CBuXCPIy7meh733nLmwueIxSAJsIl+lYhDaKTat9Jif5mJkdUy/suhHOF9zPrhsvvMKMwp+Rxvz8pPnIKgXnFtjcyHmDjYu4q8GHHj+aa6pM5V2qVbRAAPdRIBYBJi2bOUNOJY/sMkUc0A0jC0QccmK0TjjE0htbp2zlCQWbvI8KGXztW6c8ATlx76PCOAh9nskkZXL8H8LPUp3AYELHJNlon2LGZKtA0lQ5ZIYMZbzvZEB8/kVcS6Nnl7QBtWrN66j9QH8x5xuoWU/1Gmq3W7X3d0rm2oZJ33r213YJXs/mjLHE/Ar5USClkdeNX4+g1kZ3Ig5k0o7FMktwO2JXcD1KOM7V69EypX8cLQjV+l7GYv9S3u+/E0G4nO//GE5n16PU51Ge6hnGrL+gjMgH1jdH+2B48SvreV33hHPG/VktyGZz5ytiNLhthqDyr5A+vkwzOdf607uOzx8WpM3dUe/0/XoiTq5jxr6jzIKBqewzH8VC/XQp79UPijb1MwhsrO68emC7XXd2qKZtPzNpGCxzCiRwfEsy/1EBb45De9iuknrxkT149P+UtK8aJuAeQEThnUiMGu3H00gk7Pyf3nZcodiIyc6tiS8OigLXZOfng74eLYRQ2F6Y5N0u9JAMkq3CR6GMX5QYt5iF6ex/r8b/n82YSRgJj1O7XxFswJggL3ArEN+8RBCPjG7sOoaljTVDry0Cx+0H4uZ4Wh0/KbsVYoG7Uf8WjFBfp9wCr1kFpZkuXK5LCJrWJcF8LJ/PYUDffqivCvKsmvEjsxEM9qNIRLOZoqhAxm3xMOPLFJJizS+lh+y4xFOYsELrLJWXbpJ+ThhKdcI70YJFjvzIVAStPGbUYiL2k8dFJoI86C8ehL/MRIvNwiAQcYslggfqvzjmWtouaLH7JMxMeMgR1+bvDfMs2veopxv/qmIvkbMzSsNpzGGme0GI1tojHk1lEmazeV0bDPsIvbugouxktsQL6yHNtUgkRPfpgLxbUGupoBJf+EwkPSijVdkU9goHb0K+41rHUuErxhAJgW/omL6tmmaKN/lyy/uUznjv6Lj6XVE4IsW9KAVBxlJGgsfr2P1G/cl1AlGck3MM8U2d0FLSlU24QzWIGiYP42mNWC3DWSFMtal0Oa2K9LeVgDjNRABKspygd1Gik29YKifZPfSGMt8MG53eQGByIiJxp13j/gLXid99GDC82GVp8uXcw5zCe1j4HnowP9awCxnPlmldO9APghCbsRrFtCV4vkh0xZYgZLrlE7uBwCkiLJGcor8MIxUrpwlfzEKfiSSB82izOd1R73gUBm52LeLeCbpZ6fnYpUC1/zJ26ugmJk+Lvpp/RU5Wx7fD4pxexv5M+LciKG/m+dXVh6vRp/fDq0+D4fnZ6OrDh2ENu7kkP7uuvMqBGt7NPKbsVlIkIlml+V3oJxLvBTuVyUImboZ4hURngs9r1iKYokpVQuPJROsKXRZrFIjGv/JyzWEH/ZMa5PynH3/97dW7V/2fa+A6ztCMzxdb2LtneEERpcWBzO5tJaYRKZqJLOCpiEVCwSI8jr/BGl4ht9nr9LrtDv437PZOup2Tg85vzRpIh/oQwQaEf+ZMbg5QDKndP0cfrdExAAkr5Lv8DJ1GoQDn6099zzjnMFd1rSQFECnOaocsZKKYXnW5RoSNJGWkMUq47izISN3RtFqZNr+W9UC6ecsdWG8WgRrK2UVbQjMfjiF41AhNMD5FubETtkJddBjD9UCAs4jWtfZ04YVt+sLRUR0LEUQQm3DtSQf1LSxqGgdWEF7bjX3SFPJOslL8fiWUYSXb0LXG8c69Tg0KwQkdQcEDrHfFDZifjgvTMFoiVKbicgHOsps51nujMcGQ0hOKlN349Kkdl75BUbX/IH1jLuXIUcGjLZaGsW+Hg4zyOPeWjSbBMirhciAcKI2qz9kzGlzj2qVuGrFLxuq7kQa8CsKM914XbWZ8B3vK+Kb6LsFjF7auXed50mNB4ka1VHVmS/u3eWVSiS/7MzkX+zzK92sr/mARIzX5tixax5/mGSbQ1H2OR/S+WGeKjiljD+lxCe7ulzAO5H3RGjPFxJrHyJow9RrqOEQczeW8L6N1JWxb3idg2z0RTTwKI+GOc5mIbXn6hNi947hKE8ydyiiF88u369+oh1fH7ePDiohEvcxIJoFIaiIRBfYsRctcKEI5eZquyzDLIsHO4yDkcUXk+YvlqIybVBlxpx8/2ZuBz+7ZRZyJqCqa9OE8cs7K5xyp5w+IP+PYImVlEVRtkQgoaab2YEUrNmIwQrkzYbaIEDpgII2XYZThJIYdG0ZaY/oGf3gs2ITfqmDMHHAjibYzZJJ6FbFGPADPepNgySQCyGssgnWcudSBZzWcCJiIBObUcWzoIoxS2bJnggci8fg4HK1FlapMXleApSCufSdm9kaZyBDWZMLRqrb/5uJFxVRSDlBN9P1IU6jknqfeyYrIcFreV03FGazYjAPN2KFBz1stFQJVzG69/4bJF18u7NJbkU9Q836ozJaa359mo/PQbVBeqYU3MfTkrnVVFKUjPg5rouTLr73Zr8swXj6wD4OK96vGU1mL3ucO5oqI+OraWs9bERXhvNLjMpx/neMy1bXFG6z76ey+Zj9mnC6x3ZS3WGf10StOOV56srIg6UGV53IrHnXkLfde9HWdRk1LhU0YdIHdUDG0HLeJFWYjmCoIjAUabty4FHvf5RCVVXHR82dhbwNWrtH+hbS9AndPZ2E7/c8lKF0kcszHYYRrGouo7nBUr6Nq4fBw8sjFY9WUDWZI7o+ZHp75PEIPdwiVU15dE02TiE/TmrQNJHughZ0MYJqschLKF1/1UeDmHVZGgAHhGcnJJBXZ16JFzVYfNWn4h3jeu/Iktl6JBjMZw2SVk1KjRVGiBHNVTsBdmGRLHo02z9/7UxZ5iQg9X7HUsQZ6ni9azyCmWtGabl9GsLmhQZP91YYGLaJiQ0OPaQRjA3Y+9yXVTNVTmnM49aompWZds0JGpdpmxoEuEYXjhCehSGuiwvgEajpmpyvWEOW0VkVeJqJJjcWnZniWPs7HMlL1p/AV8te1AjqgYOqMYtAdsZ2lxcQDLlVMskIkONXhBTJbe4dqs0BAL1FThN+wA6NkTidwZzzxpn+8aFEaTBkVgVpxWX2mr/oDtteY/tFAS66MNdQIjTV9BxfxtAKmT2RyW3FVf5Hpfaj6W1wo8jznDlFEt9BKp7Tmt/L29dBjMvYBB168fGg5PRrt0Dbtia7yVycqjW4H1bOwvr5ZJ7x42laesUiYPaJpTF06rV3Dt6gLunw+OzC+oapPKEW2SMf74dsBIJJwxY6bcR7JKWq0UEkYsz5anMW4jaeA6CBLkJO11z8bvDDpMcJsuR2VFpWqR7HQPMUYV/Tw1iIRsP9+1h/2PfabjIVn6xkS3eBuDhktNgYaP5obSzAX1ya3JpEkZYG8j4HrpYGNi6VgrB+z/tkAQ1tMTjusPuph7Zywm9OT6wXPZteZvMaaIYu5KjhJ5VyMrJDeKA7crHxqR9Y4LOpC1TUXTJILu8m/5bGb8oSmvZQd0vkmlJS7itKX3GkKGo0yKswrxhQQEYhu6Qfx8w0wdaKocNWLxbrIDM7r726iSCrQBNPaMhU/JuEcFyxUK80uztjeDxdnNtDqHh+Wuma30+k2q6DK1tLWTZebe7qWpqqSHnD4evPgqCaq3p0dQQPOvKqWms54t6a1Dn7sdytfbF4pUcNye0fHlS/4qNurb8FH3V7FC04DIep6JQeDs/Pzj5UtOHR6n1a91Iu4BGVvVId2aR2LpaRMmr2j44NXB1WoyHk4F3Vmi7y7eHdOMmhOyULGuca/dRQnk4kxZeSk0MKCMaovZrMsW6Qn+/v39/deyGMOhLJ9niL7mrZxfy6CkLcxZ+Fn72GWzaPfAWhsR5STSegDj4Ge/ldLZ3mZtBCP/QK7fw67MpvxmOxBPArTRhfejDWgmx1zDohDW5Xskm4wcarYtvpE850MCkcZ5FH6wCm34roeNbXZOT7sVCKTW+bNrkmbtfmucNpkgHqJSvbhK0FO6X1wPVZrXsJyzGamdMkmjZa2R//gVeM6yvtYJDVRTq46TdCkMqvEvdOo3KKC+V0dIdWjSrw1/oKbwN1akQYblliTsZtPaTJ3n5Wxu1+tAC3E10g2RbKiO41uvGlx5tba6g+VZJouUB0yX/C4rvztC4vMraYpOSEt04iXkvR1hmIbytX7WkVxC/EVUCssI5xPn8mHj9xtA7MV3Zi55tQsS7ie5plEH3sH3uvjTsfrvjzsHlVDfThf1ImH2Ccr39Crc3pgqXD28ZyEn6JQehWs3YZdqR5jzroY/qIhdE0YcRLGU5EsEqB1jZEcigg+MJ8Yn6AQIBGKmbqdo+mkiUqwNr3iduws4XFq64tT1b9b+v4ySYDOoDDH7hFw0jU62tJMuAkp0lo1Jo0TRrwUPNFmKc8KBjGw9cUjaZ79cSSn+wqspA03A3pwv9fpHu53uvsUzwvjaVunJbcVc9qYMIynHmzlctyp4x+/6hz4h+J1r9fFD4HPj14fH3AeHBwHwaQa2TFphiN8UGd82r4/22jOwcf+xfuhd/7reTXU60LbuknW02xzaDTsqUFAhDpaTD9/WKAwFrbqgFzYRgW8+fNX6gXKm2RWYhDoCfIsC5F5p2pH2UUqOgvpuoG/18CvawCpu8cHrw4rIE9ZJqNv3Rwd0jIZlklWVPo4j8L4tpLr5hrjELT5GJ/tYSrCxmmxfP0vStKNxyqgaVlbZH2oIVspqP6JguoJbv+XCdIOdZwFuAB7g5WIu3Ku6ou7Pxx1XntcX2WhSA9cqv3WXcNAOPPqZNy9Qf/9C0851JgntYBMTr2zHpoBN3EGKJ1HuudxK6LxXQLhsRdmOrvFaUIl0hY7ez9gLsWM7ekGa4HPkyDVV3kFwDCRlrfjO093Yvd8Wdm2hGm6FAkMhPnaVmeV74uGiAVD2N7pexJELAKHg8tdy/cSI3Q7fASke4Sux/ppukzQT4gNqHm26UdZJX+o713tvKFZ2N7pC8KXSFdJ/zSomC4HmkoEdW7/mTsR8Z7tnT1n90+//zRosQ/fGym4iP0W+/Dpe8R/9UOQ5hY7ff/9ZyRFD8tqkxiUTEdhVrfImGmMbrt8scqwd3KptNLPobivmEgXj7VmQt2pUrb3YQvFcRH7NfKBR6NlHGZfkR08Aj5vBq58egZbVt6cilkDzBUxksmIXKj6IGoNY2g+WHJmPnvqD1tsQDbex9I7coo+UTKJwyqAGkgwYpmNKECwAblPXVwMcWfBMx0ZWMXNCVO0XEL6M4Ub4jQMqL8agc6UNrnX6XXanZft7jHrHJx0j04OXv/PTuek06mS4LGYyGSTDX42xZMwSbNNqO2+bndeEbXdk8POSe+oWmpVT6TRrXisHYayb8a3KF0GZ8pt4HQryi/21aBfA73+MrkTNdEKH4bGd3JkBRNRBAHx9Z9yih2YS0Ld0EMy+Of2T/bytcSfOEyzxVGvWwOTUDaPXu4b8OmpAMW5HsJueyDosmZl0zU8z0YEHx8dHbzUH4ZxIB5cKhkLpD9SwcWVzytkzJZRGwgIhjDuqyML6QLl+ojlhFnZe+p1Dl9VRU4qkpBHo0KBXNVvgkbzV1MZLH46Uu1rsf50pz6DdBykmYj9xxzg1zRXpy1Wr8hixuMltWtusdBeFI5NJoZJx8VtMiHNIEM1yDFp7ND+jBOORlJm/NHR2zdvXp++PDt/87bz+lXn9Vm3d3rar0wzWfSz2hWxkyWLSJfL/hyCzS7CY7+g7SncXREHKllFj8q0STKRyzhA9PEHyS55PGWnBBiqKwUePTYQwobzp2E2W47h2uxPZcTj6f5UIqY/3p/Krtc93E8Tf18hju6DMfQfbyr/cXlw8LJ9eXB0UNoaONBHx+0KjwkddPlrwgmpjSeYZawSrCoxvGkkxzyyNm8ssurp/yvCBavkfhpUTde3EC5YVX16bahGXufdqHjBYPh9buO32OX3Ax6zt4gZhakvnXhCi13EvkfRg68mLd9MqKDAlKqpdD3WmildGysw61glurDxdRL9DQQGVnhQGZl/Uydf51nUawk6ECiYVJtmJTE+2JaoJM1GqRDxBmQ86b4jo6wMlxzGGVy4qWrGo6u1yJFPhIZLScPpLHMtJFURZZe3Sm2T4hndbrtzNOy+POkdnRy+9DqdrZGUp0J6fpg91gVOfmr0b2n/3skYec5bIuTR+iUyyEWcjRyjoEoihveyrWEw/VLeup29ma4n9H2/ShJr2ic329ZOViZFJtmM9cmB2lKpKKrInhuFqaxr6061yXgx+EA3TCWSTvvV0VHXO6RpWCtdpzzm28LtggbYZGVLpbT+qZAjFzuusNBLGU/DDPV70IcAyMmWa1je/L+sEcm4ccLaLw+84+7hq4NOizUinjVO2OGRd9Q5et19xf5fBaqtvCOVHVNNNA5vG6gF5094jTgz/GwZgGiSavxtmvB4GfEkN4AJrPyR+cBuoC4cTmLOqYlQYAwnWSlMcPIAshwZTSnVYLBJJGWigyctG/8ITFczO6haXpSDsyhfosV8ay47S0ALOFvIqqJzyDRcZhJd8AM2FdJQW04PGss0k3E72PIGCiK6kGnGo7o0RfMjDU9aolSeDeZaGnPO/Ky75eUZ5Dqz2gIiowraQKHexvI+pm5pDKTQRDJhv118dL1ZxnSyRKTSNO7DAH0oCWdKKxqkaeofywxH/+Ytw/5gdiKmsPRqVM1XNMPnNHP7p9PKCKlJN2si1qrmn5ZiLCqQexiWf8i4juUPTTtpjG/OfyPoLdt4GUVjznNrqdVWwX4/mUKbxHz/zVLEMh31w0Sk23Eh3KRkMlyso3CT1oUXH9f3LWTSZPSyz/Ut7Ho978A73I7EiH9VdwTTfXveyJwnt8i+zqJNNvw5At8YJhxFj+wSDhj7mMhM+jJiiH3BmtcrSD12ZULZIih2RXXbpbLv2C8/XgzPVefTH67Oz9+rH/vv3pxfqR+vzs8aqwz8BYU/W/JKl/GNeLYBr6oSGVM86HYO0+Oy/OzLV7lKeF2SA7d6AzY8dc32ZS3BMIO5l3qOljg83DJiofPS6wq7DIv2ZU5+MzUp8eXtjJI/RsskQvvO7YhLBPVzrC2QdmXGZ5+uLhkynvG2Z9LBr1nbDO+zwmyuqnSZuv4cl0H79kv7nU6n2zs43JY90zCFuQeb33PLwqtmVPODAbmmWaj5aSZiKrJlY56K40M0U5UovXR8BFzCGrgcs1gmVRWEjNPclBiIjKzm8wdSOVdi+tNSJI/6s1ax/5Uv6T2TcWDb92gEIoTY6GL4JlqgKQK3fXtSJhd6Q5GrrEU3H3OsAjm4qbwTiUGpxVry22ADZROQSrs6/2H05uJ9/+r/KMrtgVA2vn/76c2yf9rp//zTm2G/3+/T7/ih3/++SglQIIkrMlCyCE3G9dp9PjUl4YhYYpfxQqhxDXSz/h5jHy0/oAoNtuG6b2IrzFbZJdMVPe6DbUUBM8+bZ3Ri+h54PfitxfDv+a8f++/PRoPfXmjAqHyD8jWEtlyEETyBGldPqVuQpNBpekKSY4z+7tPl8ILmorHNcFHExvkq73gSEih/JOJpNlPD6gwC8r9zwcaYZ798uDpTcn3+w+gn/FZYuh23IGPWwwyEH85LQANsT3hTdtPoNm7WIKE1f2+cnlwnGb8GxF+WLa7HYXw9f+SLhScexNb9iO0mgto1GIiVuCGDjMcBT4KiONDLZ3SNxd5aZQD4PvitIiJn4V0d9PXH40TcIT6g73ZN/TXmKx0rP/7z8l1F9NyKxxrI+TG8E+1EIMCH9DSUQskJNr6cDTP48Hb4S//q/DqvlTPHxPvh9SlKQeNM3/hcX8z5VKhSpXNqzg3p/0Aykl7fhzHkCkJdEXPKpWaVcMcCFrggBdjoFoYj7UAZSauswrZfb80vPSpbx7frMzFeTqciqYiBLhl1XUbQHMYKKYlXNQSlPo9jkYxwy7qJXfWUF0GXDVh4/+f987Mr3Ug21WB8S2r4D/i7RxYI4D6IgM15FPohABCdejuGpO1PV5clcg+3pFO7+dvQ+F55QAgVodfsiolMbWHlOEWNSQCVHSx9je6EOmzVo3Rd1kpvy5BljaWTjaFuKpJTydP8jFbdrU4fwZUPRDkZDGHMBsOLX1nP63huxKAcVjhRYQK+zGQs53KZtpU/oT9G4g/3M/WbhbcphSECOedh3IaYq0epvK6N8jr1O+RL/RQu7g6dP4SLu2P968qYc+47z82XmXhQI8AX1j+pFsvqF9NtXf22TKLrmLlDfofLg6TNfQpeq6fulXZrG6XSvhWP6i+4/G87iU+lIIqlYzvRWSaRp7hXkwA1z2h0o9GWSZRbjQ0qRrFljg3H6L6IGaA+GRJPcWcAxxXXCeQqw8bWkcs42EdiCMITSjyixxwTibMCacyA5ABjDdCn1GgRq3JioAqSYSqNHX+jhrhRVxruChVBWJiuBMU6ozATCY/Yxce7YzumiP1I6hT3m99v6BS8+dcN27s4H75lV29NoJ+x3suDHhn8ovBgnnFr/DRzrWKhg8O4sFw7olp2yXIucn57GbKIVHWJke08nnPbVjTbyXM8WgMjwRK4QU5nZBLATfCfh+XRTYPPVGS4XwwzhfGbtiDMAG0WdyJ5ZMuEcLIZX/n+yuBm2oVIQhmwOeB6McjYoH2hoSBcLr1pLcckoIfHgjUW8bSRJ33j6w2AfDX+HkDWkLxJwgn7vi7B+6gAnx0FprFg8NvNP24cdZbJhct4SNDNPwg/Ahuz4EmOdqgX7TUrYMAyijYgfiUeUj04xMVEwRt/urqk2wINuQNIjUyyR7lMcALmWvfRERzqxG2WxpAlf2NIuwFYjshmonC1HMIIQeFDlizJlpTJSlsIgu3JyVCZaE9GMYv68OTw8GBfgeb8r//8Xn+ufv9HJhfb75lRTxXt2/9n72ub2zaSdb/fXzHFvXXobIkQqbc4qjqVS0tyolrZVkQ62ZNoixqSQxJlEMMAoCTm1v3vt56eFwwIUKZAQrFl1Tm7K5PgTHdPo6enp/vpjdat/jG0FxvWbJKaxywWIszI08qxwLz4IQtFcgfk6qkM/UQi5qSslvWKzT6OJuJWXTT0JjeuOSkApxMDC+RY53fgp7DAI0RkCdzcdUMRm6ODZ+YFdPVlKrQq2p/ZYXlseroaQndQazALBHqWR7BjXn0rqoPRVny9uVbNeBw7Bm7bpvBSD2+MmN5avW0QnkzWIPqhkO6lDsQ4hDmWWYu8tg1aH33Z9vnmPthjVhJ/cLC//fs0qMufuHtYg5VS2yY2P5pAv4A2D4teOvWNjkoXMa7HZLR6Sy9Kbo/9kfZY5dS5TQncWTw47DzrroeS3fx4Q9bFBm6YLrV2aPe0rx9RUTLHb+h2xTy140xGP9BuoR0R5xHcHIjpLEnpIdLVkzf610todEN/RBd3CYKnrC+SuzRFQvlgyZ2kDXXjXqzQB3UKRVl5r9ojITif+OOJIBtuJiW/WU28Q0KazcTQNk2c99VXztrnfGNnLPUwhZprIyltQc9ATmtYxZr7QUafbM2kXgxEq6Ip5TnOIjHwYySk6cYiAUpQAv+TcMtT4/lo5N/bEemZV9gsjnd31SPqCU9G4+881o0W5jYWmXT3PvIK1QEVLaz86SxYsIR/ymaoaPcb6x/wvghi+AkBNipGG/CdCALivntxGqd2cCC9+acCHDNHGpvrUTyYiKmoSnk6NPpKa1mDpGN3SXH+UTf8N8eFzriiNy8UGmkL4jCqW5VEuu77YRrUQEDKw1qwP+cohvHT1wIkm0Nm6nTxIDAiwQMx2iSJWUIymkjdXlK1al163bS98Bg7BwonTjp+pvHLMgVUtEMpukIbT/pe92e22cL4CiyQ6WIIVEuz97Lsu7njSMDa9xxDfRHIuyXaNUXFdoUlq2SrYlc8TrzpQo+gXi5oWA0NhGrecqxIj5I5cxOvsS7XstbPKHM87++h7qqVMWDpgT9LHtl8c6rSUnDGqKnIGHa3JOJ+kAYfCgwCj5PNVT+Rsx4x+AQbihiNdEoS3Gyawgj8lehenAJ4DUEzm4icrogekzFlmHdMNyEysa6l0OOB7YLQzPK8dthR+iTWD8PXvu59h/acVVtOuhLrbT70+ebKZsDiK1Kyj3r4pZ3H24zwF7TELw0t8QUocSVQ4jPESHyBR6wAHtEV6nNERnT5cyvwK+axEOjgbwJFfMFDLMJD/GahEL8lFMRvBgBxGc3tGWMfvsAePgx7+IJ4mEc8fD5ghy84h18GzuELxOGXAXH4DaMbPkNgwxdMwy1jGmYE+hzhDDMMumfNipksPOAbOpb5zSx3Rfw+GxDDbxe/8HlAF1Iaoxh6PJFTf1ARAw4OAjxRNZdT3qNcTEp11PRoqKuAifDWj2To5CEzJsIhgYVhfU1eJOVQ5kTS58OGvUOebiIfevhpxENTme0sJ6siKelBmSutR0gJyfgeWlR68YTvHR5tIif/iYTU820GUyqboRzMNe+gzJQhrOB69MM+F+Kw8fqItxoH/KjV6DfFUaM5GA72W/vDZqt/uJEk0BngqYSBuTaVhx+IvuBJ47XX9JqNveZey2seenv7jSbAJlqbyKLCOrolUSS6qo70URVnDLgKhBjOERLpC02nGGaTCSHAsY++tFaMy4KyX/Rokl40D8S6whlFfCrwKlYkDbfGVRdH2iltPGg0jyjrHz4yzqB/AflgyAYBj2N/tMjmQaK2ckDfJ2IwUWEGPYEFelIzeeytnUqP5WfQH5nJwNDoGVS2hSxAVcyp6hPjHSawH1BVO+W+j5HNCJgwCrVHIomkAclxC2f4eAxSpFrkfGTh3Xn36oy1u93/OvlXZk3GkZzPPB74PK5oVWpd7HqY4JWI7UmF5qVUQk6FT3KEVqnEtx8m0RyOhS1tdQseSatRB0LthlWEh7uHXFNYYNtW4jv0tlp41+FvE6pRkok7JHIufOqfvJBzWqZ5jEICV2jUglYRbXnxcuWT9T9Y7R0fiwGye36ip49qbH28CJqgum2ElsIflloDMieu1DZfg1Tw7rhrrYE/zAv/p2Zz//vHSbrCk3ptGfzpK9Z4TJKX99vz92t7S8R85YhUJHM7CWXlP0L4roAyWl9e+OmQBUq/nuwz7Hi1VeVCPEn44JM39ZMI3c7Hu/TreJdeit11l8nu7h6PvbWPdavuHnRcXgfkeYCgDiSua6DT6ny9+6nihfRjPSbTX1ukuHbn/Xc4z/85F4HTlThmgg8m1t2X6qVQDdlF3uVrHbaOfiglGDeysGUTsv06vA8OsaSwOUH8JOU4EOzi4uTx0hjIcOQPq3ylUxf3updMxHVPIYA0QjFPUBKfUnDdAzJaOL7u0S2CelyPiXf6vQzF7oW8230nhv58uotmrte9eMADwQzmtB+y9mwmwqF/z9rGcmvQiVbqwGbgJ35VBJn7Q4ciRoPHtjRxCIQKHrPBPE7kVJUwxd51eHYPl04M3VEz+JjAbgBkdEfNIYbmo1ADQ1zIO/WH4k39DQZzBkM98PiVdlaloqU+zdRymbMMLLEMDe5JeqOn3e5l9s4v2b1H/5fFTRmKwL8VkcJNEawN4LSInf3Le7wkKG3U02mjFcnCOdhxc5ZZhtjNpK9m8TP1kAwCfAA/czbx48n/KZGnlAqDwieqqFYM1xDGqqSV+gXXBx0dBgoELaAZurA0GNspqjFAhD5AsU9CzNRmDeZ1uS/G9epluUuSyO/PK8SHbBNQnhwpttP5HKbb9kMd5k3RzPsLNgt4gsCox34WkajXUbUcyrAh7gEjCo1QiftypO2KHdf6N0LbIOVMmgrnY4bqdALaQuUycEfguVjMnB3AN+I6VgzTy3NBQGNih0384VCEOywSfKj+G0nbO3o/3yEIqoIKqfofNfNsbYfV1NO1/5RdP2T49uzVszf0caau/Bq8Dl8GcUWYHDuZDUOAHtJ0jfazumjDuVtML9BjhbqhsUAAhuiPUdNhC4Cg8akDpW1mDH8VY4iI0uh13YctCGVKNs7tfG5tHhckfXglMvWsq8DO+lIGgodFIn6jvkLYQ0OEAD+FO2kGfmzgG3K2r55Ec7EllcKEfjjuVXZwr6uDu94TorW1yI/to/rXShH4bBbo8BSb8nA+4gMwok83BnNKg615S+A1GvggEoG41dgH7RnSf//5oUN4Lvk3eiCnHuYU3v1s4KEedbElySc8mcdVSb39MJDx0n07kVK8AqO5qmEK5HgMe6ASUMYRn038ARNRJKM4DcG6o1JqZnoRTjB2OK8mZj52IfitYPMwBT/VFZDmp+lP5Gh5fDss9tp5OJgIHHLzC3h2dfXhqvfxfffqY6d7dtq7+vChu6UVVBfRBUkTOVNQZg07OgvCjcRAh7QZXObTQkSyExnNZORGcDdkNBF8WrGFwBTbNBM0Hq4oyQ5AfI5xmEUSSIReahXsoI+0Dme//Pzv31+/e93+dUuSxt6X8OlsDVmv8kdP8wD+GdWhmchDNdnEQ7onABUin0td32vutRpN/H+3tXfcah7vPwKf/zPswhyI4RrMPrCX1jvAStJHL8e+FNgMZDBmKpt/haHhGpPS/HzV78xhGO4k7UHwJyG5DEp5puYYe0oWQhz+jJSBhgHl+jqEkfmiabVxrFe565N93VDqxS4MOBj6Yx+9k+x8KFPACRbHf8bHKEV3wkETwfp+iOOABnV21qdwh+CZpfmM+d9MbDioi3UktfJwSPjFNA72X7ySa58HCQOMdCL7+9LcYPZNeCk46KYnPuzVU5FwIIPjfQnHK46+6jsL4U/D6JVXBdTzGaTJbqag1yL5450TMbsZ0Kd2XI3RzvgQCFjQryQ9MODRHRZTipEeDrrIw5Ru87aDjNKSHYpbv7I44ikNrhHFYDdsDxaX9GW9j4e8LDPmhFwVP2Z87E1awc1ZUMfZPXZu09Q1Kgc9NozcCJFqwrmjz5Np17WcLHYncip2eZCu0aNlgol7asJNxVIkk/opJtAcPSSXLIIbbS/GX9HjovEB0yjhzvvXtVCZWq59kWb4q20MMSlX2lQL4NWXpVki1o7l9UQw8igkg8SBeSQ2leMK9XrHkUEkmDuVecnPLt4Wvy33r48aRwcbMIb2KD0ZVdeQ580iEbaFBfhZycuFnySBYGfh0OfhBiwNZvNehQlAJ5cfbaT8wbXBNViwCR968+w5+1qZ7e/sHrFZ+CBkcGYyjn1ge1t0NVBfj+0mCFxlEqoOeekxmUFW7M/9IMGuCX/SD7TVG/DQYhmPuL62nfKADi7kB8i0/KaMOMQ9sn/WCTyMAtQGhWJYJA2Ty6uGE0MmAkE5a9AnbLFD0zemPKkTwYci8njf7+mE3TWo3jBT16hi24kzvVHuKRQxGqHy7FX7zfl3W+CM0q0q4ulnmkJlh616xzYgHRpeEeWn8CATws136Nbzbk65QHGN27V4zQSBz6NWapGnE1Qgd0lBoYrfh3qted+qUdzPAuAaHh6BrPgAF3GP9/2KqP/8q2vW5cIP5/fsQ2cL61LhTqnV6qHNcgPCn9yy6nk3oNyfbnUL86fVbWExbrVluA6toYiTYkLrbdPgTINGIV7FQ6ZsAb2mlD+kJ8sriR5UXYOgM5NuQmNPAgb/mW5QRSz00Aq23GSYAAVt3m8Q+4YhpuAPQwG04RuXY++fNxtZCjvOYOLvrSG+AkudSQPLSPRk4jfiP+fgbhbJvun7hwtLCgs4UtR0bEMJPOwMcrbYNjedCYAgQ6aHZwMeDOaBMoDWO90mH6OAj+M1uChjMaC1Ha3I5GTSZFshO39RUx3Vbr7aRkSbrvw9ORrFInkq+tVs2+Xg8YAQRvfdHqcP020mI+iIrZBf4c6eox5zbYXoWz9K5jzorZ/r9ShPN0e4ns/kdG2Vh/JqU4KBzdWG+pg81YZPk/3dGz4RsYUNX49jFGANEZZ96bQg9ZTMqtw2yK/YXiyRvrHFIEiEnkIk8UVcEeXG11YIDBoABXWQKeSTHzv8bcJSIoIR0tcqYsUMz+LFtC+ps9WEXsn09StJu+k2VRHh9beZ7lvrdf16Fs2ylgQ9ktGnKqFO6m3owyfc0DgN5RExYzyO5cBPGz1z58bXqr4ek7EP2JzC+f0O4ywS+tbPDG1TZOiaeHmi3Oh2UD0La+sb3OCOL2JaSp6wQJh1oWlMZ2LdZ50af6nLonQ+OzB+oSoGKD0yy8f77tuO7n4NsYQ8kGM0OgXcdsjaGnhZqOBfJ4mQv/OqfdrRgMdEhLLKdlQiKlaPgtA0pRRXwTgFBWLI/vdpu9v22O8yFJ7NTY/wlIVinqcJ4JRwanrs4oaa0rH19XTMhvIuDCTPNFWxZTqsHbL2aQdDJ4sZnFBH5/UWDc/kmN2cHF+jNdN1Iq9BM3Qxff2P0eixZ5X0RkngZulTO7LGZnfAGPQrZZIm2E36K4/d5Ce80S+hHdL5JQyTS0XuR8sPpw/gttK8YoyN0kbI6kH8jV5muMB3rx1BLL23+VfeXUQRlXz7x5Vlsl1G/hQXBVTAxs5P2aufzk9tsNHdGixH9Vaz2aqX5QTzPAkvbg5iIR+bXK5jA/Wmw8OKOHl3egjrNvE2IS+e8FZF9HV+bre2QmCa3V4BiXuHR1sh8rC1Vx2Rh629LRAZD4Wo6rXqdE7Pzi43ItIPU0y4bZN3jrHTbtbGNcS8+qjoeBQ5I1DfOzzaf71f1pxN/amoMuPg3fm7M7KIZufKZAxjZ+b2xg5cMhkZ90KOMiEoxqhO05YwA17e5yGn8mUeI/ERh7N4dyqGPm9gzszf3v0kmQZ/nLffG2BGxuRo5A98HhCF8X90C0ibZuCx35B1MIWvl0wQ/A91pSHcKXXj1deNV+yYUzQpstWdLuu6qVpZHZxWp4Lv5DCz1UDv5AD5pFYtuRvITrWveXTQLK17G+ZGFqRG2pxGHJJ0P9Gy8q7wsOJCz2h5u6dC686lXcTRjNsmCeaWQf/hlT+eybu0vGHb3NIRmCao0wkqcuP0W/Fm1mzd+bdV1b81fribgLuztOr2iF+QiZlOaTIyS2Vi7m6uKDPxFAmFSFRzp2EJj8YisWfjQn/4vnQ24QzZ+tMZDxcV8aPOq4Q/QNNkoF5Awk4K9IR0bZWd1oCB9KosOpqJJ6jUt8w7n5bk/ZKnKUQleMVsFafxWGb1NCUZPfL2vR+Omk2v9f1B67A8x/50VmFYtt4mr9rwqHNE4D1wdnlGrgRFaDQVrNGAf6ceYw5dDN9owDMTYhv54VhEswiwjFSrgsi1ANqc6nwfCSVAckwwrFRAbXIoGvT62rGTiIexrcdE2O1WMDkYzCOASenGF3cUvlF1Etrji7gJtxGtuhbeCbFdCB5p95AnGcd05EdCLMiq7PYDOd5VoAwNuPiwa7t7zdbBbrO1i/RWdLdr6HTThhJOAxP64diDz5qPyTQHR6+b+4MD8cPeXgt/DAf88Iejfc6H+0fD4ai8vpjUsx4+qDJea9+TTSxh57J9/r7rnf37rDzHujCxajb1NJsY/pq1/AS2pSOm9PeHmcA+EI5Zh46JtZLyePy1b4ZbdduBQWAD6PSWiUg7lRPKb1FRSWgRNcWt4Z8FzRlbR/uvD0qypDyH3pfuInaJTAYyycuJF9PADz+Vvh6t8ExPi4zx2StMRfgeOyyl+buc5uKxknzMK4scdyd0Ho8oaPyRgsZRisum4xSoi37VWYooq8PMduPKLz04/74enEVL8a0333xQJl9Z180HePkSWnF8Ze02H5Dm191n8wHGXAzHipkr7L9ReYPNNXn/AnpxPElnzQfE8UwbdxRx/Ox6aa5i8vk00Szi0GkPaKHa1uD1i+2e+Rkev922mZ8RzLfVL/MzwvgaGmUWsfDSIfMJO2QWLsBLa8yna41ZuADPvCfmwzx/Xc0wH+LlSzh6L5uyL7cL5kOS/LrbXz7EmXsSrJi7wnO3oWOZ0cwCb5vRL+CQvcT3Rqx9Qwfmr7LFpctIFCe9WIhwDdJXHoWRoZSHQ/XDBMekMVpvGCw9mi9tjRb740niei3kMabkLXNYp3hAq9VoHnZb3x/vHR4ffO81m6WQUsdCegM/WVQFLHxi7GaOi3cyRL5rCbQtolkiY1iESc/ZqLdJePdONjRE3iCXp2xnr8fFzL1vb8pWRevhZmDayfLkyyiZsDa18S9hGBQn5Ev1/FhWtUQn2l0773yg9JccGyftzWiv6p3QdBdqzgkPeRk4TdANfyjvMeRoHgvZczGpMsRdyHDsJ6iPgh0DsEcyLxBt/f+yWiDD2jFrfL/vHbUOXu83d1gt4EntmB0ceofNwx9ar9n/K2mS8pLf2jZS/xiLqGHKzp2v8FqolsK68jDRpaq3IsB344iH84BHqcNJ1aULNkAdO6HdO0kfJ+ZUjzGc5Bc/wi4BWGFkyMSUQ89GgUQHXopt7NiYwdB0+rGDmo7HFnhC+es7bGDdU4cEtEKyxYG48qczMBqHTakodCyk4TafetKXcSLDxrDETQpUcSbjhAdVvfn1Sxqe3vpcmSsEavlKpfGr7hSVZgzrrFoLcopqUgOH+CmUdyFDnSqqIhKaSEbs9/NL92TImL6sD1SawJ0/RK81KtfURgTBQv1nXsg/HDQPSoS4IeBIjOFxVWher2iGh6xr45eTjYivyL5qwgvN6y9z0RcldRpO3V8yrIJkxNcxPMP4Zn82SrzD4vlgAmVEEY/zXCGHetfebUdjWIeQ776Zi1DGvbYfifjxnPvrlKf5syKu1mnPdX5Z3JuLSZPNyR7qzdXy9rx97+DxbAX8SV3+gH8pHv+UR0i89ZJgnYUto8y1bsRRYMYucLBhl5FM5EAGFCGE96wpQEu/KxO3FcNsKz+3xx/7J/vt5/PumWrX99PV2dl79Wf73ZuzK/Xn1dlpbVlq9KMSAtL1Uz2ePKFumKott4WOHpelG1VK5TKz21QRZGKvwfqqO6LPv/YE72AuWMq89gcHJY75OuG4qvjEcidjO3E9NrnO+WULor968yhA/7nHM2Sb3lbE0ZXbVJchxRWvbyIdQI7Cjk8PKqq5Z9F1vfpz3Grs2h/tNpvN1t7+QRmRjP0YvhacbM+to922cOofDFotzUJd+hIR0t0d6/NYHB2g659EnZvjlOOm0GB+GGKZVGnsMozTvb4jEnJZz+7JhFyJ8S9zES30ZzvZRjADSe+QDIe214WGUUH8iW4vb4JZD5/ZJhcxkzO9iEhU1SqajtlX0Q9crd2KyMBWgpb0ytLgcQzJRF2d/dR7c/6+ffU/inNr1POe7++/vJm3T5rtX39502232236N/5ot/9701VXSGxL655z00yKbeHanph6WoTwsLJQfDWuwWnVv2Ps0soAps2AqRX9EuI3y2NJJo1AhbdNFWfmefOMzkR+Bfl2ft9h+N+zf1+235/2Or9/p5Fu0kVJafBt7j+jGm41rrquNvj+MeyVnpB0F6O/+3jRPae5aGwzXBCwfkrlLY98Qs4ORDhOJmpYfbVNh9xUmTHm6W8frk6VLp/91PsF/8qQbsfN6JU90g3FwJ/mKrPZK+GN2U2tVbspgG2q/1E7Ob6OEn4N3LEkmV33/fB6uuCzmSfuRalmmXbhwGEBANtWzgOdhIdDHg2zKkAvmbEppoNRvMw0ZN35fQPGJv5tFTy1+/1I3OLgrS8jTVEr5sttEz//6+LdBjx8EosKWPjZvxUN6ogPV4VqVuQIC5xPweh8eNv9rX11dp0WLxmz/757fYIavDDRVxrX51M+Fqq+5Iy6wkKzP5AuxNd3fgj9gcJuIJB8HdBWJGIrvN2qbizoDoajt53KB5fFg+W93lhGelRWJKvrU9Gfj8ci2kBoLulVReFpDuM95NSoPBPxgIehiHq4FlzHB1rlzVOUHcS2f909O73SXQ5jjf41p87RwNtasKFAcTza6/LAH/hAXHMKoBgqpT5eXeRYPCjBmz4/b8LXe3X6QKwFjRCX3NZsW3TdVh26rc8z1EyvKFVir0Q8r8KatVpXI/mnnPE43UNVLufJApL4QNzShu6Hppt/03PP4vnjuu66j6ByKKdyHjeUX68/RoYJHyTqXxbHI3e8H8op98MGVFg9SvVODdQ7qX9Dj9Rf/uz2wPnCn90e6X8ujTnlA+e56TwR92oEnD31X6rPp/qH6dip/jWPguuQuUP+ExH0qMEHFM1VT90pa9UwBqPxSSzUN7ihbjgZNrnghOXj8eoyjwJPSawipamf0ujGQs2jIPXkalRZYGvNao4jfB4y4AYydPlC4Pzj1QUhNNIxFX6vDveFw11kKSAEoFQiWKSAL5xlWGMGGUS3W8fY8BqFGzhUNexjaXzrGzXEjYrruxQqhkCYLscDnYGfiIgH7Pzy9siOKcJBIHWu880fN7ST3fznhr06P+u+ZVdvTeSbsb3v9/fICReZB9P0THNeMncLFnvUDzPk2hEV2TlvNiv5cnpjIXaqUp20+bWVsC0ftZOngJam7p5FOI44rTpJ6dYBje3mRzfd6mKR4DLNTxRIaLwDBUbnOXErogWbRwSoy/jS75cGN9PORORL9I6PE4KL7Rv4InTTwtFHL9SOs63Tw33BarNwXEuzgvHzGlCLas8X/RbaNoo4gV5XpWyXMnJBVbCgVLdD/7r5x41jthI5c4UNrbn5BxXfYzFmPErh2DTRXr0k0/MgWIPhpfjD9ivrz0cKB/Xj1QVF1TX+iO42vZBz6nydWtSFoyDUDtaQhvoEdmNYA4zqRBDmiHN3Sr2skQGfRHPyAWW0hPtOGCYpGyoVamV0MGvrjg8O9ncVgsiPf/63/lz9+x+JnJVbJ2N6voS1qn8M7QWANYmkzjGLhQgzMrSyKzAdfshCkdwB1nYqQz+RiOsoi2S9WbMv9wXjVkU0BiA3LjUtOifvngVyrBMV8FNY1xEinYR87LqSiH/RYTDzork6MhVa/ezP7LA8Ns0KDaE7SECfBQKNcyPYKK++FXXBaCu+LqdJMx7HjvHatpm71MMbA6W3Sq8ssclkDUKXVH6JoGSyRIxjabVoa2Xpe/Tl0+c7cGCfWEnwwcH+du6XoAp/Ika/BvmltjtsWjSBfqFsghC9ROobHcktYlaPyWiVlhQ/tzf+SHujcsBcBHJ3Fg8ONc+606FkNz/ekLWwgRKm610d2j3ti6OTO6zGzY83dAthntpxJqMfaBfOjojzAqLtYjpLUnqIdPXkjf71ErzW0B/RpVaCQCTri+QuzQFQ/lJyJ2lTLNV8EDqgToOodu1Ve0wDtxN/PBFkh82k5NeqiXdIMLOZGNoOY/O++spZ75zv6oylHqZQbW0kpa3gGMhpDStXcz/I6JAtetMLgOhQNKWku1kkBn6MTCndOSBALULgfxJuTWE8H438ezsiPfMKBv94d1c9op7wZDT+zmPdaGFuJ5Hide8jyU0dGtFbxp/OggVL+Kds2oV2j7HmAe+LIMZeH2CzYbSJ3gk0ypcR616cxqmNG0hv/qkAsMmRRjndiQcTMRVVKUyHRl9pCWuQbuwuI84k6mb75rjQWVb05gVBI5UUgVHRqqTQdd8D02kCQlHe0IL9OUdVhJ+qP8g0h73UQeJBYMSAB2L0OBGzhD6YSN2XTfUsXHqttF3wGDsHZCBOH36mg8MyBVS9QXmhQhtG+l43HrUpqvgKLJBZYggAS7OXsuw7uONIwNruHEN9Eci7Jdo1RcX2gyWrZKviRjxOvOlCj6BeImhVDZ1Aat5ynEaPkjn7Eq+xrtWxVs4ocDzv76EAp5UxVOnBO0se2XNz6tFScMaoqagUdq4k4n6QBgEKXnweJ+XUPZGzHjH1BJuFGI10mg3cYJrCCPmV6F6cogEKglQ2+zVdBT0mY8ro7phWIGQ+XYugxwOrBWGR5XntsKP0SawZhq993XsK7SertpN0JdbbWOjzcgpmkKcrUqyPevilXcV7PLEvUHBfAhTcCwpcBgXumQDAvWC/bQn7zRXkc4F9c3lyy6Mr5quw8vwJEd9ewN4M2Ns3hfP23CHenjW62zKc1TMDdnvBdMtjur3AuSk4t68bye0FxO3vA3F7wW/7+/DbvjHotmeC2vYC2LYFwLaMEJ8LVluGKfcMVzFjhYdlQ8cyj5ll3SKPXzVC27cFzvb14rKZRHyPBz6PKyK6htMPTfBKxNbCmql1i3/kD8sRWjdR0ZofJtEc8rTVH259AKXfIc2SWpspZ5O7+7DJ4bOtdvDdPIT5vg5/m1Cqr0zcIXGV4lOvtoWc4zoXWSyMs3fn3asz1u52/+vkX9QGy4HAsSw47Hq56oP6H6z270YbCCE1tn4VpBncq6w5Ea2Ln6arGG7SIyrdO1INDiHrUGEO64sJv/Vl5ErPXreggXAgtGuZE54r/GKJu4MWCN/S6A/zgu40Dw/3Hi3eCn2M2jJMwVclYggmL+T28K0fDh8t5VnAExirSm2MneRp5e1Wav3qVmq1f+vkSqXaf81N3RP9ydqn6l8/nVyqPy78cK7Lp6Z88KGj/nyvMo3pH+6QH4D+Itj+0SF9xTqc61/o8s7r8LMaYdTAHbdII6x882oBI6cnLGXkqobXIP2wk1Aa5eN0xJWNUZfN30l31KXFKFyBDAdebVWKNk8SPvjkTf0kQrvN8a4ZYJds5O6jl6fSqsmJvreXIzvhui+sXQFXkI96YSnOpMsS36n+qOofXSmDzNsbsjVfo8KVgwTzC4ZJ1l2MBJ7Q4GncAZqqaBE89j8PMuyugyJ4J5NuhCIqb7Weqp/Eu912s9nc22Xf5SVG3xQJpsqN3C0iN7q6tpBcmeQUZHMh5WWUrdlfEpM1IVXJKmtpkSD9BQnLHT4vuHVHycpVDCYUB3+aV9PMtoYscwJ0ubcDPU6c5lfxbrfVPPyhQPvo8xUS2u47upXasAcs74Pu/KPXwRW+q9NVrcOJnE6RxIb/dJQuhWPVLHoWCXMdn1+jv8lArC1PV475Dag6ea7/2xWCjef9p7IVlJiujvburGvoqivdnGzdsTYTb7PZKhAxfec1m2vfXNtxPZe0L9HMrLYkj1ygh49qFS/QpbwTUWcigmDDFfp7jMzaonbF60j9KUX9uN8/vBx2MQIVf0n8BFN0L9R13TiS89kx2/tfOTG+xTgoN1fAg0jOZ92LDg7FIaobZEh1gLHGocD18WAORAg6WpvxGfLNFC6tn8QiGNEFPdASQwyBahzGb6U/BChQYyhmKCoIebCIqf6ZJRPBIhHw5P+z963LbeNKg//zFCjND9tnJVqSLV+yNTvlSM4Z78nFGzszX+3UlASRkIQxRXAI0o7m177Gvt4+yVY3ABK8SKZsKbGd1EmdsSSy0Tc0GkBfmEe+OL32qYZqX9JBWQp9r68r7wNRryqYEke6zpQ9ow2HXB7OtnZ6f6XyRfXFgSm1oYZU6uglUfq1Kltms7qoItfvrobn/cGv58NPV2fD3y+ufx2enV8NO92TYf9Nf6iu0ldrhkW5z6EQg8XTLXHh8vx9y5SslFB7r0V9KJhoS1Ng4qienszgVoqFSmSCyjNPYvyjhTm0ElI9xISMyiQN3RkWq5F4LZQFmqRAsW6MSmpVdwgUEltkXOL9+4sLx3EezlyFyZZYfIY1JMUkx2trcF1RbE5vGEnC4oW3hohdD3mwShYPksEohW+kQGMd7pOF9gBKOuIxe9Pc6iBeJYns/NFQQmk0ifmr/kmkxnNG5cyZe70tCaZvETPh0JE4jHgQ66MqoO39oEc8PoU0YzEhg/NPqfz0BaMGS4C7NaZMIdBKZdkJLCkCtOrSYnbWHpclaWSBVlB2Ishiq6BWzqwkifbb46P+8dtuv9d783ZwPDg5P3lz8vbwzds3b9v90/P+Q2QiZ7TzzYRy9etZ59lL5fT84PRgcHrQOTg5OTkZdE9OukdH/e7gtNPrdg4HnUGn3z9/0z17oHSypeabyKfbO6qWkIZIjKQ2I6EMqpLUZubN0cnx26Ojo7N27/D8bef4rH1y3n3b7Rx1z8/eHPbf9NuD7lHvvDM4PjnuvTk/Pnzz9qB/3On2z067g7O37TUlh4Ga0deLr8oiSoxTpzAwn9C1s2Wj4RJT3agkpSIDrZSkT0LEpH+GqUsXwSSiqloS3LhdMzpvkkH/Z/0e/r1GLoce/C96sCXenaFZ1UWGsgL/alyJBc898LFnKmF8QUIWgaqBil1dvdvP/G5CZjTw5IzelKv+eoesN+6ceEfjXs897nSPuyenB91uxz09GtPu4bratIksjwGN2T5mQlg+MlZoU4PUSfrQcMmS/Ajo3dBpteHfNeZFvG631+vdYNH76KyPdQkuJoHcR2zn9Li9CWKxSFS0zXjMM3C8oXsW1HYPyNWHC21ToaWj1ME8gITOkIGiD4AO3AriNxoosewDcAzM7zyMdWq53kyRWDjkd2CzZbbh4VvKfajKagWap3CnDDgfQqGIWJCRx8DAYaierhA6KvE/XyprXZ4rW7klfteyzyWLnFliDZPcb5HnC/UbmuKBcJN5WlB+Q5ZYJiFUFmHeUO2l5ba3VXqYat8ht4lHygkcmIkSb3b+aCzZwXd7R8N/99/DDv7g5BD2M9mD5/3Bqkf1IIQ0HrT/+VEX4NvVBbBF8L0XBajkxTOrCFBBw49yAGuXA6jg4vOuBVBBkB2Fv2WiKnMbtl4I4B6av5cqABVseKHJETalLy7/v0jcy0n+tymz0p/TBNIaND7ZzP8ltH2/af9LGPJ95fwvYcJzSPi3Uf+R7f8Vs/1zjP+R6v/1Uv1zjH/hef7VtD6vJP8qGn5k+K+T4V/Fweed3l9Fkb0z2zJVlftfg0eRwJxAN0XgM0zsryLpO9i4PsuU/m3uZ5YEMGY7HNNmdspv4dIWr0mw200AVZ197sLlWok2ydyw2zuKau9cmIzp2McVpAalYyF8RoMqgt6on8jEpzmydPl3CHUN2FRAAwu4r4KOOVkbTrhl0EAJiSMaSGzUruNkA+jLDQ4VfE6CgPlOXfIC9iUempDZGgRuTpRpnO6Y4VeIN/Rcu9R19THCBcJ0NUx13Xpx9uFMl62H4kDGZ4QzYk4DihHmVIKXCnd/cj/2ZSttrAbq2FJwl/7gfJnFc/8n6odBy+DY4p7cy7YSuI/QHVmyTYMPAevYYqSkdYDlfseprXQRk8mceTXk8VCF47IQXI2dUfW42BZGgyRwRY5RqiClgpbWVjN1n26HZtag7StF/Grc1o34LZP0rSJ+l2GyJRZvM+JXk1I34rdM+dOM+NV4vpiIX03PN4kt3VTEry2TlxHx+y2lsumI34J0XkjEb00JPeuIX03jViN+r/QhSr3Y3lJMrwZJjJYVWfV1Ynv14H/RA7klNi0J7lUDbyy49+D08PCwQ8dHvePeIet228fjDuuMD3vH44Ojw463Jj82dYUrYzoPbb8Xt4Y6sLPGje598a6PDu616N3Ire46BBcvee8j9tHBvZpYfaJTg9INmIX7DYHRuSK9/Q/loKOtGYAfcZDfLg7SFsH3HgdZyYtnFgdZQcOPOMi14yAruPi84yArCLIvLbZMVOU90NbjIO+h+XuJg6xgwwu9TrIpfXFxkEXiXk4cpE2ZFRX2IuIgl9D2/cZBLmHI9xUHuYQJzyEO0kb9RxzkV4yDzDH+Rxzk14uDzDH+hcdBVtP6vOIgq2j4EQe5ThxkFQefdxxkFUX2zmzLVFXufw0eRQJzAt0Ugc8wDrKKpO9g4/os4yA10lvC9oNyzUhIo/RqQ48I38EdHsRr4fci4lMeUF9Hp5VI2uk43Z01ydp2eOAH4L7P/2GeCqHDIAMzJqKSI/M+EmNfribQkCdDGpjayFU0lSlaQk+Omh3tsqf3rem9NIyHfrQONJKuUNX9eSwhptNlziuN+Zl6OGL6wgocbiJCFunYUAWEwluBhHrxImgSmbgzDNbAvh8QqwA1VAMMx9Fwwahwl+HMpXA0QuEOmPydsGjh7LzK8fFgMjmlJ6cnnfGx63o9atd2RWS/IuuK3MHPqpasVDWT4XCU3SKrfH7DbM7oeLQxg8MqEospA46oHZKm1uyOKIQWRyn/oKAVdPcYL7JBoJRs1NJxk9CYS7FUFtl3OJ6cdicHvePj8cGhR4/ogctOu6dem7XZ4fHB0asKDdXlYi02Gxq+pp5aw9ZWV/sdKKQ0Y2TGpzNQQkQZYENPITJnVCaR3lCiDqc6qfU3FYWtxWaNKDC53Z60j44pbY/pabs7Pq7B1CTy7brEnz+9u6cuMfTv0RWHscmfB74rFgGCdQN2KKHP9DJJoxj26Z8/vZPq1lI/aSwS8HEcMXoDJ/+euAsID2JBpDtjc9YkqrZTk4Q0nun3BRHB40sNK8AaiSodqtKipXpU1KQBQjfqk0R+Zosa+bJUjVRlCLkIiBRzhgHTYLSAz3O6UJW0dVj7xSVwYR88EOC3xyPmxv6imR5H0DxpapvtAGw84wDYUNuLWXfO5A4DqKYCxoCfRrqklqorbWOoCALE9NU14OnzmEXUJxeXt0cpTBa4vtDnjaM/RoA1Gf05IrsX59dvyae3JiqRkO7xQXdP4WQ/mB2dmOMXLA88Bv6EcNaBpwY2uilEhbZZ1VcVBDPqkIa9b0sj4BAW0coYB6hD4exs8AqvRU95DZNALfUmwZBfz0Tj+Yzi356ILVFdl6FDPW/olwXdHjkssjryugl6CY2V2C2LFjAEREkRWni/ANwMG7KIC4/MExlji60xnCsCfszLryhZCoN6eMxIIwymVtUseL3hwHfWWB9ErIOWMVwp5RoIDvHMVjuDqYQi8DioE9PImf6z10TKU5gABBSBQCB8driXKtZuY/pPo4nkNBSExl5Zn8JgmlOiSUSn83pn1g/SoUsRxVzYZoXgjRYydvTTyDIysQhtHoIyjH4awdkm0GT7zQZpZydPS+L7NegwMSmbbNaw1E0tceRignhCQzlJoHEZn8MqRANcIhciicB7yWzewpK1jIUd5cUDMkoi3wF4I0yaApdIhZkiGsA7OMkMVLQT83B5U86oMUTobqUgpUgitxwcaM4y89bo9eHhwb5kNHJnv/z9s/5eff4pFmFONsY4PHn57HwO5sIDv9XLLBqqrSSSsSDHt5RfFTOfByRQLRjJXAQ8FrCVU0uGGKMj5KWr5RgaRhi1QElGLPWhUNAUc8iIL6YYSKzWMzCOk5gF5C+wTek+Q8cSowOSm1C2XsyZVrn0tRQslWBnIZPIINrMOUiBiMuG5UEqAvq45Oec9oRUSsv2bNpKXWrwxr7oBcwp4BDPaoxf0N7COPGsMIZl/zQjGoVhRRTXGNa6T1MR5a/1zroSDxHFS/E4PCxfOBweHuSQwq1mDawetGSA4ccBtLIqFo6Z8j7ULzptr4oGDZMALY2CUpXWl19wfVG+iTmaKI7igAtJ8w5kIMjolxHOxDR+gehoCgt3R3ufYOZhRo5+GWEwpXmqaQ2GL2jvJoUIHjKcGrB5GGf4IOrqyZF+G7r9jPVteSyIxzGpJIAMP0bGLL5jLHOvYdD4DqrnynTLa0SrkjIhVGK43f3GtbW7zAZFT87soIDeMGRZw+lkrH6yxFjy1ixY6mHc4DUmQqTXCK6YN0AgDfuLnGqkN6uarx60P5vzgHmQhuByyXyd7wFbCdgf85vcLb1MJhP+JYWIz+yCjXy9v68eUU84IpruOeQ6WuiCwzQMI/GFz0FuuJKPF0TyeegvSIw7zrJDCKL06Zj5EtZOH11AXHfumO8j9dfvBjIzNK5wkptG2YRb3MiphNrYbksPrhD6UnPUAKZJWzrgXKsIkdHrSvdQ4VumDyHlKTMKtS3irm2tJWHmDKvlfkH+TuCgnWfKqpvRo0HKPADq+4Y6eEDCdoqFMZILha2RHpIEHosKk0DPYofAdpqaww1rX1HEAI8VdTo7gNe/QzaCCLJzoNg0jcORXRoEInO2cjOmaXEgNaAlguBg766Au8aoeraTeBlv1XEFlbEzX2gISuVBWRqMyrjhFI8HNJTc3gxplfraJ7VJRi9lMu5Cye1OzqxkG8M8esq6a1dec8GC0VCHIbB8xBHlfrZJrZimNN1bL3VqjYLHIhwiGV/BmLPJBDp8QbSSCLWiaOp32fW7wV5TpU7fBHBkRqXFdw2TEGUUm+b0EbYUuamt4QFxFRv14rgpWLvZmivmAL7xvG0+2vtl5j6TRD3Dj9/n9AYO0LcYYfBZgy9Yfcc+4ZUsyh3xms/Lz3hRCwFzc9JrPEfCA+UUw0kEHYtEGU58VO3VYJ/ss1uaboX1qSLujlMt0c3tQD9mFNpVBYwwiCsBk5kd6QRxxJnUbiMOgmZFQFPvGVwBBXBgbyyFOY6mAaGYk68w0iuAZfnnzs6jj5DdGQ2mTDrbtQZ282t12iuiRcZyiFskcwZXw0RMqq07HJyTd4OzS2DtmVLmQQrKNgM7dW2hoR1zkLZEOih2PsnJWRc9WFQ3HN2zkSOUEp07MnMAmhCQmDbDcIoG5swfsygm5zyQMePBuizBKf/NdBZH/9ZKi0iY+8PNk1++s00LM8HApj+nXMiYzfdDn8ZgUNfWbUXFFhcWW4pqsHVRtDL3N42cuaw1S8IMTnxdEalmqblFCriv1w44/AtEsJhDbIUGS2BXN7eU8LNkk8SHSTiClxzujUAH1QcgcGRcb/jvRF0XUz+/MAZehR8PJwrrq2tRUd0szWOTSqoljVQWbc5Vq9PqtbqdVrfdPewenna6xyfHre7Rafewe3rYPmx1D3qd097R8clRq9Nut9clsazFDyVy8+b5agYnfAABtMAXUx6s5BV12ANNcyR8JvNs2HyJIsCZ4EiQyQRUxDyb59pHK5C080fjho9pQIfUm/MAWuJEDDfgwXQIANco/PPivCVDWLpR+C4dwoz6J+oSZgj+cApTpzBjynfsFhaZ8FwdwyIdm1XxHCUPdw0zJH84h49xDjM+vmD3MCPy+3YQMz58Fy7it/AgzNhP1TlYwbbNew4Gu5fqFOTpe5LrfR7FzSpknaXcjP9jlV66ShsWPdcF2OD/xNbW+pbukQuvwey7WFNjGk1Z/F0eTWjSn+i5hMbux6FEeiihOfIdn0jkOPAk3ZN1idisZufIWOLA1Mfwh4uz1MWpz8Tn6gTVp/CJuUmb9ITqM+EF+0qGUnh4SKcmk8cKmSLZtzUCpxQMEz4VwNUwBKm6kOGJeX6UjCNxZ2VXp7P7esYWOhtFzsQdSaCINbljY5MaDCKWkEcCQW9poL2uCZCkqJog98fHOnkMhv1aZlyPVpQxv5yJIK+WXwmhjKUlxbuiExrxh+ZnfTOL8TmwtGWY05Yihe/FP9z36X7PaZNdJYP/TvqXn7U8yMcr0ukOOyps/z114Yv/2iNnYeiz39n4PzzeP2r3nI7TMW1eCNn9z6/X79811Tv/Zu6N2DM1SPY7XadN3osx99l+p3feOTzRTN4/ah86nTyrpTOhc+4vNsfrHJs+XhEFn+yayM+IeTMaN4nHxpwGTTKJGBtLD4KOA0/cyb0SA9WTJbwT/6lpyFLbWOaLqrwRTLV7aLYDgZ2YbEqyYE0AT0Xbl7VLKcx78Re9ZUUe3bAoYP62ZFukQY2WNi7BFOiI3i2bF4fOodNudTrd1pQFUI+miP1mjdPTkLApM2DJd5lI/6vID7OF2BxPVmNsxtNz12VBLGSTJOMkiJNV85VGdzwoYg+KtiXMdz5LCGdmZKTHGekcB4hpozGDaor/qCdEkUgol6FhEoyG1kvWOBLUAxdhziKXU1/ZMYilzvYQH9PHJTTP8X1xB5B1m8EsVxp2BGQ3LUW095r4PEi+NMmcusjRgH/JkjU0X51XxbyQj1dkIZKdnQhWeIp5GaBOJu1IJwNDepfK5cvlecATYyMAQkIRJhDpB90RfUYllEGASq+YEQE1bkXIAhiBQvkVmTCVE3Lev2rCHiyMRCgkg8otKUjqedhC0tkpKgSS+eqe+WOpip4YW9KWkp7r4e41WJ220ykuoNtF1Sordo8bBYu+5YTf+jSw3e/f3p19qON4w3PG5aZRlsOpt5ALctLuOp2/SUynuxKr0EEal3vDYqO/VKrcD0jcDqZwlIfBnEz9ifCplMJVTUkxownSEMa6qwwPIPsBfyPpxKRpRWE9GJxXZ40m05nyQeW4O0B9FRVQfyDyCCXQKc3X1MZ0imlmwGCRYDkIbKeqYcLXkJsKiP7d4kHrb2iNSkMJ0wdqQTX10UMVZiSXtx4vQu5a+W462wJLvNA0QV+yQIqI7DJn6pD/zdhNk/zOIwalSG/2MPuc30L2T7o9w4OmiE6w4HKBEzwIWLRUqgoEUQ9p4jIBS7Jr8kg0VP1bnv69JUSuJk/Rp+GuS+UK8pS103DBP0vtLw9SCwW6EFToSixMsyNm2BHT6RSdFw3yo1ZUx1ZuTX3k2FquV4EK/TOPa5CpbttHS1irxTxo6pCZAymPSzeCAgjlGaZhosQteMvkMuERu6O+L5skQuWXOBd8WPvG1Ie2L5HcwP53a4ewSOjFAHRQaXNWxtpwr2wrax9dbXF7/DHURT2RAhhoLRpEEkN/hNWEGDJuEx8K8Y95WnDWLAulH5avD7A85ADVyGyjFUOTUpqbLs1lHUw9RtW0w7fYktzwfApehwVBOxBg/yN3xmPmQmEtRWBc4hfFMKT0agvLPkhmiq4Yb7uV2oNdK1W0SQa4C4bZefX56nwP/sB9E/XxwRRo9oIpxigi8lbP871cpmrW7BoSqxdymtDIc9TfkEG8//cdG8+YH+5PxBAUk/r74B/6zJuyMZVsP0fg0PjaTDqzeP7H/0JAKWJ5ZmTP/rlXWRfG1LgyuYhlt3Lnj4aha42bXNeHxcUkkW9JS0BJ8gMZHy7PBemKKPNEc8LRYEm+Izn2EIGk3X33Vsr9cq3c365qF/a2MN4cGzayzS7x0vqimpE45fTKJtOFnvpweZMbturtJZPCvWXOnMcRQ35jru7+hP6Nyu3/5N6yISbcDi3k5NCNGGyr/uhjnfl0WNvScnALAg8bbEiwF/3fzm31+bMk1YsAtoofr4hqUkO6TqfrHOmSL2BKC4bW7AU/XfbX6PrNAqgLvO1pYWyndSuF/hEYO0w4Xy6a8pSoElHFnDivy4Kt+SlAuaFYG4Tdi8GeKS6g+3DkinLk+KBhEszoXjjkwk7LJkn+ok8PoIGaW+kyXzOg66n+3YzGQy6HMAW4t6d1PedNcGYdDBR1/WLw56vcwCijlmp81G63aze/wcqebHtly89IxFRZteUGJudla2sDMQ0emfOYT/GHjBdGGEZUzCvIpciYaom4U94a82DfvWWguI475b/AHz+nfDzqdNZgIyjecKvKr/eaIiISahZUqmqJeKCk0+6cOOsoBcAPWOTcssAT0RZJsqtG5IRoUCAKhRJZ1yygY5/VJ0hEzBlnfXFWETPxBa1cRneu4L5dQlotiSBtVFUYaztt8L87baet677An2TMzC3EHEr6SChtatcWfAOOpdQQBZzcgJ8mJZMSqmHimQj7EvqCx4YpcxZH3JVkl8YxdW/ILca3ZeeeqqzfFx4vmiSM+C332ZTpqsc6rgPq5GJJ6L0m4fOQunEG1Y7SABgpXKivPYUWRgqUjrdCnHQnWCxEvcQJqHC6jIOOU7vlCTcBkvdK/mnP6a0nYhbc8kgEAI36T0fW5zZa9wmdBguSFq1ELdESapKHSAjr6fGIweDyCYgoZlAi9SlJ51pjdJ9goAkQuNuJmgrAUo9bhbQyccAsMbJy2caYXpPD2z1Rx+37B9NQxfZYFtmGeffDb4O9bLGHDTGPKWQya5DQ5/+WASPBlEJVJDzIbrwTdxA58555PJk3lHFpQBflBhrE/m9XV+S2C+Y1NZ8pRNQEODRPnQtTTtwaC05CpQXrwGnr6lULPNn12AQKA6ZA9T4gezgnI0uL8AkuibiDGlOA95wGdKpOot5efLq6dj5GU9UUieziF2A8yeer1piC+x6IoBVGYpL2xCG57jVQSBYujOZcSlPSXxA4W4A7thCOHolkLioneLagezF4X6EItJrAv5jRuSTUjYREqsmdiHxviYoGt54D7RSdqbjFk4qWNkVoI8rGQF2h1FNVLZItaem1LfVKDwNsB3IPDYWmC/UNjGmURc8QWEuhpZ0WBJTjoxHGGFgm4GEcLDKwD8O41F/NRcNDaJRjH0bCZ9LPGnrdf1/FJXgBvlockEm6uxsYEnM8CZPlS6Ftv8y15rTPLbnEmBp/ASFiU91Vgly/uyLg2oAr3yQen/KY+lnDvqwLn4bIvjA3icHHI2MeUDjlapKr/fcX789zp6Q80FHvY+HhM3CSGMCpGUzFCRaQN1gKPPe/Sefs76aau90DDe/OoDqt0OXnm3DZk90GY0TgCMBiH6iRg2A0RAiwZdJ4tIPzTy0WwKrh5YYAM6NXaFPrbgRvjrD7CxbHz13CjFl22ZzeDuLtj0YEXnbkjHZ7R6O9lLzzWy1UGmeBuBYaNhtxf2pudKzrN9nMo2JYAaQbfth1KvVxNEhbH2CRUexLR5/Bw2sj3T5CQ8SfXZ/DyTX+vIG7EurjBIblBjMa5JaMTNqTS/fNs8bVdTB3r84+7Dkqkg/GkeSWRgtYESyF0KBJ1ioUWJSTFbyLtYbV9MQoTiXRrIkGaP/gwxWxKSZkF0CZMtZSu+u5RBEmnaIZ2vmXVfW7tvehW3l/k06UaSPKh/Vwr2jVv36L/pT+b9GdUhZJ+3y1Jt5PoSPletJTDSnThpPgWjXJx88/F9rSYwvKFZLWYMmDJf5kOlG+F4myCr9xdrcmEbavuWVCKptPPmziXgTuI+h8Aj0o1yO7oNlrkv5Ce1UGIh5iG5oa5HjZeptD+ZpD+EBM7mbcnZWcQowPxK4MTHfw9rCi9S31uVcSUrfdbbfax63OEWkfvO70Xh+c/rd2+3X9fB8gSN1TbZMiPHuoQw3cKZwgNZ3Xh+3X3d561Fjt5LfdG/zMwE8DhtQFf5yr7Aw994tUrtF926LHTaJbtiVaYAeL8BUtOpyF+T4Q6+qfMorslufWzgzbWKQ/pYcXJfphlx/2up0HMIF9CUVg0pJq9zXJ0XquQaRi81jEb0tCQ8JqEnTU6x0c6y954LEvNhWEeMIdqviywvdrEC75P+wRRIOAAYTZLlqylCH00uQBGfO47J1324cnddGVLOLU327rXp0kqYYyF7G45KRqW7264ZEJGiAZs8C1z7Mn+iYbRKQkHs4o3q5ztwkNjbLYcLWLjfVJA+xjXeGDYwE7nyQMVch4Cjrr6ldibK/39s2b0/7x4PzN2/bpSft00On2+2e1LUN6nDFMFXRLLL8wl58RHmXa7E2RsC3C77A5h+0SA55Iuwg96El2/EL+Lcg7GkxJP1qEsSA+H0c0WjjkirH0JnXK41kyxvimqfBpMN2fiv2xL8b7U9FxOof7MnL3XQSwD3t6/D9nKn56d3Bw3Hp30Cv3JAK3vHfUWsMMmwbc32S7KdP9pkGjSNDjW96n9H2L7WSRnM9X6+L9FLaTRdOjcYMQkCrvWu0nr65/znzQJnn3c66/v7XfVGf5uLvcmLSfzFYyR/S6VNg7ni1TUrmXNHgUicoJ7jFEPYGNY4HG2mS80E2gvvTcrqdjZRPBkqZdj5KaHaxCugXq/pqMGV5t08CdiUh9bKkF5lWa8v9GPZND4X8g7L7pvKTXJHhdH4VnVwt4E+r7urklUI2+ZOWJOaZEQe8py1DDG68J9XnavBK6JpqHrQcrEIR/A+hFCbtNj7TAs7dehNhr9Ynns6Pg1n2adWkx+AF9DpQJ/0cE96GH5BYfnvMppGaCEPHqIAddcUQ/qcAKnFz6K/VhWKU3S0hP5YNhNxgKME0iFIoarIq+GqwHCdnPrSQLmfZQma6EDMwFd59JB6p4WIeo9/IID1rUu8S8S7hnpoXri8TLZkAfPpo4gghClSjckVVPivf6VxWW5eZexYjmbBtNPW+IDwwNSBgEmrKKqDhHcpTjSw6f06lV93bZ3ZRlEuict+jY9Trdg8PVqnMBsMnFIA10xCFTXgF2hPxEzkCE+IzwPY1vDlMgzMF3HcOEe9Fdph2VYFZqiDW6wXxYg1+rEUh5wL2H4mBp+KOxqDtbLDzm1J3xgA2tVO6HoqFB2VnhdbHQKwTG7AwtI/lQVJbBq4tPGAk0sI9WEA0om5l1MYAuhSJ45Og5IMWRtX3zhHvDoszADcznCmugfkMHChZ632fY9Rutm/oNjIGEyktDtcRkjpHxK9R4rdS4LVn/U7Tq0J9dyueB2QD19TQ2r0l/rGKgxcTqVypFuGQoMJ3rjwZv2UvfmqMW3qw36MOHw16BEpaA64+Dj6/Jr+IOPKg5DWG1kOwXC2yFL3OPP7NiYcoWJ4WCY3QaXIxXy9RG6/mv5pkS6ItgImzt1qseACXGxlkKDd9XqrNeF8/7JvQEw/lMs07pMFc6i7lv1k+VOQiPgD8AsXfZm4UCxELGj5gZy0WZK65nQIyF8BkNaopjkvEKDkktNSmPK6QzTrhfHrKsAanb0uicDDrt00Y9dCC9CkawI7SqEYEjpsp5swoXGUcsdmf1kTGjqH52wSLV2JtkDJV2YiYzDf2P/V0F3Oz31A3N+5QZ0MyXvNc+Zy/da6OzRx+hjUVZhMJzagpiBa8t3oTCQ3zLYoehEu5tbKRL4ZHPF4PqgXhYGoeHDxri4rI8Avw/3tFsjJgMYnkw4ZWWp0cOZmphLRmssGd8/IAGYFWCPoz4//7P/5VEl9oqoaRXm389el2zfh7OaRhCYUZFV+NfjbVp0uvwnIZlLmLfPXQfnh7eFm7VyEsGjqaInh7qKWbViEcs9Dnc9eUOMjLky+jVGzaDu2TSeCz0xWLOgg0PnMFdMjBsDaAI78ZJtgAvGTrzNDY6cApWX+R4fIIJqlANggZph/ms5GiUBHAatWeWdr2KZuv6ZfpFBQb6x2xFTw9SqlbgDPamll/2pe7WQY/tZIHxK7YPxWEgJyUqDWQjWJKQ4Qy+mnfozPNlsuwxcyv9KsVYefxeiVutqsx5bApK+mh8qoo0FMfMVU0w/1OjVv4EFXoL4SiV5NestVxyoCn2bX5lZspfwhc3nLZoEguohwQ5qNm0+Z/qV7hdxXy4BbGfS89V65zEVoCy/WaNRwpy2R2Ffs5R9wb5JLf75mJeyhUYwz9zu6MjWMQkRU1fSSzHhnubROScQqFXGBMaC9vVCXQ0H+QujRlhPJ5lsvCIl6hSKDGNYiifr3aHiCHcBcFtFXxJswsJGJmENKJzBvljItLJkihrBtV4mKda3+MX8LGps+8RNUyxoj6AiKUKabq4VE9ogwW98eHRGWzG8ihBfA6PJXKmmrk6bSSMhJe48SZZDJhma40eALaJKdWrENqC8uUQ2pGmLCLZtXDauwcpK1t/YzgpqEZwGcsszZIkSgIsdcmDagyTyN80Xp8/vSMzOPqCkECFiJ4ViOMqEbpJxLya+OSPY5bg8/uMxbMcT+6oTCeZPtSCsDeIO9H1GkREAhGnJxLFq9+GrggyYzSK4SKPzEXAYxE1ChZ3ibHUT79att4voUSPqt/O31XmB7IHs64glklyxZhGomZQeL3i1OjxbqY1SE46BcjLl/YVC/vSZR2TEf9h0WsiMdPy1auVjtJjycLOOH+JMVwgUpmGDqdq5HxDQj294KzniF4LyBTVBIKRiZmMq2CtIiSRlWRYobmVYw/0KLB6zjnkTDNXBJ4s0ybdGZvX9eWTyHdKLyzz4VfK/kyl/pEk8jUK+TzkUeyGoybmYMJ/IIBzpFIA8W85qpho+ji/LiG5/kgPJuRXc0IkJmmLCeWKaMmDH9JXBh4z4IMpnuqaZ62Ad/iXvgRO5sVlBZWPOCC8uFyJ5YWNVR4Tcw7WzMGDpXTEQ1NyW6+xUqfUSuHfQvHV0ORrZoEBSYQ7Y4BaQSHs4XN6rwuB5Je5BxqdC1XlVUQgBE0jcTEkG9NITMqF4UQsQHKlTl0Ztu6MuTfDoiV4AGZnJBY3LDA+M1TGBNOb+DENmEikvyA8uBU3zDNdpiZqcAmnk7rcA5wikbsZixgxFXzJxaWqhYwPm0XdFEKGxFtVWKwsCDifkGHuTidL3Rli1Yp6pGHsDD6v3B44wczOU9Hth2906UwVp6D+RpzRK8GnwItngWc9jF8bLy9gX2I0J17iM09xp4Iu1WXkcdK6hprRCEcNns2YdIVGWwJZ+AlUfjabnkbCGxg82NCX/o0ygvqX9dabPH6XCgQegOiJl6FYc/HJLfCr+FTCxYx+MagEXHBcHgQaYRgvFEyGdWnbeK9Fgd8XPU8d2akKJlghSDYrDKYGcLUMclAblxGTescKyuhTGesQRjbncZztc6nWTNyfZmoD38ls2rLACwUPYqiDJU21QDW/ofDHaC48XKj9kdN4VS3MSkEu4/QKZboYpEpuMyTHJrgaGdK4NJCV5nfPKG8xrw+MjpYQFuDAUS8GygYwFpTGNh7VcC5f3eMnrRj8HQum8QzJBAQyWuEMgLAvXIIA0Z3yfS5LWJQWrQewWWfO4dqo4DVJIwmhrpAn7gJtNia+ugJqvMp7sQuNL+wm3RkaWx7EgpjnlyxdZZyhjMyURctwjtGv1doaJWWBJOHDQCbhKqjAgYfBhTdXQQYGDWdcxlnpaPinKvXlYrbtt1jgyRI+pWsmj0k3+v/sXUtv3Mjxv8+nIP4XX/wnNggWCXxzZG9srFcWLDk5jiiyR8OYQ86ySUkD5MMHv2I12U8+ZkZeGVjvXmyJ9e7q6urqqp6crbJs6m0Ci6bybkRYnsXlrufgmh4zuAmjm1jhk6s8uNJn4V245qfXfXDtzyDnWB8w6gdOVM0pPmGpXxj1DWOLbr6PCPqJI8C3+ykMls84AoftO/qAo93tkvrgCzm6n8xMd/Fv//ARhqHLkIxHTP1m6IRcbQbCcHnaRLJNUyGGdzBBBZ8b8SbJC5E9216Pddly161c8lmH7k0et1Uh4uh9d2TAWa8mXt09HA+Id3s37KLq2nmkoA9YtBc1rlk04+mIYtnkMvpLf37nBgwg9zU3nku5T6cyzS7upTubWtDp0iZ8lzytmXh5CvWwnF3ylO/anaZINnUyHzVloeMQ+ZjLquH+ZUnatMPbb/5NiXmjonZp3uRlUiiqT6uAvKlbgRREWUWbtiY1M82PeVEY9HYyzVHPgZeuxqTmgTb64XnyEH0G4pso+84GBFm5EG7DqPTX3wZw1kRzi2+vfqP2RHkqZrpE7XDoo96gHNBVpgbfmQdx6uhXImHSVL3n5sSURiJSeFFRVd/a/UwSBxgzvPYgXQ0Rg1Vq1JH8ECnNc+clhxQhnvMrR3OfP4gylCasG1c0uhLCSXGVT0T5OasySqgjHVXnqGTnsMZcBemEtMfFn+EcvVpMh7LZiiZPtWLf/7vu/5HQyJkWa8DyyyugIA2hmTGJz3G5pn4ZI9SSe7E2L6inv6MOKadFAB8BAilg1cYF1kezPLAZdkfIqsYrvmozPEuVKtRCTlb11LWp2ycHzNOaPiZOnDXRosLdbtBe9BDikZqPHtBkKXHI0MGud/lOTIrbAo5v+mkegBLx40YTvmzEfnKJ2uYT2Aomr/HCl4I6RJexKcbDtjZmbyPB4jxk6nTr/dCWYdi36K6u67Kv7jcaoQURYVWMOrpxCSzxeTaq/1RtXYrDaopzH8nLreeInIr6pEnu5WqE4xtMU+zdCpEPr8H8ycX4ntsumLAXbBqirqv6eQxjTPA7IWVy7/8uaFCySdJv05/0v57WQpRyWzXrWmwmecwOZbLLU3vXGGf+Mc+arZckv6IMJf0bH6vbNirZGEiG4vb5kyhk7EW8Ffn9tjkW8wf6ehK1F/NdUYVyWbZIHbxvm6bO71oup+/yL1jAmBb6kGdtYtDRYYqjzxg/iuJB3NvQviEy3LiiZUDzqrvKoRGlGDv9KF5l0e8t6kvNKuWQKg2pJtLW5rg9+2QLQumo3J38isNQogxawQPemiJPjUt+FI5GF1Xd9ejPqOc4cY7udDT5IUJ3SRXH3tXVoxS1P4jlH6phLjPD2QGkX5UGe/8wUVDGoqmTtH+PZstYYRFPe/R7GJpE+JFNxJdv72RVtI1QY2E5iqQGGwOGnjpOoJgcqjoIDB5Ge8GKHxQ88JQpvgbvsi4hB+oyqjO7SXV+wpw63KodCWvzF9WasxFls2lRX5uXalKPBcRHzAlbivqsOAcfn5L6XvzRnGSpKj49gZN3akUyKxEOJCJDprM7yHwfXqxj0JHMfKqS4ylXgI6iPy2kl3zfNxYDaVu0eIHygIT9AW0e5TbfoE4FoypUJq0oog2q2T0lemrZr8lpraYkOOKKCADnZinF3+S7bjPrX8l0iW+UC9UiKdjbGEA4QuRv5ZHuZlEMZjGlklPwgRpLXjxWFiIQgHmQpKpNJ7boahPtkvob0vA7kUg0qdSaU3kRk0c+zuR3VVk1VclDu/MSyQhJyQhiliBD/GJE9ieuZSd5NbGKZnKmvvZoz+YlPh8zYwvbnMQ09ZXFziMFpQjtAEUxZaxy4q0PhVC7qsVBH25urlRSdWbkwxD8Mg8sfUKzLHk3PHyYk3dGOH5q1pkvCZDH7qu/WTSxQ54zHconjZDVKCB3VaYnGMJAQoB0YOeIw02BqD8UnbNpQdA988RAX1xs3tOo35FdQQXN1II/QTXTdhicYvJQiyyvMad8NZ+JCQZUM8UedLSpiqJ67GhNauqWTS0A+4nNOFx8Qu1cTgPW+aobcPiGGi9okKOlJ2UORgilD3ZQzrI17k0NnYkkE9Zz8ZEk7UiidpYscFOZJmXn2BVypVk2dZGp1drpL17ZRC++9jAJIehFchB1VNNdS1Pne9pMZDxz3ag6aa/UfGvHIOe/1g+jIfNzJ5pHIUpu8H93aGjjZXn83iIg74rWH2vUU5Y4yTnQlOT4V/k1X1fGzpRXdawhTbBwKnScrEXieIRIa0vpjpmKV86v4275DW52e2TIAZQVRtolVLlMFoqnaUgAJNG+Fpv8CdfRVknR8B9fT2cVGm5UDbmBw5CPoFXRXVRAkVFpFkuYf+jOK8noG1ASL3R0S4IGj/p7uduhy8qHjGUo1ot89Vx7q3Ql6RlRaArGpX4s1JMGe9MZXM6flvC8loAlL9bsBo6yhFE7kPyqgCcJdvlw3fN4PIYDkD3IhMd4wUJWFr7uNqfziLl7/KFWl/LxusD7Nxz6P0L4DixWBq1NbZega3vWluH9oY9Bcw442/f/uJpTkZODNIjQQGbqiLaIps4F3m+p19P6ES1iWmI/MbQVHWU8Y95aJ4939d5QcDIuZdLpKbqGPXWRrwMOX+RljlHh0c3FlaZfVUoXR+/LjONmms40+G8HWpbzKyNjg3jJe8FLsWLtQLzmjJJ9MFaJpuUHZA2i3/YCAfIvtKQRdFI1wf+jgCHSaeljZFtsCntXMbr2Vj64B6gAGfj/UstxAZpekEoFjDRu9RGlmwnuwhqxl0OhbLxyKPMkbvxr0q8/JoUKU5U0dFuyBTJqQ/OcUtB2VjYCCEguY8yU9jUAdCKuk/I1MFLJT8w/6fxAQvLHBgWE9KaS4vq2nCuJZSUkFslXlcx1XRARfF3EGnltPKb8KV55qTgl92rbZbw6R9WLheRaK4zu0LyObnvjvkUa9haqENltAL+Zv1qEHOkFjjOGXj8dDWo7qHOKEWR7J5u8aT0nQVMOa20yzbI1QM6H68QBBBszND3USI4owbPkw6sjvPwMNVCopcdpKiTj04T6ZyNTpS+P0BIZdRijAlvgOHjXaVI9C4swgDIiM3eYpr9hdCUZ8DFAoSJKTr4sSsfuq3pJHbD16yflYwGLQ4145RB2Yjbq5uLUZBTHb8bPQoRMEBOMNoXEQPdcbqPEjhntE4QDcDhRDFXOOm8h/r7LcvAh4wBVnF2iQ7YFf4PbGqRHb+vgOkzxvrBgtc10t/H13TK30X3tl2VAhl/fneY21PuMlS0NN9oIUID/L/tXNcgudjl4LinmF5plg5ApLVo6yeAlSi70w+GZvMXXdz+Ct0Bc3lJPeyJYYkJB86ejmO8ofCkiZW9I/NQiFfmD+omKMRx4KmxB2CglipHUAf+FSJW9CgxE/qw5luvPF79e/4zuiE+Hmb6lh+HXQEDyOiLLsyhpmH8720q++XT9slaykwDT93zGBdt8yBMltqbqn4s64IbV3q9qDUhTKRik45dij2qbawr9wRFUJUoaioqPFgXKfdGUq5eAPjTDWLTTacMy15hCTlGMOYt8oMPyWlk/7u1N9Lf47/FPq5DkhrlweRltkgek6zbUpEz1ssR0vXgYg34bR++TusiFanJgzjXvTeKV1Md9du2ajKnmU5zqY+SneAoI4UhGCfMtruybM3L5h/uXbVJmcpt802UUJuUYD7PJS7gXWHyPzAkTNAfCgaADeJCvzluIv+/hSGw8lCFfTclwRH4Xg9WoF8i8uzdF/9x3rmaROGprIY/ND7mqvagy0VME8Kr3mHwdbfSmhuJpn6MDFFoaptskL5HKcsDdqgeo8B67fXPAvQGSvMz6PpHSTuQr5rLkINe1QGdDfVb2hGon+BsOAJTVAY4I3esLvRuApiKWBHHoACMZCBlHlwK16w94CEHPIrjJCn7qcQidf11mRO9E0ykDzsqgULXNn2ky3hmtcwxnWXv48Smt6g9b05vor/2Onac7/WT68eK3q5lbNH/pF2jALD5eRegbw/DilV9+CgPnB89zBs03EZiL3qfb6gsDxvnTd+GxeAvpIUcMmq6Zv4h9cbC3EQ2Ezfeog523AicjtKzUIzS0cFx2R9Z97xdLQAkaEgaJIz8OqrmKaeEMMIssK+Vc21iqIlDRvZR6WQq5r/epppH7L1cXC1XCEPxyCOiE0GxRpr/lW+h5YuemDitbDK4Pm5EfBshI70pkEMSr37qxbLa6wqIIwxWwwYEwUSsIqiWO9zrTuWA6lgW6zQOs4WpF3fkQNdYWfv3+y78+Xv7T2bhvLz/frNUPg/TaF0FHEE1K12+Eqo0rdrwcsAj//KtL89eyDy9vz+FBP9g02MtUTxMOcSuusvWWNq7lPuca/t8AV9l6Fw=="
 }
@@ -0,0 +1,188 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/elastic/beats/v7/heartbeat/look"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// responseFields converts the response to ECS dns fields, plus the
+// heartbeat specific dns.rtt.
+func responseFields(query, resp *dns.Msg, rtt time.Duration) mapstr.M {
+	q := query.Question[0]
+	fields := mapstr.M{
+		"id":            resp.Id,
+		"type":          "answer",
+		"op_code":       dns.OpcodeToString[resp.Opcode],
+		"response_code": dns.RcodeToString[resp.Rcode],
+		"header_flags":  headerFlags(resp),
+		"question": mapstr.M{
+			"name":  strings.TrimSuffix(q.Name, "."),
+			"type":  dns.TypeToString[q.Qtype],
+			"class": dns.ClassToString[q.Qclass],
+		},
+		"rtt": look.RTT(rtt),
+	}
+
+	answers := make([]mapstr.M, 0, len(resp.Answer))
+	var ips []string
+	for _, rr := range resp.Answer {
+		hdr := rr.Header()
+		answers = append(answers, mapstr.M{
+			"name":  strings.TrimSuffix(hdr.Name, "."),
+			"type":  dns.TypeToString[hdr.Rrtype],
+			"class": dns.ClassToString[hdr.Class],
+			"ttl":   hdr.Ttl,
+			"data":  rrData(rr),
+		})
+		if ip := rrIP(rr); ip != nil {
+			ips = append(ips, ip.String())
+		}
+	}
+	if len(answers) > 0 {
+		fields["answers"] = answers
+	}
+	if len(ips) > 0 {
+		fields["resolved_ip"] = ips
+	}
+	return fields
+}
+
+func headerFlags(m *dns.Msg) []string {
+	var flags []string
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"AA", m.Authoritative},
+		{"TC", m.Truncated},
+		{"RD", m.RecursionDesired},
+		{"RA", m.RecursionAvailable},
+		{"AD", m.AuthenticatedData},
+		{"CD", m.CheckingDisabled},
+	} {
+		if f.set {
+			flags = append(flags, f.name)
+		}
+	}
+	if opt := m.IsEdns0(); opt != nil && opt.Do() {
+		flags = append(flags, "DO")
+	}
+	return flags
+}
+
+// validate checks resp against the configured expectations.
+func (jf *jobFactory) validate(resp *dns.Msg) error {
+	if resp.Rcode != jf.rcode {
+		return fmt.Errorf("expected response code %s, got %s",
+			dns.RcodeToString[jf.rcode], dns.RcodeToString[resp.Rcode])
+	}
+
+	if jf.config.DNSSEC && !resp.AuthenticatedData {
+		return errors.New("response is not DNSSEC authenticated")
+	}
+
+	if jf.rcode != dns.RcodeSuccess {
+		return nil
+	}
+
+	var found bool
+	for _, rr := range resp.Answer {
+		if rr.Header().Rrtype == jf.qtype {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("no %s record found for %s",
+			dns.TypeToString[jf.qtype], jf.config.Query.Name)
+	}
+
+	for _, want := range jf.config.Check.Answers {
+		if !containsAnswer(resp.Answer, want) {
+			return fmt.Errorf("expected answer '%s' not found", want)
+		}
+	}
+	return nil
+}
+
+func containsAnswer(rrs []dns.RR, want string) bool {
+	wantIP := net.ParseIP(want)
+	for _, rr := range rrs {
+		if ip := rrIP(rr); ip != nil {
+			if wantIP != nil && ip.Equal(wantIP) {
+				return true
+			}
+			continue
+		}
+		if _, ok := rr.(*dns.TXT); ok {
+			if rrData(rr) == want {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(rrData(rr), normalize(want)) {
+			return true
+		}
+	}
+	return false
+}
+
+func rrIP(rr dns.RR) net.IP {
+	switch v := rr.(type) {
+	case *dns.A:
+		return v.A
+	case *dns.AAAA:
+		return v.AAAA
+	}
+	return nil
+}
+
+// rrData returns the record data in presentation format, without trailing
+// dots on domain names. TXT records are returned as their joined strings.
+func rrData(rr dns.RR) string {
+	if ip := rrIP(rr); ip != nil {
+		return ip.String()
+	}
+	if txt, ok := rr.(*dns.TXT); ok {
+		return strings.Join(txt.Txt, "")
+	}
+	return normalize(strings.TrimPrefix(rr.String(), rr.Header().String()))
+}
+
+// normalize collapses whitespace and drops the trailing dot of fully
+// qualified names, so `10 mail.example.com.` and `10 mail.example.com`
+// compare equal.
+func normalize(data string) string {
+	fields := strings.Fields(data)
+	for i, f := range fields {
+		if len(f) > 1 {
+			fields[i] = strings.TrimSuffix(f, ".")
+		}
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,111 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/elastic/elastic-agent-libs/transport/tlscommon"
+)
+
+type config struct {
+	// Hosts are the DNS servers to query, e.g. `8.8.8.8`, `tcp://8.8.8.8`,
+	// `tls://dns.google` or `https://dns.google/dns-query`.
+	Hosts []string `config:"hosts" validate:"required"`
+
+	Query queryConfig `config:"query"`
+	Check checkConfig `config:"check"`
+
+	// DNSSEC sets the DO bit on the query and requires the resolver to flag
+	// the response as authenticated.
+	DNSSEC           bool `config:"dnssec"`
+	RecursionDesired bool `config:"recursion_desired"`
+
+	// configure tls for DoT and DoH servers
+	TLS *tlscommon.Config `config:"ssl"`
+
+	Timeout time.Duration `config:"timeout"`
+}
+
+type queryConfig struct {
+	Name string `config:"name" validate:"required"`
+	Type string `config:"type"`
+}
+
+type checkConfig struct {
+	// RCode is the expected response code, NOERROR by default.
+	RCode string `config:"rcode"`
+	// Answers must all be present in the answer section of the response.
+	Answers []string `config:"answers"`
+}
+
+func defaultConfig() config {
+	return config{
+		Query:            queryConfig{Type: "A"},
+		Check:            checkConfig{RCode: "NOERROR"},
+		RecursionDesired: true,
+		Timeout:          16 * time.Second,
+	}
+}
+
+func (c *config) Validate() error {
+	if _, err := c.qtype(); err != nil {
+		return err
+	}
+	if _, err := c.rcode(); err != nil {
+		return err
+	}
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be greater than 0")
+	}
+	return nil
+}
+
+// supportedTypes lists the record types the monitor knows how to query.
+var supportedTypes = map[uint16]bool{
+	dns.TypeA:     true,
+	dns.TypeAAAA:  true,
+	dns.TypeCNAME: true,
+	dns.TypeMX:    true,
+	dns.TypeNS:    true,
+	dns.TypePTR:   true,
+	dns.TypeSOA:   true,
+	dns.TypeSRV:   true,
+	dns.TypeTXT:   true,
+}
+
+func (c *config) qtype() (uint16, error) {
+	t, ok := dns.StringToType[strings.ToUpper(c.Query.Type)]
+	if !ok || !supportedTypes[t] {
+		return 0, fmt.Errorf("unsupported query type '%s'", c.Query.Type)
+	}
+	return t, nil
+}
+
+func (c *config) rcode() (int, error) {
+	r, ok := dns.StringToRcode[strings.ToUpper(c.Check.RCode)]
+	if !ok {
+		return 0, fmt.Errorf("unknown response code '%s'", c.Check.RCode)
+	}
+	return r, nil
+}
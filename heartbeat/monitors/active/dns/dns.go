@@ -0,0 +1,184 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/elastic/beats/v7/heartbeat/eventext"
+	"github.com/elastic/beats/v7/heartbeat/monitors/jobs"
+	"github.com/elastic/beats/v7/heartbeat/monitors/plugin"
+	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers/wraputil"
+	"github.com/elastic/beats/v7/heartbeat/reason"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/elastic-agent-libs/transport/tlscommon"
+)
+
+func init() {
+	plugin.Register("dns", create, "synthetics/dns")
+}
+
+var debugf = logp.MakeDebug("dns")
+
+func create(
+	name string,
+	cfg *conf.C,
+) (p plugin.Plugin, err error) {
+	jf, err := newJobFactory(cfg)
+	if err != nil {
+		return plugin.Plugin{}, err
+	}
+
+	js := jf.makeJobs()
+	return plugin.Plugin{Jobs: js, Endpoints: len(jf.servers)}, nil
+}
+
+// jobFactory holds the parsed configuration shared by the jobs of every
+// server configured on a single monitor.
+type jobFactory struct {
+	config    config
+	tlsConfig *tlscommon.TLSConfig
+	servers   []*url.URL
+	qtype     uint16
+	rcode     int
+}
+
+func newJobFactory(commonCfg *conf.C) (*jobFactory, error) {
+	jf := &jobFactory{config: defaultConfig()}
+	if err := commonCfg.Unpack(&jf.config); err != nil {
+		return nil, err
+	}
+
+	var err error
+	jf.tlsConfig, err = tlscommon.LoadTLSConfig(jf.config.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	// both have been checked by config.Validate
+	jf.qtype, _ = jf.config.qtype()
+	jf.rcode, _ = jf.config.rcode()
+
+	for _, host := range jf.config.Hosts {
+		u, err := parseServer(host)
+		if err != nil {
+			return nil, err
+		}
+		jf.servers = append(jf.servers, u)
+	}
+
+	return jf, nil
+}
+
+// makeJobs returns one job per configured server.
+func (jf *jobFactory) makeJobs() []jobs.Job {
+	js := make([]jobs.Job, 0, len(jf.servers))
+	for _, server := range jf.servers {
+		ex := jf.newExchanger(server)
+		js = append(js, wraputil.WithURLField(server, jobs.MakeSimpleJob(func(event *beat.Event) error {
+			return jf.check(event, ex)
+		})))
+	}
+	return js
+}
+
+// newQuery builds the message sent to every server.
+func (jf *jobFactory) newQuery() *dns.Msg {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(jf.config.Query.Name), jf.qtype)
+	m.RecursionDesired = jf.config.RecursionDesired
+	if jf.config.DNSSEC {
+		m.SetEdns0(4096, true)
+		m.AuthenticatedData = true
+	}
+	return m
+}
+
+// check sends a single query using ex and validates the response against
+// the configured expectations.
+func (jf *jobFactory) check(event *beat.Event, ex exchanger) error {
+	query := jf.newQuery()
+
+	start := time.Now()
+	resp, err := ex.exchange(query)
+	rtt := time.Since(start)
+	if err != nil {
+		debugf("query to %s failed with: %v", ex, err)
+		return reason.IOFailed(err)
+	}
+
+	eventext.MergeEventFields(event, mapstr.M{
+		"dns": responseFields(query, resp, rtt),
+	})
+
+	if err := jf.validate(resp); err != nil {
+		return reason.MakeValidateError(err)
+	}
+	return nil
+}
+
+// parseServer turns a configured host into a URL whose scheme selects the
+// transport. Hosts without a scheme are queried over UDP.
+func parseServer(host string) (*url.URL, error) {
+	if !strings.Contains(host, "://") {
+		host = "udp://" + withDefaultPort(host, "53")
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dns server '%s': %w", host, err)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid dns server '%s': missing host", host)
+	}
+
+	switch u.Scheme {
+	case "udp", "tcp":
+		u.Host = withDefaultPort(u.Host, "53")
+	case "tls":
+		u.Host = withDefaultPort(u.Host, "853")
+	case "https":
+		if u.Path == "" {
+			u.Path = "/dns-query"
+		}
+	default:
+		return nil, fmt.Errorf("unsupported dns server scheme '%s' in '%s'", u.Scheme, host)
+	}
+	return u, nil
+}
+
+// withDefaultPort adds port to host unless it already has one. Bare IPv6
+// addresses are bracketed.
+func withDefaultPort(host, port string) string {
+	if net.ParseIP(host) != nil {
+		return net.JoinHostPort(host, port)
+	}
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), port)
+}
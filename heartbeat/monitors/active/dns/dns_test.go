@@ -0,0 +1,450 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/require"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/go-lookslike"
+	"github.com/elastic/go-lookslike/isdef"
+	"github.com/elastic/go-lookslike/testslike"
+	"github.com/elastic/go-lookslike/validator"
+
+	"github.com/elastic/beats/v7/heartbeat/hbtest"
+	"github.com/elastic/beats/v7/heartbeat/hbtestllext"
+)
+
+// testZone is served by the local DNS stand-in.
+var testZone = map[uint16][]string{
+	dns.TypeA:     {"example.com. 300 IN A 192.0.2.1", "example.com. 300 IN A 192.0.2.2"},
+	dns.TypeAAAA:  {"example.com. 300 IN AAAA 2001:db8::1"},
+	dns.TypeMX:    {"example.com. 300 IN MX 10 mail.example.com."},
+	dns.TypeTXT:   {`example.com. 300 IN TXT "v=spf1 " "-all"`},
+	dns.TypeSRV:   {"_sip._tcp.example.com. 300 IN SRV 10 60 5060 sip.example.com."},
+	dns.TypeCNAME: {"www.example.com. 300 IN CNAME example.com."},
+}
+
+// testHandler answers queries for example.com from testZone, marking
+// responses as authenticated when the DO bit is set.
+func testHandler(t *testing.T) dns.HandlerFunc {
+	t.Helper()
+
+	// the records are parsed here, as the handler runs in the goroutines
+	// of the server
+	zone := map[uint16][]dns.RR{}
+	for qtype, records := range testZone {
+		for _, s := range records {
+			rr, err := dns.NewRR(s)
+			require.NoError(t, err)
+			zone[qtype] = append(zone[qtype], rr)
+		}
+	}
+
+	return func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+		m.RecursionAvailable = true
+
+		q := req.Question[0]
+		switch q.Name {
+		case "example.com.", "www.example.com.", "_sip._tcp.example.com.":
+			for _, rr := range zone[q.Qtype] {
+				if rr.Header().Name == q.Name {
+					m.Answer = append(m.Answer, rr)
+				}
+			}
+		default:
+			m.Rcode = dns.RcodeNameError
+		}
+
+		if opt := req.IsEdns0(); opt != nil {
+			m.SetEdns0(opt.UDPSize(), opt.Do())
+			m.AuthenticatedData = opt.Do()
+		}
+		_ = w.WriteMsg(m)
+	}
+}
+
+func startServer(t *testing.T, network string) string {
+	t.Helper()
+
+	started := make(chan struct{})
+	srv := &dns.Server{Handler: testHandler(t), NotifyStartedFunc: func() { close(started) }}
+	switch network {
+	case "udp":
+		pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		srv.PacketConn = pc
+	default:
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		srv.Listener = l
+	}
+
+	go func() { _ = srv.ActivateAndServe() }()
+	t.Cleanup(func() { _ = srv.Shutdown() })
+	<-started
+
+	if srv.PacketConn != nil {
+		return srv.PacketConn.LocalAddr().String()
+	}
+	return srv.Listener.Addr().String()
+}
+
+// dohWriter adapts an HTTP response to dns.ResponseWriter.
+type dohWriter struct {
+	dns.ResponseWriter
+	w http.ResponseWriter
+}
+
+func (d *dohWriter) WriteMsg(m *dns.Msg) error {
+	packed, err := m.Pack()
+	if err != nil {
+		return err
+	}
+	d.w.Header().Set("Content-Type", dohMediaType)
+	_, err = d.w.Write(packed)
+	return err
+}
+
+func startDoHServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	handler := testHandler(t)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != dohMediaType {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		req := new(dns.Msg)
+		if err := req.Unpack(body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		handler(&dohWriter{w: w}, req)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func respondingDNSChecks(rcode string) validator.Validator {
+	return lookslike.MustCompile(map[string]interface{}{
+		"dns": map[string]interface{}{
+			"id":            isdef.KeyPresent,
+			"type":          "answer",
+			"op_code":       "QUERY",
+			"response_code": rcode,
+			"header_flags":  isdef.KeyPresent,
+			"question": map[string]interface{}{
+				"name":  isdef.IsNonEmptyString,
+				"type":  isdef.IsNonEmptyString,
+				"class": "IN",
+			},
+			"rtt.us": hbtestllext.IsInt64,
+		},
+	})
+}
+
+func TestUpQueries(t *testing.T) {
+	udpAddr := startServer(t, "udp")
+	tcpAddr := startServer(t, "tcp")
+
+	scenarios := []struct {
+		name   string
+		host   string
+		url    string
+		config mapstr.M
+		fields mapstr.M
+	}{
+		{
+			name:   "udp A",
+			host:   udpAddr,
+			url:    "udp://" + udpAddr,
+			config: mapstr.M{"check.answers": []string{"192.0.2.2"}},
+			fields: mapstr.M{
+				"dns.question.type": "A",
+				"dns.resolved_ip":   []string{"192.0.2.1", "192.0.2.2"},
+			},
+		},
+		{
+			name:   "tcp AAAA",
+			host:   "tcp://" + tcpAddr,
+			url:    "tcp://" + tcpAddr,
+			config: mapstr.M{"query.type": "aaaa", "check.answers": []string{"2001:db8:0::1"}},
+			fields: mapstr.M{"dns.resolved_ip": []string{"2001:db8::1"}},
+		},
+		{
+			name:   "MX",
+			host:   udpAddr,
+			url:    "udp://" + udpAddr,
+			config: mapstr.M{"query.type": "MX", "check.answers": []string{"10 MAIL.example.com."}},
+			fields: mapstr.M{"dns.answers": []mapstr.M{{
+				"name": "example.com", "type": "MX", "class": "IN", "ttl": uint32(300), "data": "10 mail.example.com",
+			}}},
+		},
+		{
+			name:   "TXT",
+			host:   udpAddr,
+			url:    "udp://" + udpAddr,
+			config: mapstr.M{"query.type": "TXT", "check.answers": []string{"v=spf1 -all"}},
+		},
+		{
+			name:   "SRV",
+			host:   udpAddr,
+			url:    "udp://" + udpAddr,
+			config: mapstr.M{"query.name": "_sip._tcp.example.com", "query.type": "SRV", "check.answers": []string{"10 60 5060 sip.example.com"}},
+		},
+		{
+			name:   "CNAME",
+			host:   udpAddr,
+			url:    "udp://" + udpAddr,
+			config: mapstr.M{"query.name": "www.example.com", "query.type": "CNAME", "check.answers": []string{"example.com"}},
+		},
+		{
+			name:   "DNSSEC",
+			host:   udpAddr,
+			url:    "udp://" + udpAddr,
+			config: mapstr.M{"dnssec": true},
+			fields: mapstr.M{"dns.header_flags": []string{"RD", "RA", "AD", "DO"}},
+		},
+		{
+			name:   "expected NXDOMAIN",
+			host:   udpAddr,
+			url:    "udp://" + udpAddr,
+			config: mapstr.M{"query.name": "missing.example.com", "check.rcode": "NXDOMAIN"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			config := mapstr.M{
+				"hosts":      s.host,
+				"query.name": "example.com",
+				"timeout":    "1s",
+			}
+			config.DeepUpdate(s.config)
+			event := hbtest.RunMonitorCheck(t, create, "dns", config)
+
+			u, err := url.Parse(s.url)
+			require.NoError(t, err)
+
+			rcode := "NOERROR"
+			if v, _ := config.GetValue("check.rcode"); v != nil {
+				rcode = v.(string)
+			}
+			validators := []validator.Validator{
+				hbtest.BaseChecks("", "up", "dns"),
+				hbtest.SummaryStateChecks(1, 0),
+				hbtest.URLChecks(t, u),
+				respondingDNSChecks(rcode),
+			}
+			if s.fields != nil {
+				validators = append(validators, lookslike.MustCompile(s.fields))
+			}
+
+			testslike.Test(t, lookslike.Compose(validators...), event.Fields)
+		})
+	}
+}
+
+func TestDownQueries(t *testing.T) {
+	udpAddr := startServer(t, "udp")
+
+	scenarios := []struct {
+		name   string
+		config mapstr.M
+		msg    string
+	}{
+		{
+			name:   "unexpected rcode",
+			config: mapstr.M{"query.name": "missing.example.com"},
+			msg:    "expected response code NOERROR, got NXDOMAIN",
+		},
+		{
+			name:   "missing answer",
+			config: mapstr.M{"check.answers": []string{"192.0.2.3"}},
+			msg:    "expected answer '192.0.2.3' not found",
+		},
+		{
+			name:   "no record of type",
+			config: mapstr.M{"query.type": "SOA"},
+			msg:    "no SOA record found for example.com",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			config := mapstr.M{
+				"hosts":      udpAddr,
+				"query.name": "example.com",
+				"timeout":    "1s",
+			}
+			config.DeepUpdate(s.config)
+			event := hbtest.RunMonitorCheck(t, create, "dns", config)
+
+			testslike.Test(t, lookslike.Compose(
+				hbtest.BaseChecks("", "down", "dns"),
+				hbtest.SummaryStateChecks(0, 1),
+				hbtest.ErrorChecks(s.msg, "validate"),
+				lookslike.MustCompile(map[string]interface{}{"dns.rtt.us": hbtestllext.IsInt64}),
+			), event.Fields)
+		})
+	}
+}
+
+func TestUnreachableServer(t *testing.T) {
+	// grab a free port and close it so nothing is listening there
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	event := hbtest.RunMonitorCheck(t, create, "dns", mapstr.M{
+		"hosts":      "tcp://" + addr,
+		"query.name": "example.com",
+		"timeout":    "1s",
+	})
+
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("", "down", "dns"),
+		hbtest.SummaryStateChecks(0, 1),
+		hbtest.ErrorChecks("connection refused", "io"),
+	), event.Fields)
+}
+
+func TestDoT(t *testing.T) {
+	// borrow the self-signed certificate httptest issues for 127.0.0.1
+	hs := httptest.NewTLSServer(http.NotFoundHandler())
+	defer hs.Close()
+
+	certFile := hbtest.CertToTempFile(t, hs.Certificate())
+	defer os.Remove(certFile.Name())
+
+	started := make(chan struct{})
+	srv := &dns.Server{
+		Handler:           testHandler(t),
+		NotifyStartedFunc: func() { close(started) },
+	}
+	l, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: hs.TLS.Certificates,
+		MinVersion:   tls.VersionTLS12,
+	})
+	require.NoError(t, err)
+	srv.Listener = l
+	go func() { _ = srv.ActivateAndServe() }()
+	t.Cleanup(func() { _ = srv.Shutdown() })
+	<-started
+
+	addr := l.Addr().String()
+	event := hbtest.RunMonitorCheck(t, create, "dns", mapstr.M{
+		"hosts":                       "tls://" + addr,
+		"query.name":                  "example.com",
+		"timeout":                     "1s",
+		"ssl.certificate_authorities": certFile.Name(),
+	})
+
+	u, err := url.Parse("tls://" + addr)
+	require.NoError(t, err)
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("", "up", "dns"),
+		hbtest.SummaryStateChecks(1, 0),
+		hbtest.URLChecks(t, u),
+		respondingDNSChecks("NOERROR"),
+	), event.Fields)
+}
+
+func TestDoH(t *testing.T) {
+	srv := startDoHServer(t)
+
+	certFile := hbtest.CertToTempFile(t, srv.Certificate())
+	defer os.Remove(certFile.Name())
+
+	event := hbtest.RunMonitorCheck(t, create, "dns", mapstr.M{
+		"hosts":                       srv.URL,
+		"query.name":                  "example.com",
+		"timeout":                     "1s",
+		"ssl.certificate_authorities": certFile.Name(),
+		"check.answers":               []string{"192.0.2.1"},
+	})
+
+	u, err := url.Parse(srv.URL + "/dns-query")
+	require.NoError(t, err)
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("", "up", "dns"),
+		hbtest.SummaryStateChecks(1, 0),
+		hbtest.URLChecks(t, u),
+		respondingDNSChecks("NOERROR"),
+	), event.Fields)
+}
+
+func TestParseServer(t *testing.T) {
+	for host, want := range map[string]string{
+		"8.8.8.8":                      "udp://8.8.8.8:53",
+		"8.8.8.8:5353":                 "udp://8.8.8.8:5353",
+		"::1":                          "udp://[::1]:53",
+		"[::1]:5353":                   "udp://[::1]:5353",
+		"ns1.example.com":              "udp://ns1.example.com:53",
+		"tcp://8.8.8.8":                "tcp://8.8.8.8:53",
+		"tls://dns.google":             "tls://dns.google:853",
+		"https://dns.google":           "https://dns.google/dns-query",
+		"https://cloudflare-dns.com/q": "https://cloudflare-dns.com/q",
+	} {
+		u, err := parseServer(host)
+		require.NoError(t, err, host)
+		require.Equal(t, want, u.String(), host)
+	}
+
+	_, err := parseServer("http://dns.google")
+	require.ErrorContains(t, err, "unsupported dns server scheme")
+}
+
+func TestConfigValidate(t *testing.T) {
+	for name, c := range map[string]mapstr.M{
+		"unsupported type": {"query.type": "AXFR"},
+		"unknown type":     {"query.type": "NOPE"},
+		"unknown rcode":    {"check.rcode": "BROKEN"},
+		"missing name":     {"query.name": ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := mapstr.M{"hosts": "127.0.0.1", "query.name": "example.com"}
+			config.DeepUpdate(c)
+			cfg, err := conf.NewConfigFrom(config)
+			require.NoError(t, err)
+
+			_, err = create("dns", cfg)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dns
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"github.com/elastic/elastic-agent-libs/transport/tlscommon"
+)
+
+// dohMediaType is the wire format media type defined by RFC 8484.
+const dohMediaType = "application/dns-message"
+
+// exchanger sends a query to a single server and returns its response.
+type exchanger interface {
+	exchange(m *dns.Msg) (*dns.Msg, error)
+	String() string
+}
+
+func (jf *jobFactory) newExchanger(server *url.URL) exchanger {
+	tlsConfig := jf.tlsConfig
+	timeout := jf.config.Timeout
+
+	switch server.Scheme {
+	case "https":
+		return newDoHExchanger(server, tlsConfig, timeout)
+	case "tls":
+		return &clientExchanger{
+			addr: server.Host,
+			client: &dns.Client{
+				Net:       "tcp-tls",
+				Timeout:   timeout,
+				TLSConfig: tlsConfig.BuildModuleClientConfig(server.Hostname()),
+			},
+		}
+	case "tcp":
+		return &clientExchanger{
+			addr:   server.Host,
+			client: &dns.Client{Net: "tcp", Timeout: timeout},
+		}
+	default:
+		return &clientExchanger{
+			addr:   server.Host,
+			client: &dns.Client{Net: "udp", Timeout: timeout},
+			// truncated UDP responses are retried over TCP, as a stub
+			// resolver would do.
+			fallback: &dns.Client{Net: "tcp", Timeout: timeout},
+		}
+	}
+}
+
+// clientExchanger queries servers over UDP, TCP or TLS (DoT).
+type clientExchanger struct {
+	addr     string
+	client   *dns.Client
+	fallback *dns.Client
+}
+
+func (c *clientExchanger) exchange(m *dns.Msg) (*dns.Msg, error) {
+	r, _, err := c.client.Exchange(m, c.addr)
+	if err != nil {
+		return nil, err
+	}
+	if r.Truncated && c.fallback != nil {
+		debugf("truncated response from %s, retrying over tcp", c.addr)
+		r, _, err = c.fallback.Exchange(m, c.addr)
+	}
+	return r, err
+}
+
+func (c *clientExchanger) String() string {
+	return c.client.Net + "://" + c.addr
+}
+
+// dohExchanger queries servers using DNS over HTTPS (RFC 8484).
+type dohExchanger struct {
+	url    string
+	client *http.Client
+}
+
+func newDoHExchanger(server *url.URL, tlsConfig *tlscommon.TLSConfig, timeout time.Duration) *dohExchanger {
+	return &dohExchanger{
+		url: server.String(),
+		client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: tlsConfig.BuildModuleClientConfig(server.Hostname()),
+				// every check should measure a full connection setup
+				DisableKeepAlives: true,
+			},
+		},
+	}
+}
+
+func (d *dohExchanger) exchange(m *dns.Msg) (*dns.Msg, error) {
+	// RFC 8484 recommends a zero ID to make responses cache friendly.
+	q := m.Copy()
+	q.Id = 0
+
+	packed, err := q.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, d.url, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", dohMediaType)
+	req.Header.Set("Accept", dohMediaType)
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+
+	// DNS messages are at most 64KiB.
+	body, err := io.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > dns.MaxMsgSize {
+		return nil, errors.New("response exceeds the maximum DNS message size")
+	}
+
+	r := new(dns.Msg)
+	if err := r.Unpack(body); err != nil {
+		return nil, fmt.Errorf("invalid DNS response: %w", err)
+	}
+	if r.Id != q.Id {
+		return nil, dns.ErrId
+	}
+	return r, nil
+}
+
+func (d *dohExchanger) String() string {
+	return d.url
+}
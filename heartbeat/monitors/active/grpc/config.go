@@ -0,0 +1,60 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"errors"
+	"time"
+
+	"github.com/elastic/elastic-agent-libs/transport/tlscommon"
+)
+
+type config struct {
+	// Hosts are the servers to check, as `host:port`, `grpc://host:port`
+	// or `grpcs://host:port`.
+	Hosts []string `config:"hosts" validate:"required"`
+
+	// Services are the service names sent in the health check request. The
+	// empty name checks the overall health of the server.
+	Services []string `config:"services"`
+
+	// Metadata is sent as request headers with every check.
+	Metadata map[string]string `config:"metadata"`
+
+	// configure tls
+	TLS *tlscommon.Config `config:"ssl"`
+
+	Timeout time.Duration `config:"timeout"`
+}
+
+func defaultConfig() config {
+	return config{
+		Services: []string{""},
+		Timeout:  16 * time.Second,
+	}
+}
+
+func (c *config) Validate() error {
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be greater than 0")
+	}
+	if len(c.Services) == 0 {
+		c.Services = []string{""}
+	}
+	return nil
+}
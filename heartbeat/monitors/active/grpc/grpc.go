@@ -0,0 +1,208 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/elastic/beats/v7/heartbeat/eventext"
+	"github.com/elastic/beats/v7/heartbeat/look"
+	"github.com/elastic/beats/v7/heartbeat/monitors/jobs"
+	"github.com/elastic/beats/v7/heartbeat/monitors/plugin"
+	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers/wraputil"
+	"github.com/elastic/beats/v7/heartbeat/reason"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/elastic-agent-libs/transport/tlscommon"
+)
+
+func init() {
+	plugin.Register("grpc", create, "synthetics/grpc")
+}
+
+var debugf = logp.MakeDebug("grpc")
+
+func create(
+	name string,
+	cfg *conf.C,
+) (p plugin.Plugin, err error) {
+	jf, err := newJobFactory(cfg)
+	if err != nil {
+		return plugin.Plugin{}, err
+	}
+
+	js := jf.makeJobs()
+	return plugin.Plugin{Jobs: js, Endpoints: len(js)}, nil
+}
+
+// jobFactory holds the parsed configuration shared by the jobs of every
+// host and service configured on a single monitor.
+type jobFactory struct {
+	config    config
+	tlsConfig *tlscommon.TLSConfig
+	endpoints []*url.URL
+}
+
+func newJobFactory(commonCfg *conf.C) (*jobFactory, error) {
+	jf := &jobFactory{config: defaultConfig()}
+	if err := commonCfg.Unpack(&jf.config); err != nil {
+		return nil, err
+	}
+
+	var err error
+	jf.tlsConfig, err = tlscommon.LoadTLSConfig(jf.config.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	defaultScheme := "grpc"
+	if jf.tlsConfig != nil {
+		defaultScheme = "grpcs"
+	}
+
+	for _, host := range jf.config.Hosts {
+		u, err := parseEndpoint(host, defaultScheme)
+		if err != nil {
+			return nil, err
+		}
+		jf.endpoints = append(jf.endpoints, u)
+	}
+
+	return jf, nil
+}
+
+// makeJobs returns one job per host and service.
+func (jf *jobFactory) makeJobs() []jobs.Job {
+	var js []jobs.Job
+	for _, endpoint := range jf.endpoints {
+		for _, service := range jf.config.Services {
+			js = append(js, wraputil.WithURLField(endpoint, jobs.MakeSimpleJob(func(event *beat.Event) error {
+				return jf.check(event, endpoint, service)
+			})))
+		}
+	}
+	return js
+}
+
+// check calls grpc.health.v1.Health/Check for service on the server at
+// endpoint.
+func (jf *jobFactory) check(event *beat.Event, endpoint *url.URL, service string) error {
+	creds := insecure.NewCredentials()
+	if endpoint.Scheme == "grpcs" {
+		creds = credentials.NewTLS(jf.tlsConfig.BuildModuleClientConfig(endpoint.Hostname()))
+	}
+
+	conn, err := grpclib.NewClient("passthrough:///"+endpoint.Host, grpclib.WithTransportCredentials(creds))
+	if err != nil {
+		return reason.IOFailed(err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), jf.config.Timeout)
+	defer cancel()
+	if len(jf.config.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(jf.config.Metadata))
+	}
+
+	start := time.Now()
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	rtt := time.Since(start)
+
+	fields := mapstr.M{
+		"rtt":         look.RTT(rtt),
+		"status_code": status.Code(err).String(),
+	}
+	if service != "" {
+		fields["service"] = service
+	}
+	if resp != nil {
+		fields["status"] = resp.GetStatus().String()
+	}
+	eventext.MergeEventFields(event, mapstr.M{"grpc": fields})
+
+	if err != nil {
+		debugf("health check of %s failed with: %v", endpoint, err)
+		return checkError(err, service)
+	}
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return reason.MakeValidateError(fmt.Errorf("health check reported %s", resp.GetStatus()))
+	}
+	return nil
+}
+
+// checkError classifies RPC failures. Errors raised before the server
+// could answer are IO failures, anything the server replied with fails
+// validation.
+func checkError(err error, service string) reason.Reason {
+	st := status.Convert(err)
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return reason.IOFailed(err)
+	case codes.NotFound:
+		return reason.MakeValidateError(fmt.Errorf("service '%s' is unknown to the server", service))
+	case codes.Unimplemented:
+		return reason.MakeValidateError(fmt.Errorf("server does not implement the health service: %s", st.Message()))
+	default:
+		return reason.MakeValidateError(err)
+	}
+}
+
+// parseEndpoint turns a configured host into a URL. Hosts without a scheme
+// use defaultScheme.
+func parseEndpoint(host, defaultScheme string) (*url.URL, error) {
+	if !strings.Contains(host, "://") {
+		host = defaultScheme + "://" + host
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid grpc host '%s': %w", host, err)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid grpc host '%s': missing host", host)
+	}
+
+	switch u.Scheme {
+	case "grpc":
+		if u.Port() == "" {
+			return nil, fmt.Errorf("invalid grpc host '%s': missing port", host)
+		}
+	case "grpcs":
+		if u.Port() == "" {
+			u.Host = net.JoinHostPort(u.Hostname(), "443")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported grpc host scheme '%s' in '%s'", u.Scheme, host)
+	}
+	return u, nil
+}
@@ -0,0 +1,280 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	grpclib "google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/go-lookslike"
+	"github.com/elastic/go-lookslike/isdef"
+	"github.com/elastic/go-lookslike/testslike"
+	"github.com/elastic/go-lookslike/validator"
+
+	"github.com/elastic/beats/v7/heartbeat/hbtest"
+	"github.com/elastic/beats/v7/heartbeat/hbtestllext"
+)
+
+// startServer runs a gRPC server with the standard health service, where
+// "ok" is SERVING and "broken" is NOT_SERVING. Incoming metadata is sent
+// to md when not nil.
+func startServer(t *testing.T, registerHealth bool, md chan<- metadata.MD, opts ...grpclib.ServerOption) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	opts = append(opts, grpclib.UnaryInterceptor(func(ctx context.Context, req interface{}, info *grpclib.UnaryServerInfo, handler grpclib.UnaryHandler) (interface{}, error) {
+		if md != nil {
+			in, _ := metadata.FromIncomingContext(ctx)
+			md <- in
+		}
+		return handler(ctx, req)
+	}))
+	srv := grpclib.NewServer(opts...)
+	if registerHealth {
+		hs := health.NewServer()
+		hs.SetServingStatus("ok", healthpb.HealthCheckResponse_SERVING)
+		hs.SetServingStatus("broken", healthpb.HealthCheckResponse_NOT_SERVING)
+		healthpb.RegisterHealthServer(srv, hs)
+	}
+
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(srv.Stop)
+
+	return l.Addr().String()
+}
+
+func respondingGRPCChecks(code string) validator.Validator {
+	return lookslike.MustCompile(map[string]interface{}{
+		"grpc.rtt.us":      hbtestllext.IsInt64,
+		"grpc.status_code": code,
+		"grpc.service":     isdef.Optional(isdef.IsNonEmptyString),
+		"grpc.status":      isdef.Optional(isdef.IsNonEmptyString),
+	})
+}
+
+func TestHealthCheck(t *testing.T) {
+	addr := startServer(t, true, nil)
+	u, err := url.Parse("grpc://" + addr)
+	require.NoError(t, err)
+
+	scenarios := []struct {
+		name    string
+		service string
+		status  string
+		code    string
+		check   validator.Validator
+	}{
+		{
+			name:   "server",
+			status: "SERVING",
+			code:   "OK",
+			check:  hbtest.SummaryStateChecks(1, 0),
+		},
+		{
+			name:    "serving service",
+			service: "ok",
+			status:  "SERVING",
+			code:    "OK",
+			check:   hbtest.SummaryStateChecks(1, 0),
+		},
+		{
+			name:    "not serving service",
+			service: "broken",
+			status:  "NOT_SERVING",
+			code:    "OK",
+			check: lookslike.Compose(
+				hbtest.SummaryStateChecks(0, 1),
+				hbtest.ErrorChecks("health check reported NOT_SERVING", "validate"),
+			),
+		},
+		{
+			name:    "unknown service",
+			service: "missing",
+			code:    "NotFound",
+			check: lookslike.Compose(
+				hbtest.SummaryStateChecks(0, 1),
+				hbtest.ErrorChecks("service 'missing' is unknown to the server", "validate"),
+			),
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			config := mapstr.M{"hosts": addr, "timeout": "1s"}
+			if s.service != "" {
+				config["services"] = []string{s.service}
+			}
+			event := hbtest.RunMonitorCheck(t, create, "grpc", config)
+
+			status := "up"
+			if s.status != "SERVING" {
+				status = "down"
+			}
+			fields := mapstr.M{}
+			if s.status != "" {
+				fields["grpc.status"] = s.status
+			}
+			if s.service != "" {
+				fields["grpc.service"] = s.service
+			}
+
+			testslike.Test(t, lookslike.Compose(
+				hbtest.BaseChecks("", status, "grpc"),
+				hbtest.URLChecks(t, u),
+				respondingGRPCChecks(s.code),
+				lookslike.MustCompile(fields),
+				s.check,
+			), event.Fields)
+		})
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	md := make(chan metadata.MD, 1)
+	addr := startServer(t, true, md)
+
+	event := hbtest.RunMonitorCheck(t, create, "grpc", mapstr.M{
+		"hosts":    addr,
+		"metadata": mapstr.M{"Authorization": "Bearer secret"},
+		"timeout":  "1s",
+	})
+
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("", "up", "grpc"),
+		hbtest.SummaryStateChecks(1, 0),
+	), event.Fields)
+	require.Equal(t, []string{"Bearer secret"}, (<-md).Get("authorization"))
+}
+
+func TestTLS(t *testing.T) {
+	// borrow the self-signed certificate httptest issues for 127.0.0.1
+	hs := httptest.NewTLSServer(http.NotFoundHandler())
+	defer hs.Close()
+
+	certFile := hbtest.CertToTempFile(t, hs.Certificate())
+	defer os.Remove(certFile.Name())
+
+	creds := credentials.NewTLS(&tls.Config{
+		Certificates: hs.TLS.Certificates,
+		MinVersion:   tls.VersionTLS12,
+	})
+	addr := startServer(t, true, nil, grpclib.Creds(creds))
+
+	event := hbtest.RunMonitorCheck(t, create, "grpc", mapstr.M{
+		"hosts":                       addr,
+		"ssl.certificate_authorities": certFile.Name(),
+		"timeout":                     "1s",
+	})
+
+	u, err := url.Parse("grpcs://" + addr)
+	require.NoError(t, err)
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("", "up", "grpc"),
+		hbtest.SummaryStateChecks(1, 0),
+		hbtest.URLChecks(t, u),
+		respondingGRPCChecks("OK"),
+	), event.Fields)
+
+	// a plaintext client cannot talk to a TLS server
+	event = hbtest.RunMonitorCheck(t, create, "grpc", mapstr.M{
+		"hosts":   "grpc://" + addr,
+		"timeout": "1s",
+	})
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("", "down", "grpc"),
+		hbtest.SummaryStateChecks(0, 1),
+		hbtest.ErrorChecks("", "io"),
+	), event.Fields)
+}
+
+func TestUnimplemented(t *testing.T) {
+	addr := startServer(t, false, nil)
+
+	event := hbtest.RunMonitorCheck(t, create, "grpc", mapstr.M{"hosts": addr, "timeout": "1s"})
+
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("", "down", "grpc"),
+		hbtest.SummaryStateChecks(0, 1),
+		hbtest.ErrorChecks("server does not implement the health service", "validate"),
+		respondingGRPCChecks("Unimplemented"),
+	), event.Fields)
+}
+
+func TestUnreachableServer(t *testing.T) {
+	// grab a free port and close it so nothing is listening there
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	event := hbtest.RunMonitorCheck(t, create, "grpc", mapstr.M{"hosts": addr, "timeout": "1s"})
+
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("", "down", "grpc"),
+		hbtest.SummaryStateChecks(0, 1),
+		hbtest.ErrorChecks("connection refused", "io"),
+		respondingGRPCChecks("Unavailable"),
+	), event.Fields)
+}
+
+func TestEndpoints(t *testing.T) {
+	config, err := conf.NewConfigFrom(mapstr.M{
+		"hosts":    []string{"127.0.0.1:50051", "grpcs://svc.example.com"},
+		"services": []string{"a", "b"},
+	})
+	require.NoError(t, err)
+
+	p, err := create("grpc", config)
+	require.NoError(t, err)
+	require.Equal(t, 4, p.Endpoints)
+	require.Len(t, p.Jobs, 4)
+}
+
+func TestParseEndpoint(t *testing.T) {
+	for host, want := range map[string]string{
+		"127.0.0.1:50051":         "grpc://127.0.0.1:50051",
+		"grpc://[::1]:50051":      "grpc://[::1]:50051",
+		"grpcs://svc.example.com": "grpcs://svc.example.com:443",
+	} {
+		u, err := parseEndpoint(host, "grpc")
+		require.NoError(t, err, host)
+		require.Equal(t, want, u.String(), host)
+	}
+
+	for _, host := range []string{"svc.example.com", "http://svc.example.com:80"} {
+		_, err := parseEndpoint(host, "grpc")
+		require.Error(t, err, host)
+	}
+}
@@ -137,10 +137,14 @@ func (r *responseConfig) Validate() error {
 
 // Validate validates of the requestParameters object is valid or not
 func (r *requestParameters) Validate() error {
-	switch strings.ToUpper(r.Method) {
+	return validateMethod(r.Method)
+}
+
+func validateMethod(method string) error {
+	switch strings.ToUpper(method) {
 	case http.MethodOptions, http.MethodHead, http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodConnect, http.MethodTrace:
 	default:
-		return fmt.Errorf("HTTP method '%v' not supported", r.Method)
+		return fmt.Errorf("HTTP method '%v' not supported", method)
 	}
 
 	return nil
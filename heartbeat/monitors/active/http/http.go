@@ -81,7 +81,7 @@ func create(
 	// we execute DNS resolution requests inline with the request, not running them as a separate job, and not returning
 	// separate DNS rtt data.
 	if (config.Transport.Proxy.URL != nil && !config.Transport.Proxy.Disable) || config.MaxRedirects > 0 {
-		transport, err := newRoundTripper(&config)
+		transport, err := newRoundTripper(&config.Transport)
 		if err != nil {
 			return plugin.Plugin{}, err
 		}
@@ -122,8 +122,8 @@ func create(
 	return plugin.Plugin{Jobs: js, Endpoints: len(config.Hosts)}, nil
 }
 
-func newRoundTripper(config *Config) (http.RoundTripper, error) {
-	return config.Transport.RoundTripper(
+func newRoundTripper(settings *httpcommon.HTTPTransportSettings) (http.RoundTripper, error) {
+	return settings.RoundTripper(
 		httpcommon.WithAPMHTTPInstrumentation(),
 		httpcommon.WithoutProxyEnvironmentVariables(),
 		httpcommon.WithKeepaliveSettings{
@@ -0,0 +1,342 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PaesslerAG/gval"
+	"github.com/PaesslerAG/jsonpath"
+
+	"github.com/elastic/beats/v7/heartbeat/eventext"
+	"github.com/elastic/beats/v7/heartbeat/look"
+	"github.com/elastic/beats/v7/heartbeat/monitors/jobs"
+	"github.com/elastic/beats/v7/heartbeat/monitors/plugin"
+	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers/wraputil"
+	"github.com/elastic/beats/v7/heartbeat/reason"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+func init() {
+	plugin.Register("http_journey", createJourney, "synthetics/http_journey")
+}
+
+// variableRef matches `{{name}}` references to journey variables. The
+// `${name}` syntax is not used since the config loader already resolves it.
+var variableRef = regexp.MustCompile(`\{\{\s*([\w.-]+)\s*\}\}`)
+
+// journey runs the steps of an http_journey monitor in order, sharing
+// variables and cookies between the steps of a single check.
+type journey struct {
+	config    *journeyConfig
+	transport http.RoundTripper
+	steps     []compiledStep
+}
+
+type compiledStep struct {
+	journeyStep
+	validator  multiValidator
+	extractors []extractor
+}
+
+// extractor returns the value of a variable from a step response.
+type extractor struct {
+	name    string
+	extract func(resp *http.Response, body string) (string, error)
+}
+
+// createJourney makes a new http_journey monitor
+func createJourney(
+	name string,
+	cfg *conf.C,
+) (p plugin.Plugin, err error) {
+	config := defaultJourneyConfig()
+	if err := cfg.Unpack(&config); err != nil {
+		return plugin.Plugin{}, err
+	}
+
+	j := &journey{config: &config}
+	for _, step := range config.Steps {
+		cs, err := compileStep(step)
+		if err != nil {
+			return plugin.Plugin{}, fmt.Errorf("step '%s': %w", step.Name, err)
+		}
+		j.steps = append(j.steps, cs)
+	}
+
+	j.transport, err = newRoundTripper(&config.Transport)
+	if err != nil {
+		return plugin.Plugin{}, err
+	}
+
+	return plugin.Plugin{Jobs: []jobs.Job{jobs.MakeSimpleJob(j.run)}, Endpoints: 1}, nil
+}
+
+func compileStep(step journeyStep) (compiledStep, error) {
+	validator, err := makeValidateResponse(&step.Check.Response)
+	if err != nil {
+		return compiledStep{}, err
+	}
+
+	cs := compiledStep{journeyStep: step, validator: validator}
+	for _, e := range step.Extract {
+		ex, err := makeExtractor(e)
+		if err != nil {
+			return compiledStep{}, err
+		}
+		cs.extractors = append(cs.extractors, ex)
+	}
+	return cs, nil
+}
+
+func makeExtractor(e stepExtraction) (extractor, error) {
+	if e.JSON != "" {
+		eval, err := jsonpath.New(e.JSON)
+		if err != nil {
+			return extractor{}, fmt.Errorf("could not compile JSONPath '%s': %w", e.JSON, err)
+		}
+		return extractor{name: e.Var, extract: extractJSON(eval)}, nil
+	}
+
+	var re *regexp.Regexp
+	if e.Regex != "" {
+		// checked by stepExtraction.Validate
+		re = regexp.MustCompile(e.Regex)
+	}
+	header := e.Header
+	return extractor{name: e.Var, extract: func(resp *http.Response, body string) (string, error) {
+		value := body
+		if header != "" {
+			values := resp.Header.Values(header)
+			if len(values) == 0 {
+				return "", fmt.Errorf("header '%s' not found", header)
+			}
+			value = strings.Join(values, ", ")
+		}
+		if re == nil {
+			return value, nil
+		}
+
+		m := re.FindStringSubmatch(value)
+		switch {
+		case m == nil:
+			return "", fmt.Errorf("regex '%s' did not match", re)
+		case len(m) > 1:
+			return m[1], nil
+		default:
+			return m[0], nil
+		}
+	}}, nil
+}
+
+func extractJSON(eval gval.Evaluable) func(*http.Response, string) (string, error) {
+	return func(_ *http.Response, body string) (string, error) {
+		decoded, err := decodeJson(body, false)
+		if err != nil {
+			return "", err
+		}
+		v, err := eval(context.Background(), decoded)
+		if err != nil {
+			return "", err
+		}
+
+		switch v := v.(type) {
+		case string:
+			return v, nil
+		case float64:
+			return strconv.FormatFloat(v, 'f', -1, 64), nil
+		case nil:
+			return "", fmt.Errorf("value is null")
+		default:
+			b, err := json.Marshal(v)
+			return string(b), err
+		}
+	}
+}
+
+// run executes all steps, stopping at the first failure.
+func (j *journey) run(event *beat.Event) error {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{
+		CheckRedirect: makeCheckRedirect(j.config.MaxRedirects, nil),
+		Transport:     j.transport,
+		Timeout:       j.config.Transport.Timeout,
+		Jar:           jar,
+	}
+
+	vars := maps.Clone(j.config.Variables)
+	if vars == nil {
+		vars = map[string]string{}
+	}
+
+	start := time.Now()
+	steps := make([]mapstr.M, 0, len(j.steps))
+	var (
+		errReason error
+		stepEvent *beat.Event
+		lastURL   *url.URL
+	)
+	for i, step := range j.steps {
+		var stepFields mapstr.M
+		stepFields, stepEvent, lastURL, errReason = j.runStep(client, i, step, vars)
+		steps = append(steps, stepFields)
+		if errReason != nil {
+			break
+		}
+	}
+
+	fields := mapstr.M{
+		"steps":    steps,
+		"duration": look.RTT(time.Since(start)),
+	}
+	if errReason != nil {
+		fields["failed_step"] = j.steps[len(steps)-1].Name
+	}
+	eventext.MergeEventFields(event, mapstr.M{"http_journey": fields})
+
+	// report the response of the last step that ran, which is the failing
+	// one when the check is down.
+	if stepEvent != nil {
+		eventext.MergeEventFields(event, stepEvent.Fields)
+	}
+	if lastURL != nil {
+		eventext.MergeEventFields(event, mapstr.M{"url": wraputil.URLFields(lastURL)})
+	}
+
+	return errReason
+}
+
+// runStep executes a single step, storing extracted values in vars. The
+// step response fields are collected in a separate event so steps don't
+// mix their headers.
+func (j *journey) runStep(
+	client *http.Client,
+	index int,
+	step compiledStep,
+	vars map[string]string,
+) (fields mapstr.M, stepEvent *beat.Event, u *url.URL, err error) {
+	fields = mapstr.M{
+		"index":  index,
+		"name":   step.Name,
+		"status": "failed",
+	}
+
+	req, body, err := step.buildRequest(vars)
+	if err != nil {
+		return fields, nil, nil, reason.ValidateFailed(err)
+	}
+	fields["url"] = req.URL.Redacted()
+
+	validator := step.validator
+	if len(step.extractors) > 0 {
+		validator.bodyValidators = append(slices.Clone(validator.bodyValidators), extractInto(step.extractors, vars))
+	}
+
+	stepEvent = &beat.Event{Fields: mapstr.M{}}
+	start := time.Now()
+	_, err = execPing(stepEvent, client, req, body, j.config.Transport.Timeout, validator, j.config.Response)
+	fields["duration"] = look.RTT(time.Since(start))
+
+	if code, _ := stepEvent.GetValue("http.response.status_code"); code != nil {
+		fields["status_code"] = code
+	}
+	if err == nil {
+		fields["status"] = "succeeded"
+	}
+	return fields, stepEvent, req.URL, err
+}
+
+// extractInto returns a body validator running the extractors of a step,
+// so extraction only happens once the step checks passed.
+func extractInto(extractors []extractor, vars map[string]string) bodyValidator {
+	return func(resp *http.Response, body string) error {
+		for _, e := range extractors {
+			v, err := e.extract(resp, body)
+			if err != nil {
+				return fmt.Errorf("could not extract '%s': %w", e.name, err)
+			}
+			vars[e.name] = v
+		}
+		return nil
+	}
+}
+
+func (s *compiledStep) buildRequest(vars map[string]string) (*http.Request, []byte, error) {
+	rawURL, err := expandVariables(s.Request.URL, vars)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := http.NewRequestWithContext(context.TODO(), strings.ToUpper(s.Request.Method), rawURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Close = true
+
+	for k, v := range s.Request.Headers {
+		v, err := expandVariables(v, vars)
+		if err != nil {
+			return nil, nil, err
+		}
+		// defining the Host header isn't enough. See https://github.com/golang/go/issues/7682
+		if k == "Host" {
+			req.Host = v
+		}
+		req.Header.Add(k, v)
+	}
+
+	body, err := expandVariables(s.Request.Body, vars)
+	if err != nil {
+		return nil, nil, err
+	}
+	return req, []byte(body), nil
+}
+
+// expandVariables replaces `{{name}}` references in s, failing on
+// undefined variables.
+func expandVariables(s string, vars map[string]string) (string, error) {
+	var missing string
+	expanded := variableRef.ReplaceAllStringFunc(s, func(ref string) string {
+		name := variableRef.FindStringSubmatch(ref)[1]
+		v, ok := vars[name]
+		if !ok && missing == "" {
+			missing = name
+		}
+		return v
+	})
+	if missing != "" {
+		return "", fmt.Errorf("undefined variable '%s'", missing)
+	}
+	return expanded, nil
+}
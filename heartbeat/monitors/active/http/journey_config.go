@@ -0,0 +1,114 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package http
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/elastic/elastic-agent-libs/transport/httpcommon"
+)
+
+// journeyConfig configures an http_journey monitor, running an ordered list
+// of requests in a single check.
+type journeyConfig struct {
+	Steps        []journeyStep     `config:"steps" validate:"required"`
+	Variables    map[string]string `config:"variables"`
+	MaxRedirects int               `config:"max_redirects"`
+	Response     responseConfig    `config:"response"`
+
+	Transport httpcommon.HTTPTransportSettings `config:",inline"`
+}
+
+type journeyStep struct {
+	Name    string           `config:"name" validate:"required"`
+	Request stepRequest      `config:"request"`
+	Check   stepCheck        `config:"check"`
+	Extract []stepExtraction `config:"extract"`
+}
+
+type stepRequest struct {
+	Method  string            `config:"method"`
+	URL     string            `config:"url" validate:"required"`
+	Headers map[string]string `config:"headers"`
+	Body    string            `config:"body"`
+}
+
+type stepCheck struct {
+	Response responseParameters `config:"response"`
+}
+
+// stepExtraction stores a value of the step response in a variable.
+// JSON extracts from the body with a JSONPath expression, Header from a
+// response header. Regex applies to the header value if Header is set, to
+// the body otherwise, and keeps the first capture group if there is one.
+type stepExtraction struct {
+	Var    string `config:"var" validate:"required"`
+	JSON   string `config:"json"`
+	Header string `config:"header"`
+	Regex  string `config:"regex"`
+}
+
+func defaultJourneyConfig() journeyConfig {
+	cfg := journeyConfig{
+		Response: responseConfig{
+			IncludeBody:         "on_error",
+			IncludeBodyMaxBytes: 2048,
+			IncludeHeaders:      true,
+		},
+		Transport: httpcommon.DefaultHTTPTransportSettings(),
+	}
+	cfg.Transport.Timeout = 16 * time.Second
+
+	return cfg
+}
+
+// Validate validates of the stepRequest object is valid or not
+func (r *stepRequest) Validate() error {
+	if r.Method == "" {
+		r.Method = "GET"
+	}
+	return validateMethod(r.Method)
+}
+
+// Validate validates of the stepExtraction object is valid or not
+func (e *stepExtraction) Validate() error {
+	switch {
+	case e.JSON != "" && (e.Header != "" || e.Regex != ""):
+		return fmt.Errorf("extraction of '%s' cannot combine json with header or regex", e.Var)
+	case e.JSON == "" && e.Header == "" && e.Regex == "":
+		return fmt.Errorf("extraction of '%s' requires one of json, header or regex", e.Var)
+	}
+
+	if e.Regex != "" {
+		if _, err := regexp.Compile(e.Regex); err != nil {
+			return fmt.Errorf("invalid regex for '%s': %w", e.Var, err)
+		}
+	}
+	return nil
+}
+
+// Validate validates of the journeyConfig object is valid or not
+func (c *journeyConfig) Validate() error {
+	if len(c.Steps) == 0 {
+		return errors.New("at least one step is required")
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/go-lookslike"
+	"github.com/elastic/go-lookslike/isdef"
+	"github.com/elastic/go-lookslike/testslike"
+	"github.com/elastic/go-lookslike/validator"
+
+	"github.com/elastic/beats/v7/heartbeat/ecserr"
+	"github.com/elastic/beats/v7/heartbeat/hbtest"
+	"github.com/elastic/beats/v7/heartbeat/hbtestllext"
+	"github.com/elastic/beats/v7/heartbeat/monitors/stdfields"
+	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers"
+	"github.com/elastic/beats/v7/heartbeat/scheduler/schedule"
+	"github.com/elastic/beats/v7/libbeat/beat"
+)
+
+// journeyServer serves a small API: POST /login returns a token and sets a
+// session cookie, GET /me requires both and returns the user.
+func journeyServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		var creds struct{ User string }
+		if err := json.NewDecoder(r.Body).Decode(&creds); err != nil || creds.User != "alice" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3ss10n"})
+		w.Header().Set("X-Request-Id", "req-17")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"token":"t0k3n","expires_in":3600}`)
+	})
+	mux.HandleFunc("GET /me", func(w http.ResponseWriter, r *http.Request) {
+		c, err := r.Cookie("session")
+		if err != nil || c.Value != "s3ss10n" || r.Header.Get("Authorization") != "Bearer t0k3n" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"name":"alice","id":42,"request":"`+r.URL.Query().Get("request")+`"}`)
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func runJourney(t *testing.T, configMap mapstr.M) *beat.Event {
+	t.Helper()
+
+	config, err := conf.NewConfigFrom(configMap)
+	require.NoError(t, err)
+
+	p, err := createJourney("http_journey", config)
+	require.NoError(t, err)
+	require.Equal(t, 1, p.Endpoints)
+
+	sched := schedule.MustParse("@every 1s")
+	job := wrappers.WrapCommon(p.Jobs, stdfields.StdMonitorFields{ID: "test", Type: "http_journey", Schedule: sched, Timeout: 1}, nil)[0]
+
+	event := &beat.Event{}
+	_, err = job(event)
+	require.NoError(t, err)
+	return event
+}
+
+func journeySteps(srv *httptest.Server) []mapstr.M {
+	return []mapstr.M{
+		{
+			"name": "login",
+			"request": mapstr.M{
+				"method":  "POST",
+				"url":     srv.URL + "/login",
+				"headers": mapstr.M{"Content-Type": "application/json"},
+				"body":    `{"user":"{{user}}"}`,
+			},
+			"check.response.json": []mapstr.M{
+				{"description": "expires", "expression": "expires_in > 0"},
+			},
+			"extract": []mapstr.M{
+				{"var": "token", "json": "$.token"},
+				{"var": "request_id", "header": "X-Request-Id", "regex": `req-(\d+)`},
+			},
+		},
+		{
+			"name": "me",
+			"request": mapstr.M{
+				"url":     srv.URL + "/me?request={{ request_id }}",
+				"headers": mapstr.M{"Authorization": "Bearer {{token}}"},
+			},
+			"check.response": mapstr.M{
+				"status": []uint16{200},
+				"json": []mapstr.M{
+					{"description": "user", "expression": `name == "alice" && request == "17"`},
+				},
+			},
+			"extract": []mapstr.M{
+				{"var": "id", "json": "$.id"},
+			},
+		},
+	}
+}
+
+func TestJourneyUp(t *testing.T) {
+	srv := journeyServer(t)
+	u, err := url.Parse(srv.URL + "/me?request=17")
+	require.NoError(t, err)
+
+	event := runJourney(t, mapstr.M{
+		"variables": mapstr.M{"user": "alice"},
+		"steps":     journeySteps(srv),
+		"timeout":   "1s",
+	})
+
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("", "up", "http_journey"),
+		hbtest.SummaryStateChecks(1, 0),
+		hbtest.URLChecks(t, u),
+		lookslike.MustCompile(map[string]interface{}{
+			"http.response.status_code": 200,
+			"http_journey": map[string]interface{}{
+				"duration.us": hbtestllext.IsInt64,
+				"steps": isdef.IsSliceOf(lookslike.MustCompile(map[string]interface{}{
+					"index":       isdef.IsAny(isdef.IsEqual(0), isdef.IsEqual(1)),
+					"name":        isdef.IsNonEmptyString,
+					"status":      "succeeded",
+					"status_code": 200,
+					"url":         isdef.IsNonEmptyString,
+					"duration.us": hbtestllext.IsInt64,
+				})),
+			},
+			"http_journey.failed_step": isdef.KeyMissing,
+		}),
+	), event.Fields)
+
+	steps, err := event.GetValue("http_journey.steps")
+	require.NoError(t, err)
+	require.Len(t, steps, 2)
+}
+
+func TestJourneyDown(t *testing.T) {
+	srv := journeyServer(t)
+
+	scenarios := []struct {
+		name   string
+		vars   mapstr.M
+		mutate func(steps []mapstr.M)
+		step   string
+		steps  int
+		err    validator.Validator
+	}{
+		{
+			name:  "status check",
+			vars:  mapstr.M{"user": "bob"},
+			step:  "login",
+			steps: 1,
+			err:   hbtest.ECSErrChecks(ecserr.NewBadHTTPStatusErr(http.StatusUnauthorized)),
+		},
+		{
+			name:  "undefined variable",
+			step:  "login",
+			steps: 1,
+			err:   hbtest.ErrorChecks("undefined variable 'user'", "validate"),
+		},
+		{
+			name: "failed extraction",
+			vars: mapstr.M{"user": "alice"},
+			mutate: func(steps []mapstr.M) {
+				steps[1]["extract"] = []mapstr.M{{"var": "email", "json": "$.email"}}
+			},
+			step:  "me",
+			steps: 2,
+			err:   hbtest.ErrorChecks("could not extract 'email'", "validate"),
+		},
+		{
+			name: "json check",
+			vars: mapstr.M{"user": "alice"},
+			mutate: func(steps []mapstr.M) {
+				steps[1]["check.response"].(mapstr.M)["json"] = []mapstr.M{
+					{"description": "admin", "expression": `name == "root"`},
+				}
+			},
+			step:  "me",
+			steps: 2,
+			err:   hbtest.ErrorChecks("JSON body did not match", "validate"),
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			steps := journeySteps(srv)
+			if s.mutate != nil {
+				s.mutate(steps)
+			}
+			config := mapstr.M{"steps": steps, "timeout": "1s"}
+			if s.vars != nil {
+				config["variables"] = s.vars
+			}
+			event := runJourney(t, config)
+
+			testslike.Test(t, lookslike.Compose(
+				hbtest.BaseChecks("", "down", "http_journey"),
+				hbtest.SummaryStateChecks(0, 1),
+				s.err,
+				lookslike.MustCompile(map[string]interface{}{
+					"http_journey.failed_step": s.step,
+				}),
+			), event.Fields)
+
+			got, err := event.GetValue("http_journey.steps")
+			require.NoError(t, err)
+			require.Len(t, got, s.steps)
+			last := got.([]mapstr.M)[s.steps-1]
+			require.Equal(t, "failed", last["status"])
+		})
+	}
+}
+
+func TestJourneyConnRefused(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	event := runJourney(t, mapstr.M{
+		"steps":   []mapstr.M{{"name": "ping", "request.url": srv.URL}},
+		"timeout": "1s",
+	})
+
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("", "down", "http_journey"),
+		hbtest.SummaryStateChecks(0, 1),
+		hbtest.ErrorChecks("connection refused", "io"),
+		lookslike.MustCompile(map[string]interface{}{"http_journey.failed_step": "ping"}),
+	), event.Fields)
+}
+
+func TestJourneyConfigValidate(t *testing.T) {
+	for name, c := range map[string]mapstr.M{
+		"no steps":        {"steps": []mapstr.M{}},
+		"missing url":     {"steps": []mapstr.M{{"name": "a"}}},
+		"missing name":    {"steps": []mapstr.M{{"request.url": "http://localhost"}}},
+		"bad method":      {"steps": []mapstr.M{{"name": "a", "request": mapstr.M{"url": "http://localhost", "method": "BREW"}}}},
+		"empty extract":   {"steps": []mapstr.M{{"name": "a", "request.url": "http://localhost", "extract": []mapstr.M{{"var": "x"}}}}},
+		"json and header": {"steps": []mapstr.M{{"name": "a", "request.url": "http://localhost", "extract": []mapstr.M{{"var": "x", "json": "$.a", "header": "A"}}}}},
+		"bad regex":       {"steps": []mapstr.M{{"name": "a", "request.url": "http://localhost", "extract": []mapstr.M{{"var": "x", "regex": "("}}}}},
+		"bad jsonpath":    {"steps": []mapstr.M{{"name": "a", "request.url": "http://localhost", "extract": []mapstr.M{{"var": "x", "json": "$.["}}}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := conf.NewConfigFrom(c)
+			require.NoError(t, err)
+
+			_, err = createJourney("http_journey", cfg)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestExpandVariables(t *testing.T) {
+	vars := map[string]string{"a": "1", "b.c": "2"}
+
+	s, err := expandVariables("{{a}}-{{ b.c }}-{a}-${a}", vars)
+	require.NoError(t, err)
+	require.Equal(t, "1-2-{a}-${a}", s)
+
+	_, err = expandVariables("{{missing}}", vars)
+	require.ErrorContains(t, err, "undefined variable 'missing'")
+}
@@ -25,37 +25,9 @@ import (
 	"testing"
 	"time"
 
-	"github.com/stretchr/testify/require"
-
-	conf "github.com/elastic/elastic-agent-libs/config"
-	"github.com/elastic/elastic-agent-libs/mapstr"
-
 	"github.com/elastic/beats/v7/heartbeat/hbtest"
-	"github.com/elastic/beats/v7/heartbeat/monitors/stdfields"
-	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers"
-	"github.com/elastic/beats/v7/heartbeat/scheduler/schedule"
-	"github.com/elastic/beats/v7/libbeat/beat"
 )
 
-func testTCPConfigCheck(t *testing.T, configMap mapstr.M) *beat.Event {
-	config, err := conf.NewConfigFrom(configMap)
-	require.NoError(t, err)
-
-	p, err := create("tcp", config)
-	require.NoError(t, err)
-
-	sched := schedule.MustParse("@every 1s")
-	job := wrappers.WrapCommon(p.Jobs, stdfields.StdMonitorFields{ID: "test", Type: "tcp", Schedule: sched, Timeout: 1}, nil)[0]
-
-	event := &beat.Event{}
-	_, err = job(event)
-	require.NoError(t, err)
-
-	require.Equal(t, 1, p.Endpoints)
-
-	return event
-}
-
 func setupServer(_ *testing.T, serverCreator func(http.Handler) (*httptest.Server, error)) (*httptest.Server, uint16, error) {
 	server, err := serverCreator(hbtest.HelloWorldHandler(200))
 	if err != nil {
@@ -70,7 +70,7 @@ func TestSocks5Job(t *testing.T) {
 				"check.receive":            "echo123",
 				"check.send":               "echo123",
 			}
-			event := testTCPConfigCheck(t, configMap)
+			event := hbtest.RunMonitorCheck(t, create, "tcp", configMap)
 
 			testslike.Test(
 				t,
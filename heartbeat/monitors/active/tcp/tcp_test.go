@@ -46,7 +46,7 @@ func testTCPCheck(t *testing.T, host string, port uint16) *beat.Event {
 		"ports":   port,
 		"timeout": "1s",
 	}
-	return testTCPConfigCheck(t, config)
+	return hbtest.RunMonitorCheck(t, create, "tcp", config)
 }
 
 // TestUpEndpointJob tests an up endpoint configured using either direct lookups or IPs
@@ -169,7 +169,7 @@ func TestCheckUp(t *testing.T) {
 		"check.send":    "echo123",
 	}
 
-	event := testTCPConfigCheck(t, configMap)
+	event := hbtest.RunMonitorCheck(t, create, "tcp", configMap)
 
 	testslike.Test(
 		t,
@@ -201,7 +201,7 @@ func TestCheckDown(t *testing.T) {
 		"check.receive": "BOOM", // should fail
 		"check.send":    "echo123",
 	}
-	event := testTCPConfigCheck(t, configMap)
+	event := hbtest.RunMonitorCheck(t, create, "tcp", configMap)
 
 	testslike.Test(
 		t,
@@ -0,0 +1,254 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tls
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	cryptoTLS "crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/elastic/beats/v7/heartbeat/reason"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// failure is a single failed check. The code is reported in
+// `tls.check.failures`, the message in the error of the event.
+type failure struct {
+	code    string
+	message string
+}
+
+type checkResult struct {
+	// chains are the verified certificate chains, empty if the chain could
+	// not be verified or the chain check is disabled.
+	chains   [][]*x509.Certificate
+	notAfter time.Time
+	now      time.Time
+	failures []failure
+}
+
+func (r *checkResult) fail(code, format string, args ...interface{}) {
+	r.failures = append(r.failures, failure{code: code, message: fmt.Sprintf(format, args...)})
+}
+
+func (r *checkResult) fields() mapstr.M {
+	codes := make([]string, 0, len(r.failures))
+	for _, f := range r.failures {
+		codes = append(codes, f.code)
+	}
+	fields := mapstr.M{"failures": codes}
+	if !r.notAfter.IsZero() {
+		fields["days_remaining"] = int64(math.Floor(r.notAfter.Sub(r.now).Hours() / 24))
+	}
+	return fields
+}
+
+func (r *checkResult) err() error {
+	if len(r.failures) == 0 {
+		return nil
+	}
+	msgs := make([]string, 0, len(r.failures))
+	for _, f := range r.failures {
+		msgs = append(msgs, f.message)
+	}
+	return reason.MakeValidateError(errors.New(strings.Join(msgs, "; ")))
+}
+
+// checkConnection runs every configured check against the certificates
+// presented by the server for host.
+func checkConnection(cfg *checkConfig, roots *x509.CertPool, host string, connState cryptoTLS.ConnectionState, now time.Time) *checkResult {
+	res := &checkResult{now: now}
+	peers := connState.PeerCertificates
+	leaf := peers[0]
+
+	if cfg.Chain {
+		checkChain(res, roots, peers)
+	}
+
+	// Prefer the verified chain, it ignores unrelated certificates sent by
+	// the server and includes the trusted root.
+	certs := peers
+	if len(res.chains) > 0 {
+		certs = res.chains[0]
+	}
+
+	checkValidity(res, cfg.ExpiryThreshold, certs)
+
+	if cfg.Hostname {
+		if err := leaf.VerifyHostname(host); err != nil {
+			res.fail("hostname", "%v", err)
+		}
+	}
+
+	for _, cert := range certs {
+		checkKey(res, cfg.MinRSAKeySize, cert)
+		if cfg.Signature {
+			checkSignature(res, cert)
+		}
+	}
+
+	if cfg.OCSPStapling {
+		var issuer *x509.Certificate
+		if len(certs) > 1 {
+			issuer = certs[1]
+		}
+		checkOCSPStaple(res, connState.OCSPResponse, leaf, issuer)
+	}
+
+	return res
+}
+
+// checkChain verifies the chain presented by the server. Only the
+// intermediates sent by the server are used, so a server that does not
+// send its intermediates fails the check.
+func checkChain(res *checkResult, roots *x509.CertPool, peers []*x509.Certificate) {
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: x509.NewCertPool(),
+		CurrentTime:   res.now,
+	}
+	for _, cert := range peers[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	chains, err := peers[0].Verify(opts)
+	var invalidErr x509.CertificateInvalidError
+	if errors.As(err, &invalidErr) && invalidErr.Reason == x509.Expired {
+		// Expired certificates are reported by the validity check. Verify
+		// the chain again at a time all presented certificates are valid.
+		opts.CurrentTime = time.Time{}
+		for _, cert := range peers {
+			if cert.NotBefore.After(opts.CurrentTime) {
+				opts.CurrentTime = cert.NotBefore
+			}
+		}
+		chains, err = peers[0].Verify(opts)
+	}
+	if err != nil {
+		var authorityErr x509.UnknownAuthorityError
+		if errors.As(err, &authorityErr) {
+			res.fail("chain", "certificate chain is incomplete or not trusted: %v", err)
+		} else {
+			res.fail("chain", "certificate chain could not be verified: %v", err)
+		}
+		return
+	}
+	res.chains = chains
+}
+
+// checkValidity checks that every certificate of the chain is valid now
+// and does not expire within threshold.
+func checkValidity(res *checkResult, threshold time.Duration, certs []*x509.Certificate) {
+	var expiring *x509.Certificate
+	for _, cert := range certs {
+		if cert.NotBefore.After(res.now) {
+			res.fail("not_yet_valid", "certificate '%s' is not valid before %s", certName(cert), cert.NotBefore.Format(time.RFC3339))
+		}
+		// A zero NotAfter means the certificate never expires.
+		if cert.NotAfter.IsZero() {
+			continue
+		}
+		if expiring == nil || cert.NotAfter.Before(expiring.NotAfter) {
+			expiring = cert
+		}
+	}
+	if expiring == nil {
+		return
+	}
+
+	res.notAfter = expiring.NotAfter
+	switch {
+	case !expiring.NotAfter.After(res.now):
+		res.fail("expired", "certificate '%s' expired at %s", certName(expiring), expiring.NotAfter.Format(time.RFC3339))
+	case expiring.NotAfter.Sub(res.now) < threshold:
+		res.fail("expiring", "certificate '%s' expires at %s, within %s", certName(expiring), expiring.NotAfter.Format(time.RFC3339), threshold)
+	}
+}
+
+func checkKey(res *checkResult, minRSAKeySize int, cert *x509.Certificate) {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if size := key.N.BitLen(); size < minRSAKeySize {
+			res.fail("weak_key", "certificate '%s' uses a %d bit RSA key, at least %d bits are required", certName(cert), size, minRSAKeySize)
+		}
+	case *ecdsa.PublicKey:
+		if size := key.Curve.Params().BitSize; size < 256 {
+			res.fail("weak_key", "certificate '%s' uses the %d bit curve %s", certName(cert), size, key.Curve.Params().Name)
+		}
+	}
+}
+
+// weakSignatures are the signature algorithms based on MD5 or SHA-1.
+var weakSignatures = map[x509.SignatureAlgorithm]bool{
+	x509.MD2WithRSA:    true,
+	x509.MD5WithRSA:    true,
+	x509.SHA1WithRSA:   true,
+	x509.DSAWithSHA1:   true,
+	x509.ECDSAWithSHA1: true,
+}
+
+func checkSignature(res *checkResult, cert *x509.Certificate) {
+	// The signature of a self-signed root is not relied upon, roots are
+	// trusted by presence in the trust store.
+	if bytes.Equal(cert.RawSubject, cert.RawIssuer) && cert.IsCA {
+		return
+	}
+	if weakSignatures[cert.SignatureAlgorithm] {
+		res.fail("weak_signature", "certificate '%s' is signed with the weak algorithm %s", certName(cert), cert.SignatureAlgorithm)
+	}
+}
+
+// checkOCSPStaple requires a stapled OCSP response reporting the leaf
+// certificate as good. The signature of the response is only checked if
+// the issuer is known.
+func checkOCSPStaple(res *checkResult, staple []byte, leaf, issuer *x509.Certificate) {
+	if len(staple) == 0 {
+		res.fail("ocsp_stapling", "server did not staple an OCSP response")
+		return
+	}
+
+	resp, err := ocsp.ParseResponseForCert(staple, leaf, issuer)
+	if err != nil {
+		res.fail("ocsp_stapling", "invalid stapled OCSP response: %v", err)
+		return
+	}
+	switch {
+	case resp.Status == ocsp.Revoked:
+		res.fail("ocsp_stapling", "stapled OCSP response reports the certificate as revoked at %s", resp.RevokedAt.Format(time.RFC3339))
+	case resp.Status != ocsp.Good:
+		res.fail("ocsp_stapling", "stapled OCSP response reports the certificate status as unknown")
+	case !resp.NextUpdate.IsZero() && resp.NextUpdate.Before(res.now):
+		res.fail("ocsp_stapling", "stapled OCSP response is outdated since %s", resp.NextUpdate.Format(time.RFC3339))
+	}
+}
+
+func certName(cert *x509.Certificate) string {
+	if cert.Subject.CommonName != "" {
+		return cert.Subject.CommonName
+	}
+	return cert.Subject.String()
+}
@@ -0,0 +1,80 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tls
+
+import (
+	"errors"
+	"time"
+
+	"github.com/elastic/elastic-agent-libs/transport/tlscommon"
+)
+
+type config struct {
+	// Hosts are the servers to check, as `host`, `host:port` or
+	// `tls://host:port`. The port defaults to 443.
+	Hosts []string `config:"hosts" validate:"required"`
+
+	Check checkConfig `config:"check"`
+
+	// configure tls, the certificate authorities are used to verify the chain
+	TLS *tlscommon.Config `config:"ssl"`
+
+	Timeout time.Duration `config:"timeout"`
+}
+
+type checkConfig struct {
+	// ExpiryThreshold reports the monitor down once the certificate chain
+	// expires within this window.
+	ExpiryThreshold time.Duration `config:"expiry_threshold"`
+	// Chain requires the presented chain to verify up to a trusted root.
+	Chain bool `config:"chain"`
+	// Hostname requires the leaf certificate to be valid for the host.
+	Hostname bool `config:"hostname"`
+	// MinRSAKeySize is the smallest accepted RSA key, in bits.
+	MinRSAKeySize int `config:"min_rsa_key_size"`
+	// Signature rejects certificates signed with MD5 or SHA-1.
+	Signature bool `config:"signature"`
+	// OCSPStapling requires the server to staple a good OCSP response.
+	OCSPStapling bool `config:"ocsp_stapling"`
+}
+
+func defaultConfig() config {
+	return config{
+		Check: checkConfig{
+			ExpiryThreshold: 30 * 24 * time.Hour,
+			Chain:           true,
+			Hostname:        true,
+			MinRSAKeySize:   2048,
+			Signature:       true,
+		},
+		Timeout: 16 * time.Second,
+	}
+}
+
+func (c *config) Validate() error {
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be greater than 0")
+	}
+	if c.Check.ExpiryThreshold < 0 {
+		return errors.New("check.expiry_threshold must not be negative")
+	}
+	if c.Check.MinRSAKeySize < 0 {
+		return errors.New("check.min_rsa_key_size must not be negative")
+	}
+	return nil
+}
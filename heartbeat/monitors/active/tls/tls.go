@@ -0,0 +1,189 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tls
+
+import (
+	"context"
+	cryptoTLS "crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/elastic/beats/v7/heartbeat/eventext"
+	"github.com/elastic/beats/v7/heartbeat/look"
+	"github.com/elastic/beats/v7/heartbeat/monitors/active/dialchain/tlsmeta"
+	"github.com/elastic/beats/v7/heartbeat/monitors/jobs"
+	"github.com/elastic/beats/v7/heartbeat/monitors/plugin"
+	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers/wraputil"
+	"github.com/elastic/beats/v7/heartbeat/reason"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/elastic-agent-libs/transport/tlscommon"
+)
+
+func init() {
+	plugin.Register("tls", create, "synthetics/tls")
+}
+
+var debugf = logp.MakeDebug("tls")
+
+func create(
+	name string,
+	cfg *conf.C,
+) (p plugin.Plugin, err error) {
+	jf, err := newJobFactory(cfg)
+	if err != nil {
+		return plugin.Plugin{}, err
+	}
+
+	js := jf.makeJobs()
+	return plugin.Plugin{Jobs: js, Endpoints: len(js)}, nil
+}
+
+// jobFactory holds the parsed configuration shared by the jobs of every
+// host configured on a single monitor.
+type jobFactory struct {
+	config    config
+	tlsConfig *tlscommon.TLSConfig
+	endpoints []*url.URL
+}
+
+func newJobFactory(commonCfg *conf.C) (*jobFactory, error) {
+	jf := &jobFactory{config: defaultConfig()}
+	if err := commonCfg.Unpack(&jf.config); err != nil {
+		return nil, err
+	}
+
+	var err error
+	jf.tlsConfig, err = tlscommon.LoadTLSConfig(jf.config.TLS)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, host := range jf.config.Hosts {
+		u, err := parseEndpoint(host)
+		if err != nil {
+			return nil, err
+		}
+		jf.endpoints = append(jf.endpoints, u)
+	}
+
+	return jf, nil
+}
+
+func (jf *jobFactory) makeJobs() []jobs.Job {
+	js := make([]jobs.Job, 0, len(jf.endpoints))
+	for _, endpoint := range jf.endpoints {
+		js = append(js, wraputil.WithURLField(endpoint, jobs.MakeSimpleJob(func(event *beat.Event) error {
+			return jf.check(event, endpoint)
+		})))
+	}
+	return js
+}
+
+// check runs the TLS handshake with the server at endpoint and inspects the
+// certificates it presents. Any failed check reports the monitor down, with
+// every failed check listed in the error message.
+func (jf *jobFactory) check(event *beat.Event, endpoint *url.URL) error {
+	connState, handshakeRTT, err := jf.handshake(event, endpoint)
+	if err != nil {
+		debugf("TLS handshake with %s failed: %v", endpoint, err)
+		return reason.IOFailed(err)
+	}
+
+	res := checkConnection(&jf.config.Check, jf.rootCAs(), endpoint.Hostname(), connState, time.Now())
+
+	// Chains are not verified during the handshake, pass the chains found
+	// by the checks so the certificate timestamps cover the whole chain.
+	connState.VerifiedChains = res.chains
+	tlsmeta.AddTLSMetadata(event.Fields, connState, handshakeRTT)
+	eventext.MergeEventFields(event, mapstr.M{"tls": mapstr.M{"check": res.fields()}})
+
+	return res.err()
+}
+
+// handshake connects to endpoint and completes the TLS handshake. The
+// server certificates are not verified here, so that every problem with
+// them can be reported by the checks instead of failing the handshake.
+func (jf *jobFactory) handshake(event *beat.Event, endpoint *url.URL) (cryptoTLS.ConnectionState, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), jf.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint.Host)
+	if err != nil {
+		return cryptoTLS.ConnectionState{}, 0, err
+	}
+	defer conn.Close()
+	connected := time.Now()
+	eventext.MergeEventFields(event, mapstr.M{"tcp": mapstr.M{"rtt": mapstr.M{"connect": look.RTT(connected.Sub(start))}}})
+
+	tlsCfg := jf.tlsConfig.BuildModuleClientConfig(endpoint.Hostname())
+	tlsCfg.InsecureSkipVerify = true //nolint:gosec // certificates are verified by the checks
+	tlsCfg.VerifyConnection = nil
+	tlsCfg.VerifyPeerCertificate = nil
+
+	tlsConn := cryptoTLS.Client(conn, tlsCfg)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return cryptoTLS.ConnectionState{}, 0, err
+	}
+	handshakeRTT := time.Since(connected)
+
+	connState := tlsConn.ConnectionState()
+	if len(connState.PeerCertificates) == 0 {
+		return connState, 0, fmt.Errorf("server %s presented no certificate", endpoint.Host)
+	}
+	return connState, handshakeRTT, nil
+}
+
+// rootCAs returns the configured certificate authorities, nil selects the
+// system roots.
+func (jf *jobFactory) rootCAs() *x509.CertPool {
+	if jf.tlsConfig == nil {
+		return nil
+	}
+	return jf.tlsConfig.RootCAs
+}
+
+// parseEndpoint turns a configured host into a `tls://host:port` URL.
+func parseEndpoint(host string) (*url.URL, error) {
+	if !strings.Contains(host, "://") {
+		host = "tls://" + host
+	}
+
+	u, err := url.Parse(host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tls host '%s': %w", host, err)
+	}
+	if u.Scheme != "tls" {
+		return nil, fmt.Errorf("unsupported tls host scheme '%s' in '%s'", u.Scheme, host)
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid tls host '%s': missing host", host)
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), "443")
+	}
+	return u, nil
+}
@@ -0,0 +1,409 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package tls
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	cryptoTLS "crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/go-lookslike"
+	"github.com/elastic/go-lookslike/isdef"
+	"github.com/elastic/go-lookslike/testslike"
+	"github.com/elastic/go-lookslike/validator"
+
+	"github.com/elastic/beats/v7/heartbeat/hbtest"
+	"github.com/elastic/beats/v7/heartbeat/hbtestllext"
+)
+
+// testPKI is a root and an intermediate CA issuing the server certificates
+// of the tests.
+type testPKI struct {
+	root      *x509.Certificate
+	inter     *x509.Certificate
+	interKey  crypto.Signer
+	rootsFile string
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+
+	rootKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	root := createCert(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test Root CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(10 * 365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, rootKey, rootKey)
+
+	interKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	inter := createCert(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test Intermediate CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(5 * 365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, root, rootKey, interKey)
+
+	rootsFile := hbtest.CertToTempFile(t, root)
+	t.Cleanup(func() { os.Remove(rootsFile.Name()) })
+
+	return &testPKI{root: root, inter: inter, interKey: interKey, rootsFile: rootsFile.Name()}
+}
+
+// leaf issues a server certificate for 127.0.0.1 and localhost valid for
+// validity, modify can alter the template before signing.
+func (p *testPKI) leaf(t *testing.T, key crypto.Signer, validity time.Duration, modify func(*x509.Certificate)) *x509.Certificate {
+	t.Helper()
+
+	if key == nil {
+		var err error
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+	}
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "localhost"},
+		DNSNames:    []string{"localhost"},
+		IPAddresses: []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:   time.Now().Add(-time.Hour),
+		NotAfter:    time.Now().Add(validity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	if modify != nil {
+		modify(template)
+	}
+	return createCert(t, template, p.inter, p.interKey, key)
+}
+
+func createCert(t *testing.T, template, parent *x509.Certificate, parentKey, key crypto.Signer) *x509.Certificate {
+	t.Helper()
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template.SerialNumber = serial
+	if parent == nil {
+		parent = template
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+// startServer runs a TLS server presenting the chain, the private key of
+// the first certificate is key.
+func startServer(t *testing.T, key crypto.Signer, staple []byte, chain ...*x509.Certificate) string {
+	t.Helper()
+
+	tlsCert := cryptoTLS.Certificate{PrivateKey: key, OCSPStaple: staple, Leaf: chain[0]}
+	for _, cert := range chain {
+		tlsCert.Certificate = append(tlsCert.Certificate, cert.Raw)
+	}
+
+	l, err := cryptoTLS.Listen("tcp", "127.0.0.1:0", &cryptoTLS.Config{
+		Certificates: []cryptoTLS.Certificate{tlsCert},
+		MinVersion:   cryptoTLS.VersionTLS12,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*cryptoTLS.Conn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	return l.Addr().String()
+}
+
+func failureChecks(codes ...string) validator.Validator {
+	return lookslike.MustCompile(map[string]interface{}{
+		"tls.check.failures":       isdef.IsEqual(codes),
+		"tls.check.days_remaining": hbtestllext.IsInt64,
+		"tls.established":          true,
+		"tls.rtt.handshake.us":     hbtestllext.IsInt64,
+		"tcp.rtt.connect.us":       hbtestllext.IsInt64,
+	})
+}
+
+func TestChecks(t *testing.T) {
+	pki := newTestPKI(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+
+	scenarios := []struct {
+		name   string
+		key    crypto.Signer
+		chain  []*x509.Certificate
+		config mapstr.M
+		codes  []string
+		errMsg string
+	}{
+		{
+			name:  "valid",
+			key:   key,
+			chain: []*x509.Certificate{pki.leaf(t, key, 365*24*time.Hour, nil), pki.inter},
+			codes: []string{},
+		},
+		{
+			name:   "expiring",
+			key:    key,
+			chain:  []*x509.Certificate{pki.leaf(t, key, 10*24*time.Hour, nil), pki.inter},
+			codes:  []string{"expiring"},
+			errMsg: "certificate 'localhost' expires at",
+		},
+		{
+			name:   "expiring within custom threshold",
+			key:    key,
+			chain:  []*x509.Certificate{pki.leaf(t, key, 10*24*time.Hour, nil), pki.inter},
+			config: mapstr.M{"check.expiry_threshold": "24h"},
+			codes:  []string{},
+		},
+		{
+			name:   "expired",
+			key:    key,
+			chain:  []*x509.Certificate{pki.leaf(t, key, -time.Minute, nil), pki.inter},
+			codes:  []string{"expired"},
+			errMsg: "certificate 'localhost' expired at",
+		},
+		{
+			name:   "incomplete chain",
+			key:    key,
+			chain:  []*x509.Certificate{pki.leaf(t, key, 365*24*time.Hour, nil)},
+			codes:  []string{"chain"},
+			errMsg: "certificate chain is incomplete or not trusted",
+		},
+		{
+			name:   "incomplete chain not checked",
+			key:    key,
+			chain:  []*x509.Certificate{pki.leaf(t, key, 365*24*time.Hour, nil)},
+			config: mapstr.M{"check.chain": false},
+			codes:  []string{},
+		},
+		{
+			name: "hostname mismatch",
+			key:  key,
+			chain: []*x509.Certificate{pki.leaf(t, key, 365*24*time.Hour, func(c *x509.Certificate) {
+				c.DNSNames = []string{"example.com"}
+				c.IPAddresses = nil
+			}), pki.inter},
+			codes:  []string{"hostname"},
+			errMsg: "x509: cannot validate certificate for 127.0.0.1 because it doesn't contain any IP SANs",
+		},
+		{
+			name:   "weak key",
+			key:    weakKey,
+			chain:  []*x509.Certificate{pki.leaf(t, weakKey, 365*24*time.Hour, nil), pki.inter},
+			codes:  []string{"weak_key"},
+			errMsg: "certificate 'localhost' uses a 1024 bit RSA key, at least 2048 bits are required",
+		},
+		{
+			name:   "weak signature",
+			key:    key,
+			chain:  []*x509.Certificate{pki.leaf(t, key, 365*24*time.Hour, func(c *x509.Certificate) { c.SignatureAlgorithm = x509.ECDSAWithSHA1 }), pki.inter},
+			config: mapstr.M{"check.chain": false},
+			codes:  []string{"weak_signature"},
+			errMsg: "certificate 'localhost' is signed with the weak algorithm ECDSA-SHA1",
+		},
+		{
+			name:   "missing OCSP staple",
+			key:    key,
+			chain:  []*x509.Certificate{pki.leaf(t, key, 365*24*time.Hour, nil), pki.inter},
+			config: mapstr.M{"check.ocsp_stapling": true},
+			codes:  []string{"ocsp_stapling"},
+			errMsg: "server did not staple an OCSP response",
+		},
+		{
+			name:   "every failure listed",
+			key:    weakKey,
+			chain:  []*x509.Certificate{pki.leaf(t, weakKey, 10*24*time.Hour, func(c *x509.Certificate) { c.IPAddresses = nil })},
+			config: mapstr.M{"check.ocsp_stapling": true},
+			codes:  []string{"chain", "expiring", "hostname", "weak_key", "ocsp_stapling"},
+			errMsg: "certificate chain is incomplete or not trusted",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			addr := startServer(t, s.key, nil, s.chain...)
+			config := mapstr.M{
+				"hosts":                       addr,
+				"ssl.certificate_authorities": pki.rootsFile,
+				"timeout":                     "1s",
+			}
+			config.DeepUpdate(s.config)
+			event := hbtest.RunMonitorCheck(t, create, "tls", config)
+
+			u, err := url.Parse("tls://" + addr)
+			require.NoError(t, err)
+
+			checks := []validator.Validator{
+				hbtest.URLChecks(t, u),
+				hbtest.TLSCertChecks(s.chain[0]),
+				failureChecks(s.codes...),
+			}
+			if len(s.codes) == 0 {
+				checks = append(checks, hbtest.BaseChecks("", "up", "tls"), hbtest.SummaryStateChecks(1, 0))
+			} else {
+				checks = append(checks,
+					hbtest.BaseChecks("", "down", "tls"),
+					hbtest.SummaryStateChecks(0, 1),
+					hbtest.ErrorChecks(s.errMsg, "validate"),
+				)
+			}
+			testslike.Test(t, lookslike.Compose(checks...), event.Fields)
+		})
+	}
+}
+
+func TestOCSPStaple(t *testing.T) {
+	pki := newTestPKI(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	leaf := pki.leaf(t, key, 365*24*time.Hour, nil)
+
+	staple := func(status int) []byte {
+		resp, err := ocsp.CreateResponse(pki.inter, pki.inter, ocsp.Response{
+			Status:       status,
+			SerialNumber: leaf.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Hour),
+			NextUpdate:   time.Now().Add(time.Hour),
+			RevokedAt:    time.Now().Add(-time.Minute),
+		}, pki.interKey)
+		require.NoError(t, err)
+		return resp
+	}
+
+	scenarios := []struct {
+		name   string
+		staple []byte
+		codes  []string
+		errMsg string
+	}{
+		{
+			name:   "good",
+			staple: staple(ocsp.Good),
+			codes:  []string{},
+		},
+		{
+			name:   "revoked",
+			staple: staple(ocsp.Revoked),
+			codes:  []string{"ocsp_stapling"},
+			errMsg: "stapled OCSP response reports the certificate as revoked",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			addr := startServer(t, key, s.staple, leaf, pki.inter)
+			event := hbtest.RunMonitorCheck(t, create, "tls", mapstr.M{
+				"hosts":                       addr,
+				"ssl.certificate_authorities": pki.rootsFile,
+				"check.ocsp_stapling":         true,
+				"timeout":                     "1s",
+			})
+
+			status, up, down := "up", uint16(1), uint16(0)
+			if len(s.codes) > 0 {
+				status, up, down = "down", 0, 1
+			}
+			checks := []validator.Validator{
+				hbtest.BaseChecks("", status, "tls"),
+				hbtest.SummaryStateChecks(up, down),
+				failureChecks(s.codes...),
+			}
+			if s.errMsg != "" {
+				checks = append(checks, hbtest.ErrorChecks(s.errMsg, "validate"))
+			}
+			testslike.Test(t, lookslike.Compose(checks...), event.Fields)
+		})
+	}
+}
+
+func TestUnreachableServer(t *testing.T) {
+	// grab a free port and close it so nothing is listening there
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	event := hbtest.RunMonitorCheck(t, create, "tls", mapstr.M{"hosts": addr, "timeout": "1s"})
+
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("", "down", "tls"),
+		hbtest.SummaryStateChecks(0, 1),
+		hbtest.ErrorChecks("", "io"),
+	), event.Fields)
+}
+
+func TestParseEndpoint(t *testing.T) {
+	scenarios := []struct {
+		host     string
+		expected string
+		err      bool
+	}{
+		{host: "example.com", expected: "tls://example.com:443"},
+		{host: "example.com:8443", expected: "tls://example.com:8443"},
+		{host: "tls://example.com", expected: "tls://example.com:443"},
+		{host: "[::1]:8443", expected: "tls://[::1]:8443"},
+		{host: "https://example.com", err: true},
+		{host: "tls://:443", err: true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.host, func(t *testing.T) {
+			u, err := parseEndpoint(s.host)
+			if s.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, s.expected, u.String())
+		})
+	}
+}
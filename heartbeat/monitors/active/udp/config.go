@@ -0,0 +1,107 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package udp
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/elastic/beats/v7/heartbeat/monitors"
+)
+
+type config struct {
+	// check all ports if host does not contain port
+	Hosts []string `config:"hosts" validate:"required"`
+	Ports []uint16 `config:"ports"`
+
+	Mode monitors.IPSettings `config:",inline"`
+
+	// Timeout is how long to wait for a response to each datagram sent.
+	Timeout time.Duration `config:"timeout"`
+
+	// Retries is the number of times the payload is sent again when no
+	// response arrives before the timeout.
+	Retries int `config:"retries" validate:"min=0"`
+
+	// validate response
+	SendString    string `config:"check.send"`
+	ReceiveString string `config:"check.receive"`
+	SendHex       string `config:"check.send_hex"`
+	ReceiveHex    string `config:"check.receive_hex"`
+}
+
+func defaultConfig() config {
+	return config{
+		Timeout: 16 * time.Second,
+		Mode:    monitors.DefaultIPSettings,
+	}
+}
+
+func (c *config) Validate() error {
+	if c.SendString != "" && c.SendHex != "" {
+		return errors.New("check.send and check.send_hex are mutually exclusive")
+	}
+	if c.ReceiveString != "" && c.ReceiveHex != "" {
+		return errors.New("check.receive and check.receive_hex are mutually exclusive")
+	}
+
+	send, err := c.sendPayload()
+	if err != nil {
+		return err
+	}
+	if len(send) == 0 {
+		return errors.New("check.send or check.send_hex is required, udp servers only answer requests")
+	}
+	if _, err := c.receivePayload(); err != nil {
+		return err
+	}
+
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be greater than 0")
+	}
+	return nil
+}
+
+// sendPayload returns the datagram sent to the server.
+func (c *config) sendPayload() ([]byte, error) {
+	if c.SendHex != "" {
+		return decodeHex("check.send_hex", c.SendHex)
+	}
+	return []byte(c.SendString), nil
+}
+
+// receivePayload returns the expected prefix of the response, if any.
+func (c *config) receivePayload() ([]byte, error) {
+	if c.ReceiveHex != "" {
+		return decodeHex("check.receive_hex", c.ReceiveHex)
+	}
+	return []byte(c.ReceiveString), nil
+}
+
+// decodeHex decodes s, ignoring whitespace so long payloads can be grouped
+// by bytes or words.
+func decodeHex(name, s string) ([]byte, error) {
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return b, nil
+}
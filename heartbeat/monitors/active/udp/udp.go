@@ -0,0 +1,221 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package udp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/elastic/beats/v7/heartbeat/eventext"
+	"github.com/elastic/beats/v7/heartbeat/look"
+	"github.com/elastic/beats/v7/heartbeat/monitors"
+	"github.com/elastic/beats/v7/heartbeat/monitors/active/dialchain"
+	"github.com/elastic/beats/v7/heartbeat/monitors/jobs"
+	"github.com/elastic/beats/v7/heartbeat/monitors/plugin"
+	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers/wraputil"
+	"github.com/elastic/beats/v7/heartbeat/reason"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+func init() {
+	plugin.Register("udp", create, "synthetics/udp")
+}
+
+var debugf = logp.MakeDebug("udp")
+
+// maxDatagramSize is large enough for any UDP payload.
+const maxDatagramSize = 64 * 1024
+
+var (
+	errNoResponse     = errors.New("no response received")
+	errRecvMismatch   = errors.New("received data mismatch")
+	errNoDataReceived = errors.New("no data")
+)
+
+func create(
+	name string,
+	cfg *conf.C,
+) (p plugin.Plugin, err error) {
+	return createWithResolver(cfg, monitors.NewStdResolver())
+}
+
+// Custom resolver is useful for tests against hostnames locally where we don't want to depend on any
+// hostnames existing in test environments
+func createWithResolver(
+	cfg *conf.C,
+	resolver monitors.Resolver,
+) (p plugin.Plugin, err error) {
+	jf, err := newJobFactory(cfg, resolver)
+	if err != nil {
+		return plugin.Plugin{}, err
+	}
+
+	js, err := jf.makeJobs()
+	if err != nil {
+		return plugin.Plugin{}, err
+	}
+
+	return plugin.Plugin{Jobs: js, Endpoints: len(js)}, nil
+}
+
+// jobFactory provides a common context around the execution of a UDP check.
+type jobFactory struct {
+	config    config
+	endpoints []endpoint
+	send      []byte
+	receive   []byte
+	resolver  monitors.Resolver
+}
+
+func newJobFactory(commonCfg *conf.C, resolver monitors.Resolver) (*jobFactory, error) {
+	jf := &jobFactory{config: defaultConfig(), resolver: resolver}
+	if err := commonCfg.Unpack(&jf.config); err != nil {
+		return nil, err
+	}
+
+	var err error
+	// both payloads have been checked by config.Validate
+	jf.send, _ = jf.config.sendPayload()
+	jf.receive, _ = jf.config.receivePayload()
+
+	jf.endpoints, err = makeEndpoints(jf.config.Hosts, jf.config.Ports)
+	if err != nil {
+		return nil, err
+	}
+
+	return jf, nil
+}
+
+// makeJobs returns the actual schedulable jobs for this monitor.
+func (jf *jobFactory) makeJobs() ([]jobs.Job, error) {
+	var js []jobs.Job
+	for _, endpoint := range jf.endpoints {
+		for _, url := range endpoint.perPortURLs() {
+			endpointJob, err := jf.makeEndpointJob(url)
+			if err != nil {
+				return nil, err
+			}
+			js = append(js, wraputil.WithURLField(url, endpointJob))
+		}
+	}
+
+	return js, nil
+}
+
+// makeEndpointJob makes a job that first resolves one or multiple IPs
+// (depending on config.Mode) in order to create one continuation Task per IP.
+func (jf *jobFactory) makeEndpointJob(endpointURL *url.URL) (jobs.Job, error) {
+	return monitors.MakeByHostJob(
+		endpointURL.Hostname(),
+		jf.config.Mode,
+		jf.resolver,
+		monitors.MakePingIPFactory(
+			func(event *beat.Event, ip *net.IPAddr) error {
+				// use address from resolved IP
+				ipPort := net.JoinHostPort(ip.String(), endpointURL.Port())
+
+				return jf.dial(event, ipPort)
+			}))
+}
+
+// dial builds a dialer for the resolved address and executes the check.
+func (jf *jobFactory) dial(event *beat.Event, dialAddr string) error {
+	dc := &dialchain.DialerChain{
+		Net: dialchain.UDPDialer(jf.config.Timeout),
+	}
+	dc.AddLayer(dialchain.ConstAddrLayer(dialAddr))
+
+	dialer, err := dc.Build(event)
+	if err != nil {
+		return err
+	}
+
+	conn, err := dialer.Dial("udp", dialAddr)
+	if err != nil {
+		debugf("dial failed with: %v", err)
+		return reason.IOFailed(err)
+	}
+	defer conn.Close()
+
+	return jf.exchange(event, conn)
+}
+
+// exchange sends the payload and waits for a response, sending the payload
+// again up to config.Retries times when none arrives in time.
+func (jf *jobFactory) exchange(event *beat.Event, conn net.Conn) error {
+	buf := make([]byte, maxDatagramSize)
+
+	var (
+		n        int
+		err      error
+		attempts int
+		start    time.Time
+	)
+	for attempts < jf.config.Retries+1 {
+		attempts++
+		start = time.Now()
+		n, err = jf.roundTrip(conn, buf)
+		if !errors.Is(err, os.ErrDeadlineExceeded) {
+			break
+		}
+		debugf("no response from %s on attempt %d", conn.RemoteAddr(), attempts)
+	}
+	end := time.Now()
+
+	fields := mapstr.M{"attempts": attempts}
+	if err == nil {
+		fields["rtt"] = mapstr.M{"validate": look.RTT(end.Sub(start))}
+	}
+	eventext.MergeEventFields(event, mapstr.M{"udp": fields})
+
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		return reason.IOFailed(fmt.Errorf("%w after %d attempt(s)", errNoResponse, attempts))
+	}
+	if err != nil {
+		// e.g. ICMP port unreachable, reported as connection refused
+		debugf("check failed with: %v", err)
+		return reason.IOFailed(err)
+	}
+
+	if n == 0 && len(jf.receive) == 0 {
+		return reason.MakeValidateError(errNoDataReceived)
+	}
+	if !bytes.HasPrefix(buf[:n], jf.receive) {
+		return reason.MakeValidateError(errRecvMismatch)
+	}
+	return nil
+}
+
+// roundTrip sends a single datagram and reads the response into buf.
+func (jf *jobFactory) roundTrip(conn net.Conn, buf []byte) (int, error) {
+	if err := conn.SetDeadline(time.Now().Add(jf.config.Timeout)); err != nil {
+		return 0, err
+	}
+	if _, err := conn.Write(jf.send); err != nil {
+		return 0, err
+	}
+	return conn.Read(buf)
+}
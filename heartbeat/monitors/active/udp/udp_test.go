@@ -0,0 +1,195 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package udp
+
+import (
+	"bytes"
+	"net"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/go-lookslike"
+	"github.com/elastic/go-lookslike/testslike"
+	"github.com/elastic/go-lookslike/validator"
+
+	"github.com/elastic/beats/v7/heartbeat/hbtest"
+	"github.com/elastic/beats/v7/heartbeat/hbtestllext"
+)
+
+// startServer runs a UDP server answering every datagram with respond. A
+// nil response drops the datagram.
+func startServer(t *testing.T, respond func(req []byte) []byte) uint16 {
+	t.Helper()
+
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { pc.Close() })
+
+	go func() {
+		buf := make([]byte, maxDatagramSize)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if resp := respond(bytes.Clone(buf[:n])); resp != nil {
+				_, _ = pc.WriteTo(resp, addr)
+			}
+		}
+	}()
+
+	return uint16(pc.LocalAddr().(*net.UDPAddr).Port)
+}
+
+func echo(req []byte) []byte { return req }
+
+func respondingUDPChecks(attempts int) validator.Validator {
+	return lookslike.MustCompile(map[string]interface{}{
+		"udp.rtt.connect.us":  hbtestllext.IsInt64,
+		"udp.rtt.validate.us": hbtestllext.IsInt64,
+		"udp.attempts":        attempts,
+	})
+}
+
+func TestUpEndpointJob(t *testing.T) {
+	port := startServer(t, echo)
+	u := &url.URL{Scheme: "udp", Host: net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port)))}
+
+	for name, check := range map[string]mapstr.M{
+		"send only":        {"check.send": "ping"},
+		"send and receive": {"check.send": "ping", "check.receive": "pi"},
+		"hex":              {"check.send_hex": "de ad be ef", "check.receive_hex": "DEAD"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := mapstr.M{"hosts": "127.0.0.1", "ports": port, "timeout": "1s"}
+			config.DeepUpdate(check)
+			event := hbtest.RunMonitorCheck(t, create, "udp", config)
+
+			testslike.Test(t, lookslike.Compose(
+				hbtest.BaseChecks("127.0.0.1", "up", "udp"),
+				hbtest.SummaryStateChecks(1, 0),
+				hbtest.URLChecks(t, u),
+				respondingUDPChecks(1),
+			), event.Fields)
+		})
+	}
+}
+
+func TestReceiveMismatch(t *testing.T) {
+	port := startServer(t, func([]byte) []byte { return []byte("pong") })
+
+	event := hbtest.RunMonitorCheck(t, create, "udp", mapstr.M{
+		"hosts":         "127.0.0.1:" + strconv.Itoa(int(port)),
+		"check.send":    "ping",
+		"check.receive": "ping",
+		"timeout":       "1s",
+	})
+
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("127.0.0.1", "down", "udp"),
+		hbtest.SummaryStateChecks(0, 1),
+		hbtest.ErrorChecks(errRecvMismatch.Error(), "validate"),
+		respondingUDPChecks(1),
+	), event.Fields)
+}
+
+func TestRetries(t *testing.T) {
+	// only answer the third datagram
+	var received int
+	port := startServer(t, func(req []byte) []byte {
+		received++
+		if received < 3 {
+			return nil
+		}
+		return req
+	})
+
+	config := mapstr.M{
+		"hosts":      "127.0.0.1:" + strconv.Itoa(int(port)),
+		"check.send": "ping",
+		"timeout":    "100ms",
+		"retries":    1,
+	}
+	event := hbtest.RunMonitorCheck(t, create, "udp", config)
+
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("127.0.0.1", "down", "udp"),
+		hbtest.SummaryStateChecks(0, 1),
+		hbtest.ErrorChecks("no response received after 2 attempt(s)", "io"),
+		lookslike.MustCompile(map[string]interface{}{"udp.attempts": 2}),
+	), event.Fields)
+
+	event = hbtest.RunMonitorCheck(t, create, "udp", config)
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("127.0.0.1", "up", "udp"),
+		hbtest.SummaryStateChecks(1, 0),
+		respondingUDPChecks(1),
+	), event.Fields)
+}
+
+func TestPortUnreachable(t *testing.T) {
+	// grab a free port and close it so nothing is listening there
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := pc.LocalAddr().String()
+	require.NoError(t, pc.Close())
+
+	event := hbtest.RunMonitorCheck(t, create, "udp", mapstr.M{
+		"hosts":      addr,
+		"check.send": "ping",
+		"timeout":    "1s",
+	})
+
+	testslike.Test(t, lookslike.Compose(
+		hbtest.BaseChecks("127.0.0.1", "down", "udp"),
+		hbtest.SummaryStateChecks(0, 1),
+		hbtest.ErrorChecks("connection refused", "io"),
+	), event.Fields)
+}
+
+func TestConfigValidate(t *testing.T) {
+	for name, c := range map[string]mapstr.M{
+		"missing send":       {},
+		"send conflict":      {"check.send": "a", "check.send_hex": "61"},
+		"receive conflict":   {"check.send": "a", "check.receive": "a", "check.receive_hex": "61"},
+		"invalid hex":        {"check.send_hex": "zz"},
+		"negative retries":   {"check.send": "a", "retries": -1},
+		"unsupported scheme": {"check.send": "a", "hosts": "tcp://127.0.0.1:53"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config := mapstr.M{"hosts": "127.0.0.1:53"}
+			config.DeepUpdate(c)
+			cfg, err := conf.NewConfigFrom(config)
+			require.NoError(t, err)
+
+			_, err = create("udp", cfg)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestDecodeHex(t *testing.T) {
+	b, err := decodeHex("check.send_hex", "1b 00\n0000")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1b, 0, 0, 0}, b)
+}
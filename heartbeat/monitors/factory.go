@@ -53,10 +53,15 @@ type RunnerFactory struct {
 	logger                *logp.Logger
 	pipelineClientFactory PipelineClientFactory
 	beatLocation          *config.LocationWithID
+	clientWrappers        []ClientWrapper
 }
 
 type PipelineClientFactory func(pipeline beat.Pipeline) (beat.Client, error)
 
+// ClientWrapper decorates the pipeline client of a monitor, e.g. to observe
+// the events it publishes.
+type ClientWrapper func(client beat.Client, sf stdfields.StdMonitorFields, cfg *conf.C) (beat.Client, error)
+
 type publishSettings struct {
 	// Fields and tags to add to monitor.
 	EventMetadata mapstr.EventMetadata    `config:",inline"`
@@ -83,6 +88,8 @@ type FactoryParams struct {
 	PluginsReg            *plugin.PluginsReg
 	PipelineClientFactory PipelineClientFactory
 	BeatRunFrom           *config.LocationWithID
+	// ClientWrappers are applied in order to the pipeline client of every monitor.
+	ClientWrappers []ClientWrapper
 }
 
 // NewFactory takes a scheduler and creates a RunnerFactory that can create cfgfile.Runner(Monitor) objects.
@@ -97,6 +104,7 @@ func NewFactory(fp FactoryParams) *RunnerFactory {
 		pipelineClientFactory: fp.PipelineClientFactory,
 		beatLocation:          fp.BeatRunFrom,
 		stateLoader:           fp.StateLoader,
+		clientWrappers:        fp.ClientWrappers,
 	}
 }
 
@@ -164,6 +172,12 @@ func (f *RunnerFactory) Create(p beat.Pipeline, c *conf.C) (cfgfile.Runner, erro
 	if err != nil {
 		return nil, fmt.Errorf("could not load stdfields in factory: %w", err)
 	}
+	for _, wrap := range f.clientWrappers {
+		pc, err = wrap(pc, sf, c)
+		if err != nil {
+			return nil, fmt.Errorf("could not wrap pipeline client for monitor: %w", err)
+		}
+	}
 	loc := getLocation(f.beatLocation, sf)
 	if loc != nil {
 		geoMap, _ := util.GeoConfigToMap(loc.Geo)
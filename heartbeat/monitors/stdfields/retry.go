@@ -0,0 +1,101 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package stdfields
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryBackoff is the delay before retrying a down check. It starts at Init
+// and doubles for every further attempt, up to Max.
+type RetryBackoff struct {
+	Init time.Duration `config:"init" validate:"min=0"`
+	Max  time.Duration `config:"max" validate:"min=0"`
+}
+
+// DefaultRetryBackoff is used unless a monitor configures its retry_backoff.
+func DefaultRetryBackoff() RetryBackoff {
+	return RetryBackoff{Init: time.Second, Max: 10 * time.Second}
+}
+
+// Delay returns the delay before the attempt following the given one.
+func (rb RetryBackoff) Delay(attempt uint16) time.Duration {
+	delay := rb.Init
+	for i := uint16(1); i < attempt && delay < rb.Max; i++ {
+		delay *= 2
+	}
+	if delay > rb.Max {
+		return rb.Max
+	}
+	return delay
+}
+
+// Quorum is the number of endpoints of a check, e.g. the resolved IPs of a
+// host, that need to be down for the check to be down. It is either a count,
+// or a percentage of the endpoints checked if suffixed with `%`. The zero
+// value requires a single endpoint to be down.
+type Quorum struct {
+	Count   uint16
+	Percent float64
+}
+
+// Unpack parses the quorum from its config value.
+func (q *Quorum) Unpack(s string) error {
+	s = strings.TrimSpace(s)
+	if percent, ok := strings.CutSuffix(s, "%"); ok {
+		p, err := strconv.ParseFloat(strings.TrimSpace(percent), 64)
+		if err != nil || p <= 0 || p > 100 {
+			return fmt.Errorf("invalid quorum percentage '%s', must be in (0%%, 100%%]", s)
+		}
+		*q = Quorum{Percent: p}
+		return nil
+	}
+
+	count, err := strconv.ParseUint(s, 10, 16)
+	if err != nil || count == 0 {
+		return fmt.Errorf("invalid quorum '%s', must be a positive number of endpoints or a percentage", s)
+	}
+	*q = Quorum{Count: uint16(count)}
+	return nil
+}
+
+// Reached reports whether down out of total endpoints reach the quorum. A
+// check with all its endpoints down always reaches it.
+func (q Quorum) Reached(down, total uint16) bool {
+	if down == 0 {
+		return false
+	}
+
+	required := float64(q.Count)
+	if q.Percent > 0 {
+		required = math.Ceil(q.Percent / 100 * float64(total))
+	}
+	required = math.Max(math.Min(required, float64(total)), 1)
+	return float64(down) >= required
+}
+
+func (q Quorum) String() string {
+	if q.Percent > 0 {
+		return strconv.FormatFloat(q.Percent, 'f', -1, 64) + "%"
+	}
+	return strconv.Itoa(int(math.Max(float64(q.Count), 1)))
+}
@@ -44,6 +44,8 @@ type StdMonitorFields struct {
 	Origin             string        `config:"origin"`
 	LegacyServiceName  string        `config:"service_name"`
 	MaxAttempts        uint16        `config:"max_attempts"`
+	RetryBackoff       RetryBackoff  `config:"retry_backoff"`
+	DownQuorum         Quorum        `config:"down_quorum"`
 	// Used by zip_url and local monitors
 	// kibana originating monitors only run one journey at a time
 	// and just use the `fields` syntax / manually set monitor IDs
@@ -61,11 +63,14 @@ type StdMonitorFields struct {
 }
 
 func ConfigToStdMonitorFields(conf *config.C) (StdMonitorFields, error) {
-	sFields := StdMonitorFields{Enabled: true, MaxAttempts: 1}
+	sFields := StdMonitorFields{Enabled: true, MaxAttempts: 1, RetryBackoff: DefaultRetryBackoff()}
 
 	if err := conf.Unpack(&sFields); err != nil {
 		return sFields, fmt.Errorf("error unpacking monitor plugin config: %w", err)
 	}
+	if sFields.RetryBackoff.Max < sFields.RetryBackoff.Init {
+		sFields.RetryBackoff.Max = sFields.RetryBackoff.Init
+	}
 
 	// Use `service_name` if `service.name` is unspecified
 	// `service_name` was only document in the 7.10.0 release.
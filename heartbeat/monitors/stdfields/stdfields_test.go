@@ -20,6 +20,7 @@ package stdfields
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -65,3 +66,92 @@ func TestLegacyServiceNameConfig(t *testing.T) {
 	}
 
 }
+
+func TestRetryConfig(t *testing.T) {
+	base := mapstr.M{"type": "tcp", "id": "myId", "schedule": "@every 1s"}
+
+	tests := []struct {
+		name    string
+		cfg     mapstr.M
+		backoff RetryBackoff
+		quorum  Quorum
+		err     bool
+	}{
+		{"defaults", mapstr.M{}, DefaultRetryBackoff(), Quorum{}, false},
+		{
+			"custom backoff",
+			mapstr.M{"retry_backoff": mapstr.M{"init": "100ms", "max": "1s"}},
+			RetryBackoff{Init: 100 * time.Millisecond, Max: time.Second},
+			Quorum{},
+			false,
+		},
+		{
+			"init above default max",
+			mapstr.M{"retry_backoff": mapstr.M{"init": "30s"}},
+			RetryBackoff{Init: 30 * time.Second, Max: 30 * time.Second},
+			Quorum{},
+			false,
+		},
+		{"quorum count", mapstr.M{"down_quorum": 2}, DefaultRetryBackoff(), Quorum{Count: 2}, false},
+		{"quorum percentage", mapstr.M{"down_quorum": "50%"}, DefaultRetryBackoff(), Quorum{Percent: 50}, false},
+		{"zero quorum", mapstr.M{"down_quorum": 0}, RetryBackoff{}, Quorum{}, true},
+		{"quorum above 100%", mapstr.M{"down_quorum": "150%"}, RetryBackoff{}, Quorum{}, true},
+		{"invalid quorum", mapstr.M{"down_quorum": "most"}, RetryBackoff{}, Quorum{}, true},
+		{"negative backoff", mapstr.M{"retry_backoff.init": "-1s"}, RetryBackoff{}, Quorum{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := conf.NewConfigFrom(base)
+			require.NoError(t, err)
+			require.NoError(t, c.Merge(tt.cfg))
+
+			f, err := ConfigToStdMonitorFields(c)
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.backoff, f.RetryBackoff)
+			require.Equal(t, tt.quorum, f.DownQuorum)
+		})
+	}
+}
+
+func TestRetryBackoffDelay(t *testing.T) {
+	rb := RetryBackoff{Init: time.Second, Max: 5 * time.Second}
+	require.Equal(t, time.Second, rb.Delay(1))
+	require.Equal(t, 2*time.Second, rb.Delay(2))
+	require.Equal(t, 4*time.Second, rb.Delay(3))
+	require.Equal(t, 5*time.Second, rb.Delay(4))
+	require.Equal(t, 5*time.Second, rb.Delay(100))
+
+	require.Equal(t, time.Duration(0), RetryBackoff{}.Delay(3))
+}
+
+func TestQuorumReached(t *testing.T) {
+	tests := []struct {
+		quorum      Quorum
+		down, total uint16
+		reached     bool
+	}{
+		{Quorum{}, 0, 3, false},
+		{Quorum{}, 1, 3, true},
+		{Quorum{Count: 2}, 1, 3, false},
+		{Quorum{Count: 2}, 2, 3, true},
+		// all endpoints down always reaches the quorum
+		{Quorum{Count: 3}, 2, 2, true},
+		{Quorum{Count: 3}, 1, 2, false},
+		{Quorum{Percent: 50}, 1, 3, false},
+		{Quorum{Percent: 50}, 2, 3, true},
+		{Quorum{Percent: 50}, 1, 2, true},
+		{Quorum{Percent: 100}, 3, 4, false},
+		{Quorum{Percent: 1}, 1, 50, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s of %d/%d down", tt.quorum, tt.down, tt.total), func(t *testing.T) {
+			require.Equal(t, tt.reached, tt.quorum.Reached(tt.down, tt.total))
+		})
+	}
+}
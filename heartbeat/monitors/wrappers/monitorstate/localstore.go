@@ -0,0 +1,158 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package monitorstate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/elastic/beats/v7/heartbeat/monitors/stdfields"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/statestore"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp"
+)
+
+// LocalStore persists the states of the monitors in a local statestore, so
+// they can be loaded after a restart whatever the output is.
+type LocalStore struct {
+	store  *statestore.Store
+	ttl    time.Duration
+	logger *logp.Logger
+}
+
+type storedState struct {
+	State   *State    `struct:"state"`
+	Updated time.Time `struct:"updated"`
+}
+
+// NewLocalStore returns a LocalStore keeping the states in store. States not
+// updated within ttl are not loaded anymore, a zero ttl keeps them forever.
+func NewLocalStore(store *statestore.Store, ttl time.Duration) *LocalStore {
+	return &LocalStore{
+		store:  store,
+		ttl:    ttl,
+		logger: logp.L().Named("monitorstate"),
+	}
+}
+
+// localStoreKey mirrors the ES loader, which looks for states by monitor ID
+// and type within the location of the beat.
+func localStoreKey(sf stdfields.StdMonitorFields) string {
+	rfid := "default"
+	if sf.RunFrom != nil {
+		rfid = normalizeRunFromIDRegexp.ReplaceAllString(sf.RunFrom.ID, "_")
+	}
+	return fmt.Sprintf("%s::%s::%s", rfid, sf.Type, sf.ID)
+}
+
+// Load is a StateLoader returning the last state stored for the monitor.
+func (ls *LocalStore) Load(sf stdfields.StdMonitorFields) (*State, error) {
+	key := localStoreKey(sf)
+	found, err := ls.store.Has(key)
+	if err != nil {
+		return nil, LoaderError{err: fmt.Errorf("could not look up state of %s in local store: %w", sf.ID, err)}
+	}
+	if !found {
+		ls.logger.Infof("no previous state found for monitor %s in local store", sf.ID)
+		return nil, nil
+	}
+
+	var stored storedState
+	if err := ls.store.Get(key, &stored); err != nil {
+		return nil, LoaderError{err: fmt.Errorf("could not read state of %s from local store: %w", sf.ID, err)}
+	}
+	if ls.expired(stored) {
+		ls.logger.Infof("previous state of monitor %s in local store is older than %s, ignoring it", sf.ID, ls.ttl)
+		return nil, nil
+	}
+	return stored.State, nil
+}
+
+// Save stores state as the last state of the monitor.
+func (ls *LocalStore) Save(sf stdfields.StdMonitorFields, state *State) error {
+	return ls.store.Set(localStoreKey(sf), storedState{State: state, Updated: time.Now()})
+}
+
+// Cleanup removes the states that expired, e.g. those of deleted monitors.
+func (ls *LocalStore) Cleanup() error {
+	var expired []string
+	err := ls.store.Each(func(key string, dec statestore.ValueDecoder) (bool, error) {
+		var stored storedState
+		if err := dec.Decode(&stored); err != nil || ls.expired(stored) {
+			expired = append(expired, key)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, key := range expired {
+		if err := ls.store.Remove(key); err != nil {
+			return err
+		}
+	}
+	if len(expired) > 0 {
+		ls.logger.Infof("removed %d expired monitor states from local store", len(expired))
+	}
+	return nil
+}
+
+func (ls *LocalStore) expired(stored storedState) bool {
+	return stored.State == nil || (ls.ttl > 0 && time.Since(stored.Updated) > ls.ttl)
+}
+
+// Close closes the underlying store.
+func (ls *LocalStore) Close() error {
+	return ls.store.Close()
+}
+
+// WrapClient returns a client publishing to client that saves the states
+// found in the published summaries of the monitor.
+func (ls *LocalStore) WrapClient(client beat.Client, sf stdfields.StdMonitorFields, _ *conf.C) (beat.Client, error) {
+	return &storingClient{Client: client, store: ls, sf: sf}, nil
+}
+
+type storingClient struct {
+	beat.Client
+	store *LocalStore
+	sf    stdfields.StdMonitorFields
+}
+
+func (c *storingClient) Publish(event beat.Event) {
+	c.save(&event)
+	c.Client.Publish(event)
+}
+
+func (c *storingClient) PublishAll(events []beat.Event) {
+	for i := range events {
+		c.save(&events[i])
+	}
+	c.Client.PublishAll(events)
+}
+
+func (c *storingClient) save(event *beat.Event) {
+	state, ok := event.Fields["state"].(*State)
+	if !ok || state == nil {
+		return
+	}
+	if err := c.store.Save(c.sf, state); err != nil {
+		c.store.logger.Warnf("could not save state of monitor %s to local store: %v", c.sf.ID, err)
+	}
+}
@@ -0,0 +1,149 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package monitorstate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/heartbeat/config"
+	"github.com/elastic/beats/v7/heartbeat/monitors/stdfields"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	pubtest "github.com/elastic/beats/v7/libbeat/publisher/testing"
+	"github.com/elastic/beats/v7/libbeat/statestore"
+	"github.com/elastic/beats/v7/libbeat/statestore/backend/memlog"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// openLocalStore opens a memlog backed store in dir, closing it at the end
+// of the test if it was not closed before.
+func openLocalStore(t *testing.T, dir string, ttl time.Duration) *LocalStore {
+	t.Helper()
+
+	backend, err := memlog.New(logp.NewLogger("test"), memlog.Settings{Root: dir})
+	require.NoError(t, err)
+	registry := statestore.NewRegistry(backend)
+	store, err := registry.Get("heartbeat")
+	require.NoError(t, err)
+
+	ls := NewLocalStore(store, ttl)
+	t.Cleanup(func() {
+		_ = ls.Close()
+		_ = registry.Close()
+	})
+	return ls
+}
+
+func TestLocalStoreRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	ls := openLocalStore(t, dir, time.Hour)
+	mst := NewTracker(ls.Load, false)
+	client, err := ls.WrapClient(&pubtest.FakeClient{}, TestSf, nil)
+	require.NoError(t, err)
+
+	ms := mst.RecordStatus(TestSf, StatusUp, true)
+	client.Publish(beat.Event{Fields: mapstr.M{"state": ms}})
+	for i := 0; i < 3; i++ {
+		ms = mst.RecordStatus(TestSf, StatusDown, true)
+		client.Publish(beat.Event{Fields: mapstr.M{"state": ms}})
+	}
+	require.NoError(t, ls.Close())
+
+	// A new tracker, as after a restart, continues the stored state
+	restarted := openLocalStore(t, dir, time.Hour)
+	loaded, err := restarted.Load(TestSf)
+	require.NoError(t, err)
+	require.NotNil(t, loaded)
+	require.Equal(t, ms.ID, loaded.ID)
+	require.True(t, ms.StartedAt.Equal(loaded.StartedAt))
+	require.Nil(t, loaded.Ends)
+	requireMSCounts(t, loaded, 0, 3)
+
+	mst = NewTracker(restarted.Load, false)
+	next := mst.RecordStatus(TestSf, StatusDown, true)
+	require.Equal(t, ms.ID, next.ID)
+	requireMSCounts(t, next, 0, 4)
+	require.GreaterOrEqual(t, next.DurationMs, ms.DurationMs)
+}
+
+func TestLocalStoreKeys(t *testing.T) {
+	ls := openLocalStore(t, t.TempDir(), time.Hour)
+
+	state := newMonitorState(TestSf, StatusUp, 0, false)
+	require.NoError(t, ls.Save(TestSf, state))
+
+	others := []stdfields.StdMonitorFields{
+		{ID: "otherID", Type: TestSf.Type},
+		{ID: TestSf.ID, Type: "otherType"},
+		{ID: TestSf.ID, Type: TestSf.Type, RunFrom: &config.LocationWithID{ID: "other location"}},
+	}
+	for _, sf := range others {
+		loaded, err := ls.Load(sf)
+		require.NoError(t, err)
+		require.Nil(t, loaded, "state of %v", sf)
+	}
+
+	loaded, err := ls.Load(TestSf)
+	require.NoError(t, err)
+	require.Equal(t, state.ID, loaded.ID)
+}
+
+func TestLocalStoreTTL(t *testing.T) {
+	ls := openLocalStore(t, t.TempDir(), time.Hour)
+
+	stale := stdfields.StdMonitorFields{ID: "stale", Type: TestSf.Type}
+	require.NoError(t, ls.Save(TestSf, newMonitorState(TestSf, StatusUp, 0, false)))
+	require.NoError(t, ls.store.Set(localStoreKey(stale), storedState{
+		State:   newMonitorState(stale, StatusDown, 0, false),
+		Updated: time.Now().Add(-2 * time.Hour),
+	}))
+
+	loaded, err := ls.Load(stale)
+	require.NoError(t, err)
+	require.Nil(t, loaded)
+
+	require.NoError(t, ls.Cleanup())
+	found, err := ls.store.Has(localStoreKey(stale))
+	require.NoError(t, err)
+	require.False(t, found)
+	found, err = ls.store.Has(localStoreKey(TestSf))
+	require.NoError(t, err)
+	require.True(t, found)
+}
+
+func TestLocalStoreIgnoresEventsWithoutState(t *testing.T) {
+	ls := openLocalStore(t, t.TempDir(), time.Hour)
+	var published int
+	inner := &pubtest.FakeClient{PublishFunc: func(beat.Event) { published++ }}
+	client, err := ls.WrapClient(inner, TestSf, nil)
+	require.NoError(t, err)
+
+	client.PublishAll([]beat.Event{
+		{Fields: mapstr.M{"monitor": mapstr.M{"status": "up"}}},
+		{Fields: mapstr.M{"state": (*State)(nil)}},
+	})
+	require.Equal(t, 2, published)
+
+	found, err := ls.store.Has(localStoreKey(TestSf))
+	require.NoError(t, err)
+	require.False(t, found)
+}
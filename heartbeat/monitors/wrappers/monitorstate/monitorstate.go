@@ -60,19 +60,20 @@ func newMonitorState(sf stdfields.StdMonitorFields, status StateStatus, ctr int,
 }
 
 type State struct {
-	ID string `json:"id"`
+	ID string `json:"id" struct:"id"`
 	// StartedAt is the start time of the state, should be the same for a given state ID
-	StartedAt  time.Time   `json:"started_at"`
-	DurationMs int64       `json:"duration_ms,string"`
-	Status     StateStatus `json:"status"`
-	Checks     int         `json:"checks"`
-	Up         int         `json:"up"`
-	Down       int         `json:"down"`
+	StartedAt  time.Time   `json:"started_at" struct:"started_at"`
+	DurationMs int64       `json:"duration_ms,string" struct:"duration_ms"`
+	Status     StateStatus `json:"status" struct:"status"`
+	Checks     int         `json:"checks" struct:"checks"`
+	Up         int         `json:"up" struct:"up"`
+	Down       int         `json:"down" struct:"down"`
 	// FlapHistory retains enough info so we can resume our flap
 	// computation if loading from ES or another source
-	FlapHistory []StateStatus `json:"flap_history"`
-	// Ends is a pointer to the prior state if this is the start of a new state
-	Ends            *State `json:"ends"`
+	FlapHistory []StateStatus `json:"flap_history" struct:"flap_history"`
+	// Ends is a pointer to the prior state if this is the start of a new state,
+	// it is only relevant to that check and not kept by the LocalStore.
+	Ends            *State `json:"ends" struct:"-"`
 	flappingEnabled bool
 	ctr             int
 }
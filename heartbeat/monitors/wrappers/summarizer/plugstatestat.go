@@ -122,6 +122,7 @@ type commonSSP struct {
 	stateTracker *monitorstate.Tracker
 	sf           stdfields.StdMonitorFields
 	checkGroup   string
+	lastStatus   monitorstate.StateStatus
 }
 
 func newCommonSSP(stateTracker *monitorstate.Tracker, sf stdfields.StdMonitorFields) *commonSSP {
@@ -143,15 +144,20 @@ func (ssp *commonSSP) BeforeEach(event *beat.Event, err error) {
 }
 
 func (ssp *commonSSP) BeforeSummary(event *beat.Event) BeforeSummaryActions {
-	if ssp.js.Down > 0 {
+	// a check is down once enough of its endpoints are down
+	if ssp.sf.DownQuorum.Reached(ssp.js.Down, ssp.js.Up+ssp.js.Down) {
 		ssp.js.Status = monitorstate.StatusDown
 	} else {
 		ssp.js.Status = monitorstate.StatusUp
 	}
 
-	// Get the last status of this monitor, we use this later to
-	// determine if a retry is needed
-	lastStatus := ssp.stateTracker.GetCurrentStatus(ssp.sf)
+	// Get the last status of this monitor before the current check, we use this
+	// later to determine if a retry is needed. Retries record their status, so
+	// it must be read on the first attempt only.
+	if ssp.js.Attempt == 1 {
+		ssp.lastStatus = ssp.stateTracker.GetCurrentStatus(ssp.sf)
+	}
+	lastStatus := ssp.lastStatus
 
 	curCheckDown := ssp.js.Status == monitorstate.StatusDown
 	lastStateUpOrEmpty := lastStatus == monitorstate.StatusUp || lastStatus == monitorstate.StatusEmpty
@@ -37,7 +37,8 @@ type Summarizer struct {
 	mtx            *sync.Mutex
 	sf             stdfields.StdMonitorFields
 	mst            *monitorstate.Tracker
-	retryDelay     time.Duration
+	retryBackoff   stdfields.RetryBackoff
+	attempt        uint16
 	plugins        []SummarizerPlugin
 	startedAt      time.Time
 }
@@ -83,7 +84,8 @@ func NewSummarizer(rootJob jobs.Job, sf stdfields.StdMonitorFields, mst *monitor
 		mtx:            &sync.Mutex{},
 		mst:            mst,
 		sf:             sf,
-		retryDelay:     time.Second,
+		retryBackoff:   sf.RetryBackoff,
+		attempt:        1,
 		startedAt:      time.Now(),
 	}
 	s.setupPlugins()
@@ -152,13 +154,15 @@ func (s *Summarizer) Wrap(j jobs.Job) jobs.Job {
 				// Bump the job summary for the next attempt
 				s.contsRemaining = 1
 
-				// Delay retries by 1s for two reasons:
+				// Delay retries, by 1s and backing off by default, for two reasons:
 				// 1. Since ES timestamps are millisecond resolution they can happen so fast
 				//    that it's hard to tell the sequence in which jobs executed apart in our
 				//    kibana queries
-				// 2. If the site error is very short 1s gives it a tiny bit of time to recover
+				// 2. If the site error is very short the delay gives it a bit of time to recover
+				retryDelay := s.retryBackoff.Delay(s.attempt)
+				s.attempt++
 				delayedRootJob := func(event *beat.Event) ([]jobs.Job, error) {
-					time.Sleep(s.retryDelay)
+					time.Sleep(retryDelay)
 					for _, p := range s.plugins {
 						p.BeforeRetry()
 					}
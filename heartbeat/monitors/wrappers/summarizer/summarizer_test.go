@@ -113,6 +113,24 @@ func TestSummarizer(t *testing.T) {
 			9,
 			testURL,
 		},
+		{
+			"start up - go down with two retries - then recover",
+			3,
+			"uddddduu",
+			"uuuddduu",
+			"11231111",
+			8,
+			testURL,
+		},
+		{
+			"start up, transient down over two attempts, recover",
+			3,
+			"uuduuu",
+			"uuuuuu",
+			"111211",
+			6,
+			testURL,
+		},
 		{
 			"no retries, single down",
 			1,
@@ -162,7 +180,7 @@ func TestSummarizer(t *testing.T) {
 			for {
 				s := NewSummarizer(job, sf, tracker)
 				// Shorten retry delay to make tests run faster
-				s.retryDelay = 2 * time.Millisecond
+				s.retryBackoff = stdfields.RetryBackoff{Init: 2 * time.Millisecond, Max: 2 * time.Millisecond}
 				wrapped := s.Wrap(job)
 				events, _ := jobs.ExecJobAndConts(t, wrapped)
 				for _, event := range events {
@@ -284,7 +302,7 @@ func TestSummarizerPluginOrder(t *testing.T) {
 
 			s := NewSummarizer(job, sf, tracker)
 			// Shorten retry delay to make tests run faster
-			s.retryDelay = 2 * time.Millisecond
+			s.retryBackoff = stdfields.RetryBackoff{Init: 2 * time.Millisecond, Max: 2 * time.Millisecond}
 			// Add mock plugin
 			s.plugins = append(s.plugins, &MockPlugin{
 				eachEvent: func(_ *beat.Event, _ error) {
@@ -345,7 +363,7 @@ func TestRetryLightweightMonitorDuration(t *testing.T) {
 
 	s := NewSummarizer(job, sf, tracker)
 	// Shorten retry delay to make tests run faster
-	s.retryDelay = 2 * time.Millisecond
+	s.retryBackoff = stdfields.RetryBackoff{Init: 2 * time.Millisecond, Max: 2 * time.Millisecond}
 	// Add mock plugin
 	s.plugins = append(s.plugins, &MockPlugin{
 		beforeRetry: func() {
@@ -376,6 +394,101 @@ func TestRetryLightweightMonitorDuration(t *testing.T) {
 	require.GreaterOrEqual(t, look.RTTMS(retryElapsed), rcvdDuration)
 }
 
+func TestSummarizerDownQuorum(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		quorum stdfields.Quorum
+		// the status of each endpoint checked
+		endpoints string
+		expected  monitorstate.StateStatus
+	}{
+		{"default, one down", stdfields.Quorum{}, "uud", monitorstate.StatusDown},
+		{"count, below quorum", stdfields.Quorum{Count: 2}, "uud", monitorstate.StatusUp},
+		{"count, quorum reached", stdfields.Quorum{Count: 2}, "udd", monitorstate.StatusDown},
+		{"count, all down", stdfields.Quorum{Count: 5}, "dd", monitorstate.StatusDown},
+		{"percentage, below quorum", stdfields.Quorum{Percent: 50}, "uuud", monitorstate.StatusUp},
+		{"percentage, quorum reached", stdfields.Quorum{Percent: 50}, "uudd", monitorstate.StatusDown},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// each endpoint is checked by a continuation of the root job
+			endpointJob := func(c uint8) jobs.Job {
+				return func(event *beat.Event) ([]jobs.Job, error) {
+					event.Fields = mapstr.M{"monitor": mapstr.M{"id": "test"}}
+					if c == 'd' {
+						return nil, fmt.Errorf("endpoint down")
+					}
+					return nil, nil
+				}
+			}
+			job := func(event *beat.Event) ([]jobs.Job, error) {
+				var conts []jobs.Job
+				for i := 1; i < len(tt.endpoints); i++ {
+					conts = append(conts, endpointJob(tt.endpoints[i]))
+				}
+				_, err := endpointJob(tt.endpoints[0])(event)
+				return conts, err
+			}
+
+			tracker := monitorstate.NewTracker(monitorstate.NilStateLoader, false)
+			sf := stdfields.StdMonitorFields{ID: "testmon", Name: "testmon", Type: "tcp", MaxAttempts: 1, DownQuorum: tt.quorum}
+			s := NewSummarizer(job, sf, tracker)
+			events, _ := jobs.ExecJobAndConts(t, s.Wrap(job))
+			require.Len(t, events, len(tt.endpoints))
+
+			var summaries []*jobsummary.JobSummary
+			for _, event := range events {
+				if summary, ok := event.Fields["summary"].(*jobsummary.JobSummary); ok {
+					summaries = append(summaries, summary)
+				}
+			}
+			require.Len(t, summaries, 1)
+			require.Equal(t, tt.expected, summaries[0].Status)
+			require.Equal(t, tt.expected, tracker.GetCurrentStatus(sf))
+		})
+	}
+}
+
+func TestSummarizerRetryBackoff(t *testing.T) {
+	t.Parallel()
+
+	tracker := monitorstate.NewTracker(monitorstate.NilStateLoader, false)
+	backoff := stdfields.RetryBackoff{Init: 20 * time.Millisecond, Max: time.Second}
+	sf := stdfields.StdMonitorFields{ID: "testmon", Name: "testmon", Type: "http", MaxAttempts: 3, RetryBackoff: backoff}
+
+	var jobEnds, retryStarts []time.Time
+	job := func(event *beat.Event) ([]jobs.Job, error) {
+		event.Fields = mapstr.M{"monitor": mapstr.M{"id": "test"}}
+		jobEnds = append(jobEnds, time.Now())
+		return nil, fmt.Errorf("dummyerr")
+	}
+
+	s := NewSummarizer(job, sf, tracker)
+	s.plugins = append(s.plugins, &MockPlugin{
+		beforeRetry: func() {
+			retryStarts = append(retryStarts, time.Now())
+		},
+		eachEvent:       func(_ *beat.Event, _ error) {},
+		beforeSummary:   func(_ *beat.Event) {},
+		beforeEachEvent: func(_ *beat.Event) {},
+	})
+
+	events, _ := jobs.ExecJobAndConts(t, s.Wrap(job))
+	require.Len(t, events, 3)
+	require.Len(t, retryStarts, 2)
+
+	// the delay doubles for every attempt
+	require.GreaterOrEqual(t, retryStarts[0].Sub(jobEnds[0]), backoff.Delay(1))
+	require.GreaterOrEqual(t, retryStarts[1].Sub(jobEnds[1]), backoff.Delay(2))
+	require.Equal(t, 40*time.Millisecond, backoff.Delay(2))
+}
+
 type MockPlugin struct {
 	eachEvent       func(e *beat.Event, err error)
 	beforeSummary   func(e *beat.Event)
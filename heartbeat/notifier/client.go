@@ -0,0 +1,240 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"sync"
+	"time"
+
+	"github.com/elastic/beats/v7/heartbeat/ecserr"
+	"github.com/elastic/beats/v7/heartbeat/monitors/stdfields"
+	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers/monitorstate"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// WrapClient returns a client publishing to client that notifies about the
+// state transitions of the monitor found in the published summaries. The
+// `notifications` section of the monitor config can disable notifications
+// or override the throttle.
+func (n *Notifier) WrapClient(client beat.Client, sf stdfields.StdMonitorFields, cfg *conf.C) (beat.Client, error) {
+	mc := monitorConfig{Enabled: true}
+	if cfg.HasField("notifications") {
+		sub, err := cfg.Child("notifications", -1)
+		if err != nil {
+			return nil, err
+		}
+		if err := sub.Unpack(&mc); err != nil {
+			return nil, err
+		}
+	}
+	if !mc.Enabled {
+		return client, nil
+	}
+
+	throttle := n.config.Throttle
+	if mc.Throttle != nil {
+		throttle = *mc.Throttle
+	}
+	return &notifyingClient{
+		Client:  client,
+		monitor: newMonitorNotifier(n, sf, throttle),
+	}, nil
+}
+
+type notifyingClient struct {
+	beat.Client
+	monitor *monitorNotifier
+}
+
+func (c *notifyingClient) Publish(event beat.Event) {
+	c.monitor.observe(&event)
+	c.Client.Publish(event)
+}
+
+func (c *notifyingClient) PublishAll(events []beat.Event) {
+	for i := range events {
+		c.monitor.observe(&events[i])
+	}
+	c.Client.PublishAll(events)
+}
+
+func (c *notifyingClient) Close() error {
+	c.monitor.stop()
+	return c.Client.Close()
+}
+
+// monitorNotifier tracks the state of a single monitor across published
+// summaries and throttles its notifications.
+type monitorNotifier struct {
+	mtx      sync.Mutex
+	notifier *Notifier
+	sf       stdfields.StdMonitorFields
+	throttle time.Duration
+
+	// last is the status of the latest summary.
+	last monitorstate.StateStatus
+	// notified is the status of the latest notification sent.
+	notified monitorstate.StateStatus
+	lastSent time.Time
+
+	// pending is the latest throttled notification, it is sent once the
+	// throttle expires if the monitor did not return to the notified status.
+	pending    *Notification
+	suppressed int
+	timer      *time.Timer
+	stopped    bool
+}
+
+func newMonitorNotifier(n *Notifier, sf stdfields.StdMonitorFields, throttle time.Duration) *monitorNotifier {
+	return &monitorNotifier{notifier: n, sf: sf, throttle: throttle}
+}
+
+// observe looks for a state transition in a summary event.
+func (m *monitorNotifier) observe(event *beat.Event) {
+	v, err := event.GetValue("state")
+	if err != nil {
+		return
+	}
+	state, ok := v.(*monitorstate.State)
+	if !ok || state == nil {
+		return
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	previous, changed := m.transition(state)
+	if !changed || m.stopped {
+		return
+	}
+	m.dispatch(newNotification(event, m.sf, state, previous))
+}
+
+// transition records the status of state and reports whether it differs
+// from the previous one. The first summary after startup only counts as a
+// transition if it starts a new state, unless the monitor is initially up.
+func (m *monitorNotifier) transition(state *monitorstate.State) (previous monitorstate.StateStatus, changed bool) {
+	previous = m.last
+	m.last = state.Status
+
+	if previous == monitorstate.StatusEmpty {
+		if state.Checks != 1 {
+			// continuing a state loaded from a previous run
+			return previous, false
+		}
+		if state.Ends != nil {
+			previous = state.Ends.Status
+		} else if state.Status == monitorstate.StatusUp {
+			// a new monitor coming up is not news
+			return previous, false
+		}
+	}
+	return previous, previous != state.Status
+}
+
+func (m *monitorNotifier) dispatch(n *Notification) {
+	now := time.Now()
+	if m.lastSent.IsZero() || now.Sub(m.lastSent) >= m.throttle {
+		m.send(n, now)
+		return
+	}
+
+	m.suppressed++
+	m.pending = n
+	if m.timer == nil {
+		m.timer = time.AfterFunc(m.lastSent.Add(m.throttle).Sub(now), m.flush)
+	}
+}
+
+// flush sends the pending notification once the throttle expired.
+func (m *monitorNotifier) flush() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.timer = nil
+	pending := m.pending
+	if pending == nil || m.stopped {
+		return
+	}
+	if monitorstate.StateStatus(pending.Status) == m.notified {
+		// Back to the notified status, the throttled changes cancel out.
+		m.notifier.logger.Debugf("dropping %d throttled notifications for monitor %s, its status is still %s", m.suppressed, m.sf.ID, m.notified)
+		m.pending = nil
+		m.suppressed = 0
+		return
+	}
+	m.suppressed--
+	m.send(pending, time.Now())
+}
+
+func (m *monitorNotifier) send(n *Notification, now time.Time) {
+	n.Suppressed = m.suppressed
+	m.pending = nil
+	m.suppressed = 0
+	m.lastSent = now
+	m.notified = monitorstate.StateStatus(n.Status)
+	m.notifier.notify(n)
+}
+
+func (m *monitorNotifier) stop() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.stopped = true
+	if m.timer != nil {
+		m.timer.Stop()
+		m.timer = nil
+	}
+}
+
+func newNotification(event *beat.Event, sf stdfields.StdMonitorFields, state *monitorstate.State, previous monitorstate.StateStatus) *Notification {
+	n := &Notification{
+		Status:         string(state.Status),
+		PreviousStatus: string(previous),
+		Monitor:        NotificationMeta{ID: sf.ID, Name: sf.Name, Type: sf.Type},
+		StateID:        state.ID,
+		StartedAt:      state.StartedAt,
+		Timestamp:      event.Timestamp,
+	}
+	switch state.Status {
+	case monitorstate.StatusUp:
+		n.Transition = TransitionUp
+	case monitorstate.StatusFlapping:
+		n.Transition = TransitionFlapping
+	default:
+		n.Transition = TransitionDown
+	}
+	if n.Timestamp.IsZero() {
+		n.Timestamp = time.Now()
+	}
+	if v, err := event.GetValue("url.full"); err == nil {
+		n.URL, _ = v.(string)
+	}
+	// The summarizer sets either an ECS error or a reason map.
+	if v, err := event.GetValue("error"); err == nil {
+		switch e := v.(type) {
+		case *ecserr.ECSErr:
+			n.Error = e.Message
+		case mapstr.M:
+			n.Error, _ = e["message"].(string)
+		}
+	}
+	return n
+}
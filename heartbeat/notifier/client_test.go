@@ -0,0 +1,256 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/heartbeat/ecserr"
+	"github.com/elastic/beats/v7/heartbeat/monitors/stdfields"
+	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers/monitorstate"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	pubtest "github.com/elastic/beats/v7/libbeat/publisher/testing"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+type recordingSender struct {
+	notifications chan *Notification
+}
+
+func (s *recordingSender) Send(_ context.Context, n *Notification) error {
+	s.notifications <- n
+	return nil
+}
+
+func (s *recordingSender) String() string {
+	return "recording"
+}
+
+func newTestNotifier(t *testing.T, throttle time.Duration) (*Notifier, <-chan *Notification) {
+	t.Helper()
+
+	rec := &recordingSender{notifications: make(chan *Notification, 10)}
+	n := newNotifier(Config{Throttle: throttle, QueueSize: 10}, []target{{
+		sender:  rec,
+		on:      map[string]bool{TransitionDown: true, TransitionUp: true, TransitionFlapping: true},
+		timeout: time.Second,
+	}})
+	t.Cleanup(n.Close)
+	return n, rec.notifications
+}
+
+var testSF = stdfields.StdMonitorFields{ID: "mon", Name: "My Monitor", Type: "http"}
+
+// testMonitor publishes a summary for every recorded status, like the
+// summarizer does.
+type testMonitor struct {
+	tracker *monitorstate.Tracker
+	client  beat.Client
+}
+
+func newTestMonitor(t *testing.T, n *Notifier, cfg mapstr.M, flapping bool) *testMonitor {
+	t.Helper()
+
+	c, err := conf.NewConfigFrom(cfg)
+	require.NoError(t, err)
+	client, err := n.WrapClient(&pubtest.FakeClient{}, testSF, c)
+	require.NoError(t, err)
+	return &testMonitor{tracker: monitorstate.NewTracker(nil, flapping), client: client}
+}
+
+func (m *testMonitor) check(status monitorstate.StateStatus) {
+	fields := mapstr.M{
+		"state":   m.tracker.RecordStatus(testSF, status, true),
+		"url":     mapstr.M{"full": "http://localhost:1234"},
+		"summary": mapstr.M{},
+	}
+	if status == monitorstate.StatusDown {
+		fields["error"] = ecserr.NewECSErr("io", "NET_COULD_NOT_CONNECT", "connection refused")
+	}
+	m.client.Publish(beat.Event{Timestamp: time.Now(), Fields: fields})
+}
+
+func requireNotification(t *testing.T, ch <-chan *Notification, transition, previous string) *Notification {
+	t.Helper()
+
+	select {
+	case n := <-ch:
+		require.Equal(t, transition, n.Transition)
+		require.Equal(t, previous, n.PreviousStatus)
+		return n
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "expected a notification", "transition %s", transition)
+		return nil
+	}
+}
+
+func requireNoNotification(t *testing.T, ch <-chan *Notification, wait time.Duration) {
+	t.Helper()
+
+	select {
+	case n := <-ch:
+		require.FailNow(t, "unexpected notification", "%+v", n)
+	case <-time.After(wait):
+	}
+}
+
+func TestTransitions(t *testing.T) {
+	n, ch := newTestNotifier(t, 0)
+	m := newTestMonitor(t, n, mapstr.M{}, false)
+
+	m.check(monitorstate.StatusUp)
+	m.check(monitorstate.StatusUp)
+	m.check(monitorstate.StatusDown)
+	down := requireNotification(t, ch, TransitionDown, "up")
+	assert.Equal(t, "down", down.Status)
+	assert.Equal(t, NotificationMeta{ID: "mon", Name: "My Monitor", Type: "http"}, down.Monitor)
+	assert.Equal(t, "http://localhost:1234", down.URL)
+	assert.Equal(t, "connection refused", down.Error)
+	assert.NotEmpty(t, down.StateID)
+
+	m.check(monitorstate.StatusDown)
+	m.check(monitorstate.StatusUp)
+	up := requireNotification(t, ch, TransitionUp, "down")
+	assert.Empty(t, up.Error)
+	assert.NotEqual(t, down.StateID, up.StateID)
+
+	requireNoNotification(t, ch, 50*time.Millisecond)
+}
+
+func TestInitiallyDown(t *testing.T) {
+	n, ch := newTestNotifier(t, 0)
+	m := newTestMonitor(t, n, mapstr.M{}, false)
+
+	m.check(monitorstate.StatusDown)
+	requireNotification(t, ch, TransitionDown, "")
+}
+
+func TestFlapping(t *testing.T) {
+	n, ch := newTestNotifier(t, 0)
+	m := newTestMonitor(t, n, mapstr.M{}, true)
+
+	m.check(monitorstate.StatusUp)
+	m.check(monitorstate.StatusDown)
+	requireNotification(t, ch, TransitionFlapping, "up")
+
+	// still flapping
+	m.check(monitorstate.StatusUp)
+	requireNoNotification(t, ch, 50*time.Millisecond)
+
+	for i := 0; i < monitorstate.FlappingThreshold; i++ {
+		m.check(monitorstate.StatusDown)
+	}
+	requireNotification(t, ch, TransitionDown, "flap")
+}
+
+func TestThrottle(t *testing.T) {
+	t.Run("latest change sent after throttle", func(t *testing.T) {
+		n, ch := newTestNotifier(t, 200*time.Millisecond)
+		m := newTestMonitor(t, n, mapstr.M{}, false)
+
+		m.check(monitorstate.StatusUp)
+		m.check(monitorstate.StatusDown)
+		requireNotification(t, ch, TransitionDown, "up")
+
+		m.check(monitorstate.StatusUp)
+		m.check(monitorstate.StatusDown)
+		m.check(monitorstate.StatusUp)
+		requireNoNotification(t, ch, 50*time.Millisecond)
+
+		up := requireNotification(t, ch, TransitionUp, "down")
+		assert.Equal(t, 2, up.Suppressed)
+	})
+
+	t.Run("changes cancelling out are dropped", func(t *testing.T) {
+		n, ch := newTestNotifier(t, 100*time.Millisecond)
+		m := newTestMonitor(t, n, mapstr.M{}, false)
+
+		m.check(monitorstate.StatusDown)
+		requireNotification(t, ch, TransitionDown, "")
+
+		m.check(monitorstate.StatusUp)
+		m.check(monitorstate.StatusDown)
+		requireNoNotification(t, ch, 300*time.Millisecond)
+	})
+
+	t.Run("per monitor override", func(t *testing.T) {
+		n, ch := newTestNotifier(t, time.Hour)
+		m := newTestMonitor(t, n, mapstr.M{"notifications.throttle": "0s"}, false)
+
+		m.check(monitorstate.StatusDown)
+		requireNotification(t, ch, TransitionDown, "")
+		m.check(monitorstate.StatusUp)
+		requireNotification(t, ch, TransitionUp, "down")
+	})
+
+	t.Run("pending dropped on close", func(t *testing.T) {
+		n, ch := newTestNotifier(t, 100*time.Millisecond)
+		m := newTestMonitor(t, n, mapstr.M{}, false)
+
+		m.check(monitorstate.StatusDown)
+		requireNotification(t, ch, TransitionDown, "")
+		m.check(monitorstate.StatusUp)
+		require.NoError(t, m.client.Close())
+		requireNoNotification(t, ch, 300*time.Millisecond)
+	})
+}
+
+func TestWrapClient(t *testing.T) {
+	n, _ := newTestNotifier(t, 0)
+
+	t.Run("disabled", func(t *testing.T) {
+		client := &pubtest.FakeClient{}
+		c, err := conf.NewConfigFrom(mapstr.M{"notifications.enabled": false})
+		require.NoError(t, err)
+
+		wrapped, err := n.WrapClient(client, testSF, c)
+		require.NoError(t, err)
+		require.Same(t, client, wrapped)
+	})
+
+	t.Run("forwards events", func(t *testing.T) {
+		var published int
+		var closed bool
+		client := &pubtest.FakeClient{
+			PublishFunc: func(beat.Event) { published++ },
+			CloseFunc:   func() error { closed = true; return nil },
+		}
+		wrapped, err := n.WrapClient(client, testSF, conf.NewConfig())
+		require.NoError(t, err)
+
+		wrapped.Publish(beat.Event{Fields: mapstr.M{}})
+		wrapped.PublishAll([]beat.Event{{Fields: mapstr.M{}}, {Fields: mapstr.M{}}})
+		require.NoError(t, wrapped.Close())
+		require.Equal(t, 3, published)
+		require.True(t, closed)
+	})
+
+	t.Run("invalid throttle", func(t *testing.T) {
+		c, err := conf.NewConfigFrom(mapstr.M{"notifications.throttle": "-1s"})
+		require.NoError(t, err)
+
+		_, err = n.WrapClient(&pubtest.FakeClient{}, testSF, c)
+		require.Error(t, err)
+	})
+}
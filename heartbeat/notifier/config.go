@@ -0,0 +1,97 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+)
+
+// Transitions that notifications can be sent for.
+const (
+	TransitionDown     = "down"
+	TransitionUp       = "up"
+	TransitionFlapping = "flapping"
+)
+
+// Config defines the `heartbeat.notifications` section of heartbeat.yml.
+type Config struct {
+	// Throttle is the minimum time between two notifications for the same
+	// monitor. Monitors can override it with `notifications.throttle`.
+	Throttle time.Duration `config:"throttle"`
+	// QueueSize is the number of notifications waiting to be sent before
+	// new ones are dropped.
+	QueueSize int       `config:"queue_size" validate:"min=1"`
+	Notifiers []*conf.C `config:"notifiers" validate:"required"`
+}
+
+func defaultConfig() Config {
+	return Config{
+		Throttle:  5 * time.Minute,
+		QueueSize: 100,
+	}
+}
+
+func (c *Config) Validate() error {
+	if c.Throttle < 0 {
+		return errors.New("throttle must not be negative")
+	}
+	return nil
+}
+
+// targetConfig holds the settings shared by all notifier types.
+type targetConfig struct {
+	Type string `config:"type" validate:"required"`
+	// On lists the transitions to notify about, all of them if empty.
+	On      []string      `config:"on"`
+	Timeout time.Duration `config:"timeout"`
+}
+
+func defaultTargetConfig() targetConfig {
+	return targetConfig{Timeout: 30 * time.Second}
+}
+
+func (c *targetConfig) Validate() error {
+	for _, t := range c.On {
+		switch t {
+		case TransitionDown, TransitionUp, TransitionFlapping:
+		default:
+			return fmt.Errorf("unknown transition '%s', valid transitions are %s, %s and %s", t, TransitionDown, TransitionUp, TransitionFlapping)
+		}
+	}
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be greater than 0")
+	}
+	return nil
+}
+
+// monitorConfig is the `notifications` section of a monitor.
+type monitorConfig struct {
+	Enabled  bool           `config:"enabled"`
+	Throttle *time.Duration `config:"throttle"`
+}
+
+func (c *monitorConfig) Validate() error {
+	if c.Throttle != nil && *c.Throttle < 0 {
+		return errors.New("notifications.throttle must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,259 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package notifier sends notifications to local webhooks, scripts or SMTP
+// relays when the state of a monitor changes, for setups without an
+// alerting system reading the published events.
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp"
+)
+
+// Notification describes a state transition of a monitor.
+type Notification struct {
+	Transition     string           `json:"transition"`
+	Status         string           `json:"status"`
+	PreviousStatus string           `json:"previous_status,omitempty"`
+	Monitor        NotificationMeta `json:"monitor"`
+	URL            string           `json:"url,omitempty"`
+	Error          string           `json:"error,omitempty"`
+	StateID        string           `json:"state_id"`
+	StartedAt      time.Time        `json:"started_at"`
+	Timestamp      time.Time        `json:"@timestamp"`
+	// Suppressed is the number of transitions that were not notified since
+	// the previous notification because of throttling.
+	Suppressed int `json:"suppressed"`
+}
+
+// NotificationMeta identifies the monitor a notification is about.
+type NotificationMeta struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+	Type string `json:"type"`
+}
+
+// Subject is a one line summary of the notification.
+func (n *Notification) Subject() string {
+	name := n.Monitor.Name
+	if name == "" {
+		name = n.Monitor.ID
+	}
+	return fmt.Sprintf("[Heartbeat] %s is %s", name, strings.ToUpper(n.Transition))
+}
+
+// Text is a plain text description of the notification.
+func (n *Notification) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Monitor: %s (%s)\n", n.Monitor.ID, n.Monitor.Type)
+	if n.Monitor.Name != "" {
+		fmt.Fprintf(&b, "Name: %s\n", n.Monitor.Name)
+	}
+	if n.URL != "" {
+		fmt.Fprintf(&b, "URL: %s\n", n.URL)
+	}
+	fmt.Fprintf(&b, "Status: %s", n.Status)
+	if n.PreviousStatus != "" {
+		fmt.Fprintf(&b, " (was %s)", n.PreviousStatus)
+	}
+	b.WriteString("\n")
+	if n.Error != "" {
+		fmt.Fprintf(&b, "Error: %s\n", n.Error)
+	}
+	fmt.Fprintf(&b, "Since: %s\n", n.StartedAt.Format(time.RFC3339))
+	if n.Suppressed > 0 {
+		fmt.Fprintf(&b, "Suppressed: %d earlier state changes were not notified\n", n.Suppressed)
+	}
+	return b.String()
+}
+
+// sender delivers notifications to a single destination.
+type sender interface {
+	Send(ctx context.Context, n *Notification) error
+	String() string
+}
+
+var senderTypes = map[string]func(cfg *conf.C) (sender, error){
+	"webhook": newWebhookSender,
+	"script":  newScriptSender,
+	"smtp":    newSMTPSender,
+}
+
+// closeTimeout bounds the time Close waits for queued notifications to be
+// sent.
+const closeTimeout = 10 * time.Second
+
+// target is a configured sender along with the transitions it wants.
+type target struct {
+	sender  sender
+	on      map[string]bool
+	timeout time.Duration
+}
+
+// Notifier sends the notifications of all monitors to the configured
+// targets. Notifications are queued and sent from a single goroutine so
+// that slow targets never delay monitor checks.
+type Notifier struct {
+	config  Config
+	targets []target
+	queue   chan *Notification
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+	wg      sync.WaitGroup
+	logger  *logp.Logger
+
+	closeTimeout time.Duration
+}
+
+// New creates a Notifier from the `heartbeat.notifications` configuration
+// and starts sending notifications. Close must be called to stop it.
+func New(cfg *conf.C) (*Notifier, error) {
+	config := defaultConfig()
+	if err := cfg.Unpack(&config); err != nil {
+		return nil, err
+	}
+
+	targets := make([]target, 0, len(config.Notifiers))
+	for i, tcfg := range config.Notifiers {
+		t, err := newTarget(tcfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notifier %d: %w", i, err)
+		}
+		targets = append(targets, t)
+	}
+
+	return newNotifier(config, targets), nil
+}
+
+func newNotifier(config Config, targets []target) *Notifier {
+	n := &Notifier{
+		config:  config,
+		targets: targets,
+		queue:   make(chan *Notification, config.QueueSize),
+		done:    make(chan struct{}),
+		logger:  logp.NewLogger("notifier"),
+
+		closeTimeout: closeTimeout,
+	}
+	n.ctx, n.cancel = context.WithCancel(context.Background())
+
+	n.wg.Add(1)
+	go n.run()
+	return n
+}
+
+func newTarget(cfg *conf.C) (target, error) {
+	tc := defaultTargetConfig()
+	if err := cfg.Unpack(&tc); err != nil {
+		return target{}, err
+	}
+
+	factory, ok := senderTypes[tc.Type]
+	if !ok {
+		return target{}, fmt.Errorf("unknown notifier type '%s'", tc.Type)
+	}
+	s, err := factory(cfg)
+	if err != nil {
+		return target{}, fmt.Errorf("%s notifier: %w", tc.Type, err)
+	}
+
+	on := tc.On
+	if len(on) == 0 {
+		on = []string{TransitionDown, TransitionUp, TransitionFlapping}
+	}
+	t := target{sender: s, on: map[string]bool{}, timeout: tc.Timeout}
+	for _, transition := range on {
+		t.on[transition] = true
+	}
+	return t, nil
+}
+
+// Close stops accepting notifications and sends the queued ones. Sending
+// is aborted if the queue is not drained after closeTimeout, the remaining
+// notifications are dropped.
+func (n *Notifier) Close() {
+	close(n.done)
+	timer := time.AfterFunc(n.closeTimeout, n.cancel)
+	defer timer.Stop()
+
+	n.wg.Wait()
+	n.cancel()
+}
+
+// notify queues a notification, it is dropped if the queue is full.
+func (n *Notifier) notify(notification *Notification) {
+	select {
+	case <-n.done:
+	case n.queue <- notification:
+	default:
+		n.logger.Warnf("notification queue is full, dropping %s notification for monitor %s", notification.Transition, notification.Monitor.ID)
+	}
+}
+
+func (n *Notifier) run() {
+	defer n.wg.Done()
+	for {
+		select {
+		case <-n.done:
+			n.drain()
+			return
+		case notification := <-n.queue:
+			n.send(notification)
+		}
+	}
+}
+
+// drain sends the queued notifications until the queue is empty or
+// sending is aborted.
+func (n *Notifier) drain() {
+	for n.ctx.Err() == nil {
+		select {
+		case notification := <-n.queue:
+			n.send(notification)
+		default:
+			return
+		}
+	}
+	if dropped := len(n.queue); dropped > 0 {
+		n.logger.Warnf("dropping %d queued notifications on close", dropped)
+	}
+}
+
+func (n *Notifier) send(notification *Notification) {
+	for _, t := range n.targets {
+		if !t.on[notification.Transition] {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(n.ctx, t.timeout)
+		err := t.sender.Send(ctx, notification)
+		cancel()
+		if err != nil {
+			n.logger.Errorf("could not send %s notification for monitor %s to %s: %v", notification.Transition, notification.Monitor.ID, t.sender, err)
+			continue
+		}
+		n.logger.Debugf("sent %s notification for monitor %s to %s", notification.Transition, notification.Monitor.ID, t.sender)
+	}
+}
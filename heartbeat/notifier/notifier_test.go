@@ -0,0 +1,196 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+func TestNew(t *testing.T) {
+	scenarios := []struct {
+		name   string
+		config mapstr.M
+		err    string
+	}{
+		{
+			name: "valid",
+			config: mapstr.M{
+				"throttle": "10m",
+				"notifiers": []mapstr.M{
+					{"type": "webhook", "url": "http://localhost:9000/hook", "on": []string{"down"}},
+					{"type": "script", "path": "sh", "args": []string{"-c", "true"}},
+					{"type": "smtp", "host": "localhost", "from": "Heartbeat <hb@example.com>", "to": []string{"ops@example.com"}},
+				},
+			},
+		},
+		{
+			name:   "no notifiers",
+			config: mapstr.M{"throttle": "10m"},
+			err:    "missing required field",
+		},
+		{
+			name:   "unknown type",
+			config: mapstr.M{"notifiers": []mapstr.M{{"type": "pager"}}},
+			err:    "unknown notifier type 'pager'",
+		},
+		{
+			name:   "unknown transition",
+			config: mapstr.M{"notifiers": []mapstr.M{{"type": "webhook", "url": "http://localhost", "on": []string{"sideways"}}}},
+			err:    "unknown transition 'sideways'",
+		},
+		{
+			name:   "webhook without url",
+			config: mapstr.M{"notifiers": []mapstr.M{{"type": "webhook"}}},
+			err:    "string value is not set accessing 'notifiers.0.url'",
+		},
+		{
+			name:   "webhook with invalid scheme",
+			config: mapstr.M{"notifiers": []mapstr.M{{"type": "webhook", "url": "ftp://localhost"}}},
+			err:    "scheme must be http or https",
+		},
+		{
+			name:   "missing script",
+			config: mapstr.M{"notifiers": []mapstr.M{{"type": "script", "path": "/does/not/exist"}}},
+			err:    "no such file or directory",
+		},
+		{
+			name:   "invalid mail address",
+			config: mapstr.M{"notifiers": []mapstr.M{{"type": "smtp", "host": "localhost", "from": "heartbeat", "to": []string{"ops@example.com"}}}},
+			err:    "invalid from address 'heartbeat'",
+		},
+		{
+			name:   "negative throttle",
+			config: mapstr.M{"throttle": "-1m", "notifiers": []mapstr.M{{"type": "script", "path": "sh"}}},
+			err:    "throttle must not be negative",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			cfg, err := conf.NewConfigFrom(s.config)
+			require.NoError(t, err)
+
+			n, err := New(cfg)
+			if s.err != "" {
+				require.ErrorContains(t, err, s.err)
+				return
+			}
+			require.NoError(t, err)
+			defer n.Close()
+
+			require.Equal(t, 10*time.Minute, n.config.Throttle)
+			require.Len(t, n.targets, 3)
+			require.Equal(t, map[string]bool{TransitionDown: true}, n.targets[0].on)
+			require.Len(t, n.targets[1].on, 3)
+		})
+	}
+}
+
+func TestNotificationText(t *testing.T) {
+	started := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	n := &Notification{
+		Transition:     TransitionDown,
+		Status:         "down",
+		PreviousStatus: "up",
+		Monitor:        NotificationMeta{ID: "mon", Name: "My Monitor", Type: "http"},
+		URL:            "http://localhost:1234",
+		Error:          "connection refused",
+		StartedAt:      started,
+		Suppressed:     2,
+	}
+
+	require.Equal(t, "[Heartbeat] My Monitor is DOWN", n.Subject())
+	require.Equal(t, `Monitor: mon (http)
+Name: My Monitor
+URL: http://localhost:1234
+Status: down (was up)
+Error: connection refused
+Since: 2024-05-01T10:00:00Z
+Suppressed: 2 earlier state changes were not notified
+`, n.Text())
+}
+
+// blockingSender records notifications, blocking until release is closed or
+// sending is aborted.
+type blockingSender struct {
+	release chan struct{}
+	sent    []*Notification
+}
+
+func (s *blockingSender) Send(ctx context.Context, n *Notification) error {
+	select {
+	case <-s.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	s.sent = append(s.sent, n)
+	return nil
+}
+
+func (s *blockingSender) String() string {
+	return "blocking"
+}
+
+func newBlockingNotifier(closeTimeout time.Duration) (*Notifier, *blockingSender) {
+	s := &blockingSender{release: make(chan struct{})}
+	n := newNotifier(Config{QueueSize: 10}, []target{{
+		sender:  s,
+		on:      map[string]bool{TransitionDown: true},
+		timeout: time.Minute,
+	}})
+	n.closeTimeout = closeTimeout
+	return n, s
+}
+
+func TestCloseSendsQueued(t *testing.T) {
+	n, s := newBlockingNotifier(time.Minute)
+	for _, id := range []string{"a", "b", "c"} {
+		n.notify(&Notification{Transition: TransitionDown, Monitor: NotificationMeta{ID: id}})
+	}
+
+	time.AfterFunc(50*time.Millisecond, func() { close(s.release) })
+	n.Close()
+
+	require.Len(t, s.sent, 3)
+	for i, id := range []string{"a", "b", "c"} {
+		require.Equal(t, id, s.sent[i].Monitor.ID)
+	}
+
+	// notifications are dropped once closed
+	n.notify(&Notification{Transition: TransitionDown, Monitor: NotificationMeta{ID: "d"}})
+	require.Len(t, s.sent, 3)
+}
+
+func TestCloseTimeout(t *testing.T) {
+	n, s := newBlockingNotifier(50 * time.Millisecond)
+	for _, id := range []string{"a", "b", "c"} {
+		n.notify(&Notification{Transition: TransitionDown, Monitor: NotificationMeta{ID: id}})
+	}
+
+	start := time.Now()
+	n.Close()
+	require.Less(t, time.Since(start), 5*time.Second)
+	require.Empty(t, s.sent)
+}
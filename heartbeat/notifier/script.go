@@ -0,0 +1,94 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+)
+
+// maxScriptOutput is the part of the script output included in errors.
+const maxScriptOutput = 1024
+
+type scriptConfig struct {
+	Path string   `config:"path" validate:"required"`
+	Args []string `config:"args"`
+}
+
+// scriptSender runs a program for every notification. The notification is
+// passed as a JSON document on stdin and in HEARTBEAT_* environment
+// variables.
+type scriptSender struct {
+	path string
+	args []string
+}
+
+func newScriptSender(cfg *conf.C) (sender, error) {
+	var config scriptConfig
+	if err := cfg.Unpack(&config); err != nil {
+		return nil, err
+	}
+	path, err := exec.LookPath(config.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &scriptSender{path: path, args: config.Args}, nil
+}
+
+func (s *scriptSender) Send(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, s.path, s.args...)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Env = append(os.Environ(),
+		"HEARTBEAT_TRANSITION="+n.Transition,
+		"HEARTBEAT_STATUS="+n.Status,
+		"HEARTBEAT_PREVIOUS_STATUS="+n.PreviousStatus,
+		"HEARTBEAT_MONITOR_ID="+n.Monitor.ID,
+		"HEARTBEAT_MONITOR_NAME="+n.Monitor.Name,
+		"HEARTBEAT_MONITOR_TYPE="+n.Monitor.Type,
+		"HEARTBEAT_URL="+n.URL,
+		"HEARTBEAT_ERROR="+n.Error,
+		"HEARTBEAT_SUPPRESSED="+strconv.Itoa(n.Suppressed),
+	)
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		// Keep the log readable if the script is chatty.
+		if len(out) > maxScriptOutput {
+			out = out[:maxScriptOutput]
+		}
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (s *scriptSender) String() string {
+	return "script " + s.path
+}
@@ -0,0 +1,69 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !windows
+
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+func TestScript(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "notify.sh")
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+cat > "$1/notification.json"
+echo "$HEARTBEAT_TRANSITION $HEARTBEAT_MONITOR_ID $HEARTBEAT_ERROR" > "$1/env"
+`), 0o700))
+
+	cfg, err := conf.NewConfigFrom(mapstr.M{"path": script, "args": []string{dir}})
+	require.NoError(t, err)
+	s, err := newScriptSender(cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Send(context.Background(), testNotification()))
+
+	env, err := os.ReadFile(filepath.Join(dir, "env"))
+	require.NoError(t, err)
+	require.Equal(t, "down mon connection refused\n", string(env))
+
+	raw, err := os.ReadFile(filepath.Join(dir, "notification.json"))
+	require.NoError(t, err)
+	var got Notification
+	require.NoError(t, json.Unmarshal(raw, &got))
+	require.Equal(t, *testNotification(), got)
+}
+
+func TestScriptFailure(t *testing.T) {
+	cfg, err := conf.NewConfigFrom(mapstr.M{"path": "sh", "args": []string{"-c", "echo no pager configured; exit 3"}})
+	require.NoError(t, err)
+	s, err := newScriptSender(cfg)
+	require.NoError(t, err)
+
+	err = s.Send(context.Background(), testNotification())
+	require.ErrorContains(t, err, "exit status 3: no pager configured")
+}
@@ -0,0 +1,166 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/transport/tlscommon"
+)
+
+type smtpConfig struct {
+	// Host is the relay to send mails through, as `host:port`. The port
+	// defaults to 25.
+	Host     string            `config:"host" validate:"required"`
+	From     string            `config:"from" validate:"required"`
+	To       []string          `config:"to" validate:"required"`
+	Username string            `config:"username"`
+	Password string            `config:"password"`
+	TLS      *tlscommon.Config `config:"ssl"`
+}
+
+func (c *smtpConfig) Validate() error {
+	if _, err := mail.ParseAddress(c.From); err != nil {
+		return fmt.Errorf("invalid from address '%s': %w", c.From, err)
+	}
+	for _, to := range c.To {
+		if _, err := mail.ParseAddress(to); err != nil {
+			return fmt.Errorf("invalid to address '%s': %w", to, err)
+		}
+	}
+	return nil
+}
+
+// smtpSender mails notifications through an SMTP relay. STARTTLS is used
+// when the relay supports it, and required if `ssl` is configured.
+type smtpSender struct {
+	config    smtpConfig
+	from      string
+	to        []string
+	addr      string
+	hostname  string
+	tlsConfig *tlscommon.TLSConfig
+}
+
+func newSMTPSender(cfg *conf.C) (sender, error) {
+	var config smtpConfig
+	if err := cfg.Unpack(&config); err != nil {
+		return nil, err
+	}
+
+	addr := config.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "25")
+	}
+	hostname, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid host '%s': %w", config.Host, err)
+	}
+
+	tlsConfig, err := tlscommon.LoadTLSConfig(config.TLS)
+	if err != nil {
+		return nil, err
+	}
+	// The envelope only takes the bare addresses.
+	s := &smtpSender{config: config, addr: addr, hostname: hostname, tlsConfig: tlsConfig}
+	from, _ := mail.ParseAddress(config.From)
+	s.from = from.Address
+	for _, to := range config.To {
+		a, _ := mail.ParseAddress(to)
+		s.to = append(s.to, a.Address)
+	}
+	return s, nil
+}
+
+func (s *smtpSender) Send(ctx context.Context, n *Notification) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, s.hostname)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(s.tlsConfig.BuildModuleClientConfig(s.hostname)); err != nil {
+			return err
+		}
+	} else if s.tlsConfig != nil {
+		return errors.New("relay does not support STARTTLS")
+	}
+
+	if s.config.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", s.config.Username, s.config.Password, s.hostname)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.from); err != nil {
+		return err
+	}
+	for _, to := range s.to {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(s.message(n)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (s *smtpSender) message(n *Notification) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", s.config.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(s.config.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", n.Subject()))
+	fmt.Fprintf(&b, "Date: %s\r\n", n.Timestamp.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(n.Text(), "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+func (s *smtpSender) String() string {
+	return "smtp " + s.addr
+}
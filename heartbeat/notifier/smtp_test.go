@@ -0,0 +1,132 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+type receivedMail struct {
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPServer runs a minimal SMTP relay accepting a single mail.
+func startSMTPServer(t *testing.T) (string, <-chan receivedMail) {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	mails := make(chan receivedMail, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		tp := textproto.NewConn(conn)
+		var mail receivedMail
+		_ = tp.PrintfLine("220 localhost ESMTP test")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch {
+			case cmd == "EHLO" || cmd == "HELO":
+				_ = tp.PrintfLine("250 localhost")
+			case strings.HasPrefix(strings.ToUpper(line), "MAIL FROM:"):
+				mail.from = line[len("MAIL FROM:"):]
+				_ = tp.PrintfLine("250 OK")
+			case strings.HasPrefix(strings.ToUpper(line), "RCPT TO:"):
+				mail.to = append(mail.to, line[len("RCPT TO:"):])
+				_ = tp.PrintfLine("250 OK")
+			case cmd == "DATA":
+				_ = tp.PrintfLine("354 go ahead")
+				lines, err := tp.ReadDotLines()
+				if err != nil {
+					return
+				}
+				mail.data = strings.Join(lines, "\n")
+				_ = tp.PrintfLine("250 OK")
+				mails <- mail
+			case cmd == "QUIT":
+				_ = tp.PrintfLine("221 bye")
+				return
+			default:
+				_ = tp.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+
+	return l.Addr().String(), mails
+}
+
+func TestSMTP(t *testing.T) {
+	addr, mails := startSMTPServer(t)
+
+	cfg, err := conf.NewConfigFrom(mapstr.M{
+		"host": addr,
+		"from": "Heartbeat <heartbeat@example.com>",
+		"to":   []string{"ops@example.com", "Oncall <oncall@example.com>"},
+	})
+	require.NoError(t, err)
+	s, err := newSMTPSender(cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Send(context.Background(), testNotification()))
+
+	mail := <-mails
+	require.Equal(t, "<heartbeat@example.com>", mail.from)
+	require.Equal(t, []string{"<ops@example.com>", "<oncall@example.com>"}, mail.to)
+	require.Contains(t, mail.data, "From: Heartbeat <heartbeat@example.com>\n")
+	require.Contains(t, mail.data, "To: ops@example.com, Oncall <oncall@example.com>\n")
+	require.Contains(t, mail.data, "Subject: [Heartbeat] My Monitor is DOWN\n")
+	require.Contains(t, mail.data, "\nError: connection refused\n")
+}
+
+func TestSMTPRequiresSTARTTLSWithSSL(t *testing.T) {
+	addr, _ := startSMTPServer(t)
+
+	cfg, err := conf.NewConfigFrom(mapstr.M{
+		"host":                        addr,
+		"from":                        "heartbeat@example.com",
+		"to":                          []string{"ops@example.com"},
+		"ssl.verification_mode":       "full",
+		"ssl.certificate_authorities": []string{},
+	})
+	require.NoError(t, err)
+	s, err := newSMTPSender(cfg)
+	require.NoError(t, err)
+
+	require.ErrorContains(t, s.Send(context.Background(), testNotification()), "relay does not support STARTTLS")
+}
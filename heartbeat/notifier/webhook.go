@@ -0,0 +1,102 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/elastic/beats/v7/libbeat/version"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/transport/httpcommon"
+	"github.com/elastic/elastic-agent-libs/useragent"
+)
+
+type webhookConfig struct {
+	URL       string                           `config:"url" validate:"required"`
+	Headers   map[string]string                `config:"headers"`
+	Transport httpcommon.HTTPTransportSettings `config:",inline"`
+}
+
+// webhookSender POSTs notifications as JSON documents.
+type webhookSender struct {
+	url     string
+	name    string
+	headers map[string]string
+	client  *http.Client
+}
+
+var userAgent = useragent.UserAgent("Heartbeat", version.GetDefaultVersion(), version.Commit(), version.BuildTime().String())
+
+func newWebhookSender(cfg *conf.C) (sender, error) {
+	config := webhookConfig{Transport: httpcommon.DefaultHTTPTransportSettings()}
+	if err := cfg.Unpack(&config); err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url '%s': %w", config.URL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid url '%s': scheme must be http or https", config.URL)
+	}
+
+	client, err := config.Transport.Client()
+	if err != nil {
+		return nil, err
+	}
+	return &webhookSender{url: config.URL, name: u.Redacted(), headers: config.Headers, client: client}, nil
+}
+
+func (w *webhookSender) Send(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %s", resp.Status)
+	}
+	return nil
+}
+
+func (w *webhookSender) String() string {
+	return "webhook " + w.name
+}
@@ -0,0 +1,98 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+func testNotification() *Notification {
+	return &Notification{
+		Transition:     TransitionDown,
+		Status:         "down",
+		PreviousStatus: "up",
+		Monitor:        NotificationMeta{ID: "mon", Name: "My Monitor", Type: "http"},
+		URL:            "http://localhost:1234",
+		Error:          "connection refused",
+		StateID:        "default-1-1",
+		StartedAt:      time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Timestamp:      time.Date(2024, 5, 1, 10, 0, 1, 0, time.UTC),
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- r
+		bodies <- body
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	cfg, err := conf.NewConfigFrom(mapstr.M{
+		"url":     server.URL + "/hook",
+		"headers": mapstr.M{"Authorization": "Bearer secret"},
+	})
+	require.NoError(t, err)
+	s, err := newWebhookSender(cfg)
+	require.NoError(t, err)
+
+	require.NoError(t, s.Send(context.Background(), testNotification()))
+
+	req := <-requests
+	require.Equal(t, http.MethodPost, req.Method)
+	require.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	require.Equal(t, "Bearer secret", req.Header.Get("Authorization"))
+	require.Contains(t, req.Header.Get("User-Agent"), "Heartbeat")
+
+	var got map[string]interface{}
+	require.NoError(t, json.Unmarshal(<-bodies, &got))
+	require.Equal(t, map[string]interface{}{
+		"transition":      "down",
+		"status":          "down",
+		"previous_status": "up",
+		"monitor":         map[string]interface{}{"id": "mon", "name": "My Monitor", "type": "http"},
+		"url":             "http://localhost:1234",
+		"error":           "connection refused",
+		"state_id":        "default-1-1",
+		"started_at":      "2024-05-01T10:00:00Z",
+		"@timestamp":      "2024-05-01T10:00:01Z",
+		"suppressed":      float64(0),
+	}, got)
+
+	cfg, err = conf.NewConfigFrom(mapstr.M{"url": server.URL + "/fail"})
+	require.NoError(t, err)
+	s, err = newWebhookSender(cfg)
+	require.NoError(t, err)
+	require.ErrorContains(t, s.Send(context.Background(), testNotification()), "500 Internal Server Error")
+}
@@ -0,0 +1,293 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package prometheus exposes the status of the last check of every running
+// monitor in the Prometheus text format.
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/elastic/beats/v7/heartbeat/monitors/stdfields"
+	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers/monitorstate"
+	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers/summarizer/jobsummary"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// Route is the route the registry is served at on the beat HTTP endpoint.
+const Route = "/metrics"
+
+// Registry keeps the status of the monitors and serves it over HTTP.
+type Registry struct {
+	mtx      sync.Mutex
+	monitors map[string]*monitorStatus
+	logger   *logp.Logger
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		monitors: map[string]*monitorStatus{},
+		logger:   logp.L().Named("prometheus"),
+	}
+}
+
+// monitorStatus is the status of a single monitor as of its latest summary.
+type monitorStatus struct {
+	labels []*dto.LabelPair
+
+	checked             bool
+	up                  bool
+	duration            time.Duration
+	lastCheck           time.Time
+	consecutiveFailures int
+	// certNotAfter is the expiry of the last TLS certificate seen, if any.
+	certNotAfter time.Time
+}
+
+// WrapClient returns a client publishing to client that records the status
+// of the monitor found in the published summaries. The monitor is removed
+// from the registry once the client is closed.
+func (r *Registry) WrapClient(client beat.Client, sf stdfields.StdMonitorFields, cfg *conf.C) (beat.Client, error) {
+	labels, err := monitorLabels(sf, cfg)
+	if err != nil {
+		return nil, err
+	}
+	status := &monitorStatus{labels: labels}
+
+	r.mtx.Lock()
+	r.monitors[sf.ID] = status
+	r.mtx.Unlock()
+
+	return &observingClient{Client: client, registry: r, id: sf.ID, status: status}, nil
+}
+
+func (r *Registry) observe(status *monitorStatus, event *beat.Event) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	status.observe(event)
+}
+
+// remove deletes the monitor from the registry unless a newer monitor with
+// the same ID replaced it already.
+func (r *Registry) remove(id string, status *monitorStatus) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.monitors[id] == status {
+		delete(r.monitors, id)
+	}
+}
+
+func (s *monitorStatus) observe(event *beat.Event) {
+	if v, err := event.GetValue("tls.server.x509.not_after"); err == nil {
+		if notAfter, ok := v.(time.Time); ok {
+			s.certNotAfter = notAfter
+		}
+	}
+
+	v, ok := event.Fields["summary"]
+	if !ok {
+		return
+	}
+	// monitor.status of a lightweight summary is the status of its last
+	// endpoint only, the summary holds the status of the whole check.
+	var status interface{}
+	if js, ok := v.(*jobsummary.JobSummary); ok {
+		status = js.Status
+	} else {
+		status, _ = event.GetValue("monitor.status")
+	}
+	s.checked = true
+	s.up = status == monitorstate.StatusUp || status == "up"
+	s.lastCheck = event.Timestamp
+	if s.lastCheck.IsZero() {
+		// lightweight events are only timestamped by the pipeline
+		s.lastCheck = time.Now()
+	}
+	if v, err := event.GetValue("monitor.duration.us"); err == nil {
+		if us, ok := v.(int64); ok {
+			s.duration = time.Duration(us) * time.Microsecond
+		}
+	}
+	if s.up {
+		s.consecutiveFailures = 0
+	} else {
+		s.consecutiveFailures++
+	}
+}
+
+type observingClient struct {
+	beat.Client
+	registry *Registry
+	id       string
+	status   *monitorStatus
+}
+
+func (c *observingClient) Publish(event beat.Event) {
+	c.registry.observe(c.status, &event)
+	c.Client.Publish(event)
+}
+
+func (c *observingClient) PublishAll(events []beat.Event) {
+	for i := range events {
+		c.registry.observe(c.status, &events[i])
+	}
+	c.Client.PublishAll(events)
+}
+
+func (c *observingClient) Close() error {
+	c.registry.remove(c.id, c.status)
+	return c.Client.Close()
+}
+
+// monitorLabels returns the labels identifying the monitor: its ID, name and
+// type followed by the flattened custom `fields` of the monitor.
+func monitorLabels(sf stdfields.StdMonitorFields, cfg *conf.C) ([]*dto.LabelPair, error) {
+	labels := []*dto.LabelPair{
+		labelPair("monitor_id", sf.ID),
+		labelPair("monitor_name", sf.Name),
+		labelPair("monitor_type", sf.Type),
+	}
+
+	settings := struct {
+		Fields mapstr.M `config:"fields"`
+	}{}
+	if err := cfg.Unpack(&settings); err != nil {
+		return nil, fmt.Errorf("could not read fields of monitor: %w", err)
+	}
+
+	var custom []*dto.LabelPair
+	for key, value := range settings.Fields.Flatten() {
+		name := labelName(key)
+		if strings.HasPrefix(name, "__") || strings.HasPrefix(name, "monitor_") {
+			// reserved by Prometheus or clashing with the monitor labels
+			continue
+		}
+		custom = append(custom, labelPair(name, fmt.Sprint(value)))
+	}
+	sort.Slice(custom, func(i, j int) bool {
+		return custom[i].GetName() < custom[j].GetName()
+	})
+	return append(labels, custom...), nil
+}
+
+// labelName turns a flattened field key into a valid label name by replacing
+// all unsupported characters with underscores.
+func labelName(key string) string {
+	name := []byte(key)
+	for i, c := range name {
+		valid := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+		if !valid {
+			name[i] = '_'
+		}
+	}
+	if len(name) > 0 && name[0] >= '0' && name[0] <= '9' {
+		return "_" + string(name)
+	}
+	return string(name)
+}
+
+func labelPair(name, value string) *dto.LabelPair {
+	return &dto.LabelPair{Name: proto.String(name), Value: proto.String(value)}
+}
+
+// ServeHTTP writes the status of all monitors in the Prometheus text format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	format := expfmt.NewFormat(expfmt.TypeTextPlain)
+	w.Header().Set("Content-Type", string(format))
+
+	enc := expfmt.NewEncoder(w, format)
+	for _, mf := range r.gather() {
+		if err := enc.Encode(mf); err != nil {
+			r.logger.Warnf("could not write metric family %s: %v", mf.GetName(), err)
+			return
+		}
+	}
+}
+
+// gather returns a snapshot of the status of all monitors, ordered by
+// monitor ID.
+func (r *Registry) gather() []*dto.MetricFamily {
+	up := gaugeFamily("heartbeat_monitor_up", "Whether the last check of the monitor was up (1) or down (0).")
+	duration := gaugeFamily("heartbeat_monitor_duration_seconds", "Duration of the last check of the monitor.")
+	lastCheck := gaugeFamily("heartbeat_monitor_last_check_timestamp_seconds", "Time of the last check of the monitor since the Unix epoch.")
+	failures := gaugeFamily("heartbeat_monitor_consecutive_failures", "Number of consecutive down checks of the monitor.")
+	certExpiry := gaugeFamily("heartbeat_monitor_tls_cert_not_after_timestamp_seconds", "Expiry of the last TLS certificate seen by the monitor since the Unix epoch.")
+
+	r.mtx.Lock()
+	ids := make([]string, 0, len(r.monitors))
+	for id := range r.monitors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		s := r.monitors[id]
+		if s.checked {
+			upValue := 0.0
+			if s.up {
+				upValue = 1
+			}
+			addGauge(up, s.labels, upValue)
+			addGauge(duration, s.labels, s.duration.Seconds())
+			addGauge(lastCheck, s.labels, unixSeconds(s.lastCheck))
+			addGauge(failures, s.labels, float64(s.consecutiveFailures))
+		}
+		if !s.certNotAfter.IsZero() {
+			addGauge(certExpiry, s.labels, unixSeconds(s.certNotAfter))
+		}
+	}
+	r.mtx.Unlock()
+
+	var families []*dto.MetricFamily
+	for _, mf := range []*dto.MetricFamily{up, duration, lastCheck, failures, certExpiry} {
+		if len(mf.Metric) > 0 {
+			families = append(families, mf)
+		}
+	}
+	return families
+}
+
+func gaugeFamily(name, help string) *dto.MetricFamily {
+	return &dto.MetricFamily{
+		Name: proto.String(name),
+		Help: proto.String(help),
+		Type: dto.MetricType_GAUGE.Enum(),
+	}
+}
+
+func addGauge(mf *dto.MetricFamily, labels []*dto.LabelPair, value float64) {
+	mf.Metric = append(mf.Metric, &dto.Metric{
+		Label: labels,
+		Gauge: &dto.Gauge{Value: proto.Float64(value)},
+	})
+}
+
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
@@ -0,0 +1,181 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package prometheus
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/heartbeat/monitors/stdfields"
+	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers/monitorstate"
+	"github.com/elastic/beats/v7/heartbeat/monitors/wrappers/summarizer/jobsummary"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	pubtest "github.com/elastic/beats/v7/libbeat/publisher/testing"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+var checkTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func wrap(t *testing.T, r *Registry, inner beat.Client, sf stdfields.StdMonitorFields, cfg mapstr.M) beat.Client {
+	t.Helper()
+
+	c, err := conf.NewConfigFrom(cfg)
+	require.NoError(t, err)
+	client, err := r.WrapClient(inner, sf, c)
+	require.NoError(t, err)
+	return client
+}
+
+func summary(status string, duration time.Duration) beat.Event {
+	return beat.Event{
+		Timestamp: checkTime,
+		Fields: mapstr.M{
+			"monitor": mapstr.M{
+				"status":   status,
+				"duration": mapstr.M{"us": duration.Microseconds()},
+			},
+			"summary": mapstr.M{},
+		},
+	}
+}
+
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", Route, nil))
+	require.Equal(t, 200, rec.Code)
+	require.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+	return rec.Body.String()
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	var webPublished int
+	var webClosed bool
+	webInner := &pubtest.FakeClient{
+		PublishFunc: func(beat.Event) { webPublished++ },
+		CloseFunc:   func() error { webClosed = true; return nil },
+	}
+	web := wrap(t, r, webInner,
+		stdfields.StdMonitorFields{ID: "web", Name: "Web", Type: "http"},
+		mapstr.M{"fields": mapstr.M{"env": "prod", "team": mapstr.M{"name": "ops"}, "monitor_id": "ignored"}},
+	)
+	db := wrap(t, r, &pubtest.FakeClient{}, stdfields.StdMonitorFields{ID: "db", Name: "DB", Type: "tcp"}, mapstr.M{})
+
+	tlsEvent := summary("up", 1500*time.Millisecond)
+	_, _ = tlsEvent.PutValue("tls.server.x509.not_after", checkTime.Add(24*time.Hour))
+	web.PublishAll([]beat.Event{tlsEvent, summary("down", 250*time.Millisecond)})
+	db.Publish(summary("down", time.Second))
+	db.Publish(summary("down", time.Second))
+	// events other than summaries only contribute the certificate expiry
+	db.Publish(beat.Event{Timestamp: time.Now(), Fields: mapstr.M{"monitor": mapstr.M{"status": "up"}}})
+
+	assert.Equal(t, 2, webPublished)
+
+	labelsDB := `monitor_id="db",monitor_name="DB",monitor_type="tcp"`
+	labelsWeb := `monitor_id="web",monitor_name="Web",monitor_type="http",env="prod",team_name="ops"`
+	expected := `# HELP heartbeat_monitor_up Whether the last check of the monitor was up (1) or down (0).
+# TYPE heartbeat_monitor_up gauge
+heartbeat_monitor_up{` + labelsDB + `} 0
+heartbeat_monitor_up{` + labelsWeb + `} 0
+# HELP heartbeat_monitor_duration_seconds Duration of the last check of the monitor.
+# TYPE heartbeat_monitor_duration_seconds gauge
+heartbeat_monitor_duration_seconds{` + labelsDB + `} 1
+heartbeat_monitor_duration_seconds{` + labelsWeb + `} 0.25
+# HELP heartbeat_monitor_last_check_timestamp_seconds Time of the last check of the monitor since the Unix epoch.
+# TYPE heartbeat_monitor_last_check_timestamp_seconds gauge
+heartbeat_monitor_last_check_timestamp_seconds{` + labelsDB + `} 1.7145648e+09
+heartbeat_monitor_last_check_timestamp_seconds{` + labelsWeb + `} 1.7145648e+09
+# HELP heartbeat_monitor_consecutive_failures Number of consecutive down checks of the monitor.
+# TYPE heartbeat_monitor_consecutive_failures gauge
+heartbeat_monitor_consecutive_failures{` + labelsDB + `} 2
+heartbeat_monitor_consecutive_failures{` + labelsWeb + `} 1
+# HELP heartbeat_monitor_tls_cert_not_after_timestamp_seconds Expiry of the last TLS certificate seen by the monitor since the Unix epoch.
+# TYPE heartbeat_monitor_tls_cert_not_after_timestamp_seconds gauge
+heartbeat_monitor_tls_cert_not_after_timestamp_seconds{` + labelsWeb + `} 1.7146512e+09
+`
+	assert.Equal(t, expected, scrape(t, r))
+
+	web.Publish(summary("up", time.Second))
+	assert.Contains(t, scrape(t, r), "heartbeat_monitor_consecutive_failures{"+labelsWeb+"} 0\n")
+	assert.Contains(t, scrape(t, r), "heartbeat_monitor_up{"+labelsWeb+"} 1\n")
+
+	require.NoError(t, web.Close())
+	assert.True(t, webClosed)
+	assert.NotContains(t, scrape(t, r), `monitor_id="web"`)
+}
+
+func TestRegistryUnchecked(t *testing.T) {
+	r := NewRegistry()
+	_ = wrap(t, r, &pubtest.FakeClient{}, stdfields.StdMonitorFields{ID: "new", Type: "icmp"}, mapstr.M{})
+
+	assert.Empty(t, scrape(t, r))
+}
+
+func TestRegistryReplacedMonitor(t *testing.T) {
+	r := NewRegistry()
+	sf := stdfields.StdMonitorFields{ID: "mon", Type: "http"}
+
+	old := wrap(t, r, &pubtest.FakeClient{}, sf, mapstr.M{})
+	current := wrap(t, r, &pubtest.FakeClient{}, sf, mapstr.M{})
+	current.Publish(summary("up", time.Second))
+
+	// closing the replaced monitor must not remove its successor
+	require.NoError(t, old.Close())
+	assert.Contains(t, scrape(t, r), `heartbeat_monitor_up{monitor_id="mon",monitor_name="",monitor_type="http"} 1`)
+}
+
+func TestRegistryUntimestampedSummary(t *testing.T) {
+	r := NewRegistry()
+	client := wrap(t, r, &pubtest.FakeClient{}, stdfields.StdMonitorFields{ID: "mon", Type: "tcp"}, mapstr.M{})
+
+	event := summary("up", time.Second)
+	event.Timestamp = time.Time{}
+	before := time.Now()
+	client.Publish(event)
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	assert.False(t, r.monitors["mon"].lastCheck.Before(before))
+}
+
+func TestRegistryJobSummaryStatus(t *testing.T) {
+	r := NewRegistry()
+	client := wrap(t, r, &pubtest.FakeClient{}, stdfields.StdMonitorFields{ID: "mon", Type: "icmp"}, mapstr.M{})
+
+	// the last endpoint was up, but another one was down
+	event := summary("up", time.Second)
+	event.Fields["summary"] = &jobsummary.JobSummary{Up: 1, Down: 1, Status: monitorstate.StatusDown}
+	client.Publish(event)
+
+	assert.Contains(t, scrape(t, r), `heartbeat_monitor_up{monitor_id="mon",monitor_name="",monitor_type="icmp"} 0`)
+}
+
+func TestLabelName(t *testing.T) {
+	assert.Equal(t, "team_name", labelName("team.name"))
+	assert.Equal(t, "http_status_2xx", labelName("http-status.2xx"))
+	assert.Equal(t, "_1st", labelName("1st"))
+	assert.Equal(t, "Env", labelName("Env"))
+}
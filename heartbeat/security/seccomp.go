@@ -58,6 +58,7 @@ func mustConfigureSeccompPolicy() {
 			"fallocate",
 			"fcntl",
 			"flock",
+			"fork", // shell scripts run by the script notifier
 			"fstat",
 			"fsync",
 			"futex",
@@ -135,6 +136,7 @@ func mustConfigureSeccompPolicy() {
 			"uname",
 			"unlink",
 			"utimensat",
+			"vfork", // shell scripts run by the script notifier
 			"write",
 		); err != nil {
 			panic(err)
@@ -0,0 +1,249 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package watcher
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elastic/beats/v7/libbeat/version"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/useragent"
+)
+
+const defaultSignatureHeader = "X-Signature"
+
+// maxHTTPPollSize limits the size of the polled content.
+const maxHTTPPollSize = 10 * 1024 * 1024
+
+var userAgent = useragent.UserAgent("Heartbeat", version.GetDefaultVersion(), version.Commit(), version.BuildTime().String())
+
+type httpPoller struct {
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+type httpChangeTester struct {
+	url       string
+	name      string
+	headers   map[string]string
+	client    *http.Client
+	publicKey ed25519.PublicKey
+	sigHeader string
+	logger    *logp.Logger
+
+	etag string
+	hash uint64
+	// polled is set once content was returned by check
+	polled bool
+}
+
+// NewHTTPPoller polls the endpoint configured in config, calling do with
+// the content returned whenever it changes. Unchanged content is detected
+// using the ETag of the responses and the hash of their bodies. If a
+// signature is configured, content with a missing or invalid signature is
+// ignored. Like NewFilePoller, the endpoint is first polled before returning.
+func NewHTTPPoller(config HTTPPollConfig, do func([]byte)) (Watch, error) {
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url '%s': %w", config.URL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid url '%s': scheme must be http or https", config.URL)
+	}
+
+	client, err := config.Transport.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	tester := &httpChangeTester{
+		url:     config.URL,
+		name:    u.Redacted(),
+		headers: config.Headers,
+		client:  client,
+		logger:  logp.L().Named("watcher"),
+	}
+	if config.Signature != nil {
+		tester.publicKey, err = loadPublicKey(config.Signature.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("could not load signature public key: %w", err)
+		}
+		tester.sigHeader = config.Signature.Header
+		if tester.sigHeader == "" {
+			tester.sigHeader = defaultSignatureHeader
+		}
+	}
+
+	hp := &httpPoller{done: make(chan struct{})}
+
+	if content, changed := tester.check(); changed {
+		do(content)
+	}
+
+	hp.wg.Add(1)
+	go func() {
+		defer hp.wg.Done()
+
+		ticker := time.NewTicker(config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-hp.done:
+				return
+			case <-ticker.C:
+			}
+
+			if content, changed := tester.check(); changed {
+				do(content)
+			}
+		}
+	}()
+
+	return hp, nil
+}
+
+// Stop stops polling, waiting for an ongoing poll to complete.
+func (h *httpPoller) Stop() {
+	close(h.done)
+	h.wg.Wait()
+}
+
+func (w *httpChangeTester) check() ([]byte, bool) {
+	content, etag, err := w.fetch()
+	if err != nil {
+		w.logger.Warnf("Polling %s failed: %v", w.name, err)
+		return nil, false
+	}
+	if content == nil {
+		// not modified
+		return nil, false
+	}
+
+	hasher := fnv.New64a()
+	hasher.Write(content)
+	hash := hasher.Sum64()
+
+	w.etag = etag
+	if w.polled && w.hash == hash {
+		return nil, false
+	}
+	w.hash = hash
+	w.polled = true
+	return content, true
+}
+
+// fetch returns the content of the endpoint and its ETag, or no content if
+// it was not modified since the last poll.
+func (w *httpChangeTester) fetch() (content []byte, etag string, err error) {
+	req, err := http.NewRequest(http.MethodGet, w.url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	for k, v := range w.headers {
+		req.Header.Set(k, v)
+	}
+	if w.etag != "" {
+		req.Header.Set("If-None-Match", w.etag)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && w.etag != "" {
+		return nil, "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("endpoint responded with status %s", resp.Status)
+	}
+
+	content, err = io.ReadAll(io.LimitReader(resp.Body, maxHTTPPollSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(content) > maxHTTPPollSize {
+		return nil, "", fmt.Errorf("content is larger than %d bytes", maxHTTPPollSize)
+	}
+	if content == nil {
+		content = []byte{}
+	}
+
+	if w.publicKey != nil {
+		if err := w.verify(content, resp.Header.Get(w.sigHeader)); err != nil {
+			return nil, "", err
+		}
+	}
+	return content, resp.Header.Get("ETag"), nil
+}
+
+func (w *httpChangeTester) verify(content []byte, signature string) error {
+	if signature == "" {
+		return fmt.Errorf("missing signature header %s", w.sigHeader)
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	if !ed25519.Verify(w.publicKey, content, sig) {
+		return errors.New("signature verification failed")
+	}
+	return nil
+}
+
+// loadPublicKey parses an ed25519 public key PEM encoded in PKIX form, given
+// either inline or as the path of a file.
+func loadPublicKey(key string) (ed25519.PublicKey, error) {
+	data := []byte(key)
+	if !strings.HasPrefix(strings.TrimSpace(key), "-----BEGIN") {
+		var err error
+		if data, err = os.ReadFile(key); err != nil {
+			return nil, err
+		}
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM encoded key found")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	edKey, ok := pub.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T, only ed25519 keys are supported", pub)
+	}
+	return edKey, nil
+}
@@ -0,0 +1,254 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package watcher
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// catalog serves a list of monitors with an ETag, counting the responses.
+type catalog struct {
+	mtx       sync.Mutex
+	content   string
+	etag      string
+	signature string
+	ok        int
+	notMod    int
+}
+
+func (c *catalog) set(content, etag string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.content, c.etag = content, etag
+}
+
+func (c *catalog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.etag != "" && r.Header.Get("If-None-Match") == c.etag {
+		c.notMod++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	c.ok++
+	if c.etag != "" {
+		w.Header().Set("ETag", c.etag)
+	}
+	if c.signature != "" {
+		w.Header().Set("X-Signature", c.signature)
+	}
+	_, _ = w.Write([]byte(c.content))
+}
+
+func newTestPoller(t *testing.T, config HTTPPollConfig) (Watch, <-chan string) {
+	t.Helper()
+
+	polled := make(chan string, 10)
+	w, err := NewHTTPPoller(config, func(content []byte) {
+		polled <- string(content)
+	})
+	require.NoError(t, err)
+	t.Cleanup(w.Stop)
+	return w, polled
+}
+
+func requirePolled(t *testing.T, polled <-chan string, expected string) {
+	t.Helper()
+
+	select {
+	case content := <-polled:
+		require.Equal(t, expected, content)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("content %q was not polled", expected)
+	}
+}
+
+func requireNotPolled(t *testing.T, polled <-chan string, wait time.Duration) {
+	t.Helper()
+
+	select {
+	case content := <-polled:
+		t.Fatalf("unexpected content polled: %q", content)
+	case <-time.After(wait):
+	}
+}
+
+func testConfig(url string) HTTPPollConfig {
+	config := DefaultHTTPPollConfig()
+	config.URL = url
+	config.Interval = 10 * time.Millisecond
+	return config
+}
+
+func TestHTTPPollerChanges(t *testing.T) {
+	c := &catalog{content: "- id: a", etag: `"v1"`}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	_, polled := newTestPoller(t, testConfig(srv.URL))
+	requirePolled(t, polled, "- id: a")
+	requireNotPolled(t, polled, 100*time.Millisecond)
+
+	c.set("- id: b", `"v2"`)
+	requirePolled(t, polled, "- id: b")
+
+	// the content did not change, even though the ETag did
+	c.set("- id: b", `"v3"`)
+	requireNotPolled(t, polled, 100*time.Millisecond)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	assert.Greater(t, c.notMod, 0)
+}
+
+func TestHTTPPollerWithoutETag(t *testing.T) {
+	c := &catalog{content: "- id: a"}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	_, polled := newTestPoller(t, testConfig(srv.URL))
+	requirePolled(t, polled, "- id: a")
+	requireNotPolled(t, polled, 100*time.Millisecond)
+
+	c.set("[]", "")
+	requirePolled(t, polled, "[]")
+}
+
+func TestHTTPPollerErrors(t *testing.T) {
+	var fail bool
+	var mtx sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte("- id: a"))
+	}))
+	defer srv.Close()
+
+	_, polled := newTestPoller(t, testConfig(srv.URL))
+	requirePolled(t, polled, "- id: a")
+
+	// errors keep the last content
+	mtx.Lock()
+	fail = true
+	mtx.Unlock()
+	requireNotPolled(t, polled, 100*time.Millisecond)
+
+	mtx.Lock()
+	fail = false
+	mtx.Unlock()
+	requireNotPolled(t, polled, 100*time.Millisecond)
+}
+
+func TestHTTPPollerHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer srv.Close()
+
+	config := testConfig(srv.URL)
+	config.Headers = map[string]string{"Authorization": "Bearer token"}
+	_, polled := newTestPoller(t, config)
+	requirePolled(t, polled, "Bearer token")
+}
+
+func TestHTTPPollerSignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	pubPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	sign := func(content string) string {
+		return base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(content)))
+	}
+
+	c := &catalog{content: "- id: a", signature: sign("- id: a")}
+	srv := httptest.NewServer(c)
+	defer srv.Close()
+
+	config := testConfig(srv.URL)
+	config.Signature = &SignatureConfig{PublicKey: pubPEM}
+	_, polled := newTestPoller(t, config)
+	requirePolled(t, polled, "- id: a")
+
+	// content signed by another key, or not matching its signature, is ignored
+	c.set("- id: b", "")
+	requireNotPolled(t, polled, 100*time.Millisecond)
+	c.mtx.Lock()
+	c.signature = "not base64"
+	c.mtx.Unlock()
+	requireNotPolled(t, polled, 100*time.Millisecond)
+
+	c.mtx.Lock()
+	c.signature = sign("- id: b")
+	c.mtx.Unlock()
+	requirePolled(t, polled, "- id: b")
+}
+
+func TestLoadPublicKey(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	pubPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	key, err := loadPublicKey(string(pubPEM))
+	require.NoError(t, err)
+	require.Equal(t, pub, key)
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, os.WriteFile(path, pubPEM, 0o600))
+	key, err = loadPublicKey(path)
+	require.NoError(t, err)
+	require.Equal(t, pub, key)
+
+	_, err = loadPublicKey(filepath.Join(t.TempDir(), "missing.pem"))
+	require.Error(t, err)
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	_, err = loadPublicKey(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	require.ErrorContains(t, err, "only ed25519 keys are supported")
+}
+
+func TestNewHTTPPollerInvalidURL(t *testing.T) {
+	_, err := NewHTTPPoller(testConfig("ftp://example.com/monitors"), func([]byte) {})
+	require.ErrorContains(t, err, "scheme must be http or https")
+}
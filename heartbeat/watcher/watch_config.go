@@ -17,10 +17,16 @@
 
 package watcher
 
-import "time"
+import (
+	"time"
+
+	"github.com/elastic/elastic-agent-libs/transport/httpcommon"
+)
 
 var defaultFilePollInterval = 5 * time.Second
 
+var defaultHTTPPollInterval = time.Minute
+
 type watchConfig struct {
 	Path string        `config:"watch.poll_file.path"`
 	Poll time.Duration `config:"watch.poll_file.interval" validate:"min=1"`
@@ -30,3 +36,29 @@ type watchConfig struct {
 var DefaultWatchConfig = watchConfig{
 	Poll: defaultFilePollInterval,
 }
+
+// HTTPPollConfig configures the polling of an HTTP(S) endpoint by NewHTTPPoller.
+type HTTPPollConfig struct {
+	URL       string                           `config:"url" validate:"required"`
+	Interval  time.Duration                    `config:"interval" validate:"min=1"`
+	Headers   map[string]string                `config:"headers"`
+	Signature *SignatureConfig                 `config:"signature"`
+	Transport httpcommon.HTTPTransportSettings `config:",inline"`
+}
+
+// SignatureConfig configures the verification of the ed25519 signature of
+// the polled content.
+type SignatureConfig struct {
+	// PublicKey is a PEM encoded public key, or the path of a file holding it.
+	PublicKey string `config:"public_key" validate:"required"`
+	// Header is the response header holding the base64 encoded signature.
+	Header string `config:"header"`
+}
+
+// DefaultHTTPPollConfig returns the defaults of HTTPPollConfig.
+func DefaultHTTPPollConfig() HTTPPollConfig {
+	return HTTPPollConfig{
+		Interval:  defaultHTTPPollInterval,
+		Transport: httpcommon.DefaultHTTPTransportSettings(),
+	}
+}
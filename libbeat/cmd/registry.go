@@ -0,0 +1,379 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/cmd/instance"
+	"github.com/elastic/beats/v7/libbeat/cmd/instance/locks"
+	"github.com/elastic/beats/v7/libbeat/common/cli"
+	"github.com/elastic/beats/v7/libbeat/statestore"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/go-structform/gotype"
+	sfjson "github.com/elastic/go-structform/json"
+)
+
+// RegistryManager interface provides the beat specific access to the
+// registry needed to implement the registry command.
+type RegistryManager interface {
+	// Store returns the store holding the state of the beat.
+	Store() *statestore.Store
+
+	// Describe returns the input ID and the path of the source the entry
+	// belongs to. Empty strings are returned if they are unknown.
+	Describe(key string, value mapstr.M) (inputID, path string)
+
+	// ResetOffset updates value, such that the source is read from the
+	// beginning. Returns false if the entry has no offset.
+	ResetOffset(key string, value mapstr.M) bool
+
+	// Close closes the store and the underlying registry.
+	Close() error
+}
+
+// registryManagerFactory builds and return a RegistryManager for the given Beat
+type registryManagerFactory func(beat *beat.Beat) (RegistryManager, error)
+
+// registryFilter selects registry entries by input ID and path glob.
+type registryFilter struct {
+	inputID string
+	path    string
+}
+
+// GenRegistryCmd initializes a command to inspect and edit the registry of a
+// beat. The registry must not be used by a running beat while the command is
+// executed, the data path lock is acquired before the registry is opened.
+func GenRegistryCmd(name, version string, registryFactory registryManagerFactory) *cobra.Command {
+	registryCmd := cobra.Command{
+		Use:   "registry",
+		Short: "Inspect and edit the registry",
+	}
+	settings := instance.Settings{Name: name, Version: version}
+
+	registryCmd.AddCommand(genListRegistryCmd(settings, registryFactory))
+	registryCmd.AddCommand(genShowRegistryCmd(settings, registryFactory))
+	registryCmd.AddCommand(genExportRegistryCmd(settings, registryFactory))
+	registryCmd.AddCommand(genImportRegistryCmd(settings, registryFactory))
+	registryCmd.AddCommand(genDeleteRegistryCmd(settings, registryFactory))
+	registryCmd.AddCommand(genResetOffsetRegistryCmd(settings, registryFactory))
+
+	return &registryCmd
+}
+
+func genListRegistryCmd(settings instance.Settings, registryFactory registryManagerFactory) *cobra.Command {
+	var filter registryFilter
+	command := &cobra.Command{
+		Use:   "list",
+		Short: "List registry entries",
+		Run: cli.RunWith(func(cmd *cobra.Command, args []string) error {
+			return withRegistry(settings, registryFactory, func(registry RegistryManager) error {
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+				fmt.Fprintln(w, "KEY\tINPUT ID\tPATH")
+				err := eachRegistryEntry(registry, nil, filter, func(key string, value mapstr.M) error {
+					inputID, path := registry.Describe(key, value)
+					fmt.Fprintf(w, "%s\t%s\t%s\n", key, inputID, path)
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				return w.Flush()
+			})
+		}),
+	}
+	addRegistryFilterFlags(command, &filter)
+	return command
+}
+
+func genShowRegistryCmd(settings instance.Settings, registryFactory registryManagerFactory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show KEY",
+		Short: "Show a registry entry",
+		Args:  cobra.ExactArgs(1),
+		Run: cli.RunWith(func(cmd *cobra.Command, args []string) error {
+			return withRegistry(settings, registryFactory, func(registry RegistryManager) error {
+				var value mapstr.M
+				if err := registry.Store().Get(args[0], &value); err != nil {
+					return fmt.Errorf("failed to read key '%s': %w", args[0], err)
+				}
+				return writeRegistryJSON(cmd.OutOrStdout(), value)
+			})
+		}),
+	}
+}
+
+func genExportRegistryCmd(settings instance.Settings, registryFactory registryManagerFactory) *cobra.Command {
+	var filter registryFilter
+	var output string
+	command := &cobra.Command{
+		Use:   "export",
+		Short: "Export registry entries as JSON",
+		Run: cli.RunWith(func(cmd *cobra.Command, args []string) error {
+			return withRegistry(settings, registryFactory, func(registry RegistryManager) error {
+				entries := map[string]mapstr.M{}
+				err := eachRegistryEntry(registry, nil, filter, func(key string, value mapstr.M) error {
+					entries[key] = value
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+
+				if output == "" || output == "-" {
+					return writeRegistryJSON(cmd.OutOrStdout(), entries)
+				}
+				f, err := os.OpenFile(output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+				if err != nil {
+					return err
+				}
+				if err := writeRegistryJSON(f, entries); err != nil {
+					f.Close()
+					return err
+				}
+				return f.Close()
+			})
+		}),
+	}
+	addRegistryFilterFlags(command, &filter)
+	command.Flags().StringVarP(&output, "output", "o", "", "Write the entries to a file instead of stdout")
+	return command
+}
+
+func genImportRegistryCmd(settings instance.Settings, registryFactory registryManagerFactory) *cobra.Command {
+	return &cobra.Command{
+		Use:   "import FILE",
+		Short: "Import registry entries from a JSON file created by export",
+		Long:  "Import registry entries from a JSON file created by export. Existing entries with the same key are overwritten. Use - to read from stdin.",
+		Args:  cobra.ExactArgs(1),
+		Run: cli.RunWith(func(cmd *cobra.Command, args []string) error {
+			entries, err := readRegistryEntries(args[0])
+			if err != nil {
+				return err
+			}
+
+			return withRegistry(settings, registryFactory, func(registry RegistryManager) error {
+				for key, value := range entries {
+					if err := registry.Store().Set(key, value); err != nil {
+						return fmt.Errorf("failed to write key '%s': %w", key, err)
+					}
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Imported %d entries\n", len(entries))
+				return nil
+			})
+		}),
+	}
+}
+
+func genDeleteRegistryCmd(settings instance.Settings, registryFactory registryManagerFactory) *cobra.Command {
+	var filter registryFilter
+	command := &cobra.Command{
+		Use:   "delete [KEY...]",
+		Short: "Delete registry entries",
+		Long:  "Delete registry entries by key, input ID or path. The source of a deleted entry is read again from the beginning.",
+		Run: cli.RunWith(func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && filter == (registryFilter{}) {
+				return errors.New("no entries selected, pass keys or set --input-id or --path")
+			}
+
+			return withRegistry(settings, registryFactory, func(registry RegistryManager) error {
+				var count int
+				err := eachRegistryEntry(registry, args, filter, func(key string, _ mapstr.M) error {
+					if err := registry.Store().Remove(key); err != nil {
+						return fmt.Errorf("failed to delete key '%s': %w", key, err)
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "Deleted %s\n", key)
+					count++
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Deleted %d entries\n", count)
+				return nil
+			})
+		}),
+	}
+	addRegistryFilterFlags(command, &filter)
+	return command
+}
+
+func genResetOffsetRegistryCmd(settings instance.Settings, registryFactory registryManagerFactory) *cobra.Command {
+	var filter registryFilter
+	command := &cobra.Command{
+		Use:   "reset-offset [KEY...]",
+		Short: "Reset the offset of registry entries",
+		Long:  "Reset the offset of registry entries selected by key, input ID or path, so their sources are read again from the beginning.",
+		Run: cli.RunWith(func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 && filter == (registryFilter{}) {
+				return errors.New("no entries selected, pass keys or set --input-id or --path")
+			}
+
+			return withRegistry(settings, registryFactory, func(registry RegistryManager) error {
+				var count int
+				err := eachRegistryEntry(registry, args, filter, func(key string, value mapstr.M) error {
+					if !registry.ResetOffset(key, value) {
+						fmt.Fprintf(cmd.ErrOrStderr(), "Entry %s has no offset, skipping\n", key)
+						return nil
+					}
+					if err := registry.Store().Set(key, value); err != nil {
+						return fmt.Errorf("failed to write key '%s': %w", key, err)
+					}
+					fmt.Fprintf(cmd.OutOrStdout(), "Reset %s\n", key)
+					count++
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Reset %d entries\n", count)
+				return nil
+			})
+		}),
+	}
+	addRegistryFilterFlags(command, &filter)
+	return command
+}
+
+func addRegistryFilterFlags(command *cobra.Command, filter *registryFilter) {
+	command.Flags().StringVar(&filter.inputID, "input-id", "", "Select entries of the input with this ID")
+	command.Flags().StringVar(&filter.path, "path", "", "Select entries with a path matching this glob pattern")
+}
+
+// withRegistry locks the data path and opens the registry, failing if the
+// beat is running.
+func withRegistry(settings instance.Settings, registryFactory registryManagerFactory, fn func(RegistryManager) error) error {
+	b, err := instance.NewInitializedBeat(settings)
+	if err != nil {
+		return fmt.Errorf("error initializing beat: %w", err)
+	}
+
+	lock := locks.New(b.Info)
+	if err := lock.Lock(); err != nil {
+		return fmt.Errorf("the registry can only be accessed while %s is stopped: %w", settings.Name, err)
+	}
+	defer func() {
+		_ = lock.Unlock()
+	}()
+
+	registry, err := registryFactory(&b.Beat)
+	if err != nil {
+		return fmt.Errorf("error opening registry: %w", err)
+	}
+
+	err = fn(registry)
+	if closeErr := registry.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// eachRegistryEntry calls fn for all entries selected by keys and filter. All
+// entries are read before fn is called, so fn can modify the store.
+func eachRegistryEntry(registry RegistryManager, keys []string, filter registryFilter, fn func(string, mapstr.M) error) error {
+	if filter.path != "" {
+		if _, err := filepath.Match(filter.path, ""); err != nil {
+			return fmt.Errorf("invalid path pattern '%s': %w", filter.path, err)
+		}
+	}
+
+	selected := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		selected[key] = true
+	}
+
+	type entry struct {
+		key   string
+		value mapstr.M
+	}
+	var entries []entry
+	err := registry.Store().Each(func(key string, dec statestore.ValueDecoder) (bool, error) {
+		if len(selected) > 0 && !selected[key] {
+			return true, nil
+		}
+
+		var value mapstr.M
+		if err := dec.Decode(&value); err != nil {
+			return false, fmt.Errorf("failed to read key '%s': %w", key, err)
+		}
+
+		inputID, path := registry.Describe(key, value)
+		if filter.inputID != "" && filter.inputID != inputID {
+			return true, nil
+		}
+		if filter.path != "" {
+			if matched, _ := filepath.Match(filter.path, path); !matched {
+				return true, nil
+			}
+		}
+
+		entries = append(entries, entry{key: key, value: value})
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if err := fn(e.key, e.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeRegistryJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// readRegistryEntries reads a JSON object mapping keys to entries. The
+// document is parsed with go-structform, so integers are not converted to
+// floats.
+func readRegistryEntries(path string) (map[string]mapstr.M, error) {
+	in := os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	entries := map[string]mapstr.M{}
+	unfolder, err := gotype.NewUnfolder(&entries)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := sfjson.ParseReader(in, unfolder); err != nil {
+		return nil, fmt.Errorf("failed to parse registry entries from '%s': %w", path, err)
+	}
+	return entries, nil
+}
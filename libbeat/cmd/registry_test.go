@@ -0,0 +1,337 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gofrs/flock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/cfgfile"
+	"github.com/elastic/beats/v7/libbeat/statestore"
+	"github.com/elastic/beats/v7/libbeat/statestore/backend/memlog"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/logp/logptest"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/elastic-agent-libs/monitoring"
+	"github.com/elastic/elastic-agent-libs/paths"
+)
+
+const testRegistryBeat = "testbeat"
+
+// testRegistryManager keeps the entries in a memlog store under the data
+// path. The input ID and path of an entry are read from its `input` and
+// `path` fields.
+type testRegistryManager struct {
+	registry *statestore.Registry
+	store    *statestore.Store
+}
+
+func openTestRegistry(logger *logp.Logger, dataPath string) (*testRegistryManager, error) {
+	backend, err := memlog.New(logger, memlog.Settings{Root: filepath.Join(dataPath, "registry")})
+	if err != nil {
+		return nil, err
+	}
+	registry := statestore.NewRegistry(backend)
+	store, err := registry.Get(testRegistryBeat)
+	if err != nil {
+		registry.Close()
+		return nil, err
+	}
+	return &testRegistryManager{registry: registry, store: store}, nil
+}
+
+func buildTestRegistryManager(b *beat.Beat) (RegistryManager, error) {
+	return openTestRegistry(b.Info.Logger, paths.Resolve(paths.Data, ""))
+}
+
+func (m *testRegistryManager) Store() *statestore.Store { return m.store }
+
+func (m *testRegistryManager) Describe(_ string, value mapstr.M) (string, string) {
+	inputID, _ := value["input"].(string)
+	path, _ := value["path"].(string)
+	return inputID, path
+}
+
+func (m *testRegistryManager) ResetOffset(_ string, value mapstr.M) bool {
+	if _, ok := value["offset"]; !ok {
+		return false
+	}
+	value["offset"] = int64(0)
+	return true
+}
+
+func (m *testRegistryManager) Close() error {
+	m.store.Close()
+	return m.registry.Close()
+}
+
+var testRegistryEntries = map[string]mapstr.M{
+	"a": {"input": "logs", "path": "/var/log/a.log", "offset": int64(10)},
+	"b": {"input": "logs", "path": "/var/log/b.txt", "offset": int64(20)},
+	"c": {"input": "audit", "path": "/var/log/audit/c.log", "offset": int64(30)},
+	"d": {"ttl": int64(-1)},
+}
+
+// setupRegistryHome points the beat to a new home directory holding an
+// empty configuration file, and writes entries to its registry.
+func setupRegistryHome(t *testing.T, entries map[string]mapstr.M) string {
+	t.Helper()
+
+	home := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(home, "beat.yml"), nil, 0o600))
+	setRegistryHome(t, home)
+
+	registry, err := openTestRegistry(logptest.NewTestingLogger(t, ""), filepath.Join(home, "data"))
+	require.NoError(t, err)
+	for key, value := range entries {
+		require.NoError(t, registry.Store().Set(key, value))
+	}
+	require.NoError(t, registry.Close())
+	return home
+}
+
+func setRegistryHome(t *testing.T, home string) {
+	t.Helper()
+
+	cfgfile.Initialize()
+	require.NoError(t, flag.Set("path.home", home))
+	require.NoError(t, flag.Set("path.logs", filepath.Join(home, "logs")))
+}
+
+func runRegistryCmd(t *testing.T, args ...string) string {
+	t.Helper()
+
+	// The beat registers its metrics in the global monitoring namespaces,
+	// so it can be initialized only once with the same registries.
+	for _, name := range []string{"info", "state", "stats"} {
+		namespace := monitoring.GetNamespace(name)
+		registry := namespace.GetRegistry()
+		namespace.SetRegistry(monitoring.NewRegistry())
+		t.Cleanup(func() { namespace.SetRegistry(registry) })
+	}
+
+	var out bytes.Buffer
+	command := GenRegistryCmd(testRegistryBeat, "", buildTestRegistryManager)
+	command.SetOut(&out)
+	command.SetErr(&out)
+	command.SetArgs(args)
+	require.NoError(t, command.Execute())
+	return out.String()
+}
+
+// assertTestRegistry checks the entries in the registry. The entries are
+// compared as JSON, as numbers are decoded as floats.
+func assertTestRegistry(t *testing.T, home string, want map[string]mapstr.M) {
+	t.Helper()
+
+	wantJSON, err := json.Marshal(want)
+	require.NoError(t, err)
+	got, err := json.Marshal(readTestRegistry(t, home))
+	require.NoError(t, err)
+	assert.JSONEq(t, string(wantJSON), string(got))
+}
+
+func readTestRegistry(t *testing.T, home string) map[string]mapstr.M {
+	t.Helper()
+
+	registry, err := openTestRegistry(logptest.NewTestingLogger(t, ""), filepath.Join(home, "data"))
+	require.NoError(t, err)
+	defer registry.Close()
+
+	entries := map[string]mapstr.M{}
+	err = registry.Store().Each(func(key string, dec statestore.ValueDecoder) (bool, error) {
+		var value mapstr.M
+		err := dec.Decode(&value)
+		entries[key] = value
+		return true, err
+	})
+	require.NoError(t, err)
+	return entries
+}
+
+func listedKeys(out string) []string {
+	var keys []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n")[1:] {
+		keys = append(keys, strings.Fields(line)[0])
+	}
+	return keys
+}
+
+func TestRegistryList(t *testing.T) {
+	setupRegistryHome(t, testRegistryEntries)
+
+	tests := map[string]struct {
+		args []string
+		keys []string
+	}{
+		"all":               {nil, []string{"a", "b", "c", "d"}},
+		"input ID":          {[]string{"--input-id", "logs"}, []string{"a", "b"}},
+		"path":              {[]string{"--path", "/var/log/*.log"}, []string{"a"}},
+		"input ID and path": {[]string{"--input-id", "audit", "--path", "/var/log/*.log"}, nil},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			out := runRegistryCmd(t, append([]string{"list"}, test.args...)...)
+			assert.ElementsMatch(t, test.keys, listedKeys(out))
+		})
+	}
+
+	t.Run("columns", func(t *testing.T) {
+		out := runRegistryCmd(t, "list", "--input-id", "audit")
+		lines := strings.Split(strings.TrimSpace(out), "\n")
+		require.Len(t, lines, 2)
+		assert.Equal(t, []string{"KEY", "INPUT", "ID", "PATH"}, strings.Fields(lines[0]))
+		assert.Equal(t, []string{"c", "audit", "/var/log/audit/c.log"}, strings.Fields(lines[1]))
+	})
+}
+
+func TestRegistryShow(t *testing.T) {
+	setupRegistryHome(t, testRegistryEntries)
+
+	out := runRegistryCmd(t, "show", "b")
+	var value map[string]any
+	require.NoError(t, json.Unmarshal([]byte(out), &value))
+	assert.Equal(t, map[string]any{"input": "logs", "path": "/var/log/b.txt", "offset": float64(20)}, value)
+}
+
+func TestRegistryDelete(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		deleted []string
+	}{
+		"keys":     {[]string{"a", "d"}, []string{"a", "d"}},
+		"input ID": {[]string{"--input-id", "logs"}, []string{"a", "b"}},
+		"path":     {[]string{"--path", "/var/log/*/*.log"}, []string{"c"}},
+		"keys and input ID": {
+			[]string{"a", "c", "--input-id", "logs"},
+			[]string{"a"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			home := setupRegistryHome(t, testRegistryEntries)
+
+			out := runRegistryCmd(t, append([]string{"delete"}, test.args...)...)
+			assert.Contains(t, out, fmt.Sprintf("Deleted %d entries", len(test.deleted)))
+
+			want := map[string]mapstr.M{}
+			for key, value := range testRegistryEntries {
+				want[key] = value
+			}
+			for _, key := range test.deleted {
+				delete(want, key)
+			}
+			assertTestRegistry(t, home, want)
+		})
+	}
+}
+
+func TestRegistryResetOffset(t *testing.T) {
+	home := setupRegistryHome(t, testRegistryEntries)
+
+	out := runRegistryCmd(t, "reset-offset", "--input-id", "logs")
+	assert.Contains(t, out, "Reset 2 entries")
+
+	out = runRegistryCmd(t, "reset-offset", "d")
+	assert.Contains(t, out, "Entry d has no offset, skipping")
+	assert.Contains(t, out, "Reset 0 entries")
+
+	assertTestRegistry(t, home, map[string]mapstr.M{
+		"a": {"input": "logs", "path": "/var/log/a.log", "offset": int64(0)},
+		"b": {"input": "logs", "path": "/var/log/b.txt", "offset": int64(0)},
+		"c": {"input": "audit", "path": "/var/log/audit/c.log", "offset": int64(30)},
+		"d": {"ttl": int64(-1)},
+	})
+}
+
+func TestRegistryExportImport(t *testing.T) {
+	home := setupRegistryHome(t, testRegistryEntries)
+	export := filepath.Join(t.TempDir(), "registry.json")
+	runRegistryCmd(t, "export", "--output", export)
+
+	// stdout holds the same entries as the file
+	out := runRegistryCmd(t, "export")
+	exported, err := os.ReadFile(export)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(exported), out)
+
+	home = setupRegistryHome(t, map[string]mapstr.M{
+		"a": {"input": "logs", "path": "/var/log/a.log", "offset": int64(99)},
+		"e": {"input": "other", "offset": int64(1)},
+	})
+	out = runRegistryCmd(t, "import", export)
+	assert.Contains(t, out, "Imported 4 entries")
+
+	// imported entries overwrite existing ones
+	want := map[string]mapstr.M{"e": {"input": "other", "offset": int64(1)}}
+	for key, value := range testRegistryEntries {
+		want[key] = value
+	}
+	assertTestRegistry(t, home, want)
+
+	t.Run("filtered", func(t *testing.T) {
+		setupRegistryHome(t, testRegistryEntries)
+		out := runRegistryCmd(t, "export", "--path", "/var/log/*.txt")
+		var entries map[string]any
+		require.NoError(t, json.Unmarshal([]byte(out), &entries))
+		assert.Len(t, entries, 1)
+		assert.Contains(t, entries, "b")
+	})
+}
+
+// TestRegistryLocked runs the registry command in a subprocess, as it exits
+// the process on error.
+func TestRegistryLocked(t *testing.T) {
+	if home := os.Getenv("REGISTRY_TEST_LOCKED_HOME"); home != "" {
+		setRegistryHome(t, home)
+		runRegistryCmd(t, "delete", "a")
+		return
+	}
+
+	home := setupRegistryHome(t, testRegistryEntries)
+	lock := flock.New(filepath.Join(home, "data", testRegistryBeat+".lock"))
+	locked, err := lock.TryLock()
+	require.NoError(t, err)
+	require.True(t, locked)
+	defer lock.Unlock()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRegistryLocked$")
+	cmd.Env = append(os.Environ(), "REGISTRY_TEST_LOCKED_HOME="+home)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+
+	var exitErr *exec.ExitError
+	require.True(t, errors.As(err, &exitErr), "command must fail, got: %v", err)
+	assert.Equal(t, 1, exitErr.ExitCode())
+	assert.Contains(t, stderr.String(), "the registry can only be accessed while testbeat is stopped")
+	assertTestRegistry(t, home, testRegistryEntries)
+}
@@ -31,10 +31,13 @@ const (
 	patternMode multilineType = iota
 	countMode
 	whilePatternMode
+	stacktraceMode
 
 	patternStr      = "pattern"
 	countStr        = "count"
 	whilePatternStr = "while_pattern"
+	stacktraceStr   = "stacktrace"
+	autoStr         = "auto"
 )
 
 var (
@@ -42,6 +45,8 @@ var (
 		patternStr:      patternMode,
 		countStr:        countMode,
 		whilePatternStr: whilePatternMode,
+		stacktraceStr:   stacktraceMode,
+		autoStr:         stacktraceMode,
 	}
 
 	ErrMissingPattern = errors.New("multiline.pattern cannot be empty when pattern based matching is selected")
@@ -61,6 +66,8 @@ type Config struct {
 
 	LinesCount  int  `config:"count_lines" validate:"positive"`
 	SkipNewLine bool `config:"skip_newline"`
+
+	Languages []string `config:"languages"`
 }
 
 // Validate validates the Config option for multiline reader.
@@ -80,6 +87,10 @@ func (c *Config) Validate() error {
 		if c.Pattern == nil {
 			return ErrMissingPattern
 		}
+	} else if c.Type == stacktraceMode {
+		if err := validateTraceLanguages(c.Languages); err != nil {
+			return err
+		}
 	} else {
 		return fmt.Errorf("unknown multiline type %d", c.Type)
 	}
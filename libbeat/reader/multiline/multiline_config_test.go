@@ -55,6 +55,13 @@ func TestInvalidConfiguration(t *testing.T) {
 			},
 			expectedError: ErrMissingPattern,
 		},
+		"unknown stack trace language": {
+			config: map[string]interface{}{
+				"type":      "stacktrace",
+				"languages": []string{"java", "cobol"},
+			},
+			expectedError: fmt.Errorf("unknown stack trace language: cobol"),
+		},
 	}
 
 	for name, test := range testcases {
@@ -92,6 +99,17 @@ func TestValidConfiguration(t *testing.T) {
 				"count_lines": 5,
 			},
 		},
+		"correct stacktrace based multiline": {
+			config: map[string]interface{}{
+				"type":      "stacktrace",
+				"languages": []string{"java", "python"},
+			},
+		},
+		"auto multiline": {
+			config: map[string]interface{}{
+				"type": "auto",
+			},
+		},
 	}
 
 	for name, test := range testcases {
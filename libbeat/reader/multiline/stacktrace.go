@@ -0,0 +1,216 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package multiline
+
+import (
+	"io"
+
+	"github.com/elastic/beats/v7/libbeat/reader"
+	"github.com/elastic/beats/v7/libbeat/reader/readfile"
+	"github.com/elastic/elastic-agent-libs/logp"
+)
+
+// stacktraceReader combines the lines of stack traces into one multi-line
+// event. Stack traces are recognized with the built-in rule sets of the
+// configured languages, other lines are returned as they are.
+//
+// Errors will force the multiline reader to return the currently active
+// multiline event first and finally return the actual error on next call to Next.
+type stacktraceReader struct {
+	reader    reader.Reader
+	detector  *traceDetector
+	logger    *logp.Logger
+	msgBuffer *messageBuffer
+	state     func(*stacktraceReader) (reader.Message, error)
+}
+
+func newMultilineStacktraceReader(
+	r reader.Reader,
+	separator string,
+	maxBytes int,
+	config *Config,
+) (reader.Reader, error) {
+	detector, err := newTraceDetector(config.Languages)
+	if err != nil {
+		return nil, err
+	}
+
+	maxLines := defaultMaxLines
+	if config.MaxLines != nil {
+		maxLines = *config.MaxLines
+	}
+
+	tout := defaultMultilineTimeout
+	if config.Timeout != nil {
+		tout = *config.Timeout
+	}
+
+	if tout > 0 {
+		r = readfile.NewTimeoutReader(r, sigMultilineTimeout, tout)
+	}
+
+	sr := &stacktraceReader{
+		reader:    r,
+		detector:  detector,
+		msgBuffer: newMessageBuffer(maxBytes, maxLines, []byte(separator), config.SkipNewLine),
+		logger:    logp.NewLogger("reader_multiline"),
+		state:     (*stacktraceReader).readFirst,
+	}
+	return sr, nil
+}
+
+// Next returns next multi-line event.
+func (sr *stacktraceReader) Next() (reader.Message, error) {
+	return sr.state(sr)
+}
+
+func (sr *stacktraceReader) readFirst() (reader.Message, error) {
+	for {
+		message, err := sr.reader.Next()
+		if err != nil {
+			// no lines buffered -> ignore timeout
+			if err == sigMultilineTimeout {
+				continue
+			}
+
+			// pass error to caller (next layer) for handling
+			return message, err
+		}
+
+		if message.Bytes == 0 {
+			continue
+		}
+
+		// not the start of a stack trace, return message
+		if !sr.detector.start(message.Content) {
+			return message, nil
+		}
+
+		// Start new multiline event
+		sr.msgBuffer.startNewMessage(message)
+		sr.setState((*stacktraceReader).readNext)
+		return sr.readNext()
+	}
+}
+
+func (sr *stacktraceReader) readNext() (reader.Message, error) {
+	for {
+		message, err := sr.reader.Next()
+		if err != nil {
+			// handle multiline timeout signal
+			if err == sigMultilineTimeout {
+				// no lines buffered -> ignore timeout
+				if sr.msgBuffer.isEmpty() {
+					continue
+				}
+
+				sr.logger.Debug("Multiline event flushed because timeout reached.")
+
+				// return collected multiline event and
+				// empty buffer for new multiline event
+				msg := sr.msgBuffer.finalize()
+				sr.resetState()
+				return msg, nil
+			}
+
+			// handle error without any bytes returned from reader
+			if message.Bytes == 0 {
+				// no lines buffered -> return error
+				if sr.msgBuffer.isEmpty() {
+					return reader.Message{}, err
+				}
+
+				// lines buffered, return multiline and error on next read
+				return sr.collectMessageAfterError(err)
+			}
+
+			// handle error with some content being returned by reader and
+			// line continuing the stack trace
+			if sr.detector.update(message.Content) {
+				sr.msgBuffer.addLine(message)
+
+				// return multiline and error on next read
+				return sr.collectMessageAfterError(err)
+			}
+
+			// no match, return current multiline and return current line on next
+			// call to readNext
+			msg := sr.msgBuffer.finalize()
+			sr.msgBuffer.load(message)
+			sr.setState((*stacktraceReader).notMatchedMessageLoad)
+			return msg, nil
+		}
+
+		// add line to current stack trace
+		if sr.detector.update(message.Content) {
+			sr.msgBuffer.addLine(message)
+			continue
+		}
+
+		// the stack trace is complete, return it. The current line either
+		// starts a new stack trace or is returned on the next call to Next.
+		msg := sr.msgBuffer.finalize()
+		sr.msgBuffer.load(message)
+		if sr.detector.start(message.Content) {
+			return msg, nil
+		}
+		sr.setState((*stacktraceReader).notMatchedMessageLoad)
+		return msg, nil
+	}
+}
+
+func (sr *stacktraceReader) collectMessageAfterError(err error) (reader.Message, error) {
+	msg := sr.msgBuffer.finalize()
+	sr.msgBuffer.setErr(err)
+	sr.setState((*stacktraceReader).readFailed)
+	return msg, nil
+}
+
+// readFailed returns empty message and error and resets line reader
+func (sr *stacktraceReader) readFailed() (reader.Message, error) {
+	err := sr.msgBuffer.err
+	sr.msgBuffer.setErr(nil)
+	sr.resetState()
+	return reader.Message{}, err
+}
+
+// notMatchedMessageLoad returns not matched message from buffer
+func (sr *stacktraceReader) notMatchedMessageLoad() (reader.Message, error) {
+	msg := sr.msgBuffer.finalize()
+	sr.resetState()
+	return msg, nil
+}
+
+// resetState sets state of the reader to readFirst
+func (sr *stacktraceReader) resetState() {
+	sr.setState((*stacktraceReader).readFirst)
+}
+
+// setState sets state to the given function
+func (sr *stacktraceReader) setState(next func(sr *stacktraceReader) (reader.Message, error)) {
+	sr.state = next
+}
+
+func (sr *stacktraceReader) Close() error {
+	sr.setState((*stacktraceReader).readClosed)
+	return sr.reader.Close()
+}
+
+func (sr *stacktraceReader) readClosed() (reader.Message, error) {
+	return reader.Message{}, io.EOF
+}
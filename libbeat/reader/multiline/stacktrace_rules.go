@@ -0,0 +1,169 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package multiline
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// traceState is a state of the stack trace detector. States are prefixed
+// with the name of the rule set defining them.
+type traceState string
+
+const startState traceState = "start"
+
+// traceRule moves the detector from one of the from states to the to state
+// when a line matches its pattern.
+type traceRule struct {
+	from    []traceState
+	pattern *regexp.Regexp
+	to      traceState
+}
+
+func rule(to traceState, pattern string, from ...traceState) traceRule {
+	return traceRule{from: from, pattern: regexp.MustCompile(pattern), to: to}
+}
+
+// traceRules holds the built-in rule sets, by language. A line continues a
+// stack trace if a rule of the current states matches it. The first line of
+// a stack trace must match a rule of the start state.
+var traceRules = map[string][]traceRule{
+	"java": {
+		rule("java_after_exception", `(?:Exception|Error|Throwable|V8 errors stack trace)(?::|$)`, startState),
+		rule("java", `^[\t ]+(?:eval )?at `, "java_after_exception", "java"),
+		rule("java_after_exception", `^[\t ]*(?:Caused by|Suppressed):`, "java"),
+		rule("java", `^[\t ]*\.\.\. \d+ (?:more|common frames omitted)`, "java"),
+	},
+	"python": {
+		rule("python", `^Traceback \(most recent call last\):$`, startState),
+		rule("python_code", `^[\t ]+File `, "python", "python_code"),
+		rule("python", `^[\t ]+\S`, "python_code"),
+		rule("python_end", `^[A-Za-z_][\w.]*(?::.*)?$`, "python", "python_code"),
+	},
+	"go": {
+		rule("go_after_panic", `^(?:panic|fatal error): `, startState),
+		rule("go_after_panic", `^(?:\tpanic: |\[signal )`, "go_after_panic"),
+		rule("go_func", `^goroutine \d+ \[[^\]]+\]:$`, startState, "go_after_panic", "go_between_goroutines"),
+		rule("go_between_goroutines", `^$`, "go_after_panic", "go_func"),
+		rule("go_file", `^(?:created by \S+(?: in goroutine \d+)?|[\w./\-*()\[\]]+\(.*\))$`, "go_func"),
+		rule("go_func", `^\t\S.*:\d+(?: \+0x[0-9a-f]+)?$`, "go_file"),
+		rule("go_func", `^\.\.\.additional frames elided\.\.\.$`, "go_func"),
+	},
+	"nodejs": {
+		rule("nodejs_after_error", `^(?:Uncaught )?(?:[\w$.]*Error|Exception)(?: \[[\w-]+\])?(?::|$)`, startState),
+		rule("nodejs", `^[\t ]+at `, "nodejs_after_error", "nodejs"),
+		rule("nodejs_after_error", `^[\t ]*\[cause\]: `, "nodejs"),
+	},
+	"ruby": {
+		rule("ruby", `^\S.*:\d+:in [`+"`"+`'].*: .* \([\w:]+\)$`, startState),
+		rule("ruby", `^[A-Z][\w:]*(?:Error|Exception) \(.*\):?$`, startState),
+		rule("ruby", `^[\t ]*(?:from )?\S+:\d+:in [`+"`"+`']`, "ruby"),
+	},
+	"dotnet": {
+		rule("dotnet_after_exception", `(?:^|\s)(?:[\w.]+\.)?[\w]*Exception(?::|$)`, startState),
+		rule("dotnet", `^[\t ]+at `, "dotnet_after_exception", "dotnet"),
+		rule("dotnet_after_exception", `^[\t ]*---> `, "dotnet_after_exception", "dotnet"),
+		rule("dotnet", `^[\t ]*--- End of .* ---$`, "dotnet"),
+	},
+}
+
+// traceDetector recognizes stack traces using the rules of the configured
+// languages. It can be in several states at once, as the rule sets of
+// different languages can match the same lines.
+type traceDetector struct {
+	rules  []traceRule
+	states []traceState
+}
+
+func newTraceDetector(languages []string) (*traceDetector, error) {
+	if len(languages) == 0 {
+		languages = traceLanguages()
+	}
+	if err := validateTraceLanguages(languages); err != nil {
+		return nil, err
+	}
+	d := &traceDetector{}
+	for _, lang := range languages {
+		d.rules = append(d.rules, traceRules[lang]...)
+	}
+	return d, nil
+}
+
+// traceLanguages returns the names of the built-in rule sets.
+func traceLanguages() []string {
+	names := make([]string, 0, len(traceRules))
+	for name := range traceRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// start resets the detector and reports whether line starts a stack trace.
+func (d *traceDetector) start(line []byte) bool {
+	d.states = d.next([]traceState{startState}, line)
+	return len(d.states) > 0
+}
+
+// update reports whether line continues the current stack trace.
+func (d *traceDetector) update(line []byte) bool {
+	if len(d.states) == 0 {
+		return false
+	}
+	d.states = d.next(d.states, line)
+	return len(d.states) > 0
+}
+
+func (d *traceDetector) next(states []traceState, line []byte) []traceState {
+	line = bytes.TrimRight(line, "\r\n")
+	var next []traceState
+	for _, r := range d.rules {
+		if !hasState(r.from, states) || hasState(next, []traceState{r.to}) {
+			continue
+		}
+		if r.pattern.Match(line) {
+			next = append(next, r.to)
+		}
+	}
+	return next
+}
+
+func hasState(states, want []traceState) bool {
+	for _, s := range states {
+		for _, w := range want {
+			if s == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func validateTraceLanguages(languages []string) error {
+	for _, lang := range languages {
+		if _, ok := traceRules[lang]; !ok {
+			return fmt.Errorf("unknown stack trace language: %s, supported languages are %s",
+				lang, strings.Join(traceLanguages(), ", "))
+		}
+	}
+	return nil
+}
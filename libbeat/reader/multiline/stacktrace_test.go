@@ -0,0 +1,144 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !integration
+
+package multiline
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	javaTrace = `Exception in thread "main" java.lang.IllegalStateException: A book has a null property
+	at com.example.myproject.Author.getBookIds(Author.java:38)
+	at com.example.myproject.Bootstrap.main(Bootstrap.java:14)
+Caused by: java.lang.NullPointerException
+	at com.example.myproject.Book.getId(Book.java:22)
+	at com.example.myproject.Author.getBookIds(Author.java:35)
+	... 1 more
+`
+	pythonTrace = `Traceback (most recent call last):
+  File "/app/main.py", line 10, in <module>
+    main()
+  File "/app/main.py", line 6, in main
+    raise ValueError("invalid value")
+ValueError: invalid value
+`
+	goPanic = `panic: runtime error: invalid memory address or nil pointer dereference
+[signal SIGSEGV: segmentation violation code=0x1 addr=0x0 pc=0x48f1f6]
+
+goroutine 1 [running]:
+main.(*server).handle(0x0, {0x4c2b60, 0xc000012345})
+	/app/main.go:21 +0x16
+main.main()
+	/app/main.go:12 +0x25
+
+goroutine 6 [chan receive]:
+main.worker()
+	/app/worker.go:8 +0x2a
+created by main.main in goroutine 1
+	/app/main.go:10 +0x1e
+`
+	nodeTrace = `TypeError: Cannot read properties of undefined (reading 'id')
+    at getUser (/app/src/users.js:12:21)
+    at async Promise.all (index 0)
+    at async main (/app/src/index.js:5:3)
+`
+	rubyTrace = `app.rb:3:in 'divide': divided by 0 (ZeroDivisionError)
+	from app.rb:3:in 'Integer#/'
+	from app.rb:7:in '<main>'
+`
+	dotnetTrace = `System.InvalidOperationException: Operation failed
+ ---> System.ArgumentNullException: Value cannot be null. (Parameter 'name')
+   at Example.Service.Lookup(String name) in /src/Service.cs:line 42
+   --- End of inner exception stack trace ---
+   at Example.Service.Run() in /src/Service.cs:line 17
+   at Example.Program.Main(String[] args) in /src/Program.cs:line 8
+`
+)
+
+func TestMultilineStacktrace(t *testing.T) {
+	cases := map[string]string{
+		"java":   javaTrace,
+		"python": pythonTrace,
+		"go":     goPanic,
+		"nodejs": nodeTrace,
+		"ruby":   rubyTrace,
+		"dotnet": dotnetTrace,
+	}
+	for name, trace := range cases {
+		t.Run(name, func(t *testing.T) {
+			testMultilineOK(t,
+				Config{Type: stacktraceMode},
+				3,
+				"2024-05-01 12:00:00 ERROR request failed\n",
+				trace,
+				"2024-05-01 12:00:01 INFO next request\n",
+			)
+		})
+	}
+}
+
+func TestMultilineStacktraceConsecutive(t *testing.T) {
+	testMultilineOK(t,
+		Config{Type: stacktraceMode},
+		4,
+		javaTrace,
+		pythonTrace,
+		goPanic,
+		"done\n",
+	)
+}
+
+func TestMultilineStacktraceLanguages(t *testing.T) {
+	// Python tracebacks are not recognized when only Java is enabled.
+	lines := strings.SplitAfter(pythonTrace, "\n")
+	lines = lines[:len(lines)-1]
+	testMultilineOK(t,
+		Config{Type: stacktraceMode, Languages: []string{"java"}},
+		len(lines),
+		lines...,
+	)
+}
+
+func TestMultilineStacktraceTruncated(t *testing.T) {
+	maxLines := 2
+	testMultilineTruncated(t,
+		Config{Type: stacktraceMode, MaxLines: &maxLines},
+		1,
+		true,
+		[]string{nodeTrace},
+		[]string{"TypeError: Cannot read properties of undefined (reading 'id')\n    at getUser (/app/src/users.js:12:21)\n"},
+	)
+}
+
+func TestTraceDetectorStartOnly(t *testing.T) {
+	d, err := newTraceDetector(nil)
+	assert.NoError(t, err)
+
+	// A line looking like the start of a stack trace, not followed by
+	// frames, is not combined with the next line.
+	assert.True(t, d.start([]byte("java.io.IOException: broken pipe")))
+	assert.False(t, d.update([]byte("2024-05-01 12:00:01 INFO next request")))
+
+	assert.False(t, d.start([]byte("2024-05-01 12:00:01 INFO next request")))
+	assert.False(t, d.update([]byte("\tat com.example.Foo.bar(Foo.java:1)")))
+}
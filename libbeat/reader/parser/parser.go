@@ -28,6 +28,7 @@ import (
 	"github.com/elastic/beats/v7/libbeat/reader"
 	"github.com/elastic/beats/v7/libbeat/reader/filter"
 	"github.com/elastic/beats/v7/libbeat/reader/multiline"
+	"github.com/elastic/beats/v7/libbeat/reader/readcsv"
 	"github.com/elastic/beats/v7/libbeat/reader/readfile"
 	"github.com/elastic/beats/v7/libbeat/reader/readjson"
 	"github.com/elastic/beats/v7/libbeat/reader/syslog"
@@ -45,6 +46,13 @@ type Parser interface {
 	Next() (reader.Message, error)
 }
 
+// State holds the state of the parsers that must be persisted by inputs
+// resuming reading in the middle of a source, like the header read by the
+// csv parser.
+type State struct {
+	CSVHeader []string `json:"csv_header,omitempty" struct:"csv_header,omitempty"`
+}
+
 type CommonConfig struct {
 	MaxBytes       cfgtype.ByteSize        `config:"max_bytes"`
 	LineTerminator readfile.LineTerminator `config:"line_terminator"`
@@ -128,6 +136,13 @@ func NewConfig(pCfg CommonConfig, parsers []config.Namespace) (*Config, error) {
 			if err != nil {
 				return nil, fmt.Errorf("error while parsing include_message parser config: %w", err)
 			}
+		case "csv":
+			config := readcsv.DefaultConfig()
+			cfg := ns.Config()
+			err := cfg.Unpack(&config)
+			if err != nil {
+				return nil, fmt.Errorf("error while parsing csv parser config: %w", err)
+			}
 		default:
 			return nil, fmt.Errorf("%s: %w", name, ErrNoSuchParser)
 		}
@@ -142,6 +157,16 @@ func NewConfig(pCfg CommonConfig, parsers []config.Namespace) (*Config, error) {
 }
 
 func (c *Config) Create(in reader.Reader) Parser {
+	return c.CreateWithState(in, nil)
+}
+
+// CreateWithState creates the parsers with a previously persisted state.
+// The parsers update state as they read, so it can be persisted with the
+// position of the input in the source.
+func (c *Config) CreateWithState(in reader.Reader, state *State) Parser {
+	if state == nil {
+		state = &State{}
+	}
 	p := in
 	for _, ns := range c.parsers {
 		name := ns.Name()
@@ -189,6 +214,14 @@ func (c *Config) Create(in reader.Reader) Parser {
 				return p
 			}
 			p = filter.NewParser(p, &config)
+		case "csv":
+			config := readcsv.DefaultConfig()
+			cfg := ns.Config()
+			err := cfg.Unpack(&config)
+			if err != nil {
+				return p
+			}
+			p = readcsv.NewParser(p, &config, &state.CSVHeader)
 		default:
 			return p
 		}
@@ -682,6 +682,51 @@ func TestContainerParser(t *testing.T) {
 				},
 			},
 		},
+		"docker lines with a stack trace": {
+			lines: `{"log":"Starting server\n","stream":"stderr","time":"2016-03-02T22:58:51.338462311Z"}
+{"log":"panic: boom\n","stream":"stderr","time":"2016-03-02T22:58:51.338462312Z"}
+{"log":"\n","stream":"stderr","time":"2016-03-02T22:58:51.338462313Z"}
+{"log":"goroutine 1 [running]:\n","stream":"stderr","time":"2016-03-02T22:58:51.338462314Z"}
+{"log":"main.main()\n","stream":"stderr","time":"2016-03-02T22:58:51.338462315Z"}
+{"log":"\t/app/main.go:5 +0x25\n","stream":"stderr","time":"2016-03-02T22:58:51.338462316Z"}
+{"log":"exit status 2\n","stream":"stderr","time":"2016-03-02T22:58:51.338462317Z"}
+`,
+			parsers: map[string]interface{}{
+				"parsers": []map[string]interface{}{
+					map[string]interface{}{
+						"container": map[string]interface{}{},
+					},
+					map[string]interface{}{
+						"multiline": map[string]interface{}{
+							"type": "stacktrace",
+						},
+					},
+				},
+			},
+			expectedMessages: []reader.Message{
+				reader.Message{
+					Content: []byte("Starting server\n"),
+					Fields: mapstr.M{
+						"stream": "stderr",
+					},
+				},
+				reader.Message{
+					Content: []byte("panic: boom\n\n\n\ngoroutine 1 [running]:\n\nmain.main()\n\n\t/app/main.go:5 +0x25\n"),
+					Fields: mapstr.M{
+						"log": mapstr.M{
+							"flags": []string{"multiline"},
+						},
+						"stream": "stderr",
+					},
+				},
+				reader.Message{
+					Content: []byte("exit status 2\n"),
+					Fields: mapstr.M{
+						"stream": "stderr",
+					},
+				},
+			},
+		},
 		"corrupt docker lines are skipped": {
 			lines: `{"log":"Fetching main repository github.com/elastic/beats...\n","stream":"stdout","time":"2016-03-02T22:58:51.338462311Z"}
 "log":"Fetching dependencies...\n","stream":"stdout","time":"2016-03-02T22:59:04.609292428Z"}
@@ -768,6 +813,116 @@ func TestParserIncludeMessages(t *testing.T) {
 	require.Equal(t, expectedMessages, readMsgs, "fii")
 }
 
+func TestJSONParserConditions(t *testing.T) {
+	parserConfig := map[string]interface{}{
+		"parsers": []map[string]interface{}{
+			{
+				"ndjson": map[string]interface{}{
+					"target": "",
+					"include": map[string]interface{}{
+						"or": []map[string]interface{}{
+							{"equals.level": "error"},
+							{"range.http.status.gte": 500},
+						},
+					},
+					"exclude": map[string]interface{}{
+						"contains.msg": "health",
+					},
+				},
+			},
+		},
+	}
+
+	lines := `{"level":"info","msg":"started"}
+{"level":"error","msg":"failed","id":1}
+{"level":"error","msg":"health check failed"}
+{"level":"info","http":{"status":503},"id":2}
+{"level":"debug"}
+`
+
+	cfg := config.MustNewConfigFrom(parserConfig)
+	var c inputParsersConfig
+	err := cfg.Unpack(&c)
+	require.NoError(t, err)
+
+	p := c.Parsers.Create(testReader(lines))
+
+	var ids []interface{}
+	var offsets []int
+	msg, err := p.Next()
+	for err == nil {
+		id, _ := msg.Fields.GetValue("id")
+		ids = append(ids, id)
+		offsets = append(offsets, msg.Offset)
+		msg, err = p.Next()
+	}
+	require.ErrorIs(t, err, io.EOF)
+
+	require.Equal(t, []interface{}{int64(1), int64(2)}, ids)
+	// The bytes of the dropped lines are reported in the offset of the
+	// following message.
+	require.Equal(t, []int{len(`{"level":"info","msg":"started"}` + "\n"), len(`{"level":"error","msg":"health check failed"}` + "\n")}, offsets)
+}
+
+func TestJSONParserConditionsMalformed(t *testing.T) {
+	lines := `{"level":"info"}
+{"level":"error"
+{"level":"error","id":1}
+`
+
+	for name, condition := range map[string]map[string]interface{}{
+		"include": {"include": map[string]interface{}{"equals.level": "error"}},
+		"exclude": {"exclude": map[string]interface{}{"equals.level": "info"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ndjson := map[string]interface{}{"target": "", "add_error_key": true}
+			for k, v := range condition {
+				ndjson[k] = v
+			}
+			cfg := config.MustNewConfigFrom(map[string]interface{}{
+				"parsers": []map[string]interface{}{{"ndjson": ndjson}},
+			})
+			var c inputParsersConfig
+			require.NoError(t, cfg.Unpack(&c))
+
+			p := c.Parsers.Create(testReader(lines))
+
+			// The line that is not valid JSON is kept with the decoding error.
+			msg, err := p.Next()
+			require.NoError(t, err)
+			require.Equal(t, "{\"level\":\"error\"\n", msg.Fields["message"])
+			errMsg, err := msg.Fields.GetValue("error.message")
+			require.NoError(t, err)
+			require.Contains(t, errMsg, "Error decoding JSON")
+
+			msg, err = p.Next()
+			require.NoError(t, err)
+			id, _ := msg.Fields.GetValue("id")
+			require.Equal(t, int64(1), id)
+
+			_, err = p.Next()
+			require.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func TestJSONParserConditionsConfig(t *testing.T) {
+	cfg := config.MustNewConfigFrom(map[string]interface{}{
+		"parsers": []map[string]interface{}{
+			{
+				"ndjson": map[string]interface{}{
+					"include": map[string]interface{}{
+						"unknown.field": "value",
+					},
+				},
+			},
+		},
+	})
+	var c inputParsersConfig
+	err := cfg.Unpack(&c)
+	require.ErrorContains(t, err, "invalid include condition")
+}
+
 type testParsersConfig struct {
 	Parsers []config.Namespace `struct:"parsers"`
 }
@@ -816,3 +971,32 @@ func (r *messageReader) Close() error {
 	r.read = false
 	return nil
 }
+
+func TestCSVParserState(t *testing.T) {
+	cfg := config.MustNewConfigFrom(map[string]interface{}{
+		"parsers": []map[string]interface{}{
+			{
+				"csv": map[string]interface{}{},
+			},
+		},
+	})
+	var parsersConfig testParsersConfig
+	err := cfg.Unpack(&parsersConfig)
+	require.NoError(t, err)
+	c, err := NewConfig(CommonConfig{MaxBytes: 1024, LineTerminator: readfile.AutoLineTerminator}, parsersConfig.Parsers)
+	require.NoError(t, err)
+
+	// The header read from the first line is stored in the state.
+	var state State
+	p := c.CreateWithState(testReader("host,status\nweb-1,up\n"), &state)
+	msg, err := p.Next()
+	require.NoError(t, err)
+	require.Equal(t, mapstr.M{"csv": mapstr.M{"host": "web-1", "status": "up"}}, msg.Fields)
+	require.Equal(t, []string{"host", "status"}, state.CSVHeader)
+
+	// Reading resumes after the header with the stored state.
+	p = c.CreateWithState(testReader("db-1,down\n"), &state)
+	msg, err = p.Next()
+	require.NoError(t, err)
+	require.Equal(t, mapstr.M{"csv": mapstr.M{"host": "db-1", "status": "down"}}, msg.Fields)
+}
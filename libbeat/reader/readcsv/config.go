@@ -0,0 +1,53 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package readcsv
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// Config holds the options of the csv parser.
+type Config struct {
+	Separator        string   `config:"separator"`
+	Columns          []string `config:"columns"`
+	Target           string   `config:"target"`
+	TrimLeadingSpace bool     `config:"trim_leading_space"`
+	InferTypes       bool     `config:"infer_types"`
+}
+
+// DefaultConfig returns the default configuration of the csv parser.
+func DefaultConfig() Config {
+	return Config{
+		Separator: ",",
+		Target:    "csv",
+	}
+}
+
+// Validate validates the Config option for the csv parser.
+func (c *Config) Validate() error {
+	if utf8.RuneCountInString(c.Separator) != 1 {
+		return errors.New("separator must be a single character")
+	}
+	for _, column := range c.Columns {
+		if column == "" {
+			return errors.New("column names must not be empty")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package readcsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/elastic/beats/v7/libbeat/reader"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// Parser decodes the lines of a CSV file into fields named after the
+// columns of the file. Unless the columns are configured, the first
+// non-empty line is the header of the file. It is stored in the header passed to
+// NewParser, so it can be persisted and passed again when reading resumes
+// in the middle of the file.
+//
+// The header line is not returned, its bytes are accounted for in the
+// offset of the next message. Each line is decoded on its own, quoted values
+// spanning several lines are not supported.
+type Parser struct {
+	reader    reader.Reader
+	separator rune
+	target    string
+	trim      bool
+	infer     bool
+	columns   []string
+	header    *[]string
+	logger    *logp.Logger
+}
+
+// NewParser creates a new csv parser. header holds the header read from the
+// file so far, it is nil when the file is read from its beginning.
+func NewParser(r reader.Reader, c *Config, header *[]string) *Parser {
+	if header == nil {
+		header = new([]string)
+	}
+	sep, _ := utf8.DecodeRuneInString(c.Separator)
+	return &Parser{
+		reader:    r,
+		separator: sep,
+		target:    c.Target,
+		trim:      c.TrimLeadingSpace,
+		infer:     c.InferTypes,
+		columns:   c.Columns,
+		header:    header,
+		logger:    logp.NewLogger("parser_csv"),
+	}
+}
+
+// Next returns the next line with the decoded values added to its fields.
+func (p *Parser) Next() (message reader.Message, err error) {
+	// discardedOffset accounts for the bytes of the header line, which is
+	// not returned. The inputs need it to correctly track the file offset.
+	var discardedOffset int
+	defer func() {
+		message.Offset += discardedOffset
+	}()
+
+	for {
+		message, err = p.reader.Next()
+		if err != nil {
+			return message, err
+		}
+
+		content := bytes.TrimRight(message.Content, "\r\n")
+		if len(bytes.TrimSpace(content)) == 0 {
+			return message, nil
+		}
+
+		if len(p.columns) == 0 && len(*p.header) == 0 {
+			header, err := p.decode(bytes.TrimPrefix(content, utf8BOM))
+			if err != nil {
+				p.logger.Errorf("Error decoding CSV header: %v", err)
+				message.AddFields(mapstr.M{"error": mapstr.M{"message": fmt.Sprintf("Error decoding CSV header: %v", err)}})
+				return message, nil
+			}
+			*p.header = header
+			discardedOffset += message.Bytes + message.Offset
+			continue
+		}
+
+		values, err := p.decode(content)
+		if err != nil {
+			p.logger.Debugf("Error decoding CSV line: %v", err)
+			message.AddFields(mapstr.M{"error": mapstr.M{"message": fmt.Sprintf("Error decoding CSV line: %v", err)}})
+			return message, nil
+		}
+
+		fields := p.fields(values)
+		if p.target == "" {
+			message.AddFields(fields)
+		} else {
+			message.AddFields(mapstr.M{p.target: fields})
+		}
+		return message, nil
+	}
+}
+
+func (p *Parser) decode(line []byte) ([]string, error) {
+	r := csv.NewReader(bytes.NewReader(line))
+	r.Comma = p.separator
+	r.TrimLeadingSpace = p.trim
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	return r.Read()
+}
+
+// fields maps the values of a line to the columns of the header. Values
+// beyond the last column are named after their position, starting at 1.
+func (p *Parser) fields(values []string) mapstr.M {
+	header := p.columns
+	if len(header) == 0 {
+		header = *p.header
+	}
+	fields := make(mapstr.M, len(values))
+	for i, value := range values {
+		name := "column" + strconv.Itoa(i+1)
+		if i < len(header) && header[i] != "" {
+			name = header[i]
+		}
+		if p.infer {
+			fields[name] = inferType(value)
+		} else {
+			fields[name] = value
+		}
+	}
+	return fields
+}
+
+// inferType converts value to a boolean or a number if it has the syntax
+// of one, otherwise it is returned as is.
+func inferType(value string) interface{} {
+	switch {
+	case value == "":
+		return value
+	case strings.EqualFold(value, "true"):
+		return true
+	case strings.EqualFold(value, "false"):
+		return false
+	}
+	if !strings.ContainsAny(value[:1], "0123456789+-.") {
+		return value
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil && !strings.ContainsAny(value, "nNiIxX_") {
+		return f
+	}
+	return value
+}
+
+func (p *Parser) Close() error {
+	return p.reader.Close()
+}
@@ -0,0 +1,156 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package readcsv
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/reader"
+	"github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+func TestParser(t *testing.T) {
+	tests := map[string]struct {
+		config         map[string]interface{}
+		header         []string
+		input          []string
+		expectedHeader []string
+		expectedFields []mapstr.M
+	}{
+		"header from first line": {
+			input:          []string{"\xef\xbb\xbfhost,status,latency", "web-1,up,12", "web-2,\"down, restarting\",", "web-3,up,7,extra"},
+			expectedHeader: []string{"host", "status", "latency"},
+			expectedFields: []mapstr.M{
+				{"csv": mapstr.M{"host": "web-1", "status": "up", "latency": "12"}},
+				{"csv": mapstr.M{"host": "web-2", "status": "down, restarting", "latency": ""}},
+				{"csv": mapstr.M{"host": "web-3", "status": "up", "latency": "7", "column4": "extra"}},
+			},
+		},
+		"header from state": {
+			header:         []string{"host", "status"},
+			input:          []string{"web-1,up"},
+			expectedHeader: []string{"host", "status"},
+			expectedFields: []mapstr.M{
+				{"csv": mapstr.M{"host": "web-1", "status": "up"}},
+			},
+		},
+		"configured columns": {
+			config:         map[string]interface{}{"columns": []string{"a", "b"}, "target": ""},
+			input:          []string{"1,2", "3"},
+			expectedHeader: nil,
+			expectedFields: []mapstr.M{
+				{"a": "1", "b": "2"},
+				{"a": "3"},
+			},
+		},
+		"empty lines before header": {
+			config:         map[string]interface{}{"separator": ";", "trim_leading_space": true},
+			input:          []string{"", "x; y", "1; 2"},
+			expectedHeader: []string{"x", "y"},
+			expectedFields: []mapstr.M{
+				nil,
+				{"csv": mapstr.M{"x": "1", "y": "2"}},
+			},
+		},
+		"infer types": {
+			config:         map[string]interface{}{"infer_types": true},
+			header:         []string{"int", "float", "bool", "string", "nan", "empty"},
+			input:          []string{"-42,1.5e3,TRUE,0x10,NaN,"},
+			expectedHeader: []string{"int", "float", "bool", "string", "nan", "empty"},
+			expectedFields: []mapstr.M{
+				{"csv": mapstr.M{"int": int64(-42), "float": 1500.0, "bool": true, "string": "0x10", "nan": "NaN", "empty": ""}},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := DefaultConfig()
+			err := config.MustNewConfigFrom(test.config).Unpack(&c)
+			require.NoError(t, err)
+
+			header := test.header
+			p := NewParser(newTestReader(test.input), &c, &header)
+
+			var fields []mapstr.M
+			msg, err := p.Next()
+			for err == nil {
+				fields = append(fields, msg.Fields)
+				msg, err = p.Next()
+			}
+			require.ErrorIs(t, err, io.EOF)
+			assert.Equal(t, test.expectedFields, fields)
+			assert.Equal(t, test.expectedHeader, header)
+		})
+	}
+}
+
+func TestParserHeaderBytes(t *testing.T) {
+	c := DefaultConfig()
+	p := NewParser(newTestReader([]string{"a,b", "1,2", "3,4"}), &c, nil)
+
+	msg, err := p.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "1,2", string(msg.Content))
+	assert.Equal(t, 4, msg.Bytes)
+	assert.Equal(t, 4, msg.Offset, "the header must be accounted for in the offset")
+
+	msg, err = p.Next()
+	require.NoError(t, err)
+	assert.Equal(t, 0, msg.Offset)
+}
+
+func TestConfigValidate(t *testing.T) {
+	for name, cfg := range map[string]map[string]interface{}{
+		"empty separator":    {"separator": ""},
+		"long separator":     {"separator": "::"},
+		"empty column names": {"columns": []string{"a", ""}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := DefaultConfig()
+			err := config.MustNewConfigFrom(cfg).Unpack(&c)
+			assert.Error(t, err)
+		})
+	}
+}
+
+type testReader struct {
+	lines []string
+	idx   int
+}
+
+func newTestReader(lines []string) reader.Reader {
+	return &testReader{lines: lines}
+}
+
+func (r *testReader) Next() (reader.Message, error) {
+	if r.idx == len(r.lines) {
+		return reader.Message{}, io.EOF
+	}
+
+	line := r.lines[r.idx]
+	r.idx++
+	return reader.Message{Content: []byte(line), Bytes: len(line) + 1}, nil
+}
+
+func (r *testReader) Close() error { return nil }
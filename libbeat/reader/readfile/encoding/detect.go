@@ -0,0 +1,135 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package encoding
+
+import (
+	"io"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// detectSampleSize is the number of bytes read from the start of a file to
+// detect its encoding.
+const detectSampleSize = 1024
+
+// Encodings returned by Detect, by name. A leading UTF-8 BOM is removed by
+// the encode reader, leading UTF-16 BOMs are removed by the decoders.
+var detectedEncodings = map[string]Encoding{
+	"utf-8":    mixed{},
+	"utf-16le": utf16StripBOM(unicode.LittleEndian),
+	"utf-16be": utf16StripBOM(unicode.BigEndian),
+}
+
+// bomStripping decodes with an encoding removing a leading BOM. The
+// encoder, used to encode line terminators, must not add a BOM.
+type bomStripping struct {
+	Encoding
+	decoding Encoding
+}
+
+func utf16StripBOM(e unicode.Endianness) Encoding {
+	return bomStripping{
+		Encoding: unicode.UTF16(e, unicode.IgnoreBOM),
+		decoding: unicode.UTF16(e, unicode.UseBOM),
+	}
+}
+
+func (e bomStripping) NewDecoder() *encoding.Decoder {
+	return e.decoding.NewDecoder()
+}
+
+// autoDetect detects the encoding of a seekable data source.
+func autoDetect(in_ io.Reader) (Encoding, error) {
+	in, ok := in_.(io.ReadSeeker)
+	if !ok {
+		return nil, ErrUnsupportedSourceTypeBOM
+	}
+	_, encoding, err := Detect(in)
+	return encoding, err
+}
+
+// Detect sniffs the encoding of in from the Byte Order Marker or the null
+// bytes pattern at the beginning of the data source. UTF-8 is assumed if no
+// UTF-16 encoding is detected. The read offset of in is restored.
+//
+// The returned name can be passed to Detected to get the same encoding
+// without reading the data source again.
+func Detect(in io.ReadSeeker) (string, Encoding, error) {
+	offset, err := in.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err = in.Seek(0, io.SeekStart); err != nil {
+		return "", nil, err
+	}
+
+	buf := make([]byte, detectSampleSize)
+	n, err := io.ReadFull(in, buf)
+	if _, serr := in.Seek(offset, io.SeekStart); serr != nil {
+		return "", nil, serr
+	}
+	switch {
+	case n == 0 && (err == nil || err == io.EOF):
+		return "", nil, transform.ErrShortSrc
+	case err != nil && err != io.EOF && err != io.ErrUnexpectedEOF:
+		return "", nil, err
+	}
+
+	name := detect(buf[:n])
+	return name, detectedEncodings[name], nil
+}
+
+// Detected returns the encoding for a name returned by Detect.
+func Detected(name string) (Encoding, bool) {
+	encoding, ok := detectedEncodings[name]
+	return encoding, ok
+}
+
+func detect(buf []byte) string {
+	switch {
+	case len(buf) >= 2 && buf[0] == 0xff && buf[1] == 0xfe:
+		return "utf-16le"
+	case len(buf) >= 2 && buf[0] == 0xfe && buf[1] == 0xff:
+		return "utf-16be"
+	}
+
+	// Text in UTF-16 is mostly made of characters of the Basic Latin and
+	// Latin-1 blocks, having one of their two bytes set to zero. UTF-8 text
+	// contains no null bytes.
+	var evenZeros, oddZeros int
+	pairs := len(buf) / 2
+	for i := 0; i < 2*pairs; i += 2 {
+		if buf[i] == 0 {
+			evenZeros++
+		}
+		if buf[i+1] == 0 {
+			oddZeros++
+		}
+	}
+	switch {
+	case pairs == 0:
+		return "utf-8"
+	case 2*oddZeros >= pairs && 10*evenZeros <= oddZeros:
+		return "utf-16le"
+	case 2*evenZeros >= pairs && 10*oddZeros <= evenZeros:
+		return "utf-16be"
+	}
+	return "utf-8"
+}
@@ -0,0 +1,89 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !integration
+
+package encoding
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+func TestDetect(t *testing.T) {
+	text := "hello world\nsecond line\n"
+	encode := func(enc Encoding) []byte {
+		buf := bytes.NewBuffer(nil)
+		writer := transform.NewWriter(buf, enc.NewEncoder())
+		writer.Write([]byte(text))
+		writer.Close()
+		return buf.Bytes()
+	}
+
+	tests := map[string]struct {
+		data     []byte
+		expected string
+		decoded  string
+	}{
+		"utf-8":                {[]byte(text), "utf-8", text},
+		"utf-8 with bom":       {append([]byte{0xef, 0xbb, 0xbf}, text...), "utf-8", "\ufeff" + text},
+		"utf-8 single byte":    {[]byte("a"), "utf-8", "a"},
+		"utf-8 non ascii":      {[]byte("Grüße aus Köln\n"), "utf-8", "Grüße aus Köln\n"},
+		"utf-16le with bom":    {encode(unicode.UTF16(unicode.LittleEndian, unicode.UseBOM)), "utf-16le", text},
+		"utf-16be with bom":    {encode(unicode.UTF16(unicode.BigEndian, unicode.UseBOM)), "utf-16be", text},
+		"utf-16le without bom": {encode(unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)), "utf-16le", text},
+		"utf-16be without bom": {encode(unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)), "utf-16be", text},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := bytes.NewReader(test.data)
+			// detection reads from the beginning and restores the offset
+			_, err := r.Seek(2, io.SeekStart)
+			require.NoError(t, err)
+
+			detected, enc, err := Detect(r)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, detected)
+
+			offset, err := r.Seek(0, io.SeekCurrent)
+			require.NoError(t, err)
+			assert.EqualValues(t, 2, offset)
+
+			decoded, err := enc.NewDecoder().Bytes(test.data)
+			require.NoError(t, err)
+			assert.Equal(t, test.decoded, string(decoded))
+
+			// line terminators are encoded without BOM
+			nl, err := enc.NewEncoder().Bytes([]byte("\n"))
+			require.NoError(t, err)
+			assert.Equal(t, strings.Count(test.decoded, "\n"), bytes.Count(test.data, nl))
+		})
+	}
+}
+
+func TestDetectEmpty(t *testing.T) {
+	_, _, err := Detect(bytes.NewReader(nil))
+	assert.ErrorIs(t, err, transform.ErrShortSrc)
+}
@@ -104,6 +104,9 @@ var encodings = map[string]EncodingFactory{
 	"utf-16-bom":   utf16BOMRequired,
 	"utf-16be-bom": utf16BOMBigEndian,
 	"utf-16le-bom": utf16BOMLittleEndian,
+
+	// detect utf-8 or utf16 from BOM or null bytes (seekable data source required)
+	"auto": autoDetect,
 }
 
 // Plain file encoding not transforming any read bytes.
@@ -0,0 +1,184 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package readfile
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/text/encoding"
+
+	"github.com/elastic/beats/v7/libbeat/reader"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// FrameReader reads records from the underlying reader using a
+// bufio.SplitFunc, for example one created by ScanLengthPrefixed or
+// ScanDelimited. The records are decoded with the configured codec once
+// split. The reader keeps track of bytes consumed from the raw input stream
+// for every record, including the length prefix or delimiter.
+// Incomplete records are kept buffered until more data is available.
+// Delimited records larger than the maximum size are skipped, their bytes are
+// added to the next record.
+type FrameReader struct {
+	reader   io.ReadCloser
+	split    bufio.SplitFunc
+	decoder  *encoding.Decoder
+	buf      []byte
+	start    int // start of the buffered data
+	end      int // end of the buffered data
+	consumed int // bytes consumed from the buffer since the last record
+	err      error
+	logger   *logp.Logger
+
+	skipping *delimitedFrameTooLargeError // record being skipped
+	skipped  int                          // bytes skipped of the record
+}
+
+// NewFrameReader creates a new reader splitting records with split. Only the
+// Codec and BufferSize settings of config are used.
+func NewFrameReader(input io.ReadCloser, split bufio.SplitFunc, config Config) (*FrameReader, error) {
+	if config.BufferSize <= 0 {
+		return nil, fmt.Errorf("invalid buffer size: %d", config.BufferSize)
+	}
+
+	return &FrameReader{
+		reader:  input,
+		split:   split,
+		decoder: config.Codec.NewDecoder(),
+		buf:     make([]byte, config.BufferSize),
+		logger:  logp.NewLogger("reader_frame"),
+	}, nil
+}
+
+// Next returns the next record.
+func (r *FrameReader) Next() (reader.Message, error) {
+	for {
+		if r.skipping != nil && !r.skip() {
+			if err := r.fill(); err != nil {
+				return reader.Message{}, err
+			}
+			continue
+		}
+
+		if r.start < r.end {
+			advance, token, err := r.split(r.buf[r.start:r.end], false)
+			var tooLarge *delimitedFrameTooLargeError
+			if errors.As(err, &tooLarge) {
+				r.skipping = tooLarge
+				continue
+			}
+			if err != nil {
+				return reader.Message{}, err
+			}
+			if advance < 0 || advance > r.end-r.start {
+				return reader.Message{}, errors.New("split function returned an invalid advance count")
+			}
+
+			r.start += advance
+			r.consumed += advance
+			if token != nil {
+				content, err := r.decoder.Bytes(token)
+				if err != nil {
+					return reader.Message{}, fmt.Errorf("failed to decode record: %w", err)
+				}
+
+				n := r.consumed
+				r.consumed = 0
+				return reader.Message{
+					Ts:      time.Now(),
+					Content: content,
+					Bytes:   n,
+					Fields:  mapstr.M{},
+				}, nil
+			}
+			if advance > 0 {
+				continue
+			}
+		}
+
+		if err := r.fill(); err != nil {
+			return reader.Message{}, err
+		}
+	}
+}
+
+// skip drops the buffered data up to and including the next delimiter. It
+// returns false if the delimiter is not buffered yet, keeping only the bytes
+// that can be the start of a delimiter.
+func (r *FrameReader) skip() bool {
+	delimiter := r.skipping.delimiter
+	data := r.buf[r.start:r.end]
+
+	n := len(data) - len(delimiter) + 1
+	found := false
+	if i := bytes.Index(data, delimiter); i >= 0 {
+		n = i + len(delimiter)
+		found = true
+	}
+	if n > 0 {
+		r.start += n
+		r.consumed += n
+		r.skipped += n
+	}
+	if !found {
+		return false
+	}
+
+	r.logger.Warnf("Exceeded %d max bytes in record limit, skipped %d bytes record", r.skipping.maxBytes, r.skipped)
+	r.skipping = nil
+	r.skipped = 0
+	return true
+}
+
+// fill reads more data into the buffer, growing it if it is full.
+func (r *FrameReader) fill() error {
+	if r.err != nil {
+		err := r.err
+		r.err = nil
+		return err
+	}
+
+	if r.start > 0 {
+		r.end = copy(r.buf, r.buf[r.start:r.end])
+		r.start = 0
+	}
+	if r.end == len(r.buf) {
+		buf := make([]byte, 2*len(r.buf))
+		copy(buf, r.buf[:r.end])
+		r.buf = buf
+	}
+
+	n, err := r.reader.Read(r.buf[r.end:])
+	r.end += n
+	if n > 0 {
+		// Split the data read before reporting the error.
+		r.err = err
+		return nil
+	}
+	return err
+}
+
+func (r *FrameReader) Close() error {
+	return r.reader.Close()
+}
@@ -0,0 +1,162 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !integration
+
+package readfile
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/reader/readfile/encoding"
+)
+
+// chunkReader returns the data written to it in chunks of at most size
+// bytes and io.EOF when no data is available.
+type chunkReader struct {
+	buf  bytes.Buffer
+	size int
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(p) > r.size {
+		p = p[:r.size]
+	}
+	return r.buf.Read(p)
+}
+
+func (r *chunkReader) Close() error { return nil }
+
+func TestFrameReader(t *testing.T) {
+	in := &chunkReader{size: 3}
+	in.buf.Write(lengthPrefixed(Uint32BigEndian, "first", "second record"))
+
+	codec, err := encoding.Plain(nil)
+	require.NoError(t, err)
+	r, err := NewFrameReader(in, ScanLengthPrefixed(Uint32BigEndian, 0), Config{
+		Codec:      codec,
+		BufferSize: 4,
+	})
+	require.NoError(t, err)
+
+	msg, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(msg.Content))
+	assert.Equal(t, 4+5, msg.Bytes)
+
+	msg, err = r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "second record", string(msg.Content))
+	assert.Equal(t, 4+13, msg.Bytes)
+
+	// An incomplete record is returned once the rest of it is written.
+	third := lengthPrefixed(Uint32BigEndian, "third")
+	in.buf.Write(third[:6])
+	_, err = r.Next()
+	require.ErrorIs(t, err, io.EOF)
+
+	in.buf.Write(third[6:])
+	msg, err = r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "third", string(msg.Content))
+	assert.Equal(t, len(third), msg.Bytes)
+
+	_, err = r.Next()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestFrameReaderDecodesRecords(t *testing.T) {
+	codec, ok := encoding.FindEncoding("utf-16le")
+	require.True(t, ok)
+	enc, err := codec(bytes.NewReader(nil))
+	require.NoError(t, err)
+
+	utf16, err := enc.NewEncoder().String("héllo")
+	require.NoError(t, err)
+
+	in := &chunkReader{size: 1024}
+	in.buf.WriteString(utf16 + "\x1e\x00")
+
+	r, err := NewFrameReader(in, ScanDelimited([]byte("\x1e\x00"), 0), Config{
+		Codec:      enc,
+		BufferSize: 16,
+	})
+	require.NoError(t, err)
+
+	msg, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "héllo", string(msg.Content))
+	assert.Equal(t, len(utf16)+2, msg.Bytes)
+}
+
+func TestFrameReaderSkipsLargeRecords(t *testing.T) {
+	in := &chunkReader{size: 3}
+	in.buf.WriteString("first||" + strings.Repeat("x", 20) + "|x||second||")
+
+	codec, err := encoding.Plain(nil)
+	require.NoError(t, err)
+	r, err := NewFrameReader(in, ScanDelimited([]byte("||"), 8), Config{
+		Codec:      codec,
+		BufferSize: 4,
+	})
+	require.NoError(t, err)
+
+	msg, err := r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(msg.Content))
+	assert.Equal(t, 7, msg.Bytes)
+
+	// The bytes of the skipped record are added to the next record.
+	msg, err = r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(msg.Content))
+	assert.Equal(t, 24+8, msg.Bytes)
+
+	// A large record is skipped until its delimiter is written.
+	in.buf.WriteString(strings.Repeat("y", 10) + "|")
+	_, err = r.Next()
+	require.ErrorIs(t, err, io.EOF)
+
+	in.buf.WriteString("|third||")
+	msg, err = r.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "third", string(msg.Content))
+	assert.Equal(t, 12+7, msg.Bytes)
+}
+
+func TestFrameReaderLengthPrefixTooLarge(t *testing.T) {
+	in := &chunkReader{size: 1024}
+	in.buf.Write(lengthPrefixed(Uint32BigEndian, "too large", "second"))
+
+	codec, err := encoding.Plain(nil)
+	require.NoError(t, err)
+	r, err := NewFrameReader(in, ScanLengthPrefixed(Uint32BigEndian, 8), Config{
+		Codec:      codec,
+		BufferSize: 16,
+	})
+	require.NoError(t, err)
+
+	// The end of a record can not be found without its length prefix.
+	_, err = r.Next()
+	require.ErrorIs(t, err, ErrFrameTooLarge)
+}
@@ -0,0 +1,254 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package readfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ErrFrameTooLarge is returned by the split functions when a record is
+// larger than the maximum configured size.
+var ErrFrameTooLarge = errors.New("record exceeds the maximum size")
+
+// delimitedFrameTooLargeError is returned by ScanDelimited for records larger
+// than the maximum size. Unlike for length-prefixed records, the reader can
+// recover by skipping the record up to the next delimiter.
+type delimitedFrameTooLargeError struct {
+	msg       string
+	maxBytes  int
+	delimiter []byte
+}
+
+func (e *delimitedFrameTooLargeError) Error() string {
+	return ErrFrameTooLarge.Error() + ": " + e.msg
+}
+
+func (e *delimitedFrameTooLargeError) Unwrap() error {
+	return ErrFrameTooLarge
+}
+
+// Framing is the option selecting how records are split.
+type Framing uint8
+
+const (
+	// LineFraming splits records on the configured line terminator.
+	LineFraming Framing = iota
+	// LengthPrefixedFraming reads records preceded by their length.
+	LengthPrefixedFraming
+	// DelimiterFraming splits records on an arbitrary sequence of bytes.
+	DelimiterFraming
+)
+
+var framings = map[string]Framing{
+	"line":            LineFraming,
+	"length_prefixed": LengthPrefixedFraming,
+	"delimiter":       DelimiterFraming,
+}
+
+// Unpack unpacks the configuration from the config file
+func (f *Framing) Unpack(option string) error {
+	framing, ok := framings[option]
+	if !ok {
+		return fmt.Errorf("invalid framing: %s", option)
+	}
+
+	*f = framing
+
+	return nil
+}
+
+// LengthPrefix is the option storing the encoding of the length preceding
+// length-prefixed records.
+type LengthPrefix uint8
+
+const (
+	// Uint32BigEndian is a 4 bytes big endian unsigned integer
+	Uint32BigEndian LengthPrefix = iota
+	// Uint32LittleEndian is a 4 bytes little endian unsigned integer
+	Uint32LittleEndian
+	// Uint16BigEndian is a 2 bytes big endian unsigned integer
+	Uint16BigEndian
+	// Uint16LittleEndian is a 2 bytes little endian unsigned integer
+	Uint16LittleEndian
+	// Varint is an unsigned varint as encoded by protobuf
+	Varint
+)
+
+var lengthPrefixes = map[string]LengthPrefix{
+	"uint32be": Uint32BigEndian,
+	"uint32le": Uint32LittleEndian,
+	"uint16be": Uint16BigEndian,
+	"uint16le": Uint16LittleEndian,
+	"varint":   Varint,
+}
+
+// Unpack unpacks the configuration from the config file
+func (p *LengthPrefix) Unpack(option string) error {
+	prefix, ok := lengthPrefixes[option]
+	if !ok {
+		return fmt.Errorf("invalid length prefix: %s", option)
+	}
+
+	*p = prefix
+
+	return nil
+}
+
+// decode returns the length stored at the beginning of data and the size of
+// the prefix. The size is 0 if data does not hold a complete prefix yet.
+func (p LengthPrefix) decode(data []byte) (uint64, int, error) {
+	switch p {
+	case Uint32BigEndian, Uint32LittleEndian:
+		if len(data) < 4 {
+			return 0, 0, nil
+		}
+		if p == Uint32BigEndian {
+			return uint64(binary.BigEndian.Uint32(data)), 4, nil
+		}
+		return uint64(binary.LittleEndian.Uint32(data)), 4, nil
+	case Uint16BigEndian, Uint16LittleEndian:
+		if len(data) < 2 {
+			return 0, 0, nil
+		}
+		if p == Uint16BigEndian {
+			return uint64(binary.BigEndian.Uint16(data)), 2, nil
+		}
+		return uint64(binary.LittleEndian.Uint16(data)), 2, nil
+	case Varint:
+		length, n := binary.Uvarint(data)
+		if n < 0 {
+			return 0, 0, errors.New("invalid varint length prefix")
+		}
+		return length, n, nil
+	default:
+		return 0, 0, fmt.Errorf("unknown length prefix: %d", p)
+	}
+}
+
+// FramingConfig stores the configuration of the record framing.
+type FramingConfig struct {
+	Type         Framing      `config:"type"`
+	LengthPrefix LengthPrefix `config:"length_prefix"`
+	Delimiter    string       `config:"delimiter"`
+}
+
+// Validate validates the framing configuration.
+func (c *FramingConfig) Validate() error {
+	if c.Type == DelimiterFraming && c.Delimiter == "" {
+		return errors.New("delimiter framing requires a non empty delimiter")
+	}
+	return nil
+}
+
+// SplitFunc returns the function splitting records for the length-prefixed
+// and delimiter framings. Records larger than maxBytes are reported as
+// ErrFrameTooLarge, a maxBytes of 0 means no limit. The FrameReader skips
+// delimited records that are too large.
+func (c *FramingConfig) SplitFunc(maxBytes int) (bufio.SplitFunc, error) {
+	switch c.Type {
+	case LengthPrefixedFraming:
+		return ScanLengthPrefixed(c.LengthPrefix, maxBytes), nil
+	case DelimiterFraming:
+		return ScanDelimited([]byte(c.Delimiter), maxBytes), nil
+	default:
+		return nil, fmt.Errorf("no split function for framing %d", c.Type)
+	}
+}
+
+// ScanLengthPrefixed returns a bufio.SplitFunc reading records preceded by
+// their length. The prefix is not part of the returned records.
+func ScanLengthPrefixed(prefix LengthPrefix, maxBytes int) bufio.SplitFunc {
+	return func(data []byte, eof bool) (int, []byte, error) {
+		if eof && len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		length, n, err := prefix.decode(data)
+		if err != nil {
+			return 0, nil, err
+		}
+		if n == 0 {
+			if eof {
+				return 0, nil, io.ErrUnexpectedEOF
+			}
+			return 0, nil, nil
+		}
+
+		if length > math.MaxInt32 || (maxBytes > unlimited && length > uint64(maxBytes)) {
+			return 0, nil, fmt.Errorf("%w: record of %d bytes", ErrFrameTooLarge, length)
+		}
+
+		end := n + int(length)
+		if len(data) < end {
+			if eof {
+				return 0, nil, io.ErrUnexpectedEOF
+			}
+			return 0, nil, nil
+		}
+		return end, data[n:end], nil
+	}
+}
+
+// ScanDelimited returns a bufio.SplitFunc splitting records on a delimiter of
+// one or more bytes. The delimiter is not part of the returned records.
+func ScanDelimited(delimiter []byte, maxBytes int) bufio.SplitFunc {
+	return func(data []byte, eof bool) (int, []byte, error) {
+		if eof && len(data) == 0 {
+			return 0, nil, nil
+		}
+		if i := bytes.Index(data, delimiter); i >= 0 {
+			if maxBytes > unlimited && i > maxBytes {
+				return 0, nil, &delimitedFrameTooLargeError{
+					msg:       fmt.Sprintf("record of %d bytes", i),
+					maxBytes:  maxBytes,
+					delimiter: delimiter,
+				}
+			}
+			return i + len(delimiter), DropDelimiter(data[0:i], delimiter), nil
+		}
+		if eof {
+			return len(data), DropDelimiter(data, delimiter), nil
+		}
+		// The record is at least as long as the buffered data minus
+		// a partially buffered delimiter.
+		if maxBytes > unlimited && len(data)-len(delimiter)+1 > maxBytes {
+			return 0, nil, &delimitedFrameTooLargeError{
+				msg:       fmt.Sprintf("record of more than %d bytes", maxBytes),
+				maxBytes:  maxBytes,
+				delimiter: delimiter,
+			}
+		}
+		return 0, nil, nil
+	}
+}
+
+// DropDelimiter strips a trailing delimiter from data, unless data only
+// consists of the delimiter.
+func DropDelimiter(data []byte, delimiter []byte) []byte {
+	if len(data) > len(delimiter) &&
+		bytes.Equal(data[len(data)-len(delimiter):], delimiter) {
+		return data[0 : len(data)-len(delimiter)]
+	}
+	return data
+}
@@ -0,0 +1,144 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !integration
+
+package readfile
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lengthPrefixed(prefix LengthPrefix, records ...string) []byte {
+	var buf bytes.Buffer
+	for _, r := range records {
+		switch prefix {
+		case Uint32BigEndian:
+			_ = binary.Write(&buf, binary.BigEndian, uint32(len(r)))
+		case Uint32LittleEndian:
+			_ = binary.Write(&buf, binary.LittleEndian, uint32(len(r)))
+		case Uint16BigEndian:
+			_ = binary.Write(&buf, binary.BigEndian, uint16(len(r)))
+		case Uint16LittleEndian:
+			_ = binary.Write(&buf, binary.LittleEndian, uint16(len(r)))
+		case Varint:
+			buf.Write(binary.AppendUvarint(nil, uint64(len(r))))
+		}
+		buf.WriteString(r)
+	}
+	return buf.Bytes()
+}
+
+func scanAll(t *testing.T, data []byte, split bufio.SplitFunc) ([]string, error) {
+	t.Helper()
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 1), 1024)
+	scanner.Split(split)
+
+	var records []string
+	for scanner.Scan() {
+		records = append(records, scanner.Text())
+	}
+	return records, scanner.Err()
+}
+
+func TestScanLengthPrefixed(t *testing.T) {
+	records := []string{"first", "", "second record", string(bytes.Repeat([]byte{0xff}, 300))}
+
+	for name, prefix := range lengthPrefixes {
+		t.Run(name, func(t *testing.T) {
+			got, err := scanAll(t, lengthPrefixed(prefix, records...), ScanLengthPrefixed(prefix, 0))
+			require.NoError(t, err)
+			assert.Equal(t, records, got)
+		})
+	}
+
+	t.Run("incomplete record", func(t *testing.T) {
+		data := lengthPrefixed(Uint16BigEndian, "first", "second")
+		_, err := scanAll(t, data[:len(data)-1], ScanLengthPrefixed(Uint16BigEndian, 0))
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("record too large", func(t *testing.T) {
+		data := lengthPrefixed(Varint, "small", "too large")
+		got, err := scanAll(t, data, ScanLengthPrefixed(Varint, 5))
+		assert.ErrorIs(t, err, ErrFrameTooLarge)
+		assert.Equal(t, []string{"small"}, got)
+	})
+}
+
+func TestScanDelimited(t *testing.T) {
+	tests := map[string]struct {
+		input     string
+		delimiter string
+		maxBytes  int
+		records   []string
+		err       error
+	}{
+		"multi bytes delimiter": {
+			input:     "first<EOR>second<EOR>third",
+			delimiter: "<EOR>",
+			records:   []string{"first", "second", "third"},
+		},
+		"binary delimiter": {
+			input:     "first\x00\x1esecond\x00\x1e",
+			delimiter: "\x00\x1e",
+			records:   []string{"first", "second"},
+		},
+		"record too large": {
+			input:     "first||second-is-too-large||",
+			delimiter: "||",
+			maxBytes:  8,
+			records:   []string{"first"},
+			err:       ErrFrameTooLarge,
+		},
+		"record too large without delimiter": {
+			input:     "a record without delimiter",
+			delimiter: "||",
+			maxBytes:  8,
+			err:       ErrFrameTooLarge,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := scanAll(t, []byte(test.input), ScanDelimited([]byte(test.delimiter), test.maxBytes))
+			assert.ErrorIs(t, err, test.err)
+			assert.Equal(t, test.records, got)
+		})
+	}
+}
+
+func TestFramingConfigValidate(t *testing.T) {
+	c := FramingConfig{Type: DelimiterFraming}
+	assert.Error(t, c.Validate())
+
+	c.Delimiter = "\x1e"
+	assert.NoError(t, c.Validate())
+
+	var f Framing
+	assert.Error(t, f.Unpack("unknown"))
+	var p LengthPrefix
+	assert.Error(t, p.Unpack("uint64be"))
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/elastic/beats/v7/libbeat/beat"
 	"github.com/elastic/beats/v7/libbeat/common"
 	"github.com/elastic/beats/v7/libbeat/common/jsontransform"
+	"github.com/elastic/beats/v7/libbeat/conditions"
 	"github.com/elastic/beats/v7/libbeat/reader"
 	"github.com/elastic/elastic-agent-libs/logp"
 	"github.com/elastic/elastic-agent-libs/mapstr"
@@ -40,7 +41,8 @@ type JSONReader struct {
 
 type JSONParser struct {
 	JSONReader
-	field, target string
+	field, target    string
+	include, exclude conditions.Condition
 }
 
 // NewJSONReader creates a new reader that can decode JSON.
@@ -53,20 +55,42 @@ func NewJSONReader(r reader.Reader, cfg *Config) *JSONReader {
 }
 
 func NewJSONParser(r reader.Reader, cfg *ParserConfig) *JSONParser {
-	return &JSONParser{
-		JSONReader{
+	p := &JSONParser{
+		JSONReader: JSONReader{
 			reader: r,
 			cfg:    &cfg.Config,
 			logger: logp.NewLogger("parser_json"),
 		},
-		cfg.Field,
-		cfg.Target,
+		field:  cfg.Field,
+		target: cfg.Target,
 	}
+
+	// The conditions are validated when the configuration is unpacked.
+	var err error
+	if cfg.Include != nil {
+		if p.include, err = conditions.NewCondition(cfg.Include); err != nil {
+			p.logger.Errorf("Invalid include condition, all messages are kept: %v", err)
+		}
+	}
+	if cfg.Exclude != nil {
+		if p.exclude, err = conditions.NewCondition(cfg.Exclude); err != nil {
+			p.logger.Errorf("Invalid exclude condition, no message is dropped: %v", err)
+		}
+	}
+
+	return p
 }
 
 // decodeJSON unmarshals the text parameter into a MapStr and
 // returns the new text column if one was requested.
 func (r *JSONReader) decode(text []byte) ([]byte, mapstr.M) {
+	text, jsonFields, _ := r.decodeDocument(text)
+	return text, jsonFields
+}
+
+// decodeDocument is like decode, but also reports if the text could be
+// decoded into a JSON object.
+func (r *JSONReader) decodeDocument(text []byte) ([]byte, mapstr.M, bool) {
 	var jsonFields map[string]interface{}
 
 	err := unmarshal(text, &jsonFields)
@@ -77,11 +101,11 @@ func (r *JSONReader) decode(text []byte) ([]byte, mapstr.M) {
 		if r.cfg.AddErrorKey {
 			jsonFields = mapstr.M{"error": createJSONError(fmt.Sprintf("Error decoding JSON: %v", err))}
 		}
-		return text, jsonFields
+		return text, jsonFields, false
 	}
 
 	if len(r.cfg.MessageKey) == 0 {
-		return []byte(""), jsonFields
+		return []byte(""), jsonFields, true
 	}
 
 	textValue, ok := jsonFields[r.cfg.MessageKey]
@@ -89,7 +113,7 @@ func (r *JSONReader) decode(text []byte) ([]byte, mapstr.M) {
 		if r.cfg.AddErrorKey {
 			jsonFields["error"] = createJSONError(fmt.Sprintf("Key '%s' not found", r.cfg.MessageKey))
 		}
-		return []byte(""), jsonFields
+		return []byte(""), jsonFields, true
 	}
 
 	textString, ok := textValue.(string)
@@ -97,10 +121,10 @@ func (r *JSONReader) decode(text []byte) ([]byte, mapstr.M) {
 		if r.cfg.AddErrorKey {
 			jsonFields["error"] = createJSONError(fmt.Sprintf("Value of key '%s' is not a string", r.cfg.MessageKey))
 		}
-		return []byte(""), jsonFields
+		return []byte(""), jsonFields, true
 	}
 
-	return []byte(textString), jsonFields
+	return []byte(textString), jsonFields, true
 }
 
 // unmarshal is equivalent with json.Unmarshal but it converts numbers
@@ -139,24 +163,42 @@ func createJSONError(message string) mapstr.M {
 
 // Next decodes JSON and returns the filled Line object.
 func (p *JSONParser) Next() (reader.Message, error) {
-	message, err := p.JSONReader.reader.Next()
-	if err != nil {
-		return message, err
-	}
+	// discarded accounts for the bytes of the messages dropped by the
+	// include and exclude conditions, so the inputs can correctly track
+	// the file offset.
+	var discarded int
+	var message reader.Message
+	var jsonFields mapstr.M
+	for {
+		var err error
+		message, err = p.JSONReader.reader.Next()
+		if err != nil {
+			return message, err
+		}
+
+		var ok bool
+		from := message.Content
+		if p.field != "" {
+			from, ok = message.Fields[p.field].([]byte)
+			if !ok {
+				return message, fmt.Errorf("cannot decode JSON message, missing key: %s", p.field)
+			}
+		}
+		var decoded bool
+		message.Content, jsonFields, decoded = p.JSONReader.decodeDocument(from)
 
-	var ok bool
-	from := message.Content
-	if p.field != "" {
-		from, ok = message.Fields[p.field].([]byte)
-		if !ok {
-			return message, fmt.Errorf("cannot decode JSON message, missing key: %s", p.field)
+		// Messages that are not valid JSON are always kept, so decoding
+		// errors are not hidden by the conditions.
+		if !decoded || p.keep(jsonFields) {
+			break
 		}
+		discarded += message.Bytes + message.Offset
+		p.logger.Debugf("Dropping message because of the include/exclude conditions: %s", from)
 	}
-	var jsonFields mapstr.M
-	message.Content, jsonFields = p.JSONReader.decode(from)
+	message.Offset += discarded
 
 	if len(jsonFields) == 0 {
-		return message, err
+		return message, nil
 	}
 
 	// The message key might have been modified by multiline
@@ -200,7 +242,19 @@ func (p *JSONParser) Next() (reader.Message, error) {
 		message.AddFields(fields)
 	}
 
-	return message, err
+	return message, nil
+}
+
+// keep reports if the decoded JSON document matches the include condition
+// and does not match the exclude condition.
+func (p *JSONParser) keep(jsonFields mapstr.M) bool {
+	if p.include != nil && !p.include.Check(jsonFields) {
+		return false
+	}
+	if p.exclude != nil && p.exclude.Check(jsonFields) {
+		return false
+	}
+	return true
 }
 
 // MergeJSONFields writes the JSON fields in the event map,
@@ -17,6 +17,12 @@
 
 package readjson
 
+import (
+	"fmt"
+
+	"github.com/elastic/beats/v7/libbeat/conditions"
+)
+
 // Config holds the options a JSON reader.
 type Config struct {
 	MessageKey          string `config:"message_key"`
@@ -32,9 +38,29 @@ type ParserConfig struct {
 	Config `config:",inline"`
 	Field  string `config:"field"`
 	Target string `config:"target"`
+
+	// Include and Exclude are checked against the decoded JSON document,
+	// before it is written to the event.
+	Include *conditions.Config `config:"include"`
+	Exclude *conditions.Config `config:"exclude"`
 }
 
 // Validate validates the Config option for JSON reader.
 func (c *Config) Validate() error {
 	return nil
 }
+
+// Validate validates the Config option for JSON parser.
+func (c *ParserConfig) Validate() error {
+	if c.Include != nil {
+		if _, err := conditions.NewCondition(c.Include); err != nil {
+			return fmt.Errorf("invalid include condition: %w", err)
+		}
+	}
+	if c.Exclude != nil {
+		if _, err := conditions.NewCondition(c.Exclude); err != nil {
+			return fmt.Errorf("invalid exclude condition: %w", err)
+		}
+	}
+	return c.Config.Validate()
+}
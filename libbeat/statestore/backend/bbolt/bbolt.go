@@ -0,0 +1,156 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bbolt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/elastic/beats/v7/libbeat/statestore/backend"
+	"github.com/elastic/elastic-agent-libs/logp"
+)
+
+// Registry configures access to bbolt based stores.
+type Registry struct {
+	log *logp.Logger
+
+	mu     sync.Mutex
+	active bool
+
+	settings Settings
+}
+
+// Settings configures a new Registry.
+type Settings struct {
+	// Registry root directory. Stores will be single database files.
+	Root string
+
+	// FileMode is used to configure the file mode for new files generated by the
+	// registry. File mode 0600 will be used if this field is not set.
+	FileMode os.FileMode
+
+	// Timeout configures how long to wait for the file lock of a database
+	// file held by another process. Defaults to 1s if not set.
+	Timeout time.Duration
+
+	// SyncInterval configures how often updates are synced to disk. Updates
+	// are written to the database file on every operation, but only synced
+	// periodically and on close. Defaults to 1s if not set.
+	SyncInterval time.Duration
+}
+
+const defaultFileMode os.FileMode = 0600
+
+const defaultTimeout = time.Second
+
+const defaultSyncInterval = time.Second
+
+// New configures a bbolt Registry that can be used to open stores.
+func New(log *logp.Logger, settings Settings) (*Registry, error) {
+	if settings.FileMode == 0 {
+		settings.FileMode = defaultFileMode
+	}
+	if settings.Timeout == 0 {
+		settings.Timeout = defaultTimeout
+	}
+	if settings.SyncInterval == 0 {
+		settings.SyncInterval = defaultSyncInterval
+	}
+
+	root, err := filepath.Abs(settings.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	settings.Root = root
+	return &Registry{
+		log:      log,
+		active:   true,
+		settings: settings,
+	}, nil
+}
+
+// Access creates or opens a store. The database file is created, if the store
+// does not exist. Existing memlog data with the same store name is migrated
+// into the database file, unless it was migrated already.
+// Returns an error is any file access fails.
+func (r *Registry) Access(name string) (backend.Store, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.active {
+		return nil, errRegClosed
+	}
+
+	logger := r.log.With("store", name)
+
+	if err := os.MkdirAll(r.settings.Root, r.settings.FileMode|0100); err != nil {
+		return nil, fmt.Errorf("failed to create registry directory '%s': %w", r.settings.Root, err)
+	}
+
+	path := filepath.Join(r.settings.Root, name+".db")
+	store, err := openStore(logger, path, r.settings)
+	if err != nil {
+		return nil, err
+	}
+
+	memlogHome := filepath.Join(r.settings.Root, name)
+	if err := migrateMemlog(logger, memlogHome, store); err != nil {
+		store.Close()
+		return nil, fmt.Errorf("failed to migrate memlog store '%s': %w", memlogHome, err)
+	}
+
+	return store, nil
+}
+
+// Close closes the registry. No new store can be accessed after close.
+func (r *Registry) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.active = false
+	return nil
+}
+
+func openStore(log *logp.Logger, path string, settings Settings) (*store, error) {
+	db, err := bolt.Open(path, settings.FileMode, &bolt.Options{
+		Timeout: settings.Timeout,
+		// Commits are synced by the store periodically, see syncLoop.
+		NoSync: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store '%s': %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketName)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize store '%s': %w", path, err)
+	}
+
+	log.Debugf("Opened store '%s'", path)
+	return newStore(log, db, settings.SyncInterval), nil
+}
@@ -0,0 +1,44 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package bbolt implements a statestore backend based on bbolt, an embedded
+// key-value store persisting its data in a single B+tree file.
+//
+// Each store is kept in its own database file named `<name>.db` in the
+// registry root directory. All key-value pairs are stored in one bucket. The
+// values are serialized to JSON, such that only primitive types like intX,
+// uintX, float, bool, string, slices, or map[string]interface{} are returned
+// when decoding a value.
+//
+// Unlike memlog the store does not hold the key-value pairs in memory and
+// does not need to write checkpoints. Each Set and Remove operation is
+// executed in its own write transaction, updating only the pages touched by
+// the operation. Transactions are not synced to disk on commit, but every
+// second and when the store is closed, so that frequent updates do not wait
+// for an fsync each. Like with memlog, the updates since the last sync can
+// be lost if the system crashes.
+//
+// When a store is accessed, and a memlog store with the same name exists in
+// the registry root directory, all key-value pairs are copied from the memlog
+// store into the database file. The migration is marked as complete in the
+// same transaction, so a migration interrupted by a crash is executed again on
+// the next access. The memlog directory is renamed to `<name>.migrated`
+// afterwards.
+//
+// The store is threadsafe. bbolt allows only one active writer, but multiple
+// concurrent readers.
+package bbolt
@@ -0,0 +1,136 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bbolt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/elastic/beats/v7/libbeat/statestore/backend"
+	"github.com/elastic/beats/v7/libbeat/statestore/backend/memlog"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// migratedSuffix is appended to the directory of a memlog store once its
+// contents have been copied into a bbolt store.
+const migratedSuffix = ".migrated"
+
+var (
+	// metaBucketName is the bucket holding the metadata of a store.
+	metaBucketName = []byte("meta")
+
+	// memlogMigratedKey is set in the meta bucket in the same transaction
+	// as the migrated key-value pairs, marking the migration as complete.
+	memlogMigratedKey = []byte("memlog_migrated")
+)
+
+// migrateMemlog copies all key-value pairs of the memlog store in home into
+// the bbolt store in a single transaction. The memlog directory is renamed
+// on success. Nothing is migrated if home does not contain a memlog store,
+// or if the bbolt store is marked as migrated already. An interrupted
+// migration is run again on the next access, overwriting the keys copied
+// before.
+func migrateMemlog(log *logp.Logger, home string, to *store) error {
+	if _, err := os.Stat(filepath.Join(home, "meta.json")); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var migrated bool
+	err := to.db.View(func(tx *bolt.Tx) error {
+		if meta := tx.Bucket(metaBucketName); meta != nil {
+			migrated = meta.Get(memlogMigratedKey) != nil
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if migrated {
+		log.Debugf("Memlog store '%s' was migrated already", home)
+		return nil
+	}
+
+	log.Infof("Migrating memlog store '%s'", home)
+
+	reg, err := memlog.New(log, memlog.Settings{Root: filepath.Dir(home)})
+	if err != nil {
+		return err
+	}
+	defer reg.Close()
+
+	from, err := reg.Access(filepath.Base(home))
+	if err != nil {
+		return err
+	}
+
+	var count int
+	err = to.update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketName)
+		err := from.Each(func(key string, dec backend.ValueDecoder) (bool, error) {
+			var value mapstr.M
+			if err := dec.Decode(&value); err != nil {
+				return false, fmt.Errorf("failed to read key '%s': %w", key, err)
+			}
+			raw, err := encodeValue(value)
+			if err != nil {
+				return false, err
+			}
+			count++
+			return true, bucket.Put([]byte(key), raw)
+		})
+		if err != nil {
+			return err
+		}
+
+		meta, err := tx.CreateBucketIfNotExists(metaBucketName)
+		if err != nil {
+			return err
+		}
+		return meta.Put(memlogMigratedKey, []byte(time.Now().UTC().Format(time.RFC3339)))
+	})
+	if closeErr := from.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	// the migration must be on disk before the memlog store is moved
+	if err := to.sync(); err != nil {
+		return err
+	}
+
+	backup := home + migratedSuffix
+	if err := os.RemoveAll(backup); err != nil {
+		return err
+	}
+	if err := os.Rename(home, backup); err != nil {
+		return err
+	}
+
+	log.Infof("Migrated %d entries from memlog store, old store moved to '%s'", count, backup)
+	return nil
+}
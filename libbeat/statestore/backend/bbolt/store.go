@@ -0,0 +1,205 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bbolt
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/elastic/beats/v7/libbeat/common/transform/typeconv"
+	"github.com/elastic/beats/v7/libbeat/statestore/backend"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+	"github.com/elastic/go-structform/gotype"
+	"github.com/elastic/go-structform/json"
+)
+
+// bucketName is the bucket holding all key-value pairs of a store.
+var bucketName = []byte("states")
+
+type store struct {
+	db  *bolt.DB
+	log *logp.Logger
+
+	// dirty is set when a transaction was committed since the last sync.
+	dirty atomic.Bool
+	done  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// valueDecoder decodes a JSON encoded value. The raw bytes are owned by
+// bbolt and are only valid within the transaction the value was read in.
+type valueDecoder []byte
+
+func newStore(log *logp.Logger, db *bolt.DB, syncInterval time.Duration) *store {
+	s := &store{db: db, log: log, done: make(chan struct{})}
+	s.wg.Add(1)
+	go s.syncLoop(syncInterval)
+	return s
+}
+
+// Close syncs pending updates and closes the database file. Access to the
+// store after close returns an error.
+func (s *store) Close() error {
+	close(s.done)
+	s.wg.Wait()
+
+	err := s.sync()
+	if closeErr := s.db.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// syncLoop syncs committed transactions to disk every interval, such that
+// updates do not wait for an fsync each. Like with memlog, updates of the
+// last interval can be lost if the system crashes.
+func (s *store) syncLoop(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			if err := s.sync(); err != nil {
+				s.log.Errorf("Failed to sync store: %v", err)
+			}
+		}
+	}
+}
+
+func (s *store) sync() error {
+	if !s.dirty.Swap(false) {
+		return nil
+	}
+	if err := s.db.Sync(); err != nil {
+		s.dirty.Store(true)
+		return err
+	}
+	return nil
+}
+
+// update executes fn in a write transaction, marking the store as dirty.
+func (s *store) update(fn func(*bolt.Tx) error) error {
+	err := s.db.Update(fn)
+	s.dirty.Store(true)
+	return err
+}
+
+// Has checks if the key is known.
+func (s *store) Has(key string) (bool, error) {
+	var exists bool
+	err := s.db.View(func(tx *bolt.Tx) error {
+		exists = tx.Bucket(bucketName).Get([]byte(key)) != nil
+		return nil
+	})
+	return exists, err
+}
+
+// Get retrieves and decodes the key-value pair into to.
+func (s *store) Get(key string, to interface{}) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(bucketName).Get([]byte(key))
+		if raw == nil {
+			return errKeyUnknown
+		}
+		return valueDecoder(raw).Decode(to)
+	})
+}
+
+// Set inserts or overwrites a key-value pair.
+// The value is converted into a mapstr.M first, so that no references into
+// the data structures passed via Set are held.
+func (s *store) Set(key string, value interface{}) error {
+	var tmp mapstr.M
+	if err := typeconv.Convert(&tmp, value); err != nil {
+		return err
+	}
+
+	raw, err := encodeValue(tmp)
+	if err != nil {
+		return err
+	}
+
+	return s.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Put([]byte(key), raw)
+	})
+}
+
+// Remove removes a key from the store. The operation does not check if the
+// key exists.
+func (s *store) Remove(key string) error {
+	return s.update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Delete([]byte(key))
+	})
+}
+
+// Each iterates over all key-value pairs in the store in key order. The
+// decoder passed to fn must not be used after fn returns.
+func (s *store) Each(fn func(string, backend.ValueDecoder) (bool, error)) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucketName).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			cont, err := fn(string(k), valueDecoder(v))
+			if !cont || err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *store) SetID(_ string) {
+	// NOOP
+}
+
+// Decode parses the JSON document into a map first, such that integers are
+// not converted to floats, before converting the map into to.
+func (d valueDecoder) Decode(to interface{}) error {
+	var tmp map[string]interface{}
+	unfolder, err := gotype.NewUnfolder(&tmp)
+	if err != nil {
+		return err
+	}
+	if err := json.Parse(d, unfolder); err != nil {
+		return err
+	}
+	return typeconv.Convert(to, tmp)
+}
+
+func encodeValue(v mapstr.M) ([]byte, error) {
+	var buf bytes.Buffer
+	visitor := json.NewVisitor(&buf)
+	visitor.SetEscapeHTML(false)
+
+	folder, err := gotype.NewIterator(visitor)
+	if err != nil {
+		return nil, err
+	}
+	if err := folder.Fold(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,245 @@
+// Licensed to Elasticsearch B.V. under one or more contributor
+// license agreements. See the NOTICE file distributed with
+// this work for additional information regarding copyright
+// ownership. Elasticsearch B.V. licenses this file to you under
+// the Apache License, Version 2.0 (the "License"); you may
+// not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package bbolt
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/beats/v7/libbeat/statestore/backend"
+	"github.com/elastic/beats/v7/libbeat/statestore/backend/memlog"
+	"github.com/elastic/beats/v7/libbeat/statestore/internal/storecompliance"
+	"github.com/elastic/elastic-agent-libs/logp/logptest"
+)
+
+func TestCompliance(t *testing.T) {
+	storecompliance.TestBackendCompliance(t, func(testPath string) (backend.Registry, error) {
+		logger := logptest.NewTestingLogger(t, "")
+		return New(logger.Named("test"), Settings{Root: testPath})
+	})
+}
+
+func TestIntegerPrecision(t *testing.T) {
+	logger := logptest.NewTestingLogger(t, "")
+	reg, err := New(logger, Settings{Root: t.TempDir()})
+	require.NoError(t, err)
+	defer reg.Close()
+
+	store, err := reg.Access("test")
+	require.NoError(t, err)
+	defer store.Close()
+
+	type state struct {
+		Offset int64
+		Inode  uint64
+	}
+	want := state{Offset: math.MaxInt64, Inode: math.MaxUint64}
+	require.NoError(t, store.Set("key", want))
+
+	var got state
+	require.NoError(t, store.Get("key", &got))
+	assert.Equal(t, want, got)
+}
+
+func TestSyncInterval(t *testing.T) {
+	logger := logptest.NewTestingLogger(t, "")
+	reg, err := New(logger, Settings{Root: t.TempDir(), SyncInterval: 10 * time.Millisecond})
+	require.NoError(t, err)
+	defer reg.Close()
+
+	s, err := reg.Access("test")
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.NoError(t, s.Set("key", map[string]interface{}{"offset": 1}))
+	require.True(t, s.(*store).dirty.Load())
+	require.Eventually(t, func() bool {
+		return !s.(*store).dirty.Load()
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestMigrateMemlog(t *testing.T) {
+	logger := logptest.NewTestingLogger(t, "")
+	root := t.TempDir()
+
+	type cursor struct{ Offset int64 }
+	type state struct {
+		Cursor cursor
+		TTL    int64
+	}
+	states := map[string]state{
+		"filestream::a": {Cursor: cursor{Offset: 42}, TTL: -1},
+		"filestream::b": {Cursor: cursor{Offset: 7}, TTL: 1800000000000},
+	}
+
+	writeMemlogStore(t, root, "filebeat", states)
+
+	reg, err := New(logger, Settings{Root: root})
+	require.NoError(t, err)
+	defer reg.Close()
+
+	store, err := reg.Access("filebeat")
+	require.NoError(t, err)
+
+	assert.Equal(t, states, readStates[state](t, store))
+	require.NoError(t, store.Close())
+
+	assert.NoDirExists(t, filepath.Join(root, "filebeat"))
+	assert.DirExists(t, filepath.Join(root, "filebeat"+migratedSuffix))
+
+	// Reopening must not migrate again.
+	require.NoError(t, os.Rename(filepath.Join(root, "filebeat"+migratedSuffix), filepath.Join(root, "filebeat")))
+	store, err = reg.Access("filebeat")
+	require.NoError(t, err)
+	require.NoError(t, store.Remove("filestream::a"))
+	require.NoError(t, store.Close())
+
+	store, err = reg.Access("filebeat")
+	require.NoError(t, err)
+	defer store.Close()
+	has, err := store.Has("filestream::a")
+	require.NoError(t, err)
+	assert.False(t, has)
+	assert.DirExists(t, filepath.Join(root, "filebeat"))
+}
+
+func TestMigrateMemlogInterrupted(t *testing.T) {
+	logger := logptest.NewTestingLogger(t, "")
+	root := t.TempDir()
+
+	type state struct{ Offset int64 }
+	states := map[string]state{
+		"filestream::a": {Offset: 42},
+		"filestream::b": {Offset: 7},
+	}
+	writeMemlogStore(t, root, "filebeat", states)
+
+	// A process killed while migrating leaves a database file behind,
+	// without the migration being marked as complete.
+	partial, err := openStore(logger, filepath.Join(root, "filebeat.db"), Settings{
+		FileMode:     defaultFileMode,
+		Timeout:      defaultTimeout,
+		SyncInterval: defaultSyncInterval,
+	})
+	require.NoError(t, err)
+	require.NoError(t, partial.Set("filestream::a", state{Offset: 1}))
+	require.NoError(t, partial.Close())
+
+	reg, err := New(logger, Settings{Root: root})
+	require.NoError(t, err)
+	defer reg.Close()
+
+	store, err := reg.Access("filebeat")
+	require.NoError(t, err)
+	defer store.Close()
+
+	assert.Equal(t, states, readStates[state](t, store))
+	assert.NoDirExists(t, filepath.Join(root, "filebeat"))
+	assert.DirExists(t, filepath.Join(root, "filebeat"+migratedSuffix))
+}
+
+// BenchmarkSet compares updating the states of a filestream like registry
+// with memlog and bbolt.
+func BenchmarkSet(b *testing.B) {
+	const entries = 10000
+
+	benchmarks := map[string]func(b *testing.B, root string) backend.Registry{
+		"memlog": func(b *testing.B, root string) backend.Registry {
+			reg, err := memlog.New(logptest.NewTestingLogger(b, ""), memlog.Settings{Root: root})
+			require.NoError(b, err)
+			return reg
+		},
+		"bbolt": func(b *testing.B, root string) backend.Registry {
+			reg, err := New(logptest.NewTestingLogger(b, ""), Settings{Root: root})
+			require.NoError(b, err)
+			return reg
+		},
+	}
+
+	for name, open := range benchmarks {
+		b.Run(name, func(b *testing.B) {
+			reg := open(b, b.TempDir())
+			defer reg.Close()
+
+			store, err := reg.Access("filebeat")
+			require.NoError(b, err)
+			defer store.Close()
+
+			keys := make([]string, entries)
+			for i := range keys {
+				keys[i] = fmt.Sprintf("filestream::my-input::native::%d-66", i)
+				require.NoError(b, store.Set(keys[i], benchmarkState(0)))
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := store.Set(keys[i%entries], benchmarkState(int64(i))); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func benchmarkState(offset int64) map[string]interface{} {
+	return map[string]interface{}{
+		"ttl":     int64(1800000000000),
+		"updated": []int64{281470681743360, 1700000000},
+		"cursor":  map[string]interface{}{"offset": offset, "eof": false},
+		"meta": map[string]interface{}{
+			"source":          "/var/log/app/a.log",
+			"identifier_name": "native",
+		},
+	}
+}
+
+func writeMemlogStore[T any](t *testing.T, root, name string, states map[string]T) {
+	t.Helper()
+
+	logger := logptest.NewTestingLogger(t, "")
+	memreg, err := memlog.New(logger, memlog.Settings{Root: root})
+	require.NoError(t, err)
+	memstore, err := memreg.Access(name)
+	require.NoError(t, err)
+	for k, v := range states {
+		require.NoError(t, memstore.Set(k, v))
+	}
+	require.NoError(t, memstore.Close())
+	require.NoError(t, memreg.Close())
+}
+
+func readStates[T any](t *testing.T, store backend.Store) map[string]T {
+	t.Helper()
+
+	got := map[string]T{}
+	err := store.Each(func(key string, dec backend.ValueDecoder) (bool, error) {
+		var value T
+		err := dec.Decode(&value)
+		got[key] = value
+		return true, err
+	})
+	require.NoError(t, err)
+	return got
+}
@@ -63,16 +63,35 @@ func NewDBClient(driver, uri string, l *logp.Logger) (*DbClient, error) {
 }
 
 // FetchTableMode scan the rows and publishes the event for querys that return the response in a table format.
-func (d *DbClient) FetchTableMode(ctx context.Context, q string) ([]mapstr.M, error) {
-	rows, err := d.QueryContext(ctx, q)
+// Any args are passed to the driver as query parameters.
+func (d *DbClient) FetchTableMode(ctx context.Context, q string, args ...interface{}) ([]mapstr.M, error) {
+	rows, err := d.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, err
 	}
 	return d.fetchTableMode(rows)
 }
 
+// FetchTableModeStrict is like FetchTableMode, but fails if a row cannot be
+// scanned or if reading the rows fails, instead of returning the rows read
+// so far. It is meant for callers that must not miss any row.
+func (d *DbClient) FetchTableModeStrict(ctx context.Context, q string, args ...interface{}) ([]mapstr.M, error) {
+	rows, err := d.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return d.scanTableMode(rows, true)
+}
+
 // fetchTableMode scan the rows and publishes the event for querys that return the response in a table format.
 func (d *DbClient) fetchTableMode(rows sqlRow) ([]mapstr.M, error) {
+	return d.scanTableMode(rows, false)
+}
+
+// scanTableMode scans the rows of a table. Rows that cannot be scanned are
+// skipped and errors reading the rows are ignored, unless strict is set.
+func (d *DbClient) scanTableMode(rows sqlRow, strict bool) ([]mapstr.M, error) {
 	// Extracted from
 	// https://stackoverflow.com/questions/23507531/is-golangs-sql-package-incapable-of-ad-hoc-exploratory-queries/23507765#23507765
 	cols, err := rows.Columns()
@@ -93,6 +112,9 @@ func (d *DbClient) fetchTableMode(rows sqlRow) ([]mapstr.M, error) {
 	for rows.Next() {
 		err = rows.Scan(vals...)
 		if err != nil {
+			if strict {
+				return nil, fmt.Errorf("error trying to scan rows: %w", err)
+			}
 			d.logger.Debug(fmt.Errorf("error trying to scan rows: %w", err))
 			continue
 		}
@@ -108,6 +130,9 @@ func (d *DbClient) fetchTableMode(rows sqlRow) ([]mapstr.M, error) {
 	}
 
 	if err = rows.Err(); err != nil {
+		if strict {
+			return nil, fmt.Errorf("error trying to read rows: %w", err)
+		}
 		d.logger.Debug(fmt.Errorf("error trying to read rows: %w", err))
 	}
 
@@ -21,6 +21,7 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"errors"
 	"fmt"
 	"strconv"
 	"testing"
@@ -29,6 +30,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/elastic/beats/v7/libbeat/tests/resources"
+	"github.com/elastic/elastic-agent-libs/logp"
 	"github.com/elastic/elastic-agent-libs/mapstr"
 )
 
@@ -69,9 +71,15 @@ func (m mockVariableMode) Err() error {
 type mockTableMode struct {
 	results      []kv
 	totalResults int
+	scanErr      error
+	err          error
 }
 
 func (m *mockTableMode) Scan(dest ...interface{}) error {
+	if m.scanErr != nil {
+		m.totalResults++
+		return m.scanErr
+	}
 	for i, d := range dest {
 		d1 := d.(*interface{}) //nolint:errcheck // false positive
 		*d1 = m.results[i].v
@@ -95,7 +103,7 @@ func (m *mockTableMode) Columns() ([]string, error) {
 }
 
 func (m mockTableMode) Err() error {
-	return nil
+	return m.err
 }
 
 var results = []kv{
@@ -142,6 +150,28 @@ func TestFetchTableMode(t *testing.T) {
 	}
 }
 
+func TestFetchTableModeErrors(t *testing.T) {
+	db := DbClient{logger: logp.NewLogger("test")}
+	errScan := errors.New("scan failed")
+	errRows := errors.New("connection reset")
+
+	// rows that cannot be scanned are skipped
+	mss, err := db.scanTableMode(&mockTableMode{results: results, scanErr: errScan}, false)
+	require.NoError(t, err)
+	require.Empty(t, mss)
+
+	mss, err = db.scanTableMode(&mockTableMode{results: results, err: errRows}, false)
+	require.NoError(t, err)
+	require.Len(t, mss, len(results))
+
+	// strict mode fails instead of returning partial results
+	_, err = db.scanTableMode(&mockTableMode{results: results, scanErr: errScan}, true)
+	require.ErrorIs(t, err, errScan)
+
+	_, err = db.scanTableMode(&mockTableMode{results: results, err: errRows}, true)
+	require.ErrorIs(t, err, errRows)
+}
+
 func checkValue(t *testing.T, res kv, ms mapstr.M) {
 	t.Helper()
 
@@ -6,20 +6,17 @@ package awss3
 
 import (
 	"encoding/json"
-	"errors"
 	"os"
 	"path/filepath"
-	"reflect"
 	"testing"
 
-	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 
-	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/beats/v7/x-pack/libbeat/reader/decoder"
 )
 
-// all test files are read from the "testdata" directory
-const testDataPath = "testdata"
+// all test files are read from the "testdata" directory of the shared decoders
+const testDataPath = "../../../libbeat/reader/decoder/testdata"
 
 func TestDecoding(t *testing.T) {
 	testCases := []struct {
@@ -35,9 +32,9 @@ func TestDecoding(t *testing.T) {
 			file:      "vpc-flow.gz.parquet",
 			numEvents: 1304,
 			config: &readerConfig{
-				Decoding: decoderConfig{
-					Codec: &codecConfig{
-						Parquet: &parquetCodecConfig{
+				Decoding: decoder.Config{
+					Codec: &decoder.CodecConfig{
+						Parquet: &decoder.ParquetCodecConfig{
 							ProcessParallel: true,
 							BatchSize:       1,
 						},
@@ -50,9 +47,9 @@ func TestDecoding(t *testing.T) {
 			file:      "vpc-flow.gz.parquet",
 			numEvents: 1304,
 			config: &readerConfig{
-				Decoding: decoderConfig{
-					Codec: &codecConfig{
-						Parquet: &parquetCodecConfig{
+				Decoding: decoder.Config{
+					Codec: &decoder.CodecConfig{
+						Parquet: &decoder.ParquetCodecConfig{
 							ProcessParallel: true,
 							BatchSize:       100,
 						},
@@ -65,9 +62,9 @@ func TestDecoding(t *testing.T) {
 			file:      "vpc-flow.gz.parquet",
 			numEvents: 1304,
 			config: &readerConfig{
-				Decoding: decoderConfig{
-					Codec: &codecConfig{
-						Parquet: &parquetCodecConfig{
+				Decoding: decoder.Config{
+					Codec: &decoder.CodecConfig{
+						Parquet: &decoder.ParquetCodecConfig{
 							Enabled: true,
 						},
 					},
@@ -80,9 +77,9 @@ func TestDecoding(t *testing.T) {
 			numEvents:     1,
 			assertAgainst: "cloudtrail.json",
 			config: &readerConfig{
-				Decoding: decoderConfig{
-					Codec: &codecConfig{
-						Parquet: &parquetCodecConfig{
+				Decoding: decoder.Config{
+					Codec: &decoder.CodecConfig{
+						Parquet: &decoder.ParquetCodecConfig{
 							Enabled:         true,
 							ProcessParallel: true,
 							BatchSize:       1,
@@ -97,11 +94,11 @@ func TestDecoding(t *testing.T) {
 			numEvents:     4,
 			assertAgainst: "txn.json",
 			config: &readerConfig{
-				Decoding: decoderConfig{
-					Codec: &codecConfig{
-						CSV: &csvCodecConfig{
+				Decoding: decoder.Config{
+					Codec: &decoder.CodecConfig{
+						CSV: &decoder.CSVCodecConfig{
 							Enabled: true,
-							Comma:   ptr[configRune](' '),
+							Comma:   ptr[decoder.ConfigRune](' '),
 						},
 					},
 				},
@@ -113,11 +110,26 @@ func TestDecoding(t *testing.T) {
 			numEvents:     4,
 			assertAgainst: "txn.json",
 			config: &readerConfig{
-				Decoding: decoderConfig{
-					Codec: &codecConfig{
-						CSV: &csvCodecConfig{
+				Decoding: decoder.Config{
+					Codec: &decoder.CodecConfig{
+						CSV: &decoder.CSVCodecConfig{
+							Enabled: true,
+							Comma:   ptr[decoder.ConfigRune](' '),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:          "avro",
+			file:          "audit.avro",
+			numEvents:     3,
+			assertAgainst: "audit.json",
+			config: &readerConfig{
+				Decoding: decoder.Config{
+					Codec: &decoder.CodecConfig{
+						Avro: &decoder.AvroCodecConfig{
 							Enabled: true,
-							Comma:   ptr[configRune](' '),
 						},
 					},
 				},
@@ -164,110 +176,4 @@ func readJSONFromFile(t *testing.T, filepath string) []string {
 	return data
 }
 
-var codecConfigTests = []struct {
-	name    string
-	yaml    string
-	want    decoderConfig
-	wantErr error
-}{
-	{
-		name: "handle_rune",
-		yaml: `
-codec:
-  csv:
-    enabled: true
-    comma: ' '
-    comment: '#'
-`,
-		want: decoderConfig{&codecConfig{
-			CSV: &csvCodecConfig{
-				Enabled: true,
-				Comma:   ptr[configRune](' '),
-				Comment: '#',
-			},
-		}},
-	},
-	{
-		name: "no_comma",
-		yaml: `
-codec:
-  csv:
-    enabled: true
-`,
-		want: decoderConfig{&codecConfig{
-			CSV: &csvCodecConfig{
-				Enabled: true,
-			},
-		}},
-	},
-	{
-		name: "null_comma",
-		yaml: `
-codec:
-  csv:
-    enabled: true
-    comma: "\u0000"
-`,
-		want: decoderConfig{&codecConfig{
-			CSV: &csvCodecConfig{
-				Enabled: true,
-				Comma:   ptr[configRune]('\x00'),
-			},
-		}},
-	},
-	{
-		name: "bad_rune",
-		yaml: `
-codec:
-  csv:
-    enabled: true
-    comma: 'this is too long'
-`,
-		wantErr: errors.New(`single character option given more than one character: "this is too long" accessing 'codec.csv.comma'`),
-	},
-	{
-		name: "confused",
-		yaml: `
-codec:
-  csv:
-    enabled: true
-  parquet:
-    enabled: true
-`,
-		wantErr: errors.New(`more than one decoder configured accessing 'codec'`),
-	},
-}
-
-func TestCodecConfig(t *testing.T) {
-	for _, test := range codecConfigTests {
-		t.Run(test.name, func(t *testing.T) {
-			c, err := conf.NewConfigWithYAML([]byte(test.yaml), "")
-			if err != nil {
-				t.Fatalf("unexpected error unmarshaling config: %v", err)
-			}
-
-			var got decoderConfig
-			err = c.Unpack(&got)
-			if !sameError(err, test.wantErr) {
-				t.Errorf("unexpected error unpacking config: got:%v want:%v", err, test.wantErr)
-			}
-
-			if !reflect.DeepEqual(got, test.want) {
-				t.Errorf("unexpected result\n--- want\n+++ got\n%s", cmp.Diff(test.want, got))
-			}
-		})
-	}
-}
-
-func sameError(a, b error) bool {
-	switch {
-	case a == nil && b == nil:
-		return true
-	case a == nil, b == nil:
-		return false
-	default:
-		return a.Error() == b.Error()
-	}
-}
-
 func ptr[T any](v T) *T { return &v }
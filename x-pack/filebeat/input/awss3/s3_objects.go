@@ -25,6 +25,7 @@ import (
 	"github.com/elastic/beats/v7/libbeat/reader"
 	"github.com/elastic/beats/v7/libbeat/reader/readfile"
 	"github.com/elastic/beats/v7/libbeat/reader/readfile/encoding"
+	"github.com/elastic/beats/v7/x-pack/libbeat/reader/decoder"
 	"github.com/elastic/elastic-agent-libs/logp"
 	"github.com/elastic/elastic-agent-libs/mapstr"
 )
@@ -155,44 +156,40 @@ func (p *s3ObjectProcessor) ProcessS3Object(log *logp.Logger, eventCallback func
 	}
 
 	// try to create a dec from the using the codec config
-	dec, err := newDecoder(p.readerConfig.Decoding, reader)
+	dec, err := decoder.NewDecoder(p.readerConfig.Decoding, reader)
 	if err != nil {
 		return err
 	}
 	switch dec := dec.(type) {
-	case valueDecoder:
-		defer dec.close()
+	case decoder.ValueDecoder:
+		defer dec.Close()
 
-		for dec.next() {
-			evtOffset, val, err := dec.decodeValue()
+		for dec.Next() {
+			evtOffset, data, _, err := dec.DecodeValue()
 			if err != nil {
 				if errors.Is(err, io.EOF) {
 					return nil
 				}
 				break
 			}
-			data, err := json.Marshal(val)
-			if err != nil {
-				return err
-			}
 			evt := p.createEvent(string(data), evtOffset)
 
 			p.eventCallback(evt)
 		}
 
-	case decoder:
-		defer dec.close()
+	case decoder.Decoder:
+		defer dec.Close()
 
 		var evtOffset int64
-		for dec.next() {
-			data, err := dec.decode()
+		for dec.Next() {
+			data, err := dec.Decode()
 			if err != nil {
 				if errors.Is(err, io.EOF) {
 					return nil
 				}
 				break
 			}
-			evtOffset, err = p.readJSONSlice(bytes.NewReader(data), evtOffset)
+			evtOffset, err = p.readJSONItem(data, evtOffset)
 			if err != nil {
 				break
 			}
@@ -317,22 +314,31 @@ func (p *s3ObjectProcessor) readJSONSlice(r io.Reader, evtOffset int64) (int64,
 			return -1, fmt.Errorf("failed to decode json: %w", err)
 		}
 
-		if p.readerConfig.ExpandEventListFromField != "" {
-			if err := p.splitEventList(p.readerConfig.ExpandEventListFromField, item, evtOffset, p.s3ObjHash); err != nil {
-				return -1, err
-			}
-			continue
+		evtOffset, err = p.readJSONItem(item, evtOffset)
+		if err != nil {
+			return -1, err
 		}
-
-		data, _ := item.MarshalJSON()
-		evt := p.createEvent(string(data), evtOffset)
-		p.eventCallback(evt)
-		evtOffset++
 	}
 
 	return evtOffset, nil
 }
 
+// readJSONItem publishes the events of a single JSON value, and returns the
+// offset of the next value.
+func (p *s3ObjectProcessor) readJSONItem(item json.RawMessage, evtOffset int64) (int64, error) {
+	if p.readerConfig.ExpandEventListFromField != "" {
+		if err := p.splitEventList(p.readerConfig.ExpandEventListFromField, item, evtOffset, p.s3ObjHash); err != nil {
+			return -1, err
+		}
+		return evtOffset, nil
+	}
+
+	data, _ := item.MarshalJSON()
+	evt := p.createEvent(string(data), evtOffset)
+	p.eventCallback(evt)
+	return evtOffset + 1, nil
+}
+
 func (p *s3ObjectProcessor) splitEventList(key string, raw json.RawMessage, offset int64, objHash string) error {
 	// .[] signifies the root object is an array, and it should be split.
 	if key != ".[]" {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/elastic/beats/v7/libbeat/common/match"
 	"github.com/elastic/beats/v7/libbeat/reader/parser"
+	"github.com/elastic/beats/v7/x-pack/libbeat/reader/decoder"
 )
 
 // MaxWorkers, Poll, PollInterval, FileSelectors, TimeStampEpoch & ExpandEventListFromField can
@@ -51,8 +52,8 @@ type fileSelectorConfig struct {
 
 // readerConfig defines the options for reading the content of an azure container.
 type readerConfig struct {
-	Parsers  parser.Config `config:",inline"`
-	Decoding decoderConfig `config:"decoding"`
+	Parsers  parser.Config  `config:",inline"`
+	Decoding decoder.Config `config:"decoding"`
 }
 
 type authConfig struct {
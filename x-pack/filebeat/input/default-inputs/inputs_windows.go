@@ -26,6 +26,7 @@ import (
 	"github.com/elastic/beats/v7/x-pack/filebeat/input/netflow"
 	"github.com/elastic/beats/v7/x-pack/filebeat/input/o365audit"
 	"github.com/elastic/beats/v7/x-pack/filebeat/input/salesforce"
+	"github.com/elastic/beats/v7/x-pack/filebeat/input/sql"
 	"github.com/elastic/elastic-agent-libs/logp"
 )
 
@@ -46,6 +47,7 @@ func xpackInputs(info beat.Info, log *logp.Logger, store statestore.States) []v2
 		etw.Plugin(),
 		netflow.Plugin(log),
 		salesforce.Plugin(log, store),
+		sql.Plugin(log, store),
 		benchmark.Plugin(),
 	}
 }
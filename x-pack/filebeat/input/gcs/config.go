@@ -17,6 +17,7 @@ import (
 
 	"github.com/elastic/beats/v7/libbeat/common/match"
 	"github.com/elastic/beats/v7/libbeat/reader/parser"
+	"github.com/elastic/beats/v7/x-pack/libbeat/reader/decoder"
 )
 
 // MaxWorkers, Poll, PollInterval, BucketTimeOut, ParseJSON, FileSelectors, TimeStampEpoch & ExpandEventListFromField
@@ -73,8 +74,8 @@ type fileSelectorConfig struct {
 
 // readerConfig defines the options for reading the content of an GCS object.
 type readerConfig struct {
-	Parsers  parser.Config `config:",inline"`
-	Decoding decoderConfig `config:"decoding"`
+	Parsers  parser.Config  `config:",inline"`
+	Decoding decoder.Config `config:"decoding"`
 }
 
 // authConfig defines the authentication mechanism to be used for accessing the gcs bucket.
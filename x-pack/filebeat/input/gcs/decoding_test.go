@@ -7,23 +7,20 @@ package gcs
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"os"
 	"path/filepath"
-	"reflect"
 	"testing"
 
 	"cloud.google.com/go/storage"
-	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/elastic/beats/v7/libbeat/beat"
-	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/beats/v7/x-pack/libbeat/reader/decoder"
 	"github.com/elastic/elastic-agent-libs/logp"
 )
 
-// all test files are read from the "testdata" directory
-const testDataPath = "testdata"
+// all test files are read from the "testdata" directory of the shared decoders
+const testDataPath = "../../../libbeat/reader/decoder/testdata"
 
 func TestDecoding(t *testing.T) {
 	logp.TestingSetup()
@@ -35,18 +32,18 @@ func TestDecoding(t *testing.T) {
 		contentType   string
 		numEvents     int
 		assertAgainst string
-		config        decoderConfig
+		config        decoder.Config
 	}{
 		{
 			name:          "gzip_csv",
 			file:          "txn.csv.gz",
 			numEvents:     4,
 			assertAgainst: "txn.json",
-			config: decoderConfig{
-				Codec: &codecConfig{
-					CSV: &csvCodecConfig{
+			config: decoder.Config{
+				Codec: &decoder.CodecConfig{
+					CSV: &decoder.CSVCodecConfig{
 						Enabled: true,
-						Comma:   ptr[configRune](' '),
+						Comma:   ptr[decoder.ConfigRune](' '),
 					},
 				},
 			},
@@ -56,11 +53,52 @@ func TestDecoding(t *testing.T) {
 			file:          "txn.csv",
 			numEvents:     4,
 			assertAgainst: "txn.json",
-			config: decoderConfig{
-				Codec: &codecConfig{
-					CSV: &csvCodecConfig{
+			config: decoder.Config{
+				Codec: &decoder.CodecConfig{
+					CSV: &decoder.CSVCodecConfig{
+						Enabled: true,
+						Comma:   ptr[decoder.ConfigRune](' '),
+					},
+				},
+			},
+		},
+		{
+			name:          "parquet",
+			file:          "cloudtrail.parquet",
+			numEvents:     1,
+			assertAgainst: "cloudtrail.json",
+			config: decoder.Config{
+				Codec: &decoder.CodecConfig{
+					Parquet: &decoder.ParquetCodecConfig{
+						Enabled:   true,
+						BatchSize: 1,
+					},
+				},
+			},
+		},
+		{
+			name:      "parquet_batch_size_100",
+			file:      "vpc-flow.gz.parquet",
+			numEvents: 1304,
+			config: decoder.Config{
+				Codec: &decoder.CodecConfig{
+					Parquet: &decoder.ParquetCodecConfig{
+						Enabled:         true,
+						ProcessParallel: true,
+						BatchSize:       100,
+					},
+				},
+			},
+		},
+		{
+			name:          "avro",
+			file:          "audit.avro",
+			numEvents:     3,
+			assertAgainst: "audit.json",
+			config: decoder.Config{
+				Codec: &decoder.CodecConfig{
+					Avro: &decoder.AvroCodecConfig{
 						Enabled: true,
-						Comma:   ptr[configRune](' '),
 					},
 				},
 			},
@@ -87,6 +125,7 @@ func TestDecoding(t *testing.T) {
 			}
 
 			events := p.events
+			assert.Len(t, events, tc.numEvents)
 			if tc.assertAgainst != "" {
 				targetData := readJSONFromFile(t, filepath.Join(testDataPath, tc.assertAgainst))
 				assert.Equal(t, len(targetData), len(events))
@@ -127,99 +166,4 @@ func readJSONFromFile(t *testing.T, filepath string) []string {
 	return data
 }
 
-var codecConfigTests = []struct {
-	name    string
-	yaml    string
-	want    decoderConfig
-	wantErr error
-}{
-	{
-		name: "handle_rune",
-		yaml: `
-codec:
-  csv:
-    enabled: true
-    comma: ' '
-    comment: '#'
-`,
-		want: decoderConfig{&codecConfig{
-			CSV: &csvCodecConfig{
-				Enabled: true,
-				Comma:   ptr[configRune](' '),
-				Comment: '#',
-			},
-		}},
-	},
-	{
-		name: "no_comma",
-		yaml: `
-codec:
-  csv:
-    enabled: true
-`,
-		want: decoderConfig{&codecConfig{
-			CSV: &csvCodecConfig{
-				Enabled: true,
-			},
-		}},
-	},
-	{
-		name: "null_comma",
-		yaml: `
-codec:
-  csv:
-    enabled: true
-    comma: "\u0000"
-`,
-		want: decoderConfig{&codecConfig{
-			CSV: &csvCodecConfig{
-				Enabled: true,
-				Comma:   ptr[configRune]('\x00'),
-			},
-		}},
-	},
-	{
-		name: "bad_rune",
-		yaml: `
-codec:
-  csv:
-    enabled: true
-    comma: 'this is too long'
-`,
-		wantErr: errors.New(`single character option given more than one character: "this is too long" accessing 'codec.csv.comma'`),
-	},
-}
-
-func TestCodecConfig(t *testing.T) {
-	for _, test := range codecConfigTests {
-		t.Run(test.name, func(t *testing.T) {
-			c, err := conf.NewConfigWithYAML([]byte(test.yaml), "")
-			if err != nil {
-				t.Fatalf("unexpected error unmarshaling config: %v", err)
-			}
-
-			var got decoderConfig
-			err = c.Unpack(&got)
-			if !sameError(err, test.wantErr) {
-				t.Errorf("unexpected error unpacking config: got:%v want:%v", err, test.wantErr)
-			}
-
-			if !reflect.DeepEqual(got, test.want) {
-				t.Errorf("unexpected result\n--- want\n+++ got\n%s", cmp.Diff(test.want, got))
-			}
-		})
-	}
-}
-
-func sameError(a, b error) bool {
-	switch {
-	case a == nil && b == nil:
-		return true
-	case a == nil, b == nil:
-		return false
-	default:
-		return a.Error() == b.Error()
-	}
-}
-
 func ptr[T any](v T) *T { return &v }
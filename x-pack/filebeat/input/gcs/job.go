@@ -22,6 +22,7 @@ import (
 
 	cursor "github.com/elastic/beats/v7/filebeat/input/v2/input-cursor"
 	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/x-pack/libbeat/reader/decoder"
 	"github.com/elastic/elastic-agent-libs/logp"
 	"github.com/elastic/elastic-agent-libs/mapstr"
 )
@@ -177,23 +178,23 @@ func (j *job) decode(ctx context.Context, r io.Reader, id string) error {
 	if err != nil {
 		return fmt.Errorf("failed to add gzip decoder to object: %s, with error: %w", j.object.Name, err)
 	}
-	dec, err := newDecoder(j.src.ReaderConfig.Decoding, r)
+	dec, err := decoder.NewDecoder(j.src.ReaderConfig.Decoding, r)
 	if err != nil {
 		return err
 	}
 	var evtOffset int64
 	switch dec := dec.(type) {
-	case valueDecoder:
-		defer dec.close()
+	case decoder.ValueDecoder:
+		defer dec.Close()
 
-		for dec.next() {
+		for dec.Next() {
 			var (
 				msg []byte
 				val []mapstr.M
 			)
 			if j.src.ParseJSON {
 				var v mapstr.M
-				msg, v, err = dec.decodeValue()
+				_, msg, v, err = dec.DecodeValue()
 				if err != nil {
 					if err == io.EOF {
 						return nil
@@ -202,7 +203,7 @@ func (j *job) decode(ctx context.Context, r io.Reader, id string) error {
 				}
 				val = []mapstr.M{v}
 			} else {
-				msg, err = dec.decode()
+				msg, err = dec.Decode()
 				if err != nil {
 					if err == io.EOF {
 						return nil
@@ -211,14 +212,14 @@ func (j *job) decode(ctx context.Context, r io.Reader, id string) error {
 				}
 			}
 			evt := j.createEvent(msg, val, evtOffset)
-			j.publish(evt, !dec.more(), id)
+			j.publish(evt, !dec.More(), id)
 		}
 
-	case decoder:
-		defer dec.close()
+	case decoder.Decoder:
+		defer dec.Close()
 
-		for dec.next() {
-			msg, err := dec.decode()
+		for dec.Next() {
+			msg, err := dec.Decode()
 			if err != nil {
 				if err == io.EOF {
 					return nil
@@ -233,7 +234,7 @@ func (j *job) decode(ctx context.Context, r io.Reader, id string) error {
 				}
 			}
 			evt := j.createEvent(msg, val, evtOffset)
-			j.publish(evt, !dec.more(), id)
+			j.publish(evt, !dec.More(), id)
 		}
 
 	default:
@@ -30,6 +30,8 @@ func toBeatEvent(flow record.Record, internalNetworks []string) (event beat.Even
 		e = flowToBeatEvent(flow, internalNetworks)
 	case record.Options:
 		e = optionsToBeatEvent(flow)
+	case record.Counters:
+		e = countersToBeatEvent(flow)
 	default:
 		e = toBeatEventCommon(flow)
 	}
@@ -61,9 +63,10 @@ func normaliseIPFields(fields mapstr.M) {
 
 func toBeatEventCommon(flow record.Record) beat.Event {
 	const (
-		flowType    = "netflow_flow"
-		optionsType = "netflow_options"
-		unknownType = "netflow_unknown"
+		flowType     = "netflow_flow"
+		optionsType  = "netflow_options"
+		countersType = "netflow_counters"
+		unknownType  = "netflow_unknown"
 	)
 
 	// replace net.HardwareAddress with its String() representation
@@ -77,6 +80,8 @@ func toBeatEventCommon(flow record.Record) beat.Event {
 		flow.Fields["type"] = flowType
 	case record.Options:
 		flow.Fields["type"] = optionsType
+	case record.Counters:
+		flow.Fields["type"] = countersType
 	default:
 		flow.Fields["type"] = unknownType
 	}
@@ -133,6 +138,14 @@ func optionsToBeatEvent(flow record.Record) beat.Event {
 	return toBeatEventCommon(flow)
 }
 
+func countersToBeatEvent(flow record.Record) beat.Event {
+	event := toBeatEventCommon(flow)
+	if ecsEvent, ok := event.Fields["event"].(mapstr.M); ok {
+		ecsEvent["kind"] = "metric"
+	}
+	return event
+}
+
 func flowToBeatEvent(flow record.Record, internalNetworks []string) beat.Event {
 	event := toBeatEventCommon(flow)
 
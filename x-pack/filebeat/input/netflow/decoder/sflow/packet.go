@@ -0,0 +1,179 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package sflow
+
+import (
+	"encoding/binary"
+	"net"
+
+	"github.com/elastic/beats/v7/x-pack/filebeat/input/netflow/decoder/record"
+)
+
+// Header protocols of the raw packet header flow record.
+const (
+	headerProtocolEthernet = 1
+	headerProtocolIPv4     = 11
+	headerProtocolIPv6     = 12
+)
+
+const (
+	etherTypeIPv4  = 0x0800
+	etherTypeIPv6  = 0x86dd
+	etherTypeDot1Q = 0x8100
+	etherTypeQinQ  = 0x88a8
+
+	ipProtocolICMP   = 1
+	ipProtocolTCP    = 6
+	ipProtocolUDP    = 17
+	ipProtocolICMPv6 = 58
+)
+
+// readRawPacketHeader decodes the raw packet header flow record and the
+// sampled packet header it contains. The header is usually truncated, so
+// decoding stops silently at the first layer that is incomplete.
+func readRawPacketHeader(r *reader, fields record.Map) {
+	protocol := r.uint32()
+	frameLength := r.uint32()
+	r.uint32() // Bytes stripped from the frame, unused.
+	header := r.opaque()
+	if r.err != nil {
+		return
+	}
+
+	fields["dataLinkFrameSize"] = uint64(frameLength)
+	fields["octetDeltaCount"] = uint64(frameLength)
+	switch protocol {
+	case headerProtocolEthernet:
+		decodeEthernet(header, fields)
+	case headerProtocolIPv4:
+		decodeIPv4(header, fields)
+	case headerProtocolIPv6:
+		decodeIPv6(header, fields)
+	}
+}
+
+func decodeEthernet(data []byte, fields record.Map) {
+	if len(data) < 14 {
+		return
+	}
+	fields["destinationMacAddress"] = net.HardwareAddr(data[0:6:6])
+	fields["sourceMacAddress"] = net.HardwareAddr(data[6:12:12])
+	etherType := binary.BigEndian.Uint16(data[12:14])
+	data = data[14:]
+	for etherType == etherTypeDot1Q || etherType == etherTypeQinQ {
+		if len(data) < 4 {
+			return
+		}
+		tci := binary.BigEndian.Uint16(data[0:2])
+		// With QinQ the outer tag is reported, as done by exporters that
+		// include a single vlanId.
+		if _, found := fields["vlanId"]; !found {
+			fields["vlanId"] = uint64(tci & 0xfff)
+			fields["dot1qPriority"] = uint64(tci >> 13)
+		}
+		etherType = binary.BigEndian.Uint16(data[2:4])
+		data = data[4:]
+	}
+	fields["ethernetType"] = uint64(etherType)
+	switch etherType {
+	case etherTypeIPv4:
+		decodeIPv4(data, fields)
+	case etherTypeIPv6:
+		decodeIPv6(data, fields)
+	}
+}
+
+func decodeIPv4(data []byte, fields record.Map) {
+	if len(data) < 20 || data[0]>>4 != 4 {
+		return
+	}
+	headerLength := int(data[0]&0xf) * 4
+	if headerLength < 20 || len(data) < headerLength {
+		return
+	}
+	fields["ipVersion"] = uint64(4)
+	fields["ipClassOfService"] = uint64(data[1])
+	fields["ipTotalLength"] = uint64(binary.BigEndian.Uint16(data[2:4]))
+	fields["ipTTL"] = uint64(data[8])
+	fields["protocolIdentifier"] = uint64(data[9])
+	fields["sourceIPv4Address"] = net.IP(data[12:16:16])
+	fields["destinationIPv4Address"] = net.IP(data[16:20:20])
+
+	// Only the first fragment contains the transport header.
+	if fragmentOffset := binary.BigEndian.Uint16(data[6:8]) & 0x1fff; fragmentOffset != 0 {
+		return
+	}
+	decodeTransport(data[9], data[headerLength:], fields)
+}
+
+func decodeIPv6(data []byte, fields record.Map) {
+	if len(data) < 40 || data[0]>>4 != 6 {
+		return
+	}
+	fields["ipVersion"] = uint64(6)
+	fields["ipClassOfService"] = uint64(binary.BigEndian.Uint16(data[0:2]) >> 4 & 0xff)
+	fields["flowLabelIPv6"] = uint64(binary.BigEndian.Uint32(data[0:4]) & 0xfffff)
+	fields["ipTotalLength"] = uint64(binary.BigEndian.Uint16(data[4:6])) + 40
+	fields["ipTTL"] = uint64(data[7])
+	fields["sourceIPv6Address"] = net.IP(data[8:24:24])
+	fields["destinationIPv6Address"] = net.IP(data[24:40:40])
+
+	// Skip the extension headers that are commonly found before the
+	// transport header.
+	next, data := data[6], data[40:]
+extensions:
+	for {
+		switch next {
+		case 0, 43, 60: // Hop-by-hop, routing and destination options.
+			if len(data) < 8 {
+				return
+			}
+			next, data = data[0], data[min(len(data), (int(data[1])+1)*8):]
+		case 44: // Fragment.
+			if len(data) < 8 {
+				return
+			}
+			next = data[0]
+			if binary.BigEndian.Uint16(data[2:4])>>3 != 0 {
+				// Only the first fragment contains the transport header.
+				fields["protocolIdentifier"] = uint64(next)
+				return
+			}
+			data = data[8:]
+		default:
+			break extensions
+		}
+	}
+	fields["protocolIdentifier"] = uint64(next)
+	decodeTransport(next, data, fields)
+}
+
+func decodeTransport(protocol uint8, data []byte, fields record.Map) {
+	switch protocol {
+	case ipProtocolTCP:
+		if len(data) < 14 {
+			return
+		}
+		fields["sourceTransportPort"] = uint64(binary.BigEndian.Uint16(data[0:2]))
+		fields["destinationTransportPort"] = uint64(binary.BigEndian.Uint16(data[2:4]))
+		fields["tcpControlBits"] = uint64(binary.BigEndian.Uint16(data[12:14]) & 0x1ff)
+	case ipProtocolUDP:
+		if len(data) < 4 {
+			return
+		}
+		fields["sourceTransportPort"] = uint64(binary.BigEndian.Uint16(data[0:2]))
+		fields["destinationTransportPort"] = uint64(binary.BigEndian.Uint16(data[2:4]))
+	case ipProtocolICMP:
+		if len(data) < 2 {
+			return
+		}
+		fields["icmpTypeCodeIPv4"] = uint64(binary.BigEndian.Uint16(data[0:2]))
+	case ipProtocolICMPv6:
+		if len(data) < 2 {
+			return
+		}
+		fields["icmpTypeCodeIPv6"] = uint64(binary.BigEndian.Uint16(data[0:2]))
+	}
+}
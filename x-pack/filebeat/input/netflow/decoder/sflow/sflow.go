@@ -0,0 +1,474 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package sflow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/elastic/beats/v7/x-pack/filebeat/input/netflow/decoder/config"
+	"github.com/elastic/beats/v7/x-pack/filebeat/input/netflow/decoder/protocol"
+	"github.com/elastic/beats/v7/x-pack/filebeat/input/netflow/decoder/record"
+	"github.com/elastic/elastic-agent-libs/logp"
+)
+
+const (
+	ProtocolName = "sflow"
+	LogPrefix    = "[sflow] "
+
+	// ProtocolID is the value of the first 16 bits of an sFlow datagram.
+	// sFlow encodes its version as a 32-bit integer, so for any existing
+	// version the upper half is zero, which no NetFlow version uses.
+	ProtocolID uint16 = 0
+
+	// Version is the only supported sFlow version.
+	Version uint32 = 5
+)
+
+// Sample formats in the standard (enterprise 0) sFlow namespace.
+const (
+	formatFlowSample             = 1
+	formatCountersSample         = 2
+	formatExpandedFlowSample     = 3
+	formatExpandedCountersSample = 4
+)
+
+// Flow record formats in the standard sFlow namespace.
+const (
+	formatRawPacketHeader = 1
+	formatEthernetFrame   = 2
+	formatIPv4Data        = 3
+	formatIPv6Data        = 4
+	formatExtendedSwitch  = 1001
+	formatExtendedRouter  = 1002
+)
+
+// Counter record formats in the standard sFlow namespace.
+const (
+	formatGenericInterfaceCounters  = 1
+	formatEthernetInterfaceCounters = 2
+)
+
+var errShortData = errors.New("sflow: data too short")
+
+type SFlowProtocol struct {
+	logger  *logp.Logger
+	timeNow func() time.Time
+}
+
+func init() {
+	if err := protocol.Registry.Register(ProtocolName, New); err != nil {
+		panic(err)
+	}
+}
+
+func New(config config.Config) protocol.Protocol {
+	return &SFlowProtocol{
+		logger:  config.LogOutput().Named(LogPrefix),
+		timeNow: time.Now,
+	}
+}
+
+func (*SFlowProtocol) Version() uint16 {
+	return ProtocolID
+}
+
+func (*SFlowProtocol) Start() error {
+	return nil
+}
+
+func (*SFlowProtocol) Stop() error {
+	return nil
+}
+
+// DatagramHeader is the header of an sFlow v5 datagram.
+type DatagramHeader struct {
+	Version        uint32
+	AgentAddress   net.IP
+	SubAgentID     uint32
+	SequenceNumber uint32
+	Uptime         uint32 // milliseconds
+	NumSamples     uint32
+}
+
+func (p *SFlowProtocol) OnPacket(buf *bytes.Buffer, source net.Addr) (records []record.Record, err error) {
+	r := &reader{data: buf.Bytes()}
+	buf.Reset()
+
+	header, err := readDatagramHeader(r)
+	if err != nil {
+		p.logger.Debugf("Failed parsing packet: %v", err)
+		return nil, fmt.Errorf("error reading sflow header: %w", err)
+	}
+	timestamp := p.timeNow().UTC()
+	metadata := record.Map{
+		"version":        uint64(header.Version),
+		"protocol":       ProtocolName,
+		"timestamp":      timestamp,
+		"uptimeMillis":   uint64(header.Uptime),
+		"address":        source.String(),
+		"agentAddress":   header.AgentAddress,
+		"subAgentId":     uint64(header.SubAgentID),
+		"sequenceNumber": uint64(header.SequenceNumber),
+	}
+
+	for i := uint32(0); i < header.NumSamples; i++ {
+		format, data := r.uint32(), r.opaque()
+		if r.err != nil {
+			return records, fmt.Errorf("error reading sample %d of %d: %w", i+1, header.NumSamples, r.err)
+		}
+		enterprise, format := format>>12, format&0xfff
+		if enterprise != 0 {
+			p.logger.Debugf("Ignoring sample from enterprise %d format %d", enterprise, format)
+			continue
+		}
+
+		var rec record.Record
+		sample := &reader{data: data}
+		switch format {
+		case formatFlowSample, formatExpandedFlowSample:
+			rec = readFlowSample(sample, format == formatExpandedFlowSample, p.logger)
+		case formatCountersSample, formatExpandedCountersSample:
+			rec = readCountersSample(sample, format == formatExpandedCountersSample, p.logger)
+		default:
+			p.logger.Debugf("Ignoring sample of unknown format %d", format)
+			continue
+		}
+		if sample.err != nil {
+			return records, fmt.Errorf("error parsing sample %d of %d: %w", i+1, header.NumSamples, sample.err)
+		}
+
+		rec.Timestamp = timestamp
+		rec.Exporter = make(record.Map, len(metadata)+1)
+		for k, v := range metadata {
+			rec.Exporter[k] = v
+		}
+		if sourceID, ok := rec.Fields["sourceId"]; ok {
+			rec.Exporter["sourceId"] = sourceID
+			delete(rec.Fields, "sourceId")
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+func readDatagramHeader(r *reader) (header DatagramHeader, err error) {
+	header.Version = r.uint32()
+	if r.err == nil && header.Version != Version {
+		return header, fmt.Errorf("unsupported sflow version %d", header.Version)
+	}
+	header.AgentAddress = r.address()
+	header.SubAgentID = r.uint32()
+	header.SequenceNumber = r.uint32()
+	header.Uptime = r.uint32()
+	header.NumSamples = r.uint32()
+	return header, r.err
+}
+
+// readFlowSample decodes a flow sample. Each flow sample describes a single
+// sampled packet, so the packet and octet counts are those of the sampled
+// packet and samplingInterval must be used to estimate the total traffic.
+func readFlowSample(r *reader, expanded bool, logger *logp.Logger) record.Record {
+	fields := record.Map{
+		"packetDeltaCount": uint64(1),
+	}
+
+	r.uint32() // Sequence number, unused.
+	fields["sourceId"] = readSourceID(r, expanded)
+	fields["samplingInterval"] = uint64(r.uint32())
+	r.uint32() // Sample pool, unused.
+	fields["droppedPacketDeltaCount"] = uint64(r.uint32())
+	if input, ok := readInterface(r, expanded); ok {
+		fields["ingressInterface"] = input
+	}
+	if output, ok := readInterface(r, expanded); ok {
+		fields["egressInterface"] = output
+	}
+
+	numRecords := r.uint32()
+	for i := uint32(0); i < numRecords && r.err == nil; i++ {
+		format, data := r.uint32(), r.opaque()
+		if r.err != nil {
+			break
+		}
+		rec := &reader{data: data}
+		switch format {
+		case formatRawPacketHeader:
+			readRawPacketHeader(rec, fields)
+		case formatEthernetFrame:
+			readEthernetFrameData(rec, fields)
+		case formatIPv4Data:
+			readIPData(rec, fields, net.IPv4len)
+		case formatIPv6Data:
+			readIPData(rec, fields, net.IPv6len)
+		case formatExtendedSwitch:
+			readExtendedSwitch(rec, fields)
+		case formatExtendedRouter:
+			readExtendedRouter(rec, fields)
+		default:
+			logger.Debugf("Ignoring flow record of format %d:%d", format>>12, format&0xfff)
+			continue
+		}
+		if rec.err != nil {
+			r.err = fmt.Errorf("flow record format %d: %w", format, rec.err)
+		}
+	}
+	return record.Record{
+		Type:   record.Flow,
+		Fields: fields,
+	}
+}
+
+// readCountersSample decodes a counters sample. Counter values are reported
+// as the totals maintained by the agent.
+func readCountersSample(r *reader, expanded bool, logger *logp.Logger) record.Record {
+	fields := record.Map{}
+	r.uint32() // Sequence number, unused.
+	fields["sourceId"] = readSourceID(r, expanded)
+
+	numRecords := r.uint32()
+	for i := uint32(0); i < numRecords && r.err == nil; i++ {
+		format, data := r.uint32(), r.opaque()
+		if r.err != nil {
+			break
+		}
+		rec := &reader{data: data}
+		switch format {
+		case formatGenericInterfaceCounters:
+			readGenericInterfaceCounters(rec, fields)
+		case formatEthernetInterfaceCounters:
+			readEthernetInterfaceCounters(rec, fields)
+		default:
+			logger.Debugf("Ignoring counter record of format %d:%d", format>>12, format&0xfff)
+			continue
+		}
+		if rec.err != nil {
+			r.err = fmt.Errorf("counter record format %d: %w", format, rec.err)
+		}
+	}
+	return record.Record{
+		Type:   record.Counters,
+		Fields: fields,
+	}
+}
+
+// readSourceID returns the data source of a sample as a single value with
+// the source type in the upper 8 bits and the source index in the lower 24,
+// which is the compact encoding.
+func readSourceID(r *reader, expanded bool) uint64 {
+	if !expanded {
+		return uint64(r.uint32())
+	}
+	sourceType, index := r.uint32(), r.uint32()
+	return uint64(sourceType)<<24 | uint64(index&0xffffff)
+}
+
+// readInterface returns the ifIndex of an input or output interface. It
+// returns false when the interface is unknown, the packet was discarded or
+// it was sent to multiple interfaces.
+func readInterface(r *reader, expanded bool) (uint64, bool) {
+	var format, value uint32
+	if expanded {
+		format, value = r.uint32(), r.uint32()
+	} else {
+		v := r.uint32()
+		format, value = v>>30, v&0x3fffffff
+	}
+	if format != 0 || value == 0 {
+		return 0, false
+	}
+	return uint64(value), true
+}
+
+func readEthernetFrameData(r *reader, fields record.Map) {
+	if length := uint64(r.uint32()); fields["dataLinkFrameSize"] == nil {
+		fields["dataLinkFrameSize"] = length
+	}
+	fields["sourceMacAddress"] = r.mac()
+	fields["destinationMacAddress"] = r.mac()
+	fields["ethernetType"] = uint64(r.uint32())
+}
+
+func readIPData(r *reader, fields record.Map, addrLen int) {
+	// The length of the IP packet is only used when the frame length is
+	// not known from a raw packet header record.
+	if length := uint64(r.uint32()); fields["octetDeltaCount"] == nil {
+		fields["octetDeltaCount"] = length
+	}
+	fields["protocolIdentifier"] = uint64(r.uint32())
+	src, dst := r.bytes(addrLen), r.bytes(addrLen)
+	fields["sourceTransportPort"] = uint64(r.uint32())
+	fields["destinationTransportPort"] = uint64(r.uint32())
+	fields["tcpControlBits"] = uint64(r.uint32())
+	fields["ipClassOfService"] = uint64(r.uint32())
+	if r.err != nil {
+		return
+	}
+	if addrLen == net.IPv4len {
+		fields["ipVersion"] = uint64(4)
+		fields["sourceIPv4Address"] = net.IP(src)
+		fields["destinationIPv4Address"] = net.IP(dst)
+	} else {
+		fields["ipVersion"] = uint64(6)
+		fields["sourceIPv6Address"] = net.IP(src)
+		fields["destinationIPv6Address"] = net.IP(dst)
+	}
+}
+
+func readExtendedSwitch(r *reader, fields record.Map) {
+	fields["vlanId"] = uint64(r.uint32())
+	fields["dot1qPriority"] = uint64(r.uint32())
+	fields["postVlanId"] = uint64(r.uint32())
+	r.uint32() // Outgoing 802.1p priority, no IPFIX equivalent.
+}
+
+func readExtendedRouter(r *reader, fields record.Map) {
+	nextHop := r.address()
+	srcMask, dstMask := r.uint32(), r.uint32()
+	if r.err != nil {
+		return
+	}
+	if nextHop.To4() != nil {
+		fields["ipNextHopIPv4Address"] = nextHop
+		fields["sourceIPv4PrefixLength"] = uint64(srcMask)
+		fields["destinationIPv4PrefixLength"] = uint64(dstMask)
+	} else {
+		fields["ipNextHopIPv6Address"] = nextHop
+		fields["sourceIPv6PrefixLength"] = uint64(srcMask)
+		fields["destinationIPv6PrefixLength"] = uint64(dstMask)
+	}
+}
+
+func readGenericInterfaceCounters(r *reader, fields record.Map) {
+	fields["ifIndex"] = uint64(r.uint32())
+	fields["ifType"] = uint64(r.uint32())
+	fields["ifSpeed"] = r.uint64()
+	fields["ifDirection"] = uint64(r.uint32())
+	status := r.uint32()
+	fields["ifAdminStatusUp"] = status&1 != 0
+	fields["ifOperStatusUp"] = status&2 != 0
+	fields["ifInOctets"] = r.uint64()
+	fields["ifInUcastPkts"] = uint64(r.uint32())
+	fields["ifInMulticastPkts"] = uint64(r.uint32())
+	fields["ifInBroadcastPkts"] = uint64(r.uint32())
+	fields["ifInDiscards"] = uint64(r.uint32())
+	fields["ifInErrors"] = uint64(r.uint32())
+	fields["ifInUnknownProtos"] = uint64(r.uint32())
+	fields["ifOutOctets"] = r.uint64()
+	fields["ifOutUcastPkts"] = uint64(r.uint32())
+	fields["ifOutMulticastPkts"] = uint64(r.uint32())
+	fields["ifOutBroadcastPkts"] = uint64(r.uint32())
+	fields["ifOutDiscards"] = uint64(r.uint32())
+	fields["ifOutErrors"] = uint64(r.uint32())
+	fields["ifPromiscuousMode"] = r.uint32() == 1
+}
+
+func readEthernetInterfaceCounters(r *reader, fields record.Map) {
+	for _, name := range []string{
+		"dot3StatsAlignmentErrors",
+		"dot3StatsFCSErrors",
+		"dot3StatsSingleCollisionFrames",
+		"dot3StatsMultipleCollisionFrames",
+		"dot3StatsSQETestErrors",
+		"dot3StatsDeferredTransmissions",
+		"dot3StatsLateCollisions",
+		"dot3StatsExcessiveCollisions",
+		"dot3StatsInternalMacTransmitErrors",
+		"dot3StatsCarrierSenseErrors",
+		"dot3StatsFrameTooLongs",
+		"dot3StatsInternalMacReceiveErrors",
+		"dot3StatsSymbolErrors",
+	} {
+		fields[name] = uint64(r.uint32())
+	}
+}
+
+// reader decodes XDR encoded data as used by sFlow. The first error is
+// recorded and subsequent reads return zero values.
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.data) < n {
+		r.err = errShortData
+		return nil
+	}
+	b := r.data[:n:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) uint32() uint32 {
+	b := r.bytes(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (r *reader) uint64() uint64 {
+	b := r.bytes(8)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+// opaque reads variable length data, which is padded to a multiple of four
+// bytes.
+func (r *reader) opaque() []byte {
+	n := r.uint32()
+	if r.err != nil {
+		return nil
+	}
+	if uint64(n) > uint64(len(r.data)) {
+		r.err = errShortData
+		return nil
+	}
+	b := r.bytes(int(n))
+	// Some agents omit the padding of the last element.
+	if pad := int(-n & 3); pad <= len(r.data) {
+		r.data = r.data[pad:]
+	}
+	return b
+}
+
+// mac reads a MAC address, which is padded to eight bytes.
+func (r *reader) mac() net.HardwareAddr {
+	b := r.bytes(8)
+	if b == nil {
+		return nil
+	}
+	return net.HardwareAddr(b[:6])
+}
+
+// address reads an sFlow address, which is an IPv4 or IPv6 address preceded
+// by its type.
+func (r *reader) address() net.IP {
+	const (
+		addressIPv4 = 1
+		addressIPv6 = 2
+	)
+	switch t := r.uint32(); t {
+	case addressIPv4:
+		return net.IP(r.bytes(net.IPv4len))
+	case addressIPv6:
+		return net.IP(r.bytes(net.IPv6len))
+	default:
+		if r.err == nil {
+			r.err = fmt.Errorf("sflow: unknown address type %d", t)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package sflow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent-libs/logp"
+
+	"github.com/elastic/beats/v7/x-pack/filebeat/input/netflow/decoder/config"
+	"github.com/elastic/beats/v7/x-pack/filebeat/input/netflow/decoder/record"
+	"github.com/elastic/beats/v7/x-pack/filebeat/input/netflow/decoder/test"
+)
+
+func init() {
+	logp.TestingSetup()
+}
+
+var testTime = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// xdr builds XDR encoded test data.
+type xdr struct {
+	bytes.Buffer
+}
+
+func (x *xdr) u32(values ...uint32) *xdr {
+	for _, v := range values {
+		_ = binary.Write(x, binary.BigEndian, v)
+	}
+	return x
+}
+
+func (x *xdr) u64(v uint64) *xdr {
+	_ = binary.Write(x, binary.BigEndian, v)
+	return x
+}
+
+func (x *xdr) raw(b []byte) *xdr {
+	x.Write(b)
+	return x
+}
+
+func (x *xdr) opaque(b []byte) *xdr {
+	x.u32(uint32(len(b)))
+	x.Write(b)
+	x.Write(make([]byte, -len(b)&3))
+	return x
+}
+
+func (x *xdr) item(format uint32, body *xdr) *xdr {
+	return x.u32(format).opaque(body.Bytes())
+}
+
+func datagram(samples ...*xdr) []byte {
+	var d xdr
+	d.u32(5, 1).raw([]byte{192, 0, 2, 1}).u32(7, 1234, 60000, uint32(len(samples)))
+	for _, s := range samples {
+		d.raw(s.Bytes())
+	}
+	return d.Bytes()
+}
+
+func mustHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
+
+func newTestProtocol() *SFlowProtocol {
+	proto := New(config.Defaults(logp.L())).(*SFlowProtocol)
+	proto.timeNow = func() time.Time { return testTime }
+	return proto
+}
+
+func TestSFlowProtocol_New(t *testing.T) {
+	proto := New(config.Defaults(logp.L()))
+
+	assert.Nil(t, proto.Start())
+	assert.Equal(t, uint16(0), proto.Version())
+	assert.Nil(t, proto.Stop())
+}
+
+func TestOnPacketFlowSample(t *testing.T) {
+	// Ethernet, 802.1Q VLAN 10 priority 3, IPv4 10.0.0.1 -> 198.51.100.7,
+	// TTL 64, TCP 49152 -> 443 with SYN+ACK, truncated after the TCP header.
+	frame := mustHex(t, "00112233445566778899aabb"+"8100"+"600a"+"0800"+
+		"4510003c1c4640004006"+"0000"+"0a000001"+"c6336407"+
+		"c00001bb"+"00000001"+"00000002"+"5012"+"ffff")
+
+	var rawHeader xdr
+	rawHeader.u32(headerProtocolEthernet, 1518, 4).opaque(frame)
+	var router xdr
+	router.u32(1).raw([]byte{10, 0, 0, 254}).u32(8, 24)
+
+	var sample xdr
+	sample.u32(42, 3, 512, 100000, 2, 17, 3<<30|0)
+	sample.u32(2).item(formatRawPacketHeader, &rawHeader).item(formatExtendedRouter, &router)
+
+	var d xdr
+	d.item(formatFlowSample, &sample)
+	source := test.MakeAddress(t, "192.0.2.1:6343")
+
+	records, err := newTestProtocol().OnPacket(bytes.NewBuffer(datagram(&d)), source)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	assert.Equal(t, record.Record{
+		Type:      record.Flow,
+		Timestamp: testTime,
+		Fields: record.Map{
+			"packetDeltaCount":            uint64(1),
+			"octetDeltaCount":             uint64(1518),
+			"dataLinkFrameSize":           uint64(1518),
+			"samplingInterval":            uint64(512),
+			"droppedPacketDeltaCount":     uint64(2),
+			"ingressInterface":            uint64(17),
+			"destinationMacAddress":       net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55},
+			"sourceMacAddress":            net.HardwareAddr{0x66, 0x77, 0x88, 0x99, 0xaa, 0xbb},
+			"vlanId":                      uint64(10),
+			"dot1qPriority":               uint64(3),
+			"ethernetType":                uint64(0x0800),
+			"ipVersion":                   uint64(4),
+			"ipClassOfService":            uint64(0x10),
+			"ipTotalLength":               uint64(60),
+			"ipTTL":                       uint64(64),
+			"protocolIdentifier":          uint64(6),
+			"sourceIPv4Address":           net.IP{10, 0, 0, 1},
+			"destinationIPv4Address":      net.IP{198, 51, 100, 7},
+			"sourceTransportPort":         uint64(49152),
+			"destinationTransportPort":    uint64(443),
+			"tcpControlBits":              uint64(0x12),
+			"ipNextHopIPv4Address":        net.IP{10, 0, 0, 254},
+			"sourceIPv4PrefixLength":      uint64(8),
+			"destinationIPv4PrefixLength": uint64(24),
+		},
+		Exporter: record.Map{
+			"version":        uint64(5),
+			"protocol":       "sflow",
+			"timestamp":      testTime,
+			"uptimeMillis":   uint64(60000),
+			"address":        "192.0.2.1:6343",
+			"agentAddress":   net.IP{192, 0, 2, 1},
+			"subAgentId":     uint64(7),
+			"sequenceNumber": uint64(1234),
+			"sourceId":       uint64(3),
+		},
+	}, records[0])
+}
+
+func TestOnPacketExpandedFlowSampleIPv6(t *testing.T) {
+	// IPv6 2001:db8::1 -> 2001:db8::2 with a hop-by-hop options header,
+	// UDP 5353 -> 53.
+	packet := mustHex(t, "6000000000181101"+
+		"20010db8000000000000000000000001"+
+		"20010db8000000000000000000000002"+
+		"1100000000000000"+
+		"14e900350010ffff")
+	packet[6] = 0 // Next header is hop-by-hop.
+
+	var rawHeader xdr
+	rawHeader.u32(headerProtocolIPv6, 72, 0).opaque(packet)
+	var sw xdr
+	sw.u32(100, 0, 200, 0)
+
+	var sample xdr
+	sample.u32(1, 0, 5, 1000, 5000, 0, 0, 9, 0, 11)
+	sample.u32(2).item(formatRawPacketHeader, &rawHeader).item(formatExtendedSwitch, &sw)
+
+	var d xdr
+	d.item(formatExpandedFlowSample, &sample)
+
+	records, err := newTestProtocol().OnPacket(bytes.NewBuffer(datagram(&d)), test.MakeAddress(t, "192.0.2.1:6343"))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	fields := records[0].Fields
+	assert.Equal(t, uint64(6), fields["ipVersion"])
+	assert.Equal(t, net.ParseIP("2001:db8::1"), fields["sourceIPv6Address"])
+	assert.Equal(t, net.ParseIP("2001:db8::2"), fields["destinationIPv6Address"])
+	assert.Equal(t, uint64(17), fields["protocolIdentifier"])
+	assert.Equal(t, uint64(5353), fields["sourceTransportPort"])
+	assert.Equal(t, uint64(53), fields["destinationTransportPort"])
+	assert.Equal(t, uint64(9), fields["ingressInterface"])
+	assert.Equal(t, uint64(11), fields["egressInterface"])
+	assert.Equal(t, uint64(100), fields["vlanId"])
+	assert.Equal(t, uint64(200), fields["postVlanId"])
+	assert.Equal(t, uint64(1000), fields["samplingInterval"])
+	assert.Equal(t, uint64(5), records[0].Exporter["sourceId"])
+}
+
+func TestOnPacketCountersSample(t *testing.T) {
+	var generic xdr
+	generic.u32(4, 6).u64(10_000_000_000).u32(1, 3).u64(1<<33).u32(10, 20, 30, 40, 50, 60).u64(1<<34).u32(11, 21, 31, 41, 51, 1)
+	var ethernet xdr
+	ethernet.u32(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13)
+	var unknown xdr
+	unknown.u32(1, 2, 3)
+
+	var sample xdr
+	sample.u32(9, 4)
+	sample.u32(3).item(formatGenericInterfaceCounters, &generic).item(formatEthernetInterfaceCounters, &ethernet).item(2000, &unknown)
+
+	var d xdr
+	d.item(formatCountersSample, &sample)
+
+	records, err := newTestProtocol().OnPacket(bytes.NewBuffer(datagram(&d)), test.MakeAddress(t, "192.0.2.1:6343"))
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	assert.Equal(t, record.Counters, records[0].Type)
+	assert.Equal(t, uint64(4), records[0].Exporter["sourceId"])
+	fields := records[0].Fields
+	assert.Equal(t, uint64(4), fields["ifIndex"])
+	assert.Equal(t, uint64(10_000_000_000), fields["ifSpeed"])
+	assert.Equal(t, true, fields["ifAdminStatusUp"])
+	assert.Equal(t, true, fields["ifOperStatusUp"])
+	assert.Equal(t, uint64(1<<33), fields["ifInOctets"])
+	assert.Equal(t, uint64(60), fields["ifInUnknownProtos"])
+	assert.Equal(t, uint64(1<<34), fields["ifOutOctets"])
+	assert.Equal(t, uint64(51), fields["ifOutErrors"])
+	assert.Equal(t, true, fields["ifPromiscuousMode"])
+	assert.Equal(t, uint64(1), fields["dot3StatsAlignmentErrors"])
+	assert.Equal(t, uint64(13), fields["dot3StatsSymbolErrors"])
+	assert.Len(t, fields, 33)
+}
+
+func TestOnPacketIgnoresUnknownSamples(t *testing.T) {
+	var vendor xdr
+	vendor.u32(1, 2)
+	var d xdr
+	d.item(9<<12|1, &vendor).item(99, &vendor)
+
+	records, err := newTestProtocol().OnPacket(bytes.NewBuffer(datagram(&d)), test.MakeAddress(t, "192.0.2.1:6343"))
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+}
+
+func TestOnPacketErrors(t *testing.T) {
+	source := test.MakeAddress(t, "192.0.2.1:6343")
+
+	t.Run("unsupported version", func(t *testing.T) {
+		var d xdr
+		d.u32(4, 1).raw([]byte{192, 0, 2, 1}).u32(0, 0, 0, 0)
+		_, err := newTestProtocol().OnPacket(bytes.NewBuffer(d.Bytes()), source)
+		assert.ErrorContains(t, err, "unsupported sflow version 4")
+	})
+
+	t.Run("truncated sample", func(t *testing.T) {
+		var sample xdr
+		sample.u32(42, 3, 512)
+		var d xdr
+		d.item(formatFlowSample, &sample)
+		_, err := newTestProtocol().OnPacket(bytes.NewBuffer(datagram(&d)), source)
+		assert.ErrorIs(t, err, errShortData)
+	})
+
+	t.Run("sample count too large", func(t *testing.T) {
+		data := datagram()
+		binary.BigEndian.PutUint32(data[len(data)-4:], 3)
+		_, err := newTestProtocol().OnPacket(bytes.NewBuffer(data), source)
+		assert.ErrorIs(t, err, errShortData)
+	})
+}
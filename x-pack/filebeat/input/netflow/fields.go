@@ -19,5 +19,5 @@ func init() {
 // AssetNetflow returns asset data.
 // This is the base64 encoded zlib format compressed contents of input/netflow.
 func AssetNetflow() string {
-	return "eJysXc2S2ziSvvspFDOHvWx32K6y2+7DnmYndg67O4c57A0BkSkKXSTAAkCp1E+/AfBHpAhQyAR96Imx9X34YSIBZCYy/5r459NfD/86C3M4iRoOwhwqkKC5hfLXw9/UQSp7aFQpTrdfPyUz/nJ4g9vvBwn2VKvrp8PBClvD74e//A/Yv9fq+pdPh0MJptCitULJ3w//8elwOBz+LqAuzeGkVXMYfnngsjz8459//8f/HRyV+fXT4XDyP/vdQ345SN7AvCn3x95a+P1QadW1w98EWnva4q/Dz+btzdt0rUx/OTb6Brer0uXs7yNNuz//OoOHHdRpal5DoXQ5oI5QHo63g3XfBy4g7a+fVt2Aj1ZpC3rVlfn4n3Tkv8Hyklt+0FC7T3+w6mDPMHEfSriIAg72zO1dQPp+9R3+dcb3OGHz3vKy1GDM4t/ic/ek2+7Pfw5d/DfjhOCq9NvYxkHIwz/++bv758NJ6YbPZ2/eJ6M6XQATjy33vaqVrHBd+t+jAX3h7p8PpWq468ff3JRez6I4z2ftcARHbyIds6IBY3nTBjtWcgu4jv1LNODl20Gd0PXfN9J617r2WSPqWoQ/GH5q/ktdPWopXa1WhftgZ24ORwB50J2UQlb/7j5h3z4USpaxebqANkLJYB+FtFAtVkdCN8fFOBAfOgNlYOnxo1F1Z4GB1mq9AEvVHWsIwHr5ZK1SNTuL6szsWYM5q7pccfgp3mao1TWDQFvW8LYVssrtyoxpty61oFlnQBP7dipYq5VVhapXkFEwNlHM/VVUya+hUslbI/70a5+dal4ZRLsLsIXiLMV7t249TtC2tSh6+LEzQoIxv2io4cJlseaJzNmMpOAWKqVv2FmYUcwWGo3A72IZHThb27JOC2Yst8JYUaw/iTkrbVNoDOhfeAXSUijEWmwTUPSRm+64xxe0mp9OovilqLlZT11EiLRlRS1AWjZsyqzfS/iHaLoml0XIHVgMgaHlxRtY1CyoTlrmDlVMg2mVNICHS7iyQkkJhfsieDy95QnJzsJYVWnesGPnJuHLjlxfd+R62ZHrdUeubztyfd+R6zcCl9VcmkYYQ5JGj+ZoUc5UJJkaJEN1jHNP7fkDHt31JR7Xd3eRAZ0790EW9DhCLKTR5H6QMA11PPmfB78rWWV5nT0PQRb0NIRYcLPQM8zUSt6A1kTEMa2IEMMyBppjDSU7aV417vDilWcqvCvFgAgYhiKYC2heAXPXC821Fhc3BBE4Nobxx6p1p30rZH9u5IbJrjkmt+/wEj4s4+UfvHAjJjOcVctEe3lla2vTcIVqn6O/o9Gthkte7wdLFAV64bUohb35e876m0VuGkdxcnf1Uuj+bJmOkyVhih3Krwp/yXf/Sb/d+vtH6BIVnpP+57hrj8eI03jzAVkJCYh7W6HqGhaK4266WnF4812MQmlWgLZ9X5K/5h2M/jQLKE7071Dyp1VNoyRrtWrdoAHzmZU8iRJkAayGC6zNPDEjnLtPUaZJyoxhjlc4txOwstPDRT0iIdERjyzzDQYzY9JqXrylQ0puOTveLKA2IY+qhXxze5jb/XAqZgUXfyJsYY/ooLNmG92b6A3TUAt+FLWwtxXDUakauAwwQG0588oVNWezDRQtnCtwq+EkPnKwrAZZ2XPyN1uy4PTICkzv/vf87je8iPY+uoHMCahaohQVGMvO3Jzdph4wAEdER7i2i/GWrU5sOSnR0dDoNmSTSBiXllTC0ZGXP9aJaYdh3rnQI5SG8c6elRaWW3FJPgg4YIE7+zhIYEOIi6k0rPlg8FGcuayQDTUffoGChpBjYrNNaUrkqc6jPljvicXMYGs1pa13AkRjNygH6kDfposzZmBaozdjaZhRnMFHK3SyRhpAuPvziNJw0mDWuvsZyur1Nr2NMaAFX58cN0ENQTCM4poC0xfsFjKitHCqYz0fT5GW6woILV5BVGckzlrM3NsPy9zxDDGHyn55Z0VnrGpAsxIE4hj3iM09ICz5oh8otgyX8GFn2aMnl5oH7xDxL+fxhMmkjdmAvgi3i0pjuXyM1tkUmjB+p25YXuEY8BOtVdtCyWp+A/2VqcKCZf31AnWzCNH0dkoKTW43ctvvbd4ZHRgICD0wdvSJMyFLWF9QwjiofHjLUSteFtxYeg8GJm+oPfHkyI5HWPjIsYltzzcjCl5T2+6k2GXoF31KXv8gC31rLZR9SI2qVbVe9FE1ibYBDoDg3MYg9gxagmVn4CVo5KV1Qrf8VitexuBRBTMR9J+DDkedYPuTJNG/0UcO4qWwh03BXeu4we2Z7uGmsC0zVgNvUIp8gM/sArHQtM323ZzV6jqYpyhraKRpwBheQQ4FVY2PoZ8UK/eERRvI5kicbWpCUq06PYEPaeyDXdMPMPBhQUtejx1mGni9vk1FZuskNFx5XTMfP45AGcv8YZ5JJRk0rb1Nm+7oLUqOpOvpVkQ4k25PMkCPXMqAfyyqx30sJuOyZIY3be1kIHX+/WrjhRWX3jutOsR378HWanHsbCAAaxuI9sv1qNGx0IhCK5x34ZFgw4G1QQCyHI5m1C44hk1s2H02w6JdbxNWckltVgM3yK/lYMTWzM2wrsU46T0UJ/mirKly/wY3d0R22j0QHr/RZs2PUHstjUH5le1UrHe++sPBJdmuE2AwLS+EXN/vtgighmLaovG3kyUJ9ZK1ZCFflWY0SjNeV+7CfF7vPNtCYCzXNlMb9BxUfTCiaRqhRxN1Qg+mA2kL3PJjDexUd+bcb/v4z95TtMDfcFilr1yXbgkZy22XfkIYrwHhRxPPUKIEaafIjdTOjmh1OhmMtfN0ZceaF2+q8982WZR9FPdJVJ27+GB8ew5pDTO6YKZK/hbX4eOHAyNSQIit4srgw0YOmVsTOaEYrzEjm2AlmAJxDryyM69PTLUgcZI9B55FleqYWOB06JoTxTX8gxlARVmfrkx3NcLBcLoy0zUN1zfWviH1xJX9qSSwlgvMaXqOCjpDwriqVsfZHSjrzVqlwZ1NEn/tPfGDV151tg0cg6Imb4/tzwJh92N0aXikkMIK7rYtjdIXPbidTEIRBZcGDt7SNqD9HDHNZeWDcOnYwHxtYKcDD6nlR3R629a2Tq9bp4yCNrDo4vPQ0QoTM0Zto/sLB2vPmgd8wM+w750PNVFmLRtp0AbsWa2XXRo44mTcBvfHClaoEmE6mF7vsfDrvWiTomha35Y3NqUeSxao9RVmC+V+sdFgdIRr8LrdBDB6lCMqfZTVgEoHSOXOS7OYPILBcWQZ3F9DUGAuDdX8OdLk4sl+FCH7jAg+zLkGfwr2E4z6MiESynvfEBHGsh7EB11zKIrg6QQ1iH4vOUKVvJvEWUCmHm9CHAYaLjGPn0MknRSBZ0Ybc7qby3Okwnp8VjiM03MEN11tM/2WIxXZgToSZHtQRyKMC3U4hjJbtLj7eQ+0SveaLvUWc4fhnrbdvzJRDY1w7Mq3oBsoBbeAd+94NNm9I1rWv3RRpzE4BQEtxamH9aeGVol0F5Foaf7qO270AuFcHCLnkZfIeeM1uxNFhhz5wnccecithgLKYLhsHGSgYKZdR2hFu7kZARBHBQL44p1COt39JxZnRAuXV6ZazOtyD9Gqs6BZwJYUFoXLdwYfFqTx2UG8QCe3N9tSkof1IbjbSK8m7LpMgSG1moP2eb+wDQ4oSnvSMC7NFePanYBeEQZ8rM+RPpSagBuvIx+YK6UHm/6BKGGGjGWFsNiMLnesOyvoW/gCnQ4ntl5zK2wXaPlUKx4VJwdUsqIhNVQ+lxFtvANa4tFD3BkrRHtGi/MIfoMbOiBrQYAzSHmoN5aclUFfgCZwp9d7TgqMYqBxaNEYwUx37FMmYue6/o3xtkUc2GYgwgzV3IIsUo3PHqLhQvYFTwRkN7BnMLogKiyHpCosj6UrrDmc2DpJYXkgSWE5JF1hzdCE4dqzBm4jdsfQGWgJCtgbn4BwuqnmuoJRijHyuzD54cU/02KYGbYfgJuu8be9947r5JRemQbLADynGwaqmJFvC7e8PYWfAUXOpUECrN8/SIILYqyFBFZwXSLGriqm2uSBqitoVghWi0asxxZLh9Bw/ZbYn4Z/sKM4MpBWi+Qv71ADYsrqiYEOsdeoSBoHzIkmfMCj44cWeHwE0QJOgA7hLW6+pQ/J0TDkFUo9fTgaUqzuLHSBICf3oxwCJZquYTQbxogetFMGA8IU0gCXQyR/elSIBw1fBAXrfcxN+Y0VZyjeQpmoov3ssaZQ6a6hBixoUrR7A1YrBpciBIkeDu4ojAOrEUdW8NZ2esw7hvWSeAYlrQ9Hwj4VmoNx5zWH9A4udHitQzaq7GqsecYB1fEPKIg+vhl+DNYLbAAbMzWAyb02N2kDISEpUB96w44h59fzDvdgXC62FbziXSDHQiJ4nQL9mfZeMcQzaoQO+St8f240VofCqVOnUOFW1gLrV7cVxVvyhvLI0EkjKgnJakwcfQLmDVmPASXxsCOG7YQcLL1iwB94lgyEI8+SAA3OOQQM6IxDwMCAOQSoo6jBG7AQmqkHNUaYEqOBlRRW+V15fBtxP9hipzrANZMaLFtbm+HNh7G8eGMltOmz/gjGfbNHNNI1F8N/+ZzLsE6KjSRYZ8JGEiRHgsUI1jmvkQTJUWUxgnV2ayTBj1yCnygCkn95gaR5mD2FVe0wAvhItjc8INEe+TUe55R/wJPyvj1w0BXBnQOzBzwgMRcYB720svdesz73WNUJE/I4RTlsMR3vMLeXFh0O1EPGaCIfb2jgvfPZO1EJb3ui8dI9WhORJgrPoaEAcQHNrHqD1CC9e0CYhnuhjBMv0u9hkscey0TmzgGENKIEZi4FJjpyQCLT5jiU0qLy6Y5kRYtO8iSdpfbalzdC9dgjcFdEB3vvlOUMPgqAEsrIh4m36g76kcvNZrPTuxV0ixglIcGagrtTZsHHSijoxwYjiWacc2+8Rc7yHY92m66xOIdtAC9kwfWQyAuleZZcFprW13bJGVAnhc0d1b3ojzuEd+30hH8XMsT70ztRwYszMA2l0KPUzVIXF0pjlFEiKykh8oy8J9Q2eSneobOhnbmQPsIQ42HboErfk4MklInotPaPQEUB0oBjM12TbPy4E5VHVtSWEk2y4HBHhSM39HVWHlmtKhGryZYwjO2KcE+/R3lkGt7xu8QjAUXxD2DT4q4Va3jkSTcOnjcHHT6Q8Y73liWaBPSv/Hr90FfIKIYaAWVYPSZ0J8bZG1L2YNUgS9C7dXKg26N/pPeSDyT9k1qyNIxvEccUy3+GN0wMU6HUm8jrzEnpKzsdaQI6EdQZBLjnoAEC7KPQAIUuLlmT4PA5c9Anp0ZEEgY4Or02AaDwhHDIAM1FIHLlBvAfQ04PKJ1oZVCZXNk2ubJtWK0iGUIww8gTTpMpnIYZHwCXqWnmNGtTczJPf6ggjiV+pIibBSYKd5t1F6bBm93ywMEmYRgjDTpQYUVBLNka57Ggm6maBOUKtkmIyta55CzOXEqfHSa1CmgU78vK4I9jS5bU+qFR/C69SK08GsXv0ovUmqVR/C69SK12GsXn9KI/rGbdOWc8Yu0KCfkhgtiad7II2eJTF1pP4+2n2qqIQYFAdhWyVFekxTxIl6VHPIXvUAk1T41KjJL8IWx6jE2UZXhzfK9zmznjw9fDG4IeKT6yF0VviN+hLzarL3fbFL5CxZJqeGnYV/BgUpH6k3mPdBQ0o4JDejVFl/76t7uuwbwrWrEM1WizWLy2Yw04PS4MIlHkkmes8Nu1JbcQt16k9OmBa8N0kcJGXTlj6eIMCRso9tkK5mQ7bAU9XaYen5Fk6PEZy356fEaaJwF5+vceUJ+xIEaSqqO4FeYMGgZFnuz/D/MYIPuY5jTDCvfJdpiB9Tt+Atnw8IJEleMjHf0uNGu/381aXgHZy3ZnIIE/Zo4jigesMeLu3AvaOZ7OgVadrzUsiJI1aEhrCYaeCUsw8pibqVU1JgWkyM7AgK1vtCJw02Ysb9bXn2djyPTCdvJNqqv8+ts6hDIZirdLTFC8MWGC4i0AExR/bZ+g+Lv2BE1Nrx6AriMrk6HrmMpk6DqaMhX6gy5NP+jS9IMuTT/o0vSDLk0/6NL0gy5NP+jS9IMuTT/o0vSTLk0/6dL0ky5NP+nS9JMuTT/p0vSTLk0/6dL0ky5NP8nS9PKZLE0vn8nS9PKZLE0vn8nS9PKZLE0vn8nS9PKZLE0vn8nS9PKZLE0vn+nSFHh6kgylS9MXujR9oUvTF7o0faFL05e1NCUczCc0XaC+0AXqy1qgEH3+upapBNvJhKaL1Ve6WH1dixWmz4jk2wH0WrgwaETu7gCaLl9f1/KFaXgtYgj0S5aIvaxFDIOmS9nLWsoQ6+plLWUYNF2FvdA3xBe6fL3Q9dcLfUN8pW+Ir3TN9UqXqVf6hvhK3xBf6dL0SpemV7o0vWZpq9esDfHbWqYw6LVYYdBryUKM+xtduL7RhesbXbi+0YXrG124vic/n15jA6abZCzdPPBCv7y+0m9lr/Rb2Sv9pPIaOGukTvFrYLdPx9I/7St95b1+T5/k6zy+Ap9wsE+P3idox9R1WRX3RTWqLDN99T91JaQnnPCZaQ4nnmwCcn0GdTSgL32M5hCYkuxDCWBxHsg5gS8GQGu7h2Kcl3O0z9VDSF8T4EAnsFlx4FPYrCgo8LGCLEF+iIlGszOMUpdMdk5R1fL3McdUcijC8LS9ZvRq8kEKXPKKicJ9bMMK1bQ12OR3lg/woWwKFd5qMOmv0CdwlsIy7CRkBZq1OlR6JK6osKmolcG/eW+/tsxCcZaqVhWiPAA50zZ502h56RN84tbAkDIGM7A+xwxIq1WbHFqwTGqDrE3XqloUN/auhvoOU5FfdhaguS7OqeFmM6b3DnxevFCJsDRwqVWbqqAesbh2NWIT9r+OlwvbmOQJJ7uGuf+LeJMzQ/v4SiISWmQQYtuHzho7vmlpeBFVv3Gh9izKfnlnRWesakCzS82DWuxJVzwJDZtRx2nE5xRzGjnwZYY8MjPJ+ZqDoAIdR+OT8uzRmwBTVp926MwOvaDvTCsOaj/Iicba/j1Pu1zyPtbMqxH3H+Si82xGdbqAXKJlr9BnyRgL7jg5sQxjonfjTkDoQZasZ0l5nnznSfYwZfQ9CL9xaHHpo5Qt6FYLg8yi5p+8as54izkUDyCfjiW2Uz1Fj/kfCm6hUsH08E854rXJtocLH0PBxVAc/sZk92h/LT/CmV8E5jX8CK8qQ5jsjJwZC4qTqAGXBGYBr7msOlRY8hKOfny/gKNzMjygaRXLgyT4q+SSxbRKGkA/fl+woNJLLJGExBIjQTBT8bboCFmoZuN2mojGlYJdwc2Zt+5/URe3GEnsldzTLzcVeyXpHreAhhSrwSQtwR35Efsdj41kmt6eNNXZSpE/+4SmffYJnvPZVyTkz95q1YIOPMZ4ut7eFdzFb9y5VjSRkmJBklEEKSTTjOT0ZCKh9kRzguLS0CgLxMVzBxNWj5aBmrLPJGZ4Q+Qawxk65wSko5khnVDuJXu2Sh48pSEm1BzhfmcbjxlsVsIeRdXn+cNXKWkNdKViV6F9xkSrVc18I4/4iNqZwfMukzOi9NPxDBQ8YUZFVgM3yYlG+x8zC5iSuRpqbsUFGGgdOHTHapr156wSkNXn7zDc3qPBHQuB8aNRdWfxvR3gUslbM2SjiyQW2PgWIRLvxBDvHeqjDkSz9EHDZQ1bSzNERSohFCKqtOqw+YRDPJhiUyF8fg9Md8ye4Qto/2jXJ5PUWlx4jXn+OvIcq3ahgbjBGRbmPD6ehJd/8MLdXbOZSFX/Yyw429acpdVw2WdUg9koh+LCa1EKe/P3yWTD/cjQux3SkwEscTRBffDkoSunTTyqrsfUz08jPrYncqBSmhWgbd83ysiUPAnvQGE1XGB9tn2m/mfBW6Zrpjze0SiSZ6PKy4098vhaDcebxb3oX6BrId+GKrqx0hpPZ3dFhPIXxliwx50Zi4ZC6dIwDbXgR1EjHtxPPN5Y7c2KpLnNOSlGSfoyKntwIEuxRNhomnpFkj+s7/sNi+QpCBFRXVcTl6jAWHbm5twXu8OKoC87Uwwhn0ydHiYtOso82g1ZzySOSxuWePKj7c+44/BTvH1POJfxEyWsjcTPVsYjw14LZcmLTYsSoclx9kUo0e6/JQ95yvNmZDAzEQoMPeHZuVuWIyoOLLnoH0artoUyNx5lkw7vrH6k26tbe/WH7MOPEGX0yFi3t55OokDFCo54qHzFrKNWvMwLnXlg9Pf7Ew9Yd3Hw8Mk3iaM934zwHqW8vnRS7Do1F31CayCQhb61FkpStO+dhXiJHYDBb/EMas+gJUwPdmiH0onlSfXJpwpvItosnougIV3MxurJhW2ZsRp4Q9LeU1R/lj2gZyFVwh8pTkLDldc1rl7gDG0sW73Oot2ce7KB4silxPh4JpKaV4ZxWTLDm9anS0RPibst88L7BKxoQHWEa09PYq0Wxy6UyjiNoN+nMgxPPYvQuHKjS/RoJNp8NZXSjTtR3nhAltPM5HWpr1C9B0f+iLYegyVSRJxlKV95SEaa07q5GYbLtbqgEGWdu97uVX4pHfAKwylTb+0dK4/nM5mWF6GaZClEUENhx/d69FPrkiz3UL5kyz5Sz+iUZryu3NXsjEjwvSTySbj3UQ9j0fl9WPJURM+SqSR6knwC+krvyz35uCdMvb8Jr3lfjhlVGnqFHsMvIiGjiX1Qp5MBgq6qNLA3SH1ZNqK8BXWwpqrOtgEt+WT4nqHXJP4pJb7nnmEosS0MaJJFtydppwtBZBpxJMEjZwJFP5PDM7GGY2/hAY7AvCZwTOo0qyePLOi+iKJp+xdeor2s03Y8EbAFOjm5wwLtfrnRgacSuiZBPAddk5BnYUTjZ6Ea0HigVBrKuesww+Ixsg32wMGXuRddrjlvFnzHoAavjf24SRMXIssJIQoRUowEQZ6gqZBERYuuCDH12sYXR9jhQ8afGOO5DDRcWlGgjwohsk4KzMvlO9XuptqRkmofXeEpxtqRpOlqu5OddaTMNgCPRLtZgEdCigl4ODAxW7S0c+uQe0OhY00f4bSY07uUZKrFkYaqeSzoBkrBLdANnhlv4WcUOc/hZzRZlvU7fjS7UiOfxB7xj2KP8MfZiR71kjCA32FS8AkLZmADBTNt6nu2GXDTv/Ecbdcmq+edvUTeHD5FXl6ZOBNavLwy5fUIWhk5qFadBc1M4O3LpnBdvvvHRdKXYeoXD7r92YaHHXbNdQWUFHZ3gtlpnG5x2+lQv1PoQYCGlKoryLfT6DIyiD3wGahiF4IU/FKr+UsPXrUFqajWrCAZzQNYCwms4LokzI66gmaFYLUI1eJ7Fhjd8I/JtUuyjjqCPVxKDzxkm/GCh241XtDQKbL8wg3/EE3XZG6NI8uwDHdgIuy0Q+U11pTfWHGG4s10az/H0+U7sphC4a0dDVjQWSEDDVitGFwK0rumO5piq2mEzFynQu7k21kx0dfqkiljtS6JyCR7LLeBZYflNjBRlpuSwio9PQe/8PquV6nTE+CcSQCVta3N4L42lhdvrIQWP1OPJLT5fmQh36BiTIEaQmSudbZtMhWi1sYzKkThjWdU69zcZCpEYaFnVOvc6mSqdV55MhWi6MKCKsvksGDINTrQM9hFGMjmnDUPzaLzwJP1wOmBaw/1dGej7C8PDJQDjaO4tLI3aQzPZ6pOmDMl+vFuitdwf/x94oUN5ArYFmvJLS0m1AGH/M0+NHUQGqaB1+vDbgJZq3zCDEL/PZJmbHZwyvfMLpFwJ6KVSpjwOSUT7iT7lD4Y+bJrCISIkLUE4hSU742rLZDeo9wX56haA0gqIk2mmXA3+2C2EO9lEdyuLfB8DeRXGdikou33mVUHIjTY6gMRGlwVghVJTjWCiYxalWBGQNSZhCoFI5ZarWDC5wYJZzvw0fUIHoDYugSPcFJ9gokElah/gSIk7A/gCYn7AyzIBP5BBnQi/zvLHgn9l2w7PbLOSfC/4NghuGGPhP+PXHQ/+h4FAOJcGdpkt4IAzxh36eOOnduxV/mbwg6FA5Zc2eaXfQsJrFkzCwo8EGYWFnjGRjtBZhca2CDK6NEua2iX1bPPutlnxeyRq4RWoGBCZxYquPOQc7NOFHk5WkM0+6xPSs7WEDhoIXn6hcgZVkc4MdPqI5wW/apPxcu3b5/ZH8K6i3GGfWfFRLbuPDDRbTva3gMmKeYvhzfw3vmchJFl91Q+Nl7qP2l+RDaqRHd9xNIu0yNac1mqhvpaeHooHH/1mjIKx9C/oc7sRh88QHqvPHHk9mHYl3aicYPBPh64c6i2qylPRO8MWh1jqRuf6b2JhPC2cZe37Otn7OhueKWUm9NjDCocjJNlbB94rmgyQx3755V9wtRB56G30z0euk8c9NbF+DiwN4yOhYF3ohvfoWbTtW92x855tty+EfcLMD7onBSzZxpe1zl+vNyb1BxPSHO6hhMDAHIvcnN81jgyM7XucWvayRJgdJGVh85YniFXlmdOguWy5Lr09drGwhx5+ekDlLmpy8zNWGi8EyvbsWuLlvHiLePePjBIda2hrIa3wJQbsyMar7lH0tNYxzC/4dJE2L+rFDJzSrLe4jkC2nsmh2zNObPz2uRYchzDs1tcAkOvkOjf0Nxyv2Gnq3wGtx68u4Nyl3UcVyFLd48qeE2wlswZSI6ueaw1MYvGfVPJtXrOtqdcq6XtpIQ6yw3dlXvoG8cyvsGgbgmOI2vBdNI7FqhPyru2zXkv5Wth0U7CPmWQD3T0uRG9JzZcNvPJEC5C266Pvr9v/P59OeVdSpyNOMwHvn1Yuo40Nqpt/aLZidbxq2uyOHO/ZPGGPQ83hjLYq22JZ8p8Q2++gTfbsEs16OYYcvEGXIrhlmawzTPU0g20WYbZHIMs1RCbaYDNMbzSDa5ZhlaKgTXLsEo3qOYZUrMNqFTDaY7BNMdQijeQ7mQY3ckguo8hdB8DKNHwORbJwKIIZlIj6jfG2yFqByElVLMq1ZxKNKNmm0+pZlOiuTTbTJpjHs00iwbhJnJLirMQjarG1L4iADOgBa9jB7H44Ce8qCS3ncYsvRE7VXyUyjJ+CplBkCRHOClaV3BpiUbcUFDB50RFtipa1MM7j6kFSFpfVdPqQePh7sAOrY5/QBF5tbTZ4QEYzt27iWy7Yy0K9ga3jV05lSGiEzbhQ+XyyGeKL6oBh9yMKK4NsktjR1cG3oUREzOU46LkNnDKpjsssh0VdAdFnmOC7pAgOyLwDgi644HucKA7GsgOBrpjge5QyHEk0B0IZMeBhaat3X6JMRHak3VCXgNOp3pY0OoTh4gGjOXNOhY/Mvvj7+/F8u9JW35BXtkznCnjARBjhst3vOzgcMlwtOQ5WHIcK2SHCtGRQnSgEBwnDoJDZLtYLqK1UHOfmCLZhILyx0Qb3scLk+d9SfK6xEaA9bVcmivXgfKJ3FrEtjGwrBN7k2gsSN5nwbcRpRc4+YaxgdeuaVjcKl4SjG85kj/aAj2ztxA6P7N+0NGkwV8+nOwN6fG0CpwdYsP3yxz76gXvHLxybtwhtRF/8sEq7JOhprZIdCoSnIlkJ+KHP0jNt8Ax/41oWaI8rTlw4tDjB0miNp8kiP8fAAD//4qByOE="
+	return "eJy8fU+P7Lax7/58CiFZvI1tzL8zPuPFA4KXBM+L5OUhBu7dEWypWk2PRGpIqnvan/6iKEqt7pa6VUWdOIaDM2d+PxaLxRJZLBb/vPCfL3/Oftspl21VBZlyWQkarPRQ/JT91WTa+Kw2hdoef/rSI+798+XH7B2Ov2Qa/LYyhy9Z5pWv4JfsT/8E//fKHP70JcsKcLlVjVdG/5L97y9ZlmV/V1AVLttaU2fxNzOpi+zXf/391//OkMr99CXLtuHXfgmQHzMtaxg3hf/zxwZ+yUpr2ib+ZKK1uy3+FH9t3N64TWxl+GHf6DscD8YWo5/PNI3//raDAMvMdmjeQm5sEdWzgSLbHDOP4wN70P6nL1diwGdjrAc7Yr7u/x1B/gFeFtLLzEKFQ595k/kdDNxZAXuVQ+Z30p8MpJOrE7hX1pTCxtLKorDg3Nnfzevujtj479+iiP/LoREcjH3v28iUzn791y/419nW2FqOtXcmUwnai1uSqYYmlAtDGXgHaSygJFCgWKhb1HdpZZ3tQBZgZ2RrrPEmN9V6Cgui9bRZ63oTG432YQc6Ux69Ac7/3jSN7abiD6jODD5l3VSQOZyYM9I7+GhB5yB0W2/ATnaiMrqk9eDfkTXrWHH2oPid0nu1zklkWpuDUMVKsvy/jQO7l/jXWWFqiTb3V5w+h53Kd+MZkm0A6d2cYO1GBINZT7ZOIa7d/BiIs1//+kM33t5khXJe6bJVbpfVbeVVGMqT2brMdGbqZA1x+s9I7lUNzsv6co50YhfSA03s31QNwe8jFAe3s8uZ1tsG2xe1qirlVlLc/zWHgDqfFY01OTiX7aTLNgA6s63WSpc/4Izu2ofc6GJuhPdgnTJ62sFoDyVYmpj9vIzEYWxHbfftqq1QuoDPL3cUc6O1XxHfzzOlPditzOH8IyV1tJ/ctPgbpy/DlESTH0+CQH/5518ytf0tfjzPxJpu0DUARUqLQ68DE475RnmXNWCzbtyn2y2Uhdyfjzu/7aJtKvjEFRn8kD0EP9zqd20O+ofsMfxx21bVj92v/ZA9hR/tZLUdfvQcfqR0mGEv4Q+m9dOyy6JWWjgvfevE2Vqi68HGmAqkXtaJ/9qB34G9MCHlstCKct5Kr/ZQHbO2mRbHNGC/uzTYSHDnsrohitLC5B68SxjV34yX1egb1hHirAG1h6J3wIN0s5K0uXReNO9J0vxzkKPVCgmzRubvPIHCB2VdoQbKBLE21shiXbEGygSxCuVyaYt1BFJ6Y1pdDOJEcoo8YK2x30eag/K7rONfLk90cCKsWb+jXFJnsaksuFXXNnHF3q+Wp0U0rf9uzsBbqV2tvF88gCjNd3YIPKH+I06BJ9p/xDHwRFvVOZjWp3kHlGhF93AlD90/NNbUyuWtaZ3AVdF3WhUonY1ayrClCXkK45/DAsUJWalS16BX0NffcNmkwWdbK2sYfWGCtoaGot5ui7XN3fcWKEiZ5TvI34f9/yLZnNJlBSI3uJNSRovAtKag4ykot7ing0+Z++qYGQ3Z0PBtMfvd8n9W0NrYEH7TFFHdBwgPbgUbxD25C/Px3///bxlydkOa1eCcLCE7yHHk+KZUBWzBWihE7KXDjqypvcPOOMi2yjrfazK0EYQsoJJHjHlBLluHOoWshkK1dfjrTeuOt8XHAOlp7NOVKk+jGQX0kOPAY0O4zZRnnbgtHHxiiELtV5LwUrPaDBr1fqTEEKSJLZ+6c2e+BwerZSVqmfe28B3c5Q2Zpc56IbJ//OX/YAwOjcN2tn1b+lxaq3A/CtrBmhMsEmeBOMPAgvK9bVTGeVycatiDzaRzYO9Ot6AG4Y0RKM138fvhWABND3ApgZ2o5aeq2zp+DJz6Awi2EHm/jykMQq9kB+5Yb0y1lgEEi9zLShUhkJ117GHse8EnxJEbZ6rWRzP8chWCNe2mgglYdzIhGmMqsVPlTvidBbcz1Uyg7DZDZQ4JBNaLWjaN0mWqKCOm1UTCyFPrwDJl2+ai30N+mQv93kQJJPoyd/5zDdVGH2v1RwhkiW0lS0do9wzsId9p9dECgaBpKpV3bW9apzQ496OFCvZS57BUZyOSXHoojT1StTCiGM08HkE4TU0QYOd9I1qrgtvAw5f8ekjczli/hMaB7c50OBSq4KD4PXftZo0R9FZutyr/Ma+kc0uNyHqRVwq3YfFwWHSnRd3nKZVF6RVYHIMh7pcpODyUEbikFBZcY7QDOlzDQeRG6+5Ag47ntzwgxU45b/CAV2xaVMLjilxPK3I9r8j1siLX1xW5Xlfk+pnBtWADeQMf0JJsyomOJNGDJLiOXvdcyS/wSifhabJjkgXYVN1Psii9AgurN6kDMk2j9Bo0nA7Rv0oej0CT9TDJovQKLDQtdHKM3Epah66JlF6HiNAt56DeVFBgjLMM8ezwQV4KbwsVEWCXYvZgZQldSABjIXvsgqphIX5TNqIAzG7qlp/SXWef3cNr+MSEwN9ljj1mM+xMI1Szf5nILYxbqOY++pWMbizs06SPWXIcaAheKH8M+xxYutPYKMwivJEsM4vTBUPFSsfoc9jk43+ucXPb47D/mNpETeuk+3Xatidg1Lbf+YAulb7IWbypFYzAwpnjOCWnXXFglt0shbEiB+s7WYDYvrH0oTmDvjKh7KE1dW00Bl0a7DRQhtnorSrwrEtUsIfqWs0zQTjcT3HUpHVCN/stHK4gRdHauFGfsZDZHvcs4w8MRWPaW5m/L4dgSFRsjh5IH6GAqpR+j6FwR3MxV3D1ByzX9SV6Mu/xNrpLo3TCQqXkRlXKH2dPua8ZoPJSBOdK0tnoA0o2zvHXN4AbC1v1mYIVFejS7xaP2TnLa4r4r1GEFGyC+HggMif97AdkTMD1EoUq8eh4J91O7GXVwlLTweCmzuOyT5jt2WpMNbO94dHtX9YmfE0m7C8ZNOsx7V9W5CL3UDshW78zVmHweg+LDVk7kdPWPoWe/OrOm6l2ov4U8JnvpC6JDdWfYXKDhamDiZttalcQV3XYM/0pulsiFA023nLa+mBALPUDpZ34aMEeh40zpWPWkj/G2glnpIDPRlkgmC+CaPvnHmVhi6d9ZJS3RyLGgVWyooFqhmE4Iy0HZvfUT0iPssrYqWXLXaSXtgRGiwdQ5Y6I856ie//pBS7uCDo0/vFD5K3zpgYrClCEZdwlNnWBcM43O0Bz0/AcHr8sa0iyr+TkHmJ+5AKeoUxenzHSqfCLrJ3H0+wpWWeMZhq/khheljQGuqKtaRooRMiMeeoy3kW3vSDtLKZouoAnhyZVjNT2u5h3ggCRgCGB8/2Z+K37fVc4KHEVOc5550oQmYZEaSZseslxE9vsjk7lsjqR0PDxRkNy1/d2u3j+g87tsfGY94opNaYy5XG5myTHACNgUrdzkJguJ7ob6cRN64Bu5LEyspiDzzqYgaAbDj58qsvzsLCSZJ5vdDeT6VbYwfqUMHF9M/i2pju4y30jnLcga5Ijj/BRXCDKQWsfz4QwrNSFpzhzqKeJOeQpFFw3HglYUe4Be3N3rprbyFcekhvV6QgwyzJeZ58ynTlddSmyvcDCgqzqpdraKgsHWVUi1DEhoPD6Ai7mhTZaQN34Y++2h9MiR6O7IqKFdDuZInQjtQa73I+HXEwhdSFcKJ9hl+s/BHHxGHffnU6bljDuHdh7qzatB0cEks/lOlR/sFCr3Bra6cIlwY0DrBsEoIu4NOOKgAw3sdPHZyMs+ehtwGqpuc1akI44WghjtuaOeCGfckgfoDTLV0XFtft3OOISGb27sZQ2K7mBKnhpCirMbHSxKG23ONjLis/gGpkrXZIIAA9g+080fXdyTsLdZJ2zRI+ZRGOskFWJwaRdTTQC5zG1Js0bdBxcf9CjeR6hQzN9QgfmA3kT3MtNBWJbtW7Xffbpw95RNCDfaVhjD9IWOAkxD6V1i11hvw2YvjRxD4UpAHEdOfW1nBG2R5vt1lGinduD2FQyf8dr2Q5yt7S9kMW9VWWLtzApZ3vbg8jxzpPNhSsXj8UhDv50YsQSEGGRdBCYuzW9yLylyAElZOUZjeEpYk5YBx4EluURpgFNs+wxEG8jcXB2apszi6vlp3B4uXN5ovT2IGxbAUkfrq1raY9YnIWqkT+MBtFIRVlNj1GThyHTuLIym9EeKOnOWmlBvMNx4W+Hk/h4Km9a37R+ecg7YINDnzl+nJ0aAam08kriZ8uS/EUHboaQ0IyDWwae3KXdgHY6EhaPhTGrmI9VmoIdFjysli/Ry9vGC2a50R70TAxsdvKFS2V9FGYuGHUb3W04RLOz0gEZ+9GGVBPjPBNag9+ZggmeOWS8DY51yPKpmiPzk6m/vSemb+/NNqnyGge3COkbL0uXJWeoVxIKf+NGg7M9vAa/csDkXvao5b0sI2o5QBtcL41y8hgBx54lHn/1l/MTabjhz54mFR+3dgwC3VXmxSAPVBAC8EHBpJGZIuHc950iUgXBfqfwk0dzJApaqsYUQ/cl2kC5+GsyzwK6SOBwUEtNufw8RdJq5R1Fp6sdefZU1BOfK9y067kNHlVQS5WffYCq9EonqErTj1DjMlT4vKHtzzsgXg24VajvBox2te00ykw31MOpM9+DxVpGeMubfLyjdMrxjmpEuLUSkla7HBcCtFDbDtYtORqjtCfAWefVJ1y0YWIqokq55HUBph0CjvZEM12eGeETjt3lBsvQFJPpsvMgB7lwjVou5s0MgHmUrwhCEQ/dcUUq1I7Qwv5FmIZyuzw0YU3rsaxUvtAU9q8YgwKN0Zlo0IvbG31SFnfrU0nMHTpgKEAVLBjRq2GLXQF6aoMRxWkPM961O4BlAIMjBMdAhlRqBq7fjnx6Kth1F0QZGnJe5HjPk4vFtYI9Tm+gl8OZrVfSK99OtLytjJw1JwQaXfKQFkqcosz+RrSmo2PemchVswPLBOMJ6ow7nl92jwkml7u32w6xoZ1x5A3QAG6tYsE4ARpEq9op4doNrvmmbk3fRlc/C9k0Uz5uxn2PQAwNYS0gnS8NPgcI3ivnngUPBOxj4MDgbM50WIjkOqyA5TusMZzZOsthYbs8h4VIvsMaoRndxep60s/EHZu7oFcyiOabKowW9wtoiv2ehfzo5n8Gp++3I5w7fSfgeHccd3sfrbTgODyJvejgKWI46I/tSbjz3dP0NaCZdekkAfXcf5KElsRYYUUJfHaC0HdTCtMs7qg5gBW5EpWq1XXf5soh1NK+L5QHj6U3aiNAe6sWjzyiImKo6kmBxtxrUiYNtjkkyzGyhy7w5PyhMzw9g+gMzoDG9BYnGtAhJQcPC0NdoaWrD6TpzZ1k5qPUBYadnJZyBBSWaBK8GEaPjt4pgYEQCqlB6pjJvzwrJIDiiBCSSfoz5rr4KsILAVOVqGbl7LAuNw0sB3mwrGz3Grw1Avb5FGR2cXBC+SNBSoUVUhvf2r7uGPWUJDDg2f+np5cLGoNp6zVEhrtn5PRaRNamaCtqeAaBZvM75MwzvhG+T9YDS9FUBLOldkft5ScLGlJvxGbq8Ou+wB2YVovtCl7KtgQuuPfSl/B5733FMF9RQzUL8N2y03k7lU69VIVGFWxsmN1e5e+Oq8RWO1VqKAh4LMB8w9bngJq52FFanKU705c7lwz0Bc85A2PJc05ABqcsApROXQQoTV4EmI2qIASwCJ6pA9VOuYLigY1W3uAUHO5GnBa2VFVPcI2shsrWVC7e+XAeK5MV0CzX+iWYNmaX6LitWzyAM/jHh1SGp1SC51SCl1SCr6kEr6kEP6cSfEsleCMRsM6Xz5C8E+ZA4U0TewCfDRNJPpG/xr+m4Fl13y44zsaPyUH5BlwgKRsYhO4b3Z1ei9Hj4mCXc2BaT1Q5ZffSkNOBuqb6bCIM34n5l+pvWzwS9ZvuPppIDFEEYeLrPlZ48w56afNDQpiF00MZW5kv34dpGe+vLNUdApR2qgDh9rkqlnc0IollcxBlrCpDuSNd8rKTAknruVKHB5JIEgcEbYuIDX20xmNxs+4lrZmBmW8VF/ozm5ubzQ73VsgtUqIcGrzLJa4yc9m/hEK+bNCTWCGlDCe1RC2f8ORj02ss7cB2Aq90Lm0s5EXyPOdcHuoGz3OTOoRZtam9GpxQWIS3zXCFfxUywv3TE1Eu8x0IC4WyvdWNShfnxlKc0UJWVkHkEXlHaL2nQ0dd20mlQ3oi5YTtBpUqqFP9jISjiNZa1ESlcnwNEdlcWwOdqNiIvPKcbJIzDlwqbKTjz7NiIypTKj2zXlnQjaFo0KQId8ej2OB1K/pX4pKA4/gj2DW0bcU1fOZKNw2epoOWnsh4wofwGM8CQoJSdDgh4hG9DOhi+nr+AnHmOIMPX4XVgsZE67WEjHRryMe6L3lB0l2pZVtDfxexL7H8x/QHk8KUG/Ou0oTZGnsQ2w3PQAeCKoGAdh10goB6KXSCwub7JCUgPkUHXXFqm9SH1lZpeEY65ATNXskk/Ges6YFFbYxNoHKptu1SbduJyuSps9wlGqdLNE4nHPh0TzOmeeLzdIsKZl/mlxTzYYGBQuUSNyv9aXYj/Y7TjZ6GnKhwRTFsm7gfpEsevFXWV5ue/NpSBTsjnPryLlQ7lvzXGKwtHlkyjfDhWRmXyPKUil9FiudU/CpSvKTiV5Hiayo+RYpusZq05xzxqGbROcQktpKtzqdufyydaF1XQvzUejOTsssgOyhdmAMxYj5JN7mCJ1EEgQqo5NKsxFmS35VfnmMzyxLvHJ/euU3UeBw9nyqX/UyeFIHHrSCLT5LlFJuarcK/UMPxpmH3gofQhiVP4j4Su8QLKiAyuCm+9Vc/x2Eh3iu6Yomv0SaxBG8nakA/rlzNHdT+hd+2wTyf+ejFEpkuuG6ELpawcWdOVG6KhUWKdT4FY7IVPgUdXaIfH5Ek+PERy3p+fESaZgFp/veUUJ8wIXqSslUFPZA8ZsDLz8GRuzQeB+wzpjFNnOGh2A6mv6xBFi9esKhSzkj7c5fJxdVd5YavWYM5+9xTthMDC/w5Ojia8lJ3GZzqv2ozcY67OrCmxc+GVUzLih7S++2Gj2UEedzRVabsn2bg2E5koL5vdEWAE9J5WTfkPiSewrb6XZuDfvr5gQ995EOf+NBnPvSFD/3Kh77yoT/zod/40Dc29Bvfmr7xrekb35q+8a3pG9+avvGt6Rvfmr7xrekb35q+8a3pjW9Nb3xreuNb0xvfmt741vTGt6Y3vjW98a3pjW9Nb2xren5gW9PzA9uanh/Y1vT8wLam5we2NT0/sK3p+YFtTc8PbGt6fmBb0/MD35oeH/hQvjU98q3pkW9Nj3xreuRb0+MrZ2E+oPkG9cg3qMe3FJmfHjixkwHNN6snvlk9PSfJ/JKE/pqEfk1C8+3r6VtSw28p6OckE3t+TELzrez5OWVePb8kofku7PmVD+Xb1zPffz2/saEvD3wo33O98G3q5ZkPfeFD+db0wremF741vSR5q5e3lKn39SEJ/ZiEfkrp91e+cX3lG9dXvnF95RvXV75xvT7TI6c99lsClh8eeOZvXl/4u7IX/q7shb9SeXl6Y6v45fkpAcsf2hf+zHt5Xa7kwzi/gl5wsKutHupZk951uXrcl9SowVof4fU/c4ilN1j4iTqFLJ5kgqgEOoPZ4NFTl6MZE1NUwcfSTiDHBOExAF7bHZRyeDlG48ETp3zNBAe5gM0VB72EzRUFB96/IMuwH2ah0eQKo9wpk1xT1DTyo68x5RYbXHe1vRL81+QnKV55FOj3nMgNPk3uF9+zvIDHZ1O48MaCW34LfQBPOJ3lTsOJrdIlWNHYqadH5h0VtRS1cfQ7780T3vDOd9pUpjwScNxK2+yPRiOLUOCTNgdiyRhKxwIgVPQ0zZHYDl4UKf1uplb03AamMZXKj+LDxPcdhkd+xU6BlTbfHZcq6cT00UILM0+ELQMX1jSOiaW1awllksNvzz8XNrtLHOF0Wwv8o2OhQ34lEwkNMQmx6VJnseZNZxG1zGfd77xRBxbjHz9E3jpvarBiX8lJL3ZHlEDCwya849TjUx5z6jnozwwF5Nmym+H3rjgYLhA56lxeMjGlmWBKkmkFYVaQgv9luuLgysEuNBbgWjbnUz7kmgU3gv8hTrrA5kxrc0glOpeKvJacY3nlscQ+8cU4ETAkSLL1JCtPs+80y44q43+D6B8Oq/ZdlrIH21jliFXUwpVXi1dEKYviCArlWOa+VHfRff2HXHoozWR5+Lsc8e6PKojdxWfbwoOL1lPmeUTjXklsYCf3inIbvoeXpWMoO6FmxhnFFuvGkorAnMErqctWltzW6Zfvz+DkmgwXaN6L5ZMk9K3kOYtrDKb3Uy+/n7GQykucIxmFJXqCyUrFt01H6dzUN3anC9HxOg8X7naywf8nbdzmSOZuyd0dOaVTfA9OoFhidbJIi2oWYF/p2JlK07eVZlpfGvawD2jesA/wlGG/ImEPe2NNA9Yf6fPtw8DJ/Pov19InxSZJlOaTDBqBzxVIuJJY6emKtFAbD8zJcwIzZo/VOd1i4h0ibIwW6BwTsJZmjrVCOT3Zc+vJg7s0zIKaPRzv3A3LDDF6wp5E1dX5u9GNuR2qg7Yw4qAs3rzDGGklQiOX+Bm3M4KnbSZHRIrT+uQKc9Zk8VUpoxc20/2ywAdzlo+JBXyGcQ8CrJ1YdM+9adats/Agm/QJOsFo3x4LuCwEITfOVK2nSxvh2uhjHavRzRQWuDEWUyThEEN9tMAgGpUPips16luaU1SsJ4SmiEpr2mYFgVSRhk+XAItIp2p4DzZc2sUPq7QYHqgo1197nk3ZnAWipKMFFsY8IZ9EFr/LHPf7yUysV//nWF7ZLA0+ybtKr2LYKIViLytV4Au7uJ+EpV+rniGcqk3Z/yIcz1AvTvLIL6cNPKaqYqbT/YyP24qMVMaKHCx+93PpOT0zeosrhxzjLHuoyO5/lLyF2RB9HW/B7VVabeyeBwswh4o/pLjoGbpS+j2+ojv3tMZd7V4Rkc4L51ioy50RC9bAsAXWGKmU3KiKcOF+4AnB6hBWZOk2ZaU4S9I9o7IGx1y48a5Wzth4nvqKJL1br+t1i3VSMEXEPboauFSJkdGddLvusTuqCYZnZ/KY8okn2GPpbrwdmEa7f/lexK+rEcevu2rWZ9y/fAdOds/P8ycKUOSZccmw1kQ556WWRZmhiRpbUzLy8d85D1vlaRqJYSbGA0N3eFYWy0vCiwPnXPyBsaZpoEjNR7lJRz+svqRbS6y15GGf4c8QJUjkPK6at1uVk3IFezyU6J3FxhpZpKXOXDCi5dmtzCERPr3yXcTR7I5OhROlNFlarVZVzd5uyR4IdG6PjYeCle17YmFuYiNwcizuQf0OrIbhwg5vUTqwnCfq0h3eQHTz8VwCzZRK7sPj68m5x/LyFmTN8t5DVn9SPKBjYb2E31NslYWDrKqZR+LuDO5WWUwKu7ydRcu0PSeLFBss1m3pKgkhbSGxtKLE+w6WoRLcLWMsY9893G9az+hMIPHeqk07Vcp4GUH34UwIPIWdf6Es7bnRc3QfJLp5a2qJGCeitP5g4cxeM2kiIdM6HOk9unUZbCHFzGHZklGOxUhTWndHJ2i1Vs8oVFGlzrfTK78cAYLDQGeKQgwvj6czuUbmU2+SLSECDBv39/X4q9ZzstRF+TlbdNir0BkrZIUpm35HKPB9ToSXRFZyD/2j8+uwpLmIjiXRSXQk6QT8md4994SzbCY/8Q7eyu455umj63uerkf36RczKaMLZTDbrQNPt9PSgniHI7HZEEGN0VTT+qb11O4HhjCM3VVKuuSBobsJisZsWRHdjqQZHpWfUSONZHLJuYCi02S8JlbLz3QOpTkcgzu9KQmVhSyLyuumu+GFMWCqgZ2hX1lo/M0bAty10GuS1xQSthZ6NF0LZUTTgdpYKMZHhwkRj54txgPjWeZadKnhvFHynYAKgjcO/WYpboosJYVoSjpVMMxwimcyVMii4mVXTDF1Pis8jrDCQM5fMaZzOail9ionLxWmyPAtdcfR/eqhWqUvgq2p+GmPs4ykbiu/UpxV6ZUCwEqvHAFWmh8Cjgsm4fOGt27tCLwh55pewnk5pycrSXSLPQ3X8+ATk1BgFRJ+wDPhLvyIIuU6/IgmKbJ+wkfrZmc+qTXyHy9IXrkk/Yp+Ril3R7fHr6AUesGCEdhBLlyj6B24eb5xH+0rhrBzdw7vIvcvQu0YLe5fhAl+xJG7uH8R1rRYEsnlROPav4bLRRpL9cTJQ25/9MGjdruStuyfDmN9hc5W4/yI2xkN/5sYaVLDiRM0rFJdk3wr9S6hgtgFn4M+BMXCn3u1sNmju7ZJKm40a5KMdwJY4Rl3Lm3B0I454GmqEpWaeovvXmJ0LT+Ho11WdBQJhpOQhIjxBQ87ZnzGw48an9HwKXrrYFlFLT9V3daJn8aeJU7DFZgYX9r48pqoi68i30H+7tqaPn17FpcberSjBg82KWWgBm+NgH0+BV0gfI/mxGpqpRPnqdIrne3USq90vnPBlDBbz4nYJGtMN6XXmm5Ks6eb0cobO1wHxxtrg1/lqmeCc2QBXFasXNUdXzsv83dRQON3qSQ8fV+yxG86Y6rPMD0+rMf1tB7V83pUL+tRfV2P6nU9qp/Xo/q2HtUbkyop5HDGcL4MZorDqmA3w8AO51zzvK7Bk3TB6YLrbOyTdc35vlwwcBY0SLFvdBfSiNdnyla5HSf78RSKt3C6/L2VePWUaNZYqI+VE4rAWL8Zu9IbjbAgq5pD1phQMIMhf0Dygs3YC84CNfmJhBMR76mEAZ/yZMKJJL44MBEM4fBNlPPmDU/CWwLzFJzxHrPEW+LsHcUEF3tPccXF31VcUTFpEsOEq8UHk414rYjg7bcF7s+BqScCeB/7SarXNCreqwMzNNTXB2ZoaK8QXJFMuC+62+G+SjAiYPpMxisFPZb7WsGAT00Sjh8x/qyNK3SO6Kx3CS7hrPcJBhJSof4zFKNg/wSeUbh/goVYwH+SgVzI/8SyRkH/c7aVLlmnFPg/41ghuWGNgv+XXPxz9DUeAJjnSvAmp5L1a0q3ysMAE4wrCreiVNGnryJW+vch7QGBM5rVHhK4Zo21IVYiPJeSvXycY3tNY4t9TRfrRJQg0SpzaJXZs868WWfGrFGrhPdAwYBOfKjgxMOuzTpQpNVonaJZZ36OCFWKNJMRkrsjxK6w2sOZlVYv4bzsV7vNn79+fRC/K48b44T4zhUTO7pzwcSP7Vh/SpicHNw7Fo94h+8W6Hx22t21jxs39e803yNrUwAXy9tM92grdWHq4SiYqP7hovD8rdclvcAwO4ZbksUIJLz7ygNHqgzxu7QSDXYG2BymaSvOFdETgzWbudKN9/zeQDK5310oQMpd9nMSliaDd0ut6RFP+PrgZLzA7hiOJjHVsbteiaIMPo/8OV3jovvAwW9d9ZcDccq7GOOHtej6e6jJdM27X1G4wJYqG/N7AS4knbNy9lyNFXriLOIs1VN3UmM8o8zpNZyZAJC6kRvjk/qRWKl1jV3TSpEAZ/OkOnTOywS78jJRCV7qAhPG8b22uEtKrE8/QXkew2fo+Og84CJR+eSDXbxEiGkv/H17ZNDmUEFRdldbWTtmJOq3uRvW1VhkGO9weSaMLFulE1UScy14ExpF4N1nQmTjdonCW5cSyfH5/V3cAobOIfHH0B1Tx7C1ZToDzoeQEAKW14+D0gXuo3JZQRoD66BrnGvNrKJx+qikRj1Hn6fUqKVvtYYq6Ri6LdbwN8jS38HgfhKQI37BmUJo7AD7SnnbNCn3pcJbWLyVcCgZFBIdQ21E7MXMs5l3urBX1reYg+NHH/6tnN6U3Rdrlo3ZzQu+dVjaltU3bmx9b8WWJ/gBm8x3WMVzMnnzztgGuHOqYLTsG+aaMj3Qmx7gTQ7scgO6KYHcPhi6fJw5gdseQzPItEAtP0CbFJhNCcgOWG6bMc6QCKeEC09YasA1KdDKCbAmBVb5AdW0QGpyAJUbOE0JmKYESgcsvbW0wOhKAdF1AqHrBEAHFqLfjY9kUFGMMKlT1Tu+Jdll7RCshBtW5YZTmWHUaxgx7MgNmzLDpdcwnrysVVzEjvadpO3VJNzN7JLmWZhBVeeq8EIgJl5ixbCZhdh85we8KrX0rQUGdnjxEW//yK0Hm0qyga3hiUIrS9Tj4oMKoSYqsVXVkC7eBUylQPNkNTXeSHD0PTC2aza/Qz5za+mmwBE4Xbv3JrJpN5XKsXjxja/yUoYZn3ATHl8unxmm+UkVccSPEedoA89DeI5rvaMM+hHGnJmRDi4KfMTkioN/YJF8UME/oEg7mOAfSLAPIugHEPyDB/6BA/+ggX3AwD9Y4B8opBwk8A8Q2AcHHuqmkn5ybzYP2no08gpoPjXAJqM+8xBVg/OybpZqv//902P5p6ItPxK37AmHKf0CkBKGSz94WeHAJeGgJe2AJeVghX2gwjxIYR6gMA5OEEJDJB+x7FXjoZKhMMXiEMr8CYoiNDzHQdTABU8aum0JPaCetezrg7TDE4OnDkvvLZlF6VVoPGjZVcH3M06vWYp95WJps/icoL/LsXjQztDRjTA7fkK/8tGszu8/KzmUx7OmgsXdbzTj1gv9cPAgpcNFaq3+kDEqHIqhLm2ReajIOExkHyJ+hoXU+BMYKfAe7EJ7uuagmUOHj5bEbX6RIf7PADV7dkg="
 }
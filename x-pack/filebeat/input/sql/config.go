@@ -0,0 +1,106 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sqlhelper "github.com/elastic/beats/v7/metricbeat/helper/sql"
+)
+
+// lastValuePlaceholder is replaced in the configured query by the driver
+// specific parameter holding the last value of the tracking column.
+const lastValuePlaceholder = ":sql_last_value"
+
+type config struct {
+	Driver  string        `config:"driver" validate:"required"`
+	DSN     string        `config:"dsn" validate:"required"`
+	Query   string        `config:"sql_query" validate:"required"`
+	Period  time.Duration `config:"period" validate:"positive,nonzero"`
+	Timeout time.Duration `config:"timeout" validate:"positive,nonzero"`
+
+	// TrackingColumn is the column whose greatest published value is
+	// persisted and passed to the next query as :sql_last_value.
+	TrackingColumn     string       `config:"tracking_column"`
+	TrackingColumnType trackingType `config:"tracking_column_type"`
+	TrackingStartValue string       `config:"tracking_start_value"`
+}
+
+// trackingType is the type of the tracking column values.
+type trackingType int
+
+const (
+	trackingNumeric trackingType = iota
+	trackingTimestamp
+)
+
+var trackingTypes = map[string]trackingType{
+	"numeric":   trackingNumeric,
+	"timestamp": trackingTimestamp,
+}
+
+// Unpack validates and unpacks the "tracking_column_type" config option.
+func (t *trackingType) Unpack(value string) error {
+	tt, ok := trackingTypes[value]
+	if !ok {
+		return fmt.Errorf("invalid tracking_column_type '%s'", value)
+	}
+	*t = tt
+	return nil
+}
+
+func (t trackingType) String() string {
+	for name, tt := range trackingTypes {
+		if tt == t {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// supportedDrivers are the database/sql drivers linked into the input.
+var supportedDrivers = map[string]bool{
+	"mysql":     true,
+	"postgres":  true,
+	"mssql":     true,
+	"sqlserver": true,
+}
+
+func defaultConfig() config {
+	return config{
+		Period:             time.Minute,
+		Timeout:            time.Minute,
+		TrackingColumnType: trackingNumeric,
+	}
+}
+
+// Validate validates the config.
+func (c *config) Validate() error {
+	if !supportedDrivers[sqlhelper.SwitchDriverName(c.Driver)] {
+		return fmt.Errorf("unsupported driver '%s'", c.Driver)
+	}
+	if c.Timeout > c.Period {
+		return errors.New("timeout must not be greater than period")
+	}
+	hasPlaceholder := strings.Contains(c.Query, lastValuePlaceholder)
+	if c.TrackingColumn == "" {
+		if hasPlaceholder {
+			return fmt.Errorf("sql_query uses %s but no tracking_column is configured", lastValuePlaceholder)
+		}
+		return nil
+	}
+	if !hasPlaceholder {
+		return fmt.Errorf("sql_query must use %s when tracking_column is configured", lastValuePlaceholder)
+	}
+	if c.TrackingStartValue != "" {
+		if _, err := parseTrackingValue(c.TrackingColumnType, c.TrackingStartValue); err != nil {
+			return fmt.Errorf("invalid tracking_start_value: %w", err)
+		}
+	}
+	return nil
+}
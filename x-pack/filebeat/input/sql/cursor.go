@@ -0,0 +1,158 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package sql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	sqlhelper "github.com/elastic/beats/v7/metricbeat/helper/sql"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+// state is the cursor persisted for a source. The last value is stored in
+// its string form so numeric values keep their full precision.
+type state struct {
+	LastValue string `struct:"last_value"`
+}
+
+// timestampLayouts are the layouts accepted for timestamp tracking values.
+// The helper reports time.Time values as RFC3339Nano, drivers that return
+// timestamps as text usually use the SQL layout.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// parseTrackingValue parses a tracking value from its string form. Numeric
+// values are returned as int64 when possible and as float64 otherwise,
+// timestamps as time.Time.
+func parseTrackingValue(t trackingType, s string) (interface{}, error) {
+	switch t {
+	case trackingNumeric:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("'%s' is not a number", s)
+		}
+		return f, nil
+	case trackingTimestamp:
+		for _, layout := range timestampLayouts {
+			if ts, err := time.Parse(layout, s); err == nil {
+				return ts, nil
+			}
+		}
+		return nil, fmt.Errorf("'%s' is not a timestamp", s)
+	}
+	return nil, fmt.Errorf("unknown tracking column type %v", t)
+}
+
+// formatTrackingValue returns the string form of a tracking value as stored
+// in the cursor.
+func formatTrackingValue(v interface{}) string {
+	switch v := v.(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprint(v)
+}
+
+// initialTrackingValue returns the value used for :sql_last_value when no
+// cursor has been stored yet.
+func initialTrackingValue(cfg config) (interface{}, error) {
+	if cfg.TrackingStartValue != "" {
+		return parseTrackingValue(cfg.TrackingColumnType, cfg.TrackingStartValue)
+	}
+	if cfg.TrackingColumnType == trackingTimestamp {
+		return time.Unix(0, 0).UTC(), nil
+	}
+	return int64(0), nil
+}
+
+// rowTrackingValue returns the value of the tracking column in row.
+func rowTrackingValue(row mapstr.M, column string, t trackingType) (interface{}, error) {
+	v, err := row.GetValue(strings.ToLower(column))
+	if err != nil {
+		return nil, fmt.Errorf("tracking column '%s' not found in row", column)
+	}
+	switch v := v.(type) {
+	case nil:
+		return nil, fmt.Errorf("tracking column '%s' is null", column)
+	case time.Time:
+		if t == trackingTimestamp {
+			return v, nil
+		}
+	case int, int8, int16, int32, int64, uint8, uint16, uint32:
+		if t == trackingNumeric {
+			return strconv.ParseInt(fmt.Sprint(v), 10, 64)
+		}
+	}
+	return parseTrackingValue(t, fmt.Sprint(v))
+}
+
+// compareTrackingValues returns a negative number if a is lower than b, zero
+// if they are equal and a positive number if a is greater than b.
+func compareTrackingValues(a, b interface{}) (int, error) {
+	switch a := a.(type) {
+	case time.Time:
+		if b, ok := b.(time.Time); ok {
+			return a.Compare(b), nil
+		}
+	case int64:
+		switch b := b.(type) {
+		case int64:
+			return compareOrdered(a, b), nil
+		case float64:
+			return compareOrdered(float64(a), b), nil
+		}
+	case float64:
+		switch b := b.(type) {
+		case int64:
+			return compareOrdered(a, float64(b)), nil
+		case float64:
+			return compareOrdered(a, b), nil
+		}
+	}
+	return 0, errors.New("tracking values are not comparable")
+}
+
+func compareOrdered[T int64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// bindLastValue replaces the :sql_last_value placeholder in query with the
+// parameter syntax of the driver and returns the query arguments.
+func bindLastValue(driver, query string, value interface{}) (string, []interface{}) {
+	switch sqlhelper.SwitchDriverName(driver) {
+	case "postgres":
+		return strings.ReplaceAll(query, lastValuePlaceholder, "$1"), []interface{}{value}
+	case "mssql", "sqlserver":
+		return strings.ReplaceAll(query, lastValuePlaceholder, "@p1"), []interface{}{value}
+	}
+	// Positional parameters need one argument per occurrence.
+	n := strings.Count(query, lastValuePlaceholder)
+	args := make([]interface{}, n)
+	for i := range args {
+		args[i] = value
+	}
+	return strings.ReplaceAll(query, lastValuePlaceholder, "?"), args
+}
@@ -0,0 +1,128 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+func TestParseTrackingValue(t *testing.T) {
+	tests := []struct {
+		typ     trackingType
+		in      string
+		want    interface{}
+		wantErr string
+	}{
+		{typ: trackingNumeric, in: "42", want: int64(42)},
+		{typ: trackingNumeric, in: "9007199254740993", want: int64(9007199254740993)},
+		{typ: trackingNumeric, in: "1.5", want: 1.5},
+		{typ: trackingNumeric, in: "abc", wantErr: "'abc' is not a number"},
+		{typ: trackingTimestamp, in: "2024-05-01T12:00:00.123Z", want: time.Date(2024, 5, 1, 12, 0, 0, 123e6, time.UTC)},
+		{typ: trackingTimestamp, in: "2024-05-01 12:00:00", want: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)},
+		{typ: trackingTimestamp, in: "2024-05-01", want: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{typ: trackingTimestamp, in: "yesterday", wantErr: "'yesterday' is not a timestamp"},
+	}
+	for _, test := range tests {
+		t.Run(test.typ.String()+"/"+test.in, func(t *testing.T) {
+			got, err := parseTrackingValue(test.typ, test.in)
+			if test.wantErr != "" {
+				assert.EqualError(t, err, test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+			if ts, ok := got.(time.Time); ok {
+				got, err = parseTrackingValue(test.typ, formatTrackingValue(ts))
+				require.NoError(t, err)
+				assert.True(t, ts.Equal(got.(time.Time)))
+			} else {
+				assert.Equal(t, test.in, formatTrackingValue(got))
+			}
+		})
+	}
+}
+
+func TestRowTrackingValue(t *testing.T) {
+	row := mapstr.M{
+		"id":      int64(7),
+		"small":   int32(3),
+		"text_id": "12",
+		"created": "2024-05-01T12:00:00Z",
+		"empty":   nil,
+	}
+
+	v, err := rowTrackingValue(row, "ID", trackingNumeric)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), v)
+
+	v, err = rowTrackingValue(row, "small", trackingNumeric)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), v)
+
+	v, err = rowTrackingValue(row, "text_id", trackingNumeric)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), v)
+
+	v, err = rowTrackingValue(row, "created", trackingTimestamp)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), v)
+
+	_, err = rowTrackingValue(row, "missing", trackingNumeric)
+	assert.EqualError(t, err, "tracking column 'missing' not found in row")
+
+	_, err = rowTrackingValue(row, "empty", trackingNumeric)
+	assert.EqualError(t, err, "tracking column 'empty' is null")
+}
+
+func TestCompareTrackingValues(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		a, b interface{}
+		want int
+	}{
+		{a: int64(1), b: int64(2), want: -1},
+		{a: int64(2), b: int64(2), want: 0},
+		{a: 2.5, b: int64(2), want: 1},
+		{a: int64(2), b: 2.5, want: -1},
+		{a: ts.Add(time.Second), b: ts, want: 1},
+	}
+	for _, test := range tests {
+		got, err := compareTrackingValues(test.a, test.b)
+		require.NoError(t, err)
+		assert.Equal(t, test.want, got, "%v <=> %v", test.a, test.b)
+	}
+
+	_, err := compareTrackingValues(ts, int64(1))
+	assert.Error(t, err)
+}
+
+func TestBindLastValue(t *testing.T) {
+	const query = "SELECT * FROM audit WHERE id > :sql_last_value OR parent_id > :sql_last_value"
+	tests := []struct {
+		driver    string
+		wantQuery string
+		wantArgs  int
+	}{
+		{driver: "mysql", wantQuery: "SELECT * FROM audit WHERE id > ? OR parent_id > ?", wantArgs: 2},
+		{driver: "postgresql", wantQuery: "SELECT * FROM audit WHERE id > $1 OR parent_id > $1", wantArgs: 1},
+		{driver: "mssql", wantQuery: "SELECT * FROM audit WHERE id > @p1 OR parent_id > @p1", wantArgs: 1},
+	}
+	for _, test := range tests {
+		t.Run(test.driver, func(t *testing.T) {
+			q, args := bindLastValue(test.driver, query, int64(5))
+			assert.Equal(t, test.wantQuery, q)
+			assert.Len(t, args, test.wantArgs)
+			for _, a := range args {
+				assert.Equal(t, int64(5), a)
+			}
+		})
+	}
+}
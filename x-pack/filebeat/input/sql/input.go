@@ -0,0 +1,213 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package sql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	// Register the database/sql drivers supported by the input.
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/microsoft/go-mssqldb"
+
+	"github.com/elastic/go-concert/ctxtool"
+	"github.com/elastic/go-concert/timed"
+
+	v2 "github.com/elastic/beats/v7/filebeat/input/v2"
+	inputcursor "github.com/elastic/beats/v7/filebeat/input/v2/input-cursor"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/beats/v7/libbeat/feature"
+	"github.com/elastic/beats/v7/libbeat/statestore"
+	sqlhelper "github.com/elastic/beats/v7/metricbeat/helper/sql"
+	conf "github.com/elastic/elastic-agent-libs/config"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+const inputName = "sql"
+
+// Plugin returns the sql input plugin.
+func Plugin(log *logp.Logger, store statestore.States) v2.Plugin {
+	return v2.Plugin{
+		Name:       inputName,
+		Stability:  feature.Beta,
+		Deprecated: false,
+		Info:       "SQL query input",
+		Doc:        "The sql input periodically runs a query and publishes new rows",
+		Manager: &inputcursor.InputManager{
+			Logger:     log,
+			StateStore: store,
+			Type:       inputName,
+			Configure:  configure,
+		},
+	}
+}
+
+func configure(cfg *conf.C) ([]inputcursor.Source, inputcursor.Input, error) {
+	config := defaultConfig()
+	if err := cfg.Unpack(&config); err != nil {
+		return nil, nil, fmt.Errorf("reading config: %w", err)
+	}
+	return []inputcursor.Source{&source{cfg: config}}, &sqlInput{}, nil
+}
+
+// source is the database table read by the input. The DSN may contain
+// credentials, so the source is identified by a digest instead.
+type source struct{ cfg config }
+
+func (s *source) Name() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s", sqlhelper.SwitchDriverName(s.cfg.Driver), s.cfg.DSN, s.cfg.TrackingColumn)
+	return s.cfg.Driver + ":" + hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+type sqlInput struct{}
+
+func (inp *sqlInput) Name() string { return inputName }
+
+func (inp *sqlInput) Test(src inputcursor.Source, ctx v2.TestContext) error {
+	cfg := src.(*source).cfg
+	db, err := sqlhelper.NewDBClient(cfg.Driver, cfg.DSN, ctx.Logger)
+	if err != nil {
+		return err
+	}
+	return db.Close()
+}
+
+// Run runs the query every period until the input is stopped. Query errors
+// are logged and the query is retried in the next period.
+func (inp *sqlInput) Run(env v2.Context, src inputcursor.Source, cursor inputcursor.Cursor, pub inputcursor.Publisher) error {
+	var st *state
+	if !cursor.IsNew() {
+		st = &state{}
+		if err := cursor.Unpack(st); err != nil {
+			return fmt.Errorf("reading cursor: %w", err)
+		}
+	}
+	return inp.run(env, src.(*source).cfg, st, pub)
+}
+
+// run is the body of Run. st is the persisted cursor, or nil if the source
+// has no cursor yet.
+func (inp *sqlInput) run(env v2.Context, cfg config, st *state, pub inputcursor.Publisher) error {
+	log := env.Logger
+
+	p := &poller{cfg: cfg, log: log, pub: pub}
+	if cfg.TrackingColumn != "" {
+		var err error
+		if st != nil {
+			p.lastValue, err = parseTrackingValue(cfg.TrackingColumnType, st.LastValue)
+			if err != nil {
+				return fmt.Errorf("reading cursor: %w", err)
+			}
+		} else {
+			p.lastValue, err = initialTrackingValue(cfg)
+			if err != nil {
+				return err
+			}
+		}
+		log.Infof("Tracking column '%s' starting after %s", cfg.TrackingColumn, formatTrackingValue(p.lastValue))
+	}
+	defer p.close()
+
+	ctx := ctxtool.FromCanceller(env.Cancelation)
+	poll := func() error {
+		if err := p.poll(ctx); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Errorw("Error running query", "error", err)
+		}
+		return nil
+	}
+	err := poll()
+	if err == nil {
+		err = timed.Periodic(ctx, cfg.Period, poll)
+	}
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// poller runs the configured query and publishes the rows newer than the
+// last value of the tracking column.
+type poller struct {
+	cfg       config
+	log       *logp.Logger
+	pub       inputcursor.Publisher
+	db        *sqlhelper.DbClient
+	lastValue interface{}
+}
+
+func (p *poller) poll(ctx context.Context) error {
+	if p.db == nil {
+		db, err := sqlhelper.NewDBClient(p.cfg.Driver, p.cfg.DSN, p.log)
+		if err != nil {
+			return fmt.Errorf("connecting to database: %w", err)
+		}
+		p.db = db
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+
+	query, args := p.cfg.Query, []interface{}(nil)
+	if p.cfg.TrackingColumn != "" {
+		query, args = bindLastValue(p.cfg.Driver, query, p.lastValue)
+	}
+	// Rows must not be skipped, as the cursor would move past them.
+	rows, err := p.db.FetchTableModeStrict(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("running query: %w", err)
+	}
+	p.log.Debugf("Query returned %d rows", len(rows))
+
+	now := time.Now()
+	for _, row := range rows {
+		var update interface{}
+		if p.cfg.TrackingColumn != "" {
+			v, err := rowTrackingValue(row, p.cfg.TrackingColumn, p.cfg.TrackingColumnType)
+			if err != nil {
+				return err
+			}
+			cmp, err := compareTrackingValues(v, p.lastValue)
+			if err != nil {
+				return err
+			}
+			if cmp > 0 {
+				p.lastValue = v
+			}
+			update = state{LastValue: formatTrackingValue(p.lastValue)}
+		}
+		event := beat.Event{
+			Timestamp: now,
+			Fields: mapstr.M{
+				"sql": mapstr.M{
+					"driver": p.cfg.Driver,
+					"row":    row,
+				},
+			},
+		}
+		if err := p.pub.Publish(event, update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *poller) close() {
+	if p.db == nil {
+		return
+	}
+	if err := p.db.Close(); err != nil {
+		p.log.Warnw("Error closing database connection", "error", err)
+	}
+}
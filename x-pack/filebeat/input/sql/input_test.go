@@ -0,0 +1,357 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v2 "github.com/elastic/beats/v7/filebeat/input/v2"
+	inputcursor "github.com/elastic/beats/v7/filebeat/input/v2/input-cursor"
+	"github.com/elastic/beats/v7/libbeat/beat"
+	"github.com/elastic/elastic-agent-libs/logp"
+	"github.com/elastic/elastic-agent-libs/mapstr"
+)
+
+const testDriver = "sqltest"
+
+func init() {
+	sql.Register(testDriver, fakeDriver{})
+	supportedDrivers[testDriver] = true
+}
+
+func TestInputTrackingColumn(t *testing.T) {
+	logp.TestingSetup()
+
+	table := newFakeTable(t, []string{"ID", "Message"},
+		[]driver.Value{int64(1), "login"},
+		[]driver.Value{int64(2), "logout"},
+		[]driver.Value{int64(3), "login"},
+	)
+	cfg := testConfig(table.dsn)
+	cfg.Query = "SELECT id, message FROM audit WHERE id > :sql_last_value ORDER BY id"
+	cfg.TrackingColumn = "id"
+
+	// The second query must only ask for rows after the last published one.
+	pub := runInput(t, cfg, nil, table, 2)
+	require.Len(t, pub.events, 3)
+	assert.Equal(t, mapstr.M{
+		"sql": mapstr.M{
+			"driver": testDriver,
+			"row":    mapstr.M{"id": int64(1), "message": "login"},
+		},
+	}, pub.events[0].Fields)
+	assert.Equal(t, []interface{}{state{"1"}, state{"2"}, state{"3"}}, pub.cursors)
+	assert.Equal(t, [][]driver.Value{{int64(0)}, {int64(3)}}, table.queryArgs())
+	assert.Equal(t, "SELECT id, message FROM audit WHERE id > ? ORDER BY id", table.lastQuery())
+
+	// A restarted input continues after the persisted cursor.
+	table.insert([]driver.Value{int64(4), "login"})
+	table.resetQueries()
+	pub = runInput(t, cfg, &state{LastValue: "3"}, table, 1)
+	require.Len(t, pub.events, 1)
+	assert.Equal(t, int64(4), mustGet(t, pub.events[0].Fields, "sql.row.id"))
+	assert.Equal(t, []interface{}{state{"4"}}, pub.cursors)
+}
+
+func TestInputQueryErrorKeepsCursor(t *testing.T) {
+	logp.TestingSetup()
+
+	table := newFakeTable(t, []string{"ID", "Message"},
+		[]driver.Value{int64(1), "login"},
+		[]driver.Value{int64(2), "logout"},
+		[]driver.Value{int64(3), "login"},
+	)
+	cfg := testConfig(table.dsn)
+	cfg.Query = "SELECT id, message FROM audit WHERE id > :sql_last_value ORDER BY id"
+	cfg.TrackingColumn = "id"
+
+	// The first query fails after its first row, nothing is published and
+	// the second query starts from the same cursor.
+	table.failures = 1
+	pub := runInput(t, cfg, nil, table, 2)
+	require.Len(t, pub.events, 3)
+	assert.Equal(t, []interface{}{state{"1"}, state{"2"}, state{"3"}}, pub.cursors)
+	assert.Equal(t, [][]driver.Value{{int64(0)}, {int64(0)}}, table.queryArgs())
+}
+
+func TestInputTrackingTimestamp(t *testing.T) {
+	logp.TestingSetup()
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	table := newFakeTable(t, []string{"created", "message"},
+		[]driver.Value{start.Add(time.Minute), "a"},
+		[]driver.Value{start.Add(-time.Minute), "too old"},
+		[]driver.Value{start.Add(2 * time.Minute), "b"},
+	)
+	cfg := testConfig(table.dsn)
+	cfg.Query = "SELECT created, message FROM audit WHERE created > :sql_last_value ORDER BY created"
+	cfg.TrackingColumn = "created"
+	cfg.TrackingColumnType = trackingTimestamp
+	cfg.TrackingStartValue = "2024-05-01T12:00:00Z"
+
+	pub := runInput(t, cfg, nil, table, 1)
+	require.Len(t, pub.events, 2)
+	assert.Equal(t, "2024-05-01T12:01:00Z", mustGet(t, pub.events[0].Fields, "sql.row.created"))
+	assert.Equal(t, []interface{}{state{"2024-05-01T12:01:00Z"}, state{"2024-05-01T12:02:00Z"}}, pub.cursors)
+	assert.Equal(t, [][]driver.Value{{start}}, table.queryArgs())
+}
+
+func TestInputWithoutTracking(t *testing.T) {
+	logp.TestingSetup()
+
+	table := newFakeTable(t, []string{"count"}, []driver.Value{int64(10)})
+	cfg := testConfig(table.dsn)
+	cfg.Query = "SELECT count(*) AS count FROM audit"
+
+	// Every query publishes all rows without a cursor update.
+	pub := runInput(t, cfg, nil, table, 2)
+	assert.Len(t, pub.events, 2)
+	assert.Empty(t, pub.cursors)
+	for _, args := range table.queryArgs() {
+		assert.Empty(t, args)
+	}
+}
+
+func TestInputRetriesConnection(t *testing.T) {
+	logp.TestingSetup()
+
+	cfg := testConfig("missing")
+	cfg.Query = "SELECT 1"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(50*time.Millisecond, cancel)
+	var pub publisher
+	err := (&sqlInput{}).run(testContext(ctx), cfg, nil, &pub)
+	assert.NoError(t, err)
+	assert.Empty(t, pub.events)
+}
+
+func TestSourceName(t *testing.T) {
+	a := &source{cfg: config{Driver: "mysql", DSN: "user:secret@tcp(db:3306)/app", TrackingColumn: "id"}}
+	b := &source{cfg: config{Driver: "mysql", DSN: "user:secret@tcp(db:3306)/app", TrackingColumn: "created"}}
+	assert.NotContains(t, a.Name(), "secret")
+	assert.Equal(t, a.Name(), (&source{cfg: a.cfg}).Name())
+	assert.NotEqual(t, a.Name(), b.Name())
+}
+
+func testConfig(dsn string) config {
+	cfg := defaultConfig()
+	cfg.Driver = testDriver
+	cfg.DSN = dsn
+	cfg.Period = 10 * time.Millisecond
+	cfg.Timeout = 10 * time.Millisecond
+	return cfg
+}
+
+func testContext(ctx context.Context) v2.Context {
+	return v2.Context{
+		Logger:      logp.NewLogger(inputName),
+		ID:          "test",
+		Cancelation: ctx,
+	}
+}
+
+// runInput runs the input until the table has been queried the given number
+// of times.
+func runInput(t *testing.T, cfg config, st *state, table *fakeTable, queries int) *publisher {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	table.onQuery = func(n int) {
+		if n >= queries {
+			cancel()
+		}
+	}
+
+	var pub publisher
+	done := make(chan error, 1)
+	go func() { done <- (&sqlInput{}).run(testContext(ctx), cfg, st, &pub) }()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("input did not stop")
+	}
+	return &pub
+}
+
+func mustGet(t *testing.T, m mapstr.M, key string) interface{} {
+	t.Helper()
+	v, err := m.GetValue(key)
+	require.NoError(t, err, key)
+	return v
+}
+
+var _ inputcursor.Publisher = (*publisher)(nil)
+
+type publisher struct {
+	mu      sync.Mutex
+	events  []beat.Event
+	cursors []interface{}
+}
+
+func (p *publisher) Publish(e beat.Event, cursor interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, e)
+	if cursor != nil {
+		p.cursors = append(p.cursors, cursor)
+	}
+	return nil
+}
+
+// fakeTables holds the tables served by fakeDriver, keyed by DSN.
+var fakeTables sync.Map
+
+// fakeTable is a table served by fakeDriver. Queries with an argument return
+// the rows whose first column is greater than the argument.
+type fakeTable struct {
+	dsn     string
+	columns []string
+	onQuery func(n int)
+
+	mu      sync.Mutex
+	rows    [][]driver.Value
+	queries []string
+	args    [][]driver.Value
+	// failures is the number of queries failing after their first row.
+	failures int
+}
+
+func newFakeTable(t *testing.T, columns []string, rows ...[]driver.Value) *fakeTable {
+	table := &fakeTable{dsn: t.Name(), columns: columns, rows: rows}
+	fakeTables.Store(table.dsn, table)
+	t.Cleanup(func() { fakeTables.Delete(table.dsn) })
+	return table
+}
+
+func (t *fakeTable) insert(row []driver.Value) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rows = append(t.rows, row)
+}
+
+func (t *fakeTable) resetQueries() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.queries, t.args = nil, nil
+}
+
+func (t *fakeTable) queryArgs() [][]driver.Value {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.args
+}
+
+func (t *fakeTable) lastQuery() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.queries[len(t.queries)-1]
+}
+
+func (t *fakeTable) query(q string, args []driver.Value) ([][]driver.Value, bool) {
+	t.mu.Lock()
+	t.queries = append(t.queries, q)
+	t.args = append(t.args, args)
+	var rows [][]driver.Value
+	for _, row := range t.rows {
+		if len(args) == 0 || greater(row[0], args[0]) {
+			rows = append(rows, row)
+		}
+	}
+	n := len(t.queries)
+	fail := t.failures > 0
+	if fail {
+		t.failures--
+	}
+	t.mu.Unlock()
+
+	if t.onQuery != nil {
+		// Report the query once its rows have been consumed.
+		defer t.onQuery(n)
+	}
+	return rows, fail
+}
+
+func greater(a, b driver.Value) bool {
+	switch a := a.(type) {
+	case int64:
+		return a > b.(int64)
+	case time.Time:
+		return a.After(b.(time.Time))
+	}
+	return false
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(dsn string) (driver.Conn, error) {
+	table, ok := fakeTables.Load(dsn)
+	if !ok {
+		return nil, errors.New("unknown database")
+	}
+	return &fakeConn{table: table.(*fakeTable)}, nil
+}
+
+type fakeConn struct{ table *fakeTable }
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{table: c.table, query: query}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+type fakeStmt struct {
+	table *fakeTable
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows, fail := s.table.query(s.query, args)
+	return &fakeRows{columns: s.table.columns, rows: rows, fail: fail}, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	fail    bool
+	read    int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.fail && r.read == 1 {
+		return errors.New("connection reset")
+	}
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	r.read++
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
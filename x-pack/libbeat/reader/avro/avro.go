@@ -0,0 +1,147 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package avro implements a reader for Avro Object Container Files.
+package avro
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+
+	"github.com/elastic/elastic-agent-libs/logp"
+)
+
+// Reader reads the records of an Avro Object Container File. The schema is
+// read from the file header. Blocks compressed with the null, deflate,
+// snappy and zstandard codecs are supported.
+type Reader struct {
+	dec *ocf.Decoder
+	log *logp.Logger
+
+	record any
+	err    error
+}
+
+// NewReader creates a new reader decoding Avro records from r. It returns an
+// error if the file header cannot be read.
+func NewReader(r io.Reader) (*Reader, error) {
+	log := logp.L().Named("reader.avro")
+
+	// Files are decoded with their own schema cache, so named types of a
+	// file don't resolve to a different version of the schema read from a
+	// previous file.
+	dec, err := ocf.NewDecoder(r, ocf.WithDecoderSchemaCache(&avro.SchemaCache{}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro decoder: %w", err)
+	}
+	log.Debugw("created avro reader", "schema", dec.Schema().String(), "codec", string(dec.Metadata()["avro.codec"]))
+
+	return &Reader{dec: dec, log: log}, nil
+}
+
+// Next decodes the next record and returns true if a record was read. It
+// returns false once all records have been read. If the record or its
+// block cannot be decoded, Next returns true and the error is returned by
+// Record, after which Next returns false.
+func (r *Reader) Next() bool {
+	r.record = nil
+	if r.err != nil {
+		return false
+	}
+	if !r.dec.HasNext() {
+		if err := r.dec.Error(); err != nil {
+			r.err = fmt.Errorf("failed to read avro block: %w", err)
+			return true
+		}
+		return false
+	}
+	var v any
+	if err := r.dec.Decode(&v); err != nil {
+		r.err = fmt.Errorf("failed to decode avro record: %w", err)
+		return true
+	}
+	r.record = normalize(v)
+	return true
+}
+
+// More returns whether there are more records to read after the current one.
+func (r *Reader) More() bool {
+	return r.err == nil && r.dec.HasNext()
+}
+
+// Record returns the current record. Records are decoded into maps of
+// JSON serialisable values.
+func (r *Reader) Record() (any, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.record == nil {
+		return nil, io.EOF
+	}
+	return r.record, nil
+}
+
+// Close releases the reader. It returns the error that prevented reading
+// the file to its end, if any.
+func (r *Reader) Close() error {
+	if err := r.dec.Error(); err != nil {
+		return fmt.Errorf("failed to read avro file: %w", err)
+	}
+	return nil
+}
+
+// normalize converts the decoded values that have no natural JSON
+// representation.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = normalize(e)
+		}
+	case []any:
+		if v == nil {
+			// empty arrays are decoded as nil slices
+			return []any{}
+		}
+		for i, e := range v {
+			v[i] = normalize(e)
+		}
+	case *big.Rat:
+		return decimalString(v)
+	}
+	return v
+}
+
+// decimalString returns the decimal notation of a value of the decimal
+// logical type. Its denominator is a divisor of a power of ten, so the
+// notation is exact.
+func decimalString(r *big.Rat) string {
+	var (
+		d      = new(big.Int).Set(r.Denom())
+		two    = big.NewInt(2)
+		five   = big.NewInt(5)
+		ten    = big.NewInt(10)
+		mod    = new(big.Int)
+		digits int
+	)
+	for d.Cmp(big.NewInt(1)) != 0 {
+		switch {
+		case mod.Mod(d, ten).Sign() == 0:
+			d.Div(d, ten)
+		case mod.Mod(d, two).Sign() == 0:
+			d.Div(d, two)
+		case mod.Mod(d, five).Sign() == 0:
+			d.Div(d, five)
+		default:
+			// not a decimal fraction, can't happen with the decimal type
+			return r.FloatString(16)
+		}
+		digits++
+	}
+	return r.FloatString(digits)
+}
@@ -0,0 +1,120 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package avro
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `{
+	"type": "record",
+	"name": "audit",
+	"fields": [
+		{"name": "timestamp", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+		{"name": "user", "type": ["null", "string"]},
+		{"name": "bytes", "type": "long"},
+		{"name": "cost", "type": {"type": "bytes", "logicalType": "decimal", "precision": 9, "scale": 2}},
+		{"name": "tags", "type": {"type": "array", "items": "string"}}
+	]
+}`
+
+func createAvro(t *testing.T, codec ocf.CodecName, records int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	// small blocks to read records from more than one block
+	enc, err := ocf.NewEncoder(testSchema, &buf, ocf.WithCodec(codec), ocf.WithBlockLength(3))
+	require.NoError(t, err)
+	ts := time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < records; i++ {
+		var user any
+		if i%2 == 0 {
+			user = map[string]any{"string": fmt.Sprintf("user-%d", i)}
+		}
+		err = enc.Encode(map[string]any{
+			"timestamp": ts.Add(time.Duration(i) * time.Second),
+			"user":      user,
+			"bytes":     int64(i * 100),
+			"cost":      big.NewRat(int64(i*100+5), 100),
+			"tags":      []any{"a", "b"},
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, enc.Close())
+	return buf.Bytes()
+}
+
+func TestReaderCodecs(t *testing.T) {
+	for _, codec := range []ocf.CodecName{ocf.Null, ocf.Deflate, ocf.Snappy, ocf.ZStandard} {
+		t.Run(string(codec), func(t *testing.T) {
+			r, err := NewReader(bytes.NewReader(createAvro(t, codec, 10)))
+			require.NoError(t, err)
+
+			var records []string
+			for r.Next() {
+				v, err := r.Record()
+				require.NoError(t, err)
+				b, err := json.Marshal(v)
+				require.NoError(t, err)
+				records = append(records, string(b))
+				assert.Equal(t, len(records) < 10, r.More())
+			}
+			require.NoError(t, r.Close())
+
+			require.Len(t, records, 10)
+			assert.JSONEq(t, `{"timestamp":"2025-03-04T10:00:00Z","user":"user-0","bytes":0,"cost":"0.05","tags":["a","b"]}`, records[0])
+			assert.JSONEq(t, `{"timestamp":"2025-03-04T10:00:01Z","user":null,"bytes":100,"cost":"1.05","tags":["a","b"]}`, records[1])
+
+			_, err = r.Record()
+			assert.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	t.Run("not an avro file", func(t *testing.T) {
+		_, err := NewReader(bytes.NewReader([]byte("date,user\n2025-03-04,alice\n")))
+		assert.ErrorContains(t, err, "failed to create avro decoder")
+	})
+
+	t.Run("truncated file", func(t *testing.T) {
+		data := createAvro(t, ocf.Deflate, 10)
+		r, err := NewReader(bytes.NewReader(data[:len(data)-20]))
+		require.NoError(t, err)
+		var n int
+		for r.Next() {
+			if _, err = r.Record(); err != nil {
+				break
+			}
+			n++
+		}
+		assert.Less(t, n, 10)
+		assert.ErrorContains(t, err, "failed to read avro block")
+		assert.False(t, r.Next())
+		assert.Error(t, r.Close())
+	})
+}
+
+func TestDecimalString(t *testing.T) {
+	tests := map[string]*big.Rat{
+		"2.56":    big.NewRat(256, 100),
+		"310.5":   big.NewRat(31050, 100),
+		"-0.125":  big.NewRat(-125, 1000),
+		"42":      big.NewRat(42, 1),
+		"0.00001": big.NewRat(1, 100000),
+	}
+	for want, r := range tests {
+		assert.Equal(t, want, decimalString(r))
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package decoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/elastic/beats/v7/x-pack/libbeat/reader/avro"
+)
+
+// avroDecoder is a decoder for Avro Object Container Files.
+type avroDecoder struct {
+	reader *avro.Reader
+	// offset is the index of the current record in the file.
+	offset int64
+}
+
+// newAvroDecoder creates a new Avro decoder. It uses the libbeat avro reader under the hood.
+// It returns an error if the file header cannot be read.
+func newAvroDecoder(_ Config, r io.Reader) (Decoder, error) {
+	reader, err := avro.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro decoder: %w", err)
+	}
+	return &avroDecoder{reader: reader, offset: -1}, nil
+}
+
+// Next advances the decoder to the next record and returns true if there is more data to be decoded.
+func (ad *avroDecoder) Next() bool {
+	ad.offset++
+	return ad.reader.Next()
+}
+
+// More returns whether there are more records to read.
+func (ad *avroDecoder) More() bool {
+	return ad.reader.More()
+}
+
+// Decode returns the JSON encoded value of the current record.
+func (ad *avroDecoder) Decode() ([]byte, error) {
+	v, err := ad.reader.Record()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// DecodeValue returns the current record, its JSON encoding and its index
+// in the file, used as its offset.
+func (ad *avroDecoder) DecodeValue() (offset int64, msg []byte, val map[string]any, _ error) {
+	v, err := ad.reader.Record()
+	if err != nil {
+		return ad.offset, nil, nil, err
+	}
+	msg, err = json.Marshal(v)
+	if err != nil {
+		return ad.offset, nil, nil, err
+	}
+	// The top level schema of the file is usually a record.
+	val, _ = v.(map[string]any)
+	return ad.offset, msg, val, nil
+}
+
+// Close closes the avro decoder and releases the resources.
+func (ad *avroDecoder) Close() error {
+	return ad.reader.Close()
+}
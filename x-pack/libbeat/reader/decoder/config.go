@@ -2,7 +2,7 @@
 // or more contributor license agreements. Licensed under the Elastic License;
 // you may not use this file except in compliance with the Elastic License.
 
-package awss3
+package decoder
 
 import (
 	"errors"
@@ -10,26 +10,33 @@ import (
 	"unicode/utf8"
 )
 
-// decoderConfig contains the configuration options for instantiating a decoder.
-type decoderConfig struct {
-	Codec *codecConfig `config:"codec"`
+// Config contains the configuration options for instantiating a decoder.
+type Config struct {
+	Codec *CodecConfig `config:"codec"`
 }
 
-// codecConfig contains the configuration options for different codecs used by a decoder.
-type codecConfig struct {
-	Parquet *parquetCodecConfig `config:"parquet"`
-	CSV     *csvCodecConfig     `config:"csv"`
+// CodecConfig contains the configuration options for different codecs used by a decoder.
+type CodecConfig struct {
+	Parquet *ParquetCodecConfig `config:"parquet"`
+	CSV     *CSVCodecConfig     `config:"csv"`
+	Avro    *AvroCodecConfig    `config:"avro"`
 }
 
-func (c *codecConfig) Validate() error {
-	if c.Parquet != nil && c.CSV != nil {
+func (c *CodecConfig) Validate() error {
+	var n int
+	for _, enabled := range []bool{c.Parquet != nil, c.CSV != nil, c.Avro != nil} {
+		if enabled {
+			n++
+		}
+	}
+	if n > 1 {
 		return errors.New("more than one decoder configured")
 	}
 	return nil
 }
 
-// csvCodecConfig contains the configuration options for the CSV codec.
-type csvCodecConfig struct {
+// CSVCodecConfig contains the configuration options for the CSV codec.
+type CSVCodecConfig struct {
 	Enabled bool `config:"enabled"`
 
 	// Fields is the set of field names. If it is present
@@ -41,15 +48,16 @@ type csvCodecConfig struct {
 
 	// The fields below have the same meaning as the
 	// fields of the same name in csv.Reader.
-	Comma            *configRune `config:"comma"`
-	Comment          configRune  `config:"comment"`
+	Comma            *ConfigRune `config:"comma"`
+	Comment          ConfigRune  `config:"comment"`
 	LazyQuotes       bool        `config:"lazy_quotes"`
 	TrimLeadingSpace bool        `config:"trim_leading_space"`
 }
 
-type configRune rune
+// ConfigRune is a single character configuration option.
+type ConfigRune rune
 
-func (r *configRune) Unpack(s string) error {
+func (r *ConfigRune) Unpack(s string) error {
 	if s == "" {
 		return nil
 	}
@@ -58,13 +66,19 @@ func (r *configRune) Unpack(s string) error {
 		return fmt.Errorf("single character option given more than one character: %q", s)
 	}
 	_r, _ := utf8.DecodeRuneInString(s)
-	*r = configRune(_r)
+	*r = ConfigRune(_r)
 	return nil
 }
 
-// parquetCodecConfig contains the configuration options for the parquet codec.
-type parquetCodecConfig struct {
+// ParquetCodecConfig contains the configuration options for the parquet codec.
+type ParquetCodecConfig struct {
 	Enabled         bool `config:"enabled"`
 	ProcessParallel bool `config:"process_parallel"`
 	BatchSize       int  `config:"batch_size" default:"1"`
 }
+
+// AvroCodecConfig contains the configuration options for the avro codec.
+// The schema and compression codec are read from the file header.
+type AvroCodecConfig struct {
+	Enabled bool `config:"enabled"`
+}
@@ -0,0 +1,142 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package decoder
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// csvDecoder is a decoder for CSV data.
+type csvDecoder struct {
+	r *csv.Reader
+
+	header []string
+
+	// current is the record returned by the decoder, and coming the
+	// record read ahead to know whether there are more records.
+	current []string
+	coming  []string
+	// offset and comingOffset are the offsets of current and coming
+	// in the stream.
+	offset       int64
+	comingOffset int64
+
+	err error
+}
+
+// newCSVDecoder creates a new CSV decoder.
+func newCSVDecoder(config Config, r io.Reader) (Decoder, error) {
+	d := csvDecoder{r: csv.NewReader(r)}
+	d.r.ReuseRecord = true
+	if config.Codec.CSV.Comma != nil {
+		d.r.Comma = rune(*config.Codec.CSV.Comma)
+	}
+	d.r.Comment = rune(config.Codec.CSV.Comment)
+	d.r.LazyQuotes = config.Codec.CSV.LazyQuotes
+	d.r.TrimLeadingSpace = config.Codec.CSV.TrimLeadingSpace
+	if len(config.Codec.CSV.Fields) != 0 {
+		d.r.FieldsPerRecord = len(config.Codec.CSV.Fields)
+		d.header = config.Codec.CSV.Fields
+	} else {
+		h, err := d.r.Read()
+		if err != nil {
+			return nil, err
+		}
+		d.header = slices.Clone(h)
+	}
+	d.readAhead()
+	if d.err != nil && !errors.Is(d.err, io.EOF) {
+		return nil, d.err
+	}
+	d.current = make([]string, 0, len(d.header))
+	return &d, nil
+}
+
+func (d *csvDecoder) readAhead() {
+	d.comingOffset = d.r.InputOffset()
+	d.coming, d.err = d.r.Read()
+	if d.err != nil {
+		d.coming = nil
+	}
+}
+
+// More returns whether there are more CSV lines to read.
+func (d *csvDecoder) More() bool { return d.coming != nil }
+
+// Next advances the decoder to the next CSV line and returns true if
+// there is more data to be decoded.
+func (d *csvDecoder) Next() bool {
+	if !d.More() {
+		return false
+	}
+	d.current = append(d.current[:0], d.coming...)
+	d.offset = d.comingOffset
+	d.readAhead()
+	return true
+}
+
+// Decode returns the JSON encoded value of the current CSV line, with
+// the fields in the order of the header. Next must have been called before
+// any calls to Decode.
+func (d *csvDecoder) Decode() ([]byte, error) {
+	if len(d.current) == 0 {
+		return nil, fmt.Errorf("decode called before next")
+	}
+	// By the time we are here, current must be the same
+	// length as header; if it was not read, it would be
+	// zero, but if it was, it must match by the contract
+	// of the csv.Reader.
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, n := range d.header {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(n)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(d.current[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// DecodeValue returns the value of the current CSV line interpreted as
+// an object with fields based on the header held by the receiver, and the
+// offset of the line in the stream. Next must have been called before any
+// calls to DecodeValue.
+func (d *csvDecoder) DecodeValue() (offset int64, msg []byte, val map[string]any, _ error) {
+	msg, err := d.Decode()
+	if err != nil {
+		return d.offset, nil, nil, err
+	}
+	m := make(map[string]any, len(d.header))
+	for i, n := range d.header {
+		m[n] = d.current[i]
+	}
+	return d.offset, msg, m, nil
+}
+
+// Close closes the CSV decoder and releases the resources. It returns the
+// error that stopped the decoding, if any.
+func (d *csvDecoder) Close() error {
+	if errors.Is(d.err, io.EOF) {
+		return nil
+	}
+	return d.err
+}
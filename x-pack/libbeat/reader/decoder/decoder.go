@@ -0,0 +1,57 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+// Package decoder implements the codecs decoding the content of the objects
+// read by the awss3, gcs and azureblobstorage inputs into records.
+package decoder
+
+import (
+	"fmt"
+	"io"
+)
+
+// Decoder is an interface for decoding the records of a data stream.
+type Decoder interface {
+	// Decode returns the JSON encoded value of the current record.
+	// It returns an error if the record cannot be decoded.
+	Decode() ([]byte, error)
+	// Next advances the decoder to the next record and returns true if
+	// there is more data to be decoded.
+	Next() bool
+	// More returns whether there are more records to read after the
+	// current one.
+	More() bool
+	// Close closes the decoder and releases any resources associated
+	// with it. It returns an error if the stream could not be read fully.
+	Close() error
+}
+
+// ValueDecoder is a decoder that can decode directly to a JSON serialisable
+// value.
+type ValueDecoder interface {
+	Decoder
+
+	// DecodeValue returns the current record, its JSON encoding and its
+	// offset in the stream. If the receiver is unable to provide a unique
+	// offset for the value, offset will be negative. The value is nil if
+	// the record is not an object.
+	DecodeValue() (offset int64, msg []byte, val map[string]any, _ error)
+}
+
+// NewDecoder creates a new decoder based on the codec type. It returns
+// a nil Decoder if no codec is configured.
+func NewDecoder(config Config, r io.Reader) (Decoder, error) {
+	switch {
+	case config.Codec == nil:
+		return nil, nil
+	case config.Codec.Parquet != nil:
+		return newParquetDecoder(config, r)
+	case config.Codec.CSV != nil:
+		return newCSVDecoder(config, r)
+	case config.Codec.Avro != nil:
+		return newAvroDecoder(config, r)
+	default:
+		return nil, fmt.Errorf("unsupported config value: %v", config)
+	}
+}
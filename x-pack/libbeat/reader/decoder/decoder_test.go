@@ -0,0 +1,326 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package decoder
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	conf "github.com/elastic/elastic-agent-libs/config"
+)
+
+const testDataPath = "testdata"
+
+func TestDecoder(t *testing.T) {
+	testCases := []struct {
+		name          string
+		file          string
+		numRecords    int
+		assertAgainst string
+		config        Config
+	}{
+		{
+			name:          "csv",
+			file:          "txn.csv",
+			numRecords:    4,
+			assertAgainst: "txn.json",
+			config: Config{&CodecConfig{
+				CSV: &CSVCodecConfig{
+					Enabled: true,
+					Comma:   ptr[ConfigRune](' '),
+				},
+			}},
+		},
+		{
+			name:          "parquet_default_content_check",
+			file:          "cloudtrail.parquet",
+			numRecords:    1,
+			assertAgainst: "cloudtrail.json",
+			config: Config{&CodecConfig{
+				Parquet: &ParquetCodecConfig{
+					Enabled:   true,
+					BatchSize: 1,
+				},
+			}},
+		},
+		{
+			name:       "parquet_batch_size_1",
+			file:       "vpc-flow.gz.parquet",
+			numRecords: 1304,
+			config: Config{&CodecConfig{
+				Parquet: &ParquetCodecConfig{
+					ProcessParallel: true,
+					BatchSize:       1,
+				},
+			}},
+		},
+		{
+			name:       "parquet_batch_size_100",
+			file:       "vpc-flow.gz.parquet",
+			numRecords: 1304,
+			config: Config{&CodecConfig{
+				Parquet: &ParquetCodecConfig{
+					ProcessParallel: true,
+					BatchSize:       100,
+				},
+			}},
+		},
+		{
+			name:          "avro",
+			file:          "audit.avro",
+			numRecords:    3,
+			assertAgainst: "audit.json",
+			config: Config{&CodecConfig{
+				Avro: &AvroCodecConfig{
+					Enabled: true,
+				},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := os.Open(filepath.Join(testDataPath, tc.file))
+			require.NoError(t, err)
+			defer f.Close()
+
+			dec, err := NewDecoder(tc.config, f)
+			require.NoError(t, err)
+
+			var records []string
+			for dec.Next() {
+				var msg []byte
+				if vdec, ok := dec.(ValueDecoder); ok {
+					var val map[string]any
+					_, msg, val, err = vdec.DecodeValue()
+					require.NoError(t, err)
+					// the value must match its JSON encoding
+					b, err := json.Marshal(val)
+					require.NoError(t, err)
+					assert.JSONEq(t, string(msg), string(b))
+				} else {
+					msg, err = dec.Decode()
+					require.NoError(t, err)
+				}
+				records = append(records, string(msg))
+				// More only turns false on the last record
+				assert.Equal(t, len(records) < tc.numRecords, dec.More())
+			}
+			require.NoError(t, dec.Close())
+			assert.Len(t, records, tc.numRecords)
+
+			if tc.assertAgainst != "" {
+				targetData := readJSONFromFile(t, filepath.Join(testDataPath, tc.assertAgainst))
+				require.Equal(t, len(targetData), len(records))
+				for i, record := range records {
+					assert.JSONEq(t, targetData[i], record)
+				}
+			}
+		})
+	}
+}
+
+func TestCSVDecoder(t *testing.T) {
+	config := Config{&CodecConfig{CSV: &CSVCodecConfig{Enabled: true}}}
+
+	t.Run("offsets_and_escaping", func(t *testing.T) {
+		const data = "name,quote\nfoo,\"say \"\"hi\"\"\"\nbar,\\\nquote,quote\n"
+		dec, err := NewDecoder(config, strings.NewReader(data))
+		require.NoError(t, err)
+		vdec := dec.(ValueDecoder)
+
+		require.True(t, vdec.Next())
+		offset, msg, val, err := vdec.DecodeValue()
+		require.NoError(t, err)
+		assert.Equal(t, int64(len("name,quote\n")), offset)
+		assert.Equal(t, `{"name":"foo","quote":"say \"hi\""}`, string(msg))
+		assert.Equal(t, map[string]any{"name": "foo", "quote": `say "hi"`}, val)
+
+		require.True(t, vdec.Next())
+		offset, msg, _, err = vdec.DecodeValue()
+		require.NoError(t, err)
+		assert.Equal(t, int64(strings.Index(data, "bar")), offset)
+		assert.Equal(t, `{"name":"bar","quote":"\\"}`, string(msg))
+
+		require.True(t, vdec.Next())
+		_, msg, _, err = vdec.DecodeValue()
+		require.NoError(t, err)
+		assert.Equal(t, `{"name":"quote","quote":"quote"}`, string(msg))
+
+		assert.False(t, vdec.Next())
+		assert.NoError(t, vdec.Close())
+	})
+
+	t.Run("no_records", func(t *testing.T) {
+		dec, err := NewDecoder(config, strings.NewReader("name,quote\n"))
+		require.NoError(t, err)
+		assert.False(t, dec.More())
+		assert.False(t, dec.Next())
+		assert.NoError(t, dec.Close())
+	})
+
+	t.Run("bad_record", func(t *testing.T) {
+		dec, err := NewDecoder(config, strings.NewReader("name,quote\nfoo,bar\nbaz\n"))
+		require.NoError(t, err)
+		require.True(t, dec.Next())
+		assert.False(t, dec.Next())
+		assert.Error(t, dec.Close())
+	})
+}
+
+func readJSONFromFile(t *testing.T, filepath string) []string {
+	fileBytes, err := os.ReadFile(filepath)
+	assert.NoError(t, err)
+	var rawMessages []json.RawMessage
+	err = json.Unmarshal(fileBytes, &rawMessages)
+	assert.NoError(t, err)
+	var data []string
+
+	for _, rawMsg := range rawMessages {
+		data = append(data, string(rawMsg))
+	}
+	return data
+}
+
+var codecConfigTests = []struct {
+	name    string
+	yaml    string
+	want    Config
+	wantErr error
+}{
+	{
+		name: "handle_rune",
+		yaml: `
+codec:
+  csv:
+    enabled: true
+    comma: ' '
+    comment: '#'
+`,
+		want: Config{&CodecConfig{
+			CSV: &CSVCodecConfig{
+				Enabled: true,
+				Comma:   ptr[ConfigRune](' '),
+				Comment: '#',
+			},
+		}},
+	},
+	{
+		name: "no_comma",
+		yaml: `
+codec:
+  csv:
+    enabled: true
+`,
+		want: Config{&CodecConfig{
+			CSV: &CSVCodecConfig{
+				Enabled: true,
+			},
+		}},
+	},
+	{
+		name: "null_comma",
+		yaml: `
+codec:
+  csv:
+    enabled: true
+    comma: "\u0000"
+`,
+		want: Config{&CodecConfig{
+			CSV: &CSVCodecConfig{
+				Enabled: true,
+				Comma:   ptr[ConfigRune]('\x00'),
+			},
+		}},
+	},
+	{
+		name: "bad_rune",
+		yaml: `
+codec:
+  csv:
+    enabled: true
+    comma: 'this is too long'
+`,
+		wantErr: errors.New(`single character option given more than one character: "this is too long" accessing 'codec.csv.comma'`),
+	},
+	{
+		name: "confused",
+		yaml: `
+codec:
+  csv:
+    enabled: true
+  parquet:
+    enabled: true
+`,
+		wantErr: errors.New(`more than one decoder configured accessing 'codec'`),
+	},
+	{
+		name: "avro",
+		yaml: `
+codec:
+  avro:
+    enabled: true
+`,
+		want: Config{&CodecConfig{
+			Avro: &AvroCodecConfig{
+				Enabled: true,
+			},
+		}},
+	},
+	{
+		name: "confused_avro",
+		yaml: `
+codec:
+  avro:
+    enabled: true
+  csv:
+    enabled: true
+`,
+		wantErr: errors.New(`more than one decoder configured accessing 'codec'`),
+	},
+}
+
+func TestCodecConfig(t *testing.T) {
+	for _, test := range codecConfigTests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := conf.NewConfigWithYAML([]byte(test.yaml), "")
+			if err != nil {
+				t.Fatalf("unexpected error unmarshaling config: %v", err)
+			}
+
+			var got Config
+			err = c.Unpack(&got)
+			if !sameError(err, test.wantErr) {
+				t.Errorf("unexpected error unpacking config: got:%v want:%v", err, test.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("unexpected result\n--- want\n+++ got\n%s", cmp.Diff(test.want, got))
+			}
+		})
+	}
+}
+
+func sameError(a, b error) bool {
+	switch {
+	case a == nil && b == nil:
+		return true
+	case a == nil, b == nil:
+		return false
+	default:
+		return a.Error() == b.Error()
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
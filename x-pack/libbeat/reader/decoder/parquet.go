@@ -0,0 +1,86 @@
+// Copyright Elasticsearch B.V. and/or licensed to Elasticsearch B.V. under one
+// or more contributor license agreements. Licensed under the Elastic License;
+// you may not use this file except in compliance with the Elastic License.
+
+package decoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/elastic/beats/v7/x-pack/libbeat/reader/parquet"
+)
+
+// parquetDecoder is a decoder for parquet data. The rows of the batches
+// read from the file are decoded one at a time.
+type parquetDecoder struct {
+	reader *parquet.BufferedReader
+
+	// coming is whether the reader holds a batch that was not read yet.
+	coming bool
+	rows   []json.RawMessage
+	err    error
+}
+
+// newParquetDecoder creates a new parquet decoder. It uses the libbeat parquet reader under the hood.
+// It returns an error if the parquet reader cannot be created.
+func newParquetDecoder(config Config, r io.Reader) (Decoder, error) {
+	reader, err := parquet.NewBufferedReader(r, &parquet.Config{
+		ProcessParallel: config.Codec.Parquet.ProcessParallel,
+		BatchSize:       config.Codec.Parquet.BatchSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create parquet decoder: %w", err)
+	}
+	return &parquetDecoder{
+		reader: reader,
+		coming: reader.Next(),
+	}, nil
+}
+
+// Next advances the parquet decoder to the next row and returns true if there is more data to be decoded.
+func (pd *parquetDecoder) Next() bool {
+	if pd.err != nil {
+		return false
+	}
+	if len(pd.rows) != 0 {
+		pd.rows = pd.rows[1:]
+	}
+	for len(pd.rows) == 0 {
+		if !pd.coming {
+			return false
+		}
+		var batch []byte
+		batch, pd.err = pd.reader.Record()
+		if pd.err == nil {
+			pd.err = json.Unmarshal(batch, &pd.rows)
+		}
+		if pd.err != nil {
+			return true
+		}
+		pd.coming = pd.reader.Next()
+	}
+	return true
+}
+
+// More returns whether there are more rows to read.
+func (pd *parquetDecoder) More() bool {
+	return pd.err == nil && (len(pd.rows) > 1 || pd.coming)
+}
+
+// Decode returns the JSON encoded value of the current row.
+func (pd *parquetDecoder) Decode() ([]byte, error) {
+	if pd.err != nil {
+		return nil, pd.err
+	}
+	if len(pd.rows) == 0 {
+		return nil, fmt.Errorf("decode called before next")
+	}
+	return pd.rows[0], nil
+}
+
+// Close closes the parquet decoder and releases the resources.
+func (pd *parquetDecoder) Close() error {
+	return pd.reader.Close()
+}